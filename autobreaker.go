@@ -84,6 +84,10 @@
 //	    log.Warn("Circuit about to trip!")
 //	}
 //
+//	// One-line status for chat and incident tooling
+//	slack.Post(breaker.AlertSummary())
+//	// [payment-api] OPEN — 42% failure rate (210/500), opened 12s ago, probing in 48s
+//
 // # Runtime Configuration
 //
 // Update settings without restarting:
@@ -128,6 +132,9 @@
 // Circuit breaker errors:
 //   - ErrOpenState: Circuit is open, request rejected (fail fast)
 //   - ErrTooManyRequests: Too many concurrent requests in half-open state
+//   - ErrTooManyConcurrent: All MaxConcurrent bulkhead slots busy
+//   - ErrDeadlineTooShort: Context deadline shorter than learned p95 latency (PredictiveReject)
+//   - ErrDependencyOpen: A dependency declared with DependsOn() is open
 //
 // Application errors are passed through unchanged. Use the IsSuccessful callback
 // to customize which errors count as failures:
@@ -177,8 +184,13 @@ import "github.com/1mb-dev/autobreaker/internal/breaker"
 // All methods are thread-safe and can be called concurrently.
 type CircuitBreaker = breaker.CircuitBreaker
 
+// Breaker is the core circuit breaker contract (Execute, ExecuteContext, State,
+// Counts, Name), implemented by *CircuitBreaker. Decorators can implement it and
+// verify their behavior with autobreakertest.RunConformance.
+type Breaker = breaker.Breaker
+
 // State represents the current state of the circuit breaker.
-// Valid states are StateClosed, StateOpen, and StateHalfOpen.
+// Valid states are StateClosed, StateOpen, StateHalfOpen, and StateDisabled.
 type State = breaker.State
 
 // Counts holds statistics about requests processed by the circuit breaker.
@@ -191,6 +203,11 @@ type Counts = breaker.Counts
 // See internal/breaker.Settings for detailed field documentation.
 type Settings = breaker.Settings
 
+// Builder constructs a CircuitBreaker fluently as an alternative to Settings
+// and New(). Created with NewBuilder().
+// See internal/breaker.Builder for detailed documentation.
+type Builder = breaker.Builder
+
 // SettingsUpdate specifies runtime configuration updates. Used with UpdateSettings()
 // to modify circuit breaker settings without restarting.
 //
@@ -198,21 +215,189 @@ type Settings = breaker.Settings
 // See internal/breaker.SettingsUpdate for detailed field documentation.
 type SettingsUpdate = breaker.SettingsUpdate
 
+// ChangeSet describes the changes applied by UpdateSettingsDetailed(): each
+// changed field with its old and new value, and whether a smart reset ran.
+// JSON-marshalable for audit logs.
+//
+// See internal/breaker.ChangeSet for detailed documentation.
+type ChangeSet = breaker.ChangeSet
+
+// SettingChange is one entry of a ChangeSet.
+type SettingChange = breaker.SettingChange
+
 // Metrics provides real-time metrics about the circuit breaker state and behavior.
 // Returned by the Metrics() method. Useful for monitoring and dashboards.
 //
 // See internal/breaker.Metrics for detailed field documentation.
 type Metrics = breaker.Metrics
 
+// MetricsSample is one point of the metrics history returned by the History()
+// method when Settings.MetricsHistoryInterval is set. Useful for sparklines.
+type MetricsSample = breaker.MetricsSample
+
+// JournalEntry is one request in the outcome journal returned by the
+// LastTripJournal() method when Settings.JournalSize is set.
+//
+// See internal/breaker.JournalEntry for detailed field documentation.
+type JournalEntry = breaker.JournalEntry
+
+// JournalOutcome is how a journaled request ended.
+type JournalOutcome = breaker.JournalOutcome
+
+// Episode describes one open period, from the circuit leaving Closed until it
+// closes again: its duration, reason, rejections and probes. Returned by
+// Episodes() with Settings.EpisodeHistory.
+type Episode = breaker.Episode
+
 // Diagnostics provides comprehensive diagnostic information about the circuit breaker.
 // Returned by the Diagnostics() method. Useful for troubleshooting and debugging.
 //
 // See internal/breaker.Diagnostics for detailed field documentation.
 type Diagnostics = breaker.Diagnostics
 
+// QuickStats is a lean, allocation-free snapshot (state, counts, failure rate,
+// timestamps) returned by the QuickStats() method, for high-frequency scrapers.
+//
+// See internal/breaker.QuickStats for detailed field documentation.
+type QuickStats = breaker.QuickStats
+
+// OpenReason describes why the circuit is currently open. Exposed via
+// Diagnostics.OpenReason.
+//
+// See internal/breaker.OpenReason for detailed field documentation.
+type OpenReason = breaker.OpenReason
+
+// OpenReasonKind identifies the path that opened the circuit.
+type OpenReasonKind = breaker.OpenReasonKind
+
+// Group manages one circuit breaker per key (e.g., per load-balanced endpoint)
+// with a shared, homogeneous policy. Created with NewGroup().
+//
+// See internal/breaker.Group for detailed documentation.
+type Group = breaker.Group
+
+// AggregateView presents several breakers protecting one downstream system as
+// a single logical breaker for alerting. Created with NewAggregateView().
+//
+// See internal/breaker.AggregateView for detailed documentation.
+type AggregateView = breaker.AggregateView
+
+// Registry holds independently configured circuit breakers by name. Created
+// with NewRegistry().
+//
+// See internal/breaker.Registry for detailed documentation.
+type Registry = breaker.Registry
+
+// NamedMetrics pairs a breaker's name with its metrics. Returned by
+// Registry.Collect().
+type NamedMetrics = breaker.NamedMetrics
+
+// ApplyError is returned by Registry.ApplyAll() when a batch update is rejected.
+// It names the breaker and the field that failed; nothing in the batch is left
+// applied.
+//
+// See internal/breaker.ApplyError for detailed documentation.
+type ApplyError = breaker.ApplyError
+
+// ExecuteOpts are per-call options for CircuitBreaker.ExecuteWithOpts() and
+// ExecuteContextWithOpts(), such as whether the call may act as a half-open
+// recovery probe.
+//
+// See internal/breaker.ExecuteOpts for detailed documentation.
+type ExecuteOpts = breaker.ExecuteOpts
+
+// Fallback is one named tier of a fallback chain for
+// CircuitBreaker.ExecuteWithFallbacks(). Calls it serves are counted in
+// Metrics.FallbackServed under its name.
+type Fallback = breaker.Fallback
+
+// WindowInfo is the context of a trip decision passed to
+// Settings.ReadyToTripEx: the window start, the current time, the thresholds
+// and whether the default trip rule would trip.
+//
+// See internal/breaker.WindowInfo for detailed documentation.
+type WindowInfo = breaker.WindowInfo
+
+// NamedState pairs a breaker's name with its state. DegradationHeaderOpts.Format
+// receives one for each breaker that is not Closed.
+type NamedState = breaker.NamedState
+
+// DegradationHeaderOpts are options for DegradationHeaderWithOpts(): the header
+// name and value format.
+//
+// See internal/breaker.DegradationHeaderOpts for detailed documentation.
+type DegradationHeaderOpts = breaker.DegradationHeaderOpts
+
+// DecisionEngine replaces the trip rule with a custom algorithm (see
+// Settings.Engine). Implementations must be safe for concurrent use.
+// See internal/breaker.DecisionEngine for detailed documentation.
+type DecisionEngine = breaker.DecisionEngine
+
+// EWMAEngine is a DecisionEngine tripping on an exponentially weighted failure
+// rate with a configurable half-life. Created with NewEWMAEngine().
+type EWMAEngine = breaker.EWMAEngine
+
+// TransitionLoserBehavior controls requests that lose the race to move the
+// circuit from Open to HalfOpen. Set via Settings.TransitionLoserBehavior.
+type TransitionLoserBehavior = breaker.TransitionLoserBehavior
+
+// StuckOpenAction selects the remediation taken when a circuit stays open longer
+// than Settings.MaxOpenDuration. Set via Settings.StuckOpenAction.
+type StuckOpenAction = breaker.StuckOpenAction
+
+// ClassifierPanicOutcome selects how a call is recorded when IsSuccessful or
+// OutcomeWeight panics. Set via Settings.ClassifierPanicOutcome.
+type ClassifierPanicOutcome = breaker.ClassifierPanicOutcome
+
+// Recommendation is a FailureRateThreshold suggested from the observed baseline
+// failure rate. Returned by the Recommendation() method when
+// Settings.RecommendationWindow is set.
+//
+// See internal/breaker.Recommendation for detailed field documentation.
+type Recommendation = breaker.Recommendation
+
+// RecommendationConfidence indicates how much data backs a Recommendation.
+type RecommendationConfidence = breaker.RecommendationConfidence
+
+// DependencyOpenError is returned by Execute() when a dependency declared with
+// DependsOn() is open. It wraps ErrDependencyOpen and names the open dependency.
+//
+// See internal/breaker.DependencyOpenError for detailed documentation.
+type DependencyOpenError = breaker.DependencyOpenError
+
+// TooManyRequestsError is returned by Execute() with Settings.ProbeRejectionDetails
+// when a HalfOpen circuit rejects a request because MaxRequests probes are in
+// flight or the probe budget is spent.
+// It wraps ErrTooManyRequests and reports whether a probe is executing and for
+// how long.
+//
+// See internal/breaker.TooManyRequestsError for detailed documentation.
+type TooManyRequestsError = breaker.TooManyRequestsError
+
+// PanicError is returned by Execute() in place of a panic in the request
+// function when Settings.RecoverPanics is set. It carries the panic value and
+// stack, and unwraps to the value if it is an error.
+//
+// See internal/breaker.PanicError for detailed documentation.
+type PanicError = breaker.PanicError
+
+// Issue describes one problem found by ValidateSettings: a stable code, a
+// severity, the offending Settings field(s), and a human-readable message.
+// Issue implements error.
+//
+// See internal/breaker.Issue for detailed field documentation.
+type Issue = breaker.Issue
+
+// IssueCode identifies the kind of problem an Issue describes.
+type IssueCode = breaker.IssueCode
+
+// IssueSeverity classifies an Issue as info, warning, or error.
+type IssueSeverity = breaker.IssueSeverity
+
 // State Constants
 //
-// These constants represent the three possible circuit breaker states.
+// These constants represent the three circuit breaker states, plus the
+// StateDisabled operator override.
 
 const (
 	// StateClosed indicates the circuit is closed (normal operation).
@@ -231,6 +416,173 @@ const (
 	// backend. If they succeed, the circuit closes. If they fail, the circuit
 	// reopens.
 	StateHalfOpen = breaker.StateHalfOpen
+
+	// StateDisabled indicates the breaker was bypassed with Disable(). Every
+	// request passes through uncounted until Enable() resumes the frozen state
+	// machine.
+	StateDisabled = breaker.StateDisabled
+)
+
+// Open Reason Constants
+//
+// These constants identify why the circuit entered the Open state.
+
+const (
+	// OpenReasonNone indicates the circuit is closed (no open reason recorded).
+	OpenReasonNone = breaker.OpenReasonNone
+
+	// OpenReasonFailureRate indicates the adaptive failure rate threshold was exceeded.
+	OpenReasonFailureRate = breaker.OpenReasonFailureRate
+
+	// OpenReasonConsecutiveFailures indicates the default static consecutive
+	// failure threshold was exceeded.
+	OpenReasonConsecutiveFailures = breaker.OpenReasonConsecutiveFailures
+
+	// OpenReasonReadyToTrip indicates a custom ReadyToTrip callback tripped the circuit.
+	OpenReasonReadyToTrip = breaker.OpenReasonReadyToTrip
+
+	// OpenReasonProbeFailed indicates a half-open probe failed and the circuit reopened.
+	OpenReasonProbeFailed = breaker.OpenReasonProbeFailed
+
+	// OpenReasonCycleLimit indicates MaxRequestsPerCycle requests were admitted
+	// during the closed period.
+	OpenReasonCycleLimit = breaker.OpenReasonCycleLimit
+
+	// OpenReasonEngine indicates a custom Settings.Engine tripped the circuit.
+	OpenReasonEngine = breaker.OpenReasonEngine
+
+	// OpenReasonMirrored indicates the circuit followed the breaker it mirrors
+	// (see MirrorFrom()) into Open.
+	OpenReasonMirrored = breaker.OpenReasonMirrored
+)
+
+// Transition Loser Behaviors
+//
+// These constants select how Open → HalfOpen race losers are handled.
+
+const (
+	// TransitionLoserProbe lets losers proceed as probes, limited by MaxRequests (default).
+	TransitionLoserProbe = breaker.TransitionLoserProbe
+
+	// TransitionLoserReject rejects losers with ErrOpenState; only the winner probes.
+	TransitionLoserReject = breaker.TransitionLoserReject
+)
+
+// Stuck-Open Actions
+//
+// These constants select the remediation for a circuit stuck open.
+
+const (
+	// StuckOpenAlert only invokes OnStuckOpen (default).
+	StuckOpenAlert = breaker.StuckOpenAlert
+
+	// StuckOpenForceHalfOpen moves the circuit to HalfOpen so the next request probes.
+	StuckOpenForceHalfOpen = breaker.StuckOpenForceHalfOpen
+
+	// StuckOpenForceClose closes the circuit and admits normal traffic.
+	StuckOpenForceClose = breaker.StuckOpenForceClose
+)
+
+// Classifier Panic Outcomes
+//
+// These constants select how a call is recorded when its classifier panics.
+
+const (
+	// ClassifierPanicDefault classifies the call by err == nil, like
+	// DefaultIsSuccessful (default).
+	ClassifierPanicDefault = breaker.ClassifierPanicDefault
+
+	// ClassifierPanicFailure records the call as a failure (fail closed).
+	ClassifierPanicFailure = breaker.ClassifierPanicFailure
+
+	// ClassifierPanicSuccess records the call as a success (fail open).
+	ClassifierPanicSuccess = breaker.ClassifierPanicSuccess
+
+	// ClassifierPanicIgnore records the call as neither success nor failure.
+	ClassifierPanicIgnore = breaker.ClassifierPanicIgnore
+)
+
+// Journal Outcomes
+//
+// These constants describe how a request in the outcome journal ended.
+
+const (
+	// JournalSuccess: the request was recorded as a success.
+	JournalSuccess = breaker.JournalSuccess
+
+	// JournalFailure: the request was recorded as a failure.
+	JournalFailure = breaker.JournalFailure
+
+	// JournalIgnored: the request ran but its outcome was not recorded.
+	JournalIgnored = breaker.JournalIgnored
+
+	// JournalPanic: the request function panicked, recorded as a failure.
+	JournalPanic = breaker.JournalPanic
+)
+
+// Recommendation Confidence Levels
+//
+// These constants indicate how much observed data backs a Recommendation.
+
+const (
+	// ConfidenceNone means too little data to suggest a threshold.
+	ConfidenceNone = breaker.ConfidenceNone
+
+	// ConfidenceLow means a suggestion based on little data.
+	ConfidenceLow = breaker.ConfidenceLow
+
+	// ConfidenceMedium means enough data for AutoTune to apply the suggestion.
+	ConfidenceMedium = breaker.ConfidenceMedium
+
+	// ConfidenceHigh means most of the window is covered with ample traffic.
+	ConfidenceHigh = breaker.ConfidenceHigh
+)
+
+// LatencyOverflowBound is the upper bound LatencyHistogram() reports for its
+// overflow bucket (latencies above ~65s). Export it as +Inf.
+const LatencyOverflowBound = breaker.LatencyOverflowBound
+
+// DefaultDegradationHeader is the response header set by DegradationHeader().
+const DefaultDegradationHeader = breaker.DefaultDegradationHeader
+
+// Settings Validation Constants
+//
+// These constants classify the issues reported by ValidateSettings.
+
+const (
+	// SeverityInfo marks a setting that relies on an implicit default. Never fatal.
+	SeverityInfo = breaker.SeverityInfo
+
+	// SeverityWarning marks a setting that is ignored or shadowed by another
+	// setting. Fatal only when Settings.Strict is true.
+	SeverityWarning = breaker.SeverityWarning
+
+	// SeverityError marks an invalid setting. New() panics on it.
+	SeverityError = breaker.SeverityError
+
+	// IssueOutOfRange indicates a field value is outside its valid range.
+	IssueOutOfRange = breaker.IssueOutOfRange
+
+	// IssueUnknownValue indicates an enum field holds an unknown value.
+	IssueUnknownValue = breaker.IssueUnknownValue
+
+	// IssueThresholdOrder indicates two related rate thresholds are in the wrong order.
+	IssueThresholdOrder = breaker.IssueThresholdOrder
+
+	// IssueIgnoredField indicates a field is set but has no effect.
+	IssueIgnoredField = breaker.IssueIgnoredField
+
+	// IssueShadowedField indicates a field is set but another field takes precedence.
+	IssueShadowedField = breaker.IssueShadowedField
+
+	// IssueSuspiciousTiming indicates durations that are unlikely to behave as intended.
+	IssueSuspiciousTiming = breaker.IssueSuspiciousTiming
+
+	// IssueImplicitDefault indicates a zero value that is replaced by a default.
+	IssueImplicitDefault = breaker.IssueImplicitDefault
+
+	// IssueInvalidLabel indicates a Labels key that is empty or reserved.
+	IssueInvalidLabel = breaker.IssueInvalidLabel
 )
 
 // Errors
@@ -248,8 +600,65 @@ var (
 	// attempted in the HalfOpen state. The circuit breaker limits concurrent
 	// requests during recovery testing (controlled by MaxRequests setting).
 	// This error indicates the circuit is testing recovery and additional
-	// concurrent requests should wait or fail fast.
+	// concurrent requests should wait or fail fast. With
+	// Settings.ProbeRejectionDetails it is returned wrapped in a
+	// *TooManyRequestsError; match it with errors.Is.
 	ErrTooManyRequests = breaker.ErrTooManyRequests
+
+	// ErrServedStale is returned (wrapped, together with the rejection) by
+	// ExecuteCached() when the circuit rejected the call and the last cached
+	// result was returned instead. The result is valid but may be out of date;
+	// match it with errors.Is.
+	ErrServedStale = breaker.ErrServedStale
+
+	// ErrTooManyConcurrent is returned when all MaxConcurrent bulkhead slots are
+	// busy and none frees up within MaxConcurrentWait. The request is not executed
+	// and is not counted toward circuit statistics.
+	ErrTooManyConcurrent = breaker.ErrTooManyConcurrent
+
+	// ErrDeadlineTooShort is returned by ExecuteContext when PredictiveReject is
+	// enabled and the context deadline leaves less time than the learned p95
+	// latency of the backend. The request is rejected before execution and is
+	// not counted toward circuit statistics.
+	ErrDeadlineTooShort = breaker.ErrDeadlineTooShort
+
+	// ErrIgnoreOutcome can be returned from a request function (alone or joined
+	// with the real error via errors.Join) to mark the execution as not
+	// attributable to the backend. The outcome is neither a success nor a failure,
+	// and the caller receives the underlying error with the sentinel removed.
+	ErrIgnoreOutcome = breaker.ErrIgnoreOutcome
+
+	// ErrDependencyOpen is returned (wrapped in *DependencyOpenError) when a request
+	// is rejected because a dependency declared with DependsOn() is open. It is
+	// distinct from ErrOpenState: the breaker's own circuit may be closed. The
+	// rejection is not counted against the breaker's own statistics.
+	ErrDependencyOpen = breaker.ErrDependencyOpen
+
+	// ErrDependencyCycle is returned by DependsOn() when the declared dependency
+	// would create a cycle in the dependency graph.
+	ErrDependencyCycle = breaker.ErrDependencyCycle
+
+	// ErrMirrorCycle is returned by MirrorFrom() when the source already
+	// mirrors, directly or transitively, the breaker that would follow it.
+	ErrMirrorCycle = breaker.ErrMirrorCycle
+
+	// ErrAlreadyRegistered is returned (wrapped, with the name) by
+	// Registry.Register() and Configure() when a breaker with that name
+	// already exists.
+	ErrAlreadyRegistered = breaker.ErrAlreadyRegistered
+
+	// ErrNotRegistered is returned (wrapped in *ApplyError) by
+	// Registry.ApplyAll() when an update names an unregistered breaker.
+	ErrNotRegistered = breaker.ErrNotRegistered
+
+	// ErrBreakerClosed is returned by Execute() and ExecuteContext() after
+	// Close() shut the breaker down. Unrelated to StateClosed.
+	ErrBreakerClosed = breaker.ErrBreakerClosed
+
+	// ErrStreamClosedEarly is the error classified by Settings.IsStreamFailure
+	// when a stream returned by ExecuteStream() or ExecuteHTTP() is closed
+	// before EOF.
+	ErrStreamClosedEarly = breaker.ErrStreamClosedEarly
 )
 
 // Constructor and Helper Functions
@@ -287,6 +696,245 @@ var (
 // The returned CircuitBreaker is ready to use and thread-safe.
 var New = breaker.New
 
+// NewChecked creates a new CircuitBreaker, returning validation errors instead of
+// panicking, along with an idempotent cleanup function that stops any background
+// work owned by the breaker.
+//
+// Intended for dependency injection (uber/fx, google/wire). See the fxbreaker
+// module for an fx.Option built on top of it.
+//
+// Example:
+//
+//	breaker, cleanup, err := autobreaker.NewChecked(settings)
+//	if err != nil {
+//	    return err
+//	}
+//	defer cleanup()
+var NewChecked = breaker.NewChecked
+
+// NewBuilder returns a Builder for a breaker with the given name. Each With
+// method validates the values it sets; Build() applies the defaults of New().
+//
+// Example:
+//
+//	breaker := autobreaker.NewBuilder("api-client").
+//	    WithTimeout(10 * time.Second).
+//	    WithAdaptiveThreshold(0.05, 20).
+//	    Build()
+var NewBuilder = breaker.NewBuilder
+
+// NewEWMAEngine returns a DecisionEngine that trips when the failure rate,
+// weighted so each outcome counts half as much every halfLife, exceeds
+// failureRate over at least minObservations (weighted) outcomes. Zero values
+// select a 10s half-life, 5% and 20.
+//
+// Example:
+//
+//	breaker := autobreaker.New(autobreaker.Settings{
+//	    Name:   "api",
+//	    Engine: autobreaker.NewEWMAEngine(30*time.Second, 0.10, 20),
+//	})
+var NewEWMAEngine = breaker.NewEWMAEngine
+
+// ValidateSettings checks settings for invalid values and contradictory
+// combinations (e.g. FailureRateThreshold without AdaptiveThreshold) and returns
+// every issue found, without constructing a breaker. Intended for config tests.
+//
+// Example:
+//
+//	for _, issue := range autobreaker.ValidateSettings(settings) {
+//	    if issue.Severity >= autobreaker.SeverityWarning {
+//	        t.Errorf("%s %v: %s", issue.Code, issue.Fields, issue.Message)
+//	    }
+//	}
+var ValidateSettings = breaker.ValidateSettings
+
+// NewGroup creates a group of circuit breakers, one per key, sharing the given settings.
+//
+// Child breakers are created lazily on first use. Keys used later inherit the
+// group's current settings. Invalid settings cause a panic, as with New().
+//
+// Example:
+//
+//	group := autobreaker.NewGroup(autobreaker.Settings{
+//	    Name:    "user-service",
+//	    Timeout: 10 * time.Second,
+//	}, []string{"10.0.0.1:443", "10.0.0.2:443"})
+//
+//	endpoint := pick(group.HealthyKeys())
+//	result, err := group.Execute(endpoint, call)
+var NewGroup = breaker.NewGroup
+
+// NewAggregateView creates a view over members reporting their worst state,
+// summed metrics and aggregate state changes.
+//
+// Example:
+//
+//	view := autobreaker.NewAggregateView("payments", charge, refund, lookup)
+//	view.Subscribe(func(name string, from, to autobreaker.State) {
+//	    alerts.Send(name, to.String())
+//	})
+var NewAggregateView = breaker.NewAggregateView
+
+// NewRegistry creates an empty registry of named circuit breakers.
+//
+// Example:
+//
+//	registry := autobreaker.NewRegistry()
+//	users := registry.GetOrCreate(autobreaker.Settings{Name: "user-service"})
+//
+//	// Metrics endpoint
+//	for _, nm := range registry.Collect() {
+//	    export(nm.Name, nm.Metrics)
+//	}
+var NewRegistry = breaker.NewRegistry
+
+// Execute runs req through the default registry's breaker for name, creating an
+// adaptive breaker on first use. Handy for scripts and small tools.
+//
+// Example:
+//
+//	result, err := autobreaker.Execute("user-service", func() (interface{}, error) {
+//	    return client.GetUser(id)
+//	})
+var Execute = breaker.Execute
+
+// ExecuteContext is the context-aware variant of Execute.
+//
+// Example:
+//
+//	result, err := autobreaker.ExecuteContext(ctx, "user-service", call)
+var ExecuteContext = breaker.ExecuteContext
+
+// Configure registers the default registry's breaker for name with custom
+// settings. Call it before first use of name; returns an error wrapping
+// ErrAlreadyRegistered if the breaker already exists.
+//
+// Example:
+//
+//	if err := autobreaker.Configure("user-service", autobreaker.Settings{
+//	    Timeout: 10 * time.Second,
+//	}); err != nil {
+//	    log.Fatal(err)
+//	}
+var Configure = breaker.Configure
+
+// Default returns the default registry's breaker for name, creating it on first
+// use, e.g. to read its metrics.
+//
+// Example:
+//
+//	metrics := autobreaker.Default("user-service").Metrics()
+var Default = breaker.Default
+
+// DefaultRegistry returns the registry behind Execute, ExecuteContext,
+// Configure and Default.
+//
+// Example:
+//
+//	for _, nm := range autobreaker.DefaultRegistry().Collect() {
+//	    export(nm.Name, nm.Metrics)
+//	}
+var DefaultRegistry = breaker.DefaultRegistry
+
+// RetryableWithin reports whether a retry within d of the rejection err could be
+// admitted, using the RetryAfter() time.Duration hint of err or any error it
+// wraps. Errors without a hint, including plain ErrOpenState, return false.
+//
+// Example:
+//
+//	if !autobreaker.RetryableWithin(err, time.Until(deadline)) {
+//	    return err // circuit won't probe before the deadline
+//	}
+var RetryableWithin = breaker.RetryableWithin
+
+// SleepUntilHalfOpen sleeps until the circuit that rejected with err may admit a
+// probe, per err's RetryAfter() hint. Returns ctx.Err() if ctx ends first, or err
+// immediately if it carries no hint.
+//
+// Example:
+//
+//	if err := autobreaker.SleepUntilHalfOpen(ctx, err); err != nil {
+//	    return nil, err
+//	}
+//	return breaker.Execute(call) // retry once the circuit may probe
+var SleepUntilHalfOpen = breaker.SleepUntilHalfOpen
+
+// TransportStreamFailure is a Settings.IsStreamFailure classifier that counts
+// only truncated streams (io.ErrUnexpectedEOF) and network errors as failures.
+//
+// Example:
+//
+//	breaker := autobreaker.New(autobreaker.Settings{
+//	    Name:            "downloads",
+//	    IsStreamFailure: autobreaker.TransportStreamFailure,
+//	})
+var TransportStreamFailure = breaker.TransportStreamFailure
+
+// DegradationHeader returns HTTP middleware that sets X-AutoBreaker-Degraded
+// (e.g. "database=open,cache=half-open") on responses while any of the given
+// breakers is not Closed, based on their state when the request is admitted.
+//
+// Example:
+//
+//	handler := autobreaker.DegradationHeader(dbBreaker, cacheBreaker)(mux)
+var DegradationHeader = breaker.DegradationHeader
+
+// DegradationHeaderWithOpts is DegradationHeader() with a custom header name
+// and value format.
+var DegradationHeaderWithOpts = breaker.DegradationHeaderWithOpts
+
+// FormatDegradation formats breakers as comma-separated name=state pairs. It is
+// the default DegradationHeaderOpts.Format.
+var FormatDegradation = breaker.FormatDegradation
+
+// WithBypass returns a copy of ctx whose ExecuteContext() calls run outside the
+// breaker: no admission control, no counting. Each is tallied in
+// Metrics.BypassedCalls. A sharp tool for operator tooling only.
+//
+// Example:
+//
+//	ctx = autobreaker.WithBypass(ctx) // admin "force" retry
+//	result, err := breaker.ExecuteContext(ctx, req)
+var WithBypass = breaker.WithBypass
+
+// WithForceProbeEligible returns a copy of ctx whose ExecuteContextWithOpts()
+// calls may act as half-open probes even when ExecuteOpts.ProbeEligible is
+// unset. A sharp tool: mark only cheap, idempotent calls.
+var WithForceProbeEligible = breaker.WithForceProbeEligible
+
+// WithOutcomeIgnored returns a copy of ctx whose ExecuteContext() calls are
+// admitted as usual but never counted, like returning ErrIgnoreOutcome. A sharp
+// tool: failures hidden this way can never trip the circuit.
+var WithOutcomeIgnored = breaker.WithOutcomeIgnored
+
+// SettingsFromEnv builds Settings for the breaker called name from environment
+// variables <prefix>_<NAME>_<FIELD> (MAX_REQUESTS, INTERVAL, TIMEOUT, ADAPTIVE,
+// FAILURE_RATE, MIN_OBSERVATIONS, WARNING_THRESHOLD, RECOVERY_RATE). Unset
+// variables are left at their defaults; a malformed value returns an error
+// naming the variable.
+//
+// Example:
+//
+//	// AUTOBREAKER_PAYMENTS_TIMEOUT=30s AUTOBREAKER_PAYMENTS_FAILURE_RATE=0.05
+//	settings, err := autobreaker.SettingsFromEnv("AUTOBREAKER", "payments")
+var SettingsFromEnv = breaker.SettingsFromEnv
+
+// SettingsUpdateFromEnv reads the runtime-updatable settings from the variables
+// of SettingsFromEnv; unset variables leave their fields nil.
+var SettingsUpdateFromEnv = breaker.SettingsUpdateFromEnv
+
+// ApplyEnvOverrides applies the environment variables for the breaker's name on
+// top of its code defaults, through UpdateSettings.
+//
+// Example:
+//
+//	breaker := autobreaker.New(autobreaker.Settings{Name: "payments"})
+//	if err := autobreaker.ApplyEnvOverrides(breaker, "AUTOBREAKER"); err != nil {
+//	    log.Fatal(err)
+//	}
+var ApplyEnvOverrides = breaker.ApplyEnvOverrides
+
 // Uint32Ptr returns a pointer to the given uint32 value.
 // Helper function for constructing SettingsUpdate with explicit values.
 //
@@ -0,0 +1,404 @@
+// Package autobreakertest provides a conformance suite for implementations of
+// autobreaker.Breaker, such as decorators that wrap a CircuitBreaker with
+// logging or route requests to per-tenant breakers.
+//
+// RunConformance checks that an implementation preserves the CircuitBreaker
+// contract. Mandatory behaviors are always checked:
+//
+//   - Outcome recording: successes and failures (per Settings.IsSuccessful)
+//     are reflected in Counts
+//   - Tripping: the circuit opens when Settings.ReadyToTrip returns true
+//   - Rejection when open: ErrOpenState without running the request or
+//     counting it
+//   - Half-open: a probe is admitted after Settings.Timeout; success closes the
+//     circuit, failure reopens it; at most Settings.MaxRequests run at once and
+//     the rest are rejected with ErrTooManyRequests
+//   - Panics: propagate to the caller with their original value and count as
+//     failures
+//   - Context: ExecuteContext with a canceled context returns the context error
+//     and does not count the request as a failure
+//   - Interval: Settings.Interval clears the counts in Closed state
+//
+// Optional behaviors are checked only when enabled with an Option.
+//
+// The suite waits out Timeout and Interval in real time, using settings of a
+// few tens of milliseconds; the suite runs in well under a second.
+//
+// Example:
+//
+//	func TestLoggingBreaker(t *testing.T) {
+//	    autobreakertest.RunConformance(t, func(s autobreaker.Settings) autobreaker.Breaker {
+//	        return NewLoggingBreaker(autobreaker.New(s), logger)
+//	    })
+//	}
+package autobreakertest
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/1mb-dev/autobreaker"
+)
+
+// Option enables an optional behavior check in RunConformance.
+type Option int
+
+const (
+	// AdaptiveThreshold checks that the breaker honors Settings.AdaptiveThreshold:
+	// it trips on FailureRateThreshold once MinimumObservations requests are
+	// seen, not before. Enable it for implementations that pass Settings through
+	// to autobreaker.New.
+	AdaptiveThreshold Option = iota + 1
+)
+
+// Timing used by checks that wait for Timeout or Interval to elapse.
+const (
+	shortTimeout = 20 * time.Millisecond
+	waitPast     = 2 * shortTimeout
+)
+
+var errBackend = errors.New("autobreakertest: backend failure")
+
+// factory returns a new Breaker configured with the given settings.
+type factory = func(autobreaker.Settings) autobreaker.Breaker
+
+// check is one behavior checked by RunConformance.
+type check struct {
+	name string
+	run  func(*testing.T, factory)
+}
+
+// RunConformance runs the conformance suite against the breakers returned by
+// factory, one subtest per behavior. factory is called with fresh Settings for
+// every subtest and must return a new Breaker configured with them.
+func RunConformance(t *testing.T, factory func(autobreaker.Settings) autobreaker.Breaker, opts ...Option) {
+	t.Helper()
+
+	tests := []check{
+		{"Name", testName},
+		{"RecordsOutcomes", testRecordsOutcomes},
+		{"IsSuccessful", testIsSuccessful},
+		{"TripsOnReadyToTrip", testTripsOnReadyToTrip},
+		{"RejectsWhenOpen", testRejectsWhenOpen},
+		{"HalfOpenSuccessCloses", testHalfOpenSuccessCloses},
+		{"HalfOpenFailureReopens", testHalfOpenFailureReopens},
+		{"HalfOpenLimitsConcurrency", testHalfOpenLimitsConcurrency},
+		{"PanicCountsAsFailure", testPanicCountsAsFailure},
+		{"ContextCanceledBeforeExecution", testContextCanceledBefore},
+		{"ContextCanceledDuringExecution", testContextCanceledDuring},
+		{"IntervalClearsCounts", testIntervalClearsCounts},
+	}
+	for _, opt := range opts {
+		switch opt {
+		case AdaptiveThreshold:
+			tests = append(tests, check{"AdaptiveThreshold", testAdaptiveThreshold})
+		default:
+			t.Fatalf("autobreakertest: unknown Option %d", opt)
+		}
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.run(t, factory)
+		})
+	}
+}
+
+func succeed() (interface{}, error) { return "ok", nil }
+
+func fail() (interface{}, error) { return nil, errBackend }
+
+// tripAfter returns settings that open after n consecutive failures.
+func tripAfter(n uint32, s autobreaker.Settings) autobreaker.Settings {
+	s.ReadyToTrip = func(counts autobreaker.Counts) bool {
+		return counts.ConsecutiveFailures >= n
+	}
+	return s
+}
+
+// openBreaker returns a breaker from newBreaker that has been tripped by a failure.
+func openBreaker(t *testing.T, newBreaker factory, s autobreaker.Settings) autobreaker.Breaker {
+	t.Helper()
+	b := newBreaker(tripAfter(1, s))
+	b.Execute(fail)
+	if got := b.State(); got != autobreaker.StateOpen {
+		t.Fatalf("State() after tripping failure = %v, want %v", got, autobreaker.StateOpen)
+	}
+	return b
+}
+
+func testName(t *testing.T, newBreaker factory) {
+	b := newBreaker(autobreaker.Settings{Name: "conformance"})
+	if got := b.Name(); got != "conformance" {
+		t.Errorf("Name() = %q, want %q", got, "conformance")
+	}
+	if got := b.State(); got != autobreaker.StateClosed {
+		t.Errorf("initial State() = %v, want %v", got, autobreaker.StateClosed)
+	}
+}
+
+func testRecordsOutcomes(t *testing.T, newBreaker factory) {
+	b := newBreaker(autobreaker.Settings{Name: "conformance"})
+
+	result, err := b.Execute(succeed)
+	if result != "ok" || err != nil {
+		t.Errorf("Execute(success) = (%v, %v), want (ok, nil)", result, err)
+	}
+	b.Execute(succeed)
+	if _, err := b.Execute(fail); !errors.Is(err, errBackend) {
+		t.Errorf("Execute(failure) error = %v, want the request's error", err)
+	}
+
+	want := autobreaker.Counts{
+		Requests:            3,
+		TotalSuccesses:      2,
+		TotalFailures:       1,
+		ConsecutiveFailures: 1,
+	}
+	if got := b.Counts(); got != want {
+		t.Errorf("Counts() = %+v, want %+v", got, want)
+	}
+}
+
+func testIsSuccessful(t *testing.T, newBreaker factory) {
+	errNotFound := errors.New("not found")
+	b := newBreaker(autobreaker.Settings{
+		Name: "conformance",
+		IsSuccessful: func(err error) bool {
+			return err == nil || errors.Is(err, errNotFound)
+		},
+	})
+
+	if _, err := b.Execute(func() (interface{}, error) { return nil, errNotFound }); !errors.Is(err, errNotFound) {
+		t.Errorf("Execute error = %v, want the request's error", err)
+	}
+	if got := b.Counts(); got.TotalSuccesses != 1 || got.TotalFailures != 0 {
+		t.Errorf("Counts() = %+v, want the error counted as a success", got)
+	}
+}
+
+func testTripsOnReadyToTrip(t *testing.T, newBreaker factory) {
+	b := newBreaker(tripAfter(3, autobreaker.Settings{Name: "conformance"}))
+
+	for i := 1; i <= 2; i++ {
+		b.Execute(fail)
+		if got := b.State(); got != autobreaker.StateClosed {
+			t.Fatalf("State() after %d failures = %v, want %v", i, got, autobreaker.StateClosed)
+		}
+	}
+	b.Execute(fail)
+	if got := b.State(); got != autobreaker.StateOpen {
+		t.Errorf("State() after 3 failures = %v, want %v", got, autobreaker.StateOpen)
+	}
+}
+
+func testRejectsWhenOpen(t *testing.T, newBreaker factory) {
+	b := openBreaker(t, newBreaker, autobreaker.Settings{Name: "conformance", Timeout: time.Minute})
+
+	called := false
+	_, err := b.Execute(func() (interface{}, error) {
+		called = true
+		return succeed()
+	})
+	if !errors.Is(err, autobreaker.ErrOpenState) {
+		t.Errorf("Execute while open error = %v, want ErrOpenState", err)
+	}
+	if called {
+		t.Error("request ran while the circuit was open")
+	}
+
+	_, err = b.ExecuteContext(context.Background(), func() (interface{}, error) {
+		called = true
+		return succeed()
+	})
+	if !errors.Is(err, autobreaker.ErrOpenState) {
+		t.Errorf("ExecuteContext while open error = %v, want ErrOpenState", err)
+	}
+	if called {
+		t.Error("request ran while the circuit was open")
+	}
+
+	if got := b.Counts(); got.Requests != 0 {
+		t.Errorf("Counts().Requests = %d after rejections, want 0", got.Requests)
+	}
+}
+
+func testHalfOpenSuccessCloses(t *testing.T, newBreaker factory) {
+	b := openBreaker(t, newBreaker, autobreaker.Settings{Name: "conformance", Timeout: shortTimeout})
+	time.Sleep(waitPast)
+
+	if _, err := b.Execute(succeed); err != nil {
+		t.Fatalf("probe after Timeout error = %v, want nil", err)
+	}
+	if got := b.State(); got != autobreaker.StateClosed {
+		t.Errorf("State() after successful probe = %v, want %v", got, autobreaker.StateClosed)
+	}
+}
+
+func testHalfOpenFailureReopens(t *testing.T, newBreaker factory) {
+	b := openBreaker(t, newBreaker, autobreaker.Settings{Name: "conformance", Timeout: shortTimeout})
+	time.Sleep(waitPast)
+
+	if _, err := b.Execute(fail); !errors.Is(err, errBackend) {
+		t.Fatalf("probe after Timeout error = %v, want the request's error", err)
+	}
+	if got := b.State(); got != autobreaker.StateOpen {
+		t.Errorf("State() after failed probe = %v, want %v", got, autobreaker.StateOpen)
+	}
+	if _, err := b.Execute(succeed); !errors.Is(err, autobreaker.ErrOpenState) {
+		t.Errorf("Execute after failed probe error = %v, want ErrOpenState", err)
+	}
+}
+
+func testHalfOpenLimitsConcurrency(t *testing.T, newBreaker factory) {
+	const maxRequests = 2
+	b := openBreaker(t, newBreaker, autobreaker.Settings{
+		Name:        "conformance",
+		Timeout:     shortTimeout,
+		MaxRequests: maxRequests,
+	})
+	time.Sleep(waitPast)
+
+	// Hold maxRequests probes inside the request function
+	release := make(chan struct{})
+	var wg sync.WaitGroup
+	for i := 0; i < maxRequests; i++ {
+		started := make(chan struct{})
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			b.Execute(func() (interface{}, error) {
+				close(started)
+				<-release
+				return succeed()
+			})
+		}()
+		select {
+		case <-started:
+		case <-time.After(time.Second):
+			close(release)
+			t.Fatalf("probe %d of %d was not admitted in half-open", i+1, maxRequests)
+		}
+	}
+
+	called := false
+	_, err := b.Execute(func() (interface{}, error) {
+		called = true
+		return succeed()
+	})
+	close(release)
+	wg.Wait()
+
+	if !errors.Is(err, autobreaker.ErrTooManyRequests) {
+		t.Errorf("Execute beyond MaxRequests error = %v, want ErrTooManyRequests", err)
+	}
+	if called {
+		t.Error("request beyond MaxRequests ran in half-open")
+	}
+}
+
+func testPanicCountsAsFailure(t *testing.T, newBreaker factory) {
+	b := newBreaker(autobreaker.Settings{Name: "conformance"})
+
+	recovered := func() (r interface{}) {
+		defer func() { r = recover() }()
+		b.Execute(func() (interface{}, error) {
+			panic("conformance panic")
+		})
+		return nil
+	}()
+
+	if recovered != "conformance panic" {
+		t.Errorf("recovered %v, want the request's panic value", recovered)
+	}
+	if got := b.Counts(); got.Requests != 1 || got.TotalFailures != 1 {
+		t.Errorf("Counts() = %+v, want the panic counted as a failure", got)
+	}
+}
+
+func testContextCanceledBefore(t *testing.T, newBreaker factory) {
+	b := newBreaker(autobreaker.Settings{Name: "conformance"})
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	called := false
+	_, err := b.ExecuteContext(ctx, func() (interface{}, error) {
+		called = true
+		return succeed()
+	})
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("ExecuteContext error = %v, want context.Canceled", err)
+	}
+	if called {
+		t.Error("request ran with an already canceled context")
+	}
+	if got := b.Counts(); got.Requests != 0 {
+		t.Errorf("Counts().Requests = %d, want 0", got.Requests)
+	}
+}
+
+func testContextCanceledDuring(t *testing.T, newBreaker factory) {
+	b := newBreaker(tripAfter(1, autobreaker.Settings{Name: "conformance"}))
+	ctx, cancel := context.WithCancel(context.Background())
+
+	_, err := b.ExecuteContext(ctx, func() (interface{}, error) {
+		cancel()
+		return nil, ctx.Err()
+	})
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("ExecuteContext error = %v, want context.Canceled", err)
+	}
+	if got := b.Counts(); got.TotalFailures != 0 {
+		t.Errorf("Counts() = %+v, want the canceled request not counted as a failure", got)
+	}
+	if got := b.State(); got != autobreaker.StateClosed {
+		t.Errorf("State() after cancellation = %v, want %v", got, autobreaker.StateClosed)
+	}
+}
+
+func testIntervalClearsCounts(t *testing.T, newBreaker factory) {
+	b := newBreaker(autobreaker.Settings{Name: "conformance", Interval: shortTimeout})
+
+	b.Execute(fail)
+	b.Execute(fail)
+	time.Sleep(waitPast)
+	b.Execute(succeed)
+
+	want := autobreaker.Counts{
+		Requests:             1,
+		TotalSuccesses:       1,
+		ConsecutiveSuccesses: 1,
+	}
+	if got := b.Counts(); got != want {
+		t.Errorf("Counts() after Interval = %+v, want %+v", got, want)
+	}
+}
+
+func testAdaptiveThreshold(t *testing.T, newBreaker factory) {
+	b := newBreaker(autobreaker.Settings{
+		Name:                 "conformance",
+		AdaptiveThreshold:    true,
+		FailureRateThreshold: 0.10,
+		MinimumObservations:  20,
+	})
+
+	// 50% failures, but below MinimumObservations
+	for i := 0; i < 5; i++ {
+		b.Execute(succeed)
+		b.Execute(fail)
+	}
+	if got := b.State(); got != autobreaker.StateClosed {
+		t.Fatalf("State() at 10 requests = %v, want %v (below MinimumObservations)", got, autobreaker.StateClosed)
+	}
+
+	for i := 0; i < 5; i++ {
+		b.Execute(succeed)
+		b.Execute(fail)
+	}
+	if got := b.State(); got != autobreaker.StateOpen {
+		t.Errorf("State() at 20 requests = %v, want %v (50%% over 10%% threshold)", got, autobreaker.StateOpen)
+	}
+}
@@ -0,0 +1,37 @@
+package autobreakertest
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+
+	"github.com/1mb-dev/autobreaker"
+)
+
+func TestRunConformance_CircuitBreaker(t *testing.T) {
+	RunConformance(t, func(s autobreaker.Settings) autobreaker.Breaker {
+		return autobreaker.New(s)
+	}, AdaptiveThreshold)
+}
+
+// countingBreaker is a decorator that counts calls before delegating.
+type countingBreaker struct {
+	autobreaker.Breaker
+	calls atomic.Int64
+}
+
+func (b *countingBreaker) Execute(req func() (interface{}, error)) (interface{}, error) {
+	b.calls.Add(1)
+	return b.Breaker.Execute(req)
+}
+
+func (b *countingBreaker) ExecuteContext(ctx context.Context, req func() (interface{}, error)) (interface{}, error) {
+	b.calls.Add(1)
+	return b.Breaker.ExecuteContext(ctx, req)
+}
+
+func TestRunConformance_Decorator(t *testing.T) {
+	RunConformance(t, func(s autobreaker.Settings) autobreaker.Breaker {
+		return &countingBreaker{Breaker: autobreaker.New(s)}
+	})
+}
@@ -0,0 +1,125 @@
+// Package main is a tiny command-line client for introspectbreaker sockets.
+//
+// Start a demo process exposing two breakers, then query it:
+//
+//	go run examples/autobreakerctl/main.go -serve &
+//	go run examples/autobreakerctl/main.go list
+//	go run examples/autobreakerctl/main.go diag payments
+//	go run examples/autobreakerctl/main.go history inventory
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"net"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/1mb-dev/autobreaker"
+	"github.com/1mb-dev/autobreaker/introspectbreaker"
+)
+
+func main() {
+	socket := flag.String("socket", filepath.Join(os.TempDir(), "autobreaker.sock"), "introspection socket path")
+	serve := flag.Bool("serve", false, "run a demo process serving two breakers on -socket")
+	flag.Usage = func() {
+		fmt.Fprintln(os.Stderr, "usage: autobreakerctl [-socket path] list | diag <name> | metrics <name> | history <name>")
+		fmt.Fprintln(os.Stderr, "       autobreakerctl [-socket path] -serve")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	if *serve {
+		if err := runDemo(*socket); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		return
+	}
+	if flag.NArg() == 0 {
+		flag.Usage()
+		os.Exit(2)
+	}
+	if err := query(*socket, strings.Join(flag.Args(), " ")); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+// query sends one command and pretty-prints the response.
+func query(socket, command string) error {
+	conn, err := net.DialTimeout("unix", socket, time.Second)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	conn.SetDeadline(time.Now().Add(5 * time.Second))
+
+	if _, err := fmt.Fprintln(conn, command); err != nil {
+		return err
+	}
+	line, err := bufio.NewReader(conn).ReadBytes('\n')
+	if err != nil {
+		return err
+	}
+
+	var failure struct {
+		Error string `json:"error"`
+	}
+	if json.Unmarshal(line, &failure) == nil && failure.Error != "" {
+		return errors.New(failure.Error)
+	}
+	var out bytes.Buffer
+	if err := json.Indent(&out, line, "", "  "); err != nil {
+		return err
+	}
+	_, err = out.WriteTo(os.Stdout)
+	return err
+}
+
+// runDemo serves a healthy and a tripped breaker until interrupted.
+func runDemo(socket string) error {
+	os.Remove(socket) // A stale socket from an earlier run
+	l, err := net.Listen("unix", socket)
+	if err != nil {
+		return err
+	}
+
+	payments := autobreaker.New(autobreaker.Settings{
+		Name:                   "payments",
+		MetricsHistoryInterval: time.Second,
+	})
+	inventory := autobreaker.New(autobreaker.Settings{Name: "inventory"})
+	for i := 0; i < 6; i++ {
+		inventory.Execute(func() (interface{}, error) { return nil, errors.New("connection refused") })
+	}
+
+	srv, err := introspectbreaker.Serve(l, payments, inventory)
+	if err != nil {
+		l.Close()
+		return err
+	}
+	defer srv.Close()
+	fmt.Printf("Serving payments and inventory on %s (Ctrl+C to stop)\n", socket)
+
+	// Keep payments busy so its history fills in
+	interrupt := make(chan os.Signal, 1)
+	signal.Notify(interrupt, os.Interrupt)
+	tick := time.NewTicker(100 * time.Millisecond)
+	defer tick.Stop()
+	for {
+		select {
+		case <-tick.C:
+			payments.Execute(func() (interface{}, error) { return "ok", nil })
+		case <-interrupt:
+			return nil
+		}
+	}
+}
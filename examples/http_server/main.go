@@ -80,21 +80,23 @@ type Application struct {
 func NewApplication() *Application {
 	return &Application{
 		dbBreaker: autobreaker.New(autobreaker.Settings{
-			Name:                 "database",
-			Timeout:              10 * time.Second,
-			AdaptiveThreshold:    true,
-			FailureRateThreshold: 0.10, // 10% failure rate
-			MinimumObservations:  20,
+			Name:                   "database",
+			Timeout:                10 * time.Second,
+			AdaptiveThreshold:      true,
+			FailureRateThreshold:   0.10, // 10% failure rate
+			MinimumObservations:    20,
+			MetricsHistoryInterval: 5 * time.Second, // /debug/history: last 5 minutes
 			OnStateChange: func(name string, from, to autobreaker.State) {
 				log.Printf("🔌 Circuit %s: %s → %s", name, from, to)
 			},
 		}),
 		apiBreaker: autobreaker.New(autobreaker.Settings{
-			Name:                 "external-api",
-			Timeout:              15 * time.Second,
-			AdaptiveThreshold:    true,
-			FailureRateThreshold: 0.15, // 15% failure rate (more lenient)
-			MinimumObservations:  10,
+			Name:                   "external-api",
+			Timeout:                15 * time.Second,
+			AdaptiveThreshold:      true,
+			FailureRateThreshold:   0.15, // 15% failure rate (more lenient)
+			MinimumObservations:    10,
+			MetricsHistoryInterval: 5 * time.Second,
 			OnStateChange: func(name string, from, to autobreaker.State) {
 				log.Printf("🌐 Circuit %s: %s → %s", name, from, to)
 			},
@@ -134,11 +136,13 @@ func (app *Application) handleHealthCheck(w http.ResponseWriter, r *http.Request
 				"state":        dbMetrics.State.String(),
 				"failure_rate": fmt.Sprintf("%.2f%%", dbMetrics.Metrics.FailureRate*100),
 				"requests":     dbMetrics.Metrics.Counts.Requests,
+				"disabled":     dbMetrics.Disabled,
 			},
 			"external_api": map[string]interface{}{
 				"state":        apiMetrics.State.String(),
 				"failure_rate": fmt.Sprintf("%.2f%%", apiMetrics.Metrics.FailureRate*100),
 				"requests":     apiMetrics.Metrics.Counts.Requests,
+				"disabled":     apiMetrics.Disabled,
 			},
 		},
 	}
@@ -153,6 +157,30 @@ func (app *Application) handleHealthCheck(w http.ResponseWriter, r *http.Request
 	json.NewEncoder(w).Encode(health)
 }
 
+// handleHistory renders each circuit's recent metrics samples as JSON, e.g. for
+// a failure rate sparkline on an admin page.
+func (app *Application) handleHistory(w http.ResponseWriter, r *http.Request) {
+	type point struct {
+		Time        time.Time `json:"time"`
+		State       string    `json:"state"`
+		Requests    uint32    `json:"requests"`
+		Failures    uint32    `json:"failures"`
+		FailureRate float64   `json:"failure_rate"`
+	}
+
+	history := make(map[string][]point)
+	for _, breaker := range []*autobreaker.CircuitBreaker{app.dbBreaker, app.apiBreaker} {
+		points := []point{}
+		for _, s := range breaker.History(0) {
+			points = append(points, point{s.Time, s.State.String(), s.Requests, s.Failures, s.FailureRate})
+		}
+		history[breaker.Name()] = points
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(history)
+}
+
 // handleUser handles user endpoint with database circuit breaker.
 func (app *Application) handleUser(w http.ResponseWriter, r *http.Request) {
 	// Use database circuit breaker
@@ -225,6 +253,9 @@ func main() {
 	// Health check endpoint (no circuit breaker, always responds)
 	mux.HandleFunc("/health", app.handleHealthCheck)
 
+	// Metrics history for admin sparklines
+	mux.HandleFunc("/debug/history", app.handleHistory)
+
 	// User endpoint (protected by database circuit breaker)
 	mux.HandleFunc("/user", app.handleUser)
 
@@ -242,11 +273,13 @@ func main() {
 	log.Println()
 	log.Println("Endpoints:")
 	log.Println("  GET /health - Health check with circuit status")
+	log.Println("  GET /debug/history - Recent metrics samples per circuit (JSON)")
 	log.Println("  GET /user   - User endpoint (database circuit breaker)")
 	log.Println("  GET /data   - Data endpoint (external API circuit breaker)")
 	log.Println()
 	log.Println("Try:")
 	log.Println("  curl http://localhost:8080/health")
+	log.Println("  curl http://localhost:8080/debug/history")
 	log.Println("  curl http://localhost:8080/user")
 	log.Println("  curl http://localhost:8080/data")
 	log.Println()
@@ -25,12 +25,30 @@ type CircuitBreakerCollector struct {
 	consecFailuresDesc *prometheus.Desc
 	failureRateDesc    *prometheus.Desc
 	successRateDesc    *prometheus.Desc
+	disabledDesc       *prometheus.Desc
 }
 
+// maxConstLabels caps the breaker labels exported as constant labels. Every
+// label multiplies series in Prometheus, so large label sets are rejected.
+const maxConstLabels = 8
+
 // NewCircuitBreakerCollector creates a Prometheus collector for a circuit breaker.
-func NewCircuitBreakerCollector(breaker *autobreaker.CircuitBreaker) *CircuitBreakerCollector {
-	// Get breaker name for labels
-	name := breaker.Name()
+//
+// Every metric carries the breaker name as the "name" label, plus the breaker's
+// labels (Settings.Labels) as constant labels. Returns an error if the breaker
+// has more than maxConstLabels labels.
+func NewCircuitBreakerCollector(breaker *autobreaker.CircuitBreaker) (*CircuitBreakerCollector, error) {
+	breakerLabels := breaker.Labels()
+	if len(breakerLabels) > maxConstLabels {
+		return nil, fmt.Errorf("circuit breaker %q has %d labels, at most %d can be exported",
+			breaker.Name(), len(breakerLabels), maxConstLabels)
+	}
+
+	// Breaker name plus its labels ("name" is reserved by autobreaker)
+	labels := prometheus.Labels{"name": breaker.Name()}
+	for k, v := range breakerLabels {
+		labels[k] = v
+	}
 
 	return &CircuitBreakerCollector{
 		breaker: breaker,
@@ -40,51 +58,57 @@ func NewCircuitBreakerCollector(breaker *autobreaker.CircuitBreaker) *CircuitBre
 			"circuit_breaker_state",
 			"Current circuit breaker state (0=closed, 1=open, 2=half-open)",
 			nil,
-			prometheus.Labels{"name": name},
+			labels,
 		),
 		requestsDesc: prometheus.NewDesc(
 			"circuit_breaker_requests_total",
 			"Total number of requests",
 			nil,
-			prometheus.Labels{"name": name},
+			labels,
 		),
 		successesDesc: prometheus.NewDesc(
 			"circuit_breaker_successes_total",
 			"Total number of successful requests",
 			nil,
-			prometheus.Labels{"name": name},
+			labels,
 		),
 		failuresDesc: prometheus.NewDesc(
 			"circuit_breaker_failures_total",
 			"Total number of failed requests",
 			nil,
-			prometheus.Labels{"name": name},
+			labels,
 		),
 		consecSuccessDesc: prometheus.NewDesc(
 			"circuit_breaker_consecutive_successes",
 			"Current consecutive successes",
 			nil,
-			prometheus.Labels{"name": name},
+			labels,
 		),
 		consecFailuresDesc: prometheus.NewDesc(
 			"circuit_breaker_consecutive_failures",
 			"Current consecutive failures",
 			nil,
-			prometheus.Labels{"name": name},
+			labels,
 		),
 		failureRateDesc: prometheus.NewDesc(
 			"circuit_breaker_failure_rate",
 			"Current failure rate (failures/requests)",
 			nil,
-			prometheus.Labels{"name": name},
+			labels,
 		),
 		successRateDesc: prometheus.NewDesc(
 			"circuit_breaker_success_rate",
 			"Current success rate (successes/requests)",
 			nil,
-			prometheus.Labels{"name": name},
+			labels,
 		),
-	}
+		disabledDesc: prometheus.NewDesc(
+			"circuit_breaker_disabled",
+			"Whether the circuit breaker is disabled and bypassing traffic (1=disabled)",
+			nil,
+			labels,
+		),
+	}, nil
 }
 
 // Describe implements prometheus.Collector.
@@ -97,6 +121,7 @@ func (c *CircuitBreakerCollector) Describe(ch chan<- *prometheus.Desc) {
 	ch <- c.consecFailuresDesc
 	ch <- c.failureRateDesc
 	ch <- c.successRateDesc
+	ch <- c.disabledDesc
 }
 
 // Collect implements prometheus.Collector.
@@ -154,6 +179,17 @@ func (c *CircuitBreakerCollector) Collect(ch chan<- prometheus.Metric) {
 		prometheus.GaugeValue,
 		metrics.SuccessRate,
 	)
+
+	// Export bypass mode as a 0/1 gauge
+	disabled := 0.0
+	if metrics.Disabled {
+		disabled = 1
+	}
+	ch <- prometheus.MustNewConstMetric(
+		c.disabledDesc,
+		prometheus.GaugeValue,
+		disabled,
+	)
 }
 
 // Simulate API calls with varying success rates
@@ -177,10 +213,17 @@ func main() {
 		FailureRateThreshold: 0.10, // Trip at 10% failure rate
 		MinimumObservations:  20,
 		Timeout:              5 * time.Second,
+		Labels: map[string]string{
+			"team": "platform",
+			"tier": "critical",
+		},
 	})
 
 	// Create and register Prometheus collector
-	collector := NewCircuitBreakerCollector(breaker)
+	collector, err := NewCircuitBreakerCollector(breaker)
+	if err != nil {
+		log.Fatal(err)
+	}
 	prometheus.MustRegister(collector)
 
 	// Start background worker to make requests
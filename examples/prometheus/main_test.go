@@ -0,0 +1,61 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/1mb-dev/autobreaker"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// TestCollector_ExportsBreakerLabels verifies every exported series carries
+// the breaker name plus its Settings.Labels, and nothing else.
+func TestCollector_ExportsBreakerLabels(t *testing.T) {
+	breaker := autobreaker.New(autobreaker.Settings{
+		Name:   "api",
+		Labels: map[string]string{"team": "payments", "region": "eu"},
+	})
+
+	collector, err := NewCircuitBreakerCollector(breaker)
+	if err != nil {
+		t.Fatalf("NewCircuitBreakerCollector() error = %v", err)
+	}
+
+	registry := prometheus.NewPedanticRegistry()
+	registry.MustRegister(collector)
+
+	families, err := registry.Gather()
+	if err != nil {
+		t.Fatalf("Gather() error = %v", err)
+	}
+	if len(families) != 9 {
+		t.Errorf("got %d metric families, want 9", len(families))
+	}
+
+	want := map[string]string{"name": "api", "team": "payments", "region": "eu"}
+	for _, family := range families {
+		for _, metric := range family.GetMetric() {
+			got := make(map[string]string)
+			for _, pair := range metric.GetLabel() {
+				got[pair.GetName()] = pair.GetValue()
+			}
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("%s labels = %v, want %v", family.GetName(), got, want)
+			}
+		}
+	}
+}
+
+// TestCollector_RejectsTooManyLabels verifies breakers with more than
+// maxConstLabels labels are refused instead of exploding series counts.
+func TestCollector_RejectsTooManyLabels(t *testing.T) {
+	labels := make(map[string]string)
+	for _, k := range []string{"a", "b", "c", "d", "e", "f", "g", "h", "i"} {
+		labels[k] = "x"
+	}
+	breaker := autobreaker.New(autobreaker.Settings{Name: "api", Labels: labels})
+
+	if _, err := NewCircuitBreakerCollector(breaker); err == nil {
+		t.Fatal("NewCircuitBreakerCollector() error = nil, want error for 9 labels")
+	}
+}
@@ -65,12 +65,14 @@ func (cm *ConfigManager) LoadAndApply() error {
 	}
 
 	// Apply update
-	if err := cm.breaker.UpdateSettings(update); err != nil {
+	changes, err := cm.breaker.UpdateSettingsDetailed(update)
+	if err != nil {
 		return fmt.Errorf("failed to update settings: %w", err)
 	}
 
 	cm.lastConfig = config
 	log.Printf("Configuration updated successfully from %s", cm.configFile)
+	logChanges("file:"+cm.configFile, changes)
 	cm.logCurrentConfig()
 
 	return nil
@@ -86,6 +88,24 @@ func (cm *ConfigManager) logCurrentConfig() {
 	log.Printf("  MinimumObservations: %d", diag.MinimumObservations)
 }
 
+// logChanges writes an audit record of an applied update: who, when, and what changed
+func logChanges(source string, changes autobreaker.ChangeSet) {
+	if changes.Empty() {
+		log.Printf("Audit: %s applied no changes", source)
+		return
+	}
+	entry, err := json.Marshal(struct {
+		Source string                `json:"source"`
+		At     time.Time             `json:"at"`
+		Change autobreaker.ChangeSet `json:"change"`
+	}{source, time.Now(), changes})
+	if err != nil {
+		log.Printf("Audit: failed to encode changes: %v", err)
+		return
+	}
+	log.Printf("Audit: %s", entry)
+}
+
 // WatchForSignals sets up signal handler for config reload on SIGHUP
 func (cm *ConfigManager) WatchForSignals() {
 	sigChan := make(chan os.Signal, 1)
@@ -148,18 +168,21 @@ func (cm *ConfigManager) HTTPHandler() http.Handler {
 			MinimumObservations:  config.MinimumObservations,
 		}
 
-		if err := cm.breaker.UpdateSettings(update); err != nil {
+		changes, err := cm.breaker.UpdateSettingsDetailed(update)
+		if err != nil {
 			http.Error(w, fmt.Sprintf("Update failed: %v", err), http.StatusBadRequest)
 			return
 		}
 
 		log.Println("Configuration updated via HTTP API")
+		logChanges("http:"+r.RemoteAddr, changes)
 		cm.logCurrentConfig()
 
 		w.Header().Set("Content-Type", "application/json")
-		json.NewEncoder(w).Encode(map[string]string{
+		json.NewEncoder(w).Encode(map[string]interface{}{
 			"status":  "success",
 			"message": "Configuration updated successfully",
+			"changes": changes,
 		})
 	})
 
@@ -252,13 +275,14 @@ func main() {
 
 	fmt.Println("\n=== Scenario 2: Update Configuration via Code ===")
 	log.Println("Updating threshold to 15% (less sensitive)...")
-	err := breaker.UpdateSettings(autobreaker.SettingsUpdate{
+	changes, err := breaker.UpdateSettingsDetailed(autobreaker.SettingsUpdate{
 		FailureRateThreshold: autobreaker.Float64Ptr(0.15),
 	})
 	if err != nil {
 		log.Printf("Update failed: %v", err)
 	} else {
 		log.Println("Configuration updated successfully")
+		logChanges("code", changes)
 		configMgr.logCurrentConfig()
 	}
 	fmt.Println()
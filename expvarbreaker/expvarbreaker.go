@@ -0,0 +1,73 @@
+// Package expvarbreaker publishes circuit breaker metrics with the standard
+// library's expvar package, for debugging without a metrics stack.
+//
+// Published breakers appear at /debug/vars on any server that serves
+// http.DefaultServeMux (importing expvar registers the handler). The package is
+// separate so that autobreaker itself does not import expvar.
+//
+// Example:
+//
+//	breaker := autobreaker.New(autobreaker.Settings{Name: "payments"})
+//	expvarbreaker.Publish(breaker, "breaker.payments")
+//
+//	// curl localhost:8080/debug/vars
+//	// "breaker.payments": {"failure_rate": 0.02, "failures": 2, "requests": 100, "state": "closed"}
+package expvarbreaker
+
+import (
+	"expvar"
+
+	"github.com/1mb-dev/autobreaker"
+)
+
+// Publish registers cb's key metrics under name as an expvar map with the
+// entries "state" (string), "requests", "failures" and "failure_rate", plus
+// "labels" (an object) if the breaker has labels (see autobreaker.Settings.Labels).
+//
+// With autobreaker.Settings.EpisodeHistory, "last_episode_duration_seconds" and
+// "last_episode_rejected" describe the most recent finished open period (zero
+// before the first one).
+//
+// Each entry is an expvar.Func, so values are read from cb.Metrics() when
+// /debug/vars is served rather than kept up to date on every request. Counts
+// are the current window's (see autobreaker.Counts).
+//
+// Like expvar.Publish, Publish panics if name is already published.
+func Publish(cb *autobreaker.CircuitBreaker, name string) {
+	vars := new(expvar.Map)
+	vars.Set("state", expvar.Func(func() interface{} {
+		return cb.State().String()
+	}))
+	vars.Set("requests", expvar.Func(func() interface{} {
+		return cb.Counts().Requests
+	}))
+	vars.Set("failures", expvar.Func(func() interface{} {
+		return cb.Counts().TotalFailures
+	}))
+	vars.Set("failure_rate", expvar.Func(func() interface{} {
+		return cb.Metrics().FailureRate
+	}))
+	if cb.CurrentSettings().EpisodeHistory > 0 {
+		vars.Set("last_episode_duration_seconds", expvar.Func(func() interface{} {
+			return lastEpisode(cb).Duration.Seconds()
+		}))
+		vars.Set("last_episode_rejected", expvar.Func(func() interface{} {
+			return lastEpisode(cb).Rejected
+		}))
+	}
+	if labels := cb.Labels(); labels != nil {
+		vars.Set("labels", expvar.Func(func() interface{} {
+			return labels
+		}))
+	}
+	expvar.Publish(name, vars)
+}
+
+// lastEpisode returns cb's most recent finished episode, or a zero Episode.
+func lastEpisode(cb *autobreaker.CircuitBreaker) autobreaker.Episode {
+	episodes := cb.Episodes()
+	if len(episodes) == 0 {
+		return autobreaker.Episode{}
+	}
+	return episodes[len(episodes)-1]
+}
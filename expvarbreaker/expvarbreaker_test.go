@@ -0,0 +1,128 @@
+package expvarbreaker
+
+import (
+	"encoding/json"
+	"errors"
+	"expvar"
+	"testing"
+	"time"
+
+	"github.com/1mb-dev/autobreaker"
+)
+
+// published decodes the expvar map published under name.
+func published(t *testing.T, name string) map[string]interface{} {
+	t.Helper()
+	v := expvar.Get(name)
+	if v == nil {
+		t.Fatalf("Expected %q to be published", name)
+	}
+	var got map[string]interface{}
+	if err := json.Unmarshal([]byte(v.String()), &got); err != nil {
+		t.Fatalf("Expected JSON, got %q: %v", v.String(), err)
+	}
+	return got
+}
+
+func TestPublish_ReflectsMetrics(t *testing.T) {
+	cb := autobreaker.New(autobreaker.Settings{Name: "expvar"})
+	Publish(cb, "test.breaker")
+
+	for i := 0; i < 3; i++ {
+		cb.Execute(func() (interface{}, error) { return nil, nil })
+	}
+	cb.Execute(func() (interface{}, error) { return nil, errors.New("boom") })
+
+	metrics := cb.Metrics()
+	got := published(t, "test.breaker")
+	if got["state"] != metrics.State.String() {
+		t.Errorf("Expected state %q, got %v", metrics.State, got["state"])
+	}
+	if got["requests"] != float64(metrics.Counts.Requests) || got["requests"] != 4.0 {
+		t.Errorf("Expected 4 requests, got %v", got["requests"])
+	}
+	if got["failures"] != float64(metrics.Counts.TotalFailures) || got["failures"] != 1.0 {
+		t.Errorf("Expected 1 failure, got %v", got["failures"])
+	}
+	if got["failure_rate"] != metrics.FailureRate {
+		t.Errorf("Expected failure rate %v, got %v", metrics.FailureRate, got["failure_rate"])
+	}
+}
+
+func TestPublish_Labels(t *testing.T) {
+	cb := autobreaker.New(autobreaker.Settings{
+		Name:   "expvar",
+		Labels: map[string]string{"team": "payments"},
+	})
+	Publish(cb, "test.labels")
+
+	labels, ok := published(t, "test.labels")["labels"].(map[string]interface{})
+	if !ok || labels["team"] != "payments" || len(labels) != 1 {
+		t.Errorf("Expected labels {team: payments}, got %v", labels)
+	}
+
+	Publish(autobreaker.New(autobreaker.Settings{Name: "expvar"}), "test.nolabels")
+	if _, ok := published(t, "test.nolabels")["labels"]; ok {
+		t.Error("Expected no labels entry without labels")
+	}
+}
+
+func TestPublish_Lazy(t *testing.T) {
+	cb := autobreaker.New(autobreaker.Settings{Name: "expvar"})
+	Publish(cb, "test.lazy")
+
+	if got := published(t, "test.lazy"); got["requests"] != 0.0 {
+		t.Fatalf("Expected 0 requests, got %v", got["requests"])
+	}
+
+	fail := func() (interface{}, error) { return nil, errors.New("boom") }
+	for i := 0; i < 6; i++ {
+		cb.Execute(fail)
+	}
+	if got := published(t, "test.lazy"); got["state"] != "open" {
+		t.Errorf("Expected published state to follow the breaker, got %v", got["state"])
+	}
+}
+
+func TestPublish_DuplicateNamePanics(t *testing.T) {
+	cb := autobreaker.New(autobreaker.Settings{Name: "expvar"})
+	Publish(cb, "test.duplicate")
+
+	defer func() {
+		if recover() == nil {
+			t.Error("Expected a panic for a duplicate name")
+		}
+	}()
+	Publish(cb, "test.duplicate")
+}
+
+func TestPublish_LastEpisode(t *testing.T) {
+	cb := autobreaker.New(autobreaker.Settings{
+		Name:           "expvar",
+		Timeout:        5 * time.Millisecond,
+		EpisodeHistory: 1,
+		ReadyToTrip:    func(counts autobreaker.Counts) bool { return counts.ConsecutiveFailures >= 1 },
+	})
+	Publish(cb, "test.episode")
+	if got := published(t, "test.episode"); got["last_episode_rejected"] != 0.0 || got["last_episode_duration_seconds"] != 0.0 {
+		t.Fatalf("Expected a zero episode before the first one, got %v", got)
+	}
+
+	cb.Execute(func() (interface{}, error) { return nil, errors.New("boom") })
+	cb.Execute(func() (interface{}, error) { return nil, nil }) // Rejected
+	time.Sleep(15 * time.Millisecond)
+	cb.Execute(func() (interface{}, error) { return nil, nil })
+
+	got := published(t, "test.episode")
+	if got["last_episode_rejected"] != 1.0 {
+		t.Errorf("Expected 1 rejection, got %v", got["last_episode_rejected"])
+	}
+	if d, _ := got["last_episode_duration_seconds"].(float64); d <= 0 {
+		t.Errorf("Expected a positive duration, got %v", got["last_episode_duration_seconds"])
+	}
+
+	Publish(autobreaker.New(autobreaker.Settings{Name: "expvar"}), "test.noepisodes")
+	if _, ok := published(t, "test.noepisodes")["last_episode_rejected"]; ok {
+		t.Error("Expected no episode entries without EpisodeHistory")
+	}
+}
@@ -0,0 +1,72 @@
+// Package fxbreaker integrates autobreaker with uber/fx.
+//
+// It registers circuit breakers from configuration as named fx values and hooks
+// each breaker's cleanup into the fx lifecycle (OnStop). Invalid settings surface
+// as fx construction errors instead of panics.
+//
+// Example:
+//
+//	app := fx.New(
+//	    fxbreaker.Option(
+//	        autobreaker.Settings{Name: "payments", Timeout: 10 * time.Second},
+//	        autobreaker.Settings{Name: "inventory", AdaptiveThreshold: true},
+//	    ),
+//	    fx.Invoke(fx.Annotate(
+//	        func(cb *autobreaker.CircuitBreaker) { /* use breaker */ },
+//	        fx.ParamTags(fxbreaker.Tag("payments")),
+//	    )),
+//	)
+package fxbreaker
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/1mb-dev/autobreaker"
+	"go.uber.org/fx"
+)
+
+// Tag returns the fx struct tag under which the breaker with the given name is provided.
+//
+// Use it with fx.ParamTags or in fx.In structs:
+//
+//	type Params struct {
+//	    fx.In
+//	    Payments *autobreaker.CircuitBreaker `name:"autobreaker.payments"`
+//	}
+func Tag(name string) string {
+	return fmt.Sprintf("name:%q", "autobreaker."+name)
+}
+
+// Option returns an fx.Option that provides one *autobreaker.CircuitBreaker per settings
+// entry, each named by Tag(settings.Name).
+//
+// Breakers are constructed with autobreaker.NewChecked, so invalid settings fail
+// application startup with an error. Each breaker's cleanup runs on fx OnStop.
+func Option(settings ...autobreaker.Settings) fx.Option {
+	opts := make([]fx.Option, 0, len(settings))
+	for _, s := range settings {
+		opts = append(opts, fx.Provide(
+			fx.Annotate(constructor(s), fx.ResultTags(Tag(s.Name))),
+		))
+	}
+	return fx.Module("autobreaker", opts...)
+}
+
+// constructor returns an fx constructor for a single breaker.
+func constructor(settings autobreaker.Settings) func(fx.Lifecycle) (*autobreaker.CircuitBreaker, error) {
+	return func(lc fx.Lifecycle) (*autobreaker.CircuitBreaker, error) {
+		cb, cleanup, err := autobreaker.NewChecked(settings)
+		if err != nil {
+			return nil, fmt.Errorf("fxbreaker: breaker %q: %w", settings.Name, err)
+		}
+
+		lc.Append(fx.Hook{
+			OnStop: func(context.Context) error {
+				cleanup()
+				return nil
+			},
+		})
+		return cb, nil
+	}
+}
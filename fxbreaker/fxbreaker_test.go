@@ -0,0 +1,65 @@
+package fxbreaker
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/1mb-dev/autobreaker"
+	"go.uber.org/fx"
+	"go.uber.org/fx/fxtest"
+)
+
+func TestOption_ProvidesNamedBreakers(t *testing.T) {
+	var payments, inventory *autobreaker.CircuitBreaker
+
+	app := fxtest.New(t,
+		Option(
+			autobreaker.Settings{Name: "payments"},
+			autobreaker.Settings{Name: "inventory"},
+		),
+		fx.Invoke(fx.Annotate(
+			func(p, i *autobreaker.CircuitBreaker) {
+				payments, inventory = p, i
+			},
+			fx.ParamTags(Tag("payments"), Tag("inventory")),
+		)),
+	)
+	app.RequireStart()
+	defer app.RequireStop()
+
+	if payments == nil || payments.Name() != "payments" {
+		t.Errorf("Expected payments breaker, got %v", payments)
+	}
+	if inventory == nil || inventory.Name() != "inventory" {
+		t.Errorf("Expected inventory breaker, got %v", inventory)
+	}
+}
+
+func TestOption_InvalidSettingsError(t *testing.T) {
+	app := fx.New(
+		fx.NopLogger,
+		Option(autobreaker.Settings{
+			Name:                 "bad",
+			AdaptiveThreshold:    true,
+			FailureRateThreshold: 2,
+		}),
+		fx.Invoke(fx.Annotate(
+			func(*autobreaker.CircuitBreaker) {},
+			fx.ParamTags(Tag("bad")),
+		)),
+	)
+
+	err := app.Err()
+	if err == nil {
+		t.Fatal("Expected construction error for invalid settings")
+	}
+	if !strings.Contains(err.Error(), "FailureRateThreshold") {
+		t.Errorf("Expected validation error to surface, got %v", err)
+	}
+}
+
+func TestTag(t *testing.T) {
+	if got := Tag("payments"); got != `name:"autobreaker.payments"` {
+		t.Errorf("Unexpected tag: %s", got)
+	}
+}
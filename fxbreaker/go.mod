@@ -0,0 +1,17 @@
+module github.com/1mb-dev/autobreaker/fxbreaker
+
+go 1.24
+
+replace github.com/1mb-dev/autobreaker => ../
+
+require (
+	github.com/1mb-dev/autobreaker v1.1.2
+	go.uber.org/fx v1.24.0
+)
+
+require (
+	go.uber.org/dig v1.19.0 // indirect
+	go.uber.org/multierr v1.10.0 // indirect
+	go.uber.org/zap v1.26.0 // indirect
+	golang.org/x/sys v0.0.0-20220412211240-33da011f77ad // indirect
+)
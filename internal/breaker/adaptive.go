@@ -2,16 +2,40 @@ package breaker
 
 // defaultAdaptiveReadyToTrip implements percentage-based threshold logic.
 func (cb *CircuitBreaker) defaultAdaptiveReadyToTrip(counts Counts) bool {
+	// RequireFullWindow: no adaptive trips until the first full aligned window
+	if cb.partialWindow.Load() {
+		return false
+	}
+
 	// Need minimum observations before evaluating
 	if counts.Requests < cb.getMinimumObservations() {
 		return false
 	}
 
+	// MinObservationWindow: the minimum must also be met by recent traffic
+	if !cb.hasMinimumObservations(cb.now()) {
+		return false
+	}
+
 	// Calculate failure rate
 	if counts.Requests == 0 {
 		return false
 	}
 
-	failureRate := float64(counts.TotalFailures) / float64(counts.Requests)
-	return failureRate > cb.getFailureRateThreshold()
+	// Baseline: compare against the paired breaker's rate when it has enough data
+	failureRate := cb.failureRate(counts)
+	return cb.rateExceeds(failureRate, cb.tripComparison().threshold)
+}
+
+// defaultRateEpsilon is the default tolerance for failure rate comparisons.
+const defaultRateEpsilon = 1e-9
+
+// rateExceeds reports whether rate is strictly above threshold, treating values
+// within RateEpsilon of the threshold as exactly at it.
+//
+// The comparison is: rate > threshold + epsilon. A rate that is conceptually equal
+// to the threshold (e.g. 10/100 at 0.10) never exceeds it, regardless of float
+// rounding in the division.
+func (cb *CircuitBreaker) rateExceeds(rate, threshold float64) bool {
+	return rate > threshold+cb.rateEpsilon
 }
@@ -250,3 +250,86 @@ func TestAdaptiveSameConfigDifferentTraffic(t *testing.T) {
 		})
 	}
 }
+
+func TestAdaptiveReadyToTrip_ExactThresholdBoundary(t *testing.T) {
+	tests := []struct {
+		name      string
+		threshold float64
+		counts    Counts
+		wantTrip  bool
+	}{
+		{"10/100 at 0.10", 0.10, Counts{Requests: 100, TotalFailures: 10}, false},
+		{"11/100 at 0.10", 0.10, Counts{Requests: 100, TotalFailures: 11}, true},
+		{"1/20 at 0.05", 0.05, Counts{Requests: 20, TotalFailures: 1}, false},
+		{"7/100 at 0.07", 0.07, Counts{Requests: 100, TotalFailures: 7}, false},
+		{"3/10 at 0.3", 0.3, Counts{Requests: 10, TotalFailures: 3}, false},
+		// 1-0.9 computes as 0.09999999999999998, so 10/100 is a hair above it
+		// without a tolerance. RateEpsilon keeps it "at threshold".
+		{"10/100 at 1-0.9", 1 - 0.9, Counts{Requests: 100, TotalFailures: 10}, false},
+		{"11/100 at 1-0.9", 1 - 0.9, Counts{Requests: 100, TotalFailures: 11}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cb := New(Settings{
+				AdaptiveThreshold:    true,
+				FailureRateThreshold: tt.threshold,
+				MinimumObservations:  1,
+			})
+			if got := cb.readyToTrip(tt.counts); got != tt.wantTrip {
+				t.Errorf("readyToTrip(%+v) = %v, want %v", tt.counts, got, tt.wantTrip)
+			}
+		})
+	}
+}
+
+func TestAdaptiveReadyToTrip_CustomRateEpsilon(t *testing.T) {
+	cb := New(Settings{
+		AdaptiveThreshold:    true,
+		FailureRateThreshold: 0.10,
+		MinimumObservations:  1,
+		RateEpsilon:          0.005,
+	})
+
+	// 21/200 = 10.5% is within the custom tolerance (0.10 + 0.005)
+	if cb.readyToTrip(Counts{Requests: 200, TotalFailures: 21}) {
+		t.Error("Expected rate within RateEpsilon not to trip")
+	}
+	// 22/200 = 11% is beyond it
+	if !cb.readyToTrip(Counts{Requests: 200, TotalFailures: 22}) {
+		t.Error("Expected rate beyond RateEpsilon to trip")
+	}
+}
+
+func TestAdaptiveReadyToTrip_WillTripNextAtBoundary(t *testing.T) {
+	cb := New(Settings{
+		AdaptiveThreshold:    true,
+		FailureRateThreshold: 1 - 0.9,
+		MinimumObservations:  10,
+	})
+
+	// 90 successes + 9 failures: next failure makes 10/100, exactly at threshold
+	for i := 0; i < 90; i++ {
+		cb.Execute(successFunc)
+	}
+	for i := 0; i < 9; i++ {
+		cb.Execute(failFunc)
+	}
+
+	if cb.Diagnostics().WillTripNext {
+		t.Error("WillTripNext should be false when next failure lands exactly at threshold")
+	}
+	cb.Execute(failFunc)
+	if cb.State() != StateClosed {
+		t.Errorf("Expected closed at exactly threshold, got %v", cb.State())
+	}
+}
+
+func TestValidateSettings_RateEpsilon(t *testing.T) {
+	if err := validateSettings(&Settings{RateEpsilon: -1e-9}); err == nil {
+		t.Error("Expected error for negative RateEpsilon")
+	}
+	if err := validateSettings(&Settings{RateEpsilon: 0.5}); err == nil {
+		t.Error("Expected error for oversized RateEpsilon")
+	}
+}
@@ -0,0 +1,103 @@
+package breaker
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+const (
+	// defaultMinTimeout and defaultMaxTimeout bound the learned timeout when
+	// MinTimeout and MaxTimeout are not set.
+	defaultMinTimeout = time.Second
+	defaultMaxTimeout = 10 * time.Minute
+
+	// recoveryWeight is how far the learned timeout moves toward each new
+	// recovery estimate (EWMA smoothing factor).
+	recoveryWeight = 0.3
+)
+
+// timeoutLearner is the AdaptiveTimeout state: the learned open wait (int64
+// nanoseconds) and the bounds it is kept within.
+type timeoutLearner struct {
+	minTimeout time.Duration
+	maxTimeout time.Duration
+	learned    atomic.Int64
+}
+
+// newTimeoutLearner returns a learner bounded by minTimeout and maxTimeout
+// (defaulted when zero), starting from timeout.
+func newTimeoutLearner(minTimeout, maxTimeout, timeout time.Duration) *timeoutLearner {
+	if minTimeout == 0 {
+		minTimeout = defaultMinTimeout
+	}
+	if maxTimeout == 0 {
+		maxTimeout = defaultMaxTimeout
+	}
+	l := &timeoutLearner{minTimeout: minTimeout, maxTimeout: maxTimeout}
+	l.learned.Store(int64(l.clamp(timeout)))
+	return l
+}
+
+// adaptiveTimeoutSettings returns AdaptiveTimeout and the effective
+// MinTimeout and MaxTimeout, or zeros when AdaptiveTimeout is not set.
+func (cb *CircuitBreaker) adaptiveTimeoutSettings() (enabled bool, minTimeout, maxTimeout time.Duration) {
+	l := cb.learner
+	if l == nil {
+		return false, 0, 0
+	}
+	return true, l.minTimeout, l.maxTimeout
+}
+
+// effectiveTimeout returns how long the circuit waits in Open: the learned
+// timeout under AdaptiveTimeout, otherwise Timeout.
+func (cb *CircuitBreaker) effectiveTimeout() time.Duration {
+	if cb.learner != nil {
+		return time.Duration(cb.learner.learned.Load())
+	}
+	return cb.getTimeout()
+}
+
+// learnedTimeoutValue returns the learned timeout for Diagnostics, zero when
+// AdaptiveTimeout is not set.
+func (cb *CircuitBreaker) learnedTimeoutValue() time.Duration {
+	if cb.learner == nil {
+		return 0
+	}
+	return time.Duration(cb.learner.learned.Load())
+}
+
+// learnRecovery folds the recovery time of an incident that just closed into
+// the learned timeout. Timestamps are UnixNano: incidentStart is the first trip,
+// lastDown the latest entry into Open (the trip or the last failed probe), and
+// recovered the successful probe.
+//
+// The backend recovered at some point between lastDown and recovered, so the
+// midpoint is taken as the estimate. An incident without timestamps (closed
+// before tracking began) is ignored, as is every incident without
+// AdaptiveTimeout.
+func (cb *CircuitBreaker) learnRecovery(incidentStart, lastDown, recovered int64) {
+	if cb.learner == nil || incidentStart <= 0 || lastDown < incidentStart || recovered < lastDown {
+		return
+	}
+	sample := float64(lastDown-incidentStart) + float64(recovered-lastDown)/2
+
+	l := cb.learner
+	for {
+		current := l.learned.Load()
+		next := float64(current) + recoveryWeight*(sample-float64(current))
+		if l.learned.CompareAndSwap(current, int64(l.clamp(time.Duration(next)))) {
+			return
+		}
+	}
+}
+
+// clamp limits d to [minTimeout, maxTimeout].
+func (l *timeoutLearner) clamp(d time.Duration) time.Duration {
+	if d < l.minTimeout {
+		return l.minTimeout
+	}
+	if d > l.maxTimeout {
+		return l.maxTimeout
+	}
+	return d
+}
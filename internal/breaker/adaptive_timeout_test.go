@@ -0,0 +1,129 @@
+package breaker
+
+import (
+	"testing"
+	"time"
+)
+
+// simulateRecoveries drives learnRecovery with a simulated clock: each incident
+// trips at t=0, probes every learned timeout, and the backend recovers after
+// recoveryTime. Returns the learned timeout after each incident.
+func simulateRecoveries(cb *CircuitBreaker, recoveryTime time.Duration, incidents int) []time.Duration {
+	learned := make([]time.Duration, 0, incidents)
+	clock := time.Unix(1_700_000_000, 0).UnixNano()
+	for i := 0; i < incidents; i++ {
+		start := clock
+		lastDown := start
+		for {
+			clock += int64(cb.effectiveTimeout())
+			if time.Duration(clock-start) >= recoveryTime {
+				break // Probe succeeds
+			}
+			lastDown = clock // Probe fails, circuit reopens
+		}
+		cb.learnRecovery(start, lastDown, clock)
+		learned = append(learned, cb.effectiveTimeout())
+		clock += int64(time.Hour) // Healthy until the next incident
+	}
+	return learned
+}
+
+func TestAdaptiveTimeout_ConvergesTowardRecoveryTime(t *testing.T) {
+	cb := New(Settings{Name: "adaptive-timeout", Timeout: time.Minute, AdaptiveTimeout: true})
+
+	learned := simulateRecoveries(cb, 2*time.Second, 40)
+
+	for i := 1; i < 10; i++ {
+		if learned[i] >= learned[i-1] {
+			t.Fatalf("Expected the learned timeout to shrink while far above recovery time, got %v", learned[:10])
+		}
+	}
+	for _, got := range learned[len(learned)-10:] {
+		if got < 1500*time.Millisecond || got > 2500*time.Millisecond {
+			t.Fatalf("Expected the learned timeout to settle around 2s, got %v", learned[len(learned)-10:])
+		}
+	}
+}
+
+func TestAdaptiveTimeout_GrowsTowardSlowRecovery(t *testing.T) {
+	cb := New(Settings{Name: "adaptive-timeout-slow", Timeout: time.Second, AdaptiveTimeout: true})
+
+	learned := simulateRecoveries(cb, 30*time.Second, 40)
+
+	if got := learned[len(learned)-1]; got < 20*time.Second || got > 45*time.Second {
+		t.Errorf("Expected the learned timeout to grow toward 30s, got %v", got)
+	}
+}
+
+func TestAdaptiveTimeout_ClampedToBounds(t *testing.T) {
+	cb := New(Settings{
+		Name:            "adaptive-timeout-bounds",
+		Timeout:         time.Minute,
+		AdaptiveTimeout: true,
+		MinTimeout:      5 * time.Second,
+		MaxTimeout:      30 * time.Second,
+	})
+
+	if got := cb.effectiveTimeout(); got != 30*time.Second {
+		t.Errorf("Expected Timeout clamped to MaxTimeout, got %v", got)
+	}
+
+	learned := simulateRecoveries(cb, 100*time.Millisecond, 20)
+	if got := learned[len(learned)-1]; got != 5*time.Second {
+		t.Errorf("Expected the learned timeout held at MinTimeout, got %v", got)
+	}
+}
+
+func TestAdaptiveTimeout_LearnsFromRealRecovery(t *testing.T) {
+	cb := New(tripOnFirstFailure(Settings{
+		Name:            "adaptive-timeout-real",
+		Timeout:         100 * time.Millisecond,
+		AdaptiveTimeout: true,
+		MinTimeout:      time.Millisecond,
+	}))
+
+	if got := cb.Diagnostics().LearnedTimeout; got != 100*time.Millisecond {
+		t.Fatalf("Expected LearnedTimeout to start at Timeout, got %v", got)
+	}
+
+	cb.Execute(failFunc)
+	time.Sleep(110 * time.Millisecond)
+	cb.Execute(successFunc)
+	if cb.State() != StateClosed {
+		t.Fatalf("Expected Closed after the probe, got %v", cb.State())
+	}
+
+	// One recovery of ~100ms is estimated at ~50ms: 100 + 0.3*(50-100) = 85ms
+	learned := cb.Diagnostics().LearnedTimeout
+	if learned >= 100*time.Millisecond || learned < 80*time.Millisecond {
+		t.Errorf("Expected LearnedTimeout around 85ms, got %v", learned)
+	}
+	if got := cb.openWait(time.Now().UnixNano()); got != learned {
+		t.Errorf("Expected the open wait to use the learned timeout %v, got %v", learned, got)
+	}
+}
+
+func TestAdaptiveTimeout_UpdateTimeoutRestartsLearning(t *testing.T) {
+	cb := New(Settings{Name: "adaptive-timeout-update", Timeout: time.Minute, AdaptiveTimeout: true})
+	simulateRecoveries(cb, 2*time.Second, 5)
+
+	if err := cb.UpdateSettings(SettingsUpdate{Timeout: DurationPtr(20 * time.Second)}); err != nil {
+		t.Fatalf("UpdateSettings: %v", err)
+	}
+
+	if got := cb.Diagnostics().LearnedTimeout; got != 20*time.Second {
+		t.Errorf("Expected learning restarted from the new Timeout, got %v", got)
+	}
+}
+
+func TestAdaptiveTimeout_Disabled(t *testing.T) {
+	cb := New(Settings{Name: "fixed-timeout", Timeout: time.Minute})
+	simulateRecoveries(cb, 2*time.Second, 5)
+
+	if got := cb.effectiveTimeout(); got != time.Minute {
+		t.Errorf("Expected the fixed Timeout, got %v", got)
+	}
+	if got := cb.Diagnostics().LearnedTimeout; got != 0 {
+		t.Errorf("Expected zero LearnedTimeout without AdaptiveTimeout, got %v", got)
+	}
+}
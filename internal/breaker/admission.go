@@ -0,0 +1,420 @@
+package breaker
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// admission is one call through the admission and recording path shared by
+// Execute, ExecuteContext, their variants and the stream calls. The caller
+// sets the inputs; admit fills in the state the call was admitted in and the
+// slots it holds, which release gives back.
+type admission struct {
+	// Inputs
+	ctx        context.Context // nil for calls without one (see err and context)
+	opts       ExecuteOpts     // Probe eligibility and fairness
+	directives directives      // Per-call directives (ExecuteContext only)
+	synthetic  bool            // Admitted normally, kept out of the counts (ExecuteUncounted)
+	observe    bool            // Never rejected, counted but never evaluated (ExecuteObserveOnly)
+
+	// Set by admit
+	rejection      rejection // Which of this breaker's checks turned the call away
+	unprotected    bool      // Run directly, without accounting: disabled or bypassed
+	demand         bool      // Counts as Metrics.Demand
+	state          State     // StateClosed or StateHalfOpen
+	requestCounted bool
+	lease          *probeLease
+	gen            uint64 // Window generation at admission (see lateOutcome)
+	bulkhead       bool   // Holds a MaxConcurrent slot
+}
+
+// rejection is the kind of rejection admit returned for a call, recorded so
+// callers can react to this breaker's own rejections without matching on the
+// error, which a request may also return from a nested breaker.
+type rejection uint8
+
+const (
+	notRejected  rejection = iota
+	rejectedOpen           // ErrOpenState: Open, or held open while Closed
+	rejectedBusy           // ErrTooManyRequests: HalfOpen turned the call away
+)
+
+// reject records the kind of rejection err is and returns err.
+func (a *admission) reject(kind rejection, err error) error {
+	a.rejection = kind
+	return err
+}
+
+// err returns the call's context error, nil for calls without a context.
+func (a *admission) err() error {
+	if a.ctx == nil {
+		return nil
+	}
+	return a.ctx.Err()
+}
+
+// context returns the call's context, context.Background() for calls without one.
+func (a *admission) context() context.Context {
+	if a.ctx == nil {
+		return context.Background()
+	}
+	return a.ctx
+}
+
+// admit runs the admission checks for a call. Returns the rejection if the
+// call may not run. Otherwise the call holds its slots until release, unless
+// it is to run unprotected (a.unprotected), which holds none.
+func (cb *CircuitBreaker) admit(a *admission) error {
+	err := cb.checkAdmission(a)
+	// Demand left out of Requests: rejected, or uncounted on saturation
+	if a.demand && !a.requestCounted {
+		cb.recordUnserved()
+	}
+	return err
+}
+
+// checkAdmission implements admit.
+func (cb *CircuitBreaker) checkAdmission(a *admission) error {
+	// Reject everything once shut down
+	if cb.closed.Load() {
+		return ErrBreakerClosed
+	}
+
+	// Context already canceled/expired: never attempted, so not counted
+	if err := a.err(); err != nil {
+		return err
+	}
+
+	// WithBypass: run outside the breaker, audited
+	if a.directives&directiveBypass != 0 {
+		cb.eventCounter().bypassedCalls.Add(1)
+		a.unprotected = true
+		return nil
+	}
+
+	// Record a metrics history sample if one is due
+	if cb.history != nil {
+		cb.maybeSampleHistory(cb.now())
+	}
+
+	// Run directly, without admission or accounting, while disabled
+	if cb.Disabled() {
+		a.unprotected = true
+		return nil
+	}
+
+	// Every attempt from here on is demand, admitted or not (synthetic calls aren't)
+	a.demand = !a.synthetic
+
+	// Reject without counting while a dependency is open
+	if cb.dependencies.Load() != nil {
+		if err := cb.checkDependencies(); err != nil {
+			return err
+		}
+	}
+
+	// Capture current state for state machine logic
+	currentState := cb.machineState()
+
+	// Remediate a circuit stuck open past MaxOpenDuration (may leave Open)
+	if currentState == StateOpen && cb.watchesStuckOpen() {
+		cb.checkStuckOpen(cb.now())
+		currentState = cb.machineState()
+	}
+
+	// Check state and handle accordingly (Closed first: the hot path)
+	switch currentState {
+	case StateClosed:
+		// Check if interval-based count clearing is needed (only in Closed state)
+		if cb.intervalEnabled.Load() {
+			cb.maybeResetCounts()
+		}
+		// Apply a trip deferred by MinClosedDuration once the window has ended
+		if cb.hold != nil && !cb.admitAfterHold() {
+			return a.reject(rejectedOpen, cb.rejectOpen())
+		}
+		// Open once the closed period's request allowance is used up
+		if cb.maxRequestsPerCycle > 0 && !cb.admitInCycle() {
+			return a.reject(rejectedOpen, cb.rejectOpen())
+		}
+	case StateOpen:
+		// Circuit is open - check if we should transition to half-open
+		// (held: the cached open period is not over, see cacheOpenDeadline)
+		if cb.openHeldNow() || !cb.shouldTransitionToHalfOpen() {
+			// Reject immediately without counting as a request
+			return a.reject(rejectedOpen, cb.rejectOpen())
+		}
+		if !cb.admitAfterTimeout() {
+			// Lost the race and policy says only the winner probes
+			return a.reject(rejectedOpen, cb.rejectOpen())
+		}
+		currentState = StateHalfOpen // Fall through to half-open handling
+	}
+
+	// Let a custom decision engine shed the request
+	if cb.customEngine {
+		if err := cb.engineAdmit(a, currentState); err != nil {
+			return err
+		}
+	}
+
+	if currentState == StateHalfOpen {
+		// Leave recovery probing to probe-eligible requests (or forced ones,
+		// see WithForceProbeEligible)
+		if !a.opts.ProbeEligible && a.directives&directiveProbeEligible == 0 && !cb.ineligibleMayProbe() {
+			return cb.rejectIneligible(a)
+		}
+		// Give another fairness key the next probe
+		if cb.probeKeyRepeats(a.opts.FairnessKey) {
+			return cb.rejectRepeatedKey(a)
+		}
+	}
+
+	// Predictive rejection: fail fast if the deadline can't accommodate typical latency
+	if cb.predictiveReject && a.ctx != nil {
+		if deadline, ok := a.ctx.Deadline(); cb.deadlineTooShort(deadline, ok) {
+			return ErrDeadlineTooShort
+		}
+	}
+
+	// Reserve a bulkhead slot (may wait up to MaxConcurrentWait or until ctx is done)
+	if cb.bulkhead != nil {
+		if err := cb.acquireSlot(a.context()); err != nil {
+			return err
+		}
+		a.bulkhead = true
+	}
+
+	// Request is allowed - attempt to increment count with saturation protection.
+	// If counter is saturated (safeIncrementRequests returns false), request still
+	// proceeds but won't be counted in statistics. Synthetic calls are not requests.
+	a.requestCounted = !a.synthetic && cb.safeIncrementRequests()
+
+	// Check context again after counting but before the expensive operation
+	if err := a.err(); err != nil {
+		cb.unadmit(a)
+		return err
+	}
+
+	// Handle half-open state with request limiting
+	if currentState == StateHalfOpen {
+		lease, ok := cb.tryAcquireProbeSlot(a.opts.FairnessKey)
+		if !ok {
+			// Rejected probes are not requests
+			cb.unadmit(a)
+			return a.reject(rejectedBusy, cb.tooManyRequestsError())
+		}
+		a.lease = lease
+	}
+
+	a.state = currentState
+	a.gen = cb.admissionGeneration()
+	return nil
+}
+
+// unadmit undoes the request count and bulkhead slot of a call turned away
+// late in admit.
+func (cb *CircuitBreaker) unadmit(a *admission) {
+	if a.requestCounted {
+		cb.safeDecrementRequests()
+		a.requestCounted = false
+	}
+	if a.bulkhead {
+		cb.releaseSlot()
+		a.bulkhead = false
+	}
+}
+
+// release gives back the slots held by an admitted call.
+func (cb *CircuitBreaker) release(a *admission) {
+	if a.state == StateHalfOpen {
+		cb.releaseProbeSlot(a.lease)
+	}
+	if a.bulkhead {
+		cb.releaseSlot()
+	}
+}
+
+// run admits req and, if admitted, runs it and records its outcome. If success
+// is non-nil, the value it points to when req returns overrides IsSuccessful
+// and OutcomeWeight for this call (see ExecuteClassified).
+func (cb *CircuitBreaker) run(a *admission, req func() (interface{}, error), success *bool) (interface{}, error) {
+	if success == nil && cb.inPlainClosed() && cb.admitPlain(a) {
+		return cb.runPlain(a, req)
+	}
+	if err := cb.admit(a); err != nil {
+		return nil, err
+	}
+	return cb.runAdmitted(a, req, success)
+}
+
+// runAdmitted runs req for a call admit has let through and records its outcome.
+func (cb *CircuitBreaker) runAdmitted(a *admission, req func() (interface{}, error), success *bool) (interface{}, error) {
+	if a.unprotected {
+		return cb.runDisabled(req)
+	}
+	defer cb.release(a)
+
+	result, err, elapsed, panicked := cb.invokeRecovering(a, req)
+
+	// A panic returned as an error (RecoverPanics) has already been recorded, as
+	// has an abandoned probe (by the HalfOpenProbeTimeout watchdog)
+	if panicked || a.lease.expired() {
+		return result, err
+	}
+	return cb.recordCall(a, result, err, elapsed, success)
+}
+
+// invokeRecovering runs req for an admitted call. A panic is recorded as a
+// failure, then re-raised or, with RecoverPanics, returned as a *PanicError
+// with panicked set.
+func (cb *CircuitBreaker) invokeRecovering(a *admission, req func() (interface{}, error)) (result interface{}, err error, elapsed time.Duration, panicked bool) {
+	defer func() {
+		if r := recover(); r != nil {
+			panicked = true
+			cb.recordCallPanic(a, r)
+
+			// Return the panic as an error if configured
+			if cb.recoverPanics {
+				result, err = nil, newPanicError(r)
+				return
+			}
+
+			// Re-panic to preserve stack trace
+			panic(r)
+		}
+	}()
+
+	// Time the request function alone (for latency tracking and probe classification)
+	timed := cb.trackLatency || cb.classifiesProbe(a.state)
+	var start time.Time
+	if timed {
+		start = time.Now()
+	}
+	result, err = cb.invoke(a.context(), req)
+	if timed {
+		elapsed = time.Since(start)
+	}
+	return result, err, elapsed, false
+}
+
+// recordCallPanic records the panic r raised by an admitted call's request.
+func (cb *CircuitBreaker) recordCallPanic(a *admission, r interface{}) {
+	switch {
+	case a.lease.expired():
+		// The watchdog already recorded the abandoned probe as a failure
+	case cb.lateOutcome(a.gen):
+		// The window the call belonged to is gone (counted in LateOutcomes)
+	case cb.maintenance.Load() || cb.Disabled() || a.directives&directiveIgnoreOutcome != 0:
+		cb.discardOutcome(a.requestCounted, a.state)
+	case a.synthetic:
+		cb.recordSynthetic(false, false, a.state)
+	default:
+		cb.recordOutcome(false)
+		cb.recordPanic(r)
+
+		// Handle state transitions for panic (same as failure)
+		if !a.observe {
+			cb.handleStateTransition(false, a.state)
+		}
+	}
+}
+
+// recordCall records the outcome of an admitted call whose request returned
+// result and err after elapsed, and returns what the caller sees.
+func (cb *CircuitBreaker) recordCall(a *admission, result interface{}, err error, elapsed time.Duration, success *bool) (interface{}, error) {
+	// A call that outlived its window leaves the live counts alone
+	if cb.lateOutcome(a.gen) {
+		if ctxErr := a.err(); ctxErr != nil {
+			return nil, ctxErr
+		}
+		if errors.Is(err, ErrIgnoreOutcome) {
+			err = stripIgnoreOutcome(err)
+		}
+		return result, err
+	}
+
+	// Context canceled/expired during execution: client-initiated, so neither
+	// success nor failure. Undo the request count to keep the invariant
+	// Requests == TotalSuccesses + TotalFailures
+	if ctxErr := a.err(); ctxErr != nil {
+		if a.requestCounted {
+			cb.uncountRequest()
+		}
+		if a.state == StateHalfOpen {
+			cb.refundProbe()
+		}
+		cb.journalIgnored(a.requestCounted, ctxErr)
+		return nil, ctxErr
+	}
+
+	// Record latency only for requests that ran to completion; canceled requests
+	// would bias the learned distribution toward the caller's deadline.
+	if cb.trackLatency {
+		cb.recordLatency(elapsed)
+	}
+
+	// CacheLastSuccess: keep the result for serving while open
+	if err == nil && cb.cache != nil && cb.cache.lastSuccess && a.state == StateClosed && !a.observe {
+		cb.storeResult(result)
+	}
+
+	// Outcomes during maintenance or while disabled are not recorded
+	if cb.maintenance.Load() || cb.Disabled() {
+		cb.discardOutcome(a.requestCounted, a.state)
+		return result, err
+	}
+	// Ignored outcomes (ErrIgnoreOutcome, WithOutcomeIgnored) are attributed
+	// to neither success nor failure
+	ignored := err != nil && errors.Is(err, ErrIgnoreOutcome)
+	if a.directives&directiveIgnoreOutcome != 0 || ignored {
+		cb.discardOutcome(a.requestCounted, a.state)
+		if ignored {
+			err = stripIgnoreOutcome(err)
+		}
+		cb.journalIgnored(a.requestCounted, err)
+		return result, err
+	}
+	// Rejections by a nested breaker say nothing about this backend's health
+	if err != nil && success == nil && !cb.countNestedRejections && isNestedRejection(err) {
+		cb.discardOutcome(a.requestCounted, a.state)
+		cb.journalIgnored(a.requestCounted, err)
+		return result, err
+	}
+	// Synthetic calls are tallied apart from the counts
+	if a.synthetic {
+		cb.completeSynthetic(a.state, result, err, elapsed)
+		return result, err
+	}
+	// If request wasn't counted due to saturation, skip recording
+	if !a.requestCounted {
+		return result, err
+	}
+	// A per-call override wins; otherwise classify with IsSuccessful or
+	// OutcomeWeight (panic-safe). Then record the outcome and handle state
+	// transitions
+	if success != nil {
+		cb.completeCall(a, overrideWeight(*success), result, err, elapsed)
+		return result, err
+	}
+	weight, ok := cb.failureWeightOf(result, err)
+	if !ok {
+		// Classifier panicked under ClassifierPanicIgnore
+		cb.discardOutcome(a.requestCounted, a.state)
+		cb.journalIgnored(a.requestCounted, err)
+		return result, err
+	}
+	cb.completeCall(a, weight, result, err, elapsed)
+	return result, err
+}
+
+// completeCall records the classified outcome of an admitted call and handles
+// state transitions, or only records it for an observe-only call.
+func (cb *CircuitBreaker) completeCall(a *admission, weight float64, result interface{}, err error, elapsed time.Duration) {
+	if a.observe {
+		cb.recordClassified(weight, err, elapsed)
+		return
+	}
+	cb.completeOutcome(weight, a.state, result, err, elapsed)
+}
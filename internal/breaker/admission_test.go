@@ -0,0 +1,85 @@
+package breaker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+)
+
+// executors run a request through each execution path sharing admission and
+// recording, so tests can check they behave alike.
+var executors = []struct {
+	name string
+	run  func(cb *CircuitBreaker, req func() (interface{}, error)) (interface{}, error)
+}{
+	{"Execute", func(cb *CircuitBreaker, req func() (interface{}, error)) (interface{}, error) {
+		return cb.Execute(req)
+	}},
+	{"ExecuteContext", func(cb *CircuitBreaker, req func() (interface{}, error)) (interface{}, error) {
+		return cb.ExecuteContext(context.Background(), req)
+	}},
+	{"ExecuteWithOpts", func(cb *CircuitBreaker, req func() (interface{}, error)) (interface{}, error) {
+		return cb.ExecuteWithOpts(ExecuteOpts{ProbeEligible: true}, req)
+	}},
+}
+
+func TestAdmission_PathsRecordAlike(t *testing.T) {
+	for _, ex := range executors {
+		t.Run(ex.name, func(t *testing.T) {
+			cb := New(Settings{Name: "paths", RecoverPanics: true})
+
+			ex.run(cb, successFunc)
+			ex.run(cb, failFunc)
+			ex.run(cb, panicFunc)
+			ex.run(cb, func() (interface{}, error) {
+				return nil, fmt.Errorf("skip: %w", ErrIgnoreOutcome)
+			})
+			ex.run(cb, func() (interface{}, error) { return nil, ErrOpenState })
+
+			counts := cb.Counts()
+			if counts.Requests != 3 || counts.TotalSuccesses != 1 || counts.TotalFailures != 2 || counts.Panics != 1 {
+				t.Errorf("Expected 1 success and 2 failures (one a panic), got %+v", counts)
+			}
+			if got := cb.Metrics().Demand; got != 5 {
+				t.Errorf("Expected demand 5, got %d", got)
+			}
+		})
+	}
+}
+
+func TestAdmission_PathsProbeAlike(t *testing.T) {
+	const timeout = 30 * time.Second
+	for _, ex := range executors {
+		t.Run(ex.name, func(t *testing.T) {
+			clk := newFakeClock()
+			cb := newWithClock(clk, tripOnFirstFailure(Settings{Name: "paths", Timeout: timeout}))
+
+			ex.run(cb, failFunc)
+			if _, err := ex.run(cb, successFunc); !errors.Is(err, ErrOpenState) {
+				t.Fatalf("Expected ErrOpenState while open, got %v", err)
+			}
+
+			clk.advance(timeout)
+			block := make(chan struct{})
+			done := make(chan struct{})
+			go func() {
+				defer close(done)
+				ex.run(cb, func() (interface{}, error) { <-block; return nil, nil })
+			}()
+			requireState(t, cb, StateHalfOpen, time.Second)
+			for cb.probesInFlight.Load() == 0 {
+				time.Sleep(time.Millisecond)
+			}
+			if _, err := ex.run(cb, successFunc); !errors.Is(err, ErrTooManyRequests) {
+				t.Errorf("Expected ErrTooManyRequests beyond MaxRequests, got %v", err)
+			}
+			close(block)
+			<-done
+			if cb.State() != StateClosed {
+				t.Errorf("Expected the probe to close the circuit, got %v", cb.State())
+			}
+		})
+	}
+}
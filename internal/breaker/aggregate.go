@@ -0,0 +1,271 @@
+package breaker
+
+import (
+	"sync"
+	"weak"
+)
+
+// AggregateView presents several breakers protecting the same downstream
+// system (per endpoint, per method) as one logical breaker, for alerting that
+// wants one signal per system. Created with NewAggregateView().
+//
+// The view does not own its members: they keep their own settings, callbacks
+// and lifecycle, and can be added and removed at any time. It observes member
+// transitions alongside their OnStateChange callbacks, without replacing them.
+// Members only hold a weak reference to the view, so a view that is dropped
+// without removing its members is garbage collected as usual.
+//
+// Thread-safe: All methods are safe for concurrent use.
+type AggregateView struct {
+	name string
+
+	mu          sync.Mutex // Guards members and subscribers
+	members     []*CircuitBreaker
+	subscribers []viewSubscriber
+	nextID      uint64
+
+	// notifyMu orders evaluations, so subscribers see each aggregate
+	// transition once and in order. Changes are queued in pending and
+	// delivered to subscribers on a goroutine of the view's own.
+	notifyMu   sync.Mutex
+	state      State // Last aggregate state queued for subscribers
+	pending    []viewChange
+	delivering bool
+}
+
+type viewSubscriber struct {
+	id uint64
+	fn func(name string, from State, to State)
+}
+
+// viewChange is one aggregate transition awaiting delivery.
+type viewChange struct {
+	from, to State
+}
+
+// NewAggregateView creates a view named name over members. Duplicate and nil
+// members are ignored.
+//
+// Example:
+//
+//	view := autobreaker.NewAggregateView("payments", charge, refund, lookup)
+//	view.Subscribe(func(name string, from, to autobreaker.State) {
+//	    alerts.Send(name, to.String())
+//	})
+func NewAggregateView(name string, members ...*CircuitBreaker) *AggregateView {
+	v := &AggregateView{name: name, state: StateClosed}
+	for _, cb := range members {
+		v.Add(cb)
+	}
+	return v
+}
+
+// Name returns the view's name.
+func (v *AggregateView) Name() string {
+	return v.name
+}
+
+// Add makes cb a member of the view. Adding a member already in the view, or
+// nil, is a no-op. Subscribers are notified if cb changes the aggregate state.
+func (v *AggregateView) Add(cb *CircuitBreaker) {
+	if cb == nil {
+		return
+	}
+	v.mu.Lock()
+	for _, m := range v.members {
+		if m == cb {
+			v.mu.Unlock()
+			return
+		}
+	}
+	v.members = append(v.members, cb)
+	cb.addView(v)
+	v.mu.Unlock()
+
+	v.evaluate()
+}
+
+// Remove removes cb from the view. Removing a breaker that is not a member is
+// a no-op. Subscribers are notified if the removal changes the aggregate state.
+func (v *AggregateView) Remove(cb *CircuitBreaker) {
+	v.mu.Lock()
+	found := false
+	members := make([]*CircuitBreaker, 0, len(v.members))
+	for _, m := range v.members {
+		if m == cb {
+			found = true
+			continue
+		}
+		members = append(members, m)
+	}
+	v.members = members
+	if found {
+		cb.removeView(v)
+	}
+	v.mu.Unlock()
+
+	if found {
+		v.evaluate()
+	}
+}
+
+// Members returns the view's members in the order they were added.
+func (v *AggregateView) Members() []*CircuitBreaker {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	return append([]*CircuitBreaker(nil), v.members...)
+}
+
+// State returns the worst member state: Open if any member is Open, otherwise
+// HalfOpen if any member is HalfOpen, otherwise Closed. Disabled members count
+// as Closed. A view without members is Closed.
+func (v *AggregateView) State() State {
+	state := StateClosed
+	for _, cb := range v.Members() {
+		if s := cb.State(); stateAvailability(s) < stateAvailability(state) {
+			state = s
+		}
+	}
+	return state
+}
+
+// Metrics returns metrics aggregated across the members, as Group.Metrics()
+// does, except that the states are the worst member state (see State()).
+// FailureRate is computed from the summed counts, so it is weighted by each
+// member's traffic; it uses FailureWeight when every member has OutcomeWeight.
+func (v *AggregateView) Metrics() Metrics {
+	members := v.Members()
+	weighted := len(members) > 0
+	for _, cb := range members {
+		weighted = weighted && cb.outcomeWeight != nil
+	}
+	return aggregateMetrics(members, weighted, true)
+}
+
+// Subscribe calls fn with the view's name whenever the aggregate state
+// changes, e.g. from Closed to Open when the first member trips and back once
+// the last one recovers. Member transitions that leave the aggregate state
+// unchanged are not reported. Returns a function that cancels the
+// subscription.
+//
+// fn runs on a goroutine of the view's own, never inside the member's
+// transition, so a slow subscriber does not hold up the member. Changes are
+// delivered one at a time, in order. Panics in fn are recovered and logged.
+func (v *AggregateView) Subscribe(fn func(name string, from State, to State)) (unsubscribe func()) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	v.nextID++
+	id := v.nextID
+	v.subscribers = append(v.subscribers, viewSubscriber{id: id, fn: fn})
+
+	return func() {
+		v.mu.Lock()
+		defer v.mu.Unlock()
+		for i, s := range v.subscribers {
+			if s.id == id {
+				v.subscribers = append(v.subscribers[:i:i], v.subscribers[i+1:]...)
+				return
+			}
+		}
+	}
+}
+
+// evaluate recomputes the aggregate state and, if it changed since the last
+// evaluation, queues the change for subscribers.
+func (v *AggregateView) evaluate() {
+	v.notifyMu.Lock()
+	defer v.notifyMu.Unlock()
+
+	from, to := v.state, v.State()
+	if from == to {
+		return
+	}
+	v.state = to
+
+	v.pending = append(v.pending, viewChange{from: from, to: to})
+	if !v.delivering {
+		v.delivering = true
+		go v.deliver()
+	}
+}
+
+// deliver notifies subscribers of the queued changes, in order, until the
+// queue is empty. Only one deliver runs per view at a time.
+func (v *AggregateView) deliver() {
+	for {
+		v.notifyMu.Lock()
+		changes := v.pending
+		v.pending = nil
+		if len(changes) == 0 {
+			v.delivering = false
+			v.notifyMu.Unlock()
+			return
+		}
+		v.notifyMu.Unlock()
+
+		v.mu.Lock()
+		subscribers := append([]viewSubscriber(nil), v.subscribers...)
+		v.mu.Unlock()
+		for _, c := range changes {
+			for _, s := range subscribers {
+				safeCallOnStateChange(v.name, s.fn, c.from, c.to)
+			}
+		}
+	}
+}
+
+// addView records v as a view cb is a member of. Caller must hold v.mu.
+func (cb *CircuitBreaker) addView(v *AggregateView) {
+	for {
+		current := cb.views.Load()
+		views := append(liveViews(current, nil), weak.Make(v))
+		if cb.views.CompareAndSwap(current, &views) {
+			return
+		}
+	}
+}
+
+// removeView forgets v. Caller must hold v.mu.
+func (cb *CircuitBreaker) removeView(v *AggregateView) {
+	for {
+		current := cb.views.Load()
+		if current == nil {
+			return
+		}
+		var next *[]weak.Pointer[AggregateView]
+		if views := liveViews(current, v); len(views) > 0 {
+			next = &views
+		}
+		if cb.views.CompareAndSwap(current, next) {
+			return
+		}
+	}
+}
+
+// liveViews returns the views in current that have not been garbage collected,
+// except skip.
+func liveViews(current *[]weak.Pointer[AggregateView], skip *AggregateView) []weak.Pointer[AggregateView] {
+	var views []weak.Pointer[AggregateView]
+	if current == nil {
+		return views
+	}
+	for _, p := range *current {
+		if v := p.Value(); v != nil && v != skip {
+			views = append(views, p)
+		}
+	}
+	return views
+}
+
+// notifyViews re-evaluates the views cb is a member of after a transition.
+func (cb *CircuitBreaker) notifyViews() {
+	views := cb.views.Load()
+	if views == nil {
+		return
+	}
+	for _, p := range *views {
+		if v := p.Value(); v != nil {
+			v.evaluate()
+		}
+	}
+}
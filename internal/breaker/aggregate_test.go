@@ -0,0 +1,276 @@
+package breaker
+
+import (
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+)
+
+// viewRecorder records a view's aggregate transitions.
+type viewRecorder struct {
+	transitions []string
+}
+
+func (r *viewRecorder) record(_ string, from, to State) {
+	r.transitions = append(r.transitions, from.String()+"->"+to.String())
+}
+
+// settled waits until v has delivered every aggregate change queued so far.
+func settled(v *AggregateView) {
+	for {
+		v.notifyMu.Lock()
+		delivering := v.delivering
+		v.notifyMu.Unlock()
+		if !delivering {
+			return
+		}
+		runtime.Gosched()
+	}
+}
+
+func TestAggregateView_OneMemberFlaps(t *testing.T) {
+	const timeout = 30 * time.Second
+	clk := newFakeClock()
+	stable1 := newWithClock(clk, tripOnFirstFailure(Settings{Name: "charge"}))
+	var memberTransitions int
+	flapping := newWithClock(clk, tripOnFirstFailure(Settings{
+		Name:          "refund",
+		Timeout:       timeout,
+		OnStateChange: func(string, State, State) { memberTransitions++ },
+	}))
+	stable2 := newWithClock(clk, tripOnFirstFailure(Settings{Name: "lookup"}))
+
+	view := NewAggregateView("payments", stable1, flapping, stable2)
+	var rec viewRecorder
+	view.Subscribe(rec.record)
+
+	for cycle := 0; cycle < 2; cycle++ {
+		stable1.Execute(successFunc)
+		stable2.Execute(successFunc)
+
+		flapping.Execute(failFunc)
+		if view.State() != StateOpen {
+			t.Fatalf("Expected the view Open with one member Open, got %v", view.State())
+		}
+		settled(view)
+
+		clk.advance(timeout)
+		flapping.Execute(successFunc)
+		settled(view)
+		if view.State() != StateClosed {
+			t.Fatalf("Expected the view Closed once every member is, got %v", view.State())
+		}
+	}
+
+	want := []string{
+		"closed->open", "open->half-open", "half-open->closed",
+		"closed->open", "open->half-open", "half-open->closed",
+	}
+	if len(rec.transitions) != len(want) {
+		t.Fatalf("Expected %v, got %v", want, rec.transitions)
+	}
+	for i := range want {
+		if rec.transitions[i] != want[i] {
+			t.Errorf("Expected %v, got %v", want, rec.transitions)
+			break
+		}
+	}
+	if memberTransitions != 6 {
+		t.Errorf("Expected the member's own callback kept, got %d calls", memberTransitions)
+	}
+}
+
+func TestAggregateView_WorstOf(t *testing.T) {
+	const timeout = 30 * time.Second
+	clk := newFakeClock()
+	a := newWithClock(clk, tripOnFirstFailure(Settings{Name: "a", Timeout: timeout}))
+	b := newWithClock(clk, tripOnFirstFailure(Settings{Name: "b", Timeout: timeout, HalfOpenMaxProbes: 2}))
+	view := NewAggregateView("ab", a, b)
+	var rec viewRecorder
+	view.Subscribe(rec.record)
+
+	// b HalfOpen, a Closed: HalfOpen
+	b.Execute(failFunc)
+	clk.advance(timeout)
+	b.Execute(successFunc)
+	if view.State() != StateHalfOpen {
+		t.Fatalf("Expected HalfOpen, got %v", view.State())
+	}
+
+	// a Open, b HalfOpen: Open
+	a.Execute(failFunc)
+	if view.State() != StateOpen {
+		t.Fatalf("Expected Open, got %v", view.State())
+	}
+
+	// a probing while b still HalfOpen: no aggregate change from b's side
+	clk.advance(timeout)
+	a.Execute(successFunc)
+	if view.State() != StateHalfOpen {
+		t.Fatalf("Expected HalfOpen, got %v", view.State())
+	}
+	settled(view)
+
+	want := "closed->open,open->half-open,half-open->open,open->half-open"
+	if got := strings.Join(rec.transitions, ","); got != want {
+		t.Errorf("Expected %s, got %s", want, got)
+	}
+}
+
+func TestAggregateView_Metrics(t *testing.T) {
+	a := New(Settings{Name: "a"})
+	b := New(Settings{Name: "b"})
+	view := NewAggregateView("ab", a, b)
+
+	// a: 1/4 failed, b: 3/4 failed, so 4/8 overall
+	for i := 0; i < 3; i++ {
+		a.Execute(successFunc)
+		b.Execute(failFunc)
+	}
+	a.Execute(failFunc)
+	b.Execute(successFunc)
+
+	m := view.Metrics()
+	if m.Counts.Requests != 8 || m.Counts.TotalFailures != 4 {
+		t.Errorf("Expected summed counts, got %+v", m.Counts)
+	}
+	if m.FailureRate != 0.5 {
+		t.Errorf("Expected a traffic-weighted failure rate of 0.5, got %v", m.FailureRate)
+	}
+	if m.State != StateClosed {
+		t.Errorf("Expected Closed, got %v", m.State)
+	}
+
+	// Worst-of, unlike Group's most-available state
+	for i := 0; i < 6; i++ {
+		b.Execute(failFunc)
+	}
+	if m := view.Metrics(); m.State != StateOpen {
+		t.Errorf("Expected the worst member state, got %v", m.State)
+	}
+
+	empty := NewAggregateView("empty")
+	if m := empty.Metrics(); m.State != StateClosed || m.Counts.Requests != 0 {
+		t.Errorf("Expected an empty view Closed with no counts, got %+v", m)
+	}
+}
+
+func TestAggregateView_AddRemove(t *testing.T) {
+	a := New(tripOnFirstFailure(Settings{Name: "a"}))
+	b := New(tripOnFirstFailure(Settings{Name: "b"}))
+	view := NewAggregateView("ab", a, a, nil)
+	var rec viewRecorder
+	unsubscribe := view.Subscribe(rec.record)
+
+	if len(view.Members()) != 1 {
+		t.Fatalf("Expected duplicates and nil ignored, got %d members", len(view.Members()))
+	}
+
+	// Adding an Open member opens the view
+	b.Execute(failFunc)
+	view.Add(b)
+	if view.State() != StateOpen {
+		t.Fatalf("Expected Open after adding an Open member, got %v", view.State())
+	}
+
+	// Removing it closes the view again and detaches it
+	view.Remove(b)
+	if view.State() != StateClosed {
+		t.Fatalf("Expected Closed after removing the Open member, got %v", view.State())
+	}
+	if b.views.Load() != nil {
+		t.Error("Expected the removed member detached from the view")
+	}
+
+	settled(view)
+	if got := strings.Join(rec.transitions, ","); got != "closed->open,open->closed" {
+		t.Errorf("Expected membership changes reported, got %s", got)
+	}
+
+	// No notifications after unsubscribing
+	unsubscribe()
+	a.Execute(failFunc)
+	settled(view)
+	if len(rec.transitions) != 2 {
+		t.Errorf("Expected no notification after unsubscribe, got %v", rec.transitions)
+	}
+	if view.State() != StateOpen {
+		t.Errorf("Expected Open, got %v", view.State())
+	}
+}
+
+func TestAggregateView_SubscriberPanic(t *testing.T) {
+	a := New(tripOnFirstFailure(Settings{Name: "a"}))
+	view := NewAggregateView("a", a)
+	view.Subscribe(func(string, State, State) { panic("subscriber bug") })
+	var rec viewRecorder
+	view.Subscribe(rec.record)
+
+	a.Execute(failFunc)
+	settled(view)
+	if len(rec.transitions) != 1 {
+		t.Errorf("Expected later subscribers notified despite a panic, got %v", rec.transitions)
+	}
+	if a.State() != StateOpen {
+		t.Errorf("Expected the member unaffected, got %v", a.State())
+	}
+}
+
+func TestAggregateView_SubscribersOutsideTransition(t *testing.T) {
+	a := New(tripOnFirstFailure(Settings{Name: "a"}))
+	b := New(Settings{Name: "b"})
+	view := NewAggregateView("ab", a)
+
+	// A blocked subscriber holds up neither the member nor membership changes
+	release := make(chan struct{})
+	view.Subscribe(func(string, State, State) {
+		<-release
+		view.Add(b) // Subscribers may change membership
+	})
+
+	tripped := make(chan struct{})
+	go func() {
+		a.Execute(failFunc)
+		close(tripped)
+	}()
+	select {
+	case <-tripped:
+	case <-time.After(time.Second):
+		t.Fatal("Expected the member's transition not to wait for the subscriber")
+	}
+	if a.State() != StateOpen {
+		t.Errorf("Expected a Open, got %v", a.State())
+	}
+
+	close(release)
+	settled(view)
+	if len(view.Members()) != 2 {
+		t.Errorf("Expected the subscriber's Add applied, got %d members", len(view.Members()))
+	}
+}
+
+func TestAggregateView_DroppedViewCollected(t *testing.T) {
+	cb := New(tripOnFirstFailure(Settings{Name: "member"}))
+	NewAggregateView("dropped", cb).Subscribe(func(string, State, State) {})
+
+	// Nothing but the member refers to the view
+	runtime.GC()
+	runtime.GC()
+	for _, p := range *cb.views.Load() {
+		if p.Value() != nil {
+			t.Fatal("Expected the dropped view collected despite its member")
+		}
+	}
+
+	// The member keeps working, and forgets the view on its next membership change
+	cb.Execute(failFunc)
+	if cb.State() != StateOpen {
+		t.Errorf("Expected Open, got %v", cb.State())
+	}
+	view := NewAggregateView("kept", cb)
+	if got := len(*cb.views.Load()); got != 1 {
+		t.Errorf("Expected only the live view recorded, got %d", got)
+	}
+	runtime.KeepAlive(view)
+}
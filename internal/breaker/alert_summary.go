@@ -0,0 +1,147 @@
+package breaker
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// AlertSummary returns a concise, single-line status suitable for ChatOps and
+// incident tooling (Slack messages, pager notes).
+//
+// The format is stable and depends on the state:
+//
+//	[payment-api] OPEN — 42% failure rate (210/500), opened 12s ago, probing in 48s
+//	[payment-api] HALF-OPEN — 1/1 probes succeeded, half-open for 2s, recovering from 6 consecutive failures
+//	[payment-api] CLOSED — healthy, 0.4% failure rate (2/500)
+//
+// In Open state the failure rate is the one that opened the circuit (live counts
+// are cleared on the transition). The reason is appended when the rate alone does
+// not explain the trip (consecutive failures, custom ReadyToTrip, failed probe).
+// Once the Timeout has elapsed, "probing in" becomes "probe due".
+//
+// In Closed state the health word is "degraded" while the failure rate is in the
+// warn band (WarnFailureRate), "recovering" while the adaptive rate has not yet
+// dropped to RecoverFailureRate, and "healthy" otherwise. An open dependency (see
+// DependsOn) is appended, since requests are rejected while it is open.
+//
+// In any state, ", disabled (bypassing)" is appended while the breaker is
+// disabled (see Disable), since the state is then frozen and not enforced.
+//
+// Thread-safe: Reads the same atomic snapshot as Diagnostics().
+func (cb *CircuitBreaker) AlertSummary() string {
+	return cb.alertSummary(time.Unix(0, cb.now()))
+}
+
+// alertSummary builds the AlertSummary string relative to now.
+func (cb *CircuitBreaker) alertSummary(now time.Time) string {
+	state := cb.machineState()
+	counts := cb.Counts()
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "[%s] %s — ", cb.name, strings.ToUpper(state.String()))
+
+	switch state {
+	case StateOpen:
+		reason := cb.currentOpenReason(state)
+		var parts []string
+		if reason.Counts.Requests > 0 {
+			parts = append(parts, formatFailureRate(reason.Counts))
+		}
+		if reason.Kind != OpenReasonFailureRate || reason.Counts.Requests == 0 {
+			if cause := reasonPhrase(reason); cause != "" {
+				parts = append(parts, cause)
+			}
+		}
+		if openedAt := cb.openedAt.Load(); openedAt > 0 {
+			elapsed := now.Sub(time.Unix(0, openedAt))
+			parts = append(parts, "opened "+formatSummaryDuration(elapsed)+" ago")
+			if remaining := cb.openWait(openedAt) - elapsed; remaining > 0 {
+				parts = append(parts, "probing in "+formatSummaryDuration(remaining))
+			} else {
+				parts = append(parts, "probe due")
+			}
+		}
+		b.WriteString(strings.Join(parts, ", "))
+
+	case StateHalfOpen:
+		if counts.Requests > 0 {
+			fmt.Fprintf(&b, "%d/%d probes succeeded", counts.TotalSuccesses, counts.Requests)
+		} else {
+			b.WriteString("awaiting probe")
+		}
+		if changedAt := cb.stateChangedAt.Load(); changedAt > 0 {
+			b.WriteString(", half-open for " + formatSummaryDuration(now.Sub(time.Unix(0, changedAt))))
+		}
+		if cause := reasonPhrase(cb.currentOpenReason(state)); cause != "" {
+			b.WriteString(", recovering from " + cause)
+		}
+
+	default:
+		switch {
+		case cb.degraded.Load():
+			b.WriteString("degraded")
+		case !cb.isHealthy(state):
+			b.WriteString("recovering")
+		default:
+			b.WriteString("healthy")
+		}
+		if counts.Requests > 0 {
+			b.WriteString(", " + formatFailureRate(counts))
+		} else {
+			b.WriteString(", no traffic")
+		}
+		if dep := cb.openDependency(); dep != nil {
+			fmt.Fprintf(&b, ", dependency %q open", dep.name)
+		}
+	}
+
+	if cb.Disabled() {
+		b.WriteString(", disabled (bypassing)")
+	}
+
+	return b.String()
+}
+
+// reasonPhrase describes an open reason in a few words for AlertSummary.
+// Failure rate trips are described by the rate itself when counts are known.
+func reasonPhrase(reason OpenReason) string {
+	switch reason.Kind {
+	case OpenReasonFailureRate:
+		if reason.Counts.Requests > 0 {
+			return formatFailureRate(reason.Counts)
+		}
+		return "failure rate threshold exceeded"
+	case OpenReasonConsecutiveFailures:
+		return fmt.Sprintf("%d consecutive failures", reason.Counts.ConsecutiveFailures)
+	case OpenReasonReadyToTrip:
+		return "custom ReadyToTrip"
+	case OpenReasonProbeFailed:
+		return "failed probe"
+	case OpenReasonCycleLimit:
+		return "cycle limit"
+	case OpenReasonEngine:
+		return "decision engine"
+	case OpenReasonMirrored:
+		return reason.Detail
+	default:
+		return ""
+	}
+}
+
+// formatFailureRate formats counts as "42% failure rate (210/500)".
+func formatFailureRate(counts Counts) string {
+	rate := float64(counts.TotalFailures) / float64(counts.Requests)
+	pct := strconv.FormatFloat(rate*100, 'f', 1, 64)
+	pct = strings.TrimSuffix(pct, ".0")
+	return fmt.Sprintf("%s%% failure rate (%d/%d)", pct, counts.TotalFailures, counts.Requests)
+}
+
+// formatSummaryDuration rounds d to whole seconds for display.
+func formatSummaryDuration(d time.Duration) string {
+	if d < 0 {
+		d = 0
+	}
+	return d.Round(time.Second).String()
+}
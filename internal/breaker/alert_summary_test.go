@@ -0,0 +1,147 @@
+package breaker
+
+import (
+	"testing"
+	"time"
+)
+
+func TestAlertSummary_OpenFailureRate(t *testing.T) {
+	cb := New(Settings{
+		Name:                 "payment-api",
+		Timeout:              time.Minute,
+		AdaptiveThreshold:    true,
+		FailureRateThreshold: 0.40,
+		MinimumObservations:  500,
+	})
+
+	for i := 0; i < 290; i++ {
+		cb.Execute(successFunc)
+	}
+	for i := 0; i < 210; i++ {
+		cb.Execute(failFunc)
+	}
+	if cb.State() != StateOpen {
+		t.Fatalf("Expected circuit to trip at 42%%, got %v", cb.State())
+	}
+
+	now := time.Unix(0, cb.openedAt.Load()).Add(12 * time.Second)
+	want := "[payment-api] OPEN — 42% failure rate (210/500), opened 12s ago, probing in 48s"
+	if got := cb.alertSummary(now); got != want {
+		t.Errorf("Expected %q, got %q", want, got)
+	}
+
+	now = now.Add(time.Minute)
+	want = "[payment-api] OPEN — 42% failure rate (210/500), opened 1m12s ago, probe due"
+	if got := cb.alertSummary(now); got != want {
+		t.Errorf("Expected %q, got %q", want, got)
+	}
+}
+
+func TestAlertSummary_OpenConsecutiveFailures(t *testing.T) {
+	cb := New(Settings{Name: "inventory", Timeout: 30 * time.Second})
+
+	for i := 0; i < 6; i++ {
+		cb.Execute(failFunc)
+	}
+
+	now := time.Unix(0, cb.openedAt.Load()).Add(5 * time.Second)
+	want := "[inventory] OPEN — 100% failure rate (6/6), 6 consecutive failures, opened 5s ago, probing in 25s"
+	if got := cb.alertSummary(now); got != want {
+		t.Errorf("Expected %q, got %q", want, got)
+	}
+}
+
+func TestAlertSummary_HalfOpen(t *testing.T) {
+	cb := New(Settings{
+		Name:              "inventory",
+		Timeout:           10 * time.Millisecond,
+		MaxRequests:       3,
+		HalfOpenMaxProbes: 3,
+	})
+
+	for i := 0; i < 6; i++ {
+		cb.Execute(failFunc)
+	}
+	time.Sleep(20 * time.Millisecond)
+
+	cb.Execute(successFunc)
+	if cb.State() != StateHalfOpen {
+		t.Fatalf("Expected half-open while probe budget remains, got %v", cb.State())
+	}
+
+	now := time.Unix(0, cb.stateChangedAt.Load()).Add(2 * time.Second)
+	want := "[inventory] HALF-OPEN — 1/1 probes succeeded, half-open for 2s, recovering from 6 consecutive failures"
+	if got := cb.alertSummary(now); got != want {
+		t.Errorf("Expected %q, got %q", want, got)
+	}
+}
+
+func TestAlertSummary_HalfOpenAwaitingProbe(t *testing.T) {
+	cb := New(Settings{Name: "inventory", Timeout: 10 * time.Millisecond})
+
+	for i := 0; i < 6; i++ {
+		cb.Execute(failFunc)
+	}
+	time.Sleep(20 * time.Millisecond)
+	cb.transitionToHalfOpen()
+
+	now := time.Unix(0, cb.stateChangedAt.Load())
+	want := "[inventory] HALF-OPEN — awaiting probe, half-open for 0s, recovering from 6 consecutive failures"
+	if got := cb.alertSummary(now); got != want {
+		t.Errorf("Expected %q, got %q", want, got)
+	}
+}
+
+func TestAlertSummary_Closed(t *testing.T) {
+	cb := New(Settings{Name: "payment-api"})
+
+	if got, want := cb.AlertSummary(), "[payment-api] CLOSED — healthy, no traffic"; got != want {
+		t.Errorf("Expected %q, got %q", want, got)
+	}
+
+	for i := 0; i < 498; i++ {
+		cb.Execute(successFunc)
+	}
+	cb.Execute(failFunc)
+	cb.Execute(failFunc)
+
+	if got, want := cb.AlertSummary(), "[payment-api] CLOSED — healthy, 0.4% failure rate (2/500)"; got != want {
+		t.Errorf("Expected %q, got %q", want, got)
+	}
+}
+
+func TestAlertSummary_ClosedDegraded(t *testing.T) {
+	cb := New(Settings{
+		Name:                 "payment-api",
+		AdaptiveThreshold:    true,
+		FailureRateThreshold: 0.20,
+		MinimumObservations:  10,
+		WarnFailureRate:      0.05,
+	})
+
+	for i := 0; i < 9; i++ {
+		cb.Execute(successFunc)
+	}
+	cb.Execute(failFunc)
+
+	if got, want := cb.AlertSummary(), "[payment-api] CLOSED — degraded, 10% failure rate (1/10)"; got != want {
+		t.Errorf("Expected %q, got %q", want, got)
+	}
+}
+
+func TestAlertSummary_ClosedDependencyOpen(t *testing.T) {
+	db := New(Settings{Name: "database", Timeout: time.Minute})
+	reports := New(Settings{Name: "reports"})
+	if err := reports.DependsOn(db); err != nil {
+		t.Fatalf("DependsOn failed: %v", err)
+	}
+
+	for i := 0; i < 6; i++ {
+		db.Execute(failFunc)
+	}
+
+	want := `[reports] CLOSED — healthy, no traffic, dependency "database" open`
+	if got := reports.AlertSummary(); got != want {
+		t.Errorf("Expected %q, got %q", want, got)
+	}
+}
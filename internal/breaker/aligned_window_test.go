@@ -0,0 +1,168 @@
+package breaker
+
+import (
+	"testing"
+	"time"
+)
+
+// at returns the UnixNano for 2026-01-01 10:<minute>:<second> UTC.
+func at(minute, second int) int64 {
+	return time.Date(2026, 1, 1, 10, minute, second, 0, time.UTC).UnixNano()
+}
+
+// recordRaw counts an outcome without consulting the real clock.
+func recordRaw(cb *CircuitBreaker, success bool) {
+	cb.safeIncrementRequests()
+	cb.recordOutcome(success)
+}
+
+func TestAlignedWindow_BoundaryCrossing(t *testing.T) {
+	cb := New(Settings{Name: "test", Interval: time.Minute, AlignIntervalToWallClock: true})
+	cb.initWindow(at(0, 37))
+
+	if got, want := cb.lastClearedAt.Load(), at(0, 0); got != want {
+		t.Fatalf("Expected first window to start at :00, got %v", time.Unix(0, got).UTC())
+	}
+
+	recordRaw(cb, false)
+	recordRaw(cb, true)
+
+	// Just before the boundary: no reset
+	cb.maybeResetCountsAt(at(0, 59) + int64(999*time.Millisecond))
+	if got := cb.Counts().Requests; got != 2 {
+		t.Fatalf("Expected no reset before the boundary, got %d requests", got)
+	}
+
+	// Crossing the boundary resets and starts the window at the boundary itself
+	cb.maybeResetCountsAt(at(1, 12))
+	if got := cb.Counts().Requests; got != 0 {
+		t.Errorf("Expected reset at the boundary, got %d requests", got)
+	}
+	if got, want := cb.lastClearedAt.Load(), at(1, 0); got != want {
+		t.Errorf("Expected window start 10:01:00, got %v", time.Unix(0, got).UTC())
+	}
+
+	recordRaw(cb, true)
+	cb.maybeResetCountsAt(at(1, 59))
+	if got := cb.Counts().Requests; got != 1 {
+		t.Errorf("Expected no reset within the window, got %d requests", got)
+	}
+
+	// Skipping several windows lands on the latest boundary
+	cb.maybeResetCountsAt(at(4, 30))
+	if got, want := cb.lastClearedAt.Load(), at(4, 0); got != want {
+		t.Errorf("Expected window start 10:04:00, got %v", time.Unix(0, got).UTC())
+	}
+}
+
+func TestAlignedWindow_ReplicasShareBoundaries(t *testing.T) {
+	settings := Settings{Name: "test", Interval: time.Minute, AlignIntervalToWallClock: true}
+	early := New(settings)
+	late := New(settings)
+	early.initWindow(at(0, 5))
+	late.initWindow(at(0, 50))
+
+	for _, now := range []int64{at(1, 0), at(1, 30), at(2, 1)} {
+		early.maybeResetCountsAt(now)
+		late.maybeResetCountsAt(now)
+		if early.lastClearedAt.Load() != late.lastClearedAt.Load() {
+			t.Errorf("At %v: replicas disagree on window start: %v vs %v",
+				time.Unix(0, now).UTC(),
+				time.Unix(0, early.lastClearedAt.Load()).UTC(),
+				time.Unix(0, late.lastClearedAt.Load()).UTC())
+		}
+	}
+}
+
+func TestAlignedWindow_UnalignedByDefault(t *testing.T) {
+	cb := New(Settings{Name: "test", Interval: time.Minute})
+	cb.initWindow(at(0, 37))
+
+	cb.maybeResetCountsAt(at(1, 12))
+	if got := cb.lastClearedAt.Load(); got != at(0, 37) {
+		t.Errorf("Expected no reset 35s into an unaligned window, got reset at %v", time.Unix(0, got).UTC())
+	}
+	cb.maybeResetCountsAt(at(1, 40))
+	if got := cb.lastClearedAt.Load(); got != at(1, 40) {
+		t.Errorf("Expected unaligned window to restart at the reset time, got %v", time.Unix(0, got).UTC())
+	}
+}
+
+func TestAlignedWindow_PartialFirstWindow(t *testing.T) {
+	// A large interval keeps the real clock inside the window under test
+	interval := 1000 * time.Hour
+	settings := Settings{
+		Name:                     "test",
+		Timeout:                  time.Minute,
+		Interval:                 interval,
+		AlignIntervalToWallClock: true,
+		AdaptiveThreshold:        true,
+		FailureRateThreshold:     0.2,
+		MinimumObservations:      10,
+	}
+
+	tests := []struct {
+		name              string
+		requireFullWindow bool
+		wantTripInPartial bool
+	}{
+		{"evaluated as-is", false, true},
+		{"held off until full window", true, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := settings
+			s.RequireFullWindow = tt.requireFullWindow
+			cb := New(s)
+
+			start := cb.windowStart(time.Now().UnixNano())
+			cb.initWindow(start + int64(time.Second))
+			if got := cb.Diagnostics().PartialWindow; got != tt.requireFullWindow {
+				t.Errorf("Expected PartialWindow %v, got %v", tt.requireFullWindow, got)
+			}
+
+			for i := 0; i < 10; i++ {
+				cb.Execute(failFunc)
+			}
+			if tripped := cb.State() == StateOpen; tripped != tt.wantTripInPartial {
+				t.Fatalf("Expected trip in partial window = %v, got state %v", tt.wantTripInPartial, cb.State())
+			}
+			if !tt.requireFullWindow {
+				return
+			}
+
+			// Static thresholds are not held off: only 10 failures, so still closed
+			if cb.Counts().ConsecutiveFailures != 10 {
+				t.Fatalf("Expected failures counted during partial window, got %+v", cb.Counts())
+			}
+
+			// First boundary: the new window is full and adaptive trips resume
+			cb.maybeResetCountsAt(start + int64(interval))
+			if cb.Diagnostics().PartialWindow {
+				t.Error("Expected PartialWindow cleared at the first boundary")
+			}
+			for i := 0; i < 10; i++ {
+				cb.Execute(failFunc)
+			}
+			if cb.State() != StateOpen {
+				t.Errorf("Expected adaptive trip in the first full window, got %v", cb.State())
+			}
+		})
+	}
+}
+
+func TestAlignedWindow_StartOnBoundaryIsFull(t *testing.T) {
+	cb := New(Settings{
+		Name:                     "test",
+		Interval:                 time.Minute,
+		AlignIntervalToWallClock: true,
+		AdaptiveThreshold:        true,
+		RequireFullWindow:        true,
+	})
+	cb.initWindow(at(5, 0))
+
+	if cb.Diagnostics().PartialWindow {
+		t.Error("Expected a window started exactly on a boundary to be full")
+	}
+}
@@ -0,0 +1,204 @@
+package breaker
+
+import (
+	"fmt"
+	"sort"
+)
+
+// ApplyError is returned by Registry.ApplyAll when a batch of settings updates
+// is rejected. No update in the batch remains applied.
+//
+// It wraps the underlying error (a validation error, or ErrNotRegistered for an
+// unknown breaker name), so callers can match with errors.Is and retrieve the
+// failing breaker and field with errors.As:
+//
+//	var applyErr *autobreaker.ApplyError
+//	if errors.As(err, &applyErr) {
+//	    log.Printf("config rejected: %s.%s: %v", applyErr.Breaker, applyErr.Field, applyErr.Err)
+//	}
+type ApplyError struct {
+	// Breaker is the name of the breaker whose update failed.
+	Breaker string
+
+	// Field is the SettingsUpdate field that failed validation. Empty when the
+	// breaker is not registered.
+	Field string
+
+	// Err is the underlying error.
+	Err error
+}
+
+// Error implements the error interface.
+func (e *ApplyError) Error() string {
+	if e.Field == "" {
+		return fmt.Sprintf("autobreaker: apply %q: %v", e.Breaker, e.Err)
+	}
+	return fmt.Sprintf("autobreaker: apply %q (%s): %v", e.Breaker, e.Field, e.Err)
+}
+
+// Unwrap returns the underlying error.
+func (e *ApplyError) Unwrap() error {
+	return e.Err
+}
+
+// ApplyAll applies a batch of settings updates, keyed by breaker name, with
+// all-or-nothing semantics across the batch.
+//
+// Every update is first validated against its target breaker with the same
+// rules as UpdateSettings. If any name is unregistered or any update is
+// invalid, nothing is applied and an *ApplyError names the offending breaker
+// and field. Only once the whole batch validates are the updates applied, in
+// registration order.
+//
+// The previous value of every field being updated is captured before applying.
+// Should an update still fail partway through the batch, the breakers already
+// updated are restored to those values (in reverse order) before the error is
+// returned. Restoring a value can itself trigger a smart reset (see
+// UpdateSettings).
+//
+// Thread-safe: Safe to call concurrently with Execute() and other methods. The
+// batch is not isolated from concurrent UpdateSettings calls on the same
+// breakers, so a rollback may overwrite a concurrent change.
+//
+// Example - Fleet Config Reload:
+//
+//	updates := map[string]autobreaker.SettingsUpdate{}
+//	for name, cfg := range loadBreakerConfig() {
+//	    updates[name] = autobreaker.SettingsUpdate{
+//	        FailureRateThreshold: autobreaker.Float64Ptr(cfg.Threshold),
+//	    }
+//	}
+//	if err := registry.ApplyAll(updates); err != nil {
+//	    log.Printf("config reload rejected, fleet unchanged: %v", err)
+//	}
+func (r *Registry) ApplyAll(updates map[string]SettingsUpdate) error {
+	_, err := r.ApplyAllDetailed(updates)
+	return err
+}
+
+// ApplyAllDetailed is ApplyAll, additionally returning the ChangeSet of every
+// updated breaker, keyed by name. The map is nil when the batch is rejected.
+func (r *Registry) ApplyAllDetailed(updates map[string]SettingsUpdate) (map[string]ChangeSet, error) {
+	// Resolve targets in registration order so application is deterministic
+	r.mu.RLock()
+	names := make([]string, 0, len(updates))
+	targets := make([]*CircuitBreaker, 0, len(updates))
+	for _, name := range r.names {
+		if _, ok := updates[name]; ok {
+			names = append(names, name)
+			targets = append(targets, r.breakers[name])
+		}
+	}
+	var missing []string
+	for name := range updates {
+		if _, ok := r.breakers[name]; !ok {
+			missing = append(missing, name)
+		}
+	}
+	r.mu.RUnlock()
+
+	if len(missing) > 0 {
+		sort.Strings(missing)
+		return nil, &ApplyError{Breaker: missing[0], Err: ErrNotRegistered}
+	}
+
+	// Validate the whole batch before touching any breaker
+	for i, cb := range targets {
+		update := updates[names[i]]
+		if err := cb.validateUpdate(&update); err != nil {
+			return nil, &ApplyError{
+				Breaker: names[i],
+				Field:   invalidUpdateField(update, cb.adaptiveThreshold),
+				Err:     err,
+			}
+		}
+	}
+
+	// Capture previous values for rollback
+	previous := make([]SettingsUpdate, len(targets))
+	for i, cb := range targets {
+		previous[i] = previousValues(cb.CurrentSettings(), updates[names[i]])
+	}
+
+	changes := make(map[string]ChangeSet, len(targets))
+	for i, cb := range targets {
+		update := updates[names[i]]
+		changeSet, err := cb.UpdateSettingsDetailed(update)
+		if err != nil {
+			// Validated above, so this should not happen; undo what was applied
+			rollbackUpdates(targets[:i], previous[:i])
+			return nil, &ApplyError{
+				Breaker: names[i],
+				Field:   invalidUpdateField(update, cb.adaptiveThreshold),
+				Err:     err,
+			}
+		}
+		changes[names[i]] = changeSet
+	}
+
+	return changes, nil
+}
+
+// rollbackUpdates restores breakers to previously captured values, last
+// updated first. The values were current a moment ago, so they are applied
+// without validation.
+func rollbackUpdates(targets []*CircuitBreaker, previous []SettingsUpdate) {
+	for i := len(targets) - 1; i >= 0; i-- {
+		targets[i].applyUpdate(&previous[i])
+	}
+}
+
+// previousValues returns an update that restores the fields set in update to
+// their values in current.
+func previousValues(current Settings, update SettingsUpdate) SettingsUpdate {
+	var prev SettingsUpdate
+	if update.MaxRequests != nil {
+		prev.MaxRequests = Uint32Ptr(current.MaxRequests)
+	}
+	if update.Interval != nil {
+		prev.Interval = DurationPtr(current.Interval)
+	}
+	if update.Timeout != nil {
+		prev.Timeout = DurationPtr(current.Timeout)
+	}
+	if update.FailureRateThreshold != nil {
+		prev.FailureRateThreshold = Float64Ptr(current.FailureRateThreshold)
+	}
+	if update.MinimumObservations != nil {
+		prev.MinimumObservations = Uint32Ptr(current.MinimumObservations)
+	}
+	if update.WarningThresholdFraction != nil {
+		prev.WarningThresholdFraction = Float64Ptr(current.WarningThresholdFraction)
+	}
+	if update.RecoverFailureRate != nil {
+		prev.RecoverFailureRate = Float64Ptr(current.RecoverFailureRate)
+	}
+	if update.IsSuccessful != nil {
+		isSuccessful := current.IsSuccessful
+		prev.IsSuccessful = &isSuccessful
+	}
+	return prev
+}
+
+// invalidUpdateField returns the first SettingsUpdate field that fails
+// validation on its own, or "" if none does.
+func invalidUpdateField(update SettingsUpdate, adaptiveThreshold bool) string {
+	fields := []struct {
+		name string
+		only SettingsUpdate
+	}{
+		{"MaxRequests", SettingsUpdate{MaxRequests: update.MaxRequests}},
+		{"Interval", SettingsUpdate{Interval: update.Interval}},
+		{"Timeout", SettingsUpdate{Timeout: update.Timeout}},
+		{"FailureRateThreshold", SettingsUpdate{FailureRateThreshold: update.FailureRateThreshold}},
+		{"MinimumObservations", SettingsUpdate{MinimumObservations: update.MinimumObservations}},
+		{"WarningThresholdFraction", SettingsUpdate{WarningThresholdFraction: update.WarningThresholdFraction}},
+		{"RecoverFailureRate", SettingsUpdate{RecoverFailureRate: update.RecoverFailureRate}},
+	}
+	for _, f := range fields {
+		if validateSettingsUpdate(&f.only, adaptiveThreshold) != nil {
+			return f.name
+		}
+	}
+	return ""
+}
@@ -0,0 +1,125 @@
+package breaker
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+)
+
+// fleet registers n breakers named svc-00, svc-01, ... with a one-minute Timeout.
+func fleet(n int) (*Registry, []string) {
+	r := NewRegistry()
+	names := make([]string, n)
+	for i := range names {
+		names[i] = fmt.Sprintf("svc-%02d", i)
+		r.GetOrCreate(Settings{Name: names[i], Timeout: time.Minute})
+	}
+	return r, names
+}
+
+func TestRegistry_ApplyAll_InvalidEntryAppliesNothing(t *testing.T) {
+	r, names := fleet(30)
+
+	updates := make(map[string]SettingsUpdate, len(names))
+	for _, name := range names {
+		updates[name] = SettingsUpdate{Timeout: DurationPtr(30 * time.Second)}
+	}
+	updates[names[16]] = SettingsUpdate{
+		Timeout:     DurationPtr(30 * time.Second),
+		MaxRequests: Uint32Ptr(0),
+	}
+
+	err := r.ApplyAll(updates)
+
+	var applyErr *ApplyError
+	if !errors.As(err, &applyErr) {
+		t.Fatalf("Expected *ApplyError, got %v", err)
+	}
+	if applyErr.Breaker != names[16] || applyErr.Field != "MaxRequests" {
+		t.Errorf("Expected failure on %s.MaxRequests, got %s.%s", names[16], applyErr.Breaker, applyErr.Field)
+	}
+	for _, name := range names {
+		cb, _ := r.Lookup(name)
+		if got := cb.getTimeout(); got != time.Minute {
+			t.Errorf("%s: expected Timeout unchanged at 1m, got %v", name, got)
+		}
+	}
+}
+
+func TestRegistry_ApplyAll_ValidBatch(t *testing.T) {
+	r, names := fleet(30)
+
+	updates := make(map[string]SettingsUpdate, len(names))
+	for _, name := range names {
+		updates[name] = SettingsUpdate{Timeout: DurationPtr(30 * time.Second)}
+	}
+
+	changes, err := r.ApplyAllDetailed(updates)
+	if err != nil {
+		t.Fatalf("Expected batch applied, got %v", err)
+	}
+	if len(changes) != len(names) {
+		t.Fatalf("Expected %d ChangeSets, got %d", len(names), len(changes))
+	}
+	for _, name := range names {
+		cb, _ := r.Lookup(name)
+		if got := cb.getTimeout(); got != 30*time.Second {
+			t.Errorf("%s: expected Timeout 30s, got %v", name, got)
+		}
+		want := SettingChange{Field: "Timeout", Old: time.Minute, New: 30 * time.Second}
+		if cs := changes[name]; len(cs.Changes) != 1 || cs.Changes[0] != want {
+			t.Errorf("%s: expected ChangeSet %+v, got %+v", name, want, cs.Changes)
+		}
+	}
+}
+
+func TestRegistry_ApplyAll_UnregisteredName(t *testing.T) {
+	r, names := fleet(3)
+
+	err := r.ApplyAll(map[string]SettingsUpdate{
+		names[0]:  {Timeout: DurationPtr(time.Second)},
+		"missing": {Timeout: DurationPtr(time.Second)},
+	})
+
+	var applyErr *ApplyError
+	if !errors.As(err, &applyErr) || applyErr.Breaker != "missing" || applyErr.Field != "" {
+		t.Fatalf("Expected *ApplyError for missing, got %v", err)
+	}
+	if !errors.Is(err, ErrNotRegistered) {
+		t.Errorf("Expected ErrNotRegistered, got %v", err)
+	}
+	if cb, _ := r.Lookup(names[0]); cb.getTimeout() != time.Minute {
+		t.Errorf("Expected %s unchanged, got Timeout %v", names[0], cb.getTimeout())
+	}
+}
+
+func TestRegistry_ApplyAll_RollbackRestoresPreviousValues(t *testing.T) {
+	r, names := fleet(2)
+	a, _ := r.Lookup(names[0])
+	b, _ := r.Lookup(names[1])
+
+	update := SettingsUpdate{
+		Timeout:     DurationPtr(5 * time.Second),
+		MaxRequests: Uint32Ptr(4),
+	}
+	targets := []*CircuitBreaker{a, b}
+	previous := []SettingsUpdate{
+		previousValues(a.CurrentSettings(), update),
+		previousValues(b.CurrentSettings(), update),
+	}
+	for _, cb := range targets {
+		if err := cb.UpdateSettings(update); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	rollbackUpdates(targets, previous)
+
+	for _, cb := range targets {
+		if cb.getTimeout() != time.Minute || cb.getMaxRequests() != 1 {
+			t.Errorf("%s: expected Timeout 1m and MaxRequests 1 restored, got %v and %d",
+				cb.Name(), cb.getTimeout(), cb.getMaxRequests())
+		}
+	}
+}
@@ -0,0 +1,329 @@
+package breaker
+
+import (
+	"math"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Baseline analyzer defaults and limits.
+const (
+	defaultRecommendationBucket       = time.Hour
+	defaultRecommendationMinThreshold = 0.01
+	defaultRecommendationMaxThreshold = 0.5
+	maxRecommendationBuckets          = 10000
+
+	// recommendationStdDevs is k in the suggested threshold mean + k·stddev.
+	recommendationStdDevs = 3
+)
+
+// RecommendationConfidence indicates how much data backs a Recommendation.
+type RecommendationConfidence int32
+
+const (
+	// ConfidenceNone means fewer than two observation periods with traffic have
+	// completed; no threshold is suggested.
+	ConfidenceNone RecommendationConfidence = iota
+
+	// ConfidenceLow means under a quarter of the window is covered by
+	// observations, or they total fewer than 100 requests.
+	ConfidenceLow
+
+	// ConfidenceMedium means under three quarters of the window is covered, or
+	// observations total fewer than 1000 requests. AutoTune applies
+	// recommendations from this level up.
+	ConfidenceMedium
+
+	// ConfidenceHigh means at least three quarters of the window is covered by
+	// observations totalling at least 1000 requests.
+	ConfidenceHigh
+)
+
+// String returns the string representation of the confidence level.
+func (c RecommendationConfidence) String() string {
+	switch c {
+	case ConfidenceNone:
+		return "none"
+	case ConfidenceLow:
+		return "low"
+	case ConfidenceMedium:
+		return "medium"
+	case ConfidenceHigh:
+		return "high"
+	default:
+		return stateUnknownStr
+	}
+}
+
+// Recommendation is a FailureRateThreshold suggested from the failure rate the
+// breaker has observed over RecommendationWindow. Returned by Recommendation().
+type Recommendation struct {
+	// FailureRateThreshold is the suggested threshold: BaselineMean plus three
+	// standard deviations, clamped to [RecommendationMinThreshold,
+	// RecommendationMaxThreshold] and rounded to 4 decimal places. Zero with
+	// ConfidenceNone.
+	FailureRateThreshold float64
+
+	// Confidence indicates how much data backs the suggestion.
+	Confidence RecommendationConfidence
+
+	// BaselineMean and BaselineStdDev are the mean and sample standard
+	// deviation of the per-period failure rates.
+	BaselineMean   float64
+	BaselineStdDev float64
+
+	// Observations is the number of completed periods with traffic within the
+	// window, and Requests their total number of requests.
+	Observations int
+	Requests     uint64
+}
+
+// baselineObservation is the failure rate of one completed observation period.
+type baselineObservation struct {
+	start    int64 // UnixNano
+	rate     float64
+	requests uint64
+}
+
+// baselineAnalyzer collects per-period failure rates for Recommendation(), and
+// applies them under AutoTune.
+//
+// Requests only add to the current period's atomic counters. The request that
+// finds the period over rolls it into the ring under mu, about once per
+// period; counts racing with the rollover may land in the adjacent period.
+type baselineAnalyzer struct {
+	bucket       int64 // Period length (nanoseconds)
+	minThreshold float64
+	maxThreshold float64
+
+	autoTune         bool  // AutoTune, in adaptive mode only
+	autoTuneInterval int64 // Nanoseconds; defaults to bucket
+	onAutoTune       func(string, ChangeSet)
+
+	start    atomic.Int64 // Current period start (UnixNano), 0 before the first outcome
+	requests atomic.Uint64
+	failures atomic.Uint64
+
+	mu          sync.Mutex
+	ring        []baselineObservation // Completed periods, one slot per period in the window
+	next        int                   // Ring slot for the next completed period
+	lastTunedAt int64                 // UnixNano of the last AutoTune change
+}
+
+func newBaselineAnalyzer(settings Settings) *baselineAnalyzer {
+	bucket := settings.RecommendationBucket
+	if bucket == 0 {
+		bucket = defaultRecommendationBucket
+	}
+	minThreshold := settings.RecommendationMinThreshold
+	if minThreshold == 0 {
+		minThreshold = defaultRecommendationMinThreshold
+	}
+	maxThreshold := settings.RecommendationMaxThreshold
+	if maxThreshold == 0 {
+		maxThreshold = defaultRecommendationMaxThreshold
+	}
+
+	autoTuneInterval := settings.AutoTuneInterval
+	if autoTuneInterval == 0 {
+		autoTuneInterval = bucket
+	}
+
+	return &baselineAnalyzer{
+		bucket:           int64(bucket),
+		minThreshold:     minThreshold,
+		maxThreshold:     maxThreshold,
+		autoTune:         settings.AutoTune && settings.AdaptiveThreshold,
+		autoTuneInterval: int64(autoTuneInterval),
+		onAutoTune:       settings.OnAutoTune,
+		ring:             make([]baselineObservation, settings.RecommendationWindow/bucket),
+	}
+}
+
+// baselineSettings returns the analyzer's effective settings, or zeros without
+// an analyzer.
+func (cb *CircuitBreaker) baselineSettings() (window, bucket time.Duration, minThreshold, maxThreshold float64) {
+	b := cb.baseline
+	if b == nil {
+		return 0, 0, 0, 0
+	}
+	bucket = time.Duration(b.bucket)
+	return bucket * time.Duration(len(b.ring)), bucket, b.minThreshold, b.maxThreshold
+}
+
+// autoTuneSettings returns AutoTune, AutoTuneInterval and OnAutoTune, or zeros
+// without an analyzer.
+func (cb *CircuitBreaker) autoTuneSettings() (enabled bool, interval time.Duration, onAutoTune func(string, ChangeSet)) {
+	b := cb.baseline
+	if b == nil {
+		return false, 0, nil
+	}
+	return b.autoTune, time.Duration(b.autoTuneInterval), b.onAutoTune
+}
+
+// observeBaseline adds an outcome at now to the current observation period,
+// first completing the period if it is over.
+func (cb *CircuitBreaker) observeBaseline(success bool, now int64) {
+	b := cb.baseline
+	if start := b.start.Load(); start == 0 || now-start >= b.bucket {
+		cb.rollBaseline(now)
+	}
+
+	b.requests.Add(1)
+	if !success {
+		b.failures.Add(1)
+	}
+}
+
+// rollBaseline completes the current observation period at now and starts a
+// new one, then applies the recommendation if AutoTune is due.
+func (cb *CircuitBreaker) rollBaseline(now int64) {
+	b := cb.baseline
+	b.mu.Lock()
+
+	// Re-check under the lock: another request may have rolled already
+	start := b.start.Load()
+	if start != 0 && now-start < b.bucket {
+		b.mu.Unlock()
+		return
+	}
+
+	if start != 0 {
+		requests := b.requests.Swap(0)
+		failures := b.failures.Swap(0)
+		if requests > 0 {
+			b.ring[b.next] = baselineObservation{
+				start:    start,
+				rate:     float64(failures) / float64(requests),
+				requests: requests,
+			}
+			b.next = (b.next + 1) % len(b.ring)
+		}
+	}
+	b.start.Store(now)
+
+	var rec Recommendation
+	tune := b.autoTune && now-b.lastTunedAt >= b.autoTuneInterval
+	if tune {
+		rec = b.recommendLocked(now)
+		tune = rec.Confidence >= ConfidenceMedium
+		if tune {
+			b.lastTunedAt = now
+		}
+	}
+	b.mu.Unlock()
+
+	if tune {
+		cb.applyRecommendation(rec)
+	}
+}
+
+// applyRecommendation sets FailureRateThreshold to the recommended value via
+// UpdateSettingsDetailed and reports the change to OnAutoTune.
+func (cb *CircuitBreaker) applyRecommendation(rec Recommendation) {
+	changes, err := cb.UpdateSettingsDetailed(SettingsUpdate{
+		FailureRateThreshold: &rec.FailureRateThreshold,
+	})
+	if err != nil || changes.Empty() {
+		return
+	}
+	safeCallOnAutoTune(cb.name, cb.baseline.onAutoTune, changes)
+}
+
+// Recommendation returns a FailureRateThreshold suggested from the failure
+// rate observed over RecommendationWindow, with the baseline statistics behind
+// it.
+//
+// The suggestion is advisory: it is applied only with AutoTune. Check
+// Confidence before acting on it; with ConfidenceNone no threshold is
+// suggested. Returns a zero Recommendation when RecommendationWindow is not set.
+//
+// Thread-safe: Safe to call concurrently with Execute(). Briefly takes the
+// analyzer's lock, which requests only take when completing a period.
+//
+// Example:
+//
+//	rec := breaker.Recommendation()
+//	if rec.Confidence >= autobreaker.ConfidenceMedium {
+//	    log.Printf("baseline %.2f%% ± %.2f%%, suggest FailureRateThreshold %.4f",
+//	        rec.BaselineMean*100, rec.BaselineStdDev*100, rec.FailureRateThreshold)
+//	}
+func (cb *CircuitBreaker) Recommendation() Recommendation {
+	if cb.baseline == nil {
+		return Recommendation{}
+	}
+	return cb.recommendationAt(cb.now())
+}
+
+// recommendationAt is Recommendation with the current time (UnixNano) supplied.
+func (cb *CircuitBreaker) recommendationAt(now int64) Recommendation {
+	b := cb.baseline
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.recommendLocked(now)
+}
+
+// recommendLocked computes the recommendation from the observations within the
+// window ending at now. Caller must hold b.mu.
+func (b *baselineAnalyzer) recommendLocked(now int64) Recommendation {
+	windowStart := now - b.bucket*int64(len(b.ring))
+
+	var stats welford
+	var requests uint64
+	for _, obs := range b.ring {
+		if obs.requests == 0 || obs.start < windowStart {
+			continue
+		}
+		stats.add(obs.rate)
+		requests += obs.requests
+	}
+
+	rec := Recommendation{
+		Observations: stats.n,
+		Requests:     requests,
+		BaselineMean: stats.mean,
+	}
+	if stats.n < 2 {
+		return rec
+	}
+
+	rec.BaselineStdDev = stats.stdDev()
+	threshold := rec.BaselineMean + recommendationStdDevs*rec.BaselineStdDev
+	threshold = math.Max(b.minThreshold, math.Min(b.maxThreshold, threshold))
+	rec.FailureRateThreshold = math.Round(threshold*1e4) / 1e4
+
+	coverage := float64(stats.n) / float64(len(b.ring))
+	switch {
+	case coverage < 0.25 || requests < 100:
+		rec.Confidence = ConfidenceLow
+	case coverage < 0.75 || requests < 1000:
+		rec.Confidence = ConfidenceMedium
+	default:
+		rec.Confidence = ConfidenceHigh
+	}
+	return rec
+}
+
+// welford accumulates a running mean and variance with Welford's algorithm,
+// which avoids the cancellation error of the sum-of-squares formula.
+type welford struct {
+	n    int
+	mean float64
+	m2   float64 // Sum of squared deviations from the mean
+}
+
+func (w *welford) add(x float64) {
+	w.n++
+	delta := x - w.mean
+	w.mean += delta / float64(w.n)
+	w.m2 += delta * (x - w.mean)
+}
+
+// stdDev returns the sample standard deviation (0 with fewer than 2 values).
+func (w *welford) stdDev() float64 {
+	if w.n < 2 {
+		return 0
+	}
+	return math.Sqrt(w.m2 / float64(w.n-1))
+}
@@ -0,0 +1,227 @@
+package breaker
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+	"time"
+)
+
+// feedBaseline records one observation period per rate, each with the given
+// number of requests, starting at base. Returns the time just after the last
+// period completes.
+func feedBaseline(cb *CircuitBreaker, base int64, requests int, rates []float64) int64 {
+	bucket := cb.baseline.bucket
+	for i, rate := range rates {
+		start := base + int64(i)*bucket
+		failures := int(math.Round(rate * float64(requests)))
+		for r := 0; r < requests; r++ {
+			cb.observeBaseline(r >= failures, start+int64(r))
+		}
+	}
+	end := base + int64(len(rates))*bucket
+	cb.observeBaseline(true, end) // completes the last period
+	return end
+}
+
+func TestWelford_MatchesTwoPass(t *testing.T) {
+	// Large offset: the sum-of-squares formula loses all precision here
+	values := []float64{1e9 + 4, 1e9 + 7, 1e9 + 13, 1e9 + 16}
+
+	var w welford
+	for _, v := range values {
+		w.add(v)
+	}
+
+	if w.mean != 1e9+10 {
+		t.Errorf("Expected mean 1e9+10, got %v", w.mean)
+	}
+	if want := math.Sqrt(30); math.Abs(w.stdDev()-want) > 1e-9 {
+		t.Errorf("Expected stddev %v, got %v", want, w.stdDev())
+	}
+}
+
+func TestRecommendation_StationaryBaseline(t *testing.T) {
+	cb := New(Settings{
+		Name:                 "baseline",
+		RecommendationWindow: 24 * time.Hour,
+	})
+
+	// 24 hourly periods around 2% ± 0.5%
+	rng := rand.New(rand.NewSource(1))
+	rates := make([]float64, 24)
+	for i := range rates {
+		rates[i] = 0.02 + (rng.Float64()-0.5)*0.01
+	}
+	now := feedBaseline(cb, time.Now().UnixNano(), 1000, rates)
+
+	rec := cb.recommendationAt(now)
+	if rec.Observations != 24 || rec.Requests != 24000 || rec.Confidence != ConfidenceHigh {
+		t.Fatalf("Expected 24 observations of 24000 requests at high confidence, got %+v", rec)
+	}
+	if math.Abs(rec.BaselineMean-0.02) > 0.002 {
+		t.Errorf("Expected baseline mean near 2%%, got %v", rec.BaselineMean)
+	}
+	if rec.BaselineStdDev <= 0 || rec.BaselineStdDev > 0.005 {
+		t.Errorf("Expected a small baseline stddev, got %v", rec.BaselineStdDev)
+	}
+	want := math.Round((rec.BaselineMean+3*rec.BaselineStdDev)*1e4) / 1e4
+	if rec.FailureRateThreshold != want {
+		t.Errorf("Expected mean + 3·stddev = %v, got %v", want, rec.FailureRateThreshold)
+	}
+}
+
+func TestRecommendation_ShiftingBaseline(t *testing.T) {
+	cb := New(Settings{
+		Name:                 "baseline",
+		RecommendationWindow: 12 * time.Hour,
+	})
+	base := time.Now().UnixNano()
+
+	low := make([]float64, 12)
+	high := make([]float64, 12)
+	for i := range low {
+		low[i] = 0.01 + float64(i%2)*0.002
+		high[i] = 0.10 + float64(i%2)*0.002
+	}
+
+	now := feedBaseline(cb, base, 500, low)
+	before := cb.recommendationAt(now)
+
+	// A full window at the new level replaces the old baseline entirely
+	now = feedBaseline(cb, now, 500, high)
+	after := cb.recommendationAt(now)
+
+	if math.Abs(before.BaselineMean-0.011) > 1e-9 {
+		t.Errorf("Expected initial baseline 1.1%%, got %v", before.BaselineMean)
+	}
+	if math.Abs(after.BaselineMean-0.101) > 1e-3 {
+		t.Errorf("Expected shifted baseline 10.1%%, got %v", after.BaselineMean)
+	}
+	if after.FailureRateThreshold <= before.FailureRateThreshold {
+		t.Errorf("Expected the suggestion to follow the baseline up, got %v → %v",
+			before.FailureRateThreshold, after.FailureRateThreshold)
+	}
+}
+
+func TestRecommendation_AgesOutIdlePeriods(t *testing.T) {
+	cb := New(Settings{
+		Name:                 "baseline",
+		RecommendationWindow: 4 * time.Hour,
+	})
+
+	now := feedBaseline(cb, time.Now().UnixNano(), 200, []float64{0.01, 0.02, 0.03})
+	if rec := cb.recommendationAt(now); rec.Observations != 3 {
+		t.Fatalf("Expected 3 observations, got %d", rec.Observations)
+	}
+
+	// Idle periods leave no observation, but the old ones leave the window
+	if rec := cb.recommendationAt(now + int64(3*time.Hour)); rec.Observations != 1 {
+		t.Errorf("Expected only the latest observation left in the window, got %d", rec.Observations)
+	}
+}
+
+func TestRecommendation_ConfidenceAndClamps(t *testing.T) {
+	tests := []struct {
+		name     string
+		requests int
+		rates    []float64
+		wantConf RecommendationConfidence
+		wantRate float64
+	}{
+		{"single period", 1000, []float64{0.05}, ConfidenceNone, 0},
+		{"little coverage", 1000, []float64{0.05, 0.05}, ConfidenceLow, 0.05},
+		{"few requests", 9, make([]float64, 10), ConfidenceLow, 0.01},
+		{"half coverage", 200, []float64{0.2, 0.2, 0.2, 0.2, 0.2}, ConfidenceMedium, 0.2},
+		{"clamped high", 200, []float64{0.5, 0.9, 0.5, 0.9, 0.5, 0.9, 0.5, 0.9}, ConfidenceHigh, 0.5},
+		{"clamped low", 200, make([]float64, 8), ConfidenceHigh, 0.01},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cb := New(Settings{
+				Name:                 "baseline",
+				RecommendationWindow: 10 * time.Hour,
+			})
+			rec := cb.recommendationAt(feedBaseline(cb, time.Now().UnixNano(), tt.requests, tt.rates))
+			if rec.Confidence != tt.wantConf || rec.FailureRateThreshold != tt.wantRate {
+				t.Errorf("Expected %v confidence and threshold %v, got %+v", tt.wantConf, tt.wantRate, rec)
+			}
+		})
+	}
+}
+
+func TestAutoTune_AppliesThroughChangeSet(t *testing.T) {
+	var audits []ChangeSet
+	cb := New(Settings{
+		Name:                 "baseline",
+		AdaptiveThreshold:    true,
+		FailureRateThreshold: 0.05,
+		RecommendationWindow: 4 * time.Hour,
+		AutoTune:             true,
+		AutoTuneInterval:     2 * time.Hour,
+		OnAutoTune: func(name string, changes ChangeSet) {
+			audits = append(audits, changes)
+		},
+	})
+	base := time.Now().UnixNano()
+
+	// Two periods at 20%: medium confidence, tuned on the roll into period 3
+	now := feedBaseline(cb, base, 100, []float64{0.2, 0.2})
+	if got := cb.getFailureRateThreshold(); got != 0.2 {
+		t.Fatalf("Expected threshold tuned to 0.2, got %v", got)
+	}
+	if len(audits) != 1 || len(audits[0].Changes) != 1 {
+		t.Fatalf("Expected one audited change, got %+v", audits)
+	}
+	if c := audits[0].Changes[0]; c.Field != "FailureRateThreshold" || c.Old != 0.05 || c.New != 0.2 {
+		t.Errorf("Unexpected change: %+v", c)
+	}
+
+	// Baseline moves, but the next change waits for AutoTuneInterval
+	now = feedBaseline(cb, now, 100, []float64{0.3})
+	if got := cb.getFailureRateThreshold(); got != 0.2 {
+		t.Errorf("Expected no change within AutoTuneInterval, got %v", got)
+	}
+	feedBaseline(cb, now, 100, []float64{0.3})
+	if got := cb.getFailureRateThreshold(); got == 0.2 || len(audits) != 2 {
+		t.Errorf("Expected a second tuning after AutoTuneInterval, got %v (%d audits)", got, len(audits))
+	}
+}
+
+func TestAutoTune_AdvisoryByDefault(t *testing.T) {
+	cb := New(Settings{
+		Name:                 "baseline",
+		AdaptiveThreshold:    true,
+		FailureRateThreshold: 0.05,
+		RecommendationWindow: 4 * time.Hour,
+	})
+
+	feedBaseline(cb, time.Now().UnixNano(), 500, []float64{0.2, 0.2, 0.2, 0.2})
+	if got := cb.getFailureRateThreshold(); got != 0.05 {
+		t.Errorf("Expected threshold unchanged without AutoTune, got %v", got)
+	}
+}
+
+func TestRecommendation_RecordedByExecute(t *testing.T) {
+	cb := New(Settings{
+		Name:                 "baseline",
+		RecommendationWindow: 100 * time.Millisecond,
+		RecommendationBucket: 10 * time.Millisecond,
+	})
+
+	for period := 0; period < 3; period++ {
+		cb.Execute(successFunc)
+		cb.Execute(failFunc)
+		time.Sleep(15 * time.Millisecond)
+	}
+	cb.Execute(successFunc)
+
+	rec := cb.Recommendation()
+	if rec.Observations < 2 || math.Abs(rec.BaselineMean-0.5) > 1e-9 {
+		t.Errorf("Expected periods of 50%% failures observed, got %+v", rec)
+	}
+	if got := New(Settings{Name: "none"}).Recommendation(); got != (Recommendation{}) {
+		t.Errorf("Expected a zero Recommendation without the analyzer, got %+v", got)
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"testing"
+	"time"
 )
 
 // Benchmark helpers
@@ -193,6 +194,38 @@ func BenchmarkExecute_Closed(b *testing.B) {
 	}
 }
 
+// BenchmarkExecute_PprofLabels measures the closed-state hot path with
+// PprofLabels enabled; compare with BenchmarkExecute_Closed.
+func BenchmarkExecute_PprofLabels(b *testing.B) {
+	cb := New(Settings{Name: "bench", PprofLabels: true})
+	operation := func() (interface{}, error) {
+		return "result", nil
+	}
+
+	b.ResetTimer()
+	b.ReportAllocs()
+
+	for i := 0; i < b.N; i++ {
+		benchResult, benchError = cb.Execute(operation)
+	}
+}
+
+// BenchmarkExecute_ClosedInterval measures the closed-state hot path with
+// interval-based count clearing enabled.
+func BenchmarkExecute_ClosedInterval(b *testing.B) {
+	cb := New(Settings{Name: "bench", Interval: time.Hour})
+	operation := func() (interface{}, error) {
+		return "result", nil
+	}
+
+	b.ResetTimer()
+	b.ReportAllocs()
+
+	for i := 0; i < b.N; i++ {
+		benchResult, benchError = cb.Execute(operation)
+	}
+}
+
 // BenchmarkExecute_Open measures Execute() performance when circuit is open (fast-fail).
 func BenchmarkExecute_Open(b *testing.B) {
 	cb := New(Settings{
@@ -219,6 +252,35 @@ func BenchmarkExecute_Open(b *testing.B) {
 	}
 }
 
+// BenchmarkExecute_OpenParallel measures open-state rejection under
+// concurrent load (the cached open deadline shared across goroutines).
+func BenchmarkExecute_OpenParallel(b *testing.B) {
+	cb := New(Settings{
+		Name: "bench",
+		ReadyToTrip: func(counts Counts) bool {
+			return counts.ConsecutiveFailures >= 1
+		},
+	})
+
+	// Trip the circuit
+	_, _ = cb.Execute(func() (interface{}, error) {
+		return nil, errors.New("error")
+	})
+
+	operation := func() (interface{}, error) {
+		return "result", nil
+	}
+
+	b.ResetTimer()
+	b.ReportAllocs()
+
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			_, _ = cb.Execute(operation)
+		}
+	})
+}
+
 // BenchmarkExecute_HalfOpen measures Execute() performance in half-open state.
 func BenchmarkExecute_HalfOpen(b *testing.B) {
 	operation := func() (interface{}, error) {
@@ -319,6 +381,36 @@ func BenchmarkDiagnostics(b *testing.B) {
 	_ = diag
 }
 
+// BenchmarkDiagnostics_Cached measures Diagnostics() with the WillTripNext
+// prediction cached (DiagnosticsCacheTTL).
+func BenchmarkDiagnostics_Cached(b *testing.B) {
+	cb := New(Settings{Name: "bench", DiagnosticsCacheTTL: time.Minute})
+	var diag Diagnostics
+
+	b.ResetTimer()
+	b.ReportAllocs()
+
+	for i := 0; i < b.N; i++ {
+		diag = cb.Diagnostics()
+	}
+	_ = diag
+}
+
+// BenchmarkQuickStats measures QuickStats() lean snapshot performance, for
+// comparison with BenchmarkMetrics and BenchmarkDiagnostics.
+func BenchmarkQuickStats(b *testing.B) {
+	cb := New(Settings{Name: "bench"})
+	var stats QuickStats
+
+	b.ResetTimer()
+	b.ReportAllocs()
+
+	for i := 0; i < b.N; i++ {
+		stats = cb.QuickStats()
+	}
+	_ = stats
+}
+
 // BenchmarkUpdateSettings measures UpdateSettings() performance.
 func BenchmarkUpdateSettings(b *testing.B) {
 	cb := New(Settings{Name: "bench"})
@@ -430,3 +522,43 @@ func BenchmarkStateTransitions(b *testing.B) {
 // - UpdateSettings:       < 100 ns/op, 0 allocs/op
 // - Concurrent scaling:   Linear with cores
 // - Zero allocations:     All hot paths
+//
+// Closed-state fast path layout:
+//
+// A Closed call checks one cached flag, plainClosed (see plain_path.go), which
+// is false while any closed-path feature is configured: the warn band, early
+// warning and health latches, MinClosedDuration, MaxRequestsPerCycle, a custom
+// Engine, latency tracking, Interval, a custom IsSuccessful, maintenance, and
+// the others listed in hasClosedFeatures. With it set, a success costs the
+// request and success counters alone. Otherwise Execute dispatches on a single
+// state switch with the Closed case first, and each unconfigured feature costs
+// one comparison of a field set at construction. State() and Disabled() share
+// one load of the state word.
+//
+// Against 6573e3b ("go run ./internal/perf/cmd/benchcompare -ref 6573e3b
+// -count 5", medians, linux/amd64):
+//
+//	Benchmark                6573e3b       head
+//	Execute_Closed           63.9 ns    61.8 ns
+//	ExecuteContext_Closed    66.4 ns    67.3 ns
+//	Execute_Open            109.7 ns    18.2 ns    Open hold, see below
+//	Execute_HalfOpen         1196 ns    2445 ns    includes New; 3 allocs, was 2
+//	New                     236.7 ns   718.4 ns    896 B, was 144
+//	State                    0.60 ns    0.53 ns
+//	Counts                   0.97 ns    1.83 ns    8 fields, was 5
+//	Metrics                  25.4 ns    49.1 ns    296-byte struct, was 96
+//	UpdateSettings           17.2 ns    20.1 ns    8 fields and a ChangeSet, was 5
+//
+// An Open rejection does not read the clock: openHeld stays set until the
+// open deadline's timer clears it, and about one rejection in 64 checks the
+// deadline in case the timer runs late (see openHeldNow). The remaining cost
+// is the one shared write that counts the rejection as demand.
+//
+// New allocates one 896-byte breaker. State for features most breakers leave
+// off (bulkhead, result cache, stuck-open tracking, adaptive timeout, the
+// probe watchdog, MinClosedDuration, rare event counters) lives behind
+// pointers that stay nil until configured or first used. Execute_HalfOpen
+// builds a breaker per iteration, so it carries New's cost, plus the trip's
+// OpenReason; the reason's Detail is formatted only when read.
+//
+// Use "make bench-compare" (internal/perf) to check a change against a git ref.
@@ -0,0 +1,171 @@
+package breaker
+
+import "time"
+
+// Builder constructs a CircuitBreaker fluently, as an alternative to filling in
+// Settings for New.
+//
+// Each With method validates the fields it sets, as far as they can be checked
+// on their own, and the first invalid value is kept: later calls do not clear
+// it. Check it with Err, or let Build panic (like New) or BuildChecked return it
+// (like NewChecked). Combinations of fields are checked by Build.
+//
+// Build applies the same defaults as New: a builder that sets nothing but the
+// name yields the same breaker as New(Settings{Name: name}).
+//
+// Example:
+//
+//	breaker := autobreaker.NewBuilder("payments").
+//	    WithTimeout(10 * time.Second).
+//	    WithAdaptiveThreshold(0.05, 20).
+//	    WithOnStateChange(func(name string, from, to autobreaker.State) {
+//	        log.Printf("Circuit %s: %s -> %s", name, from, to)
+//	    }).
+//	    Build()
+//
+// Not thread-safe: a Builder must not be shared between goroutines. The
+// breakers it builds are independent of it and of each other.
+type Builder struct {
+	settings Settings
+	err      error
+}
+
+// NewBuilder returns a Builder for a breaker with the given name.
+func NewBuilder(name string) *Builder {
+	return &Builder{settings: Settings{Name: name}}
+}
+
+// WithTimeout sets Settings.Timeout.
+func (b *Builder) WithTimeout(timeout time.Duration) *Builder {
+	b.settings.Timeout = timeout
+	return b.check("Timeout")
+}
+
+// WithInterval sets Settings.Interval.
+func (b *Builder) WithInterval(interval time.Duration) *Builder {
+	b.settings.Interval = interval
+	return b.check("Interval")
+}
+
+// WithMaxRequests sets Settings.MaxRequests.
+func (b *Builder) WithMaxRequests(maxRequests uint32) *Builder {
+	b.settings.MaxRequests = maxRequests
+	return b.check("MaxRequests")
+}
+
+// WithAdaptiveThreshold enables AdaptiveThreshold with the given
+// FailureRateThreshold and MinimumObservations.
+func (b *Builder) WithAdaptiveThreshold(failureRate float64, minObservations uint32) *Builder {
+	b.settings.AdaptiveThreshold = true
+	b.settings.FailureRateThreshold = failureRate
+	b.settings.MinimumObservations = minObservations
+	return b.check("FailureRateThreshold", "MinimumObservations")
+}
+
+// WithReadyToTrip sets Settings.ReadyToTrip.
+func (b *Builder) WithReadyToTrip(readyToTrip func(counts Counts) bool) *Builder {
+	b.settings.ReadyToTrip = readyToTrip
+	return b
+}
+
+// WithReadyToTripEx sets Settings.ReadyToTripEx.
+func (b *Builder) WithReadyToTripEx(readyToTripEx func(counts Counts, info WindowInfo) bool) *Builder {
+	b.settings.ReadyToTripEx = readyToTripEx
+	return b
+}
+
+// WithIsSuccessful sets Settings.IsSuccessful.
+func (b *Builder) WithIsSuccessful(isSuccessful func(err error) bool) *Builder {
+	b.settings.IsSuccessful = isSuccessful
+	return b
+}
+
+// WithOnStateChange sets Settings.OnStateChange.
+func (b *Builder) WithOnStateChange(onStateChange func(name string, from State, to State)) *Builder {
+	b.settings.OnStateChange = onStateChange
+	return b
+}
+
+// WithMaxConcurrent sets Settings.MaxConcurrent.
+func (b *Builder) WithMaxConcurrent(maxConcurrent uint32) *Builder {
+	b.settings.MaxConcurrent = maxConcurrent
+	return b
+}
+
+// WithLabels sets Settings.Labels.
+func (b *Builder) WithLabels(labels map[string]string) *Builder {
+	b.settings.Labels = labels
+	return b
+}
+
+// WithSettings applies configure to the settings built so far, for fields
+// without a dedicated With method. Every field is validated afterwards.
+//
+// Example:
+//
+//	b.WithSettings(func(s *autobreaker.Settings) {
+//	    s.HalfOpenProbeTimeout = 5 * time.Second
+//	})
+func (b *Builder) WithSettings(configure func(s *Settings)) *Builder {
+	configure(&b.settings)
+	return b.check()
+}
+
+// Settings returns a copy of the settings built so far.
+func (b *Builder) Settings() Settings {
+	return b.settings
+}
+
+// Err returns the first invalid value set on the builder, or nil.
+func (b *Builder) Err() error {
+	return b.err
+}
+
+// Build creates the breaker. Panics if a With method was given an invalid
+// value or the settings are invalid as a whole, like New.
+func (b *Builder) Build() *CircuitBreaker {
+	if b.err != nil {
+		panic(b.err.Error())
+	}
+	return New(b.settings)
+}
+
+// BuildChecked creates the breaker like NewChecked, returning validation
+// errors instead of panicking, along with its cleanup function.
+func (b *Builder) BuildChecked() (*CircuitBreaker, func(), error) {
+	if b.err != nil {
+		return nil, nil, b.err
+	}
+	return NewChecked(b.settings)
+}
+
+// check records the first validation error involving fields (any field if
+// none are given), unless an earlier one was recorded.
+func (b *Builder) check(fields ...string) *Builder {
+	if b.err != nil {
+		return b
+	}
+	for _, issue := range ValidateSettings(b.settings) {
+		if issue.Severity == SeverityError && involves(issue, fields) {
+			b.err = issue
+			return b
+		}
+	}
+	return b
+}
+
+// involves reports whether issue concerns any of fields (true if fields is
+// empty).
+func involves(issue Issue, fields []string) bool {
+	if len(fields) == 0 {
+		return true
+	}
+	for _, f := range issue.Fields {
+		for _, want := range fields {
+			if f == want {
+				return true
+			}
+		}
+	}
+	return false
+}
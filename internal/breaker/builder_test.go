@@ -0,0 +1,116 @@
+package breaker
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+// comparableDiagnostics returns cb's diagnostics without the construction
+// timestamps, which differ between otherwise identical breakers.
+func comparableDiagnostics(cb *CircuitBreaker) Diagnostics {
+	d := cb.Diagnostics()
+	d.Metrics.StateChangedAt = time.Time{}
+	d.Metrics.CountsLastClearedAt = time.Time{}
+	return d
+}
+
+func TestBuilder_MatchesNew(t *testing.T) {
+	onStateChange := func(name string, from, to State) {}
+
+	tests := []struct {
+		name     string
+		builder  *Builder
+		settings Settings
+	}{
+		{"defaults", NewBuilder("b-defaults"), Settings{Name: "b-defaults"}},
+		{
+			"adaptive",
+			NewBuilder("b-adaptive").
+				WithTimeout(10*time.Second).
+				WithAdaptiveThreshold(0.05, 20).
+				WithOnStateChange(onStateChange),
+			Settings{
+				Name:                 "b-adaptive",
+				Timeout:              10 * time.Second,
+				AdaptiveThreshold:    true,
+				FailureRateThreshold: 0.05,
+				MinimumObservations:  20,
+				OnStateChange:        onStateChange,
+			},
+		},
+		{
+			"windowed",
+			NewBuilder("b-windowed").
+				WithInterval(time.Minute).
+				WithMaxRequests(3).
+				WithMaxConcurrent(8).
+				WithLabels(map[string]string{"team": "payments"}).
+				WithSettings(func(s *Settings) { s.HalfOpenMaxProbes = 5 }),
+			Settings{
+				Name:              "b-windowed",
+				Interval:          time.Minute,
+				MaxRequests:       3,
+				MaxConcurrent:     8,
+				Labels:            map[string]string{"team": "payments"},
+				HalfOpenMaxProbes: 5,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.builder.Err(); err != nil {
+				t.Fatalf("Expected a valid builder, got %v", err)
+			}
+			built := comparableDiagnostics(tt.builder.Build())
+			direct := comparableDiagnostics(New(tt.settings))
+			if !reflect.DeepEqual(built, direct) {
+				t.Errorf("Diagnostics differ:\nbuilder: %+v\nNew:     %+v", built, direct)
+			}
+		})
+	}
+}
+
+func TestBuilder_ValidatesIncrementally(t *testing.T) {
+	b := NewBuilder("b-invalid").WithTimeout(-time.Second)
+	if err := b.Err(); err == nil || !strings.Contains(err.Error(), "Timeout") {
+		t.Fatalf("Expected a Timeout error right after WithTimeout, got %v", err)
+	}
+
+	// The first error sticks, even once the value is fixed
+	b.WithTimeout(time.Second).WithAdaptiveThreshold(2, 20)
+	if err := b.Err(); err == nil || !strings.Contains(err.Error(), "Timeout") {
+		t.Errorf("Expected the first error to be kept, got %v", err)
+	}
+
+	if cb, cleanup, err := b.BuildChecked(); err == nil || cb != nil || cleanup != nil {
+		t.Errorf("Expected BuildChecked to fail, got %v, %v", cb, err)
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("Expected Build to panic like New")
+		}
+	}()
+	b.Build()
+}
+
+func TestBuilder_AdaptiveThresholdOutOfRange(t *testing.T) {
+	b := NewBuilder("b-rate").WithAdaptiveThreshold(1.5, 20)
+	if err := b.Err(); err == nil || !strings.Contains(err.Error(), "FailureRateThreshold") {
+		t.Errorf("Expected a FailureRateThreshold error, got %v", err)
+	}
+}
+
+func TestBuilder_BuildChecked(t *testing.T) {
+	cb, cleanup, err := NewBuilder("b-checked").WithTimeout(time.Second).BuildChecked()
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	defer cleanup()
+	if got := cb.CurrentSettings().Timeout; got != time.Second {
+		t.Errorf("Expected Timeout 1s, got %v", got)
+	}
+}
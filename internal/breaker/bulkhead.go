@@ -0,0 +1,103 @@
+package breaker
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+)
+
+// bulkhead is the MaxConcurrent limiter: its slots, how long a request waits
+// for one, and the wait statistics. done is closed by Close() to wake waiters.
+type bulkhead struct {
+	slots     chan struct{}
+	wait      time.Duration // MaxConcurrentWait
+	done      chan struct{}
+	waits     atomic.Uint64 // Requests that waited for a slot
+	waitNanos atomic.Int64  // Their total wait
+}
+
+func newBulkhead(size uint32, wait time.Duration) *bulkhead {
+	return &bulkhead{
+		slots: make(chan struct{}, size),
+		wait:  wait,
+		done:  make(chan struct{}),
+	}
+}
+
+// size returns MaxConcurrent, 0 for a nil (unlimited) bulkhead.
+func (b *bulkhead) size() uint32 {
+	if b == nil {
+		return 0
+	}
+	return uint32(cap(b.slots))
+}
+
+// maxWait returns MaxConcurrentWait, 0 for a nil bulkhead.
+func (b *bulkhead) maxWait() time.Duration {
+	if b == nil {
+		return 0
+	}
+	return b.wait
+}
+
+// acquireSlot reserves one of the MaxConcurrent bulkhead slots. If none is free,
+// it waits up to MaxConcurrentWait for one, returning ErrTooManyConcurrent on
+// timeout, ctx.Err() if ctx is done first, or ErrBreakerClosed if the breaker
+// is closed while waiting. Time spent waiting is recorded for
+// Metrics.AvgWaitTime. The caller must release an acquired slot with releaseSlot.
+func (cb *CircuitBreaker) acquireSlot(ctx context.Context) error {
+	// Fast path: a slot is free
+	b := cb.bulkhead
+	select {
+	case b.slots <- struct{}{}:
+		return nil
+	default:
+	}
+
+	if b.wait <= 0 {
+		return ErrTooManyConcurrent
+	}
+
+	start := time.Now()
+	timer := time.NewTimer(b.wait)
+	defer timer.Stop()
+
+	select {
+	case b.slots <- struct{}{}:
+		b.recordWait(time.Since(start))
+		return nil
+	case <-timer.C:
+		b.recordWait(time.Since(start))
+		return ErrTooManyConcurrent
+	case <-ctx.Done():
+		b.recordWait(time.Since(start))
+		return ctx.Err()
+	case <-b.done:
+		return ErrBreakerClosed
+	}
+}
+
+// releaseSlot returns a bulkhead slot acquired with acquireSlot.
+func (cb *CircuitBreaker) releaseSlot() {
+	<-cb.bulkhead.slots
+}
+
+// recordWait adds one bulkhead wait to the cumulative wait statistics.
+func (b *bulkhead) recordWait(d time.Duration) {
+	b.waitNanos.Add(int64(d))
+	b.waits.Add(1)
+}
+
+// avgWaitTime returns the mean time requests that had to wait spent waiting
+// for a bulkhead slot. Returns 0 if no request has waited, or there is no
+// bulkhead.
+func (cb *CircuitBreaker) avgWaitTime() time.Duration {
+	if cb.bulkhead == nil {
+		return 0
+	}
+	waits := cb.bulkhead.waits.Load()
+	if waits == 0 {
+		return 0
+	}
+	return time.Duration(uint64(cb.bulkhead.waitNanos.Load()) / waits)
+}
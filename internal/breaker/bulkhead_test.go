@@ -0,0 +1,169 @@
+package breaker
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// holdSlots occupies n bulkhead slots until the returned release func is called.
+func holdSlots(t *testing.T, cb *CircuitBreaker, n int) (release func()) {
+	t.Helper()
+	var started sync.WaitGroup
+	var done sync.WaitGroup
+	block := make(chan struct{})
+	for i := 0; i < n; i++ {
+		started.Add(1)
+		done.Add(1)
+		go func() {
+			defer done.Done()
+			cb.Execute(func() (interface{}, error) {
+				started.Done()
+				<-block
+				return nil, nil
+			})
+		}()
+	}
+	started.Wait()
+	return func() {
+		close(block)
+		done.Wait()
+	}
+}
+
+func TestBulkhead_RejectsImmediatelyWithoutWait(t *testing.T) {
+	cb := New(Settings{Name: "bulkhead", MaxConcurrent: 2})
+
+	release := holdSlots(t, cb, 2)
+	if _, err := cb.Execute(successFunc); !errors.Is(err, ErrTooManyConcurrent) {
+		t.Errorf("Expected ErrTooManyConcurrent, got %v", err)
+	}
+	release()
+
+	// Rejections are not requests and don't affect state
+	if counts := cb.Counts(); counts.Requests != 2 || counts.TotalFailures != 0 {
+		t.Errorf("Expected only the 2 admitted requests counted, got %+v", counts)
+	}
+	if m := cb.Metrics(); m.State != StateClosed || m.AvgWaitTime != 0 {
+		t.Errorf("Expected Closed with no waits, got state=%v wait=%v", m.State, m.AvgWaitTime)
+	}
+
+	if _, err := cb.Execute(successFunc); err != nil {
+		t.Errorf("Expected admission once slots are free, got %v", err)
+	}
+}
+
+func TestBulkhead_WaitAdmitsAndTimesOut(t *testing.T) {
+	cb := New(Settings{
+		Name:              "bulkhead",
+		MaxConcurrent:     1,
+		MaxConcurrentWait: 200 * time.Millisecond,
+	})
+
+	// Hold the only slot for ~50ms: a waiter gets it well within the wait budget
+	var started sync.WaitGroup
+	started.Add(1)
+	go cb.Execute(func() (interface{}, error) {
+		started.Done()
+		time.Sleep(50 * time.Millisecond)
+		return nil, nil
+	})
+	started.Wait()
+
+	start := time.Now()
+	if _, err := cb.Execute(successFunc); err != nil {
+		t.Fatalf("Expected waiter to be admitted, got %v", err)
+	}
+	if waited := time.Since(start); waited < 20*time.Millisecond {
+		t.Errorf("Expected the request to wait for the slot, waited %v", waited)
+	}
+
+	// Hold the slot longer than the wait budget: the waiter times out
+	release := holdSlots(t, cb, 1)
+	if _, err := cb.Execute(successFunc); !errors.Is(err, ErrTooManyConcurrent) {
+		t.Errorf("Expected ErrTooManyConcurrent after waiting, got %v", err)
+	}
+	release()
+
+	avg := cb.Metrics().AvgWaitTime
+	if avg < 20*time.Millisecond || avg > time.Second {
+		t.Errorf("Expected average wait between the admitted and timed-out waits, got %v", avg)
+	}
+}
+
+func TestBulkhead_SaturatedMixedOutcomes(t *testing.T) {
+	cb := New(Settings{
+		Name:              "bulkhead",
+		MaxConcurrent:     2,
+		MaxConcurrentWait: 30 * time.Millisecond,
+	})
+
+	var admitted, rejected atomic.Int32
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := cb.Execute(func() (interface{}, error) {
+				time.Sleep(20 * time.Millisecond)
+				return nil, nil
+			})
+			switch {
+			case err == nil:
+				admitted.Add(1)
+			case errors.Is(err, ErrTooManyConcurrent):
+				rejected.Add(1)
+			default:
+				t.Errorf("Unexpected error: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if admitted.Load() <= 2 {
+		t.Errorf("Expected some waiting requests to be admitted, got %d admitted", admitted.Load())
+	}
+	if rejected.Load() == 0 {
+		t.Error("Expected some waiting requests to time out")
+	}
+	if cb.Metrics().AvgWaitTime == 0 {
+		t.Error("Expected wait time reflected in metrics")
+	}
+}
+
+func TestBulkhead_ContextCanceledWhileWaiting(t *testing.T) {
+	cb := New(Settings{
+		Name:              "bulkhead",
+		MaxConcurrent:     1,
+		MaxConcurrentWait: time.Minute,
+	})
+	release := holdSlots(t, cb, 1)
+	defer release()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	_, err := cb.ExecuteContext(ctx, successFunc)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected context.DeadlineExceeded, got %v", err)
+	}
+	if counts := cb.Counts(); counts.Requests != 1 {
+		t.Errorf("Expected the canceled waiter not counted, got %+v", counts)
+	}
+}
+
+func TestBulkhead_SlotReleasedOnPanic(t *testing.T) {
+	cb := New(Settings{Name: "bulkhead", MaxConcurrent: 1})
+
+	func() {
+		defer func() { recover() }()
+		cb.Execute(func() (interface{}, error) { panic("boom") })
+	}()
+
+	if _, err := cb.Execute(successFunc); err != nil {
+		t.Errorf("Expected slot released after panic, got %v", err)
+	}
+}
@@ -0,0 +1,15 @@
+package breaker
+
+// defaultBurnRateThreshold is the BurnRateThreshold used when ErrorBudget is
+// set and the threshold is 0: trip as soon as the window spends the budget
+// faster than the SLO period allows.
+const defaultBurnRateThreshold = 1.0
+
+// burnRate returns the failure rate of counts as a multiple of ErrorBudget, or
+// 0 without an error budget.
+func (cb *CircuitBreaker) burnRate(counts Counts) float64 {
+	if cb.errorBudget <= 0 {
+		return 0
+	}
+	return cb.failureRate(counts) / cb.errorBudget
+}
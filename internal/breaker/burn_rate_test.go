@@ -0,0 +1,84 @@
+package breaker
+
+import (
+	"math"
+	"strings"
+	"testing"
+)
+
+// sloBreaker returns a breaker tripping on a 14× burn of a 0.1% error budget,
+// i.e. above a 1.4% failure rate over at least 1000 requests.
+func sloBreaker() *CircuitBreaker {
+	return New(Settings{
+		Name:                "checkout",
+		AdaptiveThreshold:   true,
+		ErrorBudget:         0.001,
+		BurnRateThreshold:   14,
+		MinimumObservations: 1000,
+	})
+}
+
+func TestBurnRate_TripsAboveThreshold(t *testing.T) {
+	cb := sloBreaker()
+
+	// 14 failures in 1000 requests: 1.4%, exactly at a 14× burn
+	for i := 0; i < 986; i++ {
+		cb.Execute(successFunc)
+	}
+	for i := 0; i < 14; i++ {
+		cb.Execute(failFunc)
+	}
+	if cb.State() != StateClosed {
+		t.Fatalf("Expected Closed at exactly the burn rate threshold, got %v", cb.State())
+	}
+	diag := cb.Diagnostics()
+	if math.Abs(diag.BurnRate-14) > 1e-9 {
+		t.Errorf("Expected a burn rate of 14, got %v", diag.BurnRate)
+	}
+	if math.Abs(diag.EffectiveFailureRateThreshold-0.014) > 1e-9 {
+		t.Errorf("Expected an effective threshold of 1.4%%, got %v", diag.EffectiveFailureRateThreshold)
+	}
+
+	// One more failure: 15/1001, about 1.5%
+	cb.Execute(failFunc)
+	if cb.State() != StateOpen {
+		t.Fatalf("Expected Open above the burn rate threshold, got %v", cb.State())
+	}
+	reason := cb.Diagnostics().OpenReason
+	if reason.Kind != OpenReasonFailureRate || !strings.Contains(reason.Detail, "14.0× error budget 0.10%") {
+		t.Errorf("Expected a burn rate trip reason, got %+v", reason)
+	}
+}
+
+func TestBurnRate_ReplacesFailureRateThreshold(t *testing.T) {
+	cb := sloBreaker()
+
+	// 2% failure rate: well under the 5% FailureRateThreshold default
+	for i := 0; i < 1000 && cb.State() == StateClosed; i++ {
+		if i%50 == 49 {
+			cb.Execute(failFunc)
+		} else {
+			cb.Execute(successFunc)
+		}
+	}
+	if cb.State() != StateOpen {
+		t.Errorf("Expected a 2%% failure rate to burn the budget too fast, got %v", cb.State())
+	}
+}
+
+func TestBurnRate_Defaults(t *testing.T) {
+	cb := New(Settings{Name: "checkout", AdaptiveThreshold: true, ErrorBudget: 0.01})
+	if got := cb.CurrentSettings().BurnRateThreshold; got != defaultBurnRateThreshold {
+		t.Errorf("Expected the default BurnRateThreshold %v, got %v", defaultBurnRateThreshold, got)
+	}
+	if got := cb.Diagnostics().EffectiveFailureRateThreshold; got != 0.01 {
+		t.Errorf("Expected the budget itself as the threshold, got %v", got)
+	}
+
+	plain := New(Settings{Name: "plain", AdaptiveThreshold: true})
+	plain.Execute(failFunc)
+	diag := plain.Diagnostics()
+	if diag.BurnRate != 0 || diag.ErrorBudget != 0 || diag.BurnRateThreshold != 0 {
+		t.Errorf("Expected no burn rate without an error budget, got %+v", diag)
+	}
+}
@@ -0,0 +1,162 @@
+package breaker
+
+import (
+	"fmt"
+	"sync/atomic"
+	"time"
+)
+
+// resultCache is the CacheTTL state: the settings, the last-known-good result
+// and how many rejections it answered.
+type resultCache struct {
+	ttl         time.Duration
+	lastSuccess bool // CacheLastSuccess
+	staleOnBusy bool // ServeStaleOnTooManyRequests
+	last        atomic.Pointer[cachedResult]
+	staleServes atomic.Uint64
+}
+
+// cacheSettings returns CacheTTL, CacheLastSuccess and
+// ServeStaleOnTooManyRequests, or zeros without a cache.
+func (cb *CircuitBreaker) cacheSettings() (ttl time.Duration, lastSuccess, staleOnBusy bool) {
+	c := cb.cache
+	if c == nil {
+		return 0, false, false
+	}
+	return c.ttl, c.lastSuccess, c.staleOnBusy
+}
+
+// staleServes returns the number of rejections answered from the cache.
+func (cb *CircuitBreaker) staleServes() uint64 {
+	if cb.cache == nil {
+		return 0
+	}
+	return cb.cache.staleServes.Load()
+}
+
+// lastGoodResult returns the last-known-good result, nil when none is stored.
+func (cb *CircuitBreaker) lastGoodResult() *cachedResult {
+	if cb.cache == nil {
+		return nil
+	}
+	return cb.cache.last.Load()
+}
+
+// cachedResult is the last-known-good result kept by ExecuteWithCache.
+type cachedResult struct {
+	value    interface{}
+	storedAt int64 // UnixNano
+}
+
+// ExecuteWithCache runs req like Execute, and serves the last-known-good result
+// while the circuit is open (see CacheTTL).
+//
+// A call that returns a nil error while the circuit is Closed replaces the
+// cached result. When the circuit rejects the call with ErrOpenState and a
+// cached result younger than CacheTTL exists, ExecuteWithCache returns that
+// result with a nil error and stale=true. Otherwise it returns whatever Execute
+// returned, with stale=false: the fresh result, a rejection, or req's error.
+//
+// Only open-state rejections by this breaker are served from the cache.
+// Failures of req itself, including a nested breaker's ErrOpenState (and
+// ErrTooManyRequests from a busy HalfOpen circuit, unless
+// ServeStaleOnTooManyRequests is set), are returned as-is, so the caller still
+// sees real errors from a circuit that hasn't tripped.
+//
+// Thread-safe: Safe to call concurrently. Concurrent successes race to update
+// the cache; the last to store wins.
+//
+// Example:
+//
+//	result, err, stale := breaker.ExecuteWithCache(func() (interface{}, error) {
+//	    return configService.Fetch()
+//	})
+//	if err != nil {
+//	    return nil, err
+//	}
+//	if stale {
+//	    log.Printf("config service unavailable, using cached config")
+//	}
+func (cb *CircuitBreaker) ExecuteWithCache(req func() (interface{}, error)) (interface{}, error, bool) {
+	result, err, stale := cb.executeWithCache(req)
+	if stale {
+		return result, nil, true
+	}
+	return result, err, false
+}
+
+// ExecuteCached is ExecuteWithCache with the usual (result, error) signature,
+// for call sites and wrappers built around Execute.
+//
+// When a rejection is served from the cache, ExecuteCached returns the cached
+// result together with a non-nil error wrapping both ErrServedStale and the
+// rejection (ErrOpenState, or ErrTooManyRequests with
+// ServeStaleOnTooManyRequests). Check for ErrServedStale before treating the
+// error as a failure:
+//
+//	result, err := breaker.ExecuteCached(fetchConfig)
+//	if errors.Is(err, autobreaker.ErrServedStale) {
+//	    log.Printf("config service unavailable, using cached config: %v", err)
+//	} else if err != nil {
+//	    return nil, err
+//	}
+//	return result.(*Config), nil
+func (cb *CircuitBreaker) ExecuteCached(req func() (interface{}, error)) (interface{}, error) {
+	result, err, stale := cb.executeWithCache(req)
+	if stale {
+		return result, fmt.Errorf("%w: %w", ErrServedStale, err)
+	}
+	return result, err
+}
+
+// executeWithCache runs req like Execute, populating the cache on success.
+// When this breaker's own admission rejects the call, it returns the cached
+// result, if fresh, with the rejection error and stale=true. Errors returned by
+// req are never served from the cache, even a nested breaker's ErrOpenState.
+func (cb *CircuitBreaker) executeWithCache(req func() (interface{}, error)) (interface{}, error, bool) {
+	c := cb.cache
+	if c == nil {
+		result, err := cb.Execute(req)
+		return result, err, false
+	}
+
+	var a admission
+	a.opts = anyProbe
+	if err := cb.admit(&a); err != nil {
+		if c.servesStale(a.rejection) {
+			if cached, ok := c.resultAt(cb.now()); ok {
+				c.staleServes.Add(1)
+				return cached, err, true
+			}
+		}
+		return nil, err, false
+	}
+	result, err := cb.runAdmitted(&a, req, nil)
+
+	// Only results of calls admitted while Closed are cached; with
+	// CacheLastSuccess, recordCall has already stored it
+	if err == nil && a.state == StateClosed && !c.lastSuccess {
+		cb.storeResult(result)
+	}
+	return result, err, false
+}
+
+// servesStale reports whether a call this breaker rejected may be answered
+// from the cache.
+func (c *resultCache) servesStale(kind rejection) bool {
+	return kind == rejectedOpen || (c.staleOnBusy && kind == rejectedBusy)
+}
+
+// storeResult replaces the cached result.
+func (cb *CircuitBreaker) storeResult(value interface{}) {
+	cb.cache.last.Store(&cachedResult{value: value, storedAt: cb.now()})
+}
+
+// resultAt returns the cached result if it is no older than CacheTTL at now.
+func (c *resultCache) resultAt(now int64) (interface{}, bool) {
+	cached := c.last.Load()
+	if cached == nil || time.Duration(now-cached.storedAt) > c.ttl {
+		return nil, false
+	}
+	return cached.value, true
+}
@@ -0,0 +1,260 @@
+package breaker
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestExecuteWithCache_ServesStaleWhileOpen(t *testing.T) {
+	cb := New(tripOnFirstFailure(Settings{
+		Name:     "cache",
+		Timeout:  time.Minute,
+		CacheTTL: time.Minute,
+	}))
+
+	result, err, stale := cb.ExecuteWithCache(func() (interface{}, error) { return "v1", nil })
+	if result != "v1" || err != nil || stale {
+		t.Fatalf("Expected fresh result, got %v, %v, stale=%v", result, err, stale)
+	}
+
+	// A failure is returned as-is (and trips the circuit)
+	if _, err, stale := cb.ExecuteWithCache(failFunc); err == nil || stale {
+		t.Fatalf("Expected the real failure, got %v, stale=%v", err, stale)
+	}
+	if cb.State() != StateOpen {
+		t.Fatalf("Expected Open, got %v", cb.State())
+	}
+
+	result, err, stale = cb.ExecuteWithCache(func() (interface{}, error) {
+		t.Error("Expected no execution while open")
+		return nil, nil
+	})
+	if result != "v1" || err != nil || !stale {
+		t.Errorf("Expected cached v1 with stale=true, got %v, %v, stale=%v", result, err, stale)
+	}
+}
+
+func TestExecuteWithCache_NestedOpenStateNotServedStale(t *testing.T) {
+	cb := New(Settings{Name: "cache-nested", CacheTTL: time.Minute})
+	inner := New(tripOnFirstFailure(Settings{Name: "inner", Timeout: time.Minute}))
+	inner.Execute(failFunc)
+
+	cb.ExecuteWithCache(func() (interface{}, error) { return "v1", nil })
+
+	// The outer circuit is Closed: the inner breaker's rejection is req's error
+	result, err, stale := cb.ExecuteWithCache(func() (interface{}, error) {
+		return inner.Execute(successFunc)
+	})
+	if !errors.Is(err, ErrOpenState) || result != nil || stale {
+		t.Errorf("Expected the nested ErrOpenState as-is, got %v, %v, stale=%v", result, err, stale)
+	}
+	if got := cb.Metrics().StaleServes; got != 0 {
+		t.Errorf("Expected no stale serves, got %d", got)
+	}
+}
+
+func TestExecuteWithCache_ExpiredCache(t *testing.T) {
+	cb := New(tripOnFirstFailure(Settings{
+		Name:     "cache",
+		Timeout:  time.Minute,
+		CacheTTL: 10 * time.Millisecond,
+	}))
+
+	cb.ExecuteWithCache(successFunc)
+	cb.ExecuteWithCache(failFunc)
+	time.Sleep(20 * time.Millisecond)
+
+	result, err, stale := cb.ExecuteWithCache(successFunc)
+	if !errors.Is(err, ErrOpenState) || result != nil || stale {
+		t.Errorf("Expected ErrOpenState once the cache expired, got %v, %v, stale=%v", result, err, stale)
+	}
+}
+
+func TestExecuteWithCache_EmptyCache(t *testing.T) {
+	cb := New(tripOnFirstFailure(Settings{
+		Name:     "cache",
+		Timeout:  time.Minute,
+		CacheTTL: time.Minute,
+	}))
+
+	cb.ExecuteWithCache(failFunc)
+	if _, err, stale := cb.ExecuteWithCache(successFunc); !errors.Is(err, ErrOpenState) || stale {
+		t.Errorf("Expected ErrOpenState with nothing cached, got %v, stale=%v", err, stale)
+	}
+}
+
+func TestExecuteWithCache_ExecuteDoesNotPopulate(t *testing.T) {
+	cb := New(tripOnFirstFailure(Settings{
+		Name:     "cache",
+		Timeout:  time.Minute,
+		CacheTTL: time.Minute,
+	}))
+
+	cb.Execute(successFunc)
+	cb.Execute(failFunc)
+	if _, err, _ := cb.ExecuteWithCache(successFunc); !errors.Is(err, ErrOpenState) {
+		t.Errorf("Expected Execute results not cached, got %v", err)
+	}
+}
+
+func TestExecuteWithCache_Disabled(t *testing.T) {
+	cb := New(tripOnFirstFailure(Settings{Name: "cache", Timeout: time.Minute}))
+
+	cb.ExecuteWithCache(successFunc)
+	cb.ExecuteWithCache(failFunc)
+	if _, err, stale := cb.ExecuteWithCache(successFunc); !errors.Is(err, ErrOpenState) || stale {
+		t.Errorf("Expected no caching without CacheTTL, got %v, stale=%v", err, stale)
+	}
+}
+
+func TestExecuteCached_ServesStaleWithSentinel(t *testing.T) {
+	cb := New(tripOnFirstFailure(Settings{
+		Name:     "cached",
+		Timeout:  time.Minute,
+		CacheTTL: time.Minute,
+	}))
+
+	if result, err := cb.ExecuteCached(func() (interface{}, error) { return "v1", nil }); result != "v1" || err != nil {
+		t.Fatalf("Expected fresh result, got %v, %v", result, err)
+	}
+	cb.ExecuteCached(failFunc)
+
+	result, err := cb.ExecuteCached(successFunc)
+	if result != "v1" {
+		t.Errorf("Expected cached v1, got %v", result)
+	}
+	if !errors.Is(err, ErrServedStale) || !errors.Is(err, ErrOpenState) {
+		t.Errorf("Expected error wrapping ErrServedStale and ErrOpenState, got %v", err)
+	}
+}
+
+func TestExecuteCached_ExpiredCache(t *testing.T) {
+	cb := New(tripOnFirstFailure(Settings{
+		Name:     "cached",
+		Timeout:  time.Minute,
+		CacheTTL: 10 * time.Millisecond,
+	}))
+
+	cb.ExecuteCached(successFunc)
+	cb.ExecuteCached(failFunc)
+	time.Sleep(20 * time.Millisecond)
+
+	result, err := cb.ExecuteCached(successFunc)
+	if result != nil || errors.Is(err, ErrServedStale) || !errors.Is(err, ErrOpenState) {
+		t.Errorf("Expected a bare ErrOpenState once the cache expired, got %v, %v", result, err)
+	}
+}
+
+func TestExecuteCached_NothingCachedYet(t *testing.T) {
+	cb := New(tripOnFirstFailure(Settings{
+		Name:     "cached",
+		Timeout:  time.Minute,
+		CacheTTL: time.Minute,
+	}))
+
+	cb.ExecuteCached(failFunc)
+
+	result, err := cb.ExecuteCached(successFunc)
+	if result != nil || errors.Is(err, ErrServedStale) || !errors.Is(err, ErrOpenState) {
+		t.Errorf("Expected a bare ErrOpenState with nothing cached, got %v, %v", result, err)
+	}
+	if got := cb.Metrics().StaleServes; got != 0 {
+		t.Errorf("Expected no stale serves, got %d", got)
+	}
+}
+
+func TestExecuteCached_StaleServesDoNotAffectCounts(t *testing.T) {
+	cb := New(tripOnFirstFailure(Settings{
+		Name:     "cached",
+		Timeout:  time.Minute,
+		CacheTTL: time.Minute,
+	}))
+
+	cb.ExecuteCached(successFunc)
+	cb.ExecuteCached(failFunc)
+	before := cb.Counts()
+
+	for i := 0; i < 5; i++ {
+		if _, err := cb.ExecuteCached(successFunc); !errors.Is(err, ErrServedStale) {
+			t.Fatalf("Expected a stale serve, got %v", err)
+		}
+	}
+
+	if got := cb.Counts(); got != before {
+		t.Errorf("Expected counts unchanged by stale serves: %+v, want %+v", got, before)
+	}
+	if got := cb.Metrics().StaleServes; got != 5 {
+		t.Errorf("Expected StaleServes 5, got %d", got)
+	}
+}
+
+func TestCacheLastSuccess_ExecutePopulates(t *testing.T) {
+	for _, tt := range []struct {
+		name    string
+		execute func(cb *CircuitBreaker, req func() (interface{}, error))
+	}{
+		{"Execute", func(cb *CircuitBreaker, req func() (interface{}, error)) { cb.Execute(req) }},
+		{"ExecuteContext", func(cb *CircuitBreaker, req func() (interface{}, error)) {
+			cb.ExecuteContext(context.Background(), req)
+		}},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			cb := New(tripOnFirstFailure(Settings{
+				Name:             "cache-last-success",
+				Timeout:          time.Minute,
+				CacheTTL:         time.Minute,
+				CacheLastSuccess: true,
+			}))
+
+			tt.execute(cb, func() (interface{}, error) { return "plain", nil })
+			tt.execute(cb, failFunc)
+
+			result, err := cb.ExecuteCached(successFunc)
+			if result != "plain" || !errors.Is(err, ErrServedStale) {
+				t.Errorf("Expected the %s result served stale, got %v, %v", tt.name, result, err)
+			}
+		})
+	}
+}
+
+func TestServeStaleOnTooManyRequests(t *testing.T) {
+	for _, serve := range []bool{false, true} {
+		cb := New(tripOnFirstFailure(Settings{
+			Name:                        "cache-busy",
+			Timeout:                     10 * time.Millisecond,
+			CacheTTL:                    time.Minute,
+			ServeStaleOnTooManyRequests: serve,
+		}))
+
+		cb.ExecuteCached(func() (interface{}, error) { return "v1", nil })
+		cb.ExecuteCached(failFunc)
+		time.Sleep(20 * time.Millisecond)
+
+		// Hold the only probe slot
+		release := make(chan struct{})
+		started := make(chan struct{})
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			cb.Execute(func() (interface{}, error) {
+				close(started)
+				<-release
+				return "probe", nil
+			})
+		}()
+		<-started
+
+		result, err := cb.ExecuteCached(successFunc)
+		close(release)
+		<-done
+
+		if !errors.Is(err, ErrTooManyRequests) {
+			t.Fatalf("serve=%v: expected ErrTooManyRequests, got %v", serve, err)
+		}
+		if stale := errors.Is(err, ErrServedStale); stale != serve || (serve && result != "v1") {
+			t.Errorf("serve=%v: got %v, %v", serve, result, err)
+		}
+	}
+}
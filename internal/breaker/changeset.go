@@ -0,0 +1,48 @@
+package breaker
+
+// SettingChange describes one setting changed by UpdateSettingsDetailed.
+//
+// Field is the Settings field name. Old and New hold the values before and
+// after the update, with the field's Go type (time.Duration marshals to JSON
+// as integer nanoseconds). A replaced function (IsSuccessful) is reported with
+// nil Old and New.
+type SettingChange struct {
+	Field string      `json:"field"`
+	Old   interface{} `json:"old"`
+	New   interface{} `json:"new"`
+}
+
+// ChangeSet describes the changes applied by UpdateSettingsDetailed.
+//
+// Changes lists only fields whose value actually changed, in SettingsUpdate
+// field order; fields that were nil or set to their current value are omitted.
+// CountsReset and TimerReset report whether the update triggered a smart reset
+// (see UpdateSettings). ChangeSet is JSON-marshalable for audit logs; recording
+// who made the change and when is left to the caller.
+type ChangeSet struct {
+	Changes     []SettingChange `json:"changes"`
+	CountsReset bool            `json:"counts_reset"`
+	TimerReset  bool            `json:"timer_reset"`
+}
+
+// Empty reports whether the update changed nothing.
+func (c ChangeSet) Empty() bool {
+	return len(c.Changes) == 0
+}
+
+// recordReplaced appends a change for a replaced function-valued field, whose
+// values cannot be compared or marshaled.
+func (c *ChangeSet) recordReplaced(field string) {
+	c.Changes = append(c.Changes, SettingChange{Field: field})
+}
+
+// recordChange appends a change to c if old and new differ, and reports
+// whether they did. The values are compared before boxing, so an unchanged
+// field costs no conversion.
+func recordChange[T comparable](c *ChangeSet, field string, old, new T) bool {
+	if old == new {
+		return false
+	}
+	c.Changes = append(c.Changes, SettingChange{Field: field, Old: old, New: new})
+	return true
+}
@@ -0,0 +1,129 @@
+package breaker
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func TestUpdateSettingsDetailed_NoOp(t *testing.T) {
+	cb := New(Settings{Name: "test", MaxRequests: 3, Timeout: 10 * time.Second})
+
+	// All fields nil
+	changes, err := cb.UpdateSettingsDetailed(SettingsUpdate{})
+	if err != nil {
+		t.Fatalf("UpdateSettingsDetailed failed: %v", err)
+	}
+	if !changes.Empty() || changes.CountsReset || changes.TimerReset {
+		t.Errorf("Expected empty ChangeSet for nil update, got %+v", changes)
+	}
+
+	// All fields equal to current values
+	changes, err = cb.UpdateSettingsDetailed(SettingsUpdate{
+		MaxRequests: Uint32Ptr(3),
+		Timeout:     DurationPtr(10 * time.Second),
+	})
+	if err != nil {
+		t.Fatalf("UpdateSettingsDetailed failed: %v", err)
+	}
+	if !changes.Empty() {
+		t.Errorf("Expected empty ChangeSet for unchanged values, got %+v", changes)
+	}
+}
+
+func TestUpdateSettingsDetailed_PartialUpdate(t *testing.T) {
+	cb := New(Settings{
+		Name:                 "test",
+		MaxRequests:          1,
+		Interval:             10 * time.Second,
+		AdaptiveThreshold:    true,
+		FailureRateThreshold: 0.05,
+	})
+
+	changes, err := cb.UpdateSettingsDetailed(SettingsUpdate{
+		MaxRequests:          Uint32Ptr(1), // unchanged
+		Interval:             DurationPtr(30 * time.Second),
+		FailureRateThreshold: Float64Ptr(0.10),
+	})
+	if err != nil {
+		t.Fatalf("UpdateSettingsDetailed failed: %v", err)
+	}
+
+	want := []SettingChange{
+		{Field: "Interval", Old: 10 * time.Second, New: 30 * time.Second},
+		{Field: "FailureRateThreshold", Old: 0.05, New: 0.10},
+	}
+	if len(changes.Changes) != len(want) {
+		t.Fatalf("Expected %d changes, got %+v", len(want), changes.Changes)
+	}
+	for i, c := range changes.Changes {
+		if c != want[i] {
+			t.Errorf("Change %d: expected %+v, got %+v", i, want[i], c)
+		}
+	}
+
+	// Interval change in Closed state resets counts; Timeout untouched
+	if !changes.CountsReset || changes.TimerReset {
+		t.Errorf("Expected count reset only, got %+v", changes)
+	}
+}
+
+func TestUpdateSettingsDetailed_TimerReset(t *testing.T) {
+	cb := New(Settings{
+		Name:    "test",
+		Timeout: 10 * time.Second,
+		ReadyToTrip: func(counts Counts) bool {
+			return counts.ConsecutiveFailures >= 1
+		},
+	})
+	cb.Execute(func() (interface{}, error) { return nil, errNotFound })
+	if cb.State() != StateOpen {
+		t.Fatalf("Expected Open, got %v", cb.State())
+	}
+
+	changes, err := cb.UpdateSettingsDetailed(SettingsUpdate{
+		Timeout: DurationPtr(time.Minute),
+	})
+	if err != nil {
+		t.Fatalf("UpdateSettingsDetailed failed: %v", err)
+	}
+	if !changes.TimerReset || changes.CountsReset {
+		t.Errorf("Expected timer reset only, got %+v", changes)
+	}
+}
+
+func TestUpdateSettingsDetailed_ValidationFailure(t *testing.T) {
+	cb := New(Settings{Name: "test", MaxRequests: 1, Timeout: 10 * time.Second})
+
+	changes, err := cb.UpdateSettingsDetailed(SettingsUpdate{
+		MaxRequests: Uint32Ptr(5),
+		Timeout:     DurationPtr(-time.Second),
+	})
+	if err == nil {
+		t.Fatal("Expected validation error")
+	}
+	if !changes.Empty() || changes.CountsReset || changes.TimerReset {
+		t.Errorf("Expected empty ChangeSet on validation failure, got %+v", changes)
+	}
+	if cb.getMaxRequests() != 1 {
+		t.Errorf("Expected MaxRequests unchanged, got %d", cb.getMaxRequests())
+	}
+}
+
+func TestChangeSet_JSON(t *testing.T) {
+	cb := New(Settings{Name: "test", MaxRequests: 1})
+
+	changes, err := cb.UpdateSettingsDetailed(SettingsUpdate{MaxRequests: Uint32Ptr(4)})
+	if err != nil {
+		t.Fatalf("UpdateSettingsDetailed failed: %v", err)
+	}
+
+	data, err := json.Marshal(changes)
+	if err != nil {
+		t.Fatalf("Marshal failed: %v", err)
+	}
+	want := `{"changes":[{"field":"MaxRequests","old":1,"new":4}],"counts_reset":false,"timer_reset":false}`
+	if string(data) != want {
+		t.Errorf("Expected %s, got %s", want, data)
+	}
+}
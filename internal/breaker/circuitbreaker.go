@@ -2,9 +2,11 @@ package breaker
 
 import (
 	"context"
-	"fmt"
+	"math"
+	"sync"
 	"sync/atomic"
 	"time"
+	"weak"
 )
 
 // CircuitBreaker implements an adaptive circuit breaker pattern.
@@ -49,6 +51,7 @@ import (
 //   - requests, totalSuccesses, totalFailures: Cumulative counts
 //   - consecutiveSuccesses, consecutiveFailures: Streak counts
 //   - halfOpenRequests: Current half-open concurrent request count
+//   - halfOpenProbes: Probes admitted in the current half-open episode
 //   - openedAt, lastClearedAt, stateChangedAt: Timestamps
 //
 // Do not construct CircuitBreaker directly; use New() constructor which validates
@@ -71,23 +74,94 @@ import (
 //	    return cachedResponse, nil
 //	}
 type CircuitBreaker struct {
-	name string
+	name   string
+	labels map[string]string // Never modified after construction
 
-	// Settings (immutable - set once at creation)
-	readyToTrip       func(Counts) bool
-	onStateChange     func(string, State, State)
-	isSuccessful      func(error) bool
-	adaptiveThreshold bool
+	// Settings (immutable - set once at creation). Grouped by size, so the
+	// 4-byte and 1-byte fields pack without padding
+	readyToTrip             func(Counts) bool
+	readyToTripEx           func(Counts, WindowInfo) bool
+	defaultTrip             func(Counts) bool
+	shadowReadyToTrip       func(Counts) bool
+	onStateChange           func(string, State, State)
+	onStateChangeDetailed   func(string, State, State, Counts)
+	onDisabledChange        func(string, bool)
+	isProbeSuccessful       func(interface{}, error, time.Duration) bool
+	outcomeWeight           func(interface{}, error) float64
+	onDegraded              func(string, float64)
+	onWarning               func(string, float64, Counts)
+	onPanicThreshold        func(string, uint32)
+	streamFailure           func(err error) bool
+	probeGate               func() bool
+	engine                  DecisionEngine // Settings.Engine, or defaultEngine
+	defaultEngine           countsEngine   // The trip rule, when Settings.Engine is unset
+	relativeBaseline        *CircuitBreaker
+	slowCallFactor          float64
+	warnFailureRate         float64
+	rateEpsilon             float64
+	relativeMultiplier      float64
+	errorBudget             float64
+	burnRateThreshold       float64
+	healthScoreAlpha        float64
+	expectedRequestRate     float64
+	minObservationWindow    time.Duration
+	streamTimeout           time.Duration
+	eligibleProbeWait       time.Duration
+	probeFairnessWait       time.Duration
+	diagnosticsCacheTTL     time.Duration
+	historyInterval         time.Duration
+	consecutiveThreshold    uint32
+	recoveryWindows         uint32
+	halfOpenMaxProbes       uint32
+	classifierPanicLatch    uint32
+	maxRequestsPerCycle     uint32
+	panicThreshold          uint32
+	transitionLoserBehavior TransitionLoserBehavior
+	classifierPanicOutcome  ClassifierPanicOutcome
+	adaptiveThreshold       bool
+	predictiveReject        bool
+	trackLatency            bool
+	watchesRate             bool // WarnFailureRate or AdaptiveThreshold set: rate latches evaluated per outcome
+	customReadyToTrip       bool
+	customEngine            bool // Settings.Engine is set
+	orderStateChanges       bool
+	requireAllSuccesses     bool
+	probeRejectionDetails   bool
+	rejectIneligibleAsOpen  bool
+	startHalfOpen           bool
+	preserveStreaks         bool
+	intervalResetsOpen      bool
+	alignInterval           bool
+	requireFullWindow       bool
+	countNestedRejections   bool
+	logTripJournal          bool
+	pprofLabels             bool
+	recoverPanics           bool
+	strict                  bool
+	logConfigWarnings       bool
 
 	// Settings (atomic - updateable at runtime)
 	maxRequests          atomic.Uint32 // uint32
+	intervalEnabled      atomic.Bool   // cached interval > 0, checked on the hot path
 	interval             atomic.Int64  // time.Duration (int64)
 	timeout              atomic.Int64  // time.Duration (int64)
 	failureRateThreshold atomic.Uint64 // float64 (stored as bits)
 	minimumObservations  atomic.Uint32 // uint32
 
-	// State (atomic)
-	state atomic.Int32 // State (0=Closed, 1=Open, 2=HalfOpen)
+	warningThresholdFraction atomic.Uint64 // float64 (stored as bits)
+	recoverFailureRate       atomic.Uint64 // float64 (stored as bits)
+
+	// State machine (atomic) - the State (0=Closed, 1=Open, 2=HalfOpen) and the
+	// Disable() flag in the low bits and the transition epoch above them, so the
+	// single CAS that commits a transition also numbers it (see commitTransition)
+	state atomic.Uint64
+
+	// Plain closed path (see plain_path.go) - plainClosed caches whether Closed
+	// calls may skip the closed-path features; closedFeatures is its part fixed
+	// at construction, plainMu serializes its recomputes
+	plainClosed    atomic.Bool
+	plainMu        sync.Mutex
+	closedFeatures bool
 
 	// Counts (atomic)
 	requests             atomic.Uint32
@@ -95,32 +169,211 @@ type CircuitBreaker struct {
 	totalFailures        atomic.Uint32
 	consecutiveSuccesses atomic.Uint32
 	consecutiveFailures  atomic.Uint32
+	failureWeight        atomic.Uint64 // float64 (stored as bits), only with outcomeWeight
+	panics               atomic.Uint32
+	timeoutFailures      atomic.Uint32
+
+	// Panic storm latch (atomic) - set once OnPanicThreshold fired in the current window
+	panicThresholdFired atomic.Bool
+
+	// Execution attempts in the current window not reflected in requests:
+	// rejected, or admitted and then uncounted (atomic). Demand is requests +
+	// unserved, so served attempts cost nothing extra
+	unserved atomic.Uint64
 
 	// Half-open limiter (atomic)
 	halfOpenRequests atomic.Int32
 
+	// Half-open probe budget (atomic) - probes admitted in the current HalfOpen episode
+	halfOpenProbes atomic.Uint32
+
+	// Half-open probe verdicts (atomic) - decided probes in the current HalfOpen
+	// episode, per IsProbeSuccessful when set (may differ from the counts)
+	probeSuccesses atomic.Uint32
+	probeFailures  atomic.Uint32
+
+	// Probes executing (atomic) - acquired half-open slots only, unlike
+	// halfOpenRequests which rejected callers briefly increment; probeStartedAt
+	// (UnixNano) is when the count last rose from zero
+	probesInFlight atomic.Int32
+	probeStartedAt atomic.Int64
+
+	// Open periods: the episode in progress and the last EpisodeHistory
+	// finished ones (nil when EpisodeHistory is 0)
+	episode  atomic.Pointer[episodeRecord]
+	episodes *episodeLog
+
+	// Probe outcomes (atomic, cumulative) - every HalfOpen probe's verdict,
+	// unlike probeSuccesses/probeFailures which count one episode's budget
+	probeSuccessesTotal atomic.Uint64
+	probeFailuresTotal  atomic.Uint64
+
+	// Probe eligibility (atomic) - awaitingEligible is set when a
+	// probe-ineligible request is turned away in the current HalfOpen episode
+	// and cleared once a probe is admitted
+	awaitingEligible atomic.Bool
+
+	// Probe fairness (atomic) - the fairness key of the last probe admitted in
+	// the current HalfOpen episode (nil when none, or fairness is unused)
+	lastProbeKey atomic.Pointer[probeKey]
+
+	// Rare event counters (atomic, cumulative) - nil until the first event
+	// (see eventCounter)
+	events atomic.Pointer[eventCounts]
+
 	// Timestamps (atomic, int64 nanoseconds)
 	openedAt       atomic.Int64
 	lastClearedAt  atomic.Int64
 	stateChangedAt atomic.Int64
 
+	// Window generation (atomic) - advanced whenever the counts are discarded
+	// (a state transition, an interval clear, or a reset by UpdateSettings or
+	// Enable). Calls capture it on admission (see lateOutcome)
+	generation atomic.Uint64
+
+	// Success classifier (atomic) - IsSuccessful, replaceable by UpdateSettings
+	isSuccessful atomic.Pointer[func(error) bool]
+
+	// Classifier panics (atomic) - the current streak of consecutive
+	// IsSuccessful panics, and the ClassifierPanicLatch latch
+	classifierPanicStreak atomic.Uint32
+	classifierLatched     atomic.Bool
+
+	// Clock - nil for the system clock; otherwise the clock now() reads and
+	// its anchor readings. Set before use
+	clock *clockAnchor
+
+	// State change dispatcher - nil unless orderStateChanges is set
+	stateChanges *stateChangeDispatcher
+
+	// Backend-requested backoff (atomic, int64 nanoseconds) - see ExecuteWithHint.
+	// retryAfterUntil is the latest pending hint, consumed on entering Open;
+	// openUntil is when the current open period ends (0: use Timeout).
+	retryAfterUntil atomic.Int64
+	openUntil       atomic.Int64
+
+	// Open rejection fast path (atomic) - openDeadline is the time (see now)
+	// until which the circuit keeps rejecting, 0 when unknown;
+	// openDeadlineGen counts invalidations; openHeld is set until a timer
+	// releases it at openDeadline, so rejections skip the clock
+	openDeadline    atomic.Int64
+	openDeadlineGen atomic.Uint64
+	openHeld        atomic.Bool
+
+	// Incident tracking - nil unless MaxOpenDuration or AdaptiveTimeout is set
+	incident *incidentTracker
+
+	// Learned open wait - nil unless AdaptiveTimeout is set
+	learner *timeoutLearner
+
 	// Saturation flags (atomic) - used for log-once behavior
 	// When a counter saturates at math.MaxUint32, the flag is set to true
 	// and only one warning is logged. Flags reset when counts are cleared.
 	requestsSaturated       atomic.Bool
 	totalSuccessesSaturated atomic.Bool
 	totalFailuresSaturated  atomic.Bool
+	unservedSaturated       atomic.Bool
+
+	// Degraded flag (atomic) - set while failure rate is in the warn band
+	degraded atomic.Bool
+
+	// Warning latch (atomic) - set once OnWarning fired, until re-armed
+	warningLatched atomic.Bool
+
+	// Shadow trip rule (atomic) - latched on the first shadow trip in a window
+	shadowTripped atomic.Bool
+
+	// Maintenance mode (atomic) - outcomes are executed but not recorded
+	maintenance atomic.Bool
+
+	// Requests admitted in the current closed period (MaxRequestsPerCycle)
+	cycleAdmitted atomic.Uint32
+
+	// Minimum closed duration - nil unless MinClosedDuration > 0
+	hold *closedHold
+
+	// Bulkhead (MaxConcurrent) - nil when unlimited
+	bulkhead *bulkhead
+
+	// Partial window flag (atomic) - set while an aligned first window entered
+	// part-way through is in progress and RequireFullWindow holds off adaptive trips
+	partialWindow atomic.Bool
+
+	// Health latch (atomic) - set when the trip rate is exceeded, cleared once the
+	// rate recovers to RecoverFailureRate. While set, trips compare against
+	// RecoverFailureRate (trip/recover hysteresis)
+	unhealthy atomic.Bool
+
+	// Recovery period (atomic) - clean windows still required after a HalfOpen →
+	// Closed transition before the backend counts as recovered (RecoveryWindows)
+	recoveryWindowsLeft atomic.Uint32
+
+	// Dependencies (atomic, copy-on-write) - breakers whose Open state disables this one
+	dependencies atomic.Pointer[[]*CircuitBreaker]
+
+	// Mirroring (atomic) - the breaker this one follows (see MirrorFrom), and
+	// the breakers following this one (copy-on-write)
+	mirrorSource atomic.Pointer[CircuitBreaker]
+	mirrors      atomic.Pointer[[]*CircuitBreaker]
+
+	// Aggregate views (atomic, copy-on-write) - weak references to the views
+	// this breaker is a member of (see NewAggregateView)
+	views atomic.Pointer[[]weak.Pointer[AggregateView]]
+
+	// Open reason (atomic) - why the circuit last entered Open, nil once Closed
+	openReason atomic.Pointer[OpenReason]
+
+	// Last trip (atomic) - the reason recorded by the most recent Closed → Open
+	// transition, kept after the circuit closes
+	lastTrip atomic.Pointer[OpenReason]
+
+	// Diagnostics prediction cache (atomic) - only used when diagnosticsCacheTTL > 0
+	willTripCache atomic.Pointer[willTripCache]
+
+	// Last-known-good result cache - nil unless CacheTTL > 0
+	cache *resultCache
+
+	// Fallback chain (cumulative) - ExecuteWithFallbacks calls served by each
+	// fallback, by name; nil until a fallback first serves a call
+	fallbackServed atomic.Pointer[sync.Map] // string -> *atomic.Uint64
+
+	// Metrics history ring - nil unless historyInterval > 0
+	history *metricsHistory
+
+	// Outcome journal ring - nil unless JournalSize > 0
+	journal *outcomeJournal
+
+	// Smoothed success rate (atomic, float64 stored as bits) - only used when
+	// healthScoreAlpha > 0
+	healthScore atomic.Uint64
+
+	// Baseline analyzer - nil unless RecommendationWindow > 0
+	baseline *baselineAnalyzer
+
+	// Recent request counts - nil unless minObservationWindow > 0
+	recent *recentRequests
+
+	// Latency tracking (atomic buckets) - nil unless trackLatency is set
+	latency *latencyHistogram
+
+	// Lifecycle - closed is set by Close(), which also wakes bulkhead waiters
+	// and stops the probe watchdog's timers
+	closed atomic.Bool
+
+	// Half-open probe watchdog - nil unless HalfOpenProbeTimeout > 0
+	watchdog *probeWatchdog
 }
 
 // New creates a new circuit breaker with the given settings.
 //
 // This constructor validates settings, applies defaults, and initializes the circuit breaker
-// in the Closed state. The returned CircuitBreaker is ready to use immediately and safe
-// for concurrent access.
+// in the Closed state (HalfOpen if StartHalfOpen is set). The returned CircuitBreaker is
+// ready to use immediately and safe for concurrent access.
 //
 // Settings and Defaults:
 //
 //	MaxRequests:          Default 1 if not set (half-open concurrent request limit)
+//	StartHalfOpen:        Default false (start Closed; true makes the first request a probe)
 //	Timeout:              Default 60s if not set (open to half-open transition time)
 //	Interval:             Default 0 (counts never reset, only on state transitions)
 //	ReadyToTrip:          Default based on AdaptiveThreshold setting
@@ -144,7 +397,10 @@ type CircuitBreaker struct {
 //
 // This function panics if settings are invalid:
 //   - FailureRateThreshold not in (0, 1) exclusive range when set with AdaptiveThreshold=true
-//   - Interval is negative
+//   - Interval or Timeout is negative
+//   - With Strict=true, any field that is ignored or shadowed by another field
+//
+// See ValidateSettings for the full list of checks.
 //
 // Use panics (not errors) because invalid settings indicate programmer error that should
 // be caught during development/testing, not at runtime.
@@ -196,27 +452,97 @@ type CircuitBreaker struct {
 //	    // Evaluate failure rate within rolling 60s window
 //	})
 func New(settings Settings) *CircuitBreaker {
-	// Validate adaptive threshold settings
-	if settings.AdaptiveThreshold {
-		// FailureRateThreshold must be in (0, 1) exclusive range if explicitly set
-		if settings.FailureRateThreshold != 0 {
-			if settings.FailureRateThreshold <= 0 || settings.FailureRateThreshold >= 1 {
-				panic(fmt.Sprintf("autobreaker: FailureRateThreshold must be in range (0, 1), got %v", settings.FailureRateThreshold))
-			}
-		}
+	if err := validateSettings(&settings); err != nil {
+		panic(err.Error())
 	}
+	return newBreaker(&settings)
+}
 
-	// Validate Interval (can be 0 for no reset, but not negative)
-	if settings.Interval < 0 {
-		panic(fmt.Sprintf("autobreaker: Interval cannot be negative, got %v", settings.Interval))
+// newBreaker creates a circuit breaker from settings the caller has already
+// validated.
+func newBreaker(settings *Settings) *CircuitBreaker {
+	cb := &CircuitBreaker{
+		name:                    settings.Name,
+		labels:                  copyLabels(settings.Labels),
+		readyToTrip:             settings.ReadyToTrip,
+		readyToTripEx:           settings.ReadyToTripEx,
+		shadowReadyToTrip:       settings.ShadowReadyToTrip,
+		onStateChange:           settings.OnStateChange,
+		onStateChangeDetailed:   settings.OnStateChangeDetailed,
+		orderStateChanges:       settings.OrderStateChanges,
+		onDisabledChange:        settings.OnDisabledChange,
+		isProbeSuccessful:       settings.IsProbeSuccessful,
+		outcomeWeight:           settings.OutcomeWeight,
+		adaptiveThreshold:       settings.AdaptiveThreshold,
+		predictiveReject:        settings.PredictiveReject,
+		trackLatency:            settings.PredictiveReject || settings.TrackLatency || settings.SlowCallFactor > 0,
+		slowCallFactor:          settings.SlowCallFactor,
+		episodes:                newEpisodeLog(settings.EpisodeHistory),
+		warnFailureRate:         settings.WarnFailureRate,
+		onDegraded:              settings.OnDegraded,
+		onWarning:               settings.OnWarning,
+		panicThreshold:          settings.PanicThreshold,
+		onPanicThreshold:        settings.OnPanicThreshold,
+		customReadyToTrip:       settings.ReadyToTrip != nil || settings.ReadyToTripEx != nil,
+		engine:                  settings.Engine,
+		customEngine:            settings.Engine != nil,
+		watchesRate:             settings.WarnFailureRate > 0 || settings.AdaptiveThreshold,
+		consecutiveThreshold:    settings.ConsecutiveFailureThreshold,
+		rateEpsilon:             settings.RateEpsilon,
+		recoveryWindows:         settings.RecoveryWindows,
+		minObservationWindow:    settings.MinObservationWindow,
+		relativeBaseline:        settings.Baseline,
+		relativeMultiplier:      settings.RelativeFailureRateMultiplier,
+		errorBudget:             settings.ErrorBudget,
+		burnRateThreshold:       settings.BurnRateThreshold,
+		halfOpenMaxProbes:       settings.HalfOpenMaxProbes,
+		requireAllSuccesses:     settings.RequireAllSuccesses,
+		probeRejectionDetails:   settings.ProbeRejectionDetails,
+		streamFailure:           settings.IsStreamFailure,
+		streamTimeout:           settings.StreamTimeout,
+		eligibleProbeWait:       settings.EligibleProbeWait,
+		rejectIneligibleAsOpen:  settings.RejectIneligibleAsOpen,
+		probeFairnessWait:       settings.ProbeFairnessWait,
+		probeGate:               settings.ProbeGate,
+		transitionLoserBehavior: settings.TransitionLoserBehavior,
+		startHalfOpen:           settings.StartHalfOpen,
+		preserveStreaks:         settings.PreserveStreaksOnIntervalReset,
+		intervalResetsOpen:      settings.IntervalResetsOpenState,
+		alignInterval:           settings.AlignIntervalToWallClock,
+		requireFullWindow:       settings.RequireFullWindow,
+		diagnosticsCacheTTL:     settings.DiagnosticsCacheTTL,
+		countNestedRejections:   settings.CountNestedRejections,
+		classifierPanicOutcome:  settings.ClassifierPanicOutcome,
+		classifierPanicLatch:    settings.ClassifierPanicLatch,
+		maxRequestsPerCycle:     settings.MaxRequestsPerCycle,
+		historyInterval:         settings.MetricsHistoryInterval,
+		logTripJournal:          settings.LogTripJournal,
+		pprofLabels:             settings.PprofLabels,
+		recoverPanics:           settings.RecoverPanics,
+		healthScoreAlpha:        settings.HealthScoreAlpha,
+		strict:                  settings.Strict,
+		expectedRequestRate:     settings.ExpectedRequestRate,
+		logConfigWarnings:       settings.LogConfigWarnings,
 	}
 
-	cb := &CircuitBreaker{
-		name:              settings.Name,
-		readyToTrip:       settings.ReadyToTrip,
-		onStateChange:     settings.OnStateChange,
-		isSuccessful:      settings.IsSuccessful,
-		adaptiveThreshold: settings.AdaptiveThreshold,
+	if settings.MaxConcurrent > 0 {
+		cb.bulkhead = newBulkhead(settings.MaxConcurrent, settings.MaxConcurrentWait)
+	}
+	if settings.MinClosedDuration > 0 {
+		cb.hold = &closedHold{duration: settings.MinClosedDuration}
+	}
+	if settings.CacheTTL > 0 {
+		cb.cache = &resultCache{
+			ttl:         settings.CacheTTL,
+			lastSuccess: settings.CacheLastSuccess,
+			staleOnBusy: settings.ServeStaleOnTooManyRequests,
+		}
+	}
+	if cb.trackLatency {
+		cb.latency = new(latencyHistogram)
+	}
+	if settings.HalfOpenProbeTimeout > 0 {
+		cb.watchdog = &probeWatchdog{timeout: settings.HalfOpenProbeTimeout}
 	}
 
 	// Set atomic fields using setters
@@ -225,6 +551,8 @@ func New(settings Settings) *CircuitBreaker {
 	cb.setTimeout(settings.Timeout)
 	cb.setFailureRateThreshold(settings.FailureRateThreshold)
 	cb.setMinimumObservations(settings.MinimumObservations)
+	cb.setWarningThresholdFraction(settings.WarningThresholdFraction)
+	cb.setRecoverFailureRate(settings.RecoverFailureRate)
 
 	// Apply defaults
 	if cb.getMaxRequests() == 0 {
@@ -235,31 +563,104 @@ func New(settings Settings) *CircuitBreaker {
 		cb.setTimeout(60 * time.Second)
 	}
 
-	if cb.readyToTrip == nil {
-		if cb.adaptiveThreshold {
-			cb.readyToTrip = cb.defaultAdaptiveReadyToTrip
-		} else {
-			cb.readyToTrip = DefaultReadyToTrip
+	if settings.AdaptiveTimeout {
+		cb.learner = newTimeoutLearner(settings.MinTimeout, settings.MaxTimeout, cb.getTimeout())
+	}
+	if settings.MaxOpenDuration > 0 || settings.AdaptiveTimeout {
+		cb.incident = &incidentTracker{
+			maxOpen:     settings.MaxOpenDuration,
+			action:      settings.StuckOpenAction,
+			onStuckOpen: settings.OnStuckOpen,
+		}
+	}
+
+	switch {
+	case cb.adaptiveThreshold:
+		cb.defaultTrip = cb.defaultAdaptiveReadyToTrip
+	case cb.consecutiveThreshold > 0:
+		cb.defaultTrip = cb.consecutiveReadyToTrip
+	default:
+		cb.defaultTrip = DefaultReadyToTrip
+	}
+	switch {
+	case cb.readyToTripEx != nil:
+		cb.readyToTrip = cb.callReadyToTripEx // Ex wins over ReadyToTrip
+	case cb.readyToTrip == nil:
+		cb.readyToTrip = cb.defaultTrip
+	}
+
+	if cb.engine == nil {
+		cb.defaultEngine.cb = cb
+		cb.engine = &cb.defaultEngine
+	}
+
+	cb.replaceIsSuccessful(settings.IsSuccessful)
+
+	if cb.historyInterval > 0 {
+		retention := settings.MetricsHistoryRetention
+		if retention == 0 {
+			retention = defaultHistoryRetention
 		}
+		cb.history = newMetricsHistory(retention)
 	}
 
-	if cb.isSuccessful == nil {
-		cb.isSuccessful = DefaultIsSuccessful
+	if settings.JournalSize > 0 {
+		cb.journal = newOutcomeJournal(settings.JournalSize, settings.JournalErrorLength)
+	}
+
+	if cb.healthScoreAlpha > 0 {
+		cb.healthScore.Store(math.Float64bits(1)) // Healthy until shown otherwise
+	}
+
+	if cb.orderStateChanges {
+		cb.stateChanges = newStateChangeDispatcher(cb.transitionEpoch())
+	}
+
+	if cb.minObservationWindow > 0 {
+		cb.recent = newRecentRequests(cb.minObservationWindow)
+	}
+
+	if settings.RecommendationWindow > 0 {
+		cb.baseline = newBaselineAnalyzer(*settings)
 	}
 
 	if cb.getFailureRateThreshold() == 0 && cb.adaptiveThreshold {
 		cb.setFailureRateThreshold(0.05) // 5% default
 	}
 
+	if cb.relativeBaseline != nil && cb.relativeMultiplier == 0 {
+		cb.relativeMultiplier = defaultRelativeFailureRateMultiplier
+	}
+
+	if cb.errorBudget > 0 && cb.burnRateThreshold == 0 {
+		cb.burnRateThreshold = defaultBurnRateThreshold
+	}
+
 	if cb.getMinimumObservations() == 0 && cb.adaptiveThreshold {
 		cb.setMinimumObservations(20)
 	}
 
+	if cb.rateEpsilon == 0 {
+		cb.rateEpsilon = defaultRateEpsilon
+	}
+
 	// Initialize state
-	now := time.Now().UnixNano()
-	cb.state.Store(int32(StateClosed))
-	cb.lastClearedAt.Store(now)
+	now := cb.now()
+	cb.initWindow(now)
 	cb.stateChangedAt.Store(now)
+	if settings.StartHalfOpen {
+		// openedAt stays 0: the circuit is probing but has never been open
+		cb.state.Store(uint64(StateHalfOpen))
+	} else {
+		cb.state.Store(uint64(StateClosed))
+	}
+
+	cb.closedFeatures = cb.hasClosedFeatures()
+	cb.refreshPlainClosed()
+
+	if cb.logConfigWarnings {
+		cb.reportConfigWarnings()
+	}
 
 	return cb
 }
@@ -281,12 +682,18 @@ func (cb *CircuitBreaker) Name() string {
 //   - StateClosed: Normal operation, requests pass through
 //   - StateOpen: Circuit tripped, requests fail fast
 //   - StateHalfOpen: Testing recovery, limited requests allowed
+//   - StateDisabled: Bypassed by Disable(), every request passes through
+//
+// While disabled, the state machine is frozen in its last state, which
+// Metrics().MachineState still reports. Maintenance (EnterMaintenance) is not
+// a state of its own: requests are still admitted according to the reported
+// state.
 //
 // The returned state is a point-in-time snapshot. The state may change
 // immediately after this method returns due to concurrent Execute() calls
 // or timeout expiration.
 //
-// Performance: ~1-2ns overhead (single atomic load).
+// Performance: ~1-2ns overhead (two atomic loads).
 //
 // Thread-safe: Safe to call concurrently.
 //
@@ -296,7 +703,17 @@ func (cb *CircuitBreaker) Name() string {
 //	    log.Warn("Circuit is open, failing fast")
 //	}
 func (cb *CircuitBreaker) State() State {
-	return State(cb.state.Load())
+	word := cb.state.Load()
+	if word&disabledBit != 0 {
+		return StateDisabled
+	}
+	return State(word & stateMask)
+}
+
+// machineState returns the state of the state machine, ignoring Disable().
+// Internal decisions use it; State() is what callers observe.
+func (cb *CircuitBreaker) machineState() State {
+	return State(cb.state.Load() & stateMask)
 }
 
 // Counts returns a snapshot of current counts.
@@ -347,6 +764,9 @@ func (cb *CircuitBreaker) Counts() Counts {
 		TotalFailures:        cb.totalFailures.Load(),
 		ConsecutiveSuccesses: cb.consecutiveSuccesses.Load(),
 		ConsecutiveFailures:  cb.consecutiveFailures.Load(),
+		FailureWeight:        math.Float64frombits(cb.failureWeight.Load()), // 0 without OutcomeWeight
+		Panics:               cb.panics.Load(),
+		TimeoutFailures:      cb.timeoutFailures.Load(),
 	}
 }
 
@@ -374,15 +794,46 @@ func (cb *CircuitBreaker) Counts() Counts {
 // If the request function panics, Execute:
 //  1. Counts the panic as a failure
 //  2. Handles state transitions as if request failed
-//  3. Re-panics to preserve stack trace and caller's panic handling
+//  3. Re-panics to preserve stack trace and caller's panic handling, or with
+//     Settings.RecoverPanics returns (nil, *PanicError) instead
+//
+// Ignored Outcomes:
+//
+// If the request function returns an error matching ErrIgnoreOutcome (errors.Is),
+// the execution is not attributed to the backend: the request is uncounted and
+// neither a success nor a failure is recorded. A half-open probe releases its slot
+// without deciding the probe. The caller receives the underlying error with the
+// sentinel removed (nil if ErrIgnoreOutcome was returned alone):
+//
+//	result, err := breaker.Execute(func() (interface{}, error) {
+//	    if err := validate(input); err != nil {
+//	        return nil, errors.Join(autobreaker.ErrIgnoreOutcome, err) // our fault, not the backend's
+//	    }
+//	    return backend.Call(input)
+//	})
+//
+// Nested Rejections:
+//
+// If the request function itself calls another circuit breaker and returns its
+// rejection (ErrOpenState, ErrTooManyRequests, ErrTooManyConcurrent,
+// ErrDependencyOpen, ErrDeadlineTooShort, matched with errors.Is), the outcome is ignored the same
+// way, so an open inner breaker doesn't trip every breaker above it. The error is
+// returned unchanged. Set Settings.CountNestedRejections to count them as failures.
 //
 // Return Values:
 //
 //   - Success: Returns (result, err) from request function
 //   - Circuit Open: Returns (nil, ErrOpenState) without executing request
 //   - Too Many Requests: Returns (nil, ErrTooManyRequests) in half-open with exceeded MaxRequests
+//     (a *TooManyRequestsError wrapping it with Settings.ProbeRejectionDetails)
+//   - Too Many Concurrent: Returns (nil, ErrTooManyConcurrent) when no MaxConcurrent
+//     slot frees up within MaxConcurrentWait
 //   - Application Error: Returns (result, err) unchanged; isSuccessful determines if counted as failure
 //
+// The result is always passed through untouched, including on the failure path, so
+// a request function may return a usable partial result together with an error.
+// Use Settings.OutcomeWeight to classify such calls as partial failures.
+//
 // Performance: <100ns overhead in Closed state (hot path). Uses lock-free atomic operations.
 //
 // Thread-safe: Can be called concurrently from multiple goroutines. State transitions
@@ -426,82 +877,20 @@ func (cb *CircuitBreaker) Counts() Counts {
 //	    return riskyOperation() // May panic
 //	})
 func (cb *CircuitBreaker) Execute(req func() (interface{}, error)) (interface{}, error) {
-	// Check if interval-based count clearing is needed (only in Closed state)
-	if cb.getInterval() > 0 && cb.State() == StateClosed {
-		cb.maybeResetCounts()
-	}
-
-	// Capture current state for state machine logic
-	currentState := cb.State()
-
-	// Check state and handle accordingly
-	if currentState == StateOpen {
-		// Circuit is open - check if we should transition to half-open
-		if cb.shouldTransitionToHalfOpen() {
-			cb.transitionToHalfOpen()
-			currentState = StateHalfOpen // Update local state
-			// Fall through to half-open handling
-		} else {
-			// Reject immediately without counting as a request
-			return nil, ErrOpenState
-		}
-	}
-
-	// Request is allowed - attempt to increment count with saturation protection.
-	// If counter is saturated (safeIncrementRequests returns false), request still
-	// proceeds but won't be counted in statistics.
-	requestCounted := cb.safeIncrementRequests()
-
-	// Handle half-open state with request limiting
-	if currentState == StateHalfOpen {
-		// Check if we've reached max concurrent requests in half-open
-		current := cb.halfOpenRequests.Add(1)
-		if current > int32(cb.getMaxRequests()) {
-			cb.halfOpenRequests.Add(-1) // Undo increment
-			return nil, ErrTooManyRequests
-		}
-		defer cb.halfOpenRequests.Add(-1)
-	}
-
-	// Execute the request with panic recovery
-	var result interface{}
-	var err error
-	panicked := false
-
-	func() {
-		defer func() {
-			if r := recover(); r != nil {
-				// Panic occurred - treat as failure
-				panicked = true
-				// Record panic as failure
-				cb.recordOutcome(false)
-
-				// Handle state transitions for panic (same as failure)
-				cb.handleStateTransition(false, currentState)
-
-				// Re-panic to preserve stack trace
-				panic(r)
-			}
-		}()
-
-		result, err = req()
-	}()
-
-	// If we got here without panic, record normal outcome
-	if !panicked {
-		// If request wasn't counted due to saturation, skip recording
-		if !requestCounted {
-			return result, err
-		}
-		// Call isSuccessful with panic recovery
-		success := safeCallIsSuccessful(cb.name, cb.isSuccessful, err)
-		cb.recordOutcome(success)
-
-		// Handle state transitions based on outcome
-		cb.handleStateTransition(success, currentState)
-	}
+	var a admission
+	a.opts = anyProbe
+	return cb.run(&a, req, nil)
+}
 
-	return result, err
+// execute implements Execute. If success is non-nil, the value it points to when
+// req returns overrides IsSuccessful and OutcomeWeight for this call (see
+// ExecuteClassified). If synthetic is set, the call is admitted normally but its
+// outcome is kept out of the counts (see ExecuteUncounted). opts restricts which
+// calls may act as half-open probes (see ExecuteWithOpts).
+func (cb *CircuitBreaker) execute(req func() (interface{}, error), success *bool, synthetic bool, opts ExecuteOpts) (interface{}, error) {
+	var a admission
+	a.opts, a.synthetic = opts, synthetic
+	return cb.run(&a, req, success)
 }
 
 // ExecuteContext runs the given request function if the circuit breaker allows it,
@@ -532,14 +921,32 @@ func (cb *CircuitBreaker) Execute(req func() (interface{}, error)) (interface{},
 // This design ensures context cancellation doesn't trip the circuit, as it indicates
 // client-side cancellation, not backend health issues.
 //
+// Per-Call Directives:
+//
+// A context from WithBypass runs the request outside the breaker, and one from
+// WithOutcomeIgnored leaves its outcome uncounted. See those functions before
+// using either.
+//
 // Return Values:
 //
 //   - Success: Returns (result, err) from request function
 //   - Context Canceled: Returns (nil, ctx.Err()) - context.Canceled or context.DeadlineExceeded
 //   - Circuit Open: Returns (nil, ErrOpenState) without executing request
 //   - Too Many Requests: Returns (nil, ErrTooManyRequests) in half-open with exceeded MaxRequests
+//     (a *TooManyRequestsError wrapping it with Settings.ProbeRejectionDetails)
+//   - Too Many Concurrent: Returns (nil, ErrTooManyConcurrent) when no MaxConcurrent
+//     slot frees up within MaxConcurrentWait
+//   - Deadline Too Short: Returns (nil, ErrDeadlineTooShort) when PredictiveReject is enabled
+//     and the context deadline is shorter than the learned p95 latency
 //   - Application Error: Returns (result, err) unchanged; isSuccessful determines if counted as failure
 //
+// Predictive Rejection:
+//
+// When Settings.PredictiveReject is enabled, ExecuteContext compares the time remaining
+// until the context deadline with the learned p95 latency of the backend. Requests that
+// clearly cannot finish in time are rejected with ErrDeadlineTooShort before execution.
+// These rejections are not counted as requests and do not affect circuit state.
+//
 // Performance: Same as Execute() (~<100ns overhead in Closed state).
 //
 // Thread-safe: Can be called concurrently from multiple goroutines with different contexts.
@@ -611,108 +1018,9 @@ func (cb *CircuitBreaker) Execute(req func() (interface{}, error)) (interface{},
 //
 //   - Simpler API is preferred
 func (cb *CircuitBreaker) ExecuteContext(ctx context.Context, req func() (interface{}, error)) (interface{}, error) {
-	// Check context before attempting execution
-	if err := ctx.Err(); err != nil {
-		// Context already canceled/expired, return immediately
-		// Don't increment request count since we never attempted execution
-		return nil, err
-	}
-
-	// Check if interval-based count clearing is needed (only in Closed state)
-	if cb.getInterval() > 0 && cb.State() == StateClosed {
-		cb.maybeResetCounts()
-	}
-
-	// Capture current state for state machine logic
-	currentState := cb.State()
-
-	// Check state and handle accordingly
-	if currentState == StateOpen {
-		// Circuit is open - check if we should transition to half-open
-		if cb.shouldTransitionToHalfOpen() {
-			cb.transitionToHalfOpen()
-			currentState = StateHalfOpen // Update local state
-			// Fall through to half-open handling
-		} else {
-			// Reject immediately without counting as a request
-			return nil, ErrOpenState
-		}
-	}
-
-	// Request is allowed - attempt to increment count with saturation protection.
-	// If counter is saturated (safeIncrementRequests returns false), request still
-	// proceeds but won't be counted in statistics.
-	requestCounted := cb.safeIncrementRequests()
-
-	// Check context again after counting but before expensive operation
-	if err := ctx.Err(); err != nil {
-		// Context canceled between state check and now
-		// Need to undo request count if we incremented it
-		if requestCounted {
-			cb.safeDecrementRequests()
-		}
-		return nil, err
-	}
-
-	// Handle half-open state with request limiting
-	if currentState == StateHalfOpen {
-		// Check if we've reached max concurrent requests in half-open
-		current := cb.halfOpenRequests.Add(1)
-		if current > int32(cb.getMaxRequests()) {
-			cb.halfOpenRequests.Add(-1) // Undo increment
-			return nil, ErrTooManyRequests
-		}
-		defer cb.halfOpenRequests.Add(-1)
-	}
-
-	// Execute the request with panic recovery
-	var result interface{}
-	var err error
-	panicked := false
-
-	func() {
-		defer func() {
-			if r := recover(); r != nil {
-				// Panic occurred - treat as failure
-				panicked = true
-				// Record panic as failure
-				cb.recordOutcome(false)
-
-				// Handle state transitions for panic (same as failure)
-				cb.handleStateTransition(false, currentState)
-
-				// Re-panic to preserve stack trace
-				panic(r)
-			}
-		}()
-
-		result, err = req()
-	}()
-
-	// Check context after execution
-	if ctxErr := ctx.Err(); ctxErr != nil {
-		// Context was canceled/expired during execution
-		// Undo request count to maintain invariant: Requests == TotalSuccesses + TotalFailures
-		// We don't record outcome for canceled requests (not a backend health indicator)
-		if requestCounted {
-			cb.safeDecrementRequests()
-		}
-		return nil, ctxErr
-	}
-
-	// If we got here without panic and context is still valid, record normal outcome
-	if !panicked {
-		// If request wasn't counted due to saturation, skip recording
-		if !requestCounted {
-			return result, err
-		}
-		// Call isSuccessful with panic recovery
-		success := safeCallIsSuccessful(cb.name, cb.isSuccessful, err)
-		cb.recordOutcome(success)
-
-		// Handle state transitions based on outcome
-		cb.handleStateTransition(success, currentState)
-	}
-
-	return result, err
+	// Per-call directives (WithBypass, WithOutcomeIgnored): none, without a
+	// context lookup, unless a directive has ever been set
+	var a admission
+	a.ctx, a.opts, a.directives = ctx, anyProbe, callDirectives(ctx)
+	return cb.run(&a, req, nil)
 }
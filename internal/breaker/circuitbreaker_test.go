@@ -109,7 +109,7 @@ func TestExecuteOpenState(t *testing.T) {
 	cb := New(Settings{Name: "test"})
 
 	// Manually set state to Open
-	cb.state.Store(int32(StateOpen))
+	cb.state.Store(uint64(StateOpen))
 
 	// Attempt execution
 	result, err := cb.Execute(successFunc)
@@ -135,7 +135,7 @@ func TestExecuteHalfOpenMaxRequests(t *testing.T) {
 	})
 
 	// Set state to HalfOpen
-	cb.state.Store(int32(StateHalfOpen))
+	cb.state.Store(uint64(StateHalfOpen))
 
 	// Use a slow function to keep requests in-flight
 	slowFunc := func() (interface{}, error) {
@@ -0,0 +1,36 @@
+package breaker
+
+// ExecuteClassified runs req like Execute, but req reports whether the call
+// succeeded. The success flag overrides IsSuccessful (and OutcomeWeight) for this
+// call only, so the same error can count as a success in one flow and a failure
+// in another without changing the breaker's global policy.
+//
+// Everything else behaves as in Execute: admission, panics (always failures),
+// maintenance, and ErrIgnoreOutcome (an ignored call is neither a success nor a
+// failure, whatever the flag says). Rejections from nested breakers are classified
+// by the flag rather than ignored. The result and error are returned unchanged.
+//
+// Thread-safe: Safe to call concurrently.
+//
+// Example - a 404 that is expected in this flow:
+//
+//	result, err := breaker.ExecuteClassified(func() (interface{}, error, bool) {
+//	    user, err := users.Get(ctx, id)
+//	    return user, err, err == nil || errors.Is(err, ErrNotFound)
+//	})
+func (cb *CircuitBreaker) ExecuteClassified(req func() (interface{}, error, bool)) (interface{}, error) {
+	var success bool
+	return cb.execute(func() (interface{}, error) {
+		result, err, ok := req()
+		success = ok
+		return result, err
+	}, &success, false, anyProbe)
+}
+
+// overrideWeight converts an explicit per-call success flag to a failure weight.
+func overrideWeight(success bool) float64 {
+	if success {
+		return 0
+	}
+	return 1
+}
@@ -0,0 +1,126 @@
+package breaker
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+var errNotFound = errors.New("not found")
+
+func TestExecuteClassified_OverridesPerCall(t *testing.T) {
+	cb := New(Settings{Name: "users", Timeout: time.Minute})
+
+	// Expected 404 in a lookup flow: success
+	result, err := cb.ExecuteClassified(func() (interface{}, error, bool) {
+		return "cached", errNotFound, true
+	})
+	if !errors.Is(err, errNotFound) || result != "cached" {
+		t.Errorf("Expected result and error passed through, got %v, %v", result, err)
+	}
+
+	// Same error where the record must exist: failure
+	cb.ExecuteClassified(func() (interface{}, error, bool) {
+		return nil, errNotFound, false
+	})
+
+	counts := cb.Counts()
+	if counts.Requests != 2 || counts.TotalSuccesses != 1 || counts.TotalFailures != 1 {
+		t.Errorf("Expected 1 success and 1 failure, got %+v", counts)
+	}
+}
+
+func TestExecuteClassified_BypassesIsSuccessful(t *testing.T) {
+	calls := 0
+	cb := New(Settings{
+		Name: "users",
+		IsSuccessful: func(err error) bool {
+			calls++
+			return err == nil
+		},
+	})
+
+	// A nil error the caller judges a failure (e.g. an empty payload)
+	cb.ExecuteClassified(func() (interface{}, error, bool) {
+		return nil, nil, false
+	})
+
+	if calls != 0 {
+		t.Errorf("Expected IsSuccessful not to be called, got %d calls", calls)
+	}
+	if got := cb.Counts().TotalFailures; got != 1 {
+		t.Errorf("Expected override failure counted, got %d failures", got)
+	}
+
+	// Plain Execute still uses the global policy
+	cb.Execute(func() (interface{}, error) { return nil, errNotFound })
+	if calls != 1 {
+		t.Errorf("Expected IsSuccessful for Execute, got %d calls", calls)
+	}
+}
+
+func TestExecuteClassified_OverridesOutcomeWeight(t *testing.T) {
+	cb := New(Settings{Name: "batch", OutcomeWeight: batchWeight})
+
+	cb.ExecuteClassified(func() (interface{}, error, bool) {
+		result, err := batchCall(9)()
+		return result, err, true
+	})
+
+	counts := cb.Counts()
+	if counts.TotalSuccesses != 1 || counts.FailureWeight != 0 {
+		t.Errorf("Expected override success with zero weight, got %+v", counts)
+	}
+}
+
+func TestExecuteClassified_TripsAndProbes(t *testing.T) {
+	cb := New(Settings{Name: "users", Timeout: 10 * time.Millisecond})
+
+	for i := 0; i < 6; i++ {
+		cb.ExecuteClassified(func() (interface{}, error, bool) {
+			return nil, nil, false
+		})
+	}
+	if cb.State() != StateOpen {
+		t.Fatalf("Expected override failures to trip, got %v", cb.State())
+	}
+
+	if _, err := cb.ExecuteClassified(func() (interface{}, error, bool) {
+		t.Error("Request must not run while open")
+		return nil, nil, true
+	}); !errors.Is(err, ErrOpenState) {
+		t.Errorf("Expected ErrOpenState, got %v", err)
+	}
+
+	time.Sleep(20 * time.Millisecond)
+	cb.ExecuteClassified(func() (interface{}, error, bool) {
+		return nil, errNotFound, true
+	})
+	if cb.State() != StateClosed {
+		t.Errorf("Expected override success probe to close, got %v", cb.State())
+	}
+}
+
+func TestExecuteClassified_IgnoreOutcomeAndPanic(t *testing.T) {
+	cb := New(Settings{Name: "users"})
+
+	_, err := cb.ExecuteClassified(func() (interface{}, error, bool) {
+		return nil, errors.Join(ErrIgnoreOutcome, errNotFound), false
+	})
+	if !errors.Is(err, errNotFound) || errors.Is(err, ErrIgnoreOutcome) {
+		t.Errorf("Expected sentinel stripped, got %v", err)
+	}
+	if got := cb.Counts().Requests; got != 0 {
+		t.Errorf("Expected ignored call uncounted, got %d requests", got)
+	}
+
+	func() {
+		defer func() { _ = recover() }()
+		cb.ExecuteClassified(func() (interface{}, error, bool) {
+			panic("boom")
+		})
+	}()
+	if got := cb.Counts().TotalFailures; got != 1 {
+		t.Errorf("Expected panic counted as failure, got %d", got)
+	}
+}
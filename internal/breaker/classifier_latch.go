@@ -0,0 +1,49 @@
+package breaker
+
+// defaultClassifier is the IsSuccessful installed when none is set. classify
+// calls it directly, without panic recovery: it cannot panic.
+var defaultClassifier = DefaultIsSuccessful
+
+// classify classifies a call's error with IsSuccessful, or with
+// DefaultIsSuccessful once ClassifierPanicLatch has latched. panicked reports
+// that IsSuccessful panicked, in which case success is meaningless.
+func (cb *CircuitBreaker) classify(err error) (success, panicked bool) {
+	fn := cb.isSuccessful.Load()
+	if fn == &defaultClassifier || cb.classifierLatched.Load() {
+		return DefaultIsSuccessful(err), false
+	}
+
+	success, panicked = safeCallIsSuccessful(cb.name, *fn, err)
+	if !panicked {
+		if cb.classifierPanicStreak.Load() != 0 {
+			cb.classifierPanicStreak.Store(0)
+		}
+		return success, false
+	}
+	cb.classifierPanicked()
+	return success, true
+}
+
+// classifierPanicked extends the IsSuccessful panic streak, latching
+// DefaultIsSuccessful once it reaches ClassifierPanicLatch.
+func (cb *CircuitBreaker) classifierPanicked() {
+	streak := cb.classifierPanicStreak.Add(1)
+	if cb.classifierPanicLatch > 0 && streak >= cb.classifierPanicLatch &&
+		cb.classifierLatched.CompareAndSwap(false, true) {
+		logClassifierLatched(cb.name, streak)
+	}
+}
+
+// replaceIsSuccessful installs fn (DefaultIsSuccessful if nil) as the success
+// classifier and releases the ClassifierPanicLatch latch.
+func (cb *CircuitBreaker) replaceIsSuccessful(fn func(error) bool) {
+	if fn == nil {
+		cb.isSuccessful.Store(&defaultClassifier)
+	} else {
+		custom := fn // Only a custom classifier is copied to the heap
+		cb.isSuccessful.Store(&custom)
+	}
+	cb.classifierPanicStreak.Store(0)
+	cb.classifierLatched.Store(false)
+	cb.refreshPlainClosed()
+}
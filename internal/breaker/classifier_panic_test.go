@@ -0,0 +1,257 @@
+package breaker
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func panickingClassifier(err error) bool {
+	if err != nil {
+		panic("classifier bug")
+	}
+	return true
+}
+
+func TestClassifierPanicOutcome_Policies(t *testing.T) {
+	tests := []struct {
+		outcome       ClassifierPanicOutcome
+		wantRequests  uint32
+		wantSuccesses uint32
+		wantFailures  uint32
+	}{
+		{ClassifierPanicDefault, 1, 0, 1},
+		{ClassifierPanicFailure, 1, 0, 1},
+		{ClassifierPanicSuccess, 1, 1, 0},
+		{ClassifierPanicIgnore, 0, 0, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.outcome.String(), func(t *testing.T) {
+			cb := New(Settings{
+				Name:                   "classifier",
+				IsSuccessful:           panickingClassifier,
+				ClassifierPanicOutcome: tt.outcome,
+			})
+
+			result, err := cb.Execute(func() (interface{}, error) {
+				return "partial", errNotFound
+			})
+			if result != "partial" || !errors.Is(err, errNotFound) {
+				t.Errorf("Expected result and error passed through, got %v, %v", result, err)
+			}
+
+			counts := cb.Counts()
+			if counts.Requests != tt.wantRequests || counts.TotalSuccesses != tt.wantSuccesses ||
+				counts.TotalFailures != tt.wantFailures {
+				t.Errorf("Expected %d requests, %d successes, %d failures, got %+v",
+					tt.wantRequests, tt.wantSuccesses, tt.wantFailures, counts)
+			}
+
+			// The breaker keeps working: a call the classifier handles is recorded
+			if _, err := cb.Execute(func() (interface{}, error) { return "ok", nil }); err != nil {
+				t.Fatalf("Expected follow-up call to succeed, got %v", err)
+			}
+			if got := cb.Counts().TotalSuccesses; got != tt.wantSuccesses+1 {
+				t.Errorf("Expected %d successes after follow-up, got %d", tt.wantSuccesses+1, got)
+			}
+		})
+	}
+}
+
+func TestClassifierPanicOutcome_OutcomeWeight(t *testing.T) {
+	tests := []struct {
+		outcome    ClassifierPanicOutcome
+		wantWeight float64
+		wantCount  uint32
+	}{
+		{ClassifierPanicDefault, 0, 1},
+		{ClassifierPanicFailure, 1, 1},
+		{ClassifierPanicSuccess, 0, 1},
+		{ClassifierPanicIgnore, 0, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.outcome.String(), func(t *testing.T) {
+			cb := New(Settings{
+				Name: "classifier",
+				OutcomeWeight: func(interface{}, error) float64 {
+					panic("weight bug")
+				},
+				ClassifierPanicOutcome: tt.outcome,
+			})
+
+			cb.Execute(func() (interface{}, error) { return nil, nil })
+
+			counts := cb.Counts()
+			if counts.Requests != tt.wantCount || counts.FailureWeight != tt.wantWeight {
+				t.Errorf("Expected %d requests with weight %v, got %+v", tt.wantCount, tt.wantWeight, counts)
+			}
+		})
+	}
+}
+
+func TestClassifierPanicOutcome_SuccessDoesNotTrip(t *testing.T) {
+	cb := New(Settings{
+		Name:                   "classifier",
+		IsSuccessful:           panickingClassifier,
+		ClassifierPanicOutcome: ClassifierPanicSuccess,
+		ReadyToTrip: func(counts Counts) bool {
+			return counts.ConsecutiveFailures >= 3
+		},
+	})
+
+	for i := 0; i < 10; i++ {
+		cb.Execute(func() (interface{}, error) { return nil, errNotFound })
+	}
+	if cb.State() != StateClosed {
+		t.Errorf("Expected Closed despite classifier panics, got %v", cb.State())
+	}
+}
+
+func TestClassifierPanicOutcome_IgnoreInHalfOpen(t *testing.T) {
+	cb := New(Settings{
+		Name:                   "classifier",
+		IsSuccessful:           panickingClassifier,
+		ClassifierPanicOutcome: ClassifierPanicIgnore,
+		StartHalfOpen:          true,
+		Timeout:                time.Minute,
+	})
+
+	// An ignored probe neither closes nor reopens, and frees its slot
+	cb.ExecuteContext(context.Background(), func() (interface{}, error) {
+		return nil, errNotFound
+	})
+	if cb.State() != StateHalfOpen {
+		t.Fatalf("Expected HalfOpen after ignored probe, got %v", cb.State())
+	}
+
+	cb.ExecuteContext(context.Background(), func() (interface{}, error) {
+		return nil, nil
+	})
+	if cb.State() != StateClosed {
+		t.Errorf("Expected next probe to close the circuit, got %v", cb.State())
+	}
+}
+
+func TestClassifierPanicOutcome_String(t *testing.T) {
+	if got := ClassifierPanicOutcome(42).String(); got != stateUnknownStr {
+		t.Errorf("Expected %q for unknown outcome, got %q", stateUnknownStr, got)
+	}
+}
+
+func TestClassifierPanicDefault_HealthyDependencyStaysClosed(t *testing.T) {
+	cb := New(Settings{
+		Name:         "classifier",
+		IsSuccessful: func(error) bool { panic("classifier bug") },
+	})
+
+	for i := 0; i < 20; i++ {
+		cb.Execute(successFunc)
+	}
+	if cb.State() != StateClosed {
+		t.Errorf("Expected a healthy dependency to stay Closed, got %v", cb.State())
+	}
+	if counts := cb.Counts(); counts.TotalSuccesses != 20 {
+		t.Errorf("Expected 20 successes, got %+v", counts)
+	}
+	if got := cb.Metrics().ClassifierPanics; got != 20 {
+		t.Errorf("Expected 20 classifier panics, got %d", got)
+	}
+}
+
+func TestClassifierPanicDefault_FailingCallsStillTrip(t *testing.T) {
+	cb := New(Settings{
+		Name:         "classifier",
+		IsSuccessful: func(error) bool { panic("classifier bug") },
+	})
+
+	for i := 0; i < 6; i++ {
+		cb.Execute(failFunc)
+	}
+	if cb.State() != StateOpen {
+		t.Errorf("Expected genuine failures to trip through the default classification, got %v", cb.State())
+	}
+}
+
+func TestClassifierPanicLatch_StopsCallingClassifier(t *testing.T) {
+	calls := 0
+	cb := New(Settings{
+		Name: "classifier",
+		IsSuccessful: func(error) bool {
+			calls++
+			panic("classifier bug")
+		},
+		ClassifierPanicLatch: 3,
+		ReadyToTrip:          func(Counts) bool { return false },
+	})
+
+	for i := 0; i < 10; i++ {
+		cb.Execute(successFunc)
+	}
+	cb.Execute(failFunc)
+	if calls != 3 {
+		t.Errorf("Expected the classifier called 3 times before latching, got %d", calls)
+	}
+	if !cb.Diagnostics().ClassifierLatched {
+		t.Error("Expected the classifier latched")
+	}
+	if counts := cb.Counts(); counts.TotalSuccesses != 10 || counts.TotalFailures != 1 {
+		t.Errorf("Expected the latched default to classify by err == nil, got %+v", counts)
+	}
+	if got := cb.Metrics().ClassifierPanics; got != 3 {
+		t.Errorf("Expected 3 classifier panics, got %d", got)
+	}
+
+	// Replacing the classifier releases the latch
+	replaced := 0
+	fixed := func(err error) bool {
+		replaced++
+		return err == nil
+	}
+	changes, err := cb.UpdateSettingsDetailed(SettingsUpdate{IsSuccessful: &fixed})
+	if err != nil {
+		t.Fatalf("Expected the classifier update to succeed, got %v", err)
+	}
+	if len(changes.Changes) != 1 || changes.Changes[0].Field != "IsSuccessful" {
+		t.Errorf("Expected the replacement in the ChangeSet, got %+v", changes)
+	}
+	if cb.Diagnostics().ClassifierLatched {
+		t.Error("Expected the latch released by the replacement")
+	}
+	cb.Execute(successFunc)
+	if replaced != 1 {
+		t.Errorf("Expected the replacement called, got %d calls", replaced)
+	}
+}
+
+func TestClassifierPanicLatch_StreakResets(t *testing.T) {
+	cb := New(Settings{
+		Name:                 "classifier",
+		IsSuccessful:         panickingClassifier,
+		ClassifierPanicLatch: 2,
+		ReadyToTrip:          func(Counts) bool { return false },
+	})
+
+	// Panics on every other call: never two in a row
+	for i := 0; i < 10; i++ {
+		cb.Execute(failFunc)
+		cb.Execute(successFunc)
+	}
+	if cb.Diagnostics().ClassifierLatched {
+		t.Error("Expected no latch without consecutive panics")
+	}
+	if got := cb.Metrics().ClassifierPanics; got != 10 {
+		t.Errorf("Expected 10 classifier panics, got %d", got)
+	}
+}
+
+func TestUpdateSettings_IsSuccessfulWithOutcomeWeight(t *testing.T) {
+	cb := New(Settings{Name: "batch", OutcomeWeight: batchWeight})
+
+	fn := DefaultIsSuccessful
+	if err := cb.UpdateSettings(SettingsUpdate{IsSuccessful: &fn}); err == nil {
+		t.Error("Expected replacing IsSuccessful to be rejected with OutcomeWeight")
+	}
+}
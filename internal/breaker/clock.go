@@ -0,0 +1,45 @@
+package breaker
+
+import "time"
+
+// monoBase anchors monoNow. Durations measured from it use the monotonic clock
+// only, which is cheaper to read than the wall clock.
+var monoBase = time.Now()
+
+// monoBaseWall is the wall time of monoBase (UnixNano).
+var monoBaseWall = monoBase.UnixNano()
+
+// monoNow returns monotonic nanoseconds since monoBase.
+func monoNow() int64 {
+	return int64(time.Since(monoBase))
+}
+
+// clock is a source of wall and monotonic time. The system clock is used
+// unless one is set on the breaker (tests use a fake to step the wall clock
+// without moving the monotonic one).
+type clock interface {
+	wallNow() int64 // UnixNano; may jump (NTP steps, VM migration)
+	monoNow() int64 // Nanoseconds since an arbitrary origin; never jumps
+}
+
+// clockAnchor is a clock set on the breaker, with its wall and monotonic
+// readings at the anchor of the breaker's timeline (see now).
+type clockAnchor struct {
+	src  clock
+	wall int64
+	mono int64
+}
+
+// now returns the current time (UnixNano) on the breaker's timeline: the wall
+// time read once, at an anchor, advanced by the monotonic clock since. Every
+// stored timestamp (openedAt, lastClearedAt, stateChangedAt and so on) is on
+// this timeline, so the open Timeout, Interval windows and the other timeout
+// math measure elapsed monotonic time and are immune to wall-clock jumps. A
+// step only shifts reported timestamps (Metrics.StateChangedAt and the like)
+// from the wall clock, by the size of the step.
+func (cb *CircuitBreaker) now() int64 {
+	if cb.clock == nil {
+		return monoBaseWall + monoNow()
+	}
+	return cb.clock.wall + cb.clock.src.monoNow() - cb.clock.mono
+}
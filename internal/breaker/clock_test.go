@@ -0,0 +1,110 @@
+package breaker
+
+import (
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// fakeClock is a clock whose wall and monotonic readings move independently.
+type fakeClock struct {
+	wall atomic.Int64
+	mono atomic.Int64
+}
+
+func newFakeClock() *fakeClock {
+	c := &fakeClock{}
+	c.wall.Store(time.Date(2026, 1, 1, 10, 0, 0, 0, time.UTC).UnixNano())
+	return c
+}
+
+func (c *fakeClock) wallNow() int64 { return c.wall.Load() }
+func (c *fakeClock) monoNow() int64 { return c.mono.Load() }
+
+// advance moves both clocks forward by d, as real time passing does.
+func (c *fakeClock) advance(d time.Duration) {
+	c.wall.Add(int64(d))
+	c.mono.Add(int64(d))
+}
+
+// step jumps the wall clock by d without moving the monotonic clock.
+func (c *fakeClock) step(d time.Duration) {
+	c.wall.Add(int64(d))
+}
+
+// newWithClock creates a breaker on clock c, its timeline anchored at c's
+// current readings.
+func newWithClock(c clock, settings Settings) *CircuitBreaker {
+	cb := New(settings)
+	cb.clock = &clockAnchor{src: c, wall: c.wallNow(), mono: c.monoNow()}
+	now := cb.now()
+	cb.initWindow(now)
+	cb.stateChangedAt.Store(now)
+	return cb
+}
+
+func TestClock_BackwardWallJumpDuringOpen(t *testing.T) {
+	const timeout = 30 * time.Second
+	clk := newFakeClock()
+	cb := newWithClock(clk, tripOnFirstFailure(Settings{Name: "wall-back", Timeout: timeout}))
+
+	cb.Execute(failFunc)
+	if cb.State() != StateOpen {
+		t.Fatalf("Expected Open, got %v", cb.State())
+	}
+
+	// The wall clock steps back an hour: the open period must not stretch
+	clk.advance(10 * time.Second)
+	clk.step(-time.Hour)
+	if got := cb.Diagnostics().TimeUntilHalfOpen; got != 20*time.Second {
+		t.Errorf("Expected 20s until half-open after 10s of monotonic time, got %v", got)
+	}
+	if _, err := cb.Execute(successFunc); err != ErrOpenState {
+		t.Fatalf("Expected rejection before Timeout, got %v", err)
+	}
+
+	// Timeout of monotonic time after opening, the probe is admitted
+	clk.advance(20 * time.Second)
+	if _, err := cb.Execute(successFunc); err != nil {
+		t.Fatalf("Expected the probe admitted at Timeout, got %v", err)
+	}
+	if cb.State() != StateClosed {
+		t.Errorf("Expected Closed after the probe, got %v", cb.State())
+	}
+}
+
+func TestClock_ForwardWallJumpDuringOpen(t *testing.T) {
+	const timeout = 30 * time.Second
+	clk := newFakeClock()
+	cb := newWithClock(clk, tripOnFirstFailure(Settings{Name: "wall-forward", Timeout: timeout}))
+
+	cb.Execute(failFunc)
+
+	// The wall clock steps forward a day: the open period must not end early
+	clk.advance(time.Second)
+	clk.step(24 * time.Hour)
+	if _, err := cb.Execute(successFunc); err != ErrOpenState {
+		t.Fatalf("Expected rejection after 1s of monotonic time, got %v", err)
+	}
+	if cb.State() != StateOpen {
+		t.Errorf("Expected Open, got %v", cb.State())
+	}
+}
+
+func TestClock_WallJumpDoesNotEndInterval(t *testing.T) {
+	clk := newFakeClock()
+	cb := newWithClock(clk, Settings{Name: "wall-interval", Interval: time.Minute})
+
+	cb.Execute(failFunc)
+	clk.step(2 * time.Hour)
+	cb.Execute(successFunc)
+	if got := cb.Counts().Requests; got != 2 {
+		t.Fatalf("Expected the window kept across a wall-clock step, got %d requests", got)
+	}
+
+	clk.advance(time.Minute)
+	cb.Execute(successFunc)
+	if got := cb.Counts().Requests; got != 1 {
+		t.Errorf("Expected the window cleared after Interval of monotonic time, got %d requests", got)
+	}
+}
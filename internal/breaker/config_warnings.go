@@ -0,0 +1,101 @@
+package breaker
+
+import "fmt"
+
+// ConfigWarnings returns descriptions of settings under which the circuit can
+// effectively never trip, or nil if none are found.
+//
+// Unlike ValidateSettings, these are judgement calls about the effective
+// configuration (defaults applied, UpdateSettings changes reflected) and never
+// fail anything: a breaker that cannot trip is harmless, it just protects
+// nothing. Typical finds are MinimumObservations above the traffic an Interval
+// will see, or a FailureRateThreshold so high it can only be exceeded when every
+// request fails.
+//
+// Checks against expected traffic need Settings.ExpectedRequestRate. A custom
+// ReadyToTrip or ReadyToTripEx is opaque and is not checked.
+//
+// Example - configuration review test:
+//
+//	func TestBreakerCanTrip(t *testing.T) {
+//	    cb := autobreaker.New(loadSettings())
+//	    for _, w := range cb.ConfigWarnings() {
+//	        t.Error(w)
+//	    }
+//	}
+func (cb *CircuitBreaker) ConfigWarnings() []string {
+	return configWarnings(cb.CurrentSettings())
+}
+
+// configWarnings implements ConfigWarnings for effective settings s.
+func configWarnings(s Settings) []string {
+	if s.ReadyToTrip != nil || s.ReadyToTripEx != nil {
+		return nil
+	}
+
+	var warnings []string
+	warn := func(format string, args ...interface{}) {
+		warnings = append(warnings, fmt.Sprintf(format, args...))
+	}
+
+	// Requests expected per observation window, 0 if unknown or unbounded
+	var perInterval float64
+	if s.ExpectedRequestRate > 0 && s.Interval > 0 {
+		perInterval = s.ExpectedRequestRate * s.Interval.Seconds()
+	}
+
+	if !s.AdaptiveThreshold {
+		// The static rule trips on more than threshold consecutive failures
+		needed := uint32(5)
+		if s.ConsecutiveFailureThreshold > 0 {
+			needed = s.ConsecutiveFailureThreshold
+		}
+		needed++
+		if perInterval > 0 && perInterval < float64(needed) && !s.PreserveStreaksOnIntervalReset {
+			warn("trip needs %d consecutive failures, but only %.1f requests are expected per Interval %v and each Interval reset clears the streak",
+				needed, perInterval, s.Interval)
+		}
+		return warnings
+	}
+
+	minimum := float64(s.MinimumObservations)
+	if perInterval > 0 && perInterval < minimum {
+		warn("MinimumObservations %d exceeds the %.1f requests expected per Interval %v: adaptive evaluation never activates",
+			s.MinimumObservations, perInterval, s.Interval)
+		return warnings
+	}
+	if s.MinObservationWindow > 0 && s.ExpectedRequestRate > 0 {
+		if recent := s.ExpectedRequestRate * s.MinObservationWindow.Seconds(); recent < minimum {
+			warn("MinimumObservations %d exceeds the %.1f requests expected per MinObservationWindow %v: adaptive evaluation never activates",
+				s.MinimumObservations, recent, s.MinObservationWindow)
+			return warnings
+		}
+	}
+
+	// Exceeding rate t with n requests needs more than t·n failures; once
+	// n·(1-t) <= 1 that means all of them
+	volume := minimum
+	if perInterval > volume {
+		volume = perInterval
+	}
+	if volume*(1-s.FailureRateThreshold) <= 1+defaultRateEpsilon {
+		warn("FailureRateThreshold %.2f can only be exceeded if every request fails (about %.0f requests per evaluation)",
+			s.FailureRateThreshold, volume)
+	}
+
+	return warnings
+}
+
+// reportConfigWarnings logs each ConfigWarnings entry (Settings.LogConfigWarnings).
+func (cb *CircuitBreaker) reportConfigWarnings() {
+	warnings := cb.ConfigWarnings()
+	if len(warnings) == 0 {
+		return
+	}
+
+	logMutex.Lock()
+	defer logMutex.Unlock()
+	for _, w := range warnings {
+		fmt.Printf("[AUTOBREAKER WARNING] Circuit %q: %s\n", cb.name, w)
+	}
+}
@@ -0,0 +1,170 @@
+package breaker
+
+import (
+	"io"
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+// captureStdout returns what f prints to stdout.
+func captureStdout(t *testing.T, f func()) string {
+	t.Helper()
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatalf("os.Pipe: %v", err)
+	}
+	stdout := os.Stdout
+	os.Stdout = w
+	defer func() { os.Stdout = stdout }()
+
+	f()
+	w.Close()
+	out, _ := io.ReadAll(r)
+	return string(out)
+}
+
+func TestConfigWarnings_NeverTrips(t *testing.T) {
+	tests := []struct {
+		name     string
+		settings Settings
+		want     string // Substring of the single expected warning
+	}{
+		{"MinimumObservations above traffic per Interval", Settings{
+			AdaptiveThreshold:   true,
+			MinimumObservations: 1000,
+			Interval:            10 * time.Second,
+			ExpectedRequestRate: 5, // 50 per Interval
+		}, "MinimumObservations 1000 exceeds the 50.0 requests expected per Interval 10s"},
+		{"MinimumObservations above traffic per MinObservationWindow", Settings{
+			AdaptiveThreshold:    true,
+			MinimumObservations:  100,
+			MinObservationWindow: 5 * time.Second,
+			ExpectedRequestRate:  2, // 10 per window
+		}, "per MinObservationWindow 5s"},
+		{"FailureRateThreshold 0.99 with tiny volume", Settings{
+			AdaptiveThreshold:    true,
+			FailureRateThreshold: 0.99,
+			MinimumObservations:  20,
+		}, "FailureRateThreshold 0.99 can only be exceeded if every request fails"},
+		{"FailureRateThreshold exactly at all-but-one", Settings{
+			AdaptiveThreshold:    true,
+			FailureRateThreshold: 0.95,
+			MinimumObservations:  20,
+		}, "can only be exceeded if every request fails"},
+		{"FailureRateThreshold 0.99 with expected traffic per Interval", Settings{
+			AdaptiveThreshold:    true,
+			FailureRateThreshold: 0.99,
+			Interval:             10 * time.Second,
+			ExpectedRequestRate:  5,
+		}, "about 50 requests per evaluation"},
+		{"consecutive failures above traffic per Interval", Settings{
+			ConsecutiveFailureThreshold: 10,
+			Interval:                    time.Second,
+			ExpectedRequestRate:         5,
+		}, "trip needs 11 consecutive failures, but only 5.0 requests are expected per Interval 1s"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.settings.Name = "dead-config"
+			got := New(tt.settings).ConfigWarnings()
+			if len(got) != 1 || !strings.Contains(got[0], tt.want) {
+				t.Errorf("ConfigWarnings() = %q, want one warning containing %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestConfigWarnings_SaneConfigs(t *testing.T) {
+	tests := []struct {
+		name     string
+		settings Settings
+	}{
+		{"defaults", Settings{}},
+		{"adaptive defaults", Settings{AdaptiveThreshold: true}},
+		{"adaptive with enough traffic", Settings{
+			AdaptiveThreshold:    true,
+			FailureRateThreshold: 0.1,
+			MinimumObservations:  50,
+			Interval:             10 * time.Second,
+			ExpectedRequestRate:  100,
+		}},
+		{"high threshold with high traffic", Settings{
+			AdaptiveThreshold:    true,
+			FailureRateThreshold: 0.99,
+			Interval:             time.Minute,
+			ExpectedRequestRate:  100, // 6000 per Interval
+		}},
+		{"static with preserved streaks", Settings{
+			ConsecutiveFailureThreshold:    10,
+			Interval:                       time.Second,
+			PreserveStreaksOnIntervalReset: true,
+			ExpectedRequestRate:            5,
+		}},
+		{"custom ReadyToTrip is not checked", Settings{
+			AdaptiveThreshold:    true,
+			FailureRateThreshold: 0.99,
+			ReadyToTrip:          func(Counts) bool { return false },
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.settings.Name = "sane-config"
+			if got := New(tt.settings).ConfigWarnings(); got != nil {
+				t.Errorf("Expected no warnings, got %q", got)
+			}
+		})
+	}
+}
+
+func TestConfigWarnings_ReflectsUpdateSettings(t *testing.T) {
+	cb := New(Settings{
+		Name:                "updated-config",
+		AdaptiveThreshold:   true,
+		Interval:            10 * time.Second,
+		ExpectedRequestRate: 10,
+	})
+	if got := cb.ConfigWarnings(); got != nil {
+		t.Fatalf("Expected no warnings before the update, got %q", got)
+	}
+
+	minimum := uint32(500)
+	if err := cb.UpdateSettings(SettingsUpdate{MinimumObservations: &minimum}); err != nil {
+		t.Fatalf("UpdateSettings: %v", err)
+	}
+	if got := cb.ConfigWarnings(); len(got) != 1 {
+		t.Errorf("Expected a warning after raising MinimumObservations, got %q", got)
+	}
+}
+
+func TestConfigWarnings_Logged(t *testing.T) {
+	settings := Settings{
+		Name:                 "logged-config",
+		AdaptiveThreshold:    true,
+		FailureRateThreshold: 0.99,
+		LogConfigWarnings:    true,
+	}
+
+	var cb *CircuitBreaker
+	out := captureStdout(t, func() { cb = New(settings) })
+	if !strings.Contains(out, `[AUTOBREAKER WARNING] Circuit "logged-config": FailureRateThreshold 0.99`) {
+		t.Errorf("Expected the warning logged by New, got %q", out)
+	}
+
+	threshold := 0.98
+	out = captureStdout(t, func() {
+		cb.UpdateSettings(SettingsUpdate{FailureRateThreshold: &threshold})
+	})
+	if !strings.Contains(out, "FailureRateThreshold 0.98") {
+		t.Errorf("Expected the warning logged by UpdateSettings, got %q", out)
+	}
+
+	// Off by default
+	settings.LogConfigWarnings = false
+	if out := captureStdout(t, func() { New(settings) }); out != "" {
+		t.Errorf("Expected nothing logged without LogConfigWarnings, got %q", out)
+	}
+}
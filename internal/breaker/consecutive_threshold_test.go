@@ -0,0 +1,85 @@
+package breaker
+
+import (
+	"testing"
+	"time"
+)
+
+func TestConsecutiveFailureThreshold_TripsAfterThreshold(t *testing.T) {
+	cb := New(Settings{
+		Name:                        "consecutive",
+		Timeout:                     time.Minute,
+		ConsecutiveFailureThreshold: 3,
+	})
+
+	for i := 0; i < 3; i++ {
+		cb.Execute(failFunc)
+	}
+	if cb.State() != StateClosed {
+		t.Fatalf("Expected Closed after 3 failures, got %v", cb.State())
+	}
+
+	cb.Execute(failFunc)
+	if cb.State() != StateOpen {
+		t.Fatalf("Expected Open on the 4th consecutive failure, got %v", cb.State())
+	}
+	if reason := cb.Diagnostics().OpenReason; reason.Kind != OpenReasonConsecutiveFailures || reason.Detail != "4 consecutive failures" {
+		t.Errorf("Expected consecutive failures reason, got %+v", reason)
+	}
+}
+
+func TestConsecutiveFailureThreshold_SuccessResetsStreak(t *testing.T) {
+	cb := New(Settings{Name: "consecutive", ConsecutiveFailureThreshold: 2})
+
+	cb.Execute(failFunc)
+	cb.Execute(failFunc)
+	cb.Execute(successFunc)
+	cb.Execute(failFunc)
+	cb.Execute(failFunc)
+	if cb.State() != StateClosed {
+		t.Errorf("Expected a success to restart the streak, got %v", cb.State())
+	}
+}
+
+func TestConsecutiveFailureThreshold_Shadowed(t *testing.T) {
+	tests := []struct {
+		name     string
+		settings Settings
+		failures int
+		want     State
+	}{
+		{"default", Settings{}, 6, StateOpen},
+		{"default not yet", Settings{}, 5, StateClosed},
+		{"custom ReadyToTrip wins", Settings{
+			ConsecutiveFailureThreshold: 1,
+			ReadyToTrip:                 func(Counts) bool { return false },
+		}, 5, StateClosed},
+		{"adaptive wins", Settings{
+			ConsecutiveFailureThreshold: 1,
+			AdaptiveThreshold:           true,
+			MinimumObservations:         10,
+		}, 5, StateClosed},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cb := New(tt.settings)
+			for i := 0; i < tt.failures; i++ {
+				cb.Execute(failFunc)
+			}
+			if cb.State() != tt.want {
+				t.Errorf("Expected %v after %d failures, got %v", tt.want, tt.failures, cb.State())
+			}
+		})
+	}
+}
+
+func TestConsecutiveFailureThreshold_CurrentSettings(t *testing.T) {
+	cb := New(Settings{Name: "consecutive", ConsecutiveFailureThreshold: 3})
+
+	s := cb.CurrentSettings()
+	if s.ConsecutiveFailureThreshold != 3 || s.ReadyToTrip != nil {
+		t.Errorf("Expected threshold reported without a custom ReadyToTrip, got %d, %v",
+			s.ConsecutiveFailureThreshold, s.ReadyToTrip != nil)
+	}
+}
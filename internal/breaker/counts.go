@@ -1,38 +1,183 @@
 package breaker
 
 import (
+	"sync/atomic"
 	"time"
 )
 
 // maybeResetCounts clears counts if interval has elapsed (Closed state only).
 func (cb *CircuitBreaker) maybeResetCounts() {
-	now := time.Now().UnixNano()
+	cb.maybeResetCountsAt(cb.now())
+}
+
+// maybeResetCountsAt is maybeResetCounts with the current time (UnixNano) supplied.
+func (cb *CircuitBreaker) maybeResetCountsAt(now int64) {
+	// Read the epoch first: a transition after this point makes the clear stale
+	epoch := cb.transitionEpoch()
 	last := cb.lastClearedAt.Load()
 
-	// Use monotonic clock for duration calculation to prevent issues from time jumps
-	lastTime := time.Unix(0, last)
-	elapsed := time.Since(lastTime)
+	// Derive elapsed from the single clock read: lastClearedAt is on the same
+	// monotonic timeline (see now), so a wall-clock step cannot end the window
+	elapsed := time.Duration(now - last)
 	if elapsed >= cb.getInterval() {
 		// Try to claim clearing responsibility
-		if cb.lastClearedAt.CompareAndSwap(last, now) {
+		if cb.lastClearedAt.CompareAndSwap(last, cb.windowStart(now)) {
 			// We won the race, clear counts
-			cb.clearCounts()
+			cb.clearIntervalCounts(epoch)
+		}
+	}
+}
+
+// clearIntervalCounts clears the counts at an Interval boundary claimed during
+// transition epoch. It does nothing if a state transition has happened since:
+// the transition cleared the counts itself, and any counted since belong to the
+// new episode.
+//
+// The clear is many stores and cannot be atomic with the epoch check, so any
+// number of transitions can commit in between: a trip, ForceOpen, MirrorFrom or
+// Promote out of this episode, then ForceClose or ForceHalfOpen from StuckOpen,
+// IntervalResetsOpenState or a probe. The epoch is therefore checked again after
+// the clear, and the window is only closed (recovery credited, generation
+// advanced) if it has not moved. If it has, the clear may have discarded the
+// first outcomes of the new episode's window, as a boundary a moment later
+// would have; HalfOpen probe decisions use their own counters and are
+// unaffected. The generation is advanced by compare-and-swap against the value
+// read with the epoch, so calls admitted after a transition that commits past
+// the re-check are not marked late by this clear.
+func (cb *CircuitBreaker) clearIntervalCounts(epoch uint64) {
+	gen := cb.generation.Load()
+	if cb.transitionEpoch() != epoch {
+		return // Stale: a transition started a new episode
+	}
+
+	recovering := cb.recoveryWindowsLeft.Load() > 0
+	var counts Counts
+	if recovering {
+		counts = cb.Counts()
+	}
+	if cb.preserveStreaks {
+		cb.clearWindowedCounts()
+	} else {
+		cb.clearCounts()
+	}
+
+	// Close the window unless a transition committed during the clear and owns
+	// the new one
+	if cb.transitionEpoch() == epoch {
+		if recovering {
+			cb.endRecoveryWindow(counts)
 		}
+		cb.generation.CompareAndSwap(gen, gen+1)
+	}
+	// Any window begun at a boundary is a full one
+	cb.partialWindow.Store(false)
+}
+
+// The state word packs the State into its low stateBits bits and the
+// transition epoch above them. The top bit of the low byte is the Disable()
+// flag, so State() costs a single load.
+const (
+	stateBits   = 8
+	disabledBit = 1 << (stateBits - 1)
+	stateMask   = disabledBit - 1
+)
+
+// transitionEpoch returns the number of state transitions committed so far.
+func (cb *CircuitBreaker) transitionEpoch() uint64 {
+	return cb.state.Load() >> stateBits
+}
+
+// commitTransition attempts the from → to state transition and, if it wins,
+// advances the transition epoch, invalidating interval clears claimed before it.
+// Returns the new epoch, or false if another goroutine already transitioned.
+//
+// The state and the epoch share one atomic word, so a single CAS commits the
+// transition and numbers it: epochs follow commit order without a lock (see
+// OrderStateChanges). A failed CAS means another transition committed first,
+// unless only the disabled flag changed, in which case it is retried.
+func (cb *CircuitBreaker) commitTransition(from, to State) (uint64, bool) {
+	word := cb.state.Load()
+	if State(word&stateMask) != from {
+		return 0, false
+	}
+
+	epoch := word>>stateBits + 1
+	for !cb.state.CompareAndSwap(word, epoch<<stateBits|word&disabledBit|uint64(to)) {
+		word = cb.state.Load()
+		if word>>stateBits != epoch-1 {
+			return 0, false
+		}
+	}
+	cb.invalidateOpenDeadline()
+	if from == StateHalfOpen {
+		cb.lastProbeKey.Store(nil) // Probe fairness is per HalfOpen episode
+	}
+	cb.generation.Add(1) // Calls in flight belong to the old window
+	return epoch, true
+}
+
+// windowStart returns the start (UnixNano) of the observation window containing
+// now. With AlignIntervalToWallClock the window starts at now truncated to a
+// multiple of Interval since the Unix epoch (integer arithmetic only), so all
+// replicas share boundaries; otherwise it starts at now.
+func (cb *CircuitBreaker) windowStart(now int64) int64 {
+	if !cb.alignInterval {
+		return now
 	}
+	interval := int64(cb.getInterval())
+	if interval <= 0 {
+		return now
+	}
+	return now - now%interval
+}
+
+// initWindow starts the first observation window at construction time (UnixNano).
+// With RequireFullWindow, an aligned window entered part-way through is marked
+// partial until the next boundary.
+func (cb *CircuitBreaker) initWindow(now int64) {
+	start := cb.windowStart(now)
+	cb.lastClearedAt.Store(start)
+	cb.partialWindow.Store(cb.requireFullWindow && start != now)
 }
 
 // clearCounts resets all counters to zero and clears saturation flags.
 func (cb *CircuitBreaker) clearCounts() {
+	cb.clearWindowedCounts()
+	cb.consecutiveSuccesses.Store(0)
+	cb.consecutiveFailures.Store(0)
+}
+
+// clearWindowedCounts resets the windowed totals (Requests, TotalSuccesses,
+// TotalFailures, FailureWeight, Demand) and saturation flags, leaving the consecutive
+// streaks untouched.
+func (cb *CircuitBreaker) clearWindowedCounts() {
 	cb.requests.Store(0)
 	cb.totalSuccesses.Store(0)
 	cb.totalFailures.Store(0)
-	cb.consecutiveSuccesses.Store(0)
-	cb.consecutiveFailures.Store(0)
+	cb.failureWeight.Store(0)
+	cb.unserved.Store(0)
+	cb.panics.Store(0)
+	cb.timeoutFailures.Store(0)
+	clearFlag(&cb.panicThresholdFired)
 
 	// Reset saturation flags so warnings can be logged again after counts are cleared
-	cb.requestsSaturated.Store(false)
-	cb.totalSuccessesSaturated.Store(false)
-	cb.totalFailuresSaturated.Store(false)
+	clearFlag(&cb.requestsSaturated)
+	clearFlag(&cb.totalSuccessesSaturated)
+	clearFlag(&cb.totalFailuresSaturated)
+	clearFlag(&cb.unservedSaturated)
+
+	// Rate is undefined with zero counts, so leave the warn band and re-arm the warning
+	clearFlag(&cb.degraded)
+	clearFlag(&cb.warningLatched)
+	clearFlag(&cb.shadowTripped)
+}
+
+// clearFlag clears f, skipping the store (a locked instruction) when f is
+// already clear, as these rarely set flags usually are.
+func clearFlag(f *atomic.Bool) {
+	if f.Load() {
+		f.Store(false)
+	}
 }
 
 // recordOutcome updates counts based on request outcome.
@@ -46,18 +191,43 @@ func (cb *CircuitBreaker) clearCounts() {
 // - Statistics (failure rate) become inaccurate after saturation
 // - The circuit breaker continues functioning for protection
 // - State transitions and interval resets will reset counters to 0
+//
+// With OutcomeWeight set, a whole failure also adds a failure weight of 1.
 func (cb *CircuitBreaker) recordOutcome(success bool) {
+	cb.countOutcome(success)
+
+	if cb.outcomeWeight != nil && !success {
+		cb.addFailureWeight(1)
+	}
+	if cb.healthScoreAlpha > 0 {
+		cb.updateHealthScore(1 - overrideWeight(success))
+	}
+}
+
+// countOutcome updates the integer counters for a whole success or failure.
+func (cb *CircuitBreaker) countOutcome(success bool) {
+	if cb.baseline != nil {
+		cb.observeBaseline(success, cb.now())
+	}
+	if cb.recent != nil {
+		cb.recent.add(cb.now())
+	}
+
 	if success {
 		// Safe increment with saturation protection for totalSuccesses
 		safeIncrementCounter(&cb.totalSuccesses, &cb.totalSuccessesSaturated, "totalSuccesses", cb.name)
 		// ConsecutiveSuccesses can safely overflow as it resets on failure
 		cb.consecutiveSuccesses.Add(1)
-		cb.consecutiveFailures.Store(0)
+		if cb.consecutiveFailures.Load() != 0 {
+			cb.consecutiveFailures.Store(0)
+		}
 	} else {
 		// Safe increment with saturation protection for totalFailures
 		safeIncrementCounter(&cb.totalFailures, &cb.totalFailuresSaturated, "totalFailures", cb.name)
 		// ConsecutiveFailures can safely overflow as it resets on success
 		cb.consecutiveFailures.Add(1)
-		cb.consecutiveSuccesses.Store(0)
+		if cb.consecutiveSuccesses.Load() != 0 {
+			cb.consecutiveSuccesses.Store(0)
+		}
 	}
 }
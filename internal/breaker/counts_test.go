@@ -37,3 +37,76 @@ func TestIntervalBasedCountClearing(t *testing.T) {
 }
 
 // Test adaptive thresholds work across different traffic levels (core value proposition)
+
+func TestPreserveStreaksOnIntervalReset_StreakCrossesBoundary(t *testing.T) {
+	interval := 100 * time.Millisecond
+	newBreaker := func(preserve bool) *CircuitBreaker {
+		return New(Settings{
+			Name:                           "test",
+			Timeout:                        time.Minute,
+			Interval:                       interval,
+			PreserveStreaksOnIntervalReset: preserve,
+		})
+	}
+	straddle := func(cb *CircuitBreaker) {
+		// Three failures before the boundary, three after: six in a row
+		for i := 0; i < 3; i++ {
+			cb.Execute(failFunc)
+		}
+		time.Sleep(interval + 50*time.Millisecond)
+		for i := 0; i < 3; i++ {
+			cb.Execute(failFunc)
+		}
+	}
+
+	// Default: the reset forgets the streak and the static threshold never trips
+	cb := newBreaker(false)
+	straddle(cb)
+	if cb.State() != StateClosed {
+		t.Fatalf("Expected reset to defeat the static threshold by default, got %v", cb.State())
+	}
+	if got := cb.Counts().ConsecutiveFailures; got != 3 {
+		t.Errorf("Expected streak of 3 after default reset, got %d", got)
+	}
+
+	cb = newBreaker(true)
+	straddle(cb)
+	if cb.State() != StateOpen {
+		t.Errorf("Expected preserved streak of 6 to trip the static threshold, got %v", cb.State())
+	}
+	if reason := cb.Diagnostics().OpenReason; reason.Counts.ConsecutiveFailures != 6 {
+		t.Errorf("Expected trip on 6 consecutive failures, got %d", reason.Counts.ConsecutiveFailures)
+	}
+}
+
+func TestPreserveStreaksOnIntervalReset_TotalsStillReset(t *testing.T) {
+	cb := New(Settings{
+		Name:                           "test",
+		Interval:                       100 * time.Millisecond,
+		PreserveStreaksOnIntervalReset: true,
+	})
+
+	cb.Execute(successFunc)
+	cb.Execute(successFunc)
+	time.Sleep(150 * time.Millisecond)
+	cb.Execute(successFunc)
+
+	counts := cb.Counts()
+	if counts.Requests != 1 || counts.TotalSuccesses != 1 {
+		t.Errorf("Expected windowed totals reset to the new request, got %+v", counts)
+	}
+	if counts.ConsecutiveSuccesses != 3 {
+		t.Errorf("Expected success streak of 3 preserved, got %d", counts.ConsecutiveSuccesses)
+	}
+
+	// State transitions still clear the streaks
+	for i := 0; i < 6; i++ {
+		cb.Execute(failFunc)
+	}
+	if cb.State() != StateOpen {
+		t.Fatalf("Expected open, got %v", cb.State())
+	}
+	if counts := cb.Counts(); counts.ConsecutiveFailures != 0 {
+		t.Errorf("Expected streak cleared on transition, got %d", counts.ConsecutiveFailures)
+	}
+}
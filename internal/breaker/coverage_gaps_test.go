@@ -233,7 +233,7 @@ func TestAdaptiveThresholdBoundary(t *testing.T) {
 func TestStateStringForInvalidStates(t *testing.T) {
 	invalidStates := []State{
 		State(-1),
-		State(3),
+		State(4),
 		State(999),
 	}
 
@@ -0,0 +1,136 @@
+package breaker
+
+// CurrentSettings returns the effective configuration of the circuit breaker as a
+// Settings value.
+//
+// Unlike the Settings passed to New(), the snapshot includes the defaults that were
+// applied (MaxRequests=1, Timeout=60s, IsSuccessful=DefaultIsSuccessful, and in
+// adaptive mode FailureRateThreshold=0.05 and MinimumObservations=20) and reflects
+// any runtime changes made via UpdateSettings().
+//
+// Callbacks are returned by reference, so the snapshot can be passed to New() to
+// create a breaker with the same behavior. ReadyToTrip is nil unless a custom one was
+// configured, because the default is derived from AdaptiveThreshold (and the adaptive
+// default is bound to this breaker's own settings). Likewise IsSuccessful is nil when
+// OutcomeWeight is set, since it is never called.
+//
+// Best-effort snapshot: updateable fields are read one at a time, so a concurrent
+// UpdateSettings() may be partially reflected. Use Diagnostics() for runtime state.
+//
+// Example:
+//
+//	replica := autobreaker.New(breaker.CurrentSettings())
+func (cb *CircuitBreaker) CurrentSettings() Settings {
+	var readyToTrip func(Counts) bool
+	if cb.customReadyToTrip && cb.readyToTripEx == nil {
+		readyToTrip = cb.readyToTrip
+	}
+
+	var engine DecisionEngine
+	if cb.customEngine {
+		engine = cb.engine
+	}
+
+	isSuccessful := *cb.isSuccessful.Load()
+	if cb.outcomeWeight != nil {
+		isSuccessful = nil
+	}
+
+	window, bucket, minRec, maxRec := cb.baselineSettings()
+	adaptiveTimeout, minTimeout, maxTimeout := cb.adaptiveTimeoutSettings()
+	maxOpen, onStuckOpen, stuckOpenAction := cb.stuckOpenSettings()
+	cacheTTL, cacheLastSuccess, staleOnBusy := cb.cacheSettings()
+	autoTune, autoTuneInterval, onAutoTune := cb.autoTuneSettings()
+
+	return Settings{
+		Name:                           cb.name,
+		Labels:                         cb.Labels(),
+		MaxRequests:                    cb.getMaxRequests(),
+		HalfOpenMaxProbes:              cb.halfOpenMaxProbes,
+		RequireAllSuccesses:            cb.requireAllSuccesses,
+		HalfOpenProbeTimeout:           cb.halfOpenProbeTimeout(),
+		ProbeRejectionDetails:          cb.probeRejectionDetails,
+		EligibleProbeWait:              cb.eligibleProbeWait,
+		RejectIneligibleAsOpen:         cb.rejectIneligibleAsOpen,
+		TransitionLoserBehavior:        cb.transitionLoserBehavior,
+		StartHalfOpen:                  cb.startHalfOpen,
+		Interval:                       cb.getInterval(),
+		AlignIntervalToWallClock:       cb.alignInterval,
+		RequireFullWindow:              cb.requireFullWindow,
+		PreserveStreaksOnIntervalReset: cb.preserveStreaks,
+		IntervalResetsOpenState:        cb.intervalResetsOpen,
+		Timeout:                        cb.getTimeout(),
+		AdaptiveTimeout:                adaptiveTimeout,
+		MinTimeout:                     minTimeout,
+		MaxTimeout:                     maxTimeout,
+		ReadyToTrip:                    readyToTrip,
+		ReadyToTripEx:                  cb.readyToTripEx,
+		ShadowReadyToTrip:              cb.shadowReadyToTrip,
+		Engine:                         engine,
+		ConsecutiveFailureThreshold:    cb.consecutiveThreshold,
+		OnStateChange:                  cb.onStateChange,
+		OnStateChangeDetailed:          cb.onStateChangeDetailed,
+		OrderStateChanges:              cb.orderStateChanges,
+		OnDisabledChange:               cb.onDisabledChange,
+		IsSuccessful:                   isSuccessful,
+		OutcomeWeight:                  cb.outcomeWeight,
+		CountNestedRejections:          cb.countNestedRejections,
+		IsStreamFailure:                cb.streamFailure,
+		StreamTimeout:                  cb.streamTimeout,
+		ProbeFairnessWait:              cb.probeFairnessWait,
+		ProbeGate:                      cb.probeGate,
+		AdaptiveThreshold:              cb.adaptiveThreshold,
+		FailureRateThreshold:           cb.getFailureRateThreshold(),
+		MinimumObservations:            cb.getMinimumObservations(),
+		MinObservationWindow:           cb.minObservationWindow,
+		RecoverFailureRate:             cb.getRecoverFailureRate(),
+		RecoveryWindows:                cb.recoveryWindows,
+		RateEpsilon:                    cb.rateEpsilon,
+		Baseline:                       cb.relativeBaseline,
+		RelativeFailureRateMultiplier:  cb.relativeMultiplier,
+		ErrorBudget:                    cb.errorBudget,
+		BurnRateThreshold:              cb.burnRateThreshold,
+		PredictiveReject:               cb.predictiveReject,
+		TrackLatency:                   cb.trackLatency,
+		SlowCallFactor:                 cb.slowCallFactor,
+		EpisodeHistory:                 cb.episodeHistory(),
+		WarnFailureRate:                cb.warnFailureRate,
+		OnDegraded:                     cb.onDegraded,
+		WarningThresholdFraction:       cb.getWarningThresholdFraction(),
+		OnWarning:                      cb.onWarning,
+		PanicThreshold:                 cb.panicThreshold,
+		OnPanicThreshold:               cb.onPanicThreshold,
+		MaxOpenDuration:                maxOpen,
+		OnStuckOpen:                    onStuckOpen,
+		StuckOpenAction:                stuckOpenAction,
+		ClassifierPanicOutcome:         cb.classifierPanicOutcome,
+		ClassifierPanicLatch:           cb.classifierPanicLatch,
+		RecoverPanics:                  cb.recoverPanics,
+		IsProbeSuccessful:              cb.isProbeSuccessful,
+		MaxRequestsPerCycle:            cb.maxRequestsPerCycle,
+		MinClosedDuration:              cb.minClosedDuration(),
+		MaxConcurrent:                  cb.bulkhead.size(),
+		MaxConcurrentWait:              cb.bulkhead.maxWait(),
+		CacheTTL:                       cacheTTL,
+		CacheLastSuccess:               cacheLastSuccess,
+		ServeStaleOnTooManyRequests:    staleOnBusy,
+		DiagnosticsCacheTTL:            cb.diagnosticsCacheTTL,
+		MetricsHistoryInterval:         cb.historyInterval,
+		RecommendationWindow:           window,
+		RecommendationBucket:           bucket,
+		RecommendationMinThreshold:     minRec,
+		RecommendationMaxThreshold:     maxRec,
+		AutoTune:                       autoTune,
+		AutoTuneInterval:               autoTuneInterval,
+		OnAutoTune:                     onAutoTune,
+		MetricsHistoryRetention:        cb.historyRetention(),
+		JournalSize:                    cb.journalSize(),
+		JournalErrorLength:             cb.journalErrorLength(),
+		LogTripJournal:                 cb.logTripJournal,
+		HealthScoreAlpha:               cb.healthScoreAlpha,
+		PprofLabels:                    cb.pprofLabels,
+		Strict:                         cb.strict,
+		ExpectedRequestRate:            cb.expectedRequestRate,
+		LogConfigWarnings:              cb.logConfigWarnings,
+	}
+}
@@ -0,0 +1,91 @@
+package breaker
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestCurrentSettings_ReflectsDefaults(t *testing.T) {
+	cb := New(Settings{Name: "test"})
+
+	s := cb.CurrentSettings()
+	if s.Name != "test" {
+		t.Errorf("Expected name 'test', got %q", s.Name)
+	}
+	if s.MaxRequests != 1 {
+		t.Errorf("Expected default MaxRequests=1, got %d", s.MaxRequests)
+	}
+	if s.Timeout != 60*time.Second {
+		t.Errorf("Expected default Timeout=60s, got %v", s.Timeout)
+	}
+	if s.Interval != 0 {
+		t.Errorf("Expected Interval=0, got %v", s.Interval)
+	}
+	if s.RateEpsilon != defaultRateEpsilon {
+		t.Errorf("Expected default RateEpsilon=%v, got %v", defaultRateEpsilon, s.RateEpsilon)
+	}
+	if s.IsSuccessful == nil || s.IsSuccessful(errors.New("x")) {
+		t.Error("Expected IsSuccessful to be the default classifier")
+	}
+	if s.ReadyToTrip != nil {
+		t.Error("Expected nil ReadyToTrip when none was configured")
+	}
+}
+
+func TestCurrentSettings_AdaptiveDefaults(t *testing.T) {
+	cb := New(Settings{Name: "test", AdaptiveThreshold: true})
+
+	s := cb.CurrentSettings()
+	if !s.AdaptiveThreshold {
+		t.Error("Expected AdaptiveThreshold=true")
+	}
+	if s.FailureRateThreshold != 0.05 {
+		t.Errorf("Expected default FailureRateThreshold=0.05, got %v", s.FailureRateThreshold)
+	}
+	if s.MinimumObservations != 20 {
+		t.Errorf("Expected default MinimumObservations=20, got %d", s.MinimumObservations)
+	}
+
+	// The snapshot recreates an equivalent breaker
+	replica := New(s)
+	if d := replica.Diagnostics(); d.FailureRateThreshold != 0.05 || d.MinimumObservations != 20 {
+		t.Errorf("Replica configuration differs: %+v", d)
+	}
+}
+
+func TestCurrentSettings_ReflectsRuntimeUpdates(t *testing.T) {
+	cb := New(Settings{Name: "test", AdaptiveThreshold: true})
+
+	if err := cb.UpdateSettings(SettingsUpdate{
+		MaxRequests:          Uint32Ptr(5),
+		Timeout:              DurationPtr(10 * time.Second),
+		FailureRateThreshold: Float64Ptr(0.2),
+	}); err != nil {
+		t.Fatalf("UpdateSettings failed: %v", err)
+	}
+
+	s := cb.CurrentSettings()
+	if s.MaxRequests != 5 || s.Timeout != 10*time.Second || s.FailureRateThreshold != 0.2 {
+		t.Errorf("Expected updated values, got MaxRequests=%d Timeout=%v FailureRateThreshold=%v",
+			s.MaxRequests, s.Timeout, s.FailureRateThreshold)
+	}
+}
+
+func TestCurrentSettings_CallbacksByReference(t *testing.T) {
+	var stateChanges int
+	cb := New(Settings{
+		Name:          "test",
+		ReadyToTrip:   func(c Counts) bool { return c.ConsecutiveFailures >= 2 },
+		OnStateChange: func(string, State, State) { stateChanges++ },
+	})
+
+	s := cb.CurrentSettings()
+	if s.ReadyToTrip == nil || !s.ReadyToTrip(Counts{ConsecutiveFailures: 2}) {
+		t.Error("Expected custom ReadyToTrip to be returned")
+	}
+	s.OnStateChange("test", StateClosed, StateOpen)
+	if stateChanges != 1 {
+		t.Errorf("Expected OnStateChange to be the configured callback, got %d calls", stateChanges)
+	}
+}
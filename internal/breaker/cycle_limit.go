@@ -0,0 +1,24 @@
+package breaker
+
+import "fmt"
+
+// admitInCycle reserves one request from the closed period's MaxRequestsPerCycle
+// allowance. Once the allowance is used up it opens the circuit and returns false.
+func (cb *CircuitBreaker) admitInCycle() bool {
+	if cb.maxRequestsPerCycle == 0 {
+		return true
+	}
+
+	if cb.cycleAdmitted.Add(1) <= cb.maxRequestsPerCycle {
+		return true
+	}
+	// Undo so the counter can't wrap while concurrent callers are rejected
+	cb.cycleAdmitted.Add(^uint32(0))
+
+	cb.openFromClosed(&OpenReason{
+		Kind:   OpenReasonCycleLimit,
+		Detail: fmt.Sprintf("cycle limit of %d requests reached", cb.maxRequestsPerCycle),
+		Counts: cb.Counts(),
+	})
+	return false
+}
@@ -0,0 +1,133 @@
+package breaker
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestMaxRequestsPerCycle_OpensAtCap(t *testing.T) {
+	cb := New(Settings{
+		Name:                "shed",
+		Timeout:             time.Minute,
+		MaxRequestsPerCycle: 5,
+	})
+
+	for i := 0; i < 5; i++ {
+		if _, err := cb.Execute(successFunc); err != nil {
+			t.Fatalf("Request %d: expected admission, got %v", i+1, err)
+		}
+	}
+	if cb.State() != StateClosed {
+		t.Fatalf("Expected Closed until the cap is exceeded, got %v", cb.State())
+	}
+
+	if _, err := cb.Execute(successFunc); !errors.Is(err, ErrOpenState) {
+		t.Errorf("Expected ErrOpenState past the cap, got %v", err)
+	}
+	if cb.State() != StateOpen {
+		t.Fatalf("Expected Open past the cap, got %v", cb.State())
+	}
+
+	reason := cb.Diagnostics().OpenReason
+	if reason.Kind != OpenReasonCycleLimit || reason.Detail != "cycle limit of 5 requests reached" {
+		t.Errorf("Expected cycle limit reason, got %+v", reason)
+	}
+
+	// Stays open until Timeout, like any trip
+	if _, err := cb.ExecuteContext(context.Background(), successFunc); !errors.Is(err, ErrOpenState) {
+		t.Errorf("Expected ErrOpenState while open, got %v", err)
+	}
+}
+
+func TestMaxRequestsPerCycle_NewCycleAfterRecovery(t *testing.T) {
+	cb := New(Settings{
+		Name:                "shed",
+		Timeout:             10 * time.Millisecond,
+		MaxRequestsPerCycle: 2,
+	})
+
+	cb.Execute(successFunc)
+	cb.Execute(successFunc)
+	cb.Execute(successFunc) // opens
+	if cb.State() != StateOpen {
+		t.Fatalf("Expected Open, got %v", cb.State())
+	}
+
+	// Successful probe closes the circuit and starts a fresh allowance
+	time.Sleep(20 * time.Millisecond)
+	if _, err := cb.Execute(successFunc); err != nil {
+		t.Fatalf("Expected probe admitted, got %v", err)
+	}
+	if cb.State() != StateClosed {
+		t.Fatalf("Expected Closed after probe, got %v", cb.State())
+	}
+
+	for i := 0; i < 2; i++ {
+		if _, err := cb.Execute(successFunc); err != nil {
+			t.Fatalf("Request %d of new cycle: expected admission, got %v", i+1, err)
+		}
+	}
+	if _, err := cb.Execute(successFunc); !errors.Is(err, ErrOpenState) {
+		t.Errorf("Expected ErrOpenState past the new cycle's cap, got %v", err)
+	}
+}
+
+func TestMaxRequestsPerCycle_NotClearedByInterval(t *testing.T) {
+	cb := New(Settings{
+		Name:                "shed",
+		Timeout:             time.Minute,
+		Interval:            time.Millisecond,
+		MaxRequestsPerCycle: 3,
+	})
+
+	for i := 0; i < 3; i++ {
+		cb.Execute(failFunc)
+		time.Sleep(2 * time.Millisecond)
+	}
+	if _, err := cb.Execute(successFunc); !errors.Is(err, ErrOpenState) {
+		t.Errorf("Expected the allowance to span interval resets, got %v", err)
+	}
+}
+
+func TestMaxRequestsPerCycle_Concurrent(t *testing.T) {
+	const limit = 50
+	cb := New(Settings{
+		Name:                "shed",
+		Timeout:             time.Minute,
+		MaxRequestsPerCycle: limit,
+	})
+
+	var admitted atomic.Int32
+	var wg sync.WaitGroup
+	for i := 0; i < 200; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := cb.Execute(successFunc); err == nil {
+				admitted.Add(1)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if got := admitted.Load(); got != limit {
+		t.Errorf("Expected exactly %d admitted, got %d", limit, got)
+	}
+	if cb.State() != StateOpen {
+		t.Errorf("Expected Open, got %v", cb.State())
+	}
+}
+
+func TestMaxRequestsPerCycle_Unlimited(t *testing.T) {
+	cb := New(Settings{Name: "shed"})
+
+	for i := 0; i < 100; i++ {
+		if _, err := cb.Execute(successFunc); err != nil {
+			t.Fatalf("Expected no cap by default, got %v", err)
+		}
+	}
+}
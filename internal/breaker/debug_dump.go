@@ -0,0 +1,198 @@
+package breaker
+
+import (
+	"fmt"
+	"math"
+	"time"
+)
+
+// DebugDump returns the raw value of every atomic field of the breaker, keyed
+// by field name, for logging when something looks wrong (a hard-to-reproduce
+// concurrency bug, a circuit that won't recover).
+//
+// UNSTABLE: This is a diagnostic API. Keys and value types mirror the
+// internal implementation and may change in any release without notice; do
+// not parse the dump or build behavior on it. Use Metrics() and Diagnostics()
+// for the supported view.
+//
+// Values are as stored, with little interpretation:
+//   - Counters and flags keep their atomic type (uint32, uint64, int32, bool)
+//   - Timestamps are int64 UnixNano on the breaker's monotonic timeline (see now); 0 means unset
+//   - Durations are time.Duration; floats stored as bits are decoded to float64
+//   - state is the State of the state machine (Disable is reported separately)
+//   - Pointers are dereferenced into a copy or a summary, or nil when unset
+//
+// Each value is loaded separately, so the dump is not a consistent snapshot:
+// concurrent requests may change fields between loads. Subsystems with their
+// own storage (journal, history, latency histogram) are not included.
+//
+// Thread-safe: Takes no locks and never blocks, so it is safe to call from a
+// stuck or contended breaker. It allocates and is not meant for the hot path.
+//
+// Example:
+//
+//	if time.Since(breaker.Metrics().StateChangedAt) > time.Hour && breaker.State() == autobreaker.StateHalfOpen {
+//	    log.Printf("breaker %s stuck half-open: %v", breaker.Name(), breaker.DebugDump())
+//	}
+func (cb *CircuitBreaker) DebugDump() map[string]interface{} {
+	ev := cb.eventTotals()
+	dump := map[string]interface{}{
+		// Runtime-updatable settings
+		"maxRequests":              cb.maxRequests.Load(),
+		"interval":                 time.Duration(cb.interval.Load()),
+		"intervalEnabled":          cb.intervalEnabled.Load(),
+		"timeout":                  time.Duration(cb.timeout.Load()),
+		"failureRateThreshold":     math.Float64frombits(cb.failureRateThreshold.Load()),
+		"minimumObservations":      cb.minimumObservations.Load(),
+		"warningThresholdFraction": math.Float64frombits(cb.warningThresholdFraction.Load()),
+		"recoverFailureRate":       math.Float64frombits(cb.recoverFailureRate.Load()),
+
+		// State and counts
+		"state":                 cb.machineState(),
+		"epoch":                 cb.transitionEpoch(),
+		"plainClosed":           cb.plainClosed.Load(),
+		"generation":            cb.generation.Load(),
+		"requests":              cb.requests.Load(),
+		"totalSuccesses":        cb.totalSuccesses.Load(),
+		"totalFailures":         cb.totalFailures.Load(),
+		"consecutiveSuccesses":  cb.consecutiveSuccesses.Load(),
+		"consecutiveFailures":   cb.consecutiveFailures.Load(),
+		"failureWeight":         cb.getFailureWeight(),
+		"classifierPanicStreak": cb.classifierPanicStreak.Load(),
+		"unserved":              cb.unserved.Load(),
+		"panics":                cb.panics.Load(),
+		"timeoutFailures":       cb.timeoutFailures.Load(),
+
+		// Half-open probing
+		"halfOpenRequests":     cb.halfOpenRequests.Load(),
+		"halfOpenProbes":       cb.halfOpenProbes.Load(),
+		"probeSuccesses":       cb.probeSuccesses.Load(),
+		"probeFailures":        cb.probeFailures.Load(),
+		"probeRejections":      ev.probeRejections.Load(),
+		"probesInFlight":       cb.probesInFlight.Load(),
+		"probeStartedAt":       cb.probeStartedAt.Load(),
+		"probeTimeouts":        ev.probeTimeouts.Load(),
+		"awaitingEligible":     cb.awaitingEligible.Load(),
+		"ineligibleRejections": ev.ineligibleRejections.Load(),
+		"fairnessRejections":   ev.fairnessRejections.Load(),
+		"probeGateRejections":  ev.probeGateRejections.Load(),
+		"probeSuccessesTotal":  cb.probeSuccessesTotal.Load(),
+		"probeFailuresTotal":   cb.probeFailuresTotal.Load(),
+
+		// Cumulative counters
+		"streamTimeouts":     ev.streamTimeouts.Load(),
+		"slowCalls":          ev.slowCalls.Load(),
+		"classifierPanics":   ev.classifierPanics.Load(),
+		"lateOutcomes":       ev.lateOutcomes.Load(),
+		"bypassedCalls":      ev.bypassedCalls.Load(),
+		"syntheticSuccesses": ev.syntheticSuccesses.Load(),
+		"syntheticFailures":  ev.syntheticFailures.Load(),
+		"shadowTrips":        ev.shadowTrips.Load(),
+		"staleServes":        cb.staleServes(),
+		"fallbackServed":     cb.fallbackServedCounts(),
+
+		// Timestamps
+		"openedAt":        cb.openedAt.Load(),
+		"lastClearedAt":   cb.lastClearedAt.Load(),
+		"stateChangedAt":  cb.stateChangedAt.Load(),
+		"retryAfterUntil": cb.retryAfterUntil.Load(),
+		"openUntil":       cb.openUntil.Load(),
+		"openDeadline":    cb.openDeadline.Load(),
+		"openDeadlineGen": cb.openDeadlineGen.Load(),
+		"openHeld":        cb.openHeld.Load(),
+
+		// Flags
+		"requestsSaturated":       cb.requestsSaturated.Load(),
+		"totalSuccessesSaturated": cb.totalSuccessesSaturated.Load(),
+		"totalFailuresSaturated":  cb.totalFailuresSaturated.Load(),
+		"unservedSaturated":       cb.unservedSaturated.Load(),
+		"degraded":                cb.degraded.Load(),
+		"warningLatched":          cb.warningLatched.Load(),
+		"shadowTripped":           cb.shadowTripped.Load(),
+		"maintenance":             cb.maintenance.Load(),
+		"disabled":                cb.Disabled(),
+		"cycleAdmitted":           cb.cycleAdmitted.Load(),
+		"partialWindow":           cb.partialWindow.Load(),
+		"recoveryWindowsLeft":     cb.recoveryWindowsLeft.Load(),
+		"classifierLatched":       cb.classifierLatched.Load(),
+		"panicThresholdFired":     cb.panicThresholdFired.Load(),
+		"unhealthy":               cb.unhealthy.Load(),
+		"healthScore":             math.Float64frombits(cb.healthScore.Load()),
+		"closed":                  cb.closed.Load(),
+	}
+
+	// Pointers: a copy or a summary of what they point to
+	dump["isSuccessful"] = fmt.Sprintf("%p", *cb.isSuccessful.Load()) // Code address, tells replacements apart
+	dump["openReason"] = nil
+	if reason := cb.openReason.Load(); reason != nil {
+		dump["openReason"] = cb.withDetail(*reason)
+	}
+	dump["lastTrip"] = nil
+	if reason := cb.lastTrip.Load(); reason != nil {
+		dump["lastTrip"] = cb.withDetail(*reason)
+	}
+	dump["lastProbeKey"] = nil
+	if key := cb.lastProbeKey.Load(); key != nil {
+		dump["lastProbeKey"] = map[string]interface{}{"key": key.key, "admittedAt": key.admittedAt}
+	}
+	dump["episode"] = nil
+	if rec := cb.episode.Load(); rec != nil {
+		dump["episode"] = map[string]interface{}{
+			"openedAt":      rec.openedAt,
+			"rejected":      rec.rejected.Load(),
+			"probeAttempts": rec.probeAttempts.Load(),
+			"failedProbes":  rec.failedProbes.Load(),
+		}
+	}
+	dump["events"] = cb.events.Load() != nil // Whether allocated; the counters are listed above
+	dump["willTripCache"] = nil
+	if cached := cb.willTripCache.Load(); cached != nil {
+		dump["willTripCache"] = map[string]interface{}{"computedAt": cached.computedAt, "willTripNext": cached.willTripNext}
+	}
+	dump["incident"] = nil
+	if inc := cb.incident; inc != nil {
+		dump["incident"] = map[string]interface{}{"startedAt": inc.startedAt.Load(), "stuckDeadline": inc.stuckDeadline.Load()}
+	}
+	dump["learnedTimeout"] = cb.learnedTimeoutValue()
+	dump["hold"] = nil
+	if h := cb.hold; h != nil {
+		dump["hold"] = map[string]interface{}{"until": h.until.Load(), "deferred": h.deferred.Load()}
+	}
+	dump["bulkhead"] = nil
+	if b := cb.bulkhead; b != nil {
+		dump["bulkhead"] = map[string]interface{}{"waits": b.waits.Load(), "waitNanos": time.Duration(b.waitNanos.Load())}
+	}
+	dump["resultCache"] = nil
+	if cached := cb.lastGoodResult(); cached != nil {
+		dump["resultCache"] = map[string]interface{}{"storedAt": cached.storedAt} // The value itself is not dumped
+	}
+	var dependencies []string
+	if deps := cb.dependencies.Load(); deps != nil {
+		for _, dep := range *deps {
+			dependencies = append(dependencies, dep.name)
+		}
+	}
+	dump["dependencies"] = dependencies
+	dump["mirrorSource"] = nil
+	if source := cb.mirrorSource.Load(); source != nil {
+		dump["mirrorSource"] = source.name
+	}
+	var mirrors []string
+	if current := cb.mirrors.Load(); current != nil {
+		for _, m := range *current {
+			mirrors = append(mirrors, m.name)
+		}
+	}
+	dump["mirrors"] = mirrors
+	var views []string
+	if current := cb.views.Load(); current != nil {
+		for _, p := range *current {
+			if v := p.Value(); v != nil {
+				views = append(views, v.name)
+			}
+		}
+	}
+	dump["views"] = views
+
+	return dump
+}
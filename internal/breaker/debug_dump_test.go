@@ -0,0 +1,100 @@
+package breaker
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestDebugDump_CoversEveryAtomic(t *testing.T) {
+	dump := New(Settings{Name: "dump"}).DebugDump()
+
+	// Every sync/atomic field of CircuitBreaker must be in the dump, so new
+	// fields can't be forgotten
+	typ := reflect.TypeOf(CircuitBreaker{})
+	for i := 0; i < typ.NumField(); i++ {
+		field := typ.Field(i)
+		if field.Type.PkgPath() != "sync/atomic" {
+			continue
+		}
+		if _, ok := dump[field.Name]; !ok {
+			t.Errorf("DebugDump is missing atomic field %s (%v)", field.Name, field.Type)
+		}
+	}
+}
+
+func TestDebugDump_Values(t *testing.T) {
+	cb := New(Settings{
+		Name:        "dump",
+		Timeout:     10 * time.Millisecond,
+		ReadyToTrip: func(counts Counts) bool { return counts.ConsecutiveFailures >= 2 },
+	})
+	cb.Execute(successFunc)
+	cb.Execute(failFunc)
+
+	dump := cb.DebugDump()
+	want := map[string]interface{}{
+		"state":               StateClosed,
+		"requests":            uint32(2),
+		"totalSuccesses":      uint32(1),
+		"totalFailures":       uint32(1),
+		"consecutiveFailures": uint32(1),
+		"halfOpenRequests":    int32(0),
+		"timeout":             10 * time.Millisecond,
+		"maxRequests":         uint32(1),
+		"epoch":               uint64(0),
+		"openedAt":            int64(0),
+		"openReason":          nil,
+		"disabled":            false,
+	}
+	for key, value := range want {
+		if got := dump[key]; got != value {
+			t.Errorf("Closed: %s = %v (%T), want %v (%T)", key, got, got, value, value)
+		}
+	}
+
+	// Trip, then hold the probe slot to catch HalfOpen mid-probe
+	cb.Execute(failFunc)
+	dump = cb.DebugDump()
+	if dump["state"] != StateOpen || dump["epoch"] != uint64(1) {
+		t.Errorf("Open: state %v epoch %v, want open at epoch 1", dump["state"], dump["epoch"])
+	}
+	if openedAt, _ := dump["openedAt"].(int64); openedAt <= 0 || openedAt > time.Now().UnixNano() {
+		t.Errorf("Open: implausible openedAt %v", dump["openedAt"])
+	}
+	if reason, ok := dump["openReason"].(OpenReason); !ok || reason.Kind != OpenReasonReadyToTrip {
+		t.Errorf("Open: openReason = %#v, want a ReadyToTrip reason", dump["openReason"])
+	}
+
+	time.Sleep(20 * time.Millisecond)
+	release := make(chan struct{})
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		cb.Execute(func() (interface{}, error) {
+			<-release
+			return nil, nil
+		})
+	}()
+	requireState(t, cb, StateHalfOpen, time.Second)
+	deadline := time.Now().Add(time.Second)
+	for cb.DebugDump()["halfOpenRequests"] != int32(1) && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+
+	dump = cb.DebugDump()
+	if dump["halfOpenRequests"] != int32(1) || dump["probesInFlight"] != int32(1) {
+		t.Errorf("HalfOpen: halfOpenRequests %v probesInFlight %v, want 1 probe in flight",
+			dump["halfOpenRequests"], dump["probesInFlight"])
+	}
+	if started, _ := dump["probeStartedAt"].(int64); started <= 0 {
+		t.Errorf("HalfOpen: implausible probeStartedAt %v", dump["probeStartedAt"])
+	}
+	close(release)
+	<-done
+
+	if dump := cb.DebugDump(); dump["state"] != StateClosed || dump["halfOpenRequests"] != int32(0) {
+		t.Errorf("Recovered: state %v halfOpenRequests %v, want closed with no probes",
+			dump["state"], dump["halfOpenRequests"])
+	}
+}
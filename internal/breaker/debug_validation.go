@@ -14,13 +14,15 @@ import "fmt"
 //   - Timestamp monotonicity: stateChangedAt should be >= openedAt
 //   - Half-open request counter: should be 0 if not in HalfOpen state
 //   - Count consistency: totals should equal sum of successes + failures
+//   - Streak consistency: consecutive counts should not exceed totals (skipped
+//     with PreserveStreaksOnIntervalReset)
 //
 // Returns nil if all invariants hold, or an error describing the first violation found.
 //
 // Note: This method is not thread-safe. It should only be called when the circuit
 // breaker is not actively processing requests (e.g., in tests or debugging).
 func (cb *CircuitBreaker) validateStateMachine() error {
-	state := cb.State()
+	state := cb.machineState()
 	openedAt := cb.openedAt.Load()
 	stateChangedAt := cb.stateChangedAt.Load()
 	halfOpenRequests := cb.halfOpenRequests.Load()
@@ -48,7 +50,12 @@ func (cb *CircuitBreaker) validateStateMachine() error {
 			counts.Requests, totalCounted)
 	}
 
-	// Validate consecutive counts don't exceed totals
+	// Validate consecutive counts don't exceed totals (preserved streaks outlive
+	// the windowed totals across interval resets)
+	if cb.preserveStreaks {
+		return nil
+	}
+
 	if counts.ConsecutiveSuccesses > counts.TotalSuccesses {
 		return fmt.Errorf("consecutive successes=%v exceeds total successes=%v",
 			counts.ConsecutiveSuccesses, counts.TotalSuccesses)
@@ -0,0 +1,57 @@
+package breaker
+
+import "context"
+
+// defaultRegistry backs the package-level Execute, ExecuteContext, Configure
+// and Default functions.
+var defaultRegistry = NewRegistry()
+
+// DefaultRegistry returns the registry used by the package-level Execute,
+// ExecuteContext, Configure and Default functions, e.g. to Lookup() or
+// Collect() the breakers they created.
+func DefaultRegistry() *Registry {
+	return defaultRegistry
+}
+
+// Default returns the default registry's breaker for name, creating it on first
+// use with adaptive defaults (AdaptiveThreshold enabled, everything else at its
+// default) unless Configure() registered it first.
+//
+// Thread-safe: Concurrent first use of a name creates exactly one breaker.
+func Default(name string) *CircuitBreaker {
+	return defaultRegistry.GetOrCreate(Settings{
+		Name:              name,
+		AdaptiveThreshold: true,
+	})
+}
+
+// Execute runs req through the default registry's breaker for name, creating
+// it on first use (see Default).
+//
+// Intended for scripts and small tools that don't want to construct and pass
+// a breaker around:
+//
+//	result, err := autobreaker.Execute("user-service", func() (interface{}, error) {
+//	    return client.GetUser(id)
+//	})
+func Execute(name string, req func() (interface{}, error)) (interface{}, error) {
+	return Default(name).Execute(req)
+}
+
+// ExecuteContext is the context-aware variant of Execute (see
+// CircuitBreaker.ExecuteContext).
+func ExecuteContext(ctx context.Context, name string, req func() (interface{}, error)) (interface{}, error) {
+	return Default(name).ExecuteContext(ctx, req)
+}
+
+// Configure registers the default registry's breaker for name with settings
+// instead of the adaptive defaults. settings.Name is set to name.
+//
+// Call it before the first Execute, ExecuteContext or Default for that name,
+// typically at startup. Returns an error wrapping ErrAlreadyRegistered if the
+// breaker already exists, or the validation error for invalid settings.
+func Configure(name string, settings Settings) error {
+	settings.Name = name
+	_, err := defaultRegistry.Register(settings)
+	return err
+}
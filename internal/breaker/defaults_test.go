@@ -0,0 +1,79 @@
+package breaker
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestDefault_ConcurrentFirstUse(t *testing.T) {
+	const goroutines = 100
+	results := make([]*CircuitBreaker, goroutines)
+	var wg sync.WaitGroup
+	for i := 0; i < goroutines; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			Execute("defaults-concurrent", successFunc)
+			results[i] = Default("defaults-concurrent")
+		}(i)
+	}
+	wg.Wait()
+
+	for i, cb := range results {
+		if cb != results[0] {
+			t.Fatalf("Goroutine %d got a different breaker", i)
+		}
+	}
+	if counts := results[0].Counts(); counts.Requests != goroutines {
+		t.Errorf("Expected all %d requests on one breaker, got %+v", goroutines, counts)
+	}
+	if !results[0].adaptiveThreshold {
+		t.Error("Expected the default breaker to use adaptive thresholds")
+	}
+	if cb, ok := DefaultRegistry().Lookup("defaults-concurrent"); !ok || cb != results[0] {
+		t.Error("Expected the breaker in the default registry")
+	}
+}
+
+func TestConfigure_BeforeUse(t *testing.T) {
+	if err := Configure("defaults-configured", Settings{Name: "ignored", Timeout: time.Minute}); err != nil {
+		t.Fatalf("Expected Configure to succeed, got %v", err)
+	}
+
+	ExecuteContext(context.Background(), "defaults-configured", successFunc)
+	cb := Default("defaults-configured")
+	if cb.Name() != "defaults-configured" || cb.getTimeout() != time.Minute {
+		t.Errorf("Expected configured settings, got name=%q timeout=%v", cb.Name(), cb.getTimeout())
+	}
+	if cb.adaptiveThreshold {
+		t.Error("Expected configured settings to replace the adaptive defaults")
+	}
+}
+
+func TestConfigure_AfterUse(t *testing.T) {
+	Execute("defaults-used", successFunc)
+
+	err := Configure("defaults-used", Settings{Timeout: 5 * time.Minute})
+	if !errors.Is(err, ErrAlreadyRegistered) {
+		t.Fatalf("Expected ErrAlreadyRegistered, got %v", err)
+	}
+	if got := err.Error(); got != `circuit breaker already registered: "defaults-used"` {
+		t.Errorf("Expected the name in the error, got %q", got)
+	}
+	if Default("defaults-used").getTimeout() == 5*time.Minute {
+		t.Error("Expected the existing breaker unchanged")
+	}
+}
+
+func TestConfigure_InvalidSettings(t *testing.T) {
+	err := Configure("defaults-invalid", Settings{FailureRateThreshold: 2, AdaptiveThreshold: true})
+	if err == nil {
+		t.Fatal("Expected a validation error")
+	}
+	if _, ok := DefaultRegistry().Lookup("defaults-invalid"); ok {
+		t.Error("Expected nothing registered for invalid settings")
+	}
+}
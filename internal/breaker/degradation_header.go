@@ -0,0 +1,113 @@
+package breaker
+
+import (
+	"net/http"
+	"strings"
+)
+
+// DefaultDegradationHeader is the response header set by DegradationHeader.
+const DefaultDegradationHeader = "X-AutoBreaker-Degraded"
+
+// NamedState pairs a breaker's name with its state, as passed to
+// DegradationHeaderOpts.Format.
+type NamedState struct {
+	Name  string
+	State State
+}
+
+// DegradationHeaderOpts are options for DegradationHeaderWithOpts.
+type DegradationHeaderOpts struct {
+	// Header is the response header to set.
+	//
+	// Default: DefaultDegradationHeader
+	Header string
+
+	// Format renders the header value from the breakers that are not Closed,
+	// in the order they were given. An empty result leaves the header unset.
+	//
+	// Default: FormatDegradation ("database=open,cache=half-open")
+	Format func(degraded []NamedState) string
+}
+
+// DegradationHeader returns HTTP middleware that tells callers which of the
+// given dependency breakers are not Closed, by setting the
+// X-AutoBreaker-Degraded response header, e.g.
+// "X-AutoBreaker-Degraded: database=open,cache=half-open". Callers learn that a
+// response may have been produced in degraded mode without polling a health
+// endpoint.
+//
+// The breakers are inspected when the request is admitted, before the wrapped
+// handler runs, so the header is in place before the handler writes the body.
+// A breaker that changes state while the handler runs is reported as it was at
+// admission. A disabled breaker is reported as "disabled". Nothing is set
+// while every breaker is Closed.
+//
+// Per request, this costs one State() call per breaker; nothing is allocated
+// while every breaker is Closed.
+//
+// Thread-safe: The middleware may serve requests concurrently.
+//
+// Example:
+//
+//	mux := http.NewServeMux()
+//	mux.HandleFunc("/orders", handleOrders)
+//	handler := autobreaker.DegradationHeader(dbBreaker, cacheBreaker)(mux)
+//	http.ListenAndServe(":8080", handler)
+func DegradationHeader(breakers ...*CircuitBreaker) func(http.Handler) http.Handler {
+	return DegradationHeaderWithOpts(DegradationHeaderOpts{}, breakers...)
+}
+
+// DegradationHeaderWithOpts is DegradationHeader with a custom header name and
+// value format.
+//
+// Example - Custom Header and Format:
+//
+//	mw := autobreaker.DegradationHeaderWithOpts(autobreaker.DegradationHeaderOpts{
+//	    Header: "X-Dependencies-Degraded",
+//	    Format: func(degraded []autobreaker.NamedState) string {
+//	        return strconv.Itoa(len(degraded))
+//	    },
+//	}, dbBreaker, cacheBreaker)
+func DegradationHeaderWithOpts(opts DegradationHeaderOpts, breakers ...*CircuitBreaker) func(http.Handler) http.Handler {
+	header := opts.Header
+	if header == "" {
+		header = DefaultDegradationHeader
+	}
+	format := opts.Format
+	if format == nil {
+		format = FormatDegradation
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			var degraded []NamedState
+			for _, cb := range breakers {
+				if state := cb.State(); state != StateClosed {
+					degraded = append(degraded, NamedState{Name: cb.Name(), State: state})
+				}
+			}
+			if len(degraded) > 0 {
+				if value := format(degraded); value != "" {
+					w.Header().Set(header, value)
+				}
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// FormatDegradation formats degraded breakers as comma-separated name=state
+// pairs, e.g. "database=open,cache=half-open". It is the default
+// DegradationHeaderOpts.Format.
+func FormatDegradation(degraded []NamedState) string {
+	var b strings.Builder
+	for i, d := range degraded {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		b.WriteString(d.Name)
+		b.WriteByte('=')
+		b.WriteString(d.State.String())
+	}
+	return b.String()
+}
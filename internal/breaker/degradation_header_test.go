@@ -0,0 +1,131 @@
+package breaker
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// degradationTestBreakers returns a Closed, an Open and a HalfOpen breaker.
+func degradationTestBreakers(t *testing.T) (closed, open, halfOpen *CircuitBreaker) {
+	t.Helper()
+	closed = New(Settings{Name: "search"})
+	open = New(tripOnFirstFailure(Settings{Name: "database", Timeout: time.Minute}))
+	open.Execute(failFunc)
+	halfOpen = New(Settings{Name: "cache", StartHalfOpen: true})
+
+	if open.State() != StateOpen || halfOpen.State() != StateHalfOpen {
+		t.Fatalf("Setup: expected open and half-open, got %v and %v", open.State(), halfOpen.State())
+	}
+	return closed, open, halfOpen
+}
+
+// serveDegradation serves one request through mw and returns the response
+// header as the handler started writing the body.
+func serveDegradation(t *testing.T, mw func(http.Handler) http.Handler) http.Header {
+	t.Helper()
+	rec := httptest.NewRecorder()
+	mw(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, "ok") // Headers are flushed with the first write
+	})).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+	return rec.Result().Header
+}
+
+func TestDegradationHeader(t *testing.T) {
+	closed, open, halfOpen := degradationTestBreakers(t)
+
+	tests := []struct {
+		name     string
+		breakers []*CircuitBreaker
+		want     string // "" for no header
+	}{
+		{"all closed", []*CircuitBreaker{closed}, ""},
+		{"no breakers", nil, ""},
+		{"one open", []*CircuitBreaker{closed, open}, "database=open"},
+		{"mixed states in order", []*CircuitBreaker{open, closed, halfOpen}, "database=open,cache=half-open"},
+		{"order follows arguments", []*CircuitBreaker{halfOpen, open}, "cache=half-open,database=open"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			header := serveDegradation(t, DegradationHeader(tt.breakers...))
+			values, present := header[http.CanonicalHeaderKey(DefaultDegradationHeader)]
+			if tt.want == "" {
+				if present {
+					t.Errorf("Expected no header, got %q", values)
+				}
+				return
+			}
+			if got := header.Get(DefaultDegradationHeader); got != tt.want {
+				t.Errorf("Expected header %q, got %q", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestDegradationHeader_Disabled(t *testing.T) {
+	cb := New(Settings{Name: "database"})
+	cb.Disable()
+
+	header := serveDegradation(t, DegradationHeader(cb))
+	if got := header.Get(DefaultDegradationHeader); got != "database=disabled" {
+		t.Errorf("Expected a disabled breaker to be reported, got %q", got)
+	}
+}
+
+func TestDegradationHeaderWithOpts(t *testing.T) {
+	closed, open, halfOpen := degradationTestBreakers(t)
+
+	var formatted []NamedState
+	mw := DegradationHeaderWithOpts(DegradationHeaderOpts{
+		Header: "X-Dependencies",
+		Format: func(degraded []NamedState) string {
+			formatted = degraded
+			names := make([]string, len(degraded))
+			for i, d := range degraded {
+				names[i] = strings.ToUpper(d.Name)
+			}
+			return strings.Join(names, ";")
+		},
+	}, closed, open, halfOpen)
+
+	header := serveDegradation(t, mw)
+	if got := header.Get("X-Dependencies"); got != "DATABASE;CACHE" {
+		t.Errorf("Expected the custom format under the custom header, got %q", got)
+	}
+	if got := header.Get(DefaultDegradationHeader); got != "" {
+		t.Errorf("Expected no default header with a custom one, got %q", got)
+	}
+	want := []NamedState{{"database", StateOpen}, {"cache", StateHalfOpen}}
+	if len(formatted) != len(want) || formatted[0] != want[0] || formatted[1] != want[1] {
+		t.Errorf("Expected Format to receive %v, got %v", want, formatted)
+	}
+
+	// An empty value leaves the header unset
+	empty := DegradationHeaderWithOpts(DegradationHeaderOpts{
+		Format: func([]NamedState) string { return "" },
+	}, open)
+	if _, present := serveDegradation(t, empty)[http.CanonicalHeaderKey(DefaultDegradationHeader)]; present {
+		t.Error("Expected no header for an empty formatted value")
+	}
+}
+
+func TestDegradationHeader_StateAtAdmission(t *testing.T) {
+	cb := New(tripOnFirstFailure(Settings{Name: "database", Timeout: time.Minute}))
+
+	rec := httptest.NewRecorder()
+	DegradationHeader(cb)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		cb.Execute(failFunc) // Trips while the handler runs
+		fmt.Fprint(w, "ok")
+	})).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	if got := rec.Result().Header.Get(DefaultDegradationHeader); got != "" {
+		t.Errorf("Expected the header to reflect the state at admission, got %q", got)
+	}
+	if cb.State() != StateOpen {
+		t.Fatalf("Expected the handler to trip the circuit, got %v", cb.State())
+	}
+}
@@ -0,0 +1,76 @@
+package breaker
+
+// updateRateLatches re-evaluates the rate-driven latches (the warn band, the
+// early warning and health) after an outcome is recorded in Closed state. The
+// counts are loaded once and shared. Only called when watchesRate is set.
+func (cb *CircuitBreaker) updateRateLatches() {
+	if cb.machineState() != StateClosed {
+		return
+	}
+
+	counts := cb.Counts()
+	if counts.Requests == 0 || counts.Requests < cb.getMinimumObservations() {
+		return
+	}
+
+	rate := cb.failureRate(counts)
+	cb.updateDegraded(rate)
+	cb.updateWarning(rate, counts)
+	cb.updateHealth(rate)
+}
+
+// updateDegraded re-evaluates the warn band for the failure rate after an outcome.
+//
+// The breaker is degraded when the failure rate exceeds WarnFailureRate without
+// having tripped. OnDegraded fires once per crossing: the flag latches when the
+// rate enters the warn band and re-arms when the rate falls back to or below
+// WarnFailureRate (or counts are cleared).
+func (cb *CircuitBreaker) updateDegraded(rate float64) {
+	if cb.warnFailureRate <= 0 {
+		return
+	}
+
+	if cb.rateExceeds(rate, cb.warnFailureRate) {
+		if cb.degraded.CompareAndSwap(false, true) {
+			safeCallOnDegraded(cb.name, cb.onDegraded, rate)
+		}
+		return
+	}
+
+	cb.degraded.Store(false)
+}
+
+// warningRearmRatio controls the hysteresis of the warning latch: once latched,
+// the warning re-arms only when the failure rate drops below this fraction of
+// the warning level, so a rate hovering around the boundary doesn't spam OnWarning.
+const warningRearmRatio = 0.9
+
+// updateWarning evaluates the early-warning level for the failure rate of counts
+// after an outcome.
+//
+// The warning level is WarningThresholdFraction × FailureRateThreshold. OnWarning
+// fires when the failure rate rises above the level, then latches until counts
+// are cleared or the rate falls below warningRearmRatio × level. Only active in
+// adaptive mode.
+func (cb *CircuitBreaker) updateWarning(rate float64, counts Counts) {
+	if !cb.adaptiveThreshold {
+		return
+	}
+	fraction := cb.getWarningThresholdFraction()
+	if fraction == 0 {
+		return
+	}
+
+	level := fraction * cb.getFailureRateThreshold()
+
+	if cb.rateExceeds(rate, level) {
+		if cb.warningLatched.CompareAndSwap(false, true) {
+			safeCallOnWarning(cb.name, cb.onWarning, rate, counts)
+		}
+		return
+	}
+
+	if rate < level*warningRearmRatio {
+		cb.warningLatched.Store(false)
+	}
+}
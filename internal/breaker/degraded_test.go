@@ -0,0 +1,312 @@
+package breaker
+
+import (
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestWarnFailureRate_DegradedAndCallback(t *testing.T) {
+	var calls atomic.Int32
+	var lastRate atomic.Uint64
+	cb := New(Settings{
+		Name:                 "test",
+		Timeout:              time.Minute,
+		AdaptiveThreshold:    true,
+		FailureRateThreshold: 0.15,
+		MinimumObservations:  20,
+		WarnFailureRate:      0.05,
+		OnDegraded: func(name string, rate float64) {
+			calls.Add(1)
+			lastRate.Store(uint64(rate * 1000))
+		},
+	})
+
+	// 19 successes + 1 failure = 5% (at warn level, not above)
+	for i := 0; i < 19; i++ {
+		cb.Execute(successFunc)
+	}
+	cb.Execute(failFunc)
+	if cb.Metrics().Degraded {
+		t.Fatal("Rate at exactly WarnFailureRate should not be degraded")
+	}
+
+	// One more failure: 2/21 ≈ 9.5% (warn band)
+	cb.Execute(failFunc)
+	if !cb.Metrics().Degraded {
+		t.Fatal("Expected Degraded in warn band")
+	}
+	if !cb.Diagnostics().Degraded {
+		t.Error("Expected Diagnostics.Degraded in warn band")
+	}
+	if calls.Load() != 1 {
+		t.Fatalf("Expected OnDegraded to fire once, got %d", calls.Load())
+	}
+	if lastRate.Load() != 95 {
+		t.Errorf("Expected callback rate ~0.095, got %v", float64(lastRate.Load())/1000)
+	}
+
+	// Staying in the warn band does not re-fire
+	cb.Execute(failFunc) // 3/22 ≈ 13.6%
+	if calls.Load() != 1 {
+		t.Errorf("Expected OnDegraded to fire once per crossing, got %d", calls.Load())
+	}
+	if cb.State() != StateClosed {
+		t.Fatalf("Expected closed in warn band, got %v", cb.State())
+	}
+
+	// Into the trip band: 4/23 ≈ 17.4%
+	cb.Execute(failFunc)
+	if cb.State() != StateOpen {
+		t.Fatalf("Expected circuit to trip above FailureRateThreshold, got %v", cb.State())
+	}
+	if cb.Metrics().Degraded {
+		t.Error("Degraded should be cleared once the circuit trips")
+	}
+}
+
+func TestWarnFailureRate_RearmsAfterRecovery(t *testing.T) {
+	var calls atomic.Int32
+	cb := New(Settings{
+		Name:                "test",
+		ReadyToTrip:         func(Counts) bool { return false },
+		MinimumObservations: 10,
+		WarnFailureRate:     0.10,
+		OnDegraded:          func(string, float64) { calls.Add(1) },
+	})
+
+	// 8 successes + 2 failures = 20% → degraded
+	for i := 0; i < 8; i++ {
+		cb.Execute(successFunc)
+	}
+	cb.Execute(failFunc)
+	cb.Execute(failFunc)
+	if !cb.Metrics().Degraded || calls.Load() != 1 {
+		t.Fatalf("Expected degraded with one callback, got degraded=%v calls=%d", cb.Metrics().Degraded, calls.Load())
+	}
+
+	// Successes pull the rate back to 10% → re-armed
+	for i := 0; i < 10; i++ {
+		cb.Execute(successFunc)
+	}
+	if cb.Metrics().Degraded {
+		t.Fatal("Expected degraded to clear after rate recovered")
+	}
+
+	// Cross again → fires again
+	for i := 0; i < 5; i++ {
+		cb.Execute(failFunc)
+	}
+	if calls.Load() != 2 {
+		t.Errorf("Expected OnDegraded to fire again after re-arming, got %d", calls.Load())
+	}
+}
+
+func TestWarnFailureRate_Disabled(t *testing.T) {
+	cb := New(Settings{Name: "test", ReadyToTrip: func(Counts) bool { return false }})
+	for i := 0; i < 10; i++ {
+		cb.Execute(failFunc)
+	}
+	if cb.Metrics().Degraded {
+		t.Error("Degraded should never be set without WarnFailureRate")
+	}
+}
+
+func TestWarnFailureRate_CallbackPanicRecovered(t *testing.T) {
+	cb := New(Settings{
+		Name:            "test",
+		ReadyToTrip:     func(Counts) bool { return false },
+		WarnFailureRate: 0.5,
+		OnDegraded:      func(string, float64) { panic("boom") },
+	})
+
+	cb.Execute(failFunc)
+	if !cb.Metrics().Degraded {
+		t.Error("Expected degraded flag set despite callback panic")
+	}
+}
+
+func TestWarnFailureRate_Validation(t *testing.T) {
+	tests := []struct {
+		name     string
+		settings Settings
+		wantErr  string
+	}{
+		{"negative", Settings{WarnFailureRate: -0.1}, "WarnFailureRate must be in range"},
+		{"one", Settings{WarnFailureRate: 1}, "WarnFailureRate must be in range"},
+		{"above trip", Settings{AdaptiveThreshold: true, FailureRateThreshold: 0.1, WarnFailureRate: 0.2}, "must be below"},
+		{"above default trip", Settings{AdaptiveThreshold: true, WarnFailureRate: 0.05}, "must be below"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateSettings(&tt.settings)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Expected error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestWarningThreshold_ExactlyOncePerExcursion(t *testing.T) {
+	var calls atomic.Int32
+	var lastCounts atomic.Value
+	cb := New(Settings{
+		Name:                     "test",
+		Timeout:                  time.Minute,
+		AdaptiveThreshold:        true,
+		FailureRateThreshold:     0.50,
+		MinimumObservations:      10,
+		WarningThresholdFraction: 0.6, // warn above 30%
+		OnWarning: func(name string, rate float64, counts Counts) {
+			calls.Add(1)
+			lastCounts.Store(counts)
+		},
+	})
+
+	// 6 successes + 4 failures = 40% → above warning level, below trip
+	for i := 0; i < 6; i++ {
+		cb.Execute(successFunc)
+	}
+	for i := 0; i < 4; i++ {
+		cb.Execute(failFunc)
+	}
+	if calls.Load() != 1 {
+		t.Fatalf("Expected OnWarning once, got %d", calls.Load())
+	}
+	if c := lastCounts.Load().(Counts); c.Requests != 10 || c.TotalFailures != 4 {
+		t.Errorf("Expected callback counts 4/10, got %+v", c)
+	}
+	if !cb.Diagnostics().WarningLatched {
+		t.Error("Expected WarningLatched in Diagnostics")
+	}
+
+	// Oscillating around the boundary does not re-fire
+	for i := 0; i < 5; i++ {
+		cb.Execute(successFunc)
+		cb.Execute(failFunc)
+	}
+	if calls.Load() != 1 {
+		t.Errorf("Expected no re-firing while latched, got %d", calls.Load())
+	}
+	if cb.State() != StateClosed {
+		t.Fatalf("Expected closed, got %v", cb.State())
+	}
+}
+
+func TestWarningThreshold_RearmsAfterRecovery(t *testing.T) {
+	var calls atomic.Int32
+	cb := New(Settings{
+		Name:                     "test",
+		AdaptiveThreshold:        true,
+		FailureRateThreshold:     0.50,
+		MinimumObservations:      10,
+		WarningThresholdFraction: 0.6,
+		OnWarning:                func(string, float64, Counts) { calls.Add(1) },
+	})
+
+	for i := 0; i < 6; i++ {
+		cb.Execute(successFunc)
+	}
+	for i := 0; i < 4; i++ {
+		cb.Execute(failFunc)
+	}
+
+	// Dropping to just below the level (hysteresis band) keeps the latch: 4/14 ≈ 28.6%
+	for i := 0; i < 4; i++ {
+		cb.Execute(successFunc)
+	}
+	if !cb.Diagnostics().WarningLatched {
+		t.Fatal("Expected latch to hold inside hysteresis band")
+	}
+
+	// Recover well below the level: 4/20 = 20% < 27%
+	for i := 0; i < 6; i++ {
+		cb.Execute(successFunc)
+	}
+	if cb.Diagnostics().WarningLatched {
+		t.Fatal("Expected latch to re-arm after recovery")
+	}
+
+	// Second excursion fires again
+	for i := 0; i < 5; i++ {
+		cb.Execute(failFunc)
+	}
+	if calls.Load() != 2 {
+		t.Errorf("Expected OnWarning twice (one per excursion), got %d", calls.Load())
+	}
+}
+
+func TestWarningThreshold_DisabledWithoutAdaptive(t *testing.T) {
+	var calls atomic.Int32
+	cb := New(Settings{
+		Name:                     "test",
+		ReadyToTrip:              func(Counts) bool { return false },
+		WarningThresholdFraction: 0.5,
+		OnWarning:                func(string, float64, Counts) { calls.Add(1) },
+	})
+
+	for i := 0; i < 30; i++ {
+		cb.Execute(failFunc)
+	}
+	if calls.Load() != 0 {
+		t.Errorf("Expected no OnWarning when adaptive mode is disabled, got %d", calls.Load())
+	}
+}
+
+func TestWarningThreshold_RuntimeTunable(t *testing.T) {
+	var calls atomic.Int32
+	cb := New(Settings{
+		Name:                 "test",
+		AdaptiveThreshold:    true,
+		FailureRateThreshold: 0.50,
+		MinimumObservations:  10,
+		OnWarning:            func(string, float64, Counts) { calls.Add(1) },
+	})
+
+	for i := 0; i < 8; i++ {
+		cb.Execute(successFunc)
+	}
+	cb.Execute(failFunc)
+	cb.Execute(failFunc) // 20%
+	if calls.Load() != 0 {
+		t.Fatal("Expected no warning while disabled")
+	}
+
+	if err := cb.UpdateSettings(SettingsUpdate{WarningThresholdFraction: Float64Ptr(0.2)}); err != nil {
+		t.Fatalf("UpdateSettings failed: %v", err)
+	}
+	if got := cb.Diagnostics().WarningThresholdFraction; got != 0.2 {
+		t.Errorf("Expected fraction 0.2, got %v", got)
+	}
+
+	cb.Execute(failFunc) // 3/11 ≈ 27% > 10%
+	if calls.Load() != 1 {
+		t.Errorf("Expected warning after enabling at runtime, got %d", calls.Load())
+	}
+
+	if err := cb.UpdateSettings(SettingsUpdate{WarningThresholdFraction: Float64Ptr(1)}); err == nil {
+		t.Error("Expected validation error for fraction >= 1")
+	}
+	if err := validateSettings(&Settings{WarningThresholdFraction: -0.1}); err == nil {
+		t.Error("Expected validation error for negative fraction")
+	}
+}
+
+func TestWarningThreshold_CallbackPanicRecovered(t *testing.T) {
+	cb := New(Settings{
+		Name:                     "test",
+		AdaptiveThreshold:        true,
+		FailureRateThreshold:     0.9,
+		MinimumObservations:      2,
+		WarningThresholdFraction: 0.5,
+		OnWarning:                func(string, float64, Counts) { panic("boom") },
+	})
+
+	cb.Execute(successFunc)
+	cb.Execute(failFunc) // 50% > 45% warning level, below 90% trip
+	if !cb.Diagnostics().WarningLatched {
+		t.Error("Expected latch set despite callback panic")
+	}
+}
@@ -0,0 +1,109 @@
+package breaker
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestDemand_CountsRejectedAttemptsWhileOpen(t *testing.T) {
+	cb := New(tripOnFirstFailure(Settings{Name: "demand-open", Timeout: time.Minute}))
+	cb.Execute(failFunc)
+
+	for i := 0; i < 5; i++ {
+		if _, err := cb.Execute(successFunc); !errors.Is(err, ErrOpenState) {
+			t.Fatalf("Expected ErrOpenState, got %v", err)
+		}
+	}
+
+	m := cb.Metrics()
+	if m.Demand != 5 {
+		t.Errorf("Expected demand to count all 5 attempts, got %d", m.Demand)
+	}
+	if m.Counts.Requests != 0 {
+		t.Errorf("Expected no admitted requests, got %d", m.Counts.Requests)
+	}
+	if m.ServedRatio != 0 {
+		t.Errorf("Expected served ratio 0 while everything is rejected, got %v", m.ServedRatio)
+	}
+}
+
+func TestDemand_ClearedWithCounts(t *testing.T) {
+	cb := New(tripOnFirstFailure(Settings{Name: "demand-half-open", Timeout: 10 * time.Millisecond}))
+	cb.Execute(failFunc)
+	time.Sleep(20 * time.Millisecond)
+
+	// One probe admitted, three turned away while it runs
+	cb.Execute(func() (interface{}, error) {
+		for i := 0; i < 3; i++ {
+			cb.Execute(successFunc)
+		}
+		return nil, errors.New("still down")
+	})
+	if cb.State() != StateOpen {
+		t.Fatalf("Expected the failed probe to reopen, got %v", cb.State())
+	}
+
+	// Counts and demand were cleared on reopening
+	if m := cb.Metrics(); m.Demand != 0 || m.ServedRatio != 0 {
+		t.Errorf("Expected demand cleared with the counts, got %d (%v)", m.Demand, m.ServedRatio)
+	}
+}
+
+func TestDemand_ServedRatioWhileClosed(t *testing.T) {
+	cb := New(Settings{Name: "demand-closed", Timeout: time.Minute})
+
+	for i := 0; i < 4; i++ {
+		cb.Execute(successFunc)
+	}
+
+	m := cb.Metrics()
+	if m.Demand != 4 || m.Counts.Requests != 4 || m.ServedRatio != 1 {
+		t.Errorf("Expected all 4 attempts served, got demand=%d requests=%d ratio=%v",
+			m.Demand, m.Counts.Requests, m.ServedRatio)
+	}
+}
+
+func TestDemand_KeepsUncountedRequests(t *testing.T) {
+	cb := New(Settings{Name: "demand-uncounted", Timeout: time.Minute})
+
+	// Canceled while running: uncounted, but still an attempt
+	ctx, cancel := context.WithCancel(context.Background())
+	cb.ExecuteContext(ctx, func() (interface{}, error) {
+		cancel()
+		return nil, nil
+	})
+	cb.Execute(successFunc)
+
+	m := cb.Metrics()
+	if m.Demand != 2 || m.Counts.Requests != 1 || m.ServedRatio != 0.5 {
+		t.Errorf("Expected demand 2 with 1 request served, got demand=%d requests=%d ratio=%v",
+			m.Demand, m.Counts.Requests, m.ServedRatio)
+	}
+}
+
+func TestDemand_ExcludesUncountedAndDisabled(t *testing.T) {
+	cb := New(Settings{Name: "demand-excluded", Timeout: time.Minute})
+
+	cb.ExecuteUncounted(successFunc)
+	cb.Disable()
+	cb.Execute(successFunc)
+	cb.Enable()
+
+	if got := cb.Metrics().Demand; got != 0 {
+		t.Errorf("Expected no demand from synthetic or bypassed calls, got %d", got)
+	}
+}
+
+func TestDemand_GroupAggregates(t *testing.T) {
+	g := NewGroup(tripOnFirstFailure(Settings{Name: "demand-group", Timeout: time.Minute}), []string{"a", "b"})
+	g.Execute("a", failFunc) // Trips a
+	g.Execute("a", successFunc)
+	g.Execute("b", successFunc)
+
+	m := g.Metrics()
+	if m.Demand != 2 || m.ServedRatio != 0.5 {
+		t.Errorf("Expected demand 2 with half served, got %d (%v)", m.Demand, m.ServedRatio)
+	}
+}
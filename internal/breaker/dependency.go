@@ -0,0 +1,145 @@
+package breaker
+
+import (
+	"fmt"
+	"sync"
+)
+
+// dependencyMu serializes changes to the dependency graph so cycle detection
+// sees a consistent view. Reads on the Execute path are lock-free.
+var dependencyMu sync.Mutex
+
+// DependencyOpenError is returned by Execute() and ExecuteContext() when a
+// dependency of the circuit breaker is open.
+//
+// It wraps ErrDependencyOpen, so callers can match with errors.Is and retrieve
+// the name of the dependency with errors.As:
+//
+//	var depErr *autobreaker.DependencyOpenError
+//	if errors.As(err, &depErr) {
+//	    log.Printf("feature disabled: %s is down", depErr.Dependency)
+//	}
+type DependencyOpenError struct {
+	// Dependency is the name of the open dependency. For chains, this is the
+	// breaker that is actually open, not the intermediate dependent.
+	Dependency string
+}
+
+// Error implements the error interface.
+func (e *DependencyOpenError) Error() string {
+	return fmt.Sprintf("%v: %q", ErrDependencyOpen, e.Dependency)
+}
+
+// Unwrap returns ErrDependencyOpen.
+func (e *DependencyOpenError) Unwrap() error {
+	return ErrDependencyOpen
+}
+
+// DependsOn declares that this circuit breaker depends on other.
+//
+// While other (or, transitively, any of its dependencies) is open, Execute() and
+// ExecuteContext() on this breaker reject immediately with a *DependencyOpenError
+// identifying the open dependency. Such rejections are not counted as requests
+// and never affect this breaker's own state. As soon as the dependency leaves Open
+// (or its Timeout elapses so it is ready to probe), requests are admitted again;
+// the dependent has no timeout of its own for dependency outages.
+//
+// Returns an error wrapping ErrDependencyCycle if the dependency would create a
+// cycle (including a breaker depending on itself). Declaring the same dependency
+// twice is a no-op.
+//
+// Thread-safe: Can be called concurrently with Execute() and other methods.
+//
+// Example:
+//
+//	db := autobreaker.New(autobreaker.Settings{Name: "database"})
+//	reports := autobreaker.New(autobreaker.Settings{Name: "report-generator"})
+//	if err := reports.DependsOn(db); err != nil {
+//	    log.Fatal(err)
+//	}
+func (cb *CircuitBreaker) DependsOn(other *CircuitBreaker) error {
+	dependencyMu.Lock()
+	defer dependencyMu.Unlock()
+
+	if other == cb || other.reaches(cb) {
+		return fmt.Errorf("autobreaker: %q depends on %q: %w", cb.name, other.name, ErrDependencyCycle)
+	}
+
+	var deps []*CircuitBreaker
+	if current := cb.dependencies.Load(); current != nil {
+		for _, dep := range *current {
+			if dep == other {
+				return nil
+			}
+		}
+		deps = append(deps, *current...)
+	}
+	deps = append(deps, other)
+	cb.dependencies.Store(&deps)
+	cb.refreshPlainClosed()
+
+	return nil
+}
+
+// EffectiveState returns the state of the circuit breaker combined with its
+// dependencies: StateOpen if any dependency (transitively) is open, otherwise
+// the breaker's own state. A disabled breaker bypasses its dependencies too,
+// so it reports StateDisabled.
+//
+// State() reports only the breaker's own state machine. Use EffectiveState() to
+// decide whether a request would currently be admitted.
+func (cb *CircuitBreaker) EffectiveState() State {
+	return cb.effectiveState(cb.State())
+}
+
+// effectiveState combines an already loaded own state with dependency state.
+func (cb *CircuitBreaker) effectiveState(own State) State {
+	if own != StateOpen && own != StateDisabled && cb.openDependency() != nil {
+		return StateOpen
+	}
+	return own
+}
+
+// openDependency returns the first open breaker among the dependencies (searched
+// depth-first), or nil if none is open. An open dependency whose Timeout has
+// elapsed is ready to probe and is not considered open.
+func (cb *CircuitBreaker) openDependency() *CircuitBreaker {
+	deps := cb.dependencies.Load()
+	if deps == nil {
+		return nil
+	}
+
+	for _, dep := range *deps {
+		if dep.machineState() == StateOpen && !dep.shouldTransitionToHalfOpen() {
+			return dep
+		}
+		if open := dep.openDependency(); open != nil {
+			return open
+		}
+	}
+	return nil
+}
+
+// checkDependencies returns a *DependencyOpenError if a dependency is open.
+func (cb *CircuitBreaker) checkDependencies() error {
+	if open := cb.openDependency(); open != nil {
+		return &DependencyOpenError{Dependency: open.name}
+	}
+	return nil
+}
+
+// reaches reports whether target is reachable from cb through dependencies.
+// Caller must hold dependencyMu.
+func (cb *CircuitBreaker) reaches(target *CircuitBreaker) bool {
+	deps := cb.dependencies.Load()
+	if deps == nil {
+		return false
+	}
+
+	for _, dep := range *deps {
+		if dep == target || dep.reaches(target) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,184 @@
+package breaker
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+// newDependencyBreaker returns a breaker that trips on one failure.
+func newDependencyBreaker(name string, timeout time.Duration) *CircuitBreaker {
+	return New(Settings{
+		Name:        name,
+		Timeout:     timeout,
+		ReadyToTrip: func(c Counts) bool { return c.ConsecutiveFailures >= 1 },
+	})
+}
+
+func TestDependsOn_ChainOfThree(t *testing.T) {
+	db := newDependencyBreaker("database", 20*time.Millisecond)
+	cache := newDependencyBreaker("cache", time.Minute)
+	reports := newDependencyBreaker("report-generator", time.Minute)
+
+	if err := cache.DependsOn(db); err != nil {
+		t.Fatalf("DependsOn failed: %v", err)
+	}
+	if err := reports.DependsOn(cache); err != nil {
+		t.Fatalf("DependsOn failed: %v", err)
+	}
+
+	if _, err := reports.Execute(successFunc); err != nil {
+		t.Fatalf("Expected success with healthy dependencies, got %v", err)
+	}
+
+	// Trip the bottom of the chain
+	db.Execute(failFunc)
+	if db.State() != StateOpen {
+		t.Fatalf("Expected database open, got %v", db.State())
+	}
+
+	executed := false
+	_, err := reports.Execute(func() (interface{}, error) {
+		executed = true
+		return nil, nil
+	})
+	if executed {
+		t.Error("Request should not execute while a dependency is open")
+	}
+	if !errors.Is(err, ErrDependencyOpen) {
+		t.Fatalf("Expected ErrDependencyOpen, got %v", err)
+	}
+	if errors.Is(err, ErrOpenState) {
+		t.Error("Dependency rejection should be distinct from ErrOpenState")
+	}
+	var depErr *DependencyOpenError
+	if !errors.As(err, &depErr) || depErr.Dependency != "database" {
+		t.Errorf("Expected error naming 'database', got %v", err)
+	}
+
+	// Own state untouched, effective state reflects the dependency
+	if reports.State() != StateClosed {
+		t.Errorf("Expected own state closed, got %v", reports.State())
+	}
+	if got := reports.EffectiveState(); got != StateOpen {
+		t.Errorf("Expected effective state open, got %v", got)
+	}
+	if m := reports.Metrics(); m.EffectiveState != StateOpen || m.Counts.Requests != 1 || m.Counts.TotalFailures != 0 {
+		t.Errorf("Expected rejection not to be counted, got %+v", m)
+	}
+
+	// Recovery of the dependency immediately re-enables the dependents
+	time.Sleep(30 * time.Millisecond)
+	if got := reports.EffectiveState(); got != StateClosed {
+		t.Errorf("Expected effective state closed once database is ready to probe, got %v", got)
+	}
+	db.Execute(successFunc)
+	if db.State() != StateClosed {
+		t.Fatalf("Expected database closed, got %v", db.State())
+	}
+	if _, err := reports.Execute(successFunc); err != nil {
+		t.Errorf("Expected success after dependency recovered, got %v", err)
+	}
+}
+
+func TestDependsOn_RejectsCycles(t *testing.T) {
+	a := newDependencyBreaker("a", time.Minute)
+	b := newDependencyBreaker("b", time.Minute)
+	c := newDependencyBreaker("c", time.Minute)
+
+	if err := a.DependsOn(a); !errors.Is(err, ErrDependencyCycle) {
+		t.Errorf("Expected ErrDependencyCycle for self-dependency, got %v", err)
+	}
+
+	if err := a.DependsOn(b); err != nil {
+		t.Fatalf("DependsOn failed: %v", err)
+	}
+	if err := b.DependsOn(c); err != nil {
+		t.Fatalf("DependsOn failed: %v", err)
+	}
+	if err := c.DependsOn(a); !errors.Is(err, ErrDependencyCycle) {
+		t.Errorf("Expected ErrDependencyCycle for c -> a, got %v", err)
+	}
+
+	// Duplicate declaration is a no-op
+	if err := a.DependsOn(b); err != nil {
+		t.Errorf("Expected duplicate DependsOn to succeed, got %v", err)
+	}
+	if deps := a.dependencies.Load(); len(*deps) != 1 {
+		t.Errorf("Expected 1 dependency, got %d", len(*deps))
+	}
+}
+
+func TestDependsOn_OwnOpenStateTakesPrecedence(t *testing.T) {
+	db := newDependencyBreaker("database", time.Minute)
+	reports := newDependencyBreaker("reports", time.Minute)
+	if err := reports.DependsOn(db); err != nil {
+		t.Fatalf("DependsOn failed: %v", err)
+	}
+
+	reports.Execute(failFunc)
+	if _, err := reports.Execute(successFunc); !errors.Is(err, ErrOpenState) {
+		t.Errorf("Expected ErrOpenState from own open circuit, got %v", err)
+	}
+	if got := reports.EffectiveState(); got != StateOpen {
+		t.Errorf("Expected effective state open, got %v", got)
+	}
+}
+
+func TestDependsOn_ConcurrentDependencyFlips(t *testing.T) {
+	db := newDependencyBreaker("database", time.Millisecond)
+	reports := newDependencyBreaker("reports", time.Minute)
+	if err := reports.DependsOn(db); err != nil {
+		t.Fatalf("DependsOn failed: %v", err)
+	}
+
+	stop := make(chan struct{})
+	var flipper sync.WaitGroup
+	flipper.Add(1)
+	go func() {
+		defer flipper.Done()
+		for {
+			select {
+			case <-stop:
+				return
+			default:
+			}
+			db.Record(false)
+			time.Sleep(2 * time.Millisecond)
+			db.Execute(successFunc)
+		}
+	}()
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 1000)
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				if _, err := reports.Execute(successFunc); err != nil {
+					errs <- err
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	close(stop)
+	flipper.Wait()
+	close(errs)
+
+	for err := range errs {
+		if !errors.Is(err, ErrDependencyOpen) {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+	}
+
+	counts := reports.Counts()
+	if counts.TotalFailures != 0 || counts.Requests != counts.TotalSuccesses {
+		t.Errorf("Dependency rejections must not affect own counts, got %+v", counts)
+	}
+	if reports.State() != StateClosed {
+		t.Errorf("Expected own state closed, got %v", reports.State())
+	}
+}
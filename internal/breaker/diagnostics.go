@@ -33,7 +33,8 @@ type Diagnostics struct {
 	// Name is the circuit breaker identifier from Settings.Name.
 	Name string
 
-	// State is the current circuit breaker state (Closed/Open/HalfOpen).
+	// State is the current circuit breaker state (Closed/Open/HalfOpen, or
+	// Disabled; see Metrics.MachineState for the frozen state while disabled).
 	State State
 
 	// Metrics provides current observability data including counts, rates, and timestamps.
@@ -47,6 +48,14 @@ type Diagnostics struct {
 	// MaxRequests is the maximum concurrent requests allowed in half-open state.
 	MaxRequests uint32
 
+	// HalfOpenMaxProbes is the total probe budget per half-open episode.
+	// Zero means no budget (only MaxRequests concurrency applies).
+	HalfOpenMaxProbes uint32
+
+	// HalfOpenProbesUsed is the number of probes admitted in the current half-open
+	// episode. Always zero outside HalfOpen or when HalfOpenMaxProbes is 0.
+	HalfOpenProbesUsed uint32
+
 	// Interval is the period to clear counts in closed state.
 	// Zero means counts are cleared only on state transitions.
 	Interval time.Duration
@@ -66,11 +75,150 @@ type Diagnostics struct {
 	// Only used when AdaptiveEnabled is true.
 	MinimumObservations uint32
 
+	// RecoverFailureRate is the effective failure rate at or below which the backend
+	// is considered healthy again. Equals FailureRateThreshold when no hysteresis is set.
+	// Only used when AdaptiveEnabled is true.
+	RecoverFailureRate float64
+
+	// BaselineFailureRate is the failure rate of the paired Settings.Baseline
+	// breaker. Zero without a Baseline.
+	BaselineFailureRate float64
+
+	// EffectiveFailureRateThreshold is the failure rate the adaptive trip rule
+	// currently compares Metrics.FailureRate against: RelativeFailureRateMultiplier ×
+	// BaselineFailureRate when RelativeComparison is true, RecoverFailureRate
+	// when RecoveryComparison is true, ErrorBudget × BurnRateThreshold with an
+	// error budget, otherwise FailureRateThreshold.
+	EffectiveFailureRateThreshold float64
+
+	// RelativeComparison indicates the trip threshold is relative to the Baseline.
+	// False without a Baseline, or while the baseline has fewer than
+	// MinimumObservations requests (the absolute threshold applies).
+	RelativeComparison bool
+
+	// ErrorBudget is the failure fraction the SLO allows. Zero when burn rate
+	// tripping is disabled.
+	ErrorBudget float64
+
+	// BurnRate is the current window's failure rate divided by ErrorBudget: how
+	// many times faster than sustainable the budget is being spent. The adaptive
+	// trip rule trips when it exceeds BurnRateThreshold. Zero without an
+	// ErrorBudget.
+	BurnRate float64
+
+	// BurnRateThreshold is the burn rate above which the circuit trips. Zero
+	// without an ErrorBudget.
+	BurnRateThreshold float64
+
+	// RecoveryWindowsRemaining is the number of clean windows still required
+	// before the recovery period ends. Zero outside a recovery period.
+	RecoveryWindowsRemaining uint32
+
+	// RecoveryComparison indicates the trip threshold is RecoverFailureRate: the
+	// rate has not recovered to it since it last exceeded FailureRateThreshold,
+	// or the circuit re-closed and has not yet had RecoveryWindows clean windows.
+	RecoveryComparison bool
+
+	// WarnFailureRate is the failure rate above which the circuit is reported as degraded.
+	// Zero means the warn band is disabled.
+	WarnFailureRate float64
+
+	// Degraded indicates the failure rate is in the warn band (above WarnFailureRate)
+	// without having tripped. Same as Metrics.Degraded.
+	Degraded bool
+
+	// WarningThresholdFraction is the early-warning level as a fraction of
+	// FailureRateThreshold. Zero means the warning is disabled.
+	WarningThresholdFraction float64
+
+	// Healthy indicates the backend is considered healthy: the circuit is Closed and,
+	// in adaptive mode, the failure rate has recovered to RecoverFailureRate since it
+	// last exceeded FailureRateThreshold. Until then the adaptive trip rule compares
+	// against RecoverFailureRate (see Settings.RecoverFailureRate).
+	Healthy bool
+
+	// Maintenance indicates the breaker is in a maintenance window (see
+	// EnterMaintenance): outcomes are executed but not recorded.
+	Maintenance bool
+
+	// Disabled indicates the breaker is bypassed (see Disable): requests run
+	// without admission checks or accounting. Same as Metrics.Disabled.
+	Disabled bool
+
+	// PartialWindow indicates adaptive trips are held off because the first
+	// aligned window after construction is still in progress (see
+	// Settings.RequireFullWindow).
+	PartialWindow bool
+
+	// WarningLatched indicates OnWarning has fired for the current excursion and
+	// will not fire again until the rate recovers or counts are cleared.
+	WarningLatched bool
+
+	// ShadowTripCount is the number of times Settings.ShadowReadyToTrip would
+	// have tripped the circuit (at most once per observation window). Zero
+	// without a shadow rule.
+	ShadowTripCount uint32
+
+	// ShadowWouldTrip indicates ShadowReadyToTrip returned true for the current
+	// window's counts: the shadow rule would have the circuit open now.
+	ShadowWouldTrip bool
+
+	// OpenReason describes why the circuit is open. Set in Open state, carried
+	// into HalfOpen with Recovering=true, and zero in Closed state.
+	//
+	// Example:
+	//   if diag.State != StateClosed {
+	//       log.Warn("circuit %s: %s", diag.Name, diag.OpenReason)
+	//   }
+	OpenReason OpenReason
+
+	// LastTripReason explains the most recent Closed → Open trip, e.g.
+	// "failure rate 18.00% (9/50) exceeded threshold 10.00%" or
+	// "6 consecutive failures". Unlike OpenReason, it is kept after the circuit
+	// recovers and is not updated by failed probes, so it answers "why did it
+	// trip last time?" in any state. Empty if the circuit has never tripped.
+	LastTripReason string
+
+	// LastTripCounts are the counts that caused the most recent trip (see
+	// LastTripReason). Zero if the circuit has never tripped.
+	LastTripCounts Counts
+
+	// Labels is a copy of the breaker's labels (see Settings.Labels), nil if
+	// it has none.
+	Labels map[string]string
+
+	// AwaitingEligibleProbe indicates the circuit is HalfOpen and has turned
+	// away probe-ineligible requests (see ExecuteOpts) while no probe has been
+	// admitted, within Settings.EligibleProbeWait. Recovery waits for an
+	// eligible request.
+	AwaitingEligibleProbe bool
+
+	// LearnedTimeout is the open wait learned from observed recovery times (see
+	// Settings.AdaptiveTimeout). It equals Timeout until the first recovery.
+	// Zero when AdaptiveTimeout is not set.
+	LearnedTimeout time.Duration
+
+	// SlowCallThreshold is the latency above which a call currently counts as
+	// slow: the learned p95 times Settings.SlowCallFactor. Zero without
+	// SlowCallFactor or until the p95 has been learned.
+	SlowCallThreshold time.Duration
+
+	// ClassifierLatched indicates IsSuccessful panicked on
+	// Settings.ClassifierPanicLatch consecutive calls and is no longer called:
+	// calls are classified by DefaultIsSuccessful until SettingsUpdate.IsSuccessful
+	// replaces it. See Metrics.ClassifierPanics.
+	ClassifierLatched bool
+
+	// Episodes are the last finished open periods, oldest first (see
+	// Settings.EpisodeHistory). Nil when episodes are not tracked.
+	Episodes []Episode
+
 	// --- Predictive Diagnostics ---
 	// These fields provide forward-looking insights about circuit behavior.
 
 	// WillTripNext predicts whether the circuit would trip if the next request fails.
-	// Only meaningful in Closed state (always false in Open/HalfOpen).
+	// Only meaningful in Closed state (always false in Open/HalfOpen), and
+	// always false with a custom Settings.Engine.
 	//
 	// Use this for:
 	//   - Proactive alerting: "Circuit about to trip!"
@@ -136,6 +284,10 @@ type Diagnostics struct {
 //   - You're polling frequently for dashboards
 //   - You don't need configuration or predictions
 //
+// Use QuickStats() for high-frequency scrapers: it never allocates and never invokes
+// user callbacks. WillTripNext calls ReadyToTrip; set Settings.DiagnosticsCacheTTL to
+// reuse the prediction across calls made in quick succession.
+//
 // Thread-safe: Can be called concurrently with Execute(), UpdateSettings(),
 // and other methods. Returns a consistent snapshot.
 //
@@ -165,36 +317,99 @@ type Diagnostics struct {
 //	        diag.FailureRateThreshold*100)
 //	}
 func (cb *CircuitBreaker) Diagnostics() Diagnostics {
+	// Remediate a circuit stuck open past MaxOpenDuration before taking the snapshot
+	if cb.watchesStuckOpen() && cb.machineState() == StateOpen {
+		cb.checkStuckOpen(cb.now())
+	}
+
 	metrics := cb.Metrics()
-	state := metrics.State
+	// Predictions follow the state machine, which a disabled breaker resumes
+	state := metrics.MachineState
 
 	// Calculate diagnostic predictions
-	willTripNext := cb.wouldTripOnNextFailure(metrics.Counts)
+	willTripNext := cb.predictWillTripNext(state, metrics.Counts)
 
 	var timeUntilHalfOpen time.Duration
 	if state == StateOpen {
 		openedAt := cb.openedAt.Load()
 		if openedAt > 0 {
-			elapsed := time.Since(time.Unix(0, openedAt))
-			remaining := cb.getTimeout() - elapsed
+			elapsed := time.Duration(cb.now() - openedAt)
+			remaining := cb.openWait(openedAt) - elapsed
 			if remaining > 0 {
 				timeUntilHalfOpen = remaining
 			}
 		}
 	}
 
+	comparison := cb.tripComparison()
+
+	var lastTrip OpenReason
+	if reason := cb.lastTrip.Load(); reason != nil {
+		lastTrip = cb.withDetail(*reason)
+	}
+
 	return Diagnostics{
 		Name:    cb.name,
-		State:   state,
+		State:   metrics.State,
 		Metrics: metrics,
 
 		// Configuration
-		MaxRequests:          cb.getMaxRequests(),
-		Interval:             cb.getInterval(),
-		Timeout:              cb.getTimeout(),
-		AdaptiveEnabled:      cb.adaptiveThreshold,
-		FailureRateThreshold: cb.getFailureRateThreshold(),
-		MinimumObservations:  cb.getMinimumObservations(),
+		MaxRequests:              cb.getMaxRequests(),
+		HalfOpenMaxProbes:        cb.halfOpenMaxProbes,
+		HalfOpenProbesUsed:       cb.halfOpenProbes.Load(),
+		Interval:                 cb.getInterval(),
+		Timeout:                  cb.getTimeout(),
+		AdaptiveEnabled:          cb.adaptiveThreshold,
+		FailureRateThreshold:     cb.getFailureRateThreshold(),
+		MinimumObservations:      cb.getMinimumObservations(),
+		RecoverFailureRate:       cb.effectiveRecoverFailureRate(),
+		WarnFailureRate:          cb.warnFailureRate,
+		WarningThresholdFraction: cb.getWarningThresholdFraction(),
+
+		// Canary comparison
+		BaselineFailureRate:           comparison.baselineRate,
+		EffectiveFailureRateThreshold: comparison.threshold,
+		RelativeComparison:            comparison.relative,
+
+		// Error budget
+		ErrorBudget:       cb.errorBudget,
+		BurnRate:          cb.burnRate(metrics.Counts),
+		BurnRateThreshold: cb.burnRateThreshold,
+
+		// Post-recovery hysteresis
+		RecoveryWindowsRemaining: cb.recoveryWindowsLeft.Load(),
+		RecoveryComparison:       comparison.recovery,
+
+		// Alerting
+		Degraded:       metrics.Degraded,
+		Healthy:        cb.isHealthy(state),
+		WarningLatched: cb.warningLatched.Load(),
+		Maintenance:    cb.maintenance.Load(),
+		Disabled:       metrics.Disabled,
+		PartialWindow:  cb.partialWindow.Load(),
+		OpenReason:     cb.currentOpenReason(state),
+		LastTripReason: lastTrip.Detail,
+		LastTripCounts: lastTrip.Counts,
+		Labels:         cb.Labels(),
+
+		// Probe eligibility
+		AwaitingEligibleProbe: cb.awaitingEligibleProbe(state),
+
+		// Adaptive timeout
+		LearnedTimeout: cb.learnedTimeoutValue(),
+
+		// Slow call classification
+		SlowCallThreshold: cb.slowCallThreshold(),
+
+		// Classifier panic latch
+		ClassifierLatched: cb.classifierLatched.Load(),
+
+		// Open periods
+		Episodes: cb.Episodes(),
+
+		// Shadow trip rule
+		ShadowTripCount: cb.eventTotals().shadowTrips.Load(),
+		ShadowWouldTrip: cb.shadowTripped.Load(),
 
 		// Predictions
 		WillTripNext:      willTripNext,
@@ -225,8 +440,8 @@ func (cb *CircuitBreaker) Diagnostics() Diagnostics {
 //
 // Thread-safe: Uses ReadyToTrip callback which must be thread-safe.
 func (cb *CircuitBreaker) wouldTripOnNextFailure(counts Counts) bool {
-	// Only relevant in Closed state
-	if cb.State() != StateClosed {
+	// Only relevant in Closed state, and only for the counts-based trip rule
+	if cb.machineState() != StateClosed || cb.customEngine {
 		return false
 	}
 
@@ -237,8 +452,40 @@ func (cb *CircuitBreaker) wouldTripOnNextFailure(counts Counts) bool {
 		TotalFailures:        counts.TotalFailures + 1,
 		ConsecutiveSuccesses: 0, // Reset on failure
 		ConsecutiveFailures:  counts.ConsecutiveFailures + 1,
+		Panics:               counts.Panics,
+		TimeoutFailures:      counts.TimeoutFailures,
+	}
+	if cb.outcomeWeight != nil {
+		simulatedCounts.FailureWeight = counts.FailureWeight + 1
 	}
 
 	// Check if readyToTrip would trigger
-	return cb.readyToTrip(simulatedCounts)
+	return safeCallReadyToTrip(cb.name, cb.readyToTrip, simulatedCounts)
+}
+
+// willTripCache is a WillTripNext prediction and the time (UnixNano) it was computed.
+type willTripCache struct {
+	computedAt   int64
+	willTripNext bool
+}
+
+// predictWillTripNext returns wouldTripOnNextFailure, reusing a prediction computed
+// within DiagnosticsCacheTTL. Outside Closed state the prediction is always false
+// and the cache is bypassed, so a cached result never outlives a trip.
+func (cb *CircuitBreaker) predictWillTripNext(state State, counts Counts) bool {
+	if state != StateClosed {
+		return false
+	}
+	if cb.diagnosticsCacheTTL <= 0 {
+		return cb.wouldTripOnNextFailure(counts)
+	}
+
+	now := cb.now()
+	if cached := cb.willTripCache.Load(); cached != nil && now-cached.computedAt < int64(cb.diagnosticsCacheTTL) {
+		return cached.willTripNext
+	}
+
+	willTripNext := cb.wouldTripOnNextFailure(counts)
+	cb.willTripCache.Store(&willTripCache{computedAt: now, willTripNext: willTripNext})
+	return willTripNext
 }
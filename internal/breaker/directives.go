@@ -0,0 +1,90 @@
+package breaker
+
+import (
+	"context"
+	"sync/atomic"
+)
+
+// directives are per-call directives carried in a context for ExecuteContext.
+type directives uint8
+
+const (
+	directiveBypass        directives = 1 << iota // Run without admission or counting
+	directiveProbeEligible                        // May act as a half-open probe
+	directiveIgnoreOutcome                        // Do not count the outcome
+)
+
+// directivesKey is the single context key holding a call's directives, so one
+// ctx.Value lookup reads them all.
+type directivesKey struct{}
+
+// directivesUsed is set once any directive has been attached to a context.
+// Until then ExecuteContext skips the ctx.Value lookup entirely.
+var directivesUsed atomic.Bool
+
+// withDirective returns a copy of ctx carrying d in addition to the
+// directives ctx already carries.
+func withDirective(ctx context.Context, d directives) context.Context {
+	directivesUsed.Store(true)
+	return context.WithValue(ctx, directivesKey{}, directivesOf(ctx)|d)
+}
+
+// directivesOf returns the directives carried by ctx.
+func directivesOf(ctx context.Context) directives {
+	d, _ := ctx.Value(directivesKey{}).(directives)
+	return d
+}
+
+// callDirectives returns the directives for an ExecuteContext call: none
+// without a lookup until a directive has ever been set.
+func callDirectives(ctx context.Context) directives {
+	if !directivesUsed.Load() {
+		return 0
+	}
+	return directivesOf(ctx)
+}
+
+// WithBypass returns a copy of ctx whose ExecuteContext calls skip the breaker:
+// the request runs in any state, without admission control (state, bulkhead,
+// dependencies, engine), without counting, and without affecting the circuit.
+// Each bypassed call is tallied in Metrics.BypassedCalls for auditing.
+//
+// A sharp tool: a bypassed call reaches a backend the breaker may be shielding.
+// Reserve it for operator tooling, such as a forced admin retry, and keep it
+// out of ordinary request paths. Execute and the other variants without a
+// context ignore it.
+//
+// Example:
+//
+//	if r.URL.Query().Get("force") == "1" && isAdmin(r) {
+//	    ctx = autobreaker.WithBypass(ctx)
+//	}
+//	result, err := breaker.ExecuteContext(ctx, req)
+func WithBypass(ctx context.Context) context.Context {
+	return withDirective(ctx, directiveBypass)
+}
+
+// WithForceProbeEligible returns a copy of ctx whose ExecuteContextWithOpts
+// calls may act as half-open recovery probes even when ExecuteOpts.ProbeEligible
+// is unset, e.g. to let operator tooling probe through a path that normally
+// leaves probing to reads. ExecuteContext treats every call as eligible
+// already.
+//
+// A sharp tool: a probe decides whether the circuit closes, so mark only cheap,
+// idempotent calls. Execute and the other variants without a context ignore it.
+func WithForceProbeEligible(ctx context.Context) context.Context {
+	return withDirective(ctx, directiveProbeEligible)
+}
+
+// WithOutcomeIgnored returns a copy of ctx whose ExecuteContext calls are
+// admitted as usual but whose outcome, including a panic, is not counted:
+// like returning ErrIgnoreOutcome, without changing the error the caller sees.
+// A half-open probe slot it used is refunded.
+//
+// A sharp tool: failures hidden this way can never trip the circuit. Use it
+// for calls known to say nothing about the backend's health, such as a
+// synthetic request from a load test. Execute and the other variants without a
+// context ignore it.
+func WithOutcomeIgnored(ctx context.Context) context.Context {
+	return withDirective(ctx, directiveIgnoreOutcome)
+}
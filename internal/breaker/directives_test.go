@@ -0,0 +1,140 @@
+package breaker
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestWithBypass_RunsOutsideBreaker(t *testing.T) {
+	cb := New(tripOnFirstFailure(Settings{Name: "bypass", Timeout: time.Minute}))
+	ctx := WithBypass(context.Background())
+
+	// A bypassed failure is not counted and cannot trip
+	if _, err := cb.ExecuteContext(ctx, failFunc); err == nil {
+		t.Fatal("Expected the request's error")
+	}
+	if cb.State() != StateClosed || cb.Counts().Requests != 0 {
+		t.Fatalf("Expected a bypassed failure uncounted, got %v %+v", cb.State(), cb.Counts())
+	}
+
+	// An open circuit still runs bypassed calls
+	cb.Execute(failFunc)
+	ran := false
+	result, err := cb.ExecuteContext(ctx, func() (interface{}, error) {
+		ran = true
+		return "forced", nil
+	})
+	if !ran || err != nil || result != "forced" {
+		t.Fatalf("Expected the bypassed call to run while open, got ran=%v %v %v", ran, result, err)
+	}
+	if cb.State() != StateOpen {
+		t.Errorf("Expected a bypassed success not to close the circuit, got %v", cb.State())
+	}
+	if _, err := cb.ExecuteContext(context.Background(), successFunc); err != ErrOpenState {
+		t.Errorf("Expected calls without the directive rejected, got %v", err)
+	}
+	if got := cb.Metrics().BypassedCalls; got != 2 {
+		t.Errorf("Expected 2 bypassed calls, got %d", got)
+	}
+}
+
+func TestWithOutcomeIgnored_NotCounted(t *testing.T) {
+	cb := New(tripOnFirstFailure(Settings{Name: "ignored", RecoverPanics: true}))
+	ctx := WithOutcomeIgnored(context.Background())
+
+	boom := errors.New("boom")
+	_, err := cb.ExecuteContext(ctx, func() (interface{}, error) { return nil, boom })
+	if err != boom {
+		t.Errorf("Expected the error returned unchanged, got %v", err)
+	}
+	if _, err := cb.ExecuteContext(ctx, panicFunc); err == nil {
+		t.Error("Expected the recovered panic as an error")
+	}
+	if cb.State() != StateClosed || cb.Counts().Requests != 0 {
+		t.Errorf("Expected ignored outcomes uncounted, got %v %+v", cb.State(), cb.Counts())
+	}
+
+	// Execute has no context: a failure there still counts
+	cb.Execute(failFunc)
+	if cb.State() != StateOpen {
+		t.Errorf("Expected Execute to count normally, got %v", cb.State())
+	}
+}
+
+func TestWithOutcomeIgnored_RefundsProbe(t *testing.T) {
+	const timeout = 10 * time.Millisecond
+	cb := New(tripOnFirstFailure(Settings{Name: "ignored-probe", Timeout: timeout}))
+	cb.Execute(failFunc)
+	time.Sleep(2 * timeout)
+
+	cb.ExecuteContext(WithOutcomeIgnored(context.Background()), failFunc)
+	if cb.State() != StateHalfOpen {
+		t.Fatalf("Expected an ignored probe to leave the circuit HalfOpen, got %v", cb.State())
+	}
+	if _, err := cb.Execute(successFunc); err != nil {
+		t.Fatalf("Expected the refunded probe slot reused, got %v", err)
+	}
+	if cb.State() != StateClosed {
+		t.Errorf("Expected Closed, got %v", cb.State())
+	}
+}
+
+func TestWithForceProbeEligible_Probes(t *testing.T) {
+	clk := newFakeClock()
+	cb := newWithClock(clk, tripOnFirstFailure(Settings{Name: "force-probe", Timeout: time.Second}))
+	cb.Execute(failFunc)
+	clk.advance(2 * time.Second)
+
+	if _, err := cb.ExecuteContextWithOpts(context.Background(), ineligible, successFunc); !errors.Is(err, ErrTooManyRequests) {
+		t.Fatalf("Expected an ineligible call rejected in HalfOpen, got %v", err)
+	}
+
+	ctx := WithForceProbeEligible(context.Background())
+	if _, err := cb.ExecuteContextWithOpts(ctx, ineligible, successFunc); err != nil {
+		t.Fatalf("Expected the forced call admitted as a probe, got %v", err)
+	}
+	if cb.State() != StateClosed {
+		t.Errorf("Expected the probe to close the circuit, got %v", cb.State())
+	}
+}
+
+func TestDirectives_Combine(t *testing.T) {
+	ctx := WithBypass(WithOutcomeIgnored(WithForceProbeEligible(context.Background())))
+	want := directiveBypass | directiveProbeEligible | directiveIgnoreOutcome
+	if got := directivesOf(ctx); got != want {
+		t.Errorf("Expected all directives, got %b", got)
+	}
+}
+
+// valueCountingContext counts Value lookups.
+type valueCountingContext struct {
+	context.Context
+	values int
+}
+
+func (c *valueCountingContext) Value(key interface{}) interface{} {
+	c.values++
+	return c.Context.Value(key)
+}
+
+func TestDirectives_NoLookupUntilUsed(t *testing.T) {
+	used := directivesUsed.Load()
+	directivesUsed.Store(false)
+	t.Cleanup(func() { directivesUsed.Store(used) })
+
+	cb := New(Settings{Name: "no-lookup"})
+	ctx := &valueCountingContext{Context: context.Background()}
+	cb.ExecuteContext(ctx, successFunc)
+	if ctx.values != 0 {
+		t.Errorf("Expected no ctx.Value calls before any directive is set, got %d", ctx.values)
+	}
+
+	// Once set anywhere, a single lookup reads all directives
+	WithBypass(context.Background())
+	cb.ExecuteContext(ctx, successFunc)
+	if ctx.values != 1 {
+		t.Errorf("Expected one ctx.Value call, got %d", ctx.values)
+	}
+}
@@ -0,0 +1,75 @@
+package breaker
+
+// Disable takes the breaker out of the request path: Execute() and
+// ExecuteContext() run every request directly, without admission checks or
+// accounting, until Enable() is called.
+//
+// While disabled:
+//
+//   - Every request runs, even if the circuit is Open or a dependency is open
+//   - Nothing is counted, so expected errors can't trip the circuit or pollute
+//     the observation window; no callbacks fire and no latency is recorded
+//   - Record() observations are discarded, and calls that started before
+//     Disable() and complete afterwards are not recorded either
+//   - State() reports StateDisabled; the underlying state is frozen and
+//     reported by Metrics().MachineState
+//
+// Unlike EnterMaintenance, which keeps rejecting requests according to the
+// current state, Disable passes all traffic through unconditionally. Use it
+// when the breaker itself must get out of the way, e.g. during planned
+// maintenance of a dependency whose errors are expected.
+//
+// Metrics().Disabled and Diagnostics().Disabled report the mode, and
+// OnDisabledChange is called if the mode changed. Calling Disable() while
+// already disabled has no effect.
+//
+// Thread-safe: Can be called concurrently with Execute() and other methods.
+//
+// Example:
+//
+//	breaker.Disable()
+//	defer breaker.Enable()
+//	runDependencyMaintenance()
+func (cb *CircuitBreaker) Disable() {
+	if !cb.setDisabled(true) {
+		return
+	}
+	safeCallOnDisabledChange(cb.name, cb.onDisabledChange, true)
+}
+
+// Enable restores normal operation after Disable().
+//
+// Counts are cleared so the breaker starts from a clean observation window;
+// the state is unchanged. An Open circuit whose Timeout elapsed while disabled
+// transitions to HalfOpen on the next request.
+//
+// Calling Enable() when not disabled has no effect.
+//
+// Thread-safe: Can be called concurrently with Execute() and other methods.
+func (cb *CircuitBreaker) Enable() {
+	if !cb.setDisabled(false) {
+		return
+	}
+	cb.resetCounts()
+	safeCallOnDisabledChange(cb.name, cb.onDisabledChange, false)
+}
+
+// Disabled reports whether the breaker is disabled (see Disable).
+func (cb *CircuitBreaker) Disabled() bool {
+	return cb.state.Load()&disabledBit != 0
+}
+
+// setDisabled sets the disabled flag in the state word, leaving the state and
+// the transition epoch unchanged. Returns false if the flag already had that
+// value.
+func (cb *CircuitBreaker) setDisabled(disabled bool) bool {
+	for {
+		word := cb.state.Load()
+		if (word&disabledBit != 0) == disabled {
+			return false
+		}
+		if cb.state.CompareAndSwap(word, word^disabledBit) {
+			return true
+		}
+	}
+}
@@ -0,0 +1,230 @@
+package breaker
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestDisable_FailuresNeverTrip(t *testing.T) {
+	var stateChanges int
+	cb := New(Settings{
+		Name:    "dep",
+		Timeout: time.Minute,
+		OnStateChange: func(string, State, State) {
+			stateChanges++
+		},
+	})
+
+	cb.Disable()
+	for i := 0; i < 50; i++ {
+		if _, err := cb.Execute(failFunc); err == nil {
+			t.Fatal("Expected the request's own error")
+		}
+		cb.ExecuteContext(context.Background(), failFunc)
+		cb.Record(false)
+	}
+
+	if cb.State() != StateDisabled {
+		t.Errorf("Expected Disabled while disabled, got %v", cb.State())
+	}
+	if got := cb.Metrics().MachineState; got != StateClosed {
+		t.Errorf("Expected the state machine frozen Closed, got %v", got)
+	}
+	if counts := cb.Counts(); counts.Requests != 0 || counts.TotalFailures != 0 {
+		t.Errorf("Expected no accounting while disabled, got %+v", counts)
+	}
+	if stateChanges != 0 {
+		t.Errorf("Expected no state changes while disabled, got %d", stateChanges)
+	}
+}
+
+func TestDisable_PassesThroughOpenCircuit(t *testing.T) {
+	cb := New(Settings{
+		Name:    "dep",
+		Timeout: time.Minute,
+		ReadyToTrip: func(counts Counts) bool {
+			return counts.ConsecutiveFailures >= 1
+		},
+	})
+	cb.Execute(failFunc)
+	if cb.State() != StateOpen {
+		t.Fatalf("Expected Open, got %v", cb.State())
+	}
+
+	cb.Disable()
+	result, err := cb.Execute(successFunc)
+	if err != nil || result != "success" {
+		t.Errorf("Expected request to run while disabled, got %v, %v", result, err)
+	}
+
+	// State() reports the bypass; MachineState keeps the frozen state
+	m := cb.Metrics()
+	if m.State != StateDisabled || m.MachineState != StateOpen || !m.Disabled {
+		t.Errorf("Expected Disabled over a frozen Open, got state=%v machine=%v disabled=%v", m.State, m.MachineState, m.Disabled)
+	}
+	if d := cb.Diagnostics(); !d.Disabled {
+		t.Error("Expected Diagnostics().Disabled")
+	}
+	if s := cb.AlertSummary(); !strings.HasSuffix(s, ", disabled (bypassing)") {
+		t.Errorf("Expected AlertSummary to flag the bypass, got %q", s)
+	}
+
+	cb.Enable()
+	if _, err := cb.Execute(successFunc); !errors.Is(err, ErrOpenState) {
+		t.Errorf("Expected ErrOpenState after Enable, got %v", err)
+	}
+}
+
+func TestEnable_StartsCleanWindow(t *testing.T) {
+	cb := New(Settings{Name: "dep"})
+
+	cb.Execute(successFunc)
+	cb.Execute(failFunc)
+	cb.Disable()
+
+	// A call in flight across Disable() is not recorded
+	cb.Execute(failFunc)
+
+	cb.Enable()
+	if counts := cb.Counts(); counts != (Counts{}) {
+		t.Errorf("Expected cleared counts after Enable, got %+v", counts)
+	}
+	if cb.Metrics().Disabled {
+		t.Error("Expected Disabled cleared after Enable")
+	}
+
+	cb.Execute(failFunc)
+	if counts := cb.Counts(); counts.Requests != 1 || counts.TotalFailures != 1 {
+		t.Errorf("Expected normal accounting after Enable, got %+v", counts)
+	}
+}
+
+func TestDisable_InFlightCallNotRecorded(t *testing.T) {
+	cb := New(Settings{Name: "dep"})
+
+	cb.Execute(func() (interface{}, error) {
+		cb.Disable()
+		return nil, errors.New("expected during maintenance")
+	})
+
+	if counts := cb.Counts(); counts.Requests != 0 || counts.TotalFailures != 0 {
+		t.Errorf("Expected in-flight outcome discarded, got %+v", counts)
+	}
+}
+
+func TestOnDisabledChange(t *testing.T) {
+	var events []bool
+	cb := New(Settings{
+		Name: "dep",
+		OnDisabledChange: func(name string, disabled bool) {
+			if name != "dep" {
+				t.Errorf("Expected name dep, got %q", name)
+			}
+			events = append(events, disabled)
+		},
+	})
+
+	cb.Enable() // no-op: not disabled
+	cb.Disable()
+	cb.Disable() // no-op: already disabled
+	cb.Enable()
+
+	if len(events) != 2 || !events[0] || events[1] {
+		t.Errorf("Expected [true false], got %v", events)
+	}
+}
+
+func TestOnDisabledChange_PanicRecovered(t *testing.T) {
+	cb := New(Settings{
+		Name: "dep",
+		OnDisabledChange: func(string, bool) {
+			panic("audit sink down")
+		},
+	})
+
+	cb.Disable()
+	if !cb.Disabled() {
+		t.Error("Expected Disable to take effect despite callback panic")
+	}
+	cb.Enable()
+	if cb.Disabled() {
+		t.Error("Expected Enable to take effect despite callback panic")
+	}
+}
+
+func TestDisable_ReportsStateDisabled(t *testing.T) {
+	cb := New(tripOnFirstFailure(Settings{Name: "disabled-state", Timeout: time.Minute}))
+	cb.Execute(failFunc)
+
+	cb.Disable()
+	if got := cb.State().String(); got != "disabled" {
+		t.Errorf("Expected State().String() disabled, got %q", got)
+	}
+	d := cb.Diagnostics()
+	if d.State != StateDisabled || d.Metrics.State != StateDisabled || d.Metrics.MachineState != StateOpen {
+		t.Errorf("Expected Disabled over a frozen Open, got state=%v machine=%v", d.State, d.Metrics.MachineState)
+	}
+	if d.OpenReason.Kind != OpenReasonReadyToTrip {
+		t.Errorf("Expected the frozen open reason kept, got %v", d.OpenReason.Kind)
+	}
+
+	cb.Enable()
+	if cb.State() != StateOpen {
+		t.Errorf("Expected the frozen Open state after Enable, got %v", cb.State())
+	}
+	if _, err := cb.Execute(successFunc); !errors.Is(err, ErrOpenState) {
+		t.Errorf("Expected ErrOpenState after Enable, got %v", err)
+	}
+}
+
+func TestDisable_EffectiveStateIgnoresDependencies(t *testing.T) {
+	db := New(tripOnFirstFailure(Settings{Name: "db", Timeout: time.Minute}))
+	reports := New(Settings{Name: "reports"})
+	if err := reports.DependsOn(db); err != nil {
+		t.Fatal(err)
+	}
+	db.Execute(failFunc)
+
+	reports.Disable()
+	if got := reports.EffectiveState(); got != StateDisabled {
+		t.Errorf("Expected EffectiveState Disabled, got %v", got)
+	}
+	if _, err := reports.Execute(successFunc); err != nil {
+		t.Errorf("Expected the request to bypass the open dependency, got %v", err)
+	}
+}
+
+func TestMaintenance_KeepsOrganicState(t *testing.T) {
+	cb := New(tripOnFirstFailure(Settings{Name: "maintenance-state", Timeout: time.Minute}))
+	cb.Execute(failFunc)
+
+	cb.EnterMaintenance()
+	defer cb.ExitMaintenance()
+
+	// Admission still follows the state, so the state is still reported
+	if cb.State() != StateOpen {
+		t.Errorf("Expected Open during maintenance, got %v", cb.State())
+	}
+	if _, err := cb.Execute(successFunc); !errors.Is(err, ErrOpenState) {
+		t.Errorf("Expected ErrOpenState during maintenance, got %v", err)
+	}
+}
+
+func TestDisable_GroupCountsDisabledAsAvailable(t *testing.T) {
+	g := NewGroup(tripOnFirstFailure(Settings{Name: "svc", Timeout: time.Minute}), []string{"a", "b"})
+	g.Breaker("a").Execute(failFunc)
+	g.Breaker("b").Execute(failFunc)
+
+	g.Breaker("a").Disable()
+
+	if keys := g.HealthyKeys(); len(keys) != 1 || keys[0] != "a" {
+		t.Errorf("Expected the disabled breaker healthy, got %v", keys)
+	}
+	m := g.Metrics()
+	if m.State != StateDisabled || m.MachineState != StateOpen {
+		t.Errorf("Expected group state Disabled over Open machines, got state=%v machine=%v", m.State, m.MachineState)
+	}
+}
@@ -383,7 +383,7 @@ func TestCallbackPanicIsSuccessful(t *testing.T) {
 	})
 
 	// Execute a request - isSuccessful will be called
-	// The panic should be recovered and treated as failure
+	// The panic should be recovered and the call classified by err == nil
 	func() {
 		defer func() {
 			if r := recover(); r != nil {
@@ -403,10 +403,14 @@ func TestCallbackPanicIsSuccessful(t *testing.T) {
 		t.Errorf("Circuit should be closed after callback panic, got %v", cb.State())
 	}
 
-	// The request should be counted as failure (panic in isSuccessful)
+	// The request succeeded, so it should be counted as a success, not as a
+	// failure of the classifier
 	counts := cb.Counts()
-	if counts.TotalFailures != 1 {
-		t.Errorf("Request with panicking isSuccessful should count as failure, got %d failures", counts.TotalFailures)
+	if counts.TotalFailures != 0 || counts.TotalSuccesses != 1 {
+		t.Errorf("Successful request with panicking isSuccessful should count as success, got %+v", counts)
+	}
+	if got := cb.Metrics().ClassifierPanics; got != 1 {
+		t.Errorf("Expected the classifier panic counted, got %d", got)
 	}
 
 	// Should be able to continue using circuit
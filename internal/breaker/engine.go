@@ -0,0 +1,117 @@
+package breaker
+
+import (
+	"fmt"
+	"time"
+)
+
+// DecisionEngine decides when a closed circuit trips and, optionally, which
+// requests are admitted. It replaces the trip rule (ReadyToTrip, AdaptiveThreshold)
+// with an alternative algorithm, such as EWMAEngine or client-side throttling.
+//
+// The state machine is unchanged: the engine decides Closed → Open and may
+// shed requests, while Timeout, half-open probing and recovery work as usual.
+//
+// The breaker calls the engine as follows:
+//
+//   - OnOutcome for every outcome recorded while Closed (ignored outcomes and
+//     HalfOpen probes are not reported)
+//   - ShouldTrip after each failure recorded while Closed
+//   - ShouldAdmit for every request in Closed or HalfOpen, after the state
+//     checks and before the request is counted
+//   - Reset when the circuit opens and when it closes again
+//
+// Count clearing (Interval) does not reset an engine; engines keep their own
+// notion of time from the now passed to OnOutcome and ShouldAdmit.
+//
+// Engine calls are panic-safe: a panic is logged and treated as "don't trip"
+// (ShouldTrip) or "admit" (ShouldAdmit), and otherwise ignored.
+//
+// Thread-Safety: Engines are called concurrently from every goroutine using
+// the breaker and must be safe for concurrent use. An engine holds per-circuit
+// state: use a separate instance for each breaker.
+type DecisionEngine interface {
+	// OnOutcome records the outcome of a request completed at now.
+	OnOutcome(success bool, now time.Time)
+
+	// ShouldTrip reports whether the circuit should open.
+	ShouldTrip() bool
+
+	// ShouldAdmit reports whether a request may run in state (StateClosed or
+	// StateHalfOpen) at now. A rejected request is not counted; the caller
+	// gets reason, or ErrOpenState if reason is nil.
+	ShouldAdmit(state State, now time.Time) (admit bool, reason error)
+
+	// Reset forgets all observations.
+	Reset()
+}
+
+// countsEngine is the default engine built from Settings: the configured trip
+// rule (static, ConsecutiveFailureThreshold, adaptive or ReadyToTrip) evaluated
+// on the breaker's Counts. Counts are recorded and cleared by the breaker, so
+// it has no state of its own.
+type countsEngine struct {
+	cb *CircuitBreaker
+}
+
+func (e *countsEngine) OnOutcome(success bool, now time.Time) {}
+
+func (e *countsEngine) ShouldTrip() bool {
+	return e.shouldTripOn(e.cb.Counts())
+}
+
+// shouldTripOn evaluates the trip rule on counts already loaded by the caller.
+func (e *countsEngine) shouldTripOn(counts Counts) bool {
+	return safeCallReadyToTrip(e.cb.name, e.cb.readyToTrip, counts)
+}
+
+func (e *countsEngine) ShouldAdmit(state State, now time.Time) (bool, error) {
+	return true, nil
+}
+
+func (e *countsEngine) Reset() {}
+
+// shouldTrip evaluates the decision engine after a failure while Closed.
+func (cb *CircuitBreaker) shouldTrip(counts Counts) bool {
+	if e, ok := cb.engine.(*countsEngine); ok {
+		return e.shouldTripOn(counts)
+	}
+	return safeCallEngineShouldTrip(cb.name, cb.engine)
+}
+
+// engineAdmit asks a custom decision engine whether a request may run in
+// state. Returns nil if it may, otherwise the error to reject it with.
+func (cb *CircuitBreaker) engineAdmit(a *admission, state State) error {
+	admit, reason := safeCallEngineShouldAdmit(cb.name, cb.engine, state, time.Unix(0, cb.now()))
+	if admit {
+		return nil
+	}
+	if reason == nil {
+		return a.reject(rejectedOpen, ErrOpenState)
+	}
+	return reason
+}
+
+// engineOutcome reports an outcome recorded while Closed to a custom engine.
+func (cb *CircuitBreaker) engineOutcome(success bool) {
+	if cb.customEngine {
+		safeCallEngine(cb.name, "OnOutcome", func() { cb.engine.OnOutcome(success, time.Unix(0, cb.now())) })
+	}
+}
+
+// resetEngine resets a custom engine on a transition out of, or back into, Closed.
+func (cb *CircuitBreaker) resetEngine() {
+	if cb.customEngine {
+		safeCallEngine(cb.name, "Reset", cb.engine.Reset)
+	}
+}
+
+// engineTripReason describes a trip decided by a custom engine.
+func (cb *CircuitBreaker) engineTripReason(counts Counts) *OpenReason {
+	return &OpenReason{
+		Kind: OpenReasonEngine,
+		Detail: fmt.Sprintf("decision engine %T tripped (%d/%d failed, %d consecutive)",
+			cb.engine, counts.TotalFailures, counts.Requests, counts.ConsecutiveFailures),
+		Counts: counts,
+	}
+}
@@ -0,0 +1,350 @@
+package breaker
+
+import (
+	"errors"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// scriptedEngine is a DecisionEngine whose decisions are set by the test.
+type scriptedEngine struct {
+	trip     atomic.Bool
+	reject   atomic.Bool
+	outcomes atomic.Int32
+	resets   atomic.Int32
+	panics   bool
+}
+
+var errShed = errors.New("shed by engine")
+
+func (e *scriptedEngine) OnOutcome(success bool, now time.Time) {
+	if e.panics {
+		panic("engine panic")
+	}
+	e.outcomes.Add(1)
+}
+
+func (e *scriptedEngine) ShouldTrip() bool {
+	if e.panics {
+		panic("engine panic")
+	}
+	return e.trip.Load()
+}
+
+func (e *scriptedEngine) ShouldAdmit(state State, now time.Time) (bool, error) {
+	if e.panics {
+		panic("engine panic")
+	}
+	if e.reject.Load() {
+		return false, errShed
+	}
+	return true, nil
+}
+
+func (e *scriptedEngine) Reset() { e.resets.Add(1) }
+
+func TestEngine_DecidesTrip(t *testing.T) {
+	engine := &scriptedEngine{}
+	cb := New(Settings{Name: "engine-trip", Engine: engine, Timeout: 10 * time.Millisecond})
+
+	// Far past the default rule's 5 consecutive failures
+	for i := 0; i < 10; i++ {
+		cb.Execute(failFunc)
+	}
+	if cb.State() != StateClosed {
+		t.Fatalf("Expected the engine, not the default rule, to decide; got %v", cb.State())
+	}
+	if got := engine.outcomes.Load(); got != 10 {
+		t.Errorf("Expected 10 outcomes reported, got %d", got)
+	}
+
+	engine.trip.Store(true)
+	cb.Execute(failFunc)
+	if cb.State() != StateOpen {
+		t.Fatalf("Expected the engine to trip the circuit, got %v", cb.State())
+	}
+	if reason := cb.Diagnostics().OpenReason; reason.Kind != OpenReasonEngine || !strings.Contains(reason.Detail, "scriptedEngine") {
+		t.Errorf("Expected an engine open reason, got %+v", reason)
+	}
+	if got := engine.resets.Load(); got != 1 {
+		t.Errorf("Expected a reset on opening, got %d", got)
+	}
+
+	// Recovery is unchanged and resets the engine again
+	engine.trip.Store(false)
+	time.Sleep(20 * time.Millisecond)
+	cb.Execute(successFunc)
+	if cb.State() != StateClosed {
+		t.Fatalf("Expected recovery through half-open, got %v", cb.State())
+	}
+	if got := engine.resets.Load(); got != 2 {
+		t.Errorf("Expected a reset on closing, got %d", got)
+	}
+	if cb.Diagnostics().WillTripNext {
+		t.Error("Expected WillTripNext to be false with an engine")
+	}
+}
+
+func TestEngine_ShedsRequests(t *testing.T) {
+	engine := &scriptedEngine{}
+	cb := New(Settings{Name: "engine-shed", Engine: engine})
+	engine.reject.Store(true)
+
+	ran := false
+	_, err := cb.Execute(func() (interface{}, error) {
+		ran = true
+		return nil, nil
+	})
+	if !errors.Is(err, errShed) {
+		t.Fatalf("Expected the engine's reason, got %v", err)
+	}
+	if ran || cb.Counts().Requests != 0 {
+		t.Errorf("Expected a shed request neither to run nor to count (ran=%v, counts=%+v)", ran, cb.Counts())
+	}
+}
+
+func TestEngine_PanicsAreContained(t *testing.T) {
+	cb := New(Settings{Name: "engine-panic", Engine: &scriptedEngine{panics: true}})
+
+	out := captureStdout(t, func() {
+		if _, err := cb.Execute(failFunc); err == nil || err.Error() != "operation failed" {
+			t.Errorf("Expected the request to run despite the panicking engine, got %v", err)
+		}
+	})
+	if cb.State() != StateClosed {
+		t.Errorf("Expected a panicking engine not to trip, got %v", cb.State())
+	}
+	if !strings.Contains(out, "DecisionEngine.ShouldTrip panicked") {
+		t.Errorf("Expected the panic to be logged, got %q", out)
+	}
+}
+
+func TestEngine_DefaultIsCountsEngine(t *testing.T) {
+	cb := New(Settings{Name: "engine-default"})
+	if _, ok := cb.engine.(*countsEngine); !ok {
+		t.Fatalf("Expected the counts engine by default, got %T", cb.engine)
+	}
+	if cb.CurrentSettings().Engine != nil {
+		t.Error("Expected CurrentSettings().Engine to be nil by default")
+	}
+
+	engine := NewEWMAEngine(0, 0, 0)
+	if got := New(Settings{Name: "engine-custom", Engine: engine}).CurrentSettings().Engine; got != engine {
+		t.Errorf("Expected CurrentSettings to return the engine, got %v", got)
+	}
+}
+
+func TestEWMAEngine_DecaysWithHalfLife(t *testing.T) {
+	e := NewEWMAEngine(time.Second, 0.5, 1)
+	t0 := time.Unix(0, 0)
+
+	e.OnOutcome(false, t0)
+	e.OnOutcome(true, t0.Add(time.Second)) // The failure now weighs 1/2
+
+	if got, want := e.FailureRate(), 0.5/1.5; got < want-1e-9 || got > want+1e-9 {
+		t.Errorf("Expected failure rate %v, got %v", want, got)
+	}
+
+	e.Reset()
+	if e.FailureRate() != 0 || e.ShouldTrip() {
+		t.Error("Expected Reset to forget all outcomes")
+	}
+}
+
+func TestNewEWMAEngine_Invalid(t *testing.T) {
+	for name, build := range map[string]func(){
+		"negative half-life": func() { NewEWMAEngine(-time.Second, 0.1, 10) },
+		"rate of 1":          func() { NewEWMAEngine(time.Second, 1, 10) },
+	} {
+		t.Run(name, func(t *testing.T) {
+			defer func() {
+				if recover() == nil {
+					t.Error("Expected a panic")
+				}
+			}()
+			build()
+		})
+	}
+}
+
+// outcomeAt is one request in a traffic trace.
+type outcomeAt struct {
+	at      time.Duration
+	success bool
+}
+
+// burst returns n outcomes at the given offset.
+func burst(at time.Duration, n int, success bool) []outcomeAt {
+	trace := make([]outcomeAt, n)
+	for i := range trace {
+		trace[i] = outcomeAt{at, success}
+	}
+	return trace
+}
+
+// replayTrips replays trace into the EWMA engine and into a breaker's
+// fixed-window adaptive engine (counts cleared every window), and returns the
+// index of the outcome that tripped each, or -1.
+func replayTrips(t *testing.T, ewma *EWMAEngine, rate float64, minObs uint32, window time.Duration, trace []outcomeAt) (ewmaTrip, fixedTrip int) {
+	t.Helper()
+	fixed := New(Settings{
+		Name:                 "fixed-window",
+		AdaptiveThreshold:    true,
+		FailureRateThreshold: rate,
+		MinimumObservations:  minObs,
+	})
+	start := time.Unix(0, 0)
+
+	ewmaTrip, fixedTrip = -1, -1
+	windowEnd := window
+	for i, o := range trace {
+		if ewmaTrip < 0 {
+			ewma.OnOutcome(o.success, start.Add(o.at))
+			if !o.success && ewma.ShouldTrip() {
+				ewmaTrip = i
+			}
+		}
+		if fixedTrip < 0 {
+			for o.at >= windowEnd {
+				fixed.clearCounts()
+				windowEnd += window
+			}
+			fixed.safeIncrementRequests()
+			fixed.recordOutcome(o.success)
+			if !o.success && fixed.engine.ShouldTrip() {
+				fixedTrip = i
+			}
+		}
+	}
+	return ewmaTrip, fixedTrip
+}
+
+func TestEWMAEngine_ComparedToFixedWindow(t *testing.T) {
+	const rate, minObs = 0.10, 20
+
+	t.Run("without decay both trip on the same request", func(t *testing.T) {
+		// 10 healthy requests, then 1 failure in 5: crosses 10% at 20 requests
+		var trace []outcomeAt
+		trace = append(trace, burst(0, 10, true)...)
+		for i := 0; i < 10; i++ {
+			trace = append(trace, burst(0, 4, true)...)
+			trace = append(trace, burst(0, 1, false)...)
+		}
+		ewmaTrip, fixedTrip := replayTrips(t, NewEWMAEngine(time.Hour, rate, minObs), rate, minObs, time.Hour, trace)
+		if ewmaTrip < 0 || ewmaTrip != fixedTrip {
+			t.Errorf("Expected both to trip on the same request, got EWMA %d, fixed window %d", ewmaTrip, fixedTrip)
+		}
+	})
+
+	t.Run("steady low failure rate trips neither", func(t *testing.T) {
+		var trace []outcomeAt
+		for i := 0; i < 50; i++ {
+			at := time.Duration(i) * 100 * time.Millisecond
+			trace = append(trace, burst(at, 49, true)...)
+			trace = append(trace, burst(at, 1, false)...)
+		}
+		ewmaTrip, fixedTrip := replayTrips(t, NewEWMAEngine(time.Second, rate, minObs), rate, minObs, 10*time.Second, trace)
+		if ewmaTrip >= 0 || fixedTrip >= 0 {
+			t.Errorf("Expected no trip at 2%%, got EWMA %d, fixed window %d", ewmaTrip, fixedTrip)
+		}
+	})
+
+	t.Run("burst split by a window boundary trips only EWMA", func(t *testing.T) {
+		// Each window sees too few requests, or too diluted a rate, to trip
+		var trace []outcomeAt
+		for i := 0; i < 45; i++ {
+			trace = append(trace, burst(time.Duration(i)*200*time.Millisecond, 1, true)...)
+		}
+		trace = append(trace, burst(9500*time.Millisecond, 3, false)...)
+		trace = append(trace, burst(10100*time.Millisecond, 6, false)...)
+
+		ewmaTrip, fixedTrip := replayTrips(t, NewEWMAEngine(2*time.Second, rate, 10), rate, 10, 10*time.Second, trace)
+		if ewmaTrip < 0 {
+			t.Error("Expected EWMA to trip on the recent burst")
+		}
+		if fixedTrip >= 0 {
+			t.Errorf("Expected the fixed window to miss the split burst, tripped at %d", fixedTrip)
+		}
+	})
+
+	t.Run("stale failures trip only the fixed window", func(t *testing.T) {
+		// Failures early in the window still count 30s later for the fixed window
+		var trace []outcomeAt
+		trace = append(trace, burst(0, 20, true)...)
+		trace = append(trace, burst(time.Second, 2, false)...)
+		trace = append(trace, burst(31*time.Second, 1, false)...)
+
+		ewmaTrip, fixedTrip := replayTrips(t, NewEWMAEngine(5*time.Second, rate, minObs), rate, minObs, time.Minute, trace)
+		if fixedTrip != len(trace)-1 {
+			t.Errorf("Expected the fixed window to trip on the last failure, got %d", fixedTrip)
+		}
+		if ewmaTrip >= 0 {
+			t.Errorf("Expected EWMA to have forgotten the old failures, tripped at %d", ewmaTrip)
+		}
+	})
+}
+
+func TestEWMAEngine_InBreaker(t *testing.T) {
+	cb := New(Settings{Name: "ewma", Engine: NewEWMAEngine(time.Minute, 0.10, 20)})
+
+	for i := 0; i < 18; i++ {
+		cb.Execute(successFunc)
+	}
+	cb.Execute(failFunc)
+	if cb.State() != StateClosed {
+		t.Fatalf("Expected no trip below MinimumObservations, got %v", cb.State())
+	}
+	cb.Execute(failFunc)
+	if cb.State() != StateClosed {
+		t.Fatalf("Expected no trip at exactly 10%%, got %v", cb.State())
+	}
+	cb.Execute(failFunc)
+	if cb.State() != StateOpen {
+		t.Errorf("Expected a trip at 3/21 failures, got %v", cb.State())
+	}
+}
+
+// timedEngine records the times the breaker passes to it.
+type timedEngine struct {
+	scriptedEngine
+	mu    sync.Mutex
+	times []time.Time
+}
+
+func (e *timedEngine) OnOutcome(success bool, now time.Time) {
+	e.mu.Lock()
+	e.times = append(e.times, now)
+	e.mu.Unlock()
+}
+
+func (e *timedEngine) ShouldAdmit(state State, now time.Time) (bool, error) {
+	e.mu.Lock()
+	e.times = append(e.times, now)
+	e.mu.Unlock()
+	return true, nil
+}
+
+func TestEngine_UsesBreakerClock(t *testing.T) {
+	clk := newFakeClock()
+	engine := &timedEngine{}
+	cb := newWithClock(clk, Settings{Name: "engine-clock", Engine: engine})
+
+	start := time.Unix(0, clk.wallNow())
+	cb.Execute(successFunc)
+	clk.advance(time.Hour)
+	cb.Execute(successFunc)
+
+	want := []time.Time{start, start, start.Add(time.Hour), start.Add(time.Hour)}
+	if len(engine.times) != len(want) {
+		t.Fatalf("Expected %d engine calls, got %v", len(want), engine.times)
+	}
+	for i := range want {
+		if !engine.times[i].Equal(want[i]) {
+			t.Errorf("Expected the breaker's clock passed to the engine, got %v want %v", engine.times, want)
+			break
+		}
+	}
+}
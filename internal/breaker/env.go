@@ -0,0 +1,215 @@
+package breaker
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Environment variable suffixes read by SettingsFromEnv and SettingsUpdateFromEnv.
+// The full name is <PREFIX>_<NAME>_<SUFFIX>, e.g. AUTOBREAKER_PAYMENTS_TIMEOUT.
+const (
+	envMaxRequests      = "MAX_REQUESTS"      // MaxRequests (uint32)
+	envInterval         = "INTERVAL"          // Interval (time.ParseDuration)
+	envTimeout          = "TIMEOUT"           // Timeout (time.ParseDuration)
+	envAdaptive         = "ADAPTIVE"          // AdaptiveThreshold (strconv.ParseBool); Settings only
+	envFailureRate      = "FAILURE_RATE"      // FailureRateThreshold (float64)
+	envMinObservations  = "MIN_OBSERVATIONS"  // MinimumObservations (uint32)
+	envWarningThreshold = "WARNING_THRESHOLD" // WarningThresholdFraction (float64)
+	envRecoveryRate     = "RECOVERY_RATE"     // RecoverFailureRate (float64)
+)
+
+// SettingsFromEnv builds Settings for the breaker called name from environment
+// variables named <prefix>_<NAME>_<FIELD>, where NAME is name upper-cased with
+// every character other than a letter or digit replaced by an underscore:
+//
+//	<prefix>_<NAME>_MAX_REQUESTS       MaxRequests (uint32)
+//	<prefix>_<NAME>_INTERVAL           Interval (duration, e.g. 10s)
+//	<prefix>_<NAME>_TIMEOUT            Timeout (duration)
+//	<prefix>_<NAME>_ADAPTIVE           AdaptiveThreshold (bool: true, false, 1, 0)
+//	<prefix>_<NAME>_FAILURE_RATE       FailureRateThreshold (float, e.g. 0.05)
+//	<prefix>_<NAME>_MIN_OBSERVATIONS   MinimumObservations (uint32)
+//	<prefix>_<NAME>_WARNING_THRESHOLD  WarningThresholdFraction (float)
+//	<prefix>_<NAME>_RECOVERY_RATE      RecoverFailureRate (float)
+//
+// Unset or empty variables leave their field zero (New() applies its
+// defaults); Name is set to name. A malformed value returns an error naming the
+// variable. Values are only parsed, not validated: New() or ValidateSettings()
+// range-check them.
+//
+// Example:
+//
+//	// AUTOBREAKER_PAYMENTS_TIMEOUT=30s AUTOBREAKER_PAYMENTS_FAILURE_RATE=0.05
+//	settings, err := autobreaker.SettingsFromEnv("AUTOBREAKER", "payments")
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	breaker := autobreaker.New(settings)
+func SettingsFromEnv(prefix, name string) (Settings, error) {
+	update, err := SettingsUpdateFromEnv(prefix, name)
+	if err != nil {
+		return Settings{}, err
+	}
+
+	settings := Settings{Name: name}
+	if update.MaxRequests != nil {
+		settings.MaxRequests = *update.MaxRequests
+	}
+	if update.Interval != nil {
+		settings.Interval = *update.Interval
+	}
+	if update.Timeout != nil {
+		settings.Timeout = *update.Timeout
+	}
+	if update.FailureRateThreshold != nil {
+		settings.FailureRateThreshold = *update.FailureRateThreshold
+	}
+	if update.MinimumObservations != nil {
+		settings.MinimumObservations = *update.MinimumObservations
+	}
+	if update.WarningThresholdFraction != nil {
+		settings.WarningThresholdFraction = *update.WarningThresholdFraction
+	}
+	if update.RecoverFailureRate != nil {
+		settings.RecoverFailureRate = *update.RecoverFailureRate
+	}
+
+	key := envKey(prefix, name, envAdaptive)
+	if value, ok := lookupEnv(key); ok {
+		adaptive, err := strconv.ParseBool(value)
+		if err != nil {
+			return Settings{}, envError(key, value, "a boolean", err)
+		}
+		settings.AdaptiveThreshold = adaptive
+	}
+	return settings, nil
+}
+
+// SettingsUpdateFromEnv reads the runtime-updatable settings of the breaker
+// called name from the variables documented on SettingsFromEnv. An unset or
+// empty variable leaves its field nil (unchanged by UpdateSettings).
+// <prefix>_<NAME>_ADAPTIVE is not read: AdaptiveThreshold cannot change at
+// runtime.
+//
+// Example:
+//
+//	update, err := autobreaker.SettingsUpdateFromEnv("AUTOBREAKER", "payments")
+//	if err == nil {
+//	    err = breaker.UpdateSettings(update)
+//	}
+func SettingsUpdateFromEnv(prefix, name string) (SettingsUpdate, error) {
+	var update SettingsUpdate
+	var err error
+	if update.MaxRequests, err = envUint32(envKey(prefix, name, envMaxRequests)); err != nil {
+		return SettingsUpdate{}, err
+	}
+	if update.Interval, err = envDuration(envKey(prefix, name, envInterval)); err != nil {
+		return SettingsUpdate{}, err
+	}
+	if update.Timeout, err = envDuration(envKey(prefix, name, envTimeout)); err != nil {
+		return SettingsUpdate{}, err
+	}
+	if update.FailureRateThreshold, err = envFloat64(envKey(prefix, name, envFailureRate)); err != nil {
+		return SettingsUpdate{}, err
+	}
+	if update.MinimumObservations, err = envUint32(envKey(prefix, name, envMinObservations)); err != nil {
+		return SettingsUpdate{}, err
+	}
+	if update.WarningThresholdFraction, err = envFloat64(envKey(prefix, name, envWarningThreshold)); err != nil {
+		return SettingsUpdate{}, err
+	}
+	if update.RecoverFailureRate, err = envFloat64(envKey(prefix, name, envRecoveryRate)); err != nil {
+		return SettingsUpdate{}, err
+	}
+	return update, nil
+}
+
+// ApplyEnvOverrides layers the environment variables for cb's name (see
+// SettingsFromEnv) on top of the settings cb was created with, through
+// UpdateSettings. Intended for startup: code sets the defaults, the deployment
+// overrides them. Does nothing if no variable is set; returns the parse or
+// validation error otherwise, leaving cb unchanged.
+//
+// Example:
+//
+//	breaker := autobreaker.New(autobreaker.Settings{Name: "payments", Timeout: 10 * time.Second})
+//	if err := autobreaker.ApplyEnvOverrides(breaker, "AUTOBREAKER"); err != nil {
+//	    log.Fatal(err)
+//	}
+func ApplyEnvOverrides(cb *CircuitBreaker, prefix string) error {
+	update, err := SettingsUpdateFromEnv(prefix, cb.Name())
+	if err != nil {
+		return err
+	}
+	if update == (SettingsUpdate{}) {
+		return nil
+	}
+	return cb.UpdateSettings(update)
+}
+
+// envKey returns the variable name <prefix>_<NAME>_<suffix>.
+func envKey(prefix, name, suffix string) string {
+	normalized := strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z':
+			return r - 'a' + 'A'
+		case r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+			return r
+		default:
+			return '_'
+		}
+	}, name)
+	return prefix + "_" + normalized + "_" + suffix
+}
+
+// lookupEnv returns the value of the variable key; set but empty counts as unset.
+func lookupEnv(key string) (string, bool) {
+	value, ok := os.LookupEnv(key)
+	return value, ok && value != ""
+}
+
+// envUint32 parses the uint32 variable key, nil if unset.
+func envUint32(key string) (*uint32, error) {
+	value, ok := lookupEnv(key)
+	if !ok {
+		return nil, nil
+	}
+	v, err := strconv.ParseUint(value, 10, 32)
+	if err != nil {
+		return nil, envError(key, value, "an unsigned 32-bit integer", err)
+	}
+	return Uint32Ptr(uint32(v)), nil
+}
+
+// envDuration parses the duration variable key, nil if unset.
+func envDuration(key string) (*time.Duration, error) {
+	value, ok := lookupEnv(key)
+	if !ok {
+		return nil, nil
+	}
+	v, err := time.ParseDuration(value)
+	if err != nil {
+		return nil, envError(key, value, "a duration", err)
+	}
+	return DurationPtr(v), nil
+}
+
+// envFloat64 parses the float variable key, nil if unset.
+func envFloat64(key string) (*float64, error) {
+	value, ok := lookupEnv(key)
+	if !ok {
+		return nil, nil
+	}
+	v, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return nil, envError(key, value, "a number", err)
+	}
+	return Float64Ptr(v), nil
+}
+
+// envError reports a malformed value of the variable key.
+func envError(key, value, want string, err error) error {
+	return fmt.Errorf("autobreaker: %s=%q is not %s: %w", key, value, want, err)
+}
@@ -0,0 +1,151 @@
+package breaker
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSettingsFromEnv_AllFields(t *testing.T) {
+	t.Setenv("AUTOBREAKER_PAYMENTS_API_MAX_REQUESTS", "3")
+	t.Setenv("AUTOBREAKER_PAYMENTS_API_INTERVAL", "10s")
+	t.Setenv("AUTOBREAKER_PAYMENTS_API_TIMEOUT", "30s")
+	t.Setenv("AUTOBREAKER_PAYMENTS_API_ADAPTIVE", "true")
+	t.Setenv("AUTOBREAKER_PAYMENTS_API_FAILURE_RATE", "0.05")
+	t.Setenv("AUTOBREAKER_PAYMENTS_API_MIN_OBSERVATIONS", "50")
+	t.Setenv("AUTOBREAKER_PAYMENTS_API_WARNING_THRESHOLD", "0.8")
+	t.Setenv("AUTOBREAKER_PAYMENTS_API_RECOVERY_RATE", "0.02")
+
+	// Punctuation in the name maps to underscores
+	settings, err := SettingsFromEnv("AUTOBREAKER", "payments-api")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if settings.Name != "payments-api" ||
+		settings.MaxRequests != 3 ||
+		settings.Interval != 10*time.Second ||
+		settings.Timeout != 30*time.Second ||
+		!settings.AdaptiveThreshold ||
+		settings.FailureRateThreshold != 0.05 ||
+		settings.MinimumObservations != 50 ||
+		settings.WarningThresholdFraction != 0.8 ||
+		settings.RecoverFailureRate != 0.02 {
+		t.Errorf("Unexpected settings: %+v", settings)
+	}
+
+	// The same variables round-trip into a breaker
+	cb := New(settings)
+	current := cb.CurrentSettings()
+	if current.Timeout != 30*time.Second || current.FailureRateThreshold != 0.05 || current.MaxRequests != 3 {
+		t.Errorf("Expected env settings applied, got %+v", current)
+	}
+}
+
+func TestSettingsUpdateFromEnv_AllFields(t *testing.T) {
+	t.Setenv("APP_USERS_MAX_REQUESTS", "4")
+	t.Setenv("APP_USERS_INTERVAL", "1m")
+	t.Setenv("APP_USERS_TIMEOUT", "5s")
+	t.Setenv("APP_USERS_FAILURE_RATE", "0.2")
+	t.Setenv("APP_USERS_MIN_OBSERVATIONS", "10")
+	t.Setenv("APP_USERS_WARNING_THRESHOLD", "0.5")
+
+	update, err := SettingsUpdateFromEnv("APP", "users")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	want := SettingsUpdate{
+		MaxRequests:              Uint32Ptr(4),
+		Interval:                 DurationPtr(time.Minute),
+		Timeout:                  DurationPtr(5 * time.Second),
+		FailureRateThreshold:     Float64Ptr(0.2),
+		MinimumObservations:      Uint32Ptr(10),
+		WarningThresholdFraction: Float64Ptr(0.5),
+	}
+	if *update.MaxRequests != *want.MaxRequests ||
+		*update.Interval != *want.Interval ||
+		*update.Timeout != *want.Timeout ||
+		*update.FailureRateThreshold != *want.FailureRateThreshold ||
+		*update.MinimumObservations != *want.MinimumObservations ||
+		*update.WarningThresholdFraction != *want.WarningThresholdFraction {
+		t.Errorf("Unexpected update: %+v", update)
+	}
+}
+
+func TestSettingsFromEnv_Empty(t *testing.T) {
+	t.Setenv("APP_EMPTY_TIMEOUT", "") // Set but empty counts as unset
+
+	settings, err := SettingsFromEnv("APP", "empty")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if !reflect.DeepEqual(settings, Settings{Name: "empty"}) {
+		t.Errorf("Expected only Name set, got %+v", settings)
+	}
+
+	update, err := SettingsUpdateFromEnv("APP", "empty")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if update != (SettingsUpdate{}) {
+		t.Errorf("Expected all fields nil, got %+v", update)
+	}
+}
+
+func TestSettingsFromEnv_Malformed(t *testing.T) {
+	tests := []struct {
+		key, value string
+	}{
+		{"APP_SVC_MAX_REQUESTS", "-1"},
+		{"APP_SVC_MAX_REQUESTS", "5000000000"},
+		{"APP_SVC_INTERVAL", "10"},
+		{"APP_SVC_TIMEOUT", "soon"},
+		{"APP_SVC_ADAPTIVE", "yes"},
+		{"APP_SVC_FAILURE_RATE", "5%"},
+		{"APP_SVC_MIN_OBSERVATIONS", "1.5"},
+		{"APP_SVC_WARNING_THRESHOLD", "high"},
+		{"APP_SVC_RECOVERY_RATE", "low"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.key+"="+tt.value, func(t *testing.T) {
+			t.Setenv(tt.key, tt.value)
+			_, err := SettingsFromEnv("APP", "svc")
+			if err == nil {
+				t.Fatal("Expected an error")
+			}
+			if !strings.Contains(err.Error(), tt.key) {
+				t.Errorf("Expected the error to name %s, got %v", tt.key, err)
+			}
+		})
+	}
+}
+
+func TestApplyEnvOverrides(t *testing.T) {
+	cb := New(Settings{Name: "orders", Timeout: 10 * time.Second, MaxRequests: 2})
+
+	// Nothing set: the code defaults stand
+	if err := ApplyEnvOverrides(cb, "APP"); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if got := cb.CurrentSettings().Timeout; got != 10*time.Second {
+		t.Errorf("Expected Timeout unchanged, got %v", got)
+	}
+
+	t.Setenv("APP_ORDERS_TIMEOUT", "45s")
+	if err := ApplyEnvOverrides(cb, "APP"); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	current := cb.CurrentSettings()
+	if current.Timeout != 45*time.Second || current.MaxRequests != 2 {
+		t.Errorf("Expected Timeout overridden and MaxRequests kept, got %+v", current)
+	}
+
+	// A value that parses but fails validation leaves the breaker unchanged
+	t.Setenv("APP_ORDERS_TIMEOUT", "0s")
+	if err := ApplyEnvOverrides(cb, "APP"); err == nil {
+		t.Error("Expected a validation error for Timeout=0")
+	}
+	if got := cb.CurrentSettings().Timeout; got != 45*time.Second {
+		t.Errorf("Expected Timeout unchanged after a rejected override, got %v", got)
+	}
+}
@@ -0,0 +1,181 @@
+package breaker
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Episode describes one open period: from the circuit leaving Closed until it
+// closes again, across any number of HalfOpen → Open round trips. It measures
+// the impact on callers of a single incident (see Settings.EpisodeHistory).
+type Episode struct {
+	// OpenedAt is when the circuit left Closed.
+	OpenedAt time.Time
+
+	// ClosedAt is when the circuit closed again.
+	ClosedAt time.Time
+
+	// Duration is ClosedAt - OpenedAt: how long callers were affected.
+	Duration time.Duration
+
+	// Reason is why the circuit opened (see OpenReason).
+	Reason OpenReason
+
+	// Rejected is the number of requests turned away during the episode:
+	// ErrOpenState rejections and HalfOpen rejections (ErrTooManyRequests,
+	// including probe-ineligible requests).
+	Rejected uint64
+
+	// ProbeAttempts is the number of HalfOpen probes admitted.
+	ProbeAttempts uint64
+
+	// FailedProbes is the number of probes recorded as failures (including
+	// probes abandoned by HalfOpenProbeTimeout).
+	FailedProbes uint64
+}
+
+// episodeRecord is the episode in progress, updated lock-free.
+type episodeRecord struct {
+	openedAt      int64 // UnixNano
+	reason        OpenReason
+	rejected      atomic.Uint64
+	probeAttempts atomic.Uint64
+	failedProbes  atomic.Uint64
+}
+
+// episodeLog retains the last N finished episodes. Episodes finish rarely, so
+// a mutex is cheap here.
+type episodeLog struct {
+	mu       sync.Mutex
+	episodes []Episode // Oldest first, at most size
+	size     int
+}
+
+// episodeHistory returns the number of episodes retained (EpisodeHistory).
+func (cb *CircuitBreaker) episodeHistory() int {
+	if cb.episodes == nil {
+		return 0
+	}
+	return cb.episodes.size
+}
+
+func newEpisodeLog(size int) *episodeLog {
+	if size <= 0 {
+		return nil
+	}
+	return &episodeLog{size: size}
+}
+
+// add appends e, dropping the oldest episode beyond size.
+func (l *episodeLog) add(e Episode) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if len(l.episodes) == l.size {
+		copy(l.episodes, l.episodes[1:])
+		l.episodes = l.episodes[:l.size-1]
+	}
+	l.episodes = append(l.episodes, e)
+}
+
+// snapshot returns a copy of the retained episodes, oldest first.
+func (l *episodeLog) snapshot() []Episode {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if len(l.episodes) == 0 {
+		return nil
+	}
+	return append([]Episode(nil), l.episodes...)
+}
+
+// Episodes returns the last Settings.EpisodeHistory finished open periods,
+// oldest first. An episode starts when the circuit leaves Closed and finishes
+// when it closes again; the episode in progress is not included. Returns nil
+// without EpisodeHistory or before the first episode has finished.
+//
+// Thread-safe: Returns a copy.
+//
+// Example - incident report:
+//
+//	for _, e := range breaker.Episodes() {
+//	    log.Printf("%s: open %v (%s), %d requests rejected, %d probes",
+//	        e.OpenedAt.Format(time.RFC3339), e.Duration, e.Reason.Kind, e.Rejected, e.ProbeAttempts)
+//	}
+func (cb *CircuitBreaker) Episodes() []Episode {
+	if cb.episodes == nil {
+		return nil
+	}
+	return cb.episodes.snapshot()
+}
+
+// beginEpisode starts an episode on leaving Closed. Called by the winner of
+// the transition only.
+func (cb *CircuitBreaker) beginEpisode(now int64, reason *OpenReason) {
+	if cb.episodes == nil {
+		return
+	}
+	rec := &episodeRecord{openedAt: now}
+	if reason != nil {
+		rec.reason = *reason
+	}
+	cb.episode.Store(rec)
+}
+
+// endEpisode finishes the episode in progress on entering Closed. Taking the
+// record makes finalization happen once even if called more than once.
+func (cb *CircuitBreaker) endEpisode(now int64) {
+	if cb.episodes == nil {
+		return
+	}
+	rec := cb.episode.Swap(nil)
+	if rec == nil {
+		return // No episode started (e.g. StartHalfOpen)
+	}
+	cb.episodes.add(Episode{
+		OpenedAt:      time.Unix(0, rec.openedAt),
+		ClosedAt:      time.Unix(0, now),
+		Duration:      time.Duration(now - rec.openedAt),
+		Reason:        cb.withDetail(rec.reason),
+		Rejected:      rec.rejected.Load(),
+		ProbeAttempts: rec.probeAttempts.Load(),
+		FailedProbes:  rec.failedProbes.Load(),
+	})
+}
+
+// rejectOpen counts an ErrOpenState rejection against the episode in progress
+// and returns ErrOpenState.
+func (cb *CircuitBreaker) rejectOpen() error {
+	cb.episodeRejected()
+	return ErrOpenState
+}
+
+// episodeRejected counts a rejection against the episode in progress.
+func (cb *CircuitBreaker) episodeRejected() {
+	if rec := cb.currentEpisode(); rec != nil {
+		rec.rejected.Add(1)
+	}
+}
+
+// episodeProbeAdmitted counts an admitted probe against the episode in progress.
+func (cb *CircuitBreaker) episodeProbeAdmitted() {
+	if rec := cb.currentEpisode(); rec != nil {
+		rec.probeAttempts.Add(1)
+	}
+}
+
+// episodeProbeFailed counts a failed probe against the episode in progress.
+func (cb *CircuitBreaker) episodeProbeFailed() {
+	if rec := cb.currentEpisode(); rec != nil {
+		rec.failedProbes.Add(1)
+	}
+}
+
+// currentEpisode returns the episode in progress, or nil.
+func (cb *CircuitBreaker) currentEpisode() *episodeRecord {
+	if cb.episodes == nil {
+		return nil
+	}
+	return cb.episode.Load()
+}
@@ -0,0 +1,160 @@
+package breaker
+
+import (
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestEpisodes_TwoCycles(t *testing.T) {
+	const timeout = 20 * time.Millisecond
+	cb := New(tripOnFirstFailure(Settings{Name: "episodes", Timeout: timeout, EpisodeHistory: 5}))
+	rejectOpen := func(n int) {
+		t.Helper()
+		for i := 0; i < n; i++ {
+			if _, err := cb.Execute(successFunc); !errors.Is(err, ErrOpenState) {
+				t.Fatalf("Expected ErrOpenState, got %v", err)
+			}
+		}
+	}
+
+	// Episode 1: 3 rejections, a failed probe, 2 more rejections, a good probe
+	before := time.Now()
+	cb.Execute(failFunc)
+	rejectOpen(3)
+	time.Sleep(timeout + 10*time.Millisecond)
+	cb.Execute(failFunc)
+	requireState(t, cb, StateOpen, time.Second)
+	rejectOpen(2)
+	time.Sleep(timeout + 10*time.Millisecond)
+	cb.Execute(successFunc)
+	requireState(t, cb, StateClosed, time.Second)
+	after := time.Now()
+
+	if got := cb.Episodes(); len(got) != 1 {
+		t.Fatalf("Expected 1 episode after the first cycle, got %d", len(got))
+	}
+
+	// Episode 2: 1 rejection, then a probe held while another request is turned away
+	cb.Execute(failFunc)
+	rejectOpen(1)
+	time.Sleep(timeout + 10*time.Millisecond)
+	release := make(chan struct{})
+	started := make(chan struct{})
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		cb.Execute(func() (interface{}, error) {
+			close(started)
+			<-release
+			return "ok", nil
+		})
+	}()
+	<-started
+	if _, err := cb.Execute(successFunc); !errors.Is(err, ErrTooManyRequests) {
+		t.Fatalf("Expected ErrTooManyRequests while the probe is in flight, got %v", err)
+	}
+	close(release)
+	<-done
+	requireState(t, cb, StateClosed, time.Second)
+
+	episodes := cb.Episodes()
+	if len(episodes) != 2 {
+		t.Fatalf("Expected 2 episodes, got %d", len(episodes))
+	}
+
+	first := episodes[0]
+	if first.Rejected != 5 || first.ProbeAttempts != 2 || first.FailedProbes != 1 {
+		t.Errorf("Expected 5 rejected, 2 probes, 1 failed in episode 1, got %+v", first)
+	}
+	if first.Reason.Kind != OpenReasonReadyToTrip {
+		t.Errorf("Expected episode 1 to record why it opened, got %v", first.Reason.Kind)
+	}
+	if first.OpenedAt.Before(before) || first.ClosedAt.After(after) || first.Duration != first.ClosedAt.Sub(first.OpenedAt) {
+		t.Errorf("Expected episode 1 times within the cycle, got %v - %v (%v)", first.OpenedAt, first.ClosedAt, first.Duration)
+	}
+	if first.Duration < 2*timeout {
+		t.Errorf("Expected episode 1 to span both open periods, got %v", first.Duration)
+	}
+
+	second := episodes[1]
+	if second.Rejected != 2 || second.ProbeAttempts != 1 || second.FailedProbes != 0 {
+		t.Errorf("Expected 2 rejected, 1 probe, 0 failed in episode 2, got %+v", second)
+	}
+	if !second.OpenedAt.After(first.ClosedAt) {
+		t.Errorf("Expected episode 2 to start after episode 1 closed")
+	}
+
+	if got := cb.Diagnostics().Episodes; len(got) != 2 || got[1] != second {
+		t.Errorf("Expected Diagnostics to include the episodes, got %+v", got)
+	}
+}
+
+func TestEpisodes_Bounded(t *testing.T) {
+	cb := New(tripOnFirstFailure(Settings{Name: "episodes-bounded", Timeout: 5 * time.Millisecond, EpisodeHistory: 2}))
+	for i := 0; i < 4; i++ {
+		cb.Execute(failFunc)
+		for r := 0; r <= i; r++ {
+			cb.Execute(successFunc) // Rejected: i+1 per episode
+		}
+		time.Sleep(15 * time.Millisecond)
+		cb.Execute(successFunc)
+		requireState(t, cb, StateClosed, time.Second)
+	}
+
+	episodes := cb.Episodes()
+	if len(episodes) != 2 {
+		t.Fatalf("Expected the last 2 episodes, got %d", len(episodes))
+	}
+	if episodes[0].Rejected != 3 || episodes[1].Rejected != 4 {
+		t.Errorf("Expected the 3rd and 4th episodes, oldest first, got %+v", episodes)
+	}
+}
+
+func TestEpisodes_Disabled(t *testing.T) {
+	cb := New(tripOnFirstFailure(Settings{Name: "episodes-off", Timeout: 5 * time.Millisecond}))
+	cb.Execute(failFunc)
+	time.Sleep(15 * time.Millisecond)
+	cb.Execute(successFunc)
+
+	if cb.Episodes() != nil || cb.Diagnostics().Episodes != nil {
+		t.Error("Expected no episodes without EpisodeHistory")
+	}
+}
+
+func TestEpisodes_FinalizedOncePerCycle(t *testing.T) {
+	var closes atomic.Int32
+	cb := New(tripOnFirstFailure(Settings{
+		Name:           "episodes-race",
+		Timeout:        time.Millisecond,
+		MaxRequests:    4,
+		EpisodeHistory: 1000,
+		OnStateChange: func(_ string, from, to State) {
+			if to == StateClosed {
+				closes.Add(1)
+			}
+		},
+	}))
+
+	var wg sync.WaitGroup
+	for w := 0; w < 8; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			for i := 0; i < 300; i++ {
+				if (i+w)%7 == 0 {
+					cb.Execute(failFunc)
+				} else {
+					cb.Execute(successFunc)
+				}
+			}
+		}(w)
+	}
+	wg.Wait()
+
+	if got, want := len(cb.Episodes()), int(closes.Load()); got != want {
+		t.Errorf("Expected one episode per close (%d), got %d", want, got)
+	}
+}
@@ -0,0 +1,45 @@
+package breaker
+
+import "sync/atomic"
+
+// eventCounts holds the cumulative counters of events most breakers rarely or
+// never see. A breaker allocates them on the first such event (see
+// eventCounter), so one that sees none does not carry them.
+type eventCounts struct {
+	probeRejections      atomic.Uint64 // Half-open requests rejected with ErrTooManyRequests
+	probeTimeouts        atomic.Uint64 // Slots released by the HalfOpenProbeTimeout watchdog
+	ineligibleRejections atomic.Uint64 // Probe-ineligible requests turned away in HalfOpen
+	fairnessRejections   atomic.Uint64 // Probes turned away by ProbeFairnessWait
+	probeGateRejections  atomic.Uint64 // Requests past the open period held off by ProbeGate
+	streamTimeouts       atomic.Uint64 // ExecuteStream calls that outlived StreamTimeout
+	slowCalls            atomic.Uint64 // Successes recorded as failures by SlowCallFactor
+	classifierPanics     atomic.Uint64 // IsSuccessful and OutcomeWeight panics
+	lateOutcomes         atomic.Uint64 // Completions after their window was discarded (see lateOutcome)
+	bypassedCalls        atomic.Uint64 // ExecuteContext calls run with WithBypass
+	syntheticSuccesses   atomic.Uint64 // ExecuteUncounted outcomes, kept out of the counts
+	syntheticFailures    atomic.Uint64
+	shadowTrips          atomic.Uint32 // Shadow trip rule latches
+}
+
+// noEvents stands in for the counters of a breaker that has seen no event.
+// Only ever read.
+var noEvents eventCounts
+
+// eventCounter returns the event counters for recording an event, allocating
+// them on first use.
+func (cb *CircuitBreaker) eventCounter() *eventCounts {
+	if ev := cb.events.Load(); ev != nil {
+		return ev
+	}
+	cb.events.CompareAndSwap(nil, new(eventCounts))
+	return cb.events.Load()
+}
+
+// eventTotals returns the event counters for reading: noEvents until the
+// first event.
+func (cb *CircuitBreaker) eventTotals() *eventCounts {
+	if ev := cb.events.Load(); ev != nil {
+		return ev
+	}
+	return &noEvents
+}
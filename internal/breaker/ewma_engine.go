@@ -0,0 +1,129 @@
+package breaker
+
+import (
+	"fmt"
+	"math"
+	"sync"
+	"time"
+)
+
+// EWMAEngine is a DecisionEngine that trips on an exponentially weighted
+// failure rate: every outcome's weight halves each HalfLife, so recent traffic
+// dominates and old failures fade out smoothly, instead of counting equally
+// until the next Interval boundary and then vanishing at once.
+//
+// The circuit trips when the weighted failure rate exceeds the threshold and
+// the weighted number of observations is at least the minimum. A burst after a
+// long healthy period is therefore judged on recent traffic, and a steady trickle
+// of failures is judged against the recent rate, not the lifetime one.
+//
+// EWMAEngine does not shed load: ShouldAdmit always admits.
+//
+// Example:
+//
+//	breaker := autobreaker.New(autobreaker.Settings{
+//	    Name:   "api",
+//	    Engine: autobreaker.NewEWMAEngine(30*time.Second, 0.10, 20),
+//	})
+//
+// Thread-safe: Safe for concurrent use. Use one instance per breaker.
+type EWMAEngine struct {
+	halfLife        time.Duration
+	threshold       float64
+	minObservations float64
+
+	mu       sync.Mutex
+	failures float64 // Decayed failure weight
+	total    float64 // Decayed outcome weight
+	last     time.Time
+}
+
+// NewEWMAEngine returns an EWMAEngine with the given outcome half-life, failure
+// rate threshold and minimum (weighted) number of observations. Zero values
+// select the defaults: a 10 second half-life, a 5% threshold and 20
+// observations, as for AdaptiveThreshold.
+//
+// Panics if halfLife is negative or failureRate is outside [0, 1), like New.
+func NewEWMAEngine(halfLife time.Duration, failureRate float64, minObservations uint32) *EWMAEngine {
+	if halfLife < 0 {
+		panic(fmt.Sprintf("autobreaker: EWMAEngine half-life cannot be negative, got %v", halfLife))
+	}
+	if failureRate < 0 || failureRate >= 1 {
+		panic(fmt.Sprintf("autobreaker: EWMAEngine failure rate must be in range [0, 1), got %v", failureRate))
+	}
+	if halfLife == 0 {
+		halfLife = 10 * time.Second
+	}
+	if failureRate == 0 {
+		failureRate = 0.05
+	}
+	if minObservations == 0 {
+		minObservations = 20
+	}
+	return &EWMAEngine{
+		halfLife:        halfLife,
+		threshold:       failureRate,
+		minObservations: float64(minObservations),
+	}
+}
+
+// OnOutcome decays the recorded weights to now and adds the outcome.
+func (e *EWMAEngine) OnOutcome(success bool, now time.Time) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.decayTo(now)
+	e.total++
+	if !success {
+		e.failures++
+	}
+}
+
+// ShouldTrip reports whether the weighted failure rate, as of the last
+// outcome, exceeds the threshold with enough weighted observations.
+func (e *EWMAEngine) ShouldTrip() bool {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	return e.total >= e.minObservations && e.failures/e.total > e.threshold
+}
+
+// ShouldAdmit always admits: EWMAEngine only decides when to trip.
+func (e *EWMAEngine) ShouldAdmit(state State, now time.Time) (bool, error) {
+	return true, nil
+}
+
+// Reset forgets all outcomes.
+func (e *EWMAEngine) Reset() {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.failures, e.total, e.last = 0, 0, time.Time{}
+}
+
+// FailureRate returns the weighted failure rate as of the last outcome, or 0
+// without outcomes.
+func (e *EWMAEngine) FailureRate() float64 {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if e.total == 0 {
+		return 0
+	}
+	return e.failures / e.total
+}
+
+// decayTo ages the weights from the last outcome to now. Outcomes reported
+// out of order are not decayed backwards. Must be called with mu held.
+func (e *EWMAEngine) decayTo(now time.Time) {
+	if !e.last.IsZero() {
+		if elapsed := now.Sub(e.last); elapsed > 0 {
+			decay := math.Exp2(-float64(elapsed) / float64(e.halfLife))
+			e.failures *= decay
+			e.total *= decay
+		}
+	}
+	if now.After(e.last) {
+		e.last = now
+	}
+}
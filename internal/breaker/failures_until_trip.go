@@ -0,0 +1,92 @@
+package breaker
+
+import "math"
+
+// FailuresUntilTrip returns how many more failures, starting from the current
+// counts, would trip the circuit. It is the quantitative form of
+// Diagnostics.WillTripNext, which predicts a trip when this returns 1.
+//
+// In static mode (DefaultReadyToTrip or ConsecutiveFailureThreshold) this is the
+// number of consecutive failures still needed. In adaptive mode it is the number
+// of failures within the current window that would push the failure rate above
+// the threshold while meeting MinimumObservations (and MinObservationWindow).
+// Each additional failure is assumed to carry weight 1 with OutcomeWeight.
+//
+// Returns -1 if the trip point cannot be determined: outside Closed state, with
+// a custom ReadyToTrip or Engine, before the first full window with
+// RequireFullWindow, or when no number of failures can exceed the threshold.
+//
+// The answer holds for the counts at the time of the call: successes, Interval
+// clearing and concurrent requests change it.
+//
+// Thread-safe: Lock-free snapshot of the current counts.
+//
+// Example - alerting before a trip:
+//
+//	if n := breaker.FailuresUntilTrip(); n >= 0 && n <= 3 {
+//	    log.Printf("Circuit %s trips after %d more failures", breaker.Name(), n)
+//	}
+func (cb *CircuitBreaker) FailuresUntilTrip() int {
+	if cb.machineState() != StateClosed || cb.customEngine || cb.customReadyToTrip {
+		return -1
+	}
+
+	counts := cb.Counts()
+	if cb.adaptiveThreshold {
+		return cb.adaptiveFailuresUntilTrip(counts)
+	}
+
+	// Static: trips once ConsecutiveFailures exceeds the threshold
+	threshold := int64(cb.consecutiveThreshold)
+	if threshold == 0 {
+		threshold = 5 // DefaultReadyToTrip
+	}
+	return int(max(threshold+1-int64(counts.ConsecutiveFailures), 1))
+}
+
+// adaptiveFailuresUntilTrip solves the adaptive trip rule for the number of
+// additional failures n: (failures+n)/(requests+n) above the threshold, with
+// requests+n meeting MinimumObservations.
+func (cb *CircuitBreaker) adaptiveFailuresUntilTrip(counts Counts) int {
+	if cb.partialWindow.Load() {
+		return -1 // No adaptive trips until the first full aligned window
+	}
+	threshold := cb.tripComparison().threshold
+	limit := threshold + cb.rateEpsilon
+	if limit >= 1 {
+		return -1 // The failure rate cannot exceed the threshold
+	}
+
+	// Enough observations, in the window and recently
+	minimum := int64(cb.getMinimumObservations())
+	lowest := max(minimum-int64(counts.Requests), 1)
+	if cb.recent != nil {
+		lowest = max(lowest, minimum-int64(cb.recent.count(cb.now())))
+	}
+
+	// Enough failures: n > (limit·requests - failures) / (1 - limit)
+	weighted := cb.outcomeWeight != nil
+	failures := float64(counts.TotalFailures)
+	if weighted {
+		failures = counts.FailureWeight
+	}
+	n := max(int64(math.Floor((limit*float64(counts.Requests)-failures)/(1-limit)))+1, lowest)
+
+	// Settle float rounding against the exact comparison the trip rule makes.
+	// The rate only grows with n, so the smallest tripping n is unique.
+	exceeds := func(n int64) bool {
+		simulated := Counts{
+			Requests:      counts.Requests + uint32(n),
+			TotalFailures: counts.TotalFailures + uint32(n),
+			FailureWeight: counts.FailureWeight + float64(n),
+		}
+		return cb.rateExceeds(failureRateOf(simulated, weighted), threshold)
+	}
+	for !exceeds(n) {
+		n++
+	}
+	for n > lowest && exceeds(n-1) {
+		n--
+	}
+	return int(n)
+}
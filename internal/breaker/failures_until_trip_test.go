@@ -0,0 +1,124 @@
+package breaker
+
+import (
+	"testing"
+	"time"
+)
+
+// failuresToTrip fails requests until the circuit opens and returns how many
+// it took.
+func failuresToTrip(t *testing.T, cb *CircuitBreaker) int {
+	t.Helper()
+	for n := 1; n <= 10000; n++ {
+		cb.Execute(failFunc)
+		if cb.State() == StateOpen {
+			return n
+		}
+	}
+	t.Fatal("Circuit did not trip after 10000 failures")
+	return 0
+}
+
+func TestFailuresUntilTrip_MatchesTripPoint(t *testing.T) {
+	tests := []struct {
+		name      string
+		settings  Settings
+		successes int
+		failures  int
+		want      int
+	}{
+		{"default fresh", Settings{}, 0, 0, 6},
+		{"default partway", Settings{}, 0, 4, 2},
+		{"default after success", Settings{}, 3, 0, 6},
+		{"consecutive threshold", Settings{ConsecutiveFailureThreshold: 3}, 1, 2, 2},
+		{"adaptive rate bound", Settings{
+			AdaptiveThreshold: true, FailureRateThreshold: 0.10, MinimumObservations: 20,
+		}, 50, 0, 6}, // 6/56 > 10% > 5/55
+		{"adaptive exact threshold", Settings{
+			AdaptiveThreshold: true, FailureRateThreshold: 0.10, MinimumObservations: 20,
+		}, 90, 0, 11}, // 10/100 is at, not above, the threshold
+		{"adaptive with failures", Settings{
+			AdaptiveThreshold: true, FailureRateThreshold: 0.20, MinimumObservations: 20,
+		}, 40, 5, 6}, // 11/51 > 20% = 10/50
+		{"adaptive observation bound", Settings{
+			AdaptiveThreshold: true, FailureRateThreshold: 0.50, MinimumObservations: 20,
+		}, 5, 0, 15},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := tt.settings
+			s.Name = "failures-until-trip"
+			s.Timeout = time.Minute
+			cb := New(s)
+
+			for i := 0; i < tt.successes; i++ {
+				cb.Execute(successFunc)
+			}
+			for i := 0; i < tt.failures; i++ {
+				cb.Execute(failFunc)
+			}
+			if cb.State() != StateClosed {
+				t.Fatalf("Setup tripped the circuit")
+			}
+
+			got := cb.FailuresUntilTrip()
+			if got != tt.want {
+				t.Errorf("FailuresUntilTrip() = %d, want %d", got, tt.want)
+			}
+			if willTrip := cb.Diagnostics().WillTripNext; willTrip != (got == 1) {
+				t.Errorf("WillTripNext = %v with %d failures until trip", willTrip, got)
+			}
+			if observed := failuresToTrip(t, cb); observed != got {
+				t.Errorf("Circuit tripped after %d failures, FailuresUntilTrip() = %d", observed, got)
+			}
+		})
+	}
+}
+
+func TestFailuresUntilTrip_CountsDown(t *testing.T) {
+	cb := New(Settings{
+		Name:                 "failures-until-trip",
+		Timeout:              time.Minute,
+		AdaptiveThreshold:    true,
+		FailureRateThreshold: 0.25,
+		MinimumObservations:  10,
+	})
+	for i := 0; i < 30; i++ {
+		cb.Execute(successFunc)
+	}
+
+	want := cb.FailuresUntilTrip()
+	for want > 1 {
+		cb.Execute(failFunc)
+		want--
+		if got := cb.FailuresUntilTrip(); got != want {
+			t.Fatalf("FailuresUntilTrip() = %d after a failure, want %d", got, want)
+		}
+	}
+	cb.Execute(failFunc)
+	if cb.State() != StateOpen {
+		t.Errorf("Expected Open, got %v", cb.State())
+	}
+}
+
+func TestFailuresUntilTrip_Undetermined(t *testing.T) {
+	custom := New(Settings{
+		Name:        "custom",
+		ReadyToTrip: func(counts Counts) bool { return counts.TotalFailures > 2 },
+	})
+	if got := custom.FailuresUntilTrip(); got != -1 {
+		t.Errorf("Custom ReadyToTrip: FailuresUntilTrip() = %d, want -1", got)
+	}
+
+	engine := New(Settings{Name: "engine", Engine: NewEWMAEngine(0, 0, 0)})
+	if got := engine.FailuresUntilTrip(); got != -1 {
+		t.Errorf("Engine: FailuresUntilTrip() = %d, want -1", got)
+	}
+
+	open := New(tripOnFirstFailure(Settings{Name: "open", Timeout: time.Minute}))
+	open.Execute(failFunc)
+	if got := open.FailuresUntilTrip(); got != -1 {
+		t.Errorf("Open: FailuresUntilTrip() = %d, want -1", got)
+	}
+}
@@ -0,0 +1,102 @@
+package breaker
+
+import (
+	"errors"
+	"sync"
+	"sync/atomic"
+)
+
+// Fallback is one tier of a fallback chain for ExecuteWithFallbacks.
+type Fallback struct {
+	// Name identifies the fallback in Metrics.FallbackServed. Use a fixed set
+	// of names (such as "cache", "stale", "default"): every distinct name is
+	// tracked for the breaker's lifetime.
+	Name string
+
+	// Func produces the result in place of the failed or rejected request.
+	// err is the request's error (for example ErrOpenState). Returning a
+	// non-nil error passes the call on to the next fallback.
+	Func func(err error) (interface{}, error)
+}
+
+// ExecuteWithFallbacks runs req like Execute and, if it returns an error
+// (a failure, or a rejection such as ErrOpenState), tries fallbacks in order
+// until one succeeds. The first successful fallback's result is returned with
+// a nil error, and the call is tallied under its name in
+// Metrics.FallbackServed.
+//
+// Only req goes through the breaker: fallbacks are not admitted, counted or
+// protected from panics, and their outcomes never affect the circuit.
+//
+// If every fallback fails, or there are none, the error is req's error joined
+// (errors.Join) with each fallback's error, in order, so errors.Is(err,
+// ErrOpenState) still reports a rejection.
+//
+// Thread-safe: Safe to call concurrently.
+//
+// Example - Cache, Then Stale, Then Default:
+//
+//	result, err := breaker.ExecuteWithFallbacks(fetchPrice,
+//	    autobreaker.Fallback{Name: "cache", Func: func(error) (interface{}, error) {
+//	        return cache.Get(key)
+//	    }},
+//	    autobreaker.Fallback{Name: "stale", Func: func(error) (interface{}, error) {
+//	        return staleStore.Get(key)
+//	    }},
+//	    autobreaker.Fallback{Name: "default", Func: func(error) (interface{}, error) {
+//	        return defaultPrice, nil
+//	    }},
+//	)
+func (cb *CircuitBreaker) ExecuteWithFallbacks(req func() (interface{}, error), fallbacks ...Fallback) (interface{}, error) {
+	result, err := cb.Execute(req)
+	if err == nil {
+		return result, nil
+	}
+
+	errs := make([]error, 0, len(fallbacks)+1)
+	errs = append(errs, err)
+	for _, fb := range fallbacks {
+		fbResult, fbErr := fb.Func(err)
+		if fbErr == nil {
+			cb.countFallbackServed(fb.Name)
+			return fbResult, nil
+		}
+		errs = append(errs, fbErr)
+	}
+	if len(errs) == 1 {
+		return result, err // No fallbacks: exactly as Execute
+	}
+	return nil, errors.Join(errs...)
+}
+
+// countFallbackServed tallies a call served by the named fallback.
+func (cb *CircuitBreaker) countFallbackServed(name string) {
+	served := cb.fallbackServed.Load()
+	if served == nil {
+		cb.fallbackServed.CompareAndSwap(nil, new(sync.Map))
+		served = cb.fallbackServed.Load()
+	}
+	counter, ok := served.Load(name)
+	if !ok {
+		counter, _ = served.LoadOrStore(name, new(atomic.Uint64))
+	}
+	counter.(*atomic.Uint64).Add(1)
+}
+
+// fallbackServedCounts returns Metrics.FallbackServed: a copy of the counters,
+// or nil if no fallback has served a call.
+func (cb *CircuitBreaker) fallbackServedCounts() map[string]uint64 {
+	served := cb.fallbackServed.Load()
+	if served == nil {
+		return nil
+	}
+	var counts map[string]uint64
+	served.Range(func(name, counter interface{}) bool {
+		if counts == nil {
+			counts = make(map[string]uint64)
+		}
+		counts[name.(string)] = counter.(*atomic.Uint64).Load()
+		return true
+	})
+	return counts
+}
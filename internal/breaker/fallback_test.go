@@ -0,0 +1,96 @@
+package breaker
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+var errCacheMiss = errors.New("cache miss")
+
+func TestExecuteWithFallbacks_ChainWhenOpen(t *testing.T) {
+	cb := New(tripOnFirstFailure(Settings{Name: "fallbacks", Timeout: time.Minute}))
+	cb.Execute(failFunc)
+
+	var cacheErr error
+	chain := []Fallback{
+		{Name: "cache", Func: func(err error) (interface{}, error) {
+			cacheErr = err
+			return nil, errCacheMiss
+		}},
+		{Name: "stale", Func: func(error) (interface{}, error) { return "stale", nil }},
+		{Name: "default", Func: func(error) (interface{}, error) {
+			t.Error("Expected the chain to stop at the first successful fallback")
+			return "default", nil
+		}},
+	}
+
+	result, err := cb.ExecuteWithFallbacks(successFunc, chain...)
+	if err != nil || result != "stale" {
+		t.Fatalf("Expected the stale fallback's result, got %v, %v", result, err)
+	}
+	if !errors.Is(cacheErr, ErrOpenState) {
+		t.Errorf("Expected fallbacks to receive ErrOpenState, got %v", cacheErr)
+	}
+
+	served := cb.Metrics().FallbackServed
+	if served["stale"] != 1 || len(served) != 1 {
+		t.Errorf("Expected one call served by stale only, got %v", served)
+	}
+
+	cb.ExecuteWithFallbacks(successFunc, chain...)
+	if got := cb.Metrics().FallbackServed["stale"]; got != 2 {
+		t.Errorf("Expected stale to have served 2 calls, got %d", got)
+	}
+}
+
+func TestExecuteWithFallbacks_PrimarySucceeds(t *testing.T) {
+	cb := New(Settings{Name: "fallbacks"})
+
+	result, err := cb.ExecuteWithFallbacks(successFunc, Fallback{Name: "default", Func: func(error) (interface{}, error) {
+		t.Error("Expected no fallback when the request succeeds")
+		return nil, nil
+	}})
+	if err != nil || result != "success" {
+		t.Fatalf("Expected the request's result, got %v, %v", result, err)
+	}
+	if served := cb.Metrics().FallbackServed; served != nil {
+		t.Errorf("Expected no fallback counters, got %v", served)
+	}
+}
+
+func TestExecuteWithFallbacks_FailureCountsAndFallsBack(t *testing.T) {
+	cb := New(Settings{Name: "fallbacks"})
+
+	result, err := cb.ExecuteWithFallbacks(failFunc, Fallback{Name: "default", Func: func(error) (interface{}, error) {
+		return "default", nil
+	}})
+	if err != nil || result != "default" {
+		t.Fatalf("Expected the default fallback's result, got %v, %v", result, err)
+	}
+	if counts := cb.Counts(); counts.TotalFailures != 1 {
+		t.Errorf("Expected the request's failure to be counted, got %+v", counts)
+	}
+}
+
+func TestExecuteWithFallbacks_AllFail(t *testing.T) {
+	cb := New(tripOnFirstFailure(Settings{Name: "fallbacks", Timeout: time.Minute}))
+	cb.Execute(failFunc)
+
+	errStale := errors.New("no stale value")
+	_, err := cb.ExecuteWithFallbacks(successFunc,
+		Fallback{Name: "cache", Func: func(error) (interface{}, error) { return nil, errCacheMiss }},
+		Fallback{Name: "stale", Func: func(error) (interface{}, error) { return nil, errStale }},
+	)
+	if !errors.Is(err, ErrOpenState) || !errors.Is(err, errCacheMiss) || !errors.Is(err, errStale) {
+		t.Errorf("Expected the rejection joined with every fallback error, got %v", err)
+	}
+	if served := cb.Metrics().FallbackServed; served != nil {
+		t.Errorf("Expected no fallback counters, got %v", served)
+	}
+
+	// Without fallbacks the error is returned as is
+	if _, err := cb.ExecuteWithFallbacks(successFunc); err != ErrOpenState {
+		t.Errorf("Expected ErrOpenState unchanged without fallbacks, got %v", err)
+	}
+}
@@ -0,0 +1,322 @@
+package breaker
+
+import (
+	"math"
+	"sync"
+)
+
+// Group manages one circuit breaker per key with a shared, homogeneous policy.
+//
+// Group is designed for load-balanced services where the same logical backend is
+// reached through several resolved endpoints. Each endpoint (key) gets its own
+// child CircuitBreaker so a single bad node is isolated, while every child runs
+// with identical settings.
+//
+// Key Features:
+//
+//   - Lazy Children: A child breaker is created on first use of its key
+//   - Homogeneous Policy: All children share the group's current settings
+//   - Endpoint Selection: HealthyKeys() returns keys whose breaker is Closed
+//   - Combined View: Metrics() aggregates counts across all children
+//   - Fan-Out Updates: UpdateSettings() applies to every child (all-or-nothing)
+//
+// Child breakers are named "<group name>/<key>" so they can be told apart in
+// OnStateChange callbacks and logs.
+//
+// Example:
+//
+//	group := autobreaker.NewGroup(autobreaker.Settings{
+//	    Name:                 "user-service",
+//	    AdaptiveThreshold:    true,
+//	    FailureRateThreshold: 0.05,
+//	}, []string{"10.0.0.1", "10.0.0.2", "10.0.0.3"})
+//
+//	endpoint := pick(group.HealthyKeys())
+//	result, err := group.Execute(endpoint, func() (interface{}, error) {
+//	    return callEndpoint(endpoint)
+//	})
+//
+// Thread-safe: All methods are safe for concurrent use.
+type Group struct {
+	mu       sync.RWMutex
+	settings Settings // current policy, inherited by newly created children
+	keys     []string // all known keys, in insertion order
+	breakers map[string]*CircuitBreaker
+}
+
+// NewGroup creates a group of circuit breakers sharing the given settings.
+//
+// The keys are registered up front (so they appear in HealthyKeys) but their
+// breakers are created lazily. Keys not in the initial list may be used later
+// with Execute() or Breaker(); they inherit the group's current settings.
+//
+// Settings are validated immediately. Like New(), NewGroup panics on invalid settings.
+func NewGroup(settings Settings, keys []string) *Group {
+	if err := validateSettings(&settings); err != nil {
+		panic(err.Error())
+	}
+
+	g := &Group{
+		settings: settings,
+		keys:     make([]string, 0, len(keys)),
+		breakers: make(map[string]*CircuitBreaker, len(keys)),
+	}
+
+	seen := make(map[string]struct{}, len(keys))
+	for _, key := range keys {
+		if _, dup := seen[key]; dup {
+			continue
+		}
+		seen[key] = struct{}{}
+		g.keys = append(g.keys, key)
+	}
+
+	return g
+}
+
+// Breaker returns the child circuit breaker for key, creating it if needed.
+//
+// Thread-safe: Concurrent calls for the same key return the same breaker.
+func (g *Group) Breaker(key string) *CircuitBreaker {
+	g.mu.RLock()
+	cb, ok := g.breakers[key]
+	g.mu.RUnlock()
+	if ok {
+		return cb
+	}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	// Re-check after acquiring write lock (another goroutine may have created it)
+	if cb, ok := g.breakers[key]; ok {
+		return cb
+	}
+
+	if !g.hasKeyLocked(key) {
+		g.keys = append(g.keys, key)
+	}
+
+	childSettings := g.settings
+	childSettings.Name = g.settings.Name + "/" + key
+	cb = New(childSettings)
+	g.breakers[key] = cb
+
+	return cb
+}
+
+// Execute runs req through the child breaker for key.
+//
+// Behavior is identical to CircuitBreaker.Execute() on that child.
+func (g *Group) Execute(key string, req func() (interface{}, error)) (interface{}, error) {
+	return g.Breaker(key).Execute(req)
+}
+
+// Keys returns all keys known to the group, in registration order.
+func (g *Group) Keys() []string {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	keys := make([]string, len(g.keys))
+	copy(keys, g.keys)
+	return keys
+}
+
+// HealthyKeys returns the keys whose breaker is currently Closed (or disabled,
+// passing all traffic), in registration order.
+//
+// Keys whose breaker has not been created yet are considered healthy (a new breaker
+// starts Closed). Use this as the candidate set for client-side load balancing.
+func (g *Group) HealthyKeys() []string {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	healthy := make([]string, 0, len(g.keys))
+	for _, key := range g.keys {
+		cb, ok := g.breakers[key]
+		if !ok || cb.Disabled() || cb.State() == StateClosed {
+			healthy = append(healthy, key)
+		}
+	}
+	return healthy
+}
+
+// Metrics returns metrics aggregated across all child breakers.
+//
+// Aggregation rules:
+//   - Counts: Summed across children (saturating at math.MaxUint32; FailureWeight is a plain sum)
+//   - Demand: Summed across children (saturating at math.MaxUint32)
+//   - FailureRate/SuccessRate/ServedRatio: Computed from the summed counts
+//   - State: The most available child state (Closed or Disabled > HalfOpen > Open),
+//     so the group reports Closed while at least one endpoint can take traffic
+//   - MachineState/EffectiveState: The most available child state, same ordering
+//   - StateChangedAt/CountsLastClearedAt: Most recent across children
+//   - Saturated/Degraded/Disabled: True if any child is saturated/degraded/disabled
+//   - ProbeRejections: Summed across children
+//
+// A group with no children yet reports StateClosed and zero counts.
+func (g *Group) Metrics() Metrics {
+	g.mu.RLock()
+	children := make([]*CircuitBreaker, 0, len(g.breakers))
+	for _, key := range g.keys {
+		if cb, ok := g.breakers[key]; ok {
+			children = append(children, cb)
+		}
+	}
+	weighted := g.settings.OutcomeWeight != nil
+	g.mu.RUnlock()
+
+	return aggregateMetrics(children, weighted, false)
+}
+
+// aggregateMetrics sums the metrics of children as described for Group.Metrics.
+// States are the most available child state, or the least available one when
+// worstOf is set. FailureRate uses FailureWeight when weighted.
+func aggregateMetrics(children []*CircuitBreaker, weighted, worstOf bool) Metrics {
+	if len(children) == 0 {
+		return Metrics{State: StateClosed, MachineState: StateClosed, EffectiveState: StateClosed}
+	}
+
+	// pick reports whether candidate replaces current as the aggregate state
+	pick := func(candidate, current State) bool {
+		return stateAvailability(candidate) > stateAvailability(current)
+	}
+	initial := StateOpen
+	if worstOf {
+		pick = func(candidate, current State) bool {
+			return stateAvailability(candidate) < stateAvailability(current)
+		}
+		initial = StateClosed
+	}
+
+	var agg Metrics
+	agg.State = initial
+	agg.MachineState = initial
+	agg.EffectiveState = initial
+	for _, cb := range children {
+		m := cb.Metrics()
+
+		agg.Counts.Requests = saturatingAdd(agg.Counts.Requests, m.Counts.Requests)
+		agg.Counts.TotalSuccesses = saturatingAdd(agg.Counts.TotalSuccesses, m.Counts.TotalSuccesses)
+		agg.Counts.TotalFailures = saturatingAdd(agg.Counts.TotalFailures, m.Counts.TotalFailures)
+		agg.Counts.ConsecutiveSuccesses = saturatingAdd(agg.Counts.ConsecutiveSuccesses, m.Counts.ConsecutiveSuccesses)
+		agg.Counts.ConsecutiveFailures = saturatingAdd(agg.Counts.ConsecutiveFailures, m.Counts.ConsecutiveFailures)
+		agg.Counts.FailureWeight += m.Counts.FailureWeight
+		agg.Counts.Panics = saturatingAdd(agg.Counts.Panics, m.Counts.Panics)
+		agg.Counts.TimeoutFailures = saturatingAdd(agg.Counts.TimeoutFailures, m.Counts.TimeoutFailures)
+		agg.Demand = saturatingAdd(agg.Demand, m.Demand)
+
+		if pick(m.State, agg.State) {
+			agg.State = m.State
+		}
+		if pick(m.MachineState, agg.MachineState) {
+			agg.MachineState = m.MachineState
+		}
+		if pick(m.EffectiveState, agg.EffectiveState) {
+			agg.EffectiveState = m.EffectiveState
+		}
+		if m.StateChangedAt.After(agg.StateChangedAt) {
+			agg.StateChangedAt = m.StateChangedAt
+		}
+		if m.CountsLastClearedAt.After(agg.CountsLastClearedAt) {
+			agg.CountsLastClearedAt = m.CountsLastClearedAt
+		}
+		agg.Saturated = agg.Saturated || m.Saturated
+		agg.Degraded = agg.Degraded || m.Degraded
+		agg.Disabled = agg.Disabled || m.Disabled
+		agg.ProbeRejections += m.ProbeRejections
+	}
+
+	if agg.Counts.Requests > 0 {
+		agg.FailureRate = failureRateOf(agg.Counts, weighted)
+		agg.SuccessRate = float64(agg.Counts.TotalSuccesses) / float64(agg.Counts.Requests)
+		if weighted {
+			agg.SuccessRate = 1 - agg.FailureRate
+		}
+	}
+	agg.ServedRatio = servedRatio(agg.Counts.Requests, agg.Demand)
+
+	return agg
+}
+
+// UpdateSettings applies the update to every child breaker and to the group policy.
+//
+// The update is validated once before anything is changed (all-or-nothing). On
+// success, every existing child is updated and keys created later inherit the
+// new settings. Child creation is blocked while the update is applied, so no
+// child can be created with a stale policy.
+func (g *Group) UpdateSettings(update SettingsUpdate) error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if err := validateSettingsUpdate(&update, g.settings.AdaptiveThreshold); err != nil {
+		return err
+	}
+
+	for _, cb := range g.breakers {
+		if err := cb.UpdateSettings(update); err != nil {
+			return err
+		}
+	}
+
+	applySettingsUpdate(&g.settings, update)
+	return nil
+}
+
+// hasKeyLocked reports whether key is registered. Caller must hold g.mu.
+func (g *Group) hasKeyLocked(key string) bool {
+	for _, k := range g.keys {
+		if k == key {
+			return true
+		}
+	}
+	return false
+}
+
+// applySettingsUpdate copies non-nil fields of update into settings.
+func applySettingsUpdate(settings *Settings, update SettingsUpdate) {
+	if update.MaxRequests != nil {
+		settings.MaxRequests = *update.MaxRequests
+	}
+	if update.Interval != nil {
+		settings.Interval = *update.Interval
+	}
+	if update.Timeout != nil {
+		settings.Timeout = *update.Timeout
+	}
+	if update.FailureRateThreshold != nil {
+		settings.FailureRateThreshold = *update.FailureRateThreshold
+	}
+	if update.MinimumObservations != nil {
+		settings.MinimumObservations = *update.MinimumObservations
+	}
+	if update.WarningThresholdFraction != nil {
+		settings.WarningThresholdFraction = *update.WarningThresholdFraction
+	}
+	if update.RecoverFailureRate != nil {
+		settings.RecoverFailureRate = *update.RecoverFailureRate
+	}
+	if update.IsSuccessful != nil {
+		settings.IsSuccessful = *update.IsSuccessful
+	}
+}
+
+// stateAvailability ranks states by how much traffic they admit.
+func stateAvailability(s State) int {
+	switch s {
+	case StateClosed, StateDisabled:
+		return 2
+	case StateHalfOpen:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// saturatingAdd adds two uint32 values, saturating at math.MaxUint32.
+func saturatingAdd(a, b uint32) uint32 {
+	if a > math.MaxUint32-b {
+		return math.MaxUint32
+	}
+	return a + b
+}
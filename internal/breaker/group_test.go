@@ -0,0 +1,190 @@
+package breaker
+
+import (
+	"errors"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestGroup_OneBadEndpointIsolated(t *testing.T) {
+	keys := []string{"ep-1", "ep-2", "ep-3", "ep-4", "ep-5"}
+	g := NewGroup(Settings{
+		Name:    "svc",
+		Timeout: time.Minute,
+	}, keys)
+
+	if got := g.HealthyKeys(); !reflect.DeepEqual(got, keys) {
+		t.Fatalf("Expected all keys healthy initially, got %v", got)
+	}
+
+	// ep-3 always fails, others always succeed
+	for i := 0; i < 10; i++ {
+		for _, key := range keys {
+			if key == "ep-3" {
+				g.Execute(key, failFunc)
+			} else {
+				g.Execute(key, successFunc)
+			}
+		}
+	}
+
+	if state := g.Breaker("ep-3").State(); state != StateOpen {
+		t.Errorf("Expected ep-3 breaker to be open, got %v", state)
+	}
+	for _, key := range []string{"ep-1", "ep-2", "ep-4", "ep-5"} {
+		if state := g.Breaker(key).State(); state != StateClosed {
+			t.Errorf("Expected %s breaker to be closed, got %v", key, state)
+		}
+	}
+
+	want := []string{"ep-1", "ep-2", "ep-4", "ep-5"}
+	if got := g.HealthyKeys(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Expected healthy keys %v, got %v", want, got)
+	}
+
+	if _, err := g.Execute("ep-3", successFunc); !errors.Is(err, ErrOpenState) {
+		t.Errorf("Expected ErrOpenState from bad endpoint, got %v", err)
+	}
+}
+
+func TestGroup_Metrics_Aggregates(t *testing.T) {
+	g := NewGroup(Settings{Name: "svc"}, []string{"a", "b"})
+
+	if m := g.Metrics(); m.State != StateClosed || m.Counts.Requests != 0 {
+		t.Errorf("Expected empty closed metrics, got %+v", m)
+	}
+
+	g.Execute("a", successFunc)
+	g.Execute("a", successFunc)
+	g.Execute("b", successFunc)
+	g.Execute("b", failFunc)
+
+	m := g.Metrics()
+	if m.Counts.Requests != 4 {
+		t.Errorf("Expected 4 requests, got %d", m.Counts.Requests)
+	}
+	if m.Counts.TotalSuccesses != 3 || m.Counts.TotalFailures != 1 {
+		t.Errorf("Expected 3 successes and 1 failure, got %+v", m.Counts)
+	}
+	if m.FailureRate != 0.25 {
+		t.Errorf("Expected failure rate 0.25, got %v", m.FailureRate)
+	}
+	if m.State != StateClosed {
+		t.Errorf("Expected aggregated state closed, got %v", m.State)
+	}
+}
+
+func TestGroup_Metrics_StateIsMostAvailable(t *testing.T) {
+	g := NewGroup(Settings{
+		Name:        "svc",
+		Timeout:     time.Minute,
+		ReadyToTrip: func(c Counts) bool { return c.ConsecutiveFailures >= 1 },
+	}, []string{"a", "b"})
+
+	g.Execute("a", failFunc)
+	g.Execute("b", successFunc)
+	if m := g.Metrics(); m.State != StateClosed {
+		t.Errorf("Expected closed while one child is closed, got %v", m.State)
+	}
+
+	g.Execute("b", failFunc)
+	if m := g.Metrics(); m.State != StateOpen {
+		t.Errorf("Expected open when all children are open, got %v", m.State)
+	}
+}
+
+func TestGroup_UpdateSettings_FanOut(t *testing.T) {
+	g := NewGroup(Settings{
+		Name:                 "svc",
+		AdaptiveThreshold:    true,
+		FailureRateThreshold: 0.05,
+	}, []string{"a", "b"})
+
+	g.Breaker("a")
+	g.Breaker("b")
+
+	if err := g.UpdateSettings(SettingsUpdate{
+		FailureRateThreshold: Float64Ptr(0.20),
+		Timeout:              DurationPtr(5 * time.Second),
+	}); err != nil {
+		t.Fatalf("UpdateSettings failed: %v", err)
+	}
+
+	for _, key := range []string{"a", "b"} {
+		cb := g.Breaker(key)
+		if got := cb.getFailureRateThreshold(); got != 0.20 {
+			t.Errorf("%s: expected threshold 0.20, got %v", key, got)
+		}
+		if got := cb.getTimeout(); got != 5*time.Second {
+			t.Errorf("%s: expected timeout 5s, got %v", key, got)
+		}
+	}
+
+	// Keys created later inherit the current settings
+	late := g.Breaker("c")
+	if got := late.getFailureRateThreshold(); got != 0.20 {
+		t.Errorf("Expected late key to inherit threshold 0.20, got %v", got)
+	}
+	if late.Name() != "svc/c" {
+		t.Errorf("Expected child name svc/c, got %q", late.Name())
+	}
+	if keys := g.Keys(); !reflect.DeepEqual(keys, []string{"a", "b", "c"}) {
+		t.Errorf("Expected keys [a b c], got %v", keys)
+	}
+}
+
+func TestGroup_UpdateSettings_AllOrNothing(t *testing.T) {
+	g := NewGroup(Settings{
+		Name:                 "svc",
+		AdaptiveThreshold:    true,
+		FailureRateThreshold: 0.05,
+	}, []string{"a", "b"})
+	g.Breaker("a")
+
+	err := g.UpdateSettings(SettingsUpdate{
+		MaxRequests:          Uint32Ptr(5),
+		FailureRateThreshold: Float64Ptr(1.5),
+	})
+	if err == nil {
+		t.Fatal("Expected validation error")
+	}
+
+	if got := g.Breaker("a").getMaxRequests(); got != 1 {
+		t.Errorf("Expected existing child unchanged, got MaxRequests=%d", got)
+	}
+	if got := g.Breaker("b").getMaxRequests(); got != 1 {
+		t.Errorf("Expected new child to use original settings, got MaxRequests=%d", got)
+	}
+}
+
+func TestGroup_ConcurrentBreakerCreation(t *testing.T) {
+	g := NewGroup(Settings{Name: "svc"}, nil)
+
+	var wg sync.WaitGroup
+	results := make([]*CircuitBreaker, 50)
+	for i := range results {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			results[i] = g.Breaker("shared")
+		}(i)
+	}
+	wg.Wait()
+
+	for _, cb := range results {
+		if cb != results[0] {
+			t.Fatal("Expected all goroutines to get the same breaker")
+		}
+	}
+}
+
+func TestNewGroup_InvalidSettingsPanics(t *testing.T) {
+	defer func() {
+		if r := recover(); r == nil {
+			t.Error("Expected panic for invalid settings")
+		}
+	}()
+	NewGroup(Settings{Interval: -time.Second}, []string{"a"})
+}
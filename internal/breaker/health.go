@@ -0,0 +1,61 @@
+package breaker
+
+// effectiveRecoverFailureRate returns the effective recovery threshold.
+// Zero (unset) means the recovery threshold equals FailureRateThreshold.
+func (cb *CircuitBreaker) effectiveRecoverFailureRate() float64 {
+	if recoverRate := cb.getRecoverFailureRate(); recoverRate > 0 {
+		return recoverRate
+	}
+	return cb.getFailureRateThreshold()
+}
+
+// updateHealth applies trip/recover hysteresis to the health latch for the
+// failure rate after an outcome (adaptive mode only).
+//
+// The backend is marked unhealthy when the failure rate exceeds FailureRateThreshold
+// (the trip rate) and is considered healthy again only once the rate is at or below
+// RecoverFailureRate. A rate hovering between the two thresholds never flips health.
+// While unhealthy, the adaptive trip rule uses RecoverFailureRate (see onProbation).
+// During a RecoveryWindows period only clean windows clear the latch.
+func (cb *CircuitBreaker) updateHealth(rate float64) {
+	if !cb.adaptiveThreshold {
+		return
+	}
+
+	switch {
+	case cb.rateExceeds(rate, cb.getFailureRateThreshold()):
+		cb.unhealthy.Store(true)
+	case cb.recoveryWindowsLeft.Load() > 0:
+		// Recovery is credited per window (endRecoveryWindow)
+	case !cb.rateExceeds(rate, cb.effectiveRecoverFailureRate()):
+		cb.unhealthy.Store(false)
+	}
+}
+
+// onProbation reports whether the adaptive trip rule applies RecoverFailureRate
+// instead of FailureRateThreshold: hysteresis is configured (RecoverFailureRate
+// below the trip rate) and the backend has not shown a rate at or below
+// RecoverFailureRate since it last exceeded the trip rate.
+func (cb *CircuitBreaker) onProbation() bool {
+	recoverRate := cb.getRecoverFailureRate()
+	return recoverRate > 0 && cb.unhealthy.Load() && recoverRate < cb.getFailureRateThreshold()
+}
+
+// probesRecovered reports whether an exhausted probe budget closes the circuit.
+// With RecoverFailureRate set in adaptive mode the probes must fail at a rate at
+// or below it; otherwise successes must outnumber failures.
+func (cb *CircuitBreaker) probesRecovered(successes, failures uint32) bool {
+	recoverRate := cb.getRecoverFailureRate()
+	if !cb.adaptiveThreshold || recoverRate <= 0 {
+		return successes > failures
+	}
+	rate := float64(failures) / float64(successes+failures)
+	return !cb.rateExceeds(rate, recoverRate)
+}
+
+// isHealthy reports whether the backend is considered healthy: the circuit is
+// Closed and, in adaptive mode, the failure rate has recovered to RecoverFailureRate
+// since the circuit last tripped or exceeded the trip rate.
+func (cb *CircuitBreaker) isHealthy(state State) bool {
+	return state == StateClosed && !cb.unhealthy.Load()
+}
@@ -0,0 +1,46 @@
+package breaker
+
+import "math"
+
+// HealthScore returns a health score from 0.0 (failing) to 1.0 (healthy) for
+// weighting traffic, e.g. by a load balancer that shifts load between endpoints
+// in proportion to their health.
+//
+// With HealthScoreAlpha set, the score is the exponentially smoothed success
+// rate over recent outcomes (see HealthScoreAlpha); it starts at 1.0. Without
+// it, the score is the current window's success rate (1.0 with no requests).
+// Either way HealthScore returns 0 while the circuit is Open, since calls are
+// being rejected; the smoothed score resumes from its last value once probing
+// starts.
+//
+// Performance: A few atomic loads; no locks.
+//
+// Thread-safe: Safe to call concurrently with Execute().
+//
+// Example:
+//
+//	for _, ep := range endpoints {
+//	    balancer.SetWeight(ep.Addr, int(100*ep.Breaker.HealthScore()))
+//	}
+func (cb *CircuitBreaker) HealthScore() float64 {
+	if cb.machineState() == StateOpen {
+		return 0
+	}
+	if cb.healthScoreAlpha > 0 {
+		return math.Float64frombits(cb.healthScore.Load())
+	}
+	return 1 - cb.failureRate(cb.Counts())
+}
+
+// updateHealthScore moves the smoothed score toward outcome (1 for a success,
+// 0 for a failure) by HealthScoreAlpha.
+func (cb *CircuitBreaker) updateHealthScore(outcome float64) {
+	for {
+		old := cb.healthScore.Load()
+		score := math.Float64frombits(old)
+		score += cb.healthScoreAlpha * (outcome - score)
+		if cb.healthScore.CompareAndSwap(old, math.Float64bits(score)) {
+			return
+		}
+	}
+}
@@ -0,0 +1,154 @@
+package breaker
+
+import (
+	"errors"
+	"math"
+	"testing"
+)
+
+func TestHealthScore_ConvergesTowardNewRate(t *testing.T) {
+	const alpha = 0.1
+	cb := New(Settings{
+		Name:             "health-score",
+		HealthScoreAlpha: alpha,
+		ReadyToTrip:      func(Counts) bool { return false },
+	})
+
+	if got := cb.HealthScore(); got != 1 {
+		t.Fatalf("Expected initial score 1.0, got %v", got)
+	}
+
+	// Success rate drops from 100% to 0%: after n failures the score has moved
+	// 1 - (1-alpha)^n of the way to 0
+	for n := 1; n <= 20; n++ {
+		cb.Execute(failFunc)
+		want := math.Pow(1-alpha, float64(n))
+		if got := cb.HealthScore(); math.Abs(got-want) > 1e-9 {
+			t.Fatalf("After %d failures: score = %v, want %v", n, got, want)
+		}
+	}
+
+	// Back to 100%: half way to 1 in log(0.5)/log(1-alpha) ≈ 6.6 outcomes
+	start := cb.HealthScore()
+	for i := 0; i < 7; i++ {
+		cb.Execute(successFunc)
+	}
+	if got := cb.HealthScore(); got < start+(1-start)/2 || got > start+(1-start)*0.6 {
+		t.Errorf("After 7 successes from %v: score = %v, want just past half way to 1", start, got)
+	}
+}
+
+func TestHealthScore_TracksMixedRate(t *testing.T) {
+	cb := New(Settings{
+		Name:             "health-score-mixed",
+		HealthScoreAlpha: 0.05,
+		ReadyToTrip:      func(Counts) bool { return false },
+	})
+
+	// 75% success rate: alternate 3 successes and 1 failure
+	for i := 0; i < 400; i++ {
+		if i%4 == 3 {
+			cb.Execute(failFunc)
+		} else {
+			cb.Execute(successFunc)
+		}
+	}
+
+	if got := cb.HealthScore(); math.Abs(got-0.75) > 0.05 {
+		t.Errorf("Expected score near 0.75, got %v", got)
+	}
+}
+
+func TestHealthScore_AlphaControlsSpeed(t *testing.T) {
+	newBreaker := func(alpha float64) *CircuitBreaker {
+		return New(Settings{
+			Name:             "health-score-speed",
+			HealthScoreAlpha: alpha,
+			ReadyToTrip:      func(Counts) bool { return false },
+		})
+	}
+	slow, fast := newBreaker(0.05), newBreaker(0.5)
+
+	for i := 0; i < 5; i++ {
+		slow.Execute(failFunc)
+		fast.Execute(failFunc)
+	}
+
+	if slow.HealthScore() <= fast.HealthScore() {
+		t.Errorf("Expected higher alpha to react faster: slow %v, fast %v",
+			slow.HealthScore(), fast.HealthScore())
+	}
+}
+
+func TestHealthScore_OutcomeWeight(t *testing.T) {
+	errSlow := errors.New("slow")
+	cb := New(Settings{
+		Name:             "health-score-weight",
+		HealthScoreAlpha: 1, // No smoothing: the score is the last outcome
+		ReadyToTrip:      func(Counts) bool { return false },
+		OutcomeWeight: func(result interface{}, err error) float64 {
+			if errors.Is(err, errSlow) {
+				return 0.25
+			}
+			if err != nil {
+				return 1
+			}
+			return 0
+		},
+	})
+
+	cb.Execute(func() (interface{}, error) { return nil, errSlow })
+
+	if got := cb.HealthScore(); got != 0.75 {
+		t.Errorf("Expected a 0.25-weight failure to score 0.75, got %v", got)
+	}
+}
+
+func TestHealthScore_ZeroWhenOpen(t *testing.T) {
+	cb := New(tripOnFirstFailure(Settings{Name: "health-score-open", HealthScoreAlpha: 0.1}))
+
+	cb.Execute(failFunc)
+	if cb.State() != StateOpen {
+		t.Fatalf("Expected Open, got %v", cb.State())
+	}
+	if got := cb.HealthScore(); got != 0 {
+		t.Errorf("Expected score 0 while Open, got %v", got)
+	}
+}
+
+func TestHealthScore_PanicCountsAsFailure(t *testing.T) {
+	cb := New(Settings{
+		Name:             "health-score-panic",
+		HealthScoreAlpha: 0.5,
+		ReadyToTrip:      func(Counts) bool { return false },
+	})
+
+	func() {
+		defer func() { _ = recover() }()
+		cb.Execute(panicFunc)
+	}()
+
+	if got := cb.HealthScore(); got != 0.5 {
+		t.Errorf("Expected score 0.5 after a panic, got %v", got)
+	}
+}
+
+func TestHealthScore_WindowRateWithoutAlpha(t *testing.T) {
+	cb := New(Settings{Name: "health-score-window"})
+
+	if got := cb.HealthScore(); got != 1 {
+		t.Errorf("Expected 1.0 with no requests, got %v", got)
+	}
+
+	for i := 0; i < 3; i++ {
+		cb.Execute(successFunc)
+	}
+	cb.Execute(failFunc)
+
+	if got := cb.HealthScore(); got != 0.75 {
+		t.Errorf("Expected the window success rate 0.75, got %v", got)
+	}
+	if got := cb.CurrentSettings().HealthScoreAlpha; got != 0 {
+		t.Errorf("Expected HealthScoreAlpha 0 in CurrentSettings, got %v", got)
+	}
+}
@@ -0,0 +1,211 @@
+package breaker
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+// runRate executes n requests, the last `failures` of which fail.
+func runRate(cb *CircuitBreaker, failures, n int) {
+	for i := 0; i < n; i++ {
+		if i >= n-failures {
+			cb.Execute(failFunc)
+		} else {
+			cb.Execute(successFunc)
+		}
+	}
+}
+
+// hysteresisSettings trips at 10% and recovers at 5%.
+func hysteresisSettings(name string) Settings {
+	return Settings{
+		Name:                 name,
+		Timeout:              10 * time.Second,
+		AdaptiveThreshold:    true,
+		FailureRateThreshold: 0.10,
+		RecoverFailureRate:   0.05,
+		MinimumObservations:  20,
+	}
+}
+
+// hover runs five rounds of 13 successes and a failure: a 7.1% failure rate,
+// between the recover (5%) and trip (10%) rates.
+func hover(cb *CircuitBreaker) {
+	for i := 0; i < 5; i++ {
+		for j := 0; j < 13; j++ {
+			cb.Execute(successFunc)
+		}
+		cb.Execute(failFunc)
+	}
+}
+
+func TestRecoverFailureRate_NoOscillationBetweenThresholds(t *testing.T) {
+	var transitions int
+	settings := hysteresisSettings("hover")
+	settings.OnStateChange = func(string, State, State) { transitions++ }
+	cb := New(settings)
+
+	// Healthy start
+	runRate(cb, 0, 20)
+	if !cb.Diagnostics().Healthy {
+		t.Fatal("Expected healthy at 0% failure rate")
+	}
+
+	// Hover between recover (5%) and trip (10%): no trip, still healthy
+	cb.resetCounts()
+	hover(cb)
+	if cb.State() != StateClosed || !cb.Diagnostics().Healthy {
+		t.Fatalf("Expected closed and healthy while below trip rate, got state=%v healthy=%v",
+			cb.State(), cb.Diagnostics().Healthy)
+	}
+	if transitions != 0 {
+		t.Fatalf("Expected no transitions while hovering, got %d", transitions)
+	}
+}
+
+func TestRecoverFailureRate_TripsOnRecoverRateUntilRecovered(t *testing.T) {
+	clk := newFakeClock()
+	cb := newWithClock(clk, hysteresisSettings("hysteresis"))
+
+	// Exceed the trip rate, then re-close through a probe
+	runRate(cb, 3, 20) // 15%
+	if cb.State() != StateOpen {
+		t.Fatalf("Expected open above trip rate, got %v", cb.State())
+	}
+	clk.advance(10 * time.Second)
+	cb.Execute(successFunc)
+	if cb.State() != StateClosed {
+		t.Fatalf("Expected closed after probe, got %v", cb.State())
+	}
+	diag := cb.Diagnostics()
+	if diag.Healthy || !diag.RecoveryComparison || diag.EffectiveFailureRateThreshold != 0.05 {
+		t.Fatalf("Expected unhealthy and tripping at the recover rate after re-close, got healthy=%v comparison=%v threshold=%v",
+			diag.Healthy, diag.RecoveryComparison, diag.EffectiveFailureRateThreshold)
+	}
+
+	// Still failing at the trip rate, which a recovered backend is allowed:
+	// reopens rather than staying closed
+	for i := 0; i < 20; i++ {
+		if i%10 == 9 {
+			cb.Execute(failFunc)
+		} else {
+			cb.Execute(successFunc)
+		}
+	}
+	if cb.State() != StateOpen {
+		t.Fatalf("Expected a rate above RecoverFailureRate to reopen before recovery, got %v", cb.State())
+	}
+	if reason := cb.Diagnostics().OpenReason; !strings.Contains(reason.Detail, "5.00% (recovery)") {
+		t.Errorf("Expected the trip reason to name the recover rate, got %+v", reason)
+	}
+
+	// Recovering to the lower rate restores the trip rate
+	clk.advance(10 * time.Second)
+	cb.Execute(successFunc)
+	runRate(cb, 1, 20) // 5%
+	diag = cb.Diagnostics()
+	if !diag.Healthy || diag.RecoveryComparison || diag.EffectiveFailureRateThreshold != 0.10 {
+		t.Fatalf("Expected healthy on the trip rate once at RecoverFailureRate, got healthy=%v comparison=%v threshold=%v",
+			diag.Healthy, diag.RecoveryComparison, diag.EffectiveFailureRateThreshold)
+	}
+	cb.resetCounts()
+	hover(cb)
+	if cb.State() != StateClosed {
+		t.Errorf("Expected no trip between the thresholds once recovered, got %v", cb.State())
+	}
+}
+
+func TestRecoverFailureRate_ProbesCloseAtRecoverRate(t *testing.T) {
+	clk := newFakeClock()
+	settings := hysteresisSettings("probe-hysteresis")
+	settings.FailureRateThreshold = 0.5
+	settings.RecoverFailureRate = 0.2
+	settings.HalfOpenMaxProbes = 4
+	cb := newWithClock(clk, settings)
+
+	probeRound := func(failures int) {
+		clk.advance(10 * time.Second)
+		runRate(cb, failures, 4)
+	}
+
+	runRate(cb, 20, 20)
+	// 3 of 4 probes succeed: a majority, but 25% fails above the recover rate
+	probeRound(1)
+	if cb.State() != StateOpen {
+		t.Fatalf("Expected probes failing above RecoverFailureRate to reopen, got %v", cb.State())
+	}
+
+	// Without hysteresis the same probes close the circuit
+	settings.RecoverFailureRate = 0
+	cb = newWithClock(clk, settings)
+	runRate(cb, 20, 20)
+	probeRound(1)
+	if cb.State() != StateClosed {
+		t.Fatalf("Expected a probe majority to close without RecoverFailureRate, got %v", cb.State())
+	}
+}
+
+func TestRecoverFailureRate_DefaultsToTripRate(t *testing.T) {
+	cb := New(Settings{
+		Name:                 "test",
+		AdaptiveThreshold:    true,
+		FailureRateThreshold: 0.10,
+		MinimumObservations:  10,
+	})
+
+	if got := cb.Diagnostics().RecoverFailureRate; got != 0.10 {
+		t.Errorf("Expected RecoverFailureRate to default to 0.10, got %v", got)
+	}
+
+	// Follows runtime threshold updates
+	if err := cb.UpdateSettings(SettingsUpdate{FailureRateThreshold: Float64Ptr(0.2)}); err != nil {
+		t.Fatalf("UpdateSettings failed: %v", err)
+	}
+	if got := cb.Diagnostics().RecoverFailureRate; got != 0.2 {
+		t.Errorf("Expected RecoverFailureRate to follow threshold, got %v", got)
+	}
+}
+
+func TestRecoverFailureRate_StaticModeHealthIsState(t *testing.T) {
+	cb := New(Settings{Name: "test", Timeout: time.Minute})
+	if !cb.Diagnostics().Healthy {
+		t.Error("Expected healthy when closed")
+	}
+	runRate(cb, 6, 6)
+	if cb.Diagnostics().Healthy {
+		t.Error("Expected unhealthy when open")
+	}
+}
+
+func TestRecoverFailureRate_StaticModeHealthyAfterRecovery(t *testing.T) {
+	const timeout = time.Minute
+	clk := newFakeClock()
+	cb := newWithClock(clk, Settings{Name: "test", Timeout: timeout})
+
+	runRate(cb, 6, 6)
+	clk.advance(timeout)
+	cb.Execute(successFunc)
+	if cb.State() != StateClosed {
+		t.Fatalf("Expected Closed after the probe, got %v", cb.State())
+	}
+
+	if !cb.Diagnostics().Healthy {
+		t.Error("Expected healthy once closed again in static mode")
+	}
+	if summary := cb.AlertSummary(); strings.Contains(summary, "recovering") {
+		t.Errorf("Expected no recovering note once closed, got %q", summary)
+	}
+}
+
+func TestRecoverFailureRate_Validation(t *testing.T) {
+	if err := validateSettings(&Settings{AdaptiveThreshold: true, FailureRateThreshold: 0.1, RecoverFailureRate: 0.2}); err == nil {
+		t.Error("Expected error for RecoverFailureRate above trip rate")
+	}
+	if err := validateSettings(&Settings{AdaptiveThreshold: true, RecoverFailureRate: -0.01}); err == nil {
+		t.Error("Expected error for negative RecoverFailureRate")
+	}
+	if err := validateSettings(&Settings{AdaptiveThreshold: true, FailureRateThreshold: 0.1, RecoverFailureRate: 0.1}); err != nil {
+		t.Errorf("Expected RecoverFailureRate equal to trip rate to be valid, got %v", err)
+	}
+}
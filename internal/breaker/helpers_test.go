@@ -53,3 +53,26 @@ const (
 	moduloFor12Percent = 8  // 1/8 = 12.5%
 	moduloFor20Percent = 5  // 1/5 = 20%
 )
+
+// tripToHalfOpen trips cb with a failure and advances clk to the end of the
+// open period, so the next call probes.
+func tripToHalfOpen(t *testing.T, cb *CircuitBreaker, clk *fakeClock) {
+	t.Helper()
+	cb.Execute(failFunc)
+	if cb.State() != StateOpen {
+		t.Fatalf("Expected Open after the trip, got %v", cb.State())
+	}
+	clk.advance(cb.openWait(cb.openedAt.Load()))
+}
+
+// recoverFromTrip trips cb and closes it again through a successful probe.
+func recoverFromTrip(t *testing.T, cb *CircuitBreaker, clk *fakeClock) {
+	t.Helper()
+	tripToHalfOpen(t, cb, clk)
+	if _, err := cb.Execute(successFunc); err != nil {
+		t.Fatalf("Expected the probe to run, got %v", err)
+	}
+	if cb.State() != StateClosed {
+		t.Fatalf("Expected Closed after the probe, got %v", cb.State())
+	}
+}
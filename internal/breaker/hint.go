@@ -0,0 +1,58 @@
+package breaker
+
+import "time"
+
+// ExecuteWithHint runs req like Execute, but req may also report how long the
+// backend asked callers to back off (for example, an HTTP Retry-After header on
+// a 503). A positive hint replaces Timeout for the next open period: if this
+// call trips the circuit, or a later one does before the hint expires, the
+// circuit stays open until the backend-requested time instead of for Timeout.
+//
+// The hint is measured from when req returns. A zero or negative hint leaves
+// the current hint unchanged; a newer positive hint replaces an older one. A
+// hint that expires before the circuit trips has no effect, and each hint
+// applies to one open period only. A failed half-open probe that reports a hint
+// reopens the circuit for the hinted duration.
+//
+// The hint may lengthen or shorten the open period. MaxOpenDuration still
+// bounds how long the circuit stays open, and UpdateSettings with a new Timeout
+// discards a hint applied to the current open period.
+//
+// Everything else behaves as in Execute. The result and error are returned
+// unchanged.
+//
+// Thread-safe: Safe to call concurrently.
+//
+// Example - honoring Retry-After:
+//
+//	result, err := breaker.ExecuteWithHint(func() (interface{}, error, time.Duration) {
+//	    resp, err := client.Do(req)
+//	    if err != nil {
+//	        return nil, err, 0
+//	    }
+//	    if resp.StatusCode == http.StatusServiceUnavailable {
+//	        secs, _ := strconv.Atoi(resp.Header.Get("Retry-After"))
+//	        return nil, errUnavailable, time.Duration(secs) * time.Second
+//	    }
+//	    return resp, nil, 0
+//	})
+func (cb *CircuitBreaker) ExecuteWithHint(req func() (interface{}, error, time.Duration)) (interface{}, error) {
+	return cb.execute(func() (interface{}, error) {
+		result, err, hint := req()
+		if hint > 0 {
+			cb.retryAfterUntil.Store(cb.now() + int64(hint))
+		}
+		return result, err
+	}, nil, false, anyProbe)
+}
+
+// takeRetryAfter consumes the pending backoff hint for a circuit opening at now
+// (UnixNano). Returns the hinted end of the open period, or 0 if there is no
+// hint or it has expired.
+func (cb *CircuitBreaker) takeRetryAfter(now int64) int64 {
+	until := cb.retryAfterUntil.Swap(0)
+	if until <= now {
+		return 0
+	}
+	return until
+}
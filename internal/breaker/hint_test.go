@@ -0,0 +1,159 @@
+package breaker
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+var errUnavailable = errors.New("503 service unavailable")
+
+// hinted returns a request for ExecuteWithHint that fails reporting hint.
+func hinted(hint time.Duration) func() (interface{}, error, time.Duration) {
+	return func() (interface{}, error, time.Duration) {
+		return nil, errUnavailable, hint
+	}
+}
+
+func TestExecuteWithHint_RetryAfterReplacesTimeout(t *testing.T) {
+	cb := New(tripOnFirstFailure(Settings{Name: "retry-after"}))
+
+	_, err := cb.ExecuteWithHint(hinted(30 * time.Second))
+	if !errors.Is(err, errUnavailable) {
+		t.Fatalf("Expected request error returned unchanged, got %v", err)
+	}
+	if cb.State() != StateOpen {
+		t.Fatalf("Expected Open, got %v", cb.State())
+	}
+
+	// The open period follows Retry-After, not the 60s default Timeout
+	openedAt := cb.openedAt.Load()
+	if wait := cb.openWait(openedAt); wait <= 29*time.Second || wait > 30*time.Second {
+		t.Errorf("Expected open wait of about 30s, got %v", wait)
+	}
+	if wait := cb.Diagnostics().TimeUntilHalfOpen; wait <= 29*time.Second || wait > 30*time.Second {
+		t.Errorf("Expected TimeUntilHalfOpen of about 30s, got %v", wait)
+	}
+}
+
+func TestExecuteWithHint_LongerThanTimeout(t *testing.T) {
+	cb := New(tripOnFirstFailure(Settings{Name: "retry-after-long", Timeout: 10 * time.Millisecond}))
+
+	cb.ExecuteWithHint(hinted(time.Minute))
+	time.Sleep(20 * time.Millisecond)
+
+	// Timeout has elapsed, but the backend asked for a minute
+	if _, err := cb.Execute(successFunc); !errors.Is(err, ErrOpenState) {
+		t.Errorf("Expected ErrOpenState while Retry-After is pending, got %v", err)
+	}
+}
+
+func TestExecuteWithHint_ShorterThanTimeout(t *testing.T) {
+	cb := New(tripOnFirstFailure(Settings{Name: "retry-after-short", Timeout: time.Hour}))
+
+	cb.ExecuteWithHint(hinted(20 * time.Millisecond))
+	time.Sleep(30 * time.Millisecond)
+
+	if _, err := cb.Execute(successFunc); err != nil {
+		t.Fatalf("Expected probe admitted after Retry-After, got %v", err)
+	}
+	if cb.State() != StateClosed {
+		t.Errorf("Expected successful probe to close, got %v", cb.State())
+	}
+}
+
+func TestExecuteWithHint_PendingHintAppliesToLaterTrip(t *testing.T) {
+	cb := New(Settings{
+		Name:        "retry-after-pending",
+		ReadyToTrip: func(counts Counts) bool { return counts.ConsecutiveFailures >= 2 },
+	})
+
+	// First failure reports Retry-After but does not trip
+	cb.ExecuteWithHint(hinted(30 * time.Second))
+	if cb.State() != StateClosed {
+		t.Fatalf("Expected Closed after one failure, got %v", cb.State())
+	}
+
+	// A plain failure trips; the pending hint still applies
+	cb.Execute(failFunc)
+	if cb.State() != StateOpen {
+		t.Fatalf("Expected Open, got %v", cb.State())
+	}
+	if wait := cb.openWait(cb.openedAt.Load()); wait > 30*time.Second || wait <= 29*time.Second {
+		t.Errorf("Expected open wait of about 30s, got %v", wait)
+	}
+}
+
+func TestExecuteWithHint_ExpiredHintIgnored(t *testing.T) {
+	cb := New(Settings{
+		Name:        "retry-after-expired",
+		ReadyToTrip: func(counts Counts) bool { return counts.ConsecutiveFailures >= 2 },
+	})
+
+	cb.ExecuteWithHint(hinted(time.Millisecond))
+	time.Sleep(5 * time.Millisecond)
+	cb.Execute(failFunc)
+
+	if wait := cb.openWait(cb.openedAt.Load()); wait != time.Minute {
+		t.Errorf("Expected default Timeout after the hint expired, got %v", wait)
+	}
+}
+
+func TestExecuteWithHint_AppliesToOneOpenPeriod(t *testing.T) {
+	cb := New(tripOnFirstFailure(Settings{Name: "retry-after-once", Timeout: 10 * time.Millisecond}))
+
+	cb.ExecuteWithHint(hinted(20 * time.Millisecond))
+	time.Sleep(30 * time.Millisecond)
+
+	// The probe fails without a hint: reopen for Timeout
+	cb.Execute(failFunc)
+	if cb.State() != StateOpen {
+		t.Fatalf("Expected Open after failed probe, got %v", cb.State())
+	}
+	if wait := cb.openWait(cb.openedAt.Load()); wait != 10*time.Millisecond {
+		t.Errorf("Expected Timeout after the hinted period, got %v", wait)
+	}
+}
+
+func TestExecuteWithHint_FailedProbeReopensForHint(t *testing.T) {
+	cb := New(tripOnFirstFailure(Settings{Name: "retry-after-probe", Timeout: 10 * time.Millisecond}))
+
+	cb.Execute(failFunc)
+	time.Sleep(20 * time.Millisecond)
+
+	cb.ExecuteWithHint(hinted(30 * time.Second))
+	if cb.State() != StateOpen {
+		t.Fatalf("Expected Open after failed probe, got %v", cb.State())
+	}
+	if wait := cb.openWait(cb.openedAt.Load()); wait <= 29*time.Second || wait > 30*time.Second {
+		t.Errorf("Expected open wait of about 30s, got %v", wait)
+	}
+}
+
+func TestExecuteWithHint_SuccessWithoutHint(t *testing.T) {
+	cb := New(Settings{Name: "retry-after-success"})
+
+	result, err := cb.ExecuteWithHint(func() (interface{}, error, time.Duration) {
+		return "ok", nil, 0
+	})
+	if err != nil || result != "ok" {
+		t.Errorf("Expected (ok, nil), got (%v, %v)", result, err)
+	}
+	if got := cb.retryAfterUntil.Load(); got != 0 {
+		t.Errorf("Expected no pending hint, got %d", got)
+	}
+}
+
+func TestExecuteWithHint_TimeoutUpdateDiscardsHint(t *testing.T) {
+	cb := New(tripOnFirstFailure(Settings{Name: "retry-after-update"}))
+
+	cb.ExecuteWithHint(hinted(30 * time.Second))
+	timeout := 5 * time.Second
+	if _, err := cb.UpdateSettingsDetailed(SettingsUpdate{Timeout: &timeout}); err != nil {
+		t.Fatalf("UpdateSettingsDetailed: %v", err)
+	}
+
+	if wait := cb.openWait(cb.openedAt.Load()); wait != timeout {
+		t.Errorf("Expected the updated Timeout, got %v", wait)
+	}
+}
@@ -0,0 +1,115 @@
+package breaker
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+// defaultHistoryRetention is the number of samples kept when
+// MetricsHistoryRetention is not set.
+const defaultHistoryRetention = 60
+
+// MetricsSample is one point of a breaker's metrics history, recorded every
+// MetricsHistoryInterval and returned by History().
+//
+// Requests and Failures are the Closed-state counts at sample time (see
+// Counts), so they drop back to zero at Interval resets and state changes.
+// State marshals to JSON as its integer value; use State.String() for display.
+type MetricsSample struct {
+	Time        time.Time `json:"time"`
+	State       State     `json:"state"`
+	Requests    uint32    `json:"requests"`
+	Failures    uint32    `json:"failures"`
+	FailureRate float64   `json:"failure_rate"`
+}
+
+// metricsHistory is a fixed-size ring of samples.
+//
+// Writers are serialized by the CAS on nextAt: only the request that moves
+// nextAt forward records a sample. Slots hold immutable samples behind atomic
+// pointers, so History() can read concurrently with a write.
+type metricsHistory struct {
+	nextAt  atomic.Int64  // UnixNano at which the next sample is due
+	written atomic.Uint64 // Samples recorded so far; the next goes to slot written % len(slots)
+	slots   []atomic.Pointer[MetricsSample]
+}
+
+func newMetricsHistory(retention uint32) *metricsHistory {
+	return &metricsHistory{
+		slots: make([]atomic.Pointer[MetricsSample], retention),
+	}
+}
+
+// maybeSampleHistory records a sample at now if one is due. Of concurrent
+// requests finding a sample due, exactly one (the CAS winner) records it.
+func (cb *CircuitBreaker) maybeSampleHistory(now int64) {
+	h := cb.history
+	due := h.nextAt.Load()
+	if now < due {
+		return
+	}
+	if !h.nextAt.CompareAndSwap(due, now+int64(cb.historyInterval)) {
+		return // Another request is recording this sample
+	}
+
+	counts := cb.Counts()
+	sample := &MetricsSample{
+		Time:        time.Unix(0, now),
+		State:       cb.State(),
+		Requests:    counts.Requests,
+		Failures:    counts.TotalFailures,
+		FailureRate: cb.failureRate(counts),
+	}
+
+	n := h.written.Load()
+	h.slots[n%uint64(len(h.slots))].Store(sample)
+	h.written.Store(n + 1)
+}
+
+// History returns up to the n most recent metrics samples, oldest first. With
+// n <= 0 it returns every retained sample.
+//
+// Returns nil when MetricsHistoryInterval is not set. Samples are recorded by
+// requests (see MetricsHistoryInterval), so a breaker that has seen no traffic
+// has no history yet.
+//
+// Thread-safe: Safe to call concurrently with Execute(). A sample recorded
+// during the call may or may not be included.
+//
+// Example - failure rate sparkline:
+//
+//	for _, s := range breaker.History(0) {
+//	    points = append(points, s.FailureRate)
+//	}
+func (cb *CircuitBreaker) History(n int) []MetricsSample {
+	h := cb.history
+	if h == nil {
+		return nil
+	}
+
+	written := h.written.Load()
+	available := written
+	if size := uint64(len(h.slots)); available > size {
+		available = size
+	}
+	if n <= 0 || uint64(n) > available {
+		n = int(available)
+	}
+
+	samples := make([]MetricsSample, 0, n)
+	for i := written - uint64(n); i < written; i++ {
+		if sample := h.slots[i%uint64(len(h.slots))].Load(); sample != nil {
+			samples = append(samples, *sample)
+		}
+	}
+	return samples
+}
+
+// historyRetention returns the configured number of retained samples, or 0
+// without history.
+func (cb *CircuitBreaker) historyRetention() uint32 {
+	if cb.history == nil {
+		return 0
+	}
+	return uint32(len(cb.history.slots))
+}
@@ -0,0 +1,135 @@
+package breaker
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestHistory_SampleSpacing(t *testing.T) {
+	cb := New(Settings{
+		Name:                   "history",
+		MetricsHistoryInterval: 5 * time.Second,
+	})
+	base := time.Now().UnixNano()
+	sec := int64(time.Second)
+
+	// Requests every second: a sample at 0s, 5s and 10s
+	for i := int64(0); i <= 12; i++ {
+		cb.maybeSampleHistory(base + i*sec)
+	}
+
+	samples := cb.History(0)
+	if len(samples) != 3 {
+		t.Fatalf("Expected 3 samples, got %d", len(samples))
+	}
+	for i, s := range samples {
+		if want := time.Unix(0, base+int64(i)*5*sec); !s.Time.Equal(want) {
+			t.Errorf("Sample %d: expected time %v, got %v", i, want, s.Time)
+		}
+	}
+
+	// After a quiet period, the next request samples immediately
+	cb.maybeSampleHistory(base + 60*sec)
+	samples = cb.History(1)
+	if len(samples) != 1 || !samples[0].Time.Equal(time.Unix(0, base+60*sec)) {
+		t.Errorf("Expected a sample at 60s, got %+v", samples)
+	}
+}
+
+func TestHistory_RingWrapAround(t *testing.T) {
+	cb := New(Settings{
+		Name:                    "history",
+		MetricsHistoryInterval:  time.Second,
+		MetricsHistoryRetention: 4,
+	})
+	base := time.Now().UnixNano()
+
+	for i := int64(0); i < 10; i++ {
+		cb.maybeSampleHistory(base + i*int64(time.Second))
+	}
+
+	samples := cb.History(0)
+	if len(samples) != 4 {
+		t.Fatalf("Expected retention of 4 samples, got %d", len(samples))
+	}
+	for i, s := range samples {
+		// Oldest first: samples 6..9 survive
+		if want := time.Unix(0, base+int64(6+i)*int64(time.Second)); !s.Time.Equal(want) {
+			t.Errorf("Sample %d: expected time %v, got %v", i, want, s.Time)
+		}
+	}
+
+	if got := cb.History(2); len(got) != 2 || !got[1].Time.Equal(samples[3].Time) {
+		t.Errorf("Expected the 2 most recent samples, got %+v", got)
+	}
+	if got := cb.History(100); len(got) != 4 {
+		t.Errorf("Expected n capped at retention, got %d samples", len(got))
+	}
+}
+
+func TestHistory_SampleContents(t *testing.T) {
+	cb := New(Settings{
+		Name:                   "history",
+		MetricsHistoryInterval: time.Hour,
+	})
+
+	cb.Execute(successFunc) // first request samples the empty breaker
+	cb.Execute(failFunc)
+	cb.Execute(successFunc)
+	cb.Execute(failFunc)
+	cb.maybeSampleHistory(time.Now().Add(2 * time.Hour).UnixNano())
+
+	samples := cb.History(0)
+	if len(samples) != 2 {
+		t.Fatalf("Expected 2 samples, got %d", len(samples))
+	}
+	if s := samples[0]; s.Requests != 0 || s.State != StateClosed {
+		t.Errorf("Expected an empty first sample, got %+v", s)
+	}
+	if s := samples[1]; s.Requests != 4 || s.Failures != 2 || s.FailureRate != 0.5 || s.State != StateClosed {
+		t.Errorf("Expected 4 requests, 2 failures, rate 0.5, got %+v", s)
+	}
+}
+
+func TestHistory_ConcurrentSampling(t *testing.T) {
+	cb := New(Settings{
+		Name:                   "history",
+		MetricsHistoryInterval: time.Hour,
+	})
+
+	var wg sync.WaitGroup
+	for i := 0; i < 100; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			cb.Execute(successFunc)
+			cb.History(0)
+		}()
+	}
+	wg.Wait()
+
+	if got := len(cb.History(0)); got != 1 {
+		t.Errorf("Expected exactly one sample recorded, got %d", got)
+	}
+}
+
+func TestHistory_Disabled(t *testing.T) {
+	cb := New(Settings{Name: "history"})
+	cb.Execute(successFunc)
+
+	if got := cb.History(0); got != nil {
+		t.Errorf("Expected nil history when disabled, got %+v", got)
+	}
+	if s := cb.CurrentSettings(); s.MetricsHistoryRetention != 0 {
+		t.Errorf("Expected no retention reported, got %d", s.MetricsHistoryRetention)
+	}
+}
+
+func TestHistory_DefaultRetention(t *testing.T) {
+	cb := New(Settings{Name: "history", MetricsHistoryInterval: time.Second})
+
+	if s := cb.CurrentSettings(); s.MetricsHistoryRetention != defaultHistoryRetention {
+		t.Errorf("Expected default retention %d, got %d", defaultHistoryRetention, s.MetricsHistoryRetention)
+	}
+}
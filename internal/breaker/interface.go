@@ -0,0 +1,31 @@
+package breaker
+
+import "context"
+
+// Breaker is the core circuit breaker contract: admission and outcome recording
+// through Execute and ExecuteContext, observed through State and Counts.
+//
+// *CircuitBreaker implements Breaker. Decorators (a logging breaker, a
+// multi-tenant breaker routing to per-tenant breakers) can implement it too, and
+// accept any Breaker where they only need the contract. The autobreakertest
+// package verifies that an implementation preserves the CircuitBreaker
+// semantics.
+type Breaker interface {
+	// Name returns the name from Settings.Name.
+	Name() string
+
+	// State returns the current state.
+	State() State
+
+	// Counts returns the current window's statistics.
+	Counts() Counts
+
+	// Execute runs req if the breaker admits it and records the outcome.
+	Execute(req func() (interface{}, error)) (interface{}, error)
+
+	// ExecuteContext is Execute with context cancellation: a request canceled
+	// before or during execution is not counted as a failure.
+	ExecuteContext(ctx context.Context, req func() (interface{}, error)) (interface{}, error)
+}
+
+var _ Breaker = (*CircuitBreaker)(nil)
@@ -0,0 +1,151 @@
+package breaker
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestIntervalWindow_StartsAtCloseTransition(t *testing.T) {
+	const interval = time.Hour
+	clk := newFakeClock()
+	cb := newWithClock(clk, tripOnFirstFailure(Settings{
+		Name:     "window-at-close",
+		Interval: interval,
+		Timeout:  10 * time.Millisecond,
+	}))
+
+	recoverFromTrip(t, cb, clk)
+
+	closedAt := cb.stateChangedAt.Load()
+	if got := cb.lastClearedAt.Load(); got != closedAt {
+		t.Fatalf("Expected window to start at the close transition (%d), got %d", closedAt, got)
+	}
+
+	cb.Execute(successFunc)
+
+	// Just before a full Interval since closing: the recovery window is kept
+	cb.maybeResetCountsAt(closedAt + int64(interval) - 1)
+	if got := cb.Counts().Requests; got != 1 {
+		t.Errorf("Expected post-recovery counts kept within Interval of closing, got %d requests", got)
+	}
+
+	// A full Interval after closing: cleared
+	cb.maybeResetCountsAt(closedAt + int64(interval))
+	if got := cb.Counts().Requests; got != 0 {
+		t.Errorf("Expected counts cleared Interval after closing, got %d requests", got)
+	}
+}
+
+func TestIntervalWindow_LongHalfOpenDoesNotClearAfterClose(t *testing.T) {
+	const interval = 20 * time.Millisecond
+	clk := newFakeClock()
+	cb := newWithClock(clk, tripOnFirstFailure(Settings{
+		Name:     "long-half-open",
+		Interval: interval,
+		Timeout:  time.Millisecond,
+	}))
+
+	cb.Execute(failFunc)
+	clk.advance(5 * time.Millisecond)
+
+	// A slow probe keeps the circuit HalfOpen for longer than Interval
+	cb.Execute(func() (interface{}, error) {
+		clk.advance(2 * interval)
+		return "ok", nil
+	})
+	if cb.State() != StateClosed {
+		t.Fatalf("Expected Closed after probe, got %v", cb.State())
+	}
+
+	// The first requests after closing are in a fresh window, not a stale one
+	cb.Execute(successFunc)
+	cb.Execute(successFunc)
+	if got := cb.Counts().Requests; got != 2 {
+		t.Errorf("Expected 2 requests after recovery, got %d", got)
+	}
+}
+
+func TestIntervalClear_StaleEpochDoesNotWipeNewEpisode(t *testing.T) {
+	clk := newFakeClock()
+	cb := newWithClock(clk, tripOnFirstFailure(Settings{
+		Name:     "stale-clear",
+		Interval: time.Hour,
+		Timeout:  time.Millisecond,
+	}))
+
+	// An interval clear claimed under Closed, then delayed...
+	epoch := cb.transitionEpoch()
+
+	// ...while the circuit trips and recovers
+	recoverFromTrip(t, cb, clk)
+	cb.Execute(successFunc)
+	before := cb.Counts()
+
+	cb.clearIntervalCounts(epoch)
+
+	if got := cb.Counts(); got != before {
+		t.Errorf("Stale interval clear wiped the new episode: %+v, want %+v", got, before)
+	}
+}
+
+func TestIntervalClear_CurrentEpochClears(t *testing.T) {
+	cb := New(Settings{Name: "current-clear", Interval: time.Hour})
+
+	cb.Execute(successFunc)
+	cb.clearIntervalCounts(cb.transitionEpoch())
+
+	if got := cb.Counts(); got != (Counts{}) {
+		t.Errorf("Expected counts cleared, got %+v", got)
+	}
+}
+
+func TestIntervalClear_EpochAdvancesOnEveryTransition(t *testing.T) {
+	clk := newFakeClock()
+	cb := newWithClock(clk, tripOnFirstFailure(Settings{
+		Name:     "epoch-transitions",
+		Interval: time.Hour,
+		Timeout:  time.Millisecond,
+	}))
+
+	start := cb.transitionEpoch()
+	recoverFromTrip(t, cb, clk) // Closed → Open → HalfOpen → Closed
+
+	if got := cb.transitionEpoch() - start; got != 3 {
+		t.Errorf("Expected epoch to advance 3 times, got %d", got)
+	}
+}
+
+func TestIntervalClear_ConcurrentWithTransitions(t *testing.T) {
+	cb := New(Settings{
+		Name:     "clear-race",
+		Interval: time.Millisecond,
+		Timeout:  time.Millisecond,
+		ReadyToTrip: func(counts Counts) bool {
+			return counts.ConsecutiveFailures >= 3
+		},
+	})
+
+	deadline := time.Now().Add(100 * time.Millisecond)
+	var wg sync.WaitGroup
+	for g := 0; g < 8; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			for i := 0; time.Now().Before(deadline); i++ {
+				// Runs of 4 failures trip even without interleaving (one CPU)
+				if (i+g)%5 != 0 {
+					cb.Execute(failFunc)
+				} else {
+					cb.Execute(successFunc)
+				}
+			}
+		}(g)
+	}
+	wg.Wait()
+
+	// Clears and transitions interleaved without a data race (run with -race)
+	if cb.transitionEpoch() == 0 {
+		t.Fatal("Expected state transitions during the test")
+	}
+}
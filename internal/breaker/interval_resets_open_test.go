@@ -0,0 +1,123 @@
+package breaker
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+// tripOnFirstFailure returns settings that open on a single failure.
+func tripOnFirstFailure(s Settings) Settings {
+	s.ReadyToTrip = func(counts Counts) bool { return counts.ConsecutiveFailures >= 1 }
+	return s
+}
+
+func TestIntervalResetsOpenState_ProbesAtIntervalBoundary(t *testing.T) {
+	cb := New(tripOnFirstFailure(Settings{
+		Name:                    "interval-open",
+		Interval:                20 * time.Millisecond,
+		Timeout:                 time.Minute,
+		IntervalResetsOpenState: true,
+	}))
+
+	cb.Execute(failFunc)
+	if cb.State() != StateOpen {
+		t.Fatalf("Expected Open, got %v", cb.State())
+	}
+	if _, err := cb.Execute(successFunc); !errors.Is(err, ErrOpenState) {
+		t.Errorf("Expected ErrOpenState before the interval boundary, got %v", err)
+	}
+	if wait := cb.Diagnostics().TimeUntilHalfOpen; wait <= 0 || wait > 20*time.Millisecond {
+		t.Errorf("Expected TimeUntilHalfOpen bounded by Interval, got %v", wait)
+	}
+
+	// Well before Timeout, the interval boundary lets a probe through
+	time.Sleep(30 * time.Millisecond)
+	if _, err := cb.Execute(successFunc); err != nil {
+		t.Fatalf("Expected probe admitted at the interval boundary, got %v", err)
+	}
+	if cb.State() != StateClosed {
+		t.Errorf("Expected successful probe to close, got %v", cb.State())
+	}
+}
+
+func TestIntervalResetsOpenState_FailedProbeWaitsAgain(t *testing.T) {
+	cb := New(tripOnFirstFailure(Settings{
+		Name:                    "interval-open",
+		Interval:                20 * time.Millisecond,
+		Timeout:                 time.Minute,
+		IntervalResetsOpenState: true,
+	}))
+
+	cb.Execute(failFunc)
+	time.Sleep(30 * time.Millisecond)
+	cb.Execute(failFunc) // probe fails, reopens
+	if cb.State() != StateOpen {
+		t.Fatalf("Expected failed probe to reopen, got %v", cb.State())
+	}
+	if _, err := cb.Execute(successFunc); !errors.Is(err, ErrOpenState) {
+		t.Errorf("Expected a fresh wait after the failed probe, got %v", err)
+	}
+
+	time.Sleep(30 * time.Millisecond)
+	if _, err := cb.Execute(successFunc); err != nil {
+		t.Errorf("Expected another probe at the next boundary, got %v", err)
+	}
+}
+
+func TestIntervalResetsOpenState_DisabledWaitsForTimeout(t *testing.T) {
+	cb := New(tripOnFirstFailure(Settings{
+		Name:     "interval-open",
+		Interval: 20 * time.Millisecond,
+		Timeout:  time.Minute,
+	}))
+
+	cb.Execute(failFunc)
+	time.Sleep(30 * time.Millisecond)
+	if _, err := cb.Execute(successFunc); !errors.Is(err, ErrOpenState) {
+		t.Errorf("Expected Open until Timeout without the flag, got %v", err)
+	}
+}
+
+func TestIntervalResetsOpenState_OpenWait(t *testing.T) {
+	tests := []struct {
+		name     string
+		interval time.Duration
+		timeout  time.Duration
+		enabled  bool
+		want     time.Duration
+	}{
+		{"disabled", time.Second, time.Minute, false, time.Minute},
+		{"interval shorter", time.Second, time.Minute, true, time.Second},
+		{"timeout shorter", time.Minute, time.Second, true, time.Second},
+		{"no interval", 0, time.Minute, true, time.Minute},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cb := New(Settings{
+				Interval:                tt.interval,
+				Timeout:                 tt.timeout,
+				IntervalResetsOpenState: tt.enabled,
+			})
+			if got := cb.openWait(time.Now().UnixNano()); got != tt.want {
+				t.Errorf("openWait() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIntervalResetsOpenState_AlignedBoundary(t *testing.T) {
+	cb := New(Settings{
+		Interval:                 time.Minute,
+		Timeout:                  time.Hour,
+		AlignIntervalToWallClock: true,
+		IntervalResetsOpenState:  true,
+	})
+
+	// Opened 45s into a minute-aligned window: probe at the next minute
+	openedAt := time.Date(2024, 1, 1, 12, 0, 45, 0, time.UTC).UnixNano()
+	if got := cb.openWait(openedAt); got != 15*time.Second {
+		t.Errorf("openWait() = %v, want 15s to the aligned boundary", got)
+	}
+}
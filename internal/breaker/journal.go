@@ -0,0 +1,263 @@
+package breaker
+
+import (
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"time"
+	"unicode/utf8"
+)
+
+// defaultJournalErrorLength is the error text kept per journal entry when
+// JournalErrorLength is not set.
+const defaultJournalErrorLength = 128
+
+// errProbeAbandoned is the journaled error of a probe abandoned by the
+// HalfOpenProbeTimeout watchdog.
+var errProbeAbandoned = errors.New("half-open probe timed out")
+
+// JournalOutcome is how a journaled request ended.
+type JournalOutcome int
+
+const (
+	// JournalSuccess: the request was recorded as a success.
+	JournalSuccess JournalOutcome = iota
+
+	// JournalFailure: the request was recorded as a failure.
+	JournalFailure
+
+	// JournalIgnored: the request ran but its outcome was not recorded
+	// (ErrIgnoreOutcome, a nested rejection, an ignored classifier panic, or a
+	// canceled context).
+	JournalIgnored
+
+	// JournalPanic: the request function panicked, recorded as a failure.
+	JournalPanic
+)
+
+// String returns the outcome name.
+func (o JournalOutcome) String() string {
+	switch o {
+	case JournalSuccess:
+		return "success"
+	case JournalFailure:
+		return "failure"
+	case JournalIgnored:
+		return "ignored"
+	case JournalPanic:
+		return "panic"
+	default:
+		return fmt.Sprintf("unknown(%d)", int(o))
+	}
+}
+
+// JournalEntry is one request in the outcome journal (see Settings.JournalSize).
+type JournalEntry struct {
+	// Seq numbers entries in recording order, starting at 1. Dropped entries
+	// take no number, so gaps only appear where older entries were overwritten.
+	Seq uint64
+
+	// Time is when the outcome was recorded.
+	Time time.Time
+
+	// Outcome is how the request ended.
+	Outcome JournalOutcome
+
+	// Err is the request's error text, truncated to JournalErrorLength bytes.
+	// Empty for a nil error; the panic value for JournalPanic.
+	Err string
+
+	// PanicType is the type name of the recovered panic value for JournalPanic,
+	// such as "runtime.boundsError" or "string"; empty otherwise. Unlike Err,
+	// it is stable across panics of one kind, so it groups a panic storm.
+	PanicType string
+
+	// Counts are the counts right after the outcome was recorded.
+	Counts Counts
+}
+
+// outcomeJournal is a fixed-size ring of journal entries.
+//
+// A single writer at a time claims busy; a request that finds it taken drops
+// its entry (counted in dropped) rather than wait. Slots hold immutable entries
+// behind atomic pointers, so snapshots can read concurrently with a write.
+type outcomeJournal struct {
+	busy      atomic.Bool
+	written   atomic.Uint64 // Entries recorded so far; the next goes to slot written % len(slots)
+	dropped   atomic.Uint64
+	slots     []atomic.Pointer[JournalEntry]
+	errLength int
+
+	// Entries as of the latest entry into Open
+	lastTrip atomic.Pointer[[]JournalEntry]
+}
+
+func newOutcomeJournal(size, errLength int) *outcomeJournal {
+	if errLength == 0 {
+		errLength = defaultJournalErrorLength
+	}
+	return &outcomeJournal{
+		slots:     make([]atomic.Pointer[JournalEntry], size),
+		errLength: errLength,
+	}
+}
+
+// journalOutcome records a request's outcome in the journal, if enabled.
+// Called after the outcome is recorded and before any resulting transition,
+// so the entry that trips the circuit is part of the trip snapshot.
+func (cb *CircuitBreaker) journalOutcome(outcome JournalOutcome, err error) {
+	if cb.journal != nil {
+		cb.addJournalOutcome(outcome, err)
+	}
+}
+
+// addJournalOutcome implements journalOutcome for an enabled journal.
+func (cb *CircuitBreaker) addJournalOutcome(outcome JournalOutcome, err error) {
+	text := ""
+	if err != nil {
+		text = err.Error()
+	}
+	cb.journal.add(outcome, text, "", cb.Counts())
+}
+
+// journalIgnored records a counted request whose outcome was discarded, if
+// the journal is enabled. Synthetic calls (requestCounted false) are skipped.
+func (cb *CircuitBreaker) journalIgnored(requestCounted bool, err error) {
+	if requestCounted {
+		cb.journalOutcome(JournalIgnored, err)
+	}
+}
+
+// journalPanic records a panicked request in the journal, if enabled.
+func (cb *CircuitBreaker) journalPanic(r interface{}) {
+	if cb.journal == nil {
+		return
+	}
+	cb.journal.add(JournalPanic, fmt.Sprint(r), fmt.Sprintf("%T", r), cb.Counts())
+}
+
+func (j *outcomeJournal) add(outcome JournalOutcome, text, panicType string, counts Counts) {
+	if !j.busy.CompareAndSwap(false, true) {
+		j.dropped.Add(1)
+		return
+	}
+	defer j.busy.Store(false)
+
+	n := j.written.Load()
+	j.slots[n%uint64(len(j.slots))].Store(&JournalEntry{
+		Seq:       n + 1,
+		Time:      time.Now(),
+		Outcome:   outcome,
+		Err:       truncateUTF8(text, j.errLength),
+		PanicType: panicType,
+		Counts:    counts,
+	})
+	j.written.Store(n + 1)
+}
+
+// entries returns the retained entries, oldest first.
+func (j *outcomeJournal) entries() []JournalEntry {
+	written := j.written.Load()
+	n := written
+	if size := uint64(len(j.slots)); n > size {
+		n = size
+	}
+
+	entries := make([]JournalEntry, 0, n)
+	for i := written - n; i < written; i++ {
+		if entry := j.slots[i%uint64(len(j.slots))].Load(); entry != nil {
+			entries = append(entries, *entry)
+		}
+	}
+	return entries
+}
+
+// snapshotJournal keeps the journal as the LastTripJournal on entering Open
+// and logs it under LogTripJournal.
+func (cb *CircuitBreaker) snapshotJournal(from State) {
+	if cb.journal == nil {
+		return
+	}
+	entries := cb.journal.entries()
+	cb.journal.lastTrip.Store(&entries)
+
+	if cb.logTripJournal {
+		cb.logJournal(from, entries)
+	}
+}
+
+// logJournal prints a trip snapshot, one line per entry.
+func (cb *CircuitBreaker) logJournal(from State, entries []JournalEntry) {
+	logMutex.Lock()
+	defer logMutex.Unlock()
+	fmt.Printf("[AUTOBREAKER JOURNAL] Circuit %q: %s → open after %d journaled requests\n", cb.name, from, len(entries))
+	for _, e := range entries {
+		fmt.Printf("[AUTOBREAKER JOURNAL]   #%d %s %s err=%q requests=%d failures=%d consecutive_failures=%d\n",
+			e.Seq, e.Time.Format(time.RFC3339Nano), e.Outcome, e.Err,
+			e.Counts.Requests, e.Counts.TotalFailures, e.Counts.ConsecutiveFailures)
+	}
+}
+
+// LastTripJournal returns the outcome journal as it stood when the circuit
+// last entered Open, oldest entry first: the requests that led to the trip
+// (or to the failed probe that reopened it).
+//
+// Returns nil when Settings.JournalSize is not set or the circuit has not
+// opened yet.
+//
+// Example - explaining a surprising trip:
+//
+//	for _, e := range breaker.LastTripJournal() {
+//	    log.Printf("#%d %s %s %q failures=%d/%d",
+//	        e.Seq, e.Time.Format(time.StampMilli), e.Outcome, e.Err,
+//	        e.Counts.TotalFailures, e.Counts.Requests)
+//	}
+func (cb *CircuitBreaker) LastTripJournal() []JournalEntry {
+	if cb.journal == nil {
+		return nil
+	}
+	snapshot := cb.journal.lastTrip.Load()
+	if snapshot == nil {
+		return nil
+	}
+	entries := make([]JournalEntry, len(*snapshot))
+	copy(entries, *snapshot)
+	return entries
+}
+
+// journalDrops returns the number of dropped journal entries.
+func (cb *CircuitBreaker) journalDrops() uint64 {
+	if cb.journal == nil {
+		return 0
+	}
+	return cb.journal.dropped.Load()
+}
+
+// journalSize returns the configured journal size, or 0 without a journal.
+func (cb *CircuitBreaker) journalSize() int {
+	if cb.journal == nil {
+		return 0
+	}
+	return len(cb.journal.slots)
+}
+
+// journalErrorLength returns the error text kept per entry, or 0 without a
+// journal.
+func (cb *CircuitBreaker) journalErrorLength() int {
+	if cb.journal == nil {
+		return 0
+	}
+	return cb.journal.errLength
+}
+
+// truncateUTF8 shortens s to at most n bytes without splitting a character.
+func truncateUTF8(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	s = s[:n]
+	for len(s) > 0 && !utf8.ValidString(s) {
+		s = s[:len(s)-1]
+	}
+	return s
+}
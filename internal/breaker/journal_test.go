@@ -0,0 +1,196 @@
+package breaker
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// journaled returns a breaker with a journal that trips on the 3rd consecutive failure.
+func journaled(size int) *CircuitBreaker {
+	return New(Settings{
+		Name:        "journal",
+		Timeout:     time.Minute,
+		JournalSize: size,
+		ReadyToTrip: func(c Counts) bool { return c.ConsecutiveFailures >= 3 },
+	})
+}
+
+func TestJournal_TripSnapshotMatchesOutcomes(t *testing.T) {
+	cb := journaled(8)
+
+	cb.Execute(successFunc)
+	cb.Execute(func() (interface{}, error) { return nil, errors.New("timeout 1") })
+	cb.Execute(successFunc)
+	for i := 1; i <= 3; i++ {
+		n := i
+		cb.Execute(func() (interface{}, error) { return nil, fmt.Errorf("refused %d", n) })
+	}
+	if cb.State() != StateOpen {
+		t.Fatalf("Expected Open, got %v", cb.State())
+	}
+
+	want := []struct {
+		outcome JournalOutcome
+		err     string
+	}{
+		{JournalSuccess, ""},
+		{JournalFailure, "timeout 1"},
+		{JournalSuccess, ""},
+		{JournalFailure, "refused 1"},
+		{JournalFailure, "refused 2"},
+		{JournalFailure, "refused 3"},
+	}
+	got := cb.LastTripJournal()
+	if len(got) != len(want) {
+		t.Fatalf("Expected %d entries, got %d: %+v", len(want), len(got), got)
+	}
+	for i, e := range got {
+		if e.Seq != uint64(i+1) || e.Outcome != want[i].outcome || e.Err != want[i].err {
+			t.Errorf("Entry %d: expected #%d %v %q, got #%d %v %q",
+				i, i+1, want[i].outcome, want[i].err, e.Seq, e.Outcome, e.Err)
+		}
+		if e.Counts.Requests != uint32(i+1) {
+			t.Errorf("Entry %d: expected counts after recording (%d requests), got %+v", i, i+1, e.Counts)
+		}
+	}
+	if last := got[len(got)-1].Counts.ConsecutiveFailures; last != 3 {
+		t.Errorf("Expected the tripping entry to show 3 consecutive failures, got %d", last)
+	}
+}
+
+func TestJournal_WrapsKeepingNewest(t *testing.T) {
+	cb := journaled(4)
+
+	for i := 0; i < 10; i++ {
+		cb.Execute(successFunc)
+	}
+	for i := 0; i < 3; i++ {
+		cb.Execute(failFunc)
+	}
+
+	got := cb.LastTripJournal()
+	if len(got) != 4 {
+		t.Fatalf("Expected the journal bounded at 4 entries, got %d", len(got))
+	}
+	for i, e := range got {
+		if wantSeq := uint64(10 + i); e.Seq != wantSeq {
+			t.Errorf("Entry %d: expected #%d, got #%d", i, wantSeq, e.Seq)
+		}
+	}
+	if got[0].Outcome != JournalSuccess || got[3].Outcome != JournalFailure {
+		t.Errorf("Expected the newest success followed by the failures, got %+v", got)
+	}
+}
+
+func TestJournal_SnapshotKeptAfterRecovery(t *testing.T) {
+	cb := New(tripOnFirstFailure(Settings{Name: "journal-recover", Timeout: 10 * time.Millisecond, JournalSize: 4}))
+	cb.Execute(failFunc)
+	time.Sleep(20 * time.Millisecond)
+	cb.Execute(successFunc)
+	cb.Execute(successFunc)
+
+	got := cb.LastTripJournal()
+	if len(got) != 1 || got[0].Outcome != JournalFailure {
+		t.Errorf("Expected the trip snapshot unchanged by later traffic, got %+v", got)
+	}
+}
+
+func TestJournal_RecordsPanicsAndIgnoredOutcomes(t *testing.T) {
+	cb := New(Settings{
+		Name:          "journal-outcomes",
+		Timeout:       time.Minute,
+		JournalSize:   8,
+		RecoverPanics: true,
+		ReadyToTrip:   func(c Counts) bool { return c.TotalFailures >= 2 },
+	})
+
+	cb.Execute(func() (interface{}, error) { return nil, errors.Join(ErrIgnoreOutcome, errors.New("bad input")) })
+	cb.Execute(func() (interface{}, error) { panic("boom") })
+	cb.Execute(failFunc)
+
+	got := cb.LastTripJournal()
+	if len(got) != 3 {
+		t.Fatalf("Expected 3 entries, got %+v", got)
+	}
+	if got[0].Outcome != JournalIgnored || got[0].Err != "bad input" || got[0].Counts.Requests != 0 {
+		t.Errorf("Expected an ignored entry with no counted request, got %+v", got[0])
+	}
+	if got[1].Outcome != JournalPanic || got[1].Err != "boom" {
+		t.Errorf("Expected a panic entry with the panic value, got %+v", got[1])
+	}
+}
+
+func TestJournal_TruncatesErrors(t *testing.T) {
+	cb := New(tripOnFirstFailure(Settings{
+		Name:               "journal-truncate",
+		Timeout:            time.Minute,
+		JournalSize:        2,
+		JournalErrorLength: 2,
+	}))
+
+	// "é" is 2 bytes, so a 2-byte cut would split it
+	cb.Execute(func() (interface{}, error) { return nil, errors.New("héllo world") })
+
+	if got := cb.LastTripJournal()[0].Err; got != "h" {
+		t.Errorf("Expected error text cut on a character boundary, got %q", got)
+	}
+}
+
+func TestJournal_DisabledByDefault(t *testing.T) {
+	cb := New(tripOnFirstFailure(Settings{Name: "no-journal", Timeout: time.Minute}))
+	cb.Execute(failFunc)
+
+	if got := cb.LastTripJournal(); got != nil {
+		t.Errorf("Expected nil without JournalSize, got %+v", got)
+	}
+}
+
+func TestJournal_ConcurrentWritersDropNotBlock(t *testing.T) {
+	cb := New(Settings{Name: "journal-concurrent", Timeout: time.Minute, JournalSize: 16})
+
+	const workers, calls = 8, 500
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < calls; i++ {
+				cb.Execute(successFunc)
+			}
+		}()
+	}
+	wg.Wait()
+
+	written := cb.journal.written.Load()
+	if total := written + cb.Metrics().JournalDrops; total != workers*calls {
+		t.Errorf("Expected every request journaled or dropped, got %d written + drops = %d", written, total)
+	}
+	entries := cb.journal.entries()
+	for i := 1; i < len(entries); i++ {
+		if entries[i].Seq != entries[i-1].Seq+1 {
+			t.Fatalf("Expected consecutive sequence numbers, got %d after %d", entries[i].Seq, entries[i-1].Seq)
+		}
+	}
+}
+
+func TestJournal_LogTripJournal(t *testing.T) {
+	cb := New(tripOnFirstFailure(Settings{
+		Name:           "journal-log",
+		Timeout:        time.Minute,
+		JournalSize:    4,
+		LogTripJournal: true,
+	}))
+
+	out := captureStdout(t, func() {
+		cb.Execute(func() (interface{}, error) { return nil, errors.New("connection reset") })
+	})
+
+	if !strings.Contains(out, `"journal-log": closed → open`) || !strings.Contains(out, `#1 `) ||
+		!strings.Contains(out, `err="connection reset"`) {
+		t.Errorf("Expected the trip journal logged, got:\n%s", out)
+	}
+}
@@ -0,0 +1,43 @@
+package breaker
+
+import "sort"
+
+// reservedLabelName is the label key exporters use for the breaker name.
+const reservedLabelName = "name"
+
+// Labels returns a copy of the breaker's labels (see Settings.Labels), or nil
+// if it has none. Modifying the returned map does not affect the breaker.
+//
+// Thread-safe: Safe to call concurrently.
+//
+// Example - exporter attributes:
+//
+//	attrs := []attribute.KeyValue{attribute.String("name", breaker.Name())}
+//	for k, v := range breaker.Labels() {
+//	    attrs = append(attrs, attribute.String(k, v))
+//	}
+func (cb *CircuitBreaker) Labels() map[string]string {
+	return copyLabels(cb.labels)
+}
+
+// copyLabels returns a copy of labels, or nil if it is empty.
+func copyLabels(labels map[string]string) map[string]string {
+	if len(labels) == 0 {
+		return nil
+	}
+	c := make(map[string]string, len(labels))
+	for k, v := range labels {
+		c[k] = v
+	}
+	return c
+}
+
+// sortedLabelKeys returns the keys of labels in sorted order.
+func sortedLabelKeys(labels map[string]string) []string {
+	keys := make([]string, 0, len(labels))
+	for k := range labels {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
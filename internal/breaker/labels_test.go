@@ -0,0 +1,62 @@
+package breaker
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestLabels_Immutable(t *testing.T) {
+	labels := map[string]string{"team": "payments", "tier": "1"}
+	cb := New(Settings{Name: "labels", Labels: labels})
+
+	// Mutating the settings map after construction has no effect
+	labels["team"] = "changed"
+	labels["region"] = "eu"
+
+	got := cb.Labels()
+	want := map[string]string{"team": "payments", "tier": "1"}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("Expected %v, got %v", want, got)
+	}
+
+	// Nor does mutating a returned copy
+	got["tier"] = "3"
+	delete(got, "team")
+	if again := cb.Labels(); !reflect.DeepEqual(again, want) {
+		t.Errorf("Expected labels unaffected by the caller, got %v", again)
+	}
+
+	diag := cb.Diagnostics()
+	diag.Labels["tier"] = "3"
+	if again := cb.Labels(); !reflect.DeepEqual(again, want) {
+		t.Errorf("Expected labels unaffected by Diagnostics copies, got %v", again)
+	}
+}
+
+func TestLabels_Propagation(t *testing.T) {
+	want := map[string]string{"team": "payments", "region": "eu"}
+	r := NewRegistry()
+	cb := r.GetOrCreate(Settings{Name: "labels", Labels: want})
+
+	if got := cb.Diagnostics().Labels; !reflect.DeepEqual(got, want) {
+		t.Errorf("Expected Diagnostics labels %v, got %v", want, got)
+	}
+	if got := cb.CurrentSettings().Labels; !reflect.DeepEqual(got, want) {
+		t.Errorf("Expected CurrentSettings labels %v, got %v", want, got)
+	}
+	if got := r.Collect(); len(got) != 1 || !reflect.DeepEqual(got[0].Labels, want) {
+		t.Errorf("Expected Collect to carry labels %v, got %+v", want, got)
+	}
+}
+
+func TestLabels_None(t *testing.T) {
+	for _, labels := range []map[string]string{nil, {}} {
+		cb := New(Settings{Name: "labels", Labels: labels})
+		if got := cb.Labels(); got != nil {
+			t.Errorf("Expected nil labels for %#v, got %v", labels, got)
+		}
+		if got := cb.Diagnostics().Labels; got != nil {
+			t.Errorf("Expected nil Diagnostics labels for %#v, got %v", labels, got)
+		}
+	}
+}
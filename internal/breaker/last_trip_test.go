@@ -0,0 +1,124 @@
+package breaker
+
+import (
+	"testing"
+	"time"
+)
+
+func TestLastTrip_EachMechanism(t *testing.T) {
+	tests := []struct {
+		name       string
+		settings   Settings
+		outcomes   []bool // true = success
+		wantReason string
+		wantCounts Counts
+	}{
+		{
+			name:       "static consecutive failures",
+			outcomes:   []bool{false, false, false, false, false, false},
+			wantReason: "6 consecutive failures",
+			wantCounts: Counts{Requests: 6, TotalFailures: 6, ConsecutiveFailures: 6},
+		},
+		{
+			name:       "consecutive failure threshold",
+			settings:   Settings{ConsecutiveFailureThreshold: 2},
+			outcomes:   []bool{true, false, false, false},
+			wantReason: "3 consecutive failures",
+			wantCounts: Counts{Requests: 4, TotalSuccesses: 1, TotalFailures: 3, ConsecutiveFailures: 3},
+		},
+		{
+			name: "adaptive failure rate",
+			settings: Settings{
+				AdaptiveThreshold:    true,
+				FailureRateThreshold: 0.10,
+				MinimumObservations:  5,
+			},
+			outcomes:   []bool{true, true, true, true, false},
+			wantReason: "failure rate 20.00% (1/5) exceeded threshold 10.00%",
+			wantCounts: Counts{Requests: 5, TotalSuccesses: 4, TotalFailures: 1, ConsecutiveFailures: 1},
+		},
+		{
+			name:       "custom ReadyToTrip",
+			settings:   Settings{ReadyToTrip: func(c Counts) bool { return c.TotalFailures >= 2 }},
+			outcomes:   []bool{false, true, false},
+			wantReason: "ReadyToTrip returned true (2/3 failed, 1 consecutive)",
+			wantCounts: Counts{Requests: 3, TotalSuccesses: 1, TotalFailures: 2, ConsecutiveFailures: 1},
+		},
+		{
+			name:       "cycle limit",
+			settings:   Settings{MaxRequestsPerCycle: 2},
+			outcomes:   []bool{true, true, true},
+			wantReason: "cycle limit of 2 requests reached",
+			wantCounts: Counts{Requests: 2, TotalSuccesses: 2, ConsecutiveSuccesses: 2},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.settings.Name = "last-trip"
+			tt.settings.Timeout = time.Minute
+			cb := New(tt.settings)
+
+			if d := cb.Diagnostics(); d.LastTripReason != "" || d.LastTripCounts != (Counts{}) {
+				t.Fatalf("Expected no last trip before tripping, got %q %+v", d.LastTripReason, d.LastTripCounts)
+			}
+
+			for _, ok := range tt.outcomes {
+				if ok {
+					cb.Execute(successFunc)
+				} else {
+					cb.Execute(failFunc)
+				}
+			}
+			if cb.State() != StateOpen {
+				t.Fatalf("Expected Open, got %v", cb.State())
+			}
+
+			d := cb.Diagnostics()
+			if d.LastTripReason != tt.wantReason {
+				t.Errorf("Expected reason %q, got %q", tt.wantReason, d.LastTripReason)
+			}
+			if d.LastTripCounts != tt.wantCounts {
+				t.Errorf("Expected counts %+v, got %+v", tt.wantCounts, d.LastTripCounts)
+			}
+		})
+	}
+}
+
+func TestLastTrip_KeptAcrossRecovery(t *testing.T) {
+	cb := New(Settings{Name: "last-trip", Timeout: 10 * time.Millisecond})
+
+	for i := 0; i < 6; i++ {
+		cb.Execute(failFunc)
+	}
+
+	// A failed probe reopens but is not a new trip
+	time.Sleep(20 * time.Millisecond)
+	cb.Execute(failFunc)
+	if d := cb.Diagnostics(); d.OpenReason.Kind != OpenReasonProbeFailed || d.LastTripReason != "6 consecutive failures" {
+		t.Errorf("Expected last trip unchanged by a failed probe, got %q (open reason %v)", d.LastTripReason, d.OpenReason.Kind)
+	}
+
+	// Still reported once the circuit has closed again
+	time.Sleep(20 * time.Millisecond)
+	cb.Execute(successFunc)
+	if cb.State() != StateClosed {
+		t.Fatalf("Expected Closed after probe, got %v", cb.State())
+	}
+	d := cb.Diagnostics()
+	if d.OpenReason.Kind != OpenReasonNone {
+		t.Errorf("Expected no open reason while Closed, got %v", d.OpenReason.Kind)
+	}
+	if d.LastTripReason != "6 consecutive failures" || d.LastTripCounts.ConsecutiveFailures != 6 {
+		t.Errorf("Expected last trip kept after recovery, got %q %+v", d.LastTripReason, d.LastTripCounts)
+	}
+
+	// The next trip replaces it
+	cb.Execute(successFunc)
+	for i := 0; i < 6; i++ {
+		cb.Execute(failFunc)
+	}
+	if d := cb.Diagnostics(); d.LastTripCounts.Requests != 7 {
+		t.Errorf("Expected the new trip's counts, got %+v", d.LastTripCounts)
+	}
+}
@@ -0,0 +1,23 @@
+package breaker
+
+// admissionGeneration returns the window generation an admitted call belongs
+// to. Capture it once admission is decided, after any interval clear or
+// Open → HalfOpen transition the call itself triggered.
+func (cb *CircuitBreaker) admissionGeneration() uint64 {
+	return cb.generation.Load()
+}
+
+// lateOutcome reports whether a call admitted at window generation gen
+// completes after its window was discarded by a state transition, an interval
+// clear or a reset, and counts it in Metrics.LateOutcomes if so.
+//
+// A late outcome must not touch the live window: its request was counted in
+// the discarded counts (so it is not uncounted either), its consecutive streak
+// is over, and a HalfOpen probe's episode has already been decided.
+func (cb *CircuitBreaker) lateOutcome(gen uint64) bool {
+	if cb.generation.Load() == gen {
+		return false
+	}
+	cb.eventCounter().lateOutcomes.Add(1)
+	return true
+}
@@ -0,0 +1,190 @@
+package breaker
+
+import (
+	"context"
+	"errors"
+	"io"
+	"testing"
+	"time"
+)
+
+// startBlocked runs a call through Execute that waits on release and then
+// returns its error, and returns once the call has been admitted.
+func startBlocked(cb *CircuitBreaker, release <-chan error) <-chan struct{} {
+	admitted := make(chan struct{})
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		cb.Execute(func() (interface{}, error) {
+			close(admitted)
+			return nil, <-release
+		})
+	}()
+	<-admitted
+	return done
+}
+
+func TestLateOutcome_AfterIntervalClear(t *testing.T) {
+	cb := New(tripOnFirstFailure(Settings{Name: "late-interval", Interval: time.Hour}))
+
+	release := make(chan error)
+	done := startBlocked(cb, release)
+
+	// The window the call was admitted in ends while it runs
+	cb.maybeResetCountsAt(time.Now().Add(2 * time.Hour).UnixNano())
+	cb.Execute(successFunc)
+
+	release <- errors.New("late failure")
+	<-done
+
+	if cb.State() != StateClosed {
+		t.Errorf("Expected a late failure not to trip, got %v", cb.State())
+	}
+	counts := cb.Counts()
+	if counts.Requests != 1 || counts.TotalFailures != 0 || counts.ConsecutiveSuccesses != 1 {
+		t.Errorf("Expected only the new window's success counted, got %+v", counts)
+	}
+	if got := cb.Metrics().LateOutcomes; got != 1 {
+		t.Errorf("Expected 1 late outcome, got %d", got)
+	}
+}
+
+func TestLateOutcome_AfterReset(t *testing.T) {
+	cb := New(tripOnFirstFailure(Settings{Name: "late-reset", Interval: time.Hour}))
+
+	release := make(chan error)
+	done := startBlocked(cb, release)
+
+	// Changing Interval resets the counts, as does Enable()
+	if err := cb.UpdateSettings(SettingsUpdate{Interval: DurationPtr(2 * time.Hour)}); err != nil {
+		t.Fatalf("UpdateSettings: %v", err)
+	}
+
+	release <- errors.New("late failure")
+	<-done
+
+	if cb.State() != StateClosed {
+		t.Errorf("Expected a failure from before the reset not to trip, got %v", cb.State())
+	}
+	if got := cb.Counts().Requests; got != 0 {
+		t.Errorf("Expected the reset window untouched, got %d requests", got)
+	}
+	if got := cb.Metrics().LateOutcomes; got != 1 {
+		t.Errorf("Expected 1 late outcome, got %d", got)
+	}
+}
+
+func TestLateOutcome_AfterTrip(t *testing.T) {
+	const timeout = 10 * time.Millisecond
+	clk := newFakeClock()
+	cb := newWithClock(clk, tripOnFirstFailure(Settings{Name: "late-trip", Timeout: timeout}))
+
+	release := make(chan error)
+	done := startBlocked(cb, release)
+
+	// Trip and recover while the call is still running
+	recoverFromTrip(t, cb, clk)
+
+	release <- errors.New("late failure")
+	<-done
+
+	if cb.State() != StateClosed {
+		t.Errorf("Expected a failure from before the trip not to reopen, got %v", cb.State())
+	}
+	if got := cb.Counts(); got.Requests != 0 || got.ConsecutiveFailures != 0 {
+		t.Errorf("Expected the recovered window untouched, got %+v", got)
+	}
+	if got := cb.Metrics().LateOutcomes; got != 1 {
+		t.Errorf("Expected 1 late outcome, got %d", got)
+	}
+}
+
+func TestLateOutcome_HalfOpenProbeAfterDecision(t *testing.T) {
+	const timeout = 10 * time.Millisecond
+	clk := newFakeClock()
+	cb := newWithClock(clk, tripOnFirstFailure(Settings{Name: "late-probe", Timeout: timeout, MaxRequests: 2}))
+
+	cb.Execute(failFunc)
+	clk.advance(timeout)
+
+	// Two probes: the slow one outlives the first's verdict
+	release := make(chan error)
+	done := startBlocked(cb, release)
+	cb.Execute(failFunc)
+	if cb.State() != StateOpen {
+		t.Fatalf("Expected the failed probe to reopen, got %v", cb.State())
+	}
+
+	release <- nil
+	<-done
+
+	if cb.State() != StateOpen {
+		t.Errorf("Expected a late probe success not to close the reopened circuit, got %v", cb.State())
+	}
+	if got := cb.Metrics().LateOutcomes; got != 1 {
+		t.Errorf("Expected 1 late outcome, got %d", got)
+	}
+}
+
+func TestLateOutcome_ExecuteContextIgnoredOutcome(t *testing.T) {
+	cb := New(Settings{Name: "late-ignored", Interval: time.Hour})
+
+	_, err := cb.ExecuteContext(context.Background(), func() (interface{}, error) {
+		cb.maybeResetCountsAt(time.Now().Add(2 * time.Hour).UnixNano())
+		return nil, ErrIgnoreOutcome
+	})
+	if err != nil {
+		t.Errorf("Expected ErrIgnoreOutcome stripped from a late call, got %v", err)
+	}
+	if got := cb.Counts().Requests; got != 0 {
+		t.Errorf("Expected the new window untouched, got %d requests", got)
+	}
+	if got := cb.Metrics().LateOutcomes; got != 1 {
+		t.Errorf("Expected 1 late outcome, got %d", got)
+	}
+}
+
+func TestLateOutcome_StreamAfterTrip(t *testing.T) {
+	cb := New(tripOnFirstFailure(Settings{Name: "late-stream", Timeout: time.Minute}))
+
+	body, err := cb.ExecuteStream(streamOf(&failingReader{n: 10, err: errors.New("reset")}))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	cb.Execute(failFunc)
+	if cb.State() != StateOpen {
+		t.Fatalf("Expected Open, got %v", cb.State())
+	}
+
+	io.ReadAll(body)
+	body.Close()
+
+	if got := cb.Counts(); got.Requests != 0 || got.TotalFailures != 0 {
+		t.Errorf("Expected the open period's counts untouched, got %+v", got)
+	}
+	if got := cb.Metrics().LateOutcomes; got != 1 {
+		t.Errorf("Expected 1 late outcome, got %d", got)
+	}
+}
+
+func TestLateOutcome_DoubleCompletionIsNoOp(t *testing.T) {
+	cb := New(Settings{Name: "double-done"})
+
+	body, err := cb.ExecuteStream(streamOf(&failingReader{n: 10, err: io.EOF}))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	// EOF completes the call; further reads and closes must not record again
+	io.ReadAll(body)
+	body.Read(make([]byte, 1))
+	body.Close()
+	body.Close()
+
+	if got := cb.Counts(); got.Requests != 1 || got.TotalSuccesses != 1 || got.TotalFailures != 0 {
+		t.Errorf("Expected exactly one success, got %+v", got)
+	}
+	if got := cb.Metrics().LateOutcomes; got != 0 {
+		t.Errorf("Expected no late outcomes, got %d", got)
+	}
+}
@@ -0,0 +1,161 @@
+package breaker
+
+import (
+	"math"
+	"sync/atomic"
+	"time"
+)
+
+// latencyBucketBounds are the fixed upper bounds of the latency histogram buckets.
+//
+// Buckets are log-scale (each bound is 2x the previous) from 1ms to ~65s, plus an
+// implicit overflow bucket for anything slower. The scheme is fixed so that
+// percentiles and exported histograms (LatencyHistogram) from different breakers
+// are directly comparable, and so the histogram's memory use is constant.
+var latencyBucketBounds = [...]time.Duration{
+	1 * time.Millisecond,
+	2 * time.Millisecond,
+	4 * time.Millisecond,
+	8 * time.Millisecond,
+	16 * time.Millisecond,
+	32 * time.Millisecond,
+	64 * time.Millisecond,
+	128 * time.Millisecond,
+	256 * time.Millisecond,
+	512 * time.Millisecond,
+	1024 * time.Millisecond,
+	2048 * time.Millisecond,
+	4096 * time.Millisecond,
+	8192 * time.Millisecond,
+	16384 * time.Millisecond,
+	32768 * time.Millisecond,
+	65536 * time.Millisecond,
+}
+
+// minLatencySamples is the number of observations required before latency
+// percentiles are considered meaningful.
+const minLatencySamples = 20
+
+// latencyHistogram tracks request latencies in fixed log-scale buckets.
+//
+// All counters are atomic; recording and reading are lock-free. The last bucket
+// (index len(latencyBucketBounds)) counts observations above the largest bound.
+type latencyHistogram struct {
+	buckets [len(latencyBucketBounds) + 1]atomic.Uint64
+	total   atomic.Uint64
+}
+
+// observe records a single latency observation.
+func (h *latencyHistogram) observe(d time.Duration) {
+	h.buckets[latencyBucketIndex(d)].Add(1)
+	h.total.Add(1)
+}
+
+// percentile returns the upper bound of the bucket containing the given
+// percentile (0.0-1.0). Returns 0 if fewer than minLatencySamples observations
+// have been recorded.
+func (h *latencyHistogram) percentile(p float64) time.Duration {
+	total := h.total.Load()
+	if total < minLatencySamples {
+		return 0
+	}
+
+	target := uint64(math.Ceil(float64(total) * p))
+	if target == 0 {
+		target = 1
+	}
+
+	var cumulative uint64
+	for i := range latencyBucketBounds {
+		cumulative += h.buckets[i].Load()
+		if cumulative >= target {
+			return latencyBucketBounds[i]
+		}
+	}
+
+	// Overflow bucket: report the largest finite bound
+	return latencyBucketBounds[len(latencyBucketBounds)-1]
+}
+
+// LatencyOverflowBound is the upper bound LatencyHistogram reports for the
+// overflow bucket (latencies above the largest finite bound, ~65s). It stands
+// for +Inf when exporting to systems such as Prometheus.
+const LatencyOverflowBound = time.Duration(math.MaxInt64)
+
+// LatencyHistogram returns the request latency histogram: the bucket upper bounds
+// and the number of observations in each bucket.
+//
+// The bucket scheme is fixed and identical for every breaker: log-scale bounds
+// 1ms, 2ms, 4ms, ... 65.536s (each 2x the previous), followed by an overflow
+// bucket whose bound is LatencyOverflowBound. Both slices have the same length.
+// An observation falls in the first bucket whose bound is >= its latency.
+//
+// Counts are per bucket (not cumulative) and monotonic: the histogram is never
+// reset, so it can be exported directly as a Prometheus histogram by summing
+// counts into cumulative buckets, with LatencyOverflowBound as +Inf.
+//
+// Latency is recorded only with Settings.TrackLatency or Settings.PredictiveReject;
+// otherwise all counts are zero. Only requests that complete are observed
+// (panics, canceled contexts, and maintenance windows are excluded).
+//
+// Thread-safe: Counts are read one bucket at a time and may be slightly
+// inconsistent under concurrent requests.
+func (cb *CircuitBreaker) LatencyHistogram() ([]time.Duration, []uint64) {
+	bounds := make([]time.Duration, len(cb.latency.buckets))
+	counts := make([]uint64, len(cb.latency.buckets))
+	for i := range cb.latency.buckets {
+		if i < len(latencyBucketBounds) {
+			bounds[i] = latencyBucketBounds[i]
+		} else {
+			bounds[i] = LatencyOverflowBound
+		}
+		if cb.latency != nil {
+			counts[i] = cb.latency.buckets[i].Load()
+		}
+	}
+	return bounds, counts
+}
+
+// latencyBucketIndex returns the histogram bucket index for a latency.
+func latencyBucketIndex(d time.Duration) int {
+	for i, bound := range latencyBucketBounds {
+		if d <= bound {
+			return i
+		}
+	}
+	return len(latencyBucketBounds)
+}
+
+// recordLatency records the duration of a completed request if latency
+// tracking is enabled.
+func (cb *CircuitBreaker) recordLatency(d time.Duration) {
+	if !cb.trackLatency || cb.maintenance.Load() {
+		return
+	}
+	cb.latency.observe(d)
+}
+
+// latencyP95 returns the learned 95th percentile request latency, or 0 if
+// not enough requests have been observed yet.
+func (cb *CircuitBreaker) latencyP95() time.Duration {
+	if cb.latency == nil {
+		return 0
+	}
+	return cb.latency.percentile(0.95)
+}
+
+// deadlineTooShort reports whether the context deadline leaves less time than
+// the learned p95 latency. Always false when PredictiveReject is disabled, the
+// context has no deadline, or the p95 has not been learned yet.
+func (cb *CircuitBreaker) deadlineTooShort(deadline time.Time, hasDeadline bool) bool {
+	if !cb.predictiveReject || !hasDeadline {
+		return false
+	}
+
+	p95 := cb.latencyP95()
+	if p95 == 0 {
+		return false
+	}
+
+	return time.Until(deadline) < p95
+}
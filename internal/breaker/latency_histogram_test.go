@@ -0,0 +1,134 @@
+package breaker
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestLatencyHistogram_FixedScheme(t *testing.T) {
+	cb := New(Settings{Name: "test"})
+
+	bounds, counts := cb.LatencyHistogram()
+	if len(bounds) != 18 || len(counts) != len(bounds) {
+		t.Fatalf("Expected 18 bounds and counts, got %d and %d", len(bounds), len(counts))
+	}
+	if bounds[0] != time.Millisecond || bounds[16] != 65536*time.Millisecond {
+		t.Errorf("Expected bounds 1ms..65.536s, got %v..%v", bounds[0], bounds[16])
+	}
+	for i := 1; i < 17; i++ {
+		if bounds[i] != 2*bounds[i-1] {
+			t.Errorf("Expected bound %d to double the previous, got %v after %v", i, bounds[i], bounds[i-1])
+		}
+	}
+	if bounds[17] != LatencyOverflowBound {
+		t.Errorf("Expected overflow bound last, got %v", bounds[17])
+	}
+	for i, c := range counts {
+		if c != 0 {
+			t.Errorf("Expected zero counts without tracking, bucket %d has %d", i, c)
+		}
+	}
+
+	// Returned slices are copies
+	bounds[0] = 0
+	if again, _ := cb.LatencyHistogram(); again[0] != time.Millisecond {
+		t.Error("Expected LatencyHistogram to return a copy of the bounds")
+	}
+}
+
+func TestLatencyHistogram_KnownLatencies(t *testing.T) {
+	cb := New(Settings{Name: "test", TrackLatency: true})
+
+	latencies := map[time.Duration]int{
+		500 * time.Microsecond:   0, // below 1ms
+		time.Millisecond:         0, // bounds are inclusive
+		1500 * time.Microsecond:  1, // (1ms, 2ms]
+		3 * time.Millisecond:     2,
+		100 * time.Millisecond:   7, // (64ms, 128ms]
+		2 * time.Second:          11,
+		65536 * time.Millisecond: 16,
+		2 * time.Minute:          17, // overflow
+	}
+	for d := range latencies {
+		cb.recordLatency(d)
+	}
+
+	_, counts := cb.LatencyHistogram()
+	want := make([]uint64, len(counts))
+	for _, i := range latencies {
+		want[i]++
+	}
+	for i := range counts {
+		if counts[i] != want[i] {
+			t.Errorf("Bucket %d: expected %d, got %d", i, want[i], counts[i])
+		}
+	}
+}
+
+// percentileFromBuckets derives a percentile from exported buckets the way a
+// consumer of LatencyHistogram would.
+func percentileFromBuckets(bounds []time.Duration, counts []uint64, p float64) time.Duration {
+	var total uint64
+	for _, c := range counts {
+		total += c
+	}
+	target := uint64(math.Ceil(float64(total) * p))
+	var cumulative uint64
+	for i, c := range counts {
+		cumulative += c
+		if cumulative >= target {
+			if bounds[i] == LatencyOverflowBound {
+				return bounds[i-1]
+			}
+			return bounds[i]
+		}
+	}
+	return 0
+}
+
+func TestLatencyHistogram_PercentilesMatchBuckets(t *testing.T) {
+	cb := New(Settings{Name: "test", PredictiveReject: true})
+
+	// 90 fast, 6 medium, 4 slow: p95 falls in the medium bucket
+	for i := 0; i < 90; i++ {
+		cb.recordLatency(3 * time.Millisecond)
+	}
+	for i := 0; i < 6; i++ {
+		cb.recordLatency(200 * time.Millisecond)
+	}
+	for i := 0; i < 4; i++ {
+		cb.recordLatency(5 * time.Second)
+	}
+
+	bounds, counts := cb.LatencyHistogram()
+	for _, p := range []float64{0.5, 0.9, 0.95, 0.99} {
+		if got, want := cb.latency.percentile(p), percentileFromBuckets(bounds, counts, p); got != want {
+			t.Errorf("p%v: breaker reports %v, buckets give %v", p*100, got, want)
+		}
+	}
+	if got := cb.latencyP95(); got != 256*time.Millisecond {
+		t.Errorf("Expected p95 256ms, got %v", got)
+	}
+}
+
+func TestLatencyHistogram_ExecuteObserved(t *testing.T) {
+	cb := New(Settings{Name: "test", TrackLatency: true})
+
+	cb.Execute(func() (interface{}, error) {
+		time.Sleep(3 * time.Millisecond)
+		return nil, nil
+	})
+
+	_, counts := cb.LatencyHistogram()
+	var total uint64
+	for _, c := range counts {
+		total += c
+	}
+	if total != 1 {
+		t.Errorf("Expected one observation from Execute, got %d", total)
+	}
+	if !cb.CurrentSettings().TrackLatency {
+		t.Error("Expected CurrentSettings to report TrackLatency")
+	}
+}
@@ -0,0 +1,158 @@
+package breaker
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestLatencyHistogram_Percentile(t *testing.T) {
+	var h latencyHistogram
+
+	// Not enough samples yet
+	h.observe(3 * time.Millisecond)
+	if got := h.percentile(0.95); got != 0 {
+		t.Errorf("Expected 0 before minimum samples, got %v", got)
+	}
+
+	// 95 fast requests, 5 slow ones
+	for i := 0; i < 94; i++ {
+		h.observe(3 * time.Millisecond)
+	}
+	for i := 0; i < 5; i++ {
+		h.observe(300 * time.Millisecond)
+	}
+
+	if got := h.percentile(0.95); got != 4*time.Millisecond {
+		t.Errorf("Expected p95 in 4ms bucket, got %v", got)
+	}
+	if got := h.percentile(0.99); got != 512*time.Millisecond {
+		t.Errorf("Expected p99 in 512ms bucket, got %v", got)
+	}
+}
+
+func TestLatencyBucketIndex_Overflow(t *testing.T) {
+	if got := latencyBucketIndex(0); got != 0 {
+		t.Errorf("Expected index 0 for zero latency, got %d", got)
+	}
+	if got := latencyBucketIndex(time.Hour); got != len(latencyBucketBounds) {
+		t.Errorf("Expected overflow bucket, got %d", got)
+	}
+}
+
+func TestExecuteContext_PredictiveReject_ShortDeadline(t *testing.T) {
+	cb := New(Settings{
+		Name:             "test",
+		PredictiveReject: true,
+	})
+
+	// Learn a p95 of ~256ms
+	for i := 0; i < 50; i++ {
+		cb.recordLatency(200 * time.Millisecond)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	executed := false
+	_, err := cb.ExecuteContext(ctx, func() (interface{}, error) {
+		executed = true
+		return "ok", nil
+	})
+
+	if !errors.Is(err, ErrDeadlineTooShort) {
+		t.Fatalf("Expected ErrDeadlineTooShort, got %v", err)
+	}
+	if executed {
+		t.Error("Request should not have been executed")
+	}
+	if counts := cb.Counts(); counts.Requests != 0 {
+		t.Errorf("Rejected request should not be counted, got %d requests", counts.Requests)
+	}
+	if cb.State() != StateClosed {
+		t.Errorf("Predictive rejection should not change state, got %v", cb.State())
+	}
+}
+
+func TestExecuteContext_PredictiveReject_LongDeadline(t *testing.T) {
+	cb := New(Settings{
+		Name:             "test",
+		PredictiveReject: true,
+	})
+
+	for i := 0; i < 50; i++ {
+		cb.recordLatency(200 * time.Millisecond)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	result, err := cb.ExecuteContext(ctx, successFunc)
+	if err != nil {
+		t.Fatalf("Expected request to proceed, got %v", err)
+	}
+	if result != "success" {
+		t.Errorf("Expected 'success', got %v", result)
+	}
+}
+
+func TestExecuteContext_PredictiveReject_NoDeadlineOrNoSamples(t *testing.T) {
+	cb := New(Settings{
+		Name:             "test",
+		PredictiveReject: true,
+	})
+
+	// No samples learned yet: short deadline still proceeds
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	if _, err := cb.ExecuteContext(ctx, successFunc); err != nil {
+		t.Fatalf("Expected request to proceed without learned p95, got %v", err)
+	}
+
+	for i := 0; i < 50; i++ {
+		cb.recordLatency(10 * time.Second)
+	}
+
+	// No deadline: never rejected
+	if _, err := cb.ExecuteContext(context.Background(), successFunc); err != nil {
+		t.Fatalf("Expected request without deadline to proceed, got %v", err)
+	}
+}
+
+func TestExecuteContext_PredictiveReject_Disabled(t *testing.T) {
+	cb := New(Settings{Name: "test"})
+
+	// Latency is not tracked when disabled
+	cb.recordLatency(10 * time.Second)
+	for i := 0; i < 50; i++ {
+		cb.Execute(successFunc)
+	}
+	if cb.latency != nil {
+		t.Error("Expected no latency histogram when disabled")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond)
+	defer cancel()
+	if _, err := cb.ExecuteContext(ctx, successFunc); errors.Is(err, ErrDeadlineTooShort) {
+		t.Error("Predictive rejection should not fire when disabled")
+	}
+}
+
+func TestExecute_RecordsLatencyWhenTracking(t *testing.T) {
+	cb := New(Settings{
+		Name:             "test",
+		PredictiveReject: true,
+	})
+
+	for i := 0; i < 25; i++ {
+		cb.Execute(successFunc)
+	}
+
+	if total := cb.latency.total.Load(); total != 25 {
+		t.Errorf("Expected 25 latency samples, got %d", total)
+	}
+	if p95 := cb.latencyP95(); p95 != time.Millisecond {
+		t.Errorf("Expected p95 in 1ms bucket for instant requests, got %v", p95)
+	}
+}
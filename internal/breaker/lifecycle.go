@@ -0,0 +1,79 @@
+package breaker
+
+// NewChecked creates a new circuit breaker, returning validation errors instead of panicking.
+//
+// This constructor is intended for dependency injection frameworks (uber/fx, google/wire)
+// and configuration-driven setups where invalid settings come from external input and
+// should surface as errors rather than crash the process.
+//
+// Along with the breaker, NewChecked returns a cleanup function that calls
+// Close, stopping any background work owned by the breaker (timers, waiters).
+// Cleanup is idempotent and safe to call concurrently; calling it more than
+// once has no further effect. Callers using NewChecked should always arrange
+// for cleanup to run (e.g., in an fx OnStop hook).
+//
+// Validation is identical to New(). On error, the returned breaker and cleanup are nil.
+//
+// Example - google/wire provider:
+//
+//	func ProvideBreaker(cfg Config) (*autobreaker.CircuitBreaker, func(), error) {
+//	    return autobreaker.NewChecked(autobreaker.Settings{
+//	        Name:    "payments",
+//	        Timeout: cfg.BreakerTimeout,
+//	    })
+//	}
+func NewChecked(settings Settings) (*CircuitBreaker, func(), error) {
+	if err := validateSettings(&settings); err != nil {
+		return nil, nil, err
+	}
+
+	cb := newBreaker(&settings)
+	return cb, func() { cb.Close() }, nil
+}
+
+// Close shuts the breaker down for good.
+//
+// After Close:
+//
+//   - Execute(), ExecuteContext() and the other Execute variants return
+//     ErrBreakerClosed without running the request
+//   - Requests waiting for a MaxConcurrent slot are woken and get
+//     ErrBreakerClosed
+//   - HalfOpenProbeTimeout watchdogs are stopped
+//   - Record() observations are discarded
+//
+// Requests already executing run to completion and are recorded as usual.
+// Read-only methods (State, Metrics, Diagnostics, ...) keep working and report
+// the state the breaker was in. A closed breaker cannot be reopened; create a
+// new one instead.
+//
+// Closed here means shut down, not StateClosed: a breaker in StateClosed is
+// healthy and admitting traffic.
+//
+// Close is idempotent and always returns nil; the error is reserved for
+// future background work that can fail to stop.
+//
+// Thread-safe: Can be called concurrently with Execute() and other methods.
+func (cb *CircuitBreaker) Close() error {
+	if !cb.closed.CompareAndSwap(false, true) {
+		return nil
+	}
+	if cb.bulkhead != nil {
+		close(cb.bulkhead.done)
+	}
+	cb.refreshPlainClosed()
+
+	if cb.watchdog != nil {
+		cb.watchdog.leases.Range(func(key, _ interface{}) bool {
+			key.(*probeLease).timer.Stop()
+			return true
+		})
+	}
+	return nil
+}
+
+// Closed reports whether the breaker was shut down with Close. Unrelated to
+// StateClosed.
+func (cb *CircuitBreaker) Closed() bool {
+	return cb.closed.Load()
+}
@@ -0,0 +1,226 @@
+package breaker
+
+import (
+	"context"
+	"errors"
+	"runtime"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestNewChecked_Valid(t *testing.T) {
+	cb, cleanup, err := NewChecked(Settings{Name: "test", Timeout: time.Second})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if cb == nil || cleanup == nil {
+		t.Fatal("Expected non-nil breaker and cleanup")
+	}
+	if _, err := cb.Execute(successFunc); err != nil {
+		t.Errorf("Expected breaker to be usable, got %v", err)
+	}
+	cleanup()
+}
+
+func TestNewChecked_InvalidSettingsReturnsError(t *testing.T) {
+	defer func() {
+		if r := recover(); r != nil {
+			t.Fatalf("NewChecked must not panic, got %v", r)
+		}
+	}()
+
+	cb, cleanup, err := NewChecked(Settings{
+		AdaptiveThreshold:    true,
+		FailureRateThreshold: 1.5,
+	})
+	if err == nil {
+		t.Fatal("Expected validation error")
+	}
+	if !strings.Contains(err.Error(), "FailureRateThreshold") {
+		t.Errorf("Expected FailureRateThreshold error, got %v", err)
+	}
+	if cb != nil || cleanup != nil {
+		t.Error("Expected nil breaker and cleanup on error")
+	}
+
+}
+
+func TestNewChecked_NewStillPanics(t *testing.T) {
+	// New keeps its panic behavior for compatibility
+	defer func() {
+		if r := recover(); r == nil {
+			t.Error("Expected New to panic on invalid settings")
+		}
+	}()
+	New(Settings{AdaptiveThreshold: true, FailureRateThreshold: 1.5})
+}
+
+func TestNewChecked_CleanupIdempotent(t *testing.T) {
+	cb, cleanup, err := NewChecked(Settings{Name: "test"})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			cleanup()
+		}()
+	}
+	wg.Wait()
+	cleanup()
+
+	if !cb.Closed() {
+		t.Error("Expected the breaker closed after cleanup")
+	}
+}
+
+func TestClose_RejectsNewCalls(t *testing.T) {
+	cb := New(Settings{Name: "close"})
+
+	if cb.Closed() {
+		t.Fatal("Expected a new breaker not closed")
+	}
+	if err := cb.Close(); err != nil {
+		t.Fatalf("Expected Close to succeed, got %v", err)
+	}
+	if err := cb.Close(); err != nil {
+		t.Errorf("Expected a second Close to be a no-op, got %v", err)
+	}
+	if !cb.Closed() {
+		t.Error("Expected Closed after Close")
+	}
+
+	ran := false
+	req := func() (interface{}, error) {
+		ran = true
+		return nil, nil
+	}
+	calls := map[string]func() error{
+		"Execute":          func() error { _, err := cb.Execute(req); return err },
+		"ExecuteContext":   func() error { _, err := cb.ExecuteContext(context.Background(), req); return err },
+		"ExecuteUncounted": func() error { _, err := cb.ExecuteUncounted(req); return err },
+		"ExecuteClassified": func() error {
+			_, err := cb.ExecuteClassified(func() (interface{}, error, bool) {
+				result, err := req()
+				return result, err, true
+			})
+			return err
+		},
+	}
+	for name, call := range calls {
+		if err := call(); !errors.Is(err, ErrBreakerClosed) {
+			t.Errorf("%s: expected ErrBreakerClosed, got %v", name, err)
+		}
+	}
+	if ran {
+		t.Error("Expected no request to run after Close")
+	}
+
+	cb.Record(false)
+	if got := cb.Counts(); got != (Counts{}) {
+		t.Errorf("Expected Record discarded after Close, got %+v", got)
+	}
+}
+
+func TestClose_ConcurrentWithExecute(t *testing.T) {
+	before := runtime.NumGoroutine()
+	cb := New(Settings{Name: "close-concurrent", MaxConcurrent: 4, MaxConcurrentWait: time.Minute})
+
+	const goroutines = 16
+	var wg sync.WaitGroup
+	for i := 0; i < goroutines; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				_, err := cb.Execute(func() (interface{}, error) {
+					time.Sleep(100 * time.Microsecond)
+					return nil, nil
+				})
+				if errors.Is(err, ErrBreakerClosed) {
+					return
+				}
+				if err != nil {
+					t.Errorf("Unexpected error before Close: %v", err)
+					return
+				}
+			}
+		}()
+	}
+
+	time.Sleep(10 * time.Millisecond)
+	cb.Close()
+	wg.Wait() // Every goroutine saw ErrBreakerClosed, including waiters for a slot
+
+	for i := 0; i < 10; i++ {
+		if _, err := cb.Execute(successFunc); !errors.Is(err, ErrBreakerClosed) {
+			t.Fatalf("Expected ErrBreakerClosed after Close, got %v", err)
+		}
+	}
+
+	deadline := time.Now().Add(time.Second)
+	for runtime.NumGoroutine() > before && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	if after := runtime.NumGoroutine(); after > before {
+		t.Errorf("Expected no leaked goroutines, had %d before and %d after", before, after)
+	}
+}
+
+func TestClose_WakesBulkheadWaiters(t *testing.T) {
+	cb := New(Settings{Name: "close-waiters", MaxConcurrent: 1, MaxConcurrentWait: time.Minute})
+
+	release := make(chan struct{})
+	started := make(chan struct{})
+	go cb.Execute(func() (interface{}, error) {
+		close(started)
+		<-release
+		return nil, nil
+	})
+	<-started
+	defer close(release)
+
+	waiter := make(chan error, 1)
+	go func() {
+		_, err := cb.Execute(successFunc)
+		waiter <- err
+	}()
+	time.Sleep(10 * time.Millisecond) // Let the waiter block on the full bulkhead
+
+	cb.Close()
+
+	select {
+	case err := <-waiter:
+		if !errors.Is(err, ErrBreakerClosed) {
+			t.Errorf("Expected ErrBreakerClosed for the waiter, got %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Expected Close to wake the waiter")
+	}
+}
+
+func TestClose_StopsProbeWatchdog(t *testing.T) {
+	cb := New(tripOnFirstFailure(Settings{
+		Name:                 "close-watchdog",
+		Timeout:              10 * time.Millisecond,
+		HalfOpenProbeTimeout: 30 * time.Millisecond,
+	}))
+
+	release, done := startHungProbe(t, cb, func(req func() (interface{}, error)) { cb.Execute(req) })
+	defer func() { release(); <-done }()
+
+	cb.Close()
+	time.Sleep(60 * time.Millisecond)
+
+	if got := cb.Metrics().ProbeTimeouts; got != 0 {
+		t.Errorf("Expected the watchdog stopped by Close, got ProbeTimeouts %d", got)
+	}
+	if cb.State() != StateHalfOpen {
+		t.Errorf("Expected the state left as it was, got %v", cb.State())
+	}
+}
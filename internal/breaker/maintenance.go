@@ -0,0 +1,66 @@
+package breaker
+
+// EnterMaintenance pauses outcome recording for a planned backend maintenance window.
+//
+// While in maintenance, requests are still admitted or rejected according to the
+// current state, and admitted requests are executed normally, but their outcomes
+// are not recorded:
+//
+//   - Counts are not updated, so failures don't pollute the observation window
+//   - No state transitions happen: failures can't trip the circuit, an Open
+//     circuit keeps failing fast (it does not move to HalfOpen when Timeout
+//     elapses), and a HalfOpen circuit keeps probing without deciding
+//   - Warn band, early warning and health tracking are frozen (no callbacks fire)
+//   - Latency observations (PredictiveReject) are not recorded
+//   - Record() observations are discarded
+//
+// The outcome of a call is discarded if the call completes while the breaker is
+// in maintenance, including calls that started before EnterMaintenance().
+// Panics are still re-raised to the caller.
+//
+// Interval-based count clearing continues while in maintenance.
+// Diagnostics().Maintenance reports the mode. Calling EnterMaintenance() while
+// already in maintenance has no effect.
+//
+// Thread-safe: Can be called concurrently with Execute() and other methods.
+//
+// Example:
+//
+//	breaker.EnterMaintenance()
+//	defer breaker.ExitMaintenance()
+//	runMigration()
+func (cb *CircuitBreaker) EnterMaintenance() {
+	cb.maintenance.Store(true)
+	cb.refreshPlainClosed()
+}
+
+// ExitMaintenance resumes outcome recording after EnterMaintenance().
+//
+// Counts and state are exactly as they were before maintenance (apart from
+// interval-based clearing). An Open circuit whose Timeout elapsed during
+// maintenance transitions to HalfOpen on the next request.
+//
+// Calling ExitMaintenance() when not in maintenance has no effect.
+//
+// Thread-safe: Can be called concurrently with Execute() and other methods.
+func (cb *CircuitBreaker) ExitMaintenance() {
+	cb.maintenance.Store(false)
+	cb.refreshPlainClosed()
+}
+
+// InMaintenance reports whether the breaker is in a maintenance window.
+func (cb *CircuitBreaker) InMaintenance() bool {
+	return cb.maintenance.Load()
+}
+
+// discardOutcome undoes the bookkeeping for an admitted request whose outcome
+// is not recorded: the request is uncounted and a half-open probe is returned
+// to the budget.
+func (cb *CircuitBreaker) discardOutcome(requestCounted bool, currentState State) {
+	if requestCounted {
+		cb.uncountRequest()
+	}
+	if currentState == StateHalfOpen {
+		cb.refundProbe()
+	}
+}
@@ -0,0 +1,133 @@
+package breaker
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestMaintenance_FailuresNotRecorded(t *testing.T) {
+	cb := New(Settings{Name: "test", Timeout: time.Minute})
+
+	for i := 0; i < 10; i++ {
+		cb.Execute(successFunc)
+	}
+	cb.Execute(failFunc)
+	before := cb.Counts()
+
+	cb.EnterMaintenance()
+	if !cb.Diagnostics().Maintenance {
+		t.Fatal("Expected Diagnostics().Maintenance to be true")
+	}
+
+	executed := 0
+	for i := 0; i < 100; i++ {
+		_, err := cb.Execute(func() (interface{}, error) {
+			executed++
+			return nil, errors.New("backend down for maintenance")
+		})
+		if err == nil || errors.Is(err, ErrOpenState) {
+			t.Fatalf("Expected backend error to pass through, got %v", err)
+		}
+	}
+
+	if executed != 100 {
+		t.Errorf("Expected all 100 requests to execute, got %d", executed)
+	}
+	if cb.State() != StateClosed {
+		t.Errorf("Expected state unchanged (closed), got %v", cb.State())
+	}
+	if got := cb.Counts(); got != before {
+		t.Errorf("Expected counts unchanged during maintenance: before=%+v, after=%+v", before, got)
+	}
+
+	cb.ExitMaintenance()
+	if cb.Diagnostics().Maintenance {
+		t.Error("Expected Diagnostics().Maintenance to be false after exit")
+	}
+	if got := cb.Counts(); got != before {
+		t.Errorf("Expected counts unchanged on exit: before=%+v, after=%+v", before, got)
+	}
+
+	// Recording resumes after exit
+	cb.Execute(failFunc)
+	if got := cb.Counts().TotalFailures; got != before.TotalFailures+1 {
+		t.Errorf("Expected recording to resume, got %d failures", got)
+	}
+}
+
+func TestMaintenance_ExecuteContextAndPanic(t *testing.T) {
+	cb := New(Settings{Name: "test", Timeout: time.Minute})
+	cb.EnterMaintenance()
+
+	for i := 0; i < 100; i++ {
+		cb.ExecuteContext(context.Background(), failFunc)
+	}
+	func() {
+		defer func() {
+			if r := recover(); r == nil {
+				t.Error("Expected panic to be re-raised during maintenance")
+			}
+		}()
+		cb.Execute(panicFunc)
+	}()
+	cb.Record(false)
+
+	if got := cb.Counts(); got != (Counts{}) {
+		t.Errorf("Expected zero counts, got %+v", got)
+	}
+	if cb.State() != StateClosed {
+		t.Errorf("Expected closed, got %v", cb.State())
+	}
+}
+
+func TestMaintenance_OpenKeepsFailingFast(t *testing.T) {
+	cb := New(Settings{Name: "test", Timeout: 20 * time.Millisecond})
+
+	for i := 0; i < 6; i++ {
+		cb.Execute(failFunc)
+	}
+	if cb.State() != StateOpen {
+		t.Fatalf("Expected open, got %v", cb.State())
+	}
+
+	cb.EnterMaintenance()
+	time.Sleep(40 * time.Millisecond)
+
+	if _, err := cb.Execute(successFunc); !errors.Is(err, ErrOpenState) {
+		t.Errorf("Expected ErrOpenState during maintenance after timeout, got %v", err)
+	}
+	if cb.State() != StateOpen {
+		t.Errorf("Expected state to stay open, got %v", cb.State())
+	}
+
+	cb.ExitMaintenance()
+	if _, err := cb.Execute(successFunc); err != nil {
+		t.Errorf("Expected probe after exit, got %v", err)
+	}
+	if cb.State() != StateClosed {
+		t.Errorf("Expected probe success to close circuit, got %v", cb.State())
+	}
+}
+
+func TestMaintenance_HalfOpenProbeNotDecided(t *testing.T) {
+	cb := New(Settings{Name: "test", Timeout: 10 * time.Millisecond})
+
+	for i := 0; i < 6; i++ {
+		cb.Execute(failFunc)
+	}
+	time.Sleep(20 * time.Millisecond)
+	cb.transitionToHalfOpen()
+
+	cb.EnterMaintenance()
+	for i := 0; i < 10; i++ {
+		if _, err := cb.Execute(failFunc); errors.Is(err, ErrTooManyRequests) {
+			t.Fatalf("Expected probe slot to be released after request %d", i)
+		}
+	}
+
+	if cb.State() != StateHalfOpen {
+		t.Errorf("Expected half-open to be undecided, got %v", cb.State())
+	}
+}
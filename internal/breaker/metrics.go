@@ -23,28 +23,55 @@ import "time"
 // Thread-safe: Metrics() takes an atomic snapshot. The returned Metrics struct
 // is a value type and safe to use without synchronization.
 type Metrics struct {
-	// State is the current circuit breaker state.
+	// State is the current circuit breaker state, as reported by State():
+	// StateDisabled while the breaker is disabled.
 	State State
 
+	// MachineState is the state of the state machine. It equals State except
+	// while disabled, when it holds the frozen state the breaker resumes from
+	// on Enable().
+	MachineState State
+
+	// EffectiveState is State combined with dependencies (see DependsOn):
+	// StateOpen while any dependency is open, otherwise equal to State.
+	// A disabled breaker ignores its dependencies and reports StateDisabled.
+	EffectiveState State
+
 	// Counts contains request and failure statistics.
 	Counts Counts
 
-	// FailureRate is the current failure rate (TotalFailures / Requests).
+	// FailureRate is the current failure rate (TotalFailures / Requests, or
+	// FailureWeight / Requests when Settings.OutcomeWeight is set).
 	// Returns 0 if no requests have been made.
 	// Range: [0.0, 1.0]
 	FailureRate float64
 
-	// SuccessRate is the current success rate (TotalSuccesses / Requests).
+	// SuccessRate is the current success rate (TotalSuccesses / Requests, or
+	// 1 - FailureRate when Settings.OutcomeWeight is set).
 	// Returns 0 if no requests have been made.
 	// Range: [0.0, 1.0]
 	SuccessRate float64
 
+	// Demand is the number of execution attempts in the current window, admitted
+	// or not: rejections in any state (ErrOpenState, ErrTooManyRequests, a
+	// dependency or MaxConcurrent) count, unlike Counts.Requests, as do outcomes
+	// reported with Record. Cleared with the counts. ExecuteUncounted calls, and
+	// calls made while disabled or after Close, are not demand.
+	Demand uint32
+
+	// ServedRatio is the share of Demand that was admitted and counted
+	// (Counts.Requests / Demand): offered load vs served load.
+	// Returns 0 if there has been no demand.
+	// Range: [0.0, 1.0]
+	ServedRatio float64
+
 	// StateChangedAt is the timestamp of the last state transition.
 	// Zero value if no state change has occurred yet.
 	StateChangedAt time.Time
 
 	// CountsLastClearedAt is the timestamp when counts were last reset.
-	// This happens on state transitions or interval-based clearing.
+	// This happens on state transitions or interval-based clearing. With
+	// Settings.AlignIntervalToWallClock it is the aligned window start.
 	CountsLastClearedAt time.Time
 
 	// Saturated indicates if any counter has reached its maximum value (math.MaxUint32).
@@ -52,6 +79,110 @@ type Metrics struct {
 	// Counters saturate to prevent undefined overflow behavior.
 	// Saturation resets when counts are cleared (state transitions or interval reset).
 	Saturated bool
+
+	// Degraded indicates the failure rate is above Settings.WarnFailureRate
+	// without having tripped the circuit. Always false when WarnFailureRate is unset.
+	Degraded bool
+
+	// ProbeRejections is the cumulative number of half-open requests rejected with
+	// ErrTooManyRequests because MaxRequests probes were already in flight.
+	// It measures probe backpressure and is distinct from open-state rejections
+	// (ErrOpenState), which are not included.
+	// Monotonic: never reset by interval clearing or state transitions.
+	ProbeRejections uint64
+
+	// ProbeTimeouts is the cumulative number of half-open probes abandoned by the
+	// HalfOpenProbeTimeout watchdog and recorded as failures.
+	// Monotonic: never reset by interval clearing or state transitions.
+	ProbeTimeouts uint64
+
+	// StreamTimeouts is the cumulative number of streams (ExecuteStream,
+	// ExecuteHTTP) detached after Settings.StreamTimeout without an outcome.
+	// Monotonic: never reset by interval clearing or state transitions.
+	StreamTimeouts uint64
+
+	// LateOutcomes is the cumulative number of calls that completed after the
+	// window they were admitted in was discarded by a state transition, an
+	// interval clear or a reset (UpdateSettings, Enable). Their outcomes are
+	// counted here only: they never touch the live counts, consecutive streaks
+	// or a half-open decision.
+	// Monotonic: never reset by interval clearing or state transitions.
+	LateOutcomes uint64
+
+	// BypassedCalls is the cumulative number of ExecuteContext calls run
+	// outside the breaker with WithBypass, for auditing.
+	// Monotonic: never reset by interval clearing or state transitions.
+	BypassedCalls uint64
+
+	// ClassifierPanics is the cumulative number of calls whose classifier
+	// (IsSuccessful or OutcomeWeight) panicked, each recorded per
+	// Settings.ClassifierPanicOutcome.
+	// Monotonic: never reset by interval clearing or state transitions.
+	ClassifierPanics uint64
+
+	// SlowCalls is the cumulative number of calls recorded as failures because
+	// they exceeded Settings.SlowCallFactor times the learned p95 latency.
+	// Monotonic: never reset by interval clearing or state transitions.
+	SlowCalls uint64
+
+	// IneligibleRejections is the cumulative number of half-open requests
+	// rejected because they were not probe-eligible (see ExecuteOpts).
+	// Monotonic: never reset by interval clearing or state transitions.
+	IneligibleRejections uint64
+
+	// FairnessRejections is the cumulative number of half-open requests
+	// rejected to give another fairness key the next probe (see
+	// ExecuteOpts.FairnessKey). Not broken down by key.
+	// Monotonic: never reset by interval clearing or state transitions.
+	FairnessRejections uint64
+
+	// ProbeGateRejections is the cumulative number of requests rejected with
+	// ErrOpenState after Timeout had elapsed because Settings.ProbeGate held
+	// off the half-open probe.
+	// Monotonic: never reset by interval clearing or state transitions.
+	ProbeGateRejections uint64
+
+	// ProbeSuccesses and ProbeFailures are the cumulative outcomes of half-open
+	// probes, including probes abandoned by HalfOpenProbeTimeout (failures).
+	// Not broken down by fairness key.
+	// Monotonic: never reset by interval clearing or state transitions.
+	ProbeSuccesses uint64
+	ProbeFailures  uint64
+
+	// JournalDrops is the cumulative number of outcome journal entries dropped
+	// because another request was recording at the same instant (see
+	// Settings.JournalSize). Always zero without a journal.
+	// Monotonic: never reset by interval clearing or state transitions.
+	JournalDrops uint64
+
+	// SyntheticSuccesses and SyntheticFailures are the cumulative outcomes of
+	// ExecuteUncounted calls, which are excluded from Counts.
+	// Monotonic: never reset by interval clearing or state transitions.
+	SyntheticSuccesses uint64
+	SyntheticFailures  uint64
+
+	// StaleServes is the cumulative number of rejected calls answered with the
+	// cached result by ExecuteWithCache or ExecuteCached (see Settings.CacheTTL).
+	// Stale serves are rejections: they are not counted as requests.
+	// Monotonic: never reset by interval clearing or state transitions.
+	StaleServes uint64
+
+	// FallbackServed is the cumulative number of ExecuteWithFallbacks calls
+	// answered by each fallback, keyed by Fallback.Name. Nil until a fallback
+	// has served a call.
+	// Monotonic: never reset by interval clearing or state transitions.
+	FallbackServed map[string]uint64
+
+	// Disabled indicates the breaker is bypassed (see Disable): requests run
+	// without admission checks or accounting, and State is frozen.
+	Disabled bool
+
+	// AvgWaitTime is the mean time requests spent waiting for a bulkhead slot
+	// (Settings.MaxConcurrentWait), over requests that had to wait, whether
+	// they were then admitted or rejected. Requests that found a free slot are
+	// not included. Zero if no request has waited.
+	// Cumulative: never reset by interval clearing or state transitions.
+	AvgWaitTime time.Duration
 }
 
 // Metrics returns a snapshot of current circuit breaker metrics.
@@ -87,13 +218,21 @@ type Metrics struct {
 // and other methods. Returns a consistent snapshot.
 func (cb *CircuitBreaker) Metrics() Metrics {
 	counts := cb.Counts()
-	state := cb.State()
+	machineState := cb.machineState()
+	disabled := cb.Disabled()
+	state := machineState
+	if disabled {
+		state = StateDisabled
+	}
 
 	// Calculate derived metrics
 	var failureRate, successRate float64
 	if counts.Requests > 0 {
-		failureRate = float64(counts.TotalFailures) / float64(counts.Requests)
+		failureRate = cb.failureRate(counts)
 		successRate = float64(counts.TotalSuccesses) / float64(counts.Requests)
+		if cb.outcomeWeight != nil {
+			successRate = 1 - failureRate
+		}
 	}
 
 	// Get timestamps
@@ -107,18 +246,58 @@ func (cb *CircuitBreaker) Metrics() Metrics {
 		countsLastClearedAt = time.Unix(0, ts)
 	}
 
+	demand := cb.demandOf(counts.Requests)
+
 	// Check if any counter is saturated
 	saturated := cb.requestsSaturated.Load() ||
 		cb.totalSuccessesSaturated.Load() ||
-		cb.totalFailuresSaturated.Load()
+		cb.totalFailuresSaturated.Load() ||
+		cb.unservedSaturated.Load()
 
+	ev := cb.eventTotals()
 	return Metrics{
-		State:               state,
-		Counts:              counts,
-		FailureRate:         failureRate,
-		SuccessRate:         successRate,
-		StateChangedAt:      stateChangedAt,
-		CountsLastClearedAt: countsLastClearedAt,
-		Saturated:           saturated,
+		State:                state,
+		MachineState:         machineState,
+		EffectiveState:       cb.effectiveState(state),
+		Counts:               counts,
+		FailureRate:          failureRate,
+		SuccessRate:          successRate,
+		Demand:               demand,
+		ServedRatio:          servedRatio(counts.Requests, demand),
+		StateChangedAt:       stateChangedAt,
+		CountsLastClearedAt:  countsLastClearedAt,
+		Saturated:            saturated,
+		Degraded:             cb.degraded.Load(),
+		ProbeRejections:      ev.probeRejections.Load(),
+		ProbeTimeouts:        ev.probeTimeouts.Load(),
+		StreamTimeouts:       ev.streamTimeouts.Load(),
+		LateOutcomes:         ev.lateOutcomes.Load(),
+		BypassedCalls:        ev.bypassedCalls.Load(),
+		ClassifierPanics:     ev.classifierPanics.Load(),
+		SlowCalls:            ev.slowCalls.Load(),
+		IneligibleRejections: ev.ineligibleRejections.Load(),
+		FairnessRejections:   ev.fairnessRejections.Load(),
+		ProbeGateRejections:  ev.probeGateRejections.Load(),
+		ProbeSuccesses:       cb.probeSuccessesTotal.Load(),
+		ProbeFailures:        cb.probeFailuresTotal.Load(),
+		JournalDrops:         cb.journalDrops(),
+		SyntheticSuccesses:   ev.syntheticSuccesses.Load(),
+		SyntheticFailures:    ev.syntheticFailures.Load(),
+		StaleServes:          cb.staleServes(),
+		FallbackServed:       cb.fallbackServedCounts(),
+		Disabled:             disabled,
+		AvgWaitTime:          cb.avgWaitTime(),
+	}
+}
+
+// servedRatio returns requests / demand, 0 without demand. Capped at 1, for
+// aggregates whose members were read at different times.
+func servedRatio(requests, demand uint32) float64 {
+	if demand == 0 {
+		return 0
+	}
+	if requests >= demand {
+		return 1
 	}
+	return float64(requests) / float64(demand)
 }
@@ -1,6 +1,9 @@
 package breaker
 
 import (
+	"errors"
+	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 )
@@ -257,3 +260,70 @@ func TestMetricsConcurrent(t *testing.T) {
 		t.Error("Expected some requests to have been recorded")
 	}
 }
+
+func TestMetrics_ProbeRejections(t *testing.T) {
+	cb := New(Settings{
+		Name:        "test",
+		MaxRequests: 1,
+		Timeout:     10 * time.Millisecond,
+		ReadyToTrip: func(c Counts) bool { return c.ConsecutiveFailures >= 1 },
+	})
+
+	cb.Execute(failFunc)
+	if cb.State() != StateOpen {
+		t.Fatalf("Expected open, got %v", cb.State())
+	}
+
+	// Open-state rejections are not probe rejections
+	cb.Execute(successFunc)
+	if got := cb.Metrics().ProbeRejections; got != 0 {
+		t.Fatalf("Expected 0 probe rejections after open-state rejection, got %d", got)
+	}
+
+	time.Sleep(20 * time.Millisecond)
+
+	// First request takes the only half-open slot and blocks
+	release := make(chan struct{})
+	started := make(chan struct{})
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		cb.Execute(func() (interface{}, error) {
+			close(started)
+			<-release
+			return nil, nil
+		})
+	}()
+	<-started
+
+	const excess = 20
+	var wg sync.WaitGroup
+	var rejected atomic.Int32
+	for i := 0; i < excess; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := cb.Execute(successFunc); errors.Is(err, ErrTooManyRequests) {
+				rejected.Add(1)
+			}
+		}()
+	}
+	wg.Wait()
+
+	m := cb.Metrics()
+	if rejected.Load() != excess {
+		t.Fatalf("Expected %d ErrTooManyRequests, got %d", excess, rejected.Load())
+	}
+	if m.ProbeRejections != excess {
+		t.Errorf("Expected ProbeRejections=%d, got %d", excess, m.ProbeRejections)
+	}
+	if m.Counts.Requests != 1 {
+		t.Errorf("Rejected probes should not be counted as requests, got %d", m.Counts.Requests)
+	}
+	if got := cb.Diagnostics().Metrics.ProbeRejections; got != excess {
+		t.Errorf("Expected Diagnostics to report ProbeRejections=%d, got %d", excess, got)
+	}
+
+	close(release)
+	<-done
+}
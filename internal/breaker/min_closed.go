@@ -0,0 +1,59 @@
+package breaker
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+// closedHold is the MinClosedDuration state: the end (see now) of the hold
+// after the last recovery, and whether a trip within it awaits re-evaluation.
+type closedHold struct {
+	duration time.Duration
+	until    atomic.Int64
+	deferred atomic.Bool
+}
+
+// minClosedDuration returns MinClosedDuration, 0 without a hold.
+func (cb *CircuitBreaker) minClosedDuration() time.Duration {
+	if cb.hold == nil {
+		return 0
+	}
+	return cb.hold.duration
+}
+
+// holdClosed starts the MinClosedDuration window on recovering at now (UnixNano).
+func (cb *CircuitBreaker) holdClosed(now int64) {
+	h := cb.hold
+	if h == nil {
+		return
+	}
+	h.deferred.Store(false)
+	h.until.Store(now + int64(h.duration))
+}
+
+// deferTrip reports whether a trip must wait for the MinClosedDuration window
+// to end, and if so marks it for re-evaluation.
+func (cb *CircuitBreaker) deferTrip() bool {
+	h := cb.hold
+	if h == nil || cb.now() >= h.until.Load() {
+		return false
+	}
+	h.deferred.Store(true)
+	return true
+}
+
+// admitAfterHold re-evaluates a trip deferred by MinClosedDuration once the
+// window has ended. Returns false if the circuit opened, so the request must
+// be rejected.
+func (cb *CircuitBreaker) admitAfterHold() bool {
+	h := cb.hold
+	if !h.deferred.Load() || cb.now() < h.until.Load() {
+		return true
+	}
+	// One request re-evaluates; the rest are admitted as usual
+	if !h.deferred.CompareAndSwap(true, false) {
+		return true
+	}
+	cb.tripIfReady(cb.Counts())
+	return cb.machineState() == StateClosed
+}
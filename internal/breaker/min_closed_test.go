@@ -0,0 +1,121 @@
+package breaker
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestMinClosedDuration_DefersTripUntilWindowEnds(t *testing.T) {
+	clk := newFakeClock()
+	cb := newWithClock(clk, tripOnFirstFailure(Settings{
+		Name:              "min-closed",
+		Timeout:           10 * time.Millisecond,
+		MinClosedDuration: 50 * time.Millisecond,
+	}))
+
+	recoverFromTrip(t, cb, clk)
+	for i := 0; i < 4; i++ {
+		cb.Execute(failFunc)
+	}
+	if cb.State() != StateClosed {
+		t.Fatalf("Expected the trip to be deferred within MinClosedDuration, got %v", cb.State())
+	}
+
+	clk.advance(60 * time.Millisecond)
+	if _, err := cb.Execute(successFunc); !errors.Is(err, ErrOpenState) {
+		t.Fatalf("Expected the deferred trip to reject the first request after the window, got %v", err)
+	}
+	if cb.State() != StateOpen {
+		t.Fatalf("Expected Open once the window ended, got %v", cb.State())
+	}
+	if reason := cb.Diagnostics().OpenReason; reason.Kind != OpenReasonReadyToTrip || reason.Counts.ConsecutiveFailures != 4 {
+		t.Errorf("Expected the trip to be decided on the counts at the window end, got %+v", reason)
+	}
+}
+
+func TestMinClosedDuration_DefersAdaptiveTrip(t *testing.T) {
+	clk := newFakeClock()
+	cb := newWithClock(clk, Settings{
+		Name:                 "min-closed",
+		Timeout:              10 * time.Millisecond,
+		AdaptiveThreshold:    true,
+		FailureRateThreshold: 0.5,
+		MinimumObservations:  4,
+		MinClosedDuration:    50 * time.Millisecond,
+	})
+	for i := 0; i < 4; i++ {
+		cb.Execute(failFunc)
+	}
+	recoverFromTrip(t, cb, clk)
+
+	for i := 0; i < 4; i++ {
+		cb.Execute(failFunc)
+	}
+	cb.Execute(successFunc)
+	if cb.State() != StateClosed {
+		t.Fatalf("Expected the trip to be deferred within MinClosedDuration, got %v", cb.State())
+	}
+
+	clk.advance(60 * time.Millisecond)
+	if _, err := cb.Execute(successFunc); !errors.Is(err, ErrOpenState) {
+		t.Fatalf("Expected the still-bad rate to trip after the window, got %v", err)
+	}
+	if reason := cb.Diagnostics().OpenReason; reason.Kind != OpenReasonFailureRate || reason.Counts.TotalFailures != 4 {
+		t.Errorf("Expected a failure rate trip on the counts at the window end, got %+v", reason)
+	}
+}
+
+func TestMinClosedDuration_RecoveredRateDoesNotTrip(t *testing.T) {
+	clk := newFakeClock()
+	cb := newWithClock(clk, tripOnFirstFailure(Settings{
+		Name:              "min-closed",
+		Timeout:           10 * time.Millisecond,
+		MinClosedDuration: 50 * time.Millisecond,
+	}))
+	recoverFromTrip(t, cb, clk)
+
+	cb.Execute(failFunc)
+	if cb.State() != StateClosed {
+		t.Fatalf("Expected the trip to be deferred, got %v", cb.State())
+	}
+	cb.Execute(successFunc) // Breaks the streak before the window ends
+
+	clk.advance(60 * time.Millisecond)
+	if _, err := cb.Execute(successFunc); err != nil {
+		t.Fatalf("Expected the request after the window to run, got %v", err)
+	}
+	if cb.State() != StateClosed {
+		t.Errorf("Expected no trip once the failures stopped, got %v", cb.State())
+	}
+
+	// The window has ended: trips apply immediately again
+	cb.Execute(failFunc)
+	if cb.State() != StateOpen {
+		t.Errorf("Expected an immediate trip after the window, got %v", cb.State())
+	}
+}
+
+func TestMinClosedDuration_OnlyAfterRecovery(t *testing.T) {
+	cb := New(tripOnFirstFailure(Settings{
+		Name:              "min-closed",
+		Timeout:           time.Minute,
+		MinClosedDuration: time.Minute,
+	}))
+
+	cb.Execute(failFunc)
+	if cb.State() != StateOpen {
+		t.Errorf("Expected a new breaker to trip immediately, got %v", cb.State())
+	}
+}
+
+func TestMinClosedDuration_Disabled(t *testing.T) {
+	clk := newFakeClock()
+	cb := newWithClock(clk, tripOnFirstFailure(Settings{Name: "min-closed", Timeout: 10 * time.Millisecond}))
+	recoverFromTrip(t, cb, clk)
+
+	cb.Execute(failFunc)
+	if cb.State() != StateOpen {
+		t.Errorf("Expected an immediate trip after recovery without MinClosedDuration, got %v", cb.State())
+	}
+}
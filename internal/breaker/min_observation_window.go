@@ -0,0 +1,68 @@
+package breaker
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+// recentSlots is the number of sub-periods MinObservationWindow is split into.
+const recentSlots = 10
+
+// recentRequests counts requests over approximately the last MinObservationWindow.
+//
+// The window is split into recentSlots sub-periods. Each slot packs the low 32
+// bits of its sub-period number with the request count into one uint64, so a
+// request lands in the current sub-period with a single CAS and a slot left
+// over from an older sub-period is reset by the first request that reuses it.
+// The sum covers the current, partial sub-period plus the recentSlots-1 before
+// it: between 90% and 100% of the window.
+type recentRequests struct {
+	slot  int64 // Sub-period length (nanoseconds)
+	slots [recentSlots]atomic.Uint64
+}
+
+func newRecentRequests(window time.Duration) *recentRequests {
+	slot := int64(window) / recentSlots
+	if slot == 0 {
+		slot = 1
+	}
+	return &recentRequests{slot: slot}
+}
+
+// add counts one request at now (UnixNano).
+func (r *recentRequests) add(now int64) {
+	period := uint32(now / r.slot)
+	s := &r.slots[period%recentSlots]
+	for {
+		old := s.Load()
+		next := uint64(period)<<32 | 1
+		if uint32(old>>32) == period {
+			next = old + 1
+		}
+		if s.CompareAndSwap(old, next) {
+			return
+		}
+	}
+}
+
+// count returns the number of requests within the window ending at now (UnixNano).
+func (r *recentRequests) count(now int64) uint32 {
+	period := uint32(now / r.slot)
+	var total uint32
+	for i := range r.slots {
+		v := r.slots[i].Load()
+		if period-uint32(v>>32) < recentSlots {
+			total += uint32(v)
+		}
+	}
+	return total
+}
+
+// hasMinimumObservations reports whether MinimumObservations requests were
+// counted within MinObservationWindow of now. Always true without the window.
+func (cb *CircuitBreaker) hasMinimumObservations(now int64) bool {
+	if cb.recent == nil {
+		return true
+	}
+	return cb.recent.count(now) >= cb.getMinimumObservations()
+}
@@ -0,0 +1,129 @@
+package breaker
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRecentRequests_CountsWithinWindow(t *testing.T) {
+	r := newRecentRequests(10 * time.Second)
+	start := int64(time.Hour)
+
+	// 20 requests within one second
+	for i := 0; i < 20; i++ {
+		r.add(start + int64(i)*int64(50*time.Millisecond))
+	}
+	if got := r.count(start + int64(time.Second)); got != 20 {
+		t.Errorf("Expected 20 recent requests, got %d", got)
+	}
+
+	// A full window later, all have aged out
+	if got := r.count(start + int64(11*time.Second)); got != 0 {
+		t.Errorf("Expected 0 recent requests after the window, got %d", got)
+	}
+}
+
+func TestRecentRequests_SpreadOverLongPeriod(t *testing.T) {
+	r := newRecentRequests(10 * time.Second)
+	start := int64(time.Hour)
+
+	// 20 requests, one every 30s: at most one is within any 10s window
+	var now int64
+	for i := 0; i < 20; i++ {
+		now = start + int64(i)*int64(30*time.Second)
+		r.add(now)
+	}
+	if got := r.count(now); got != 1 {
+		t.Errorf("Expected 1 recent request, got %d", got)
+	}
+}
+
+func TestRecentRequests_SlotReusedAfterWrap(t *testing.T) {
+	r := newRecentRequests(10 * time.Second)
+	start := int64(time.Hour)
+
+	r.add(start)
+	r.add(start)
+
+	// Same slot, one full window later: the stale count is replaced
+	later := start + int64(10*time.Second)
+	r.add(later)
+	if got := r.count(later); got != 1 {
+		t.Errorf("Expected stale slot reset on reuse, got %d", got)
+	}
+}
+
+func TestMinObservationWindow_SpreadRequestsDoNotActivate(t *testing.T) {
+	const window = 100 * time.Millisecond
+	cb := New(Settings{
+		Name:                 "min-window-spread",
+		AdaptiveThreshold:    true,
+		FailureRateThreshold: 0.1,
+		MinimumObservations:  20,
+		MinObservationWindow: window,
+	})
+
+	for i := 0; i < 10; i++ {
+		cb.Execute(successFunc)
+	}
+	time.Sleep(window + 20*time.Millisecond)
+
+	// 20 requests at a 50% failure rate, but only 10 within the window
+	for i := 0; i < 10; i++ {
+		cb.Execute(failFunc)
+	}
+	if got := cb.Counts().Requests; got != 20 {
+		t.Fatalf("Expected 20 requests counted, got %d", got)
+	}
+	if cb.State() != StateClosed {
+		t.Errorf("Expected Closed with too few recent requests, got %v", cb.State())
+	}
+}
+
+func TestMinObservationWindow_DenseRequestsActivate(t *testing.T) {
+	cb := New(Settings{
+		Name:                 "min-window-dense",
+		AdaptiveThreshold:    true,
+		FailureRateThreshold: 0.1,
+		MinimumObservations:  20,
+		MinObservationWindow: time.Minute,
+	})
+
+	for i := 0; i < 10; i++ {
+		cb.Execute(successFunc)
+	}
+	for i := 0; i < 10; i++ {
+		cb.Execute(failFunc)
+	}
+	if cb.State() != StateOpen {
+		t.Errorf("Expected Open with 20 requests within the window, got %v", cb.State())
+	}
+}
+
+func TestMinObservationWindow_DisabledByDefault(t *testing.T) {
+	cb := New(Settings{
+		Name:                 "min-window-off",
+		AdaptiveThreshold:    true,
+		FailureRateThreshold: 0.1,
+		MinimumObservations:  20,
+	})
+
+	if cb.recent != nil {
+		t.Fatal("Expected no recent-request tracking without MinObservationWindow")
+	}
+	if !cb.hasMinimumObservations(time.Now().UnixNano()) {
+		t.Error("Expected the gate to pass without MinObservationWindow")
+	}
+}
+
+func TestMinObservationWindow_InCurrentSettings(t *testing.T) {
+	cb := New(Settings{
+		Name:                 "min-window-settings",
+		AdaptiveThreshold:    true,
+		MinObservationWindow: 5 * time.Second,
+	})
+
+	if got := cb.CurrentSettings().MinObservationWindow; got != 5*time.Second {
+		t.Errorf("Expected MinObservationWindow 5s, got %v", got)
+	}
+}
@@ -0,0 +1,147 @@
+package breaker
+
+import (
+	"fmt"
+	"sync"
+)
+
+// mirrorMu serializes changes to mirror subscriptions so cycle detection sees
+// a consistent view. Notifying mirrors on a transition is lock-free.
+var mirrorMu sync.Mutex
+
+// MirrorFrom makes this breaker a warm standby of source: it follows source's
+// state transitions, so that on failover it starts in the state the active
+// instance was in instead of cold.
+//
+// This breaker moves to source's current state immediately and then follows
+// each source transition as it is delivered (after source's OnStateChange
+// callbacks, in the same order). Transitions are made through the usual state
+// machine, so this breaker's own OnStateChange callbacks, episodes and open
+// reason (OpenReasonMirrored) report them; a Closed mirror following source
+// into HalfOpen passes through Open. An Open mirror waits its own Timeout from
+// the moment it followed.
+//
+// Per-request counts are not mirrored: each state change clears this breaker's
+// counts as usual. Requests executed through this breaker are still recorded
+// and can still trip it on their own; source's next transition overrides.
+//
+// A breaker mirrors at most one source: MirrorFrom replaces any earlier source.
+// Returns an error wrapping ErrMirrorCycle if source is this breaker or
+// (transitively) mirrors it. Call Promote to stop following.
+//
+// Thread-safe: Can be called concurrently with Execute() and other methods.
+//
+// Example - active/passive pair:
+//
+//	standby := autobreaker.New(autobreaker.Settings{Name: "payments-standby"})
+//	if err := standby.MirrorFrom(active); err != nil {
+//	    log.Fatal(err)
+//	}
+//
+//	// On failover
+//	standby.Promote()
+func (cb *CircuitBreaker) MirrorFrom(source *CircuitBreaker) error {
+	mirrorMu.Lock()
+	for src := source; src != nil; src = src.mirrorSource.Load() {
+		if src == cb {
+			mirrorMu.Unlock()
+			return fmt.Errorf("autobreaker: %q mirrors %q: %w", cb.name, source.name, ErrMirrorCycle)
+		}
+	}
+	if previous := cb.mirrorSource.Load(); previous != nil {
+		previous.removeMirror(cb)
+	}
+	cb.mirrorSource.Store(source)
+	source.addMirror(cb)
+	mirrorMu.Unlock()
+
+	// Catch up outside the lock: the transitions run state change callbacks
+	cb.followState(source, source.machineState())
+	return nil
+}
+
+// Promote stops mirroring (see MirrorFrom), so this breaker operates
+// independently from the state it was last in. Promote on a breaker that is
+// not mirroring is a no-op.
+//
+// Thread-safe: Can be called concurrently with Execute() and other methods.
+func (cb *CircuitBreaker) Promote() {
+	mirrorMu.Lock()
+	defer mirrorMu.Unlock()
+
+	if source := cb.mirrorSource.Swap(nil); source != nil {
+		source.removeMirror(cb)
+	}
+}
+
+// addMirror subscribes m to cb's transitions. Caller must hold mirrorMu.
+func (cb *CircuitBreaker) addMirror(m *CircuitBreaker) {
+	var mirrors []*CircuitBreaker
+	if current := cb.mirrors.Load(); current != nil {
+		mirrors = append(mirrors, *current...)
+	}
+	mirrors = append(mirrors, m)
+	cb.mirrors.Store(&mirrors)
+}
+
+// removeMirror unsubscribes m from cb's transitions. Caller must hold mirrorMu.
+func (cb *CircuitBreaker) removeMirror(m *CircuitBreaker) {
+	current := cb.mirrors.Load()
+	if current == nil {
+		return
+	}
+	var mirrors []*CircuitBreaker
+	for _, other := range *current {
+		if other != m {
+			mirrors = append(mirrors, other)
+		}
+	}
+	if len(mirrors) == 0 {
+		cb.mirrors.Store(nil)
+		return
+	}
+	cb.mirrors.Store(&mirrors)
+}
+
+// notifyMirrors moves cb's mirrors to state to.
+func (cb *CircuitBreaker) notifyMirrors(to State) {
+	mirrors := cb.mirrors.Load()
+	if mirrors == nil {
+		return
+	}
+	for _, m := range *mirrors {
+		m.followState(cb, to)
+	}
+}
+
+// followState moves cb to state to along the state machine's transitions, as
+// a mirror of source. Does nothing once cb no longer mirrors source.
+func (cb *CircuitBreaker) followState(source *CircuitBreaker, to State) {
+	// Each step moves one transition closer; two steps reach any state
+	for step := 0; step < 2; step++ {
+		if cb.mirrorSource.Load() != source {
+			return // Promoted
+		}
+		from := cb.machineState()
+		switch {
+		case from == to:
+			return
+		case from == StateClosed:
+			cb.openFromClosed(mirroredReason(source))
+		case from == StateOpen:
+			cb.transitionToHalfOpen()
+		case to == StateClosed:
+			cb.transitionToClosed()
+		default:
+			cb.reopenFromHalfOpen(mirroredReason(source))
+		}
+	}
+}
+
+// mirroredReason is the open reason of a mirror following source into Open.
+func mirroredReason(source *CircuitBreaker) *OpenReason {
+	return &OpenReason{
+		Kind:   OpenReasonMirrored,
+		Detail: fmt.Sprintf("mirrored from %q", source.name),
+	}
+}
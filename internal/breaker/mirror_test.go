@@ -0,0 +1,168 @@
+package breaker
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestMirrorFrom_FollowsSourceTransitions(t *testing.T) {
+	const timeout = 30 * time.Second
+	clk := newFakeClock()
+	active := newWithClock(clk, tripOnFirstFailure(Settings{Name: "active", Timeout: timeout}))
+	standby := newWithClock(clk, Settings{Name: "standby", Timeout: timeout})
+	if err := standby.MirrorFrom(active); err != nil {
+		t.Fatalf("Expected MirrorFrom to succeed, got %v", err)
+	}
+
+	// The source's traffic is not mirrored, only its trip
+	active.Execute(successFunc)
+	active.Execute(failFunc)
+	if standby.State() != StateOpen {
+		t.Fatalf("Expected the mirror to follow the source into Open, got %v", standby.State())
+	}
+	if reason := standby.Diagnostics().OpenReason; reason.Kind != OpenReasonMirrored {
+		t.Errorf("Expected a mirrored open reason, got %+v", reason)
+	}
+	if counts := standby.Counts(); counts.Requests != 0 {
+		t.Errorf("Expected no mirrored counts, got %+v", counts)
+	}
+	if _, err := standby.Execute(successFunc); err != ErrOpenState {
+		t.Errorf("Expected the mirror to reject while open, got %v", err)
+	}
+
+	// The source recovers and the mirror follows
+	clk.advance(timeout)
+	active.Execute(successFunc)
+	if active.State() != StateClosed || standby.State() != StateClosed {
+		t.Errorf("Expected both Closed, got active=%v standby=%v", active.State(), standby.State())
+	}
+}
+
+func TestMirrorFrom_CatchesUp(t *testing.T) {
+	const timeout = 30 * time.Second
+	clk := newFakeClock()
+	active := newWithClock(clk, tripOnFirstFailure(Settings{
+		Name:              "active",
+		Timeout:           timeout,
+		HalfOpenMaxProbes: 2,
+	}))
+	active.Execute(failFunc)
+	clk.advance(timeout)
+	active.Execute(successFunc) // One of two probes: still HalfOpen
+	if active.State() != StateHalfOpen {
+		t.Fatalf("Expected the source HalfOpen, got %v", active.State())
+	}
+
+	var transitions []string
+	standby := newWithClock(clk, Settings{
+		Name: "standby",
+		OnStateChange: func(_ string, from, to State) {
+			transitions = append(transitions, from.String()+"->"+to.String())
+		},
+	})
+	if err := standby.MirrorFrom(active); err != nil {
+		t.Fatal(err)
+	}
+	if standby.State() != StateHalfOpen {
+		t.Fatalf("Expected the mirror to start in the source's state, got %v", standby.State())
+	}
+	if len(transitions) != 2 || transitions[0] != "closed->open" || transitions[1] != "open->half-open" {
+		t.Errorf("Expected the mirror to pass through Open, got %v", transitions)
+	}
+
+	// HalfOpen -> Open on the source's failed probe
+	active.Execute(failFunc)
+	if standby.State() != StateOpen {
+		t.Errorf("Expected the mirror to reopen with the source, got %v", standby.State())
+	}
+}
+
+func TestPromote_OperatesIndependently(t *testing.T) {
+	const timeout = 30 * time.Second
+	clk := newFakeClock()
+	active := newWithClock(clk, tripOnFirstFailure(Settings{Name: "active", Timeout: timeout}))
+	standby := newWithClock(clk, tripOnFirstFailure(Settings{Name: "standby", Timeout: 2 * timeout}))
+	if err := standby.MirrorFrom(active); err != nil {
+		t.Fatal(err)
+	}
+
+	active.Execute(failFunc)
+	standby.Promote()
+
+	// The source recovers; the promoted mirror stays open on its own Timeout
+	clk.advance(timeout)
+	active.Execute(successFunc)
+	if active.State() != StateClosed {
+		t.Fatalf("Expected the source Closed, got %v", active.State())
+	}
+	if standby.State() != StateOpen {
+		t.Fatalf("Expected the promoted mirror to stay Open, got %v", standby.State())
+	}
+
+	// ...and recovers through its own probe
+	clk.advance(timeout)
+	if _, err := standby.Execute(successFunc); err != nil {
+		t.Fatalf("Expected the promoted mirror to probe, got %v", err)
+	}
+	if standby.State() != StateClosed {
+		t.Errorf("Expected the promoted mirror Closed, got %v", standby.State())
+	}
+
+	// Its own failures trip only itself
+	standby.Execute(failFunc)
+	if standby.State() != StateOpen || active.State() != StateClosed {
+		t.Errorf("Expected only the promoted breaker to trip, got active=%v standby=%v", active.State(), standby.State())
+	}
+
+	// A second Promote is a no-op
+	standby.Promote()
+}
+
+func TestMirrorFrom_Cycle(t *testing.T) {
+	a := New(Settings{Name: "a"})
+	b := New(Settings{Name: "b"})
+	c := New(Settings{Name: "c"})
+
+	if err := a.MirrorFrom(a); !errors.Is(err, ErrMirrorCycle) {
+		t.Errorf("Expected ErrMirrorCycle mirroring itself, got %v", err)
+	}
+	if err := b.MirrorFrom(a); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.MirrorFrom(b); err != nil {
+		t.Fatal(err)
+	}
+	if err := a.MirrorFrom(c); !errors.Is(err, ErrMirrorCycle) {
+		t.Errorf("Expected ErrMirrorCycle for a transitive cycle, got %v", err)
+	}
+
+	// Chains follow transitively
+	for i := 0; i < 6; i++ {
+		a.Execute(failFunc)
+	}
+	if c.State() != StateOpen {
+		t.Errorf("Expected the chain to follow the trip, got %v", c.State())
+	}
+}
+
+func TestMirrorFrom_ReplacesSource(t *testing.T) {
+	first := New(tripOnFirstFailure(Settings{Name: "first"}))
+	second := New(tripOnFirstFailure(Settings{Name: "second"}))
+	standby := New(Settings{Name: "standby"})
+
+	if err := standby.MirrorFrom(first); err != nil {
+		t.Fatal(err)
+	}
+	if err := standby.MirrorFrom(second); err != nil {
+		t.Fatal(err)
+	}
+	first.Execute(failFunc)
+	if standby.State() != StateClosed {
+		t.Errorf("Expected the replaced source to be ignored, got %v", standby.State())
+	}
+	second.Execute(failFunc)
+	if standby.State() != StateOpen {
+		t.Errorf("Expected the new source to be followed, got %v", standby.State())
+	}
+}
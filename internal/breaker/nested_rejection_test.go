@@ -0,0 +1,141 @@
+package breaker
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+// layered returns an outer breaker whose request function calls the inner one.
+func layered(outer, inner *CircuitBreaker) func() (interface{}, error) {
+	return func() (interface{}, error) {
+		return outer.Execute(func() (interface{}, error) {
+			return inner.Execute(successFunc)
+		})
+	}
+}
+
+func tripBreaker(t *testing.T, cb *CircuitBreaker) {
+	t.Helper()
+	for i := 0; i < 6; i++ {
+		cb.Execute(failFunc)
+	}
+	if cb.State() != StateOpen {
+		t.Fatalf("Expected %s open, got %v", cb.Name(), cb.State())
+	}
+}
+
+func TestNestedRejection_OuterStaysClosed(t *testing.T) {
+	inner := New(Settings{Name: "database", Timeout: time.Minute})
+	outer := New(Settings{Name: "api", Timeout: time.Minute})
+	call := layered(outer, inner)
+
+	call()
+	call()
+	before := outer.Counts()
+
+	tripBreaker(t, inner)
+
+	for i := 0; i < 20; i++ {
+		if _, err := call(); !errors.Is(err, ErrOpenState) {
+			t.Fatalf("Expected inner ErrOpenState passed through, got %v", err)
+		}
+	}
+
+	if outer.State() != StateClosed {
+		t.Errorf("Expected outer to stay closed, got %v", outer.State())
+	}
+	if got := outer.Counts(); got != before {
+		t.Errorf("Expected outer counts unchanged: before=%+v, after=%+v", before, got)
+	}
+}
+
+func TestNestedRejection_AllSentinelsIgnored(t *testing.T) {
+	sentinels := []error{
+		ErrOpenState,
+		ErrTooManyRequests,
+		ErrDeadlineTooShort,
+		&DependencyOpenError{Dependency: "cache"},
+	}
+
+	for _, sentinel := range sentinels {
+		t.Run(sentinel.Error(), func(t *testing.T) {
+			cb := New(Settings{Name: "api"})
+			_, err := cb.Execute(func() (interface{}, error) {
+				return nil, fmtWrap(sentinel)
+			})
+			if !errors.Is(err, sentinel) {
+				t.Errorf("Expected error returned unchanged, got %v", err)
+			}
+			if got := cb.Counts(); got.Requests != 0 || got.TotalFailures != 0 {
+				t.Errorf("Expected ignored outcome, got %+v", got)
+			}
+		})
+	}
+}
+
+func TestNestedRejection_ExecuteContext(t *testing.T) {
+	inner := New(Settings{Name: "database", Timeout: time.Minute})
+	outer := New(Settings{Name: "api", Timeout: time.Minute})
+	tripBreaker(t, inner)
+
+	for i := 0; i < 10; i++ {
+		outer.ExecuteContext(context.Background(), func() (interface{}, error) {
+			return inner.ExecuteContext(context.Background(), successFunc)
+		})
+	}
+
+	if outer.State() != StateClosed || outer.Counts().Requests != 0 {
+		t.Errorf("Expected outer untouched, got %v %+v", outer.State(), outer.Counts())
+	}
+}
+
+func TestNestedRejection_HalfOpenProbeNotDecided(t *testing.T) {
+	inner := New(Settings{Name: "database", Timeout: time.Minute})
+	outer := New(Settings{Name: "api", Timeout: time.Minute, StartHalfOpen: true})
+	tripBreaker(t, inner)
+
+	for i := 0; i < 3; i++ {
+		if _, err := layered(outer, inner)(); !errors.Is(err, ErrOpenState) {
+			t.Fatalf("Expected nested ErrOpenState, got %v", err)
+		}
+	}
+	if outer.State() != StateHalfOpen {
+		t.Errorf("Expected outer probe undecided, got %v", outer.State())
+	}
+}
+
+func TestNestedRejection_CountNestedRejections(t *testing.T) {
+	inner := New(Settings{Name: "database", Timeout: time.Minute})
+	outer := New(Settings{Name: "api", Timeout: time.Minute, CountNestedRejections: true})
+	tripBreaker(t, inner)
+
+	for i := 0; i < 6; i++ {
+		layered(outer, inner)()
+	}
+
+	if outer.State() != StateOpen {
+		t.Errorf("Expected nested rejections to trip the outer breaker, got %v", outer.State())
+	}
+	if !outer.CurrentSettings().CountNestedRejections {
+		t.Error("Expected CurrentSettings to report CountNestedRejections")
+	}
+}
+
+func TestNestedRejection_ClassifiedFlagWins(t *testing.T) {
+	cb := New(Settings{Name: "api"})
+
+	cb.ExecuteClassified(func() (interface{}, error, bool) {
+		return nil, ErrOpenState, false
+	})
+
+	if got := cb.Counts().TotalFailures; got != 1 {
+		t.Errorf("Expected explicit flag to count nested rejection, got %d failures", got)
+	}
+}
+
+// fmtWrap wraps err the way an intermediate layer typically would.
+func fmtWrap(err error) error {
+	return errors.Join(errors.New("calling dependency"), err)
+}
@@ -0,0 +1,65 @@
+package breaker
+
+// ExecuteObserveOnly runs req regardless of the circuit state and records its
+// outcome in the counts, without enforcing anything. It is meant for a warmup
+// phase, per call: traffic always flows, but the breaker's view of the backend
+// is accurate before enforcement begins.
+//
+// Unlike Execute, the call is never rejected: not in Open or HalfOpen, not by
+// MaxConcurrent, a dependency or a closed-period allowance, and it takes no
+// HalfOpen slot. Its outcome goes through the same recording path as Execute
+// (IsSuccessful or OutcomeWeight, SlowCallFactor, panics as failures) and is
+// added to Counts, but never evaluated: the call itself does not trip, close
+// or reopen the circuit. The next Execute outcome is judged against counts
+// that include it.
+//
+// Unlike Disable, which bypasses the breaker for every caller, observe-only is
+// chosen per call, and its outcomes are counted. ErrIgnoreOutcome, nested
+// rejections, maintenance and a closed breaker (ErrBreakerClosed) behave as in
+// Execute; while disabled the call runs without accounting.
+//
+// Thread-safe: Safe to call concurrently.
+//
+// Example - Warmup Before Enforcing:
+//
+//	if time.Since(startedAt) < warmup {
+//	    return breaker.ExecuteObserveOnly(call)
+//	}
+//	return breaker.Execute(call)
+func (cb *CircuitBreaker) ExecuteObserveOnly(req func() (interface{}, error)) (interface{}, error) {
+	a := admission{observe: true}
+	if err := cb.admitObserving(&a); err != nil {
+		return nil, err
+	}
+	return cb.runAdmitted(&a, req, nil)
+}
+
+// admitObserving is admit for an observe-only call: it never rejects, except
+// once shut down, and holds no slots. The call is admitted as if Closed, so it
+// never takes or refunds a HalfOpen probe slot.
+func (cb *CircuitBreaker) admitObserving(a *admission) error {
+	// Reject everything once shut down
+	if cb.closed.Load() {
+		return ErrBreakerClosed
+	}
+
+	// Run directly, without accounting, while disabled
+	if cb.Disabled() {
+		a.unprotected = true
+		return nil
+	}
+
+	// Keep the Closed-state window current (clearing counts is not a transition)
+	if cb.intervalEnabled.Load() && cb.machineState() == StateClosed {
+		cb.maybeResetCounts()
+	}
+
+	// If the counter is saturated the call still runs but is not recorded
+	a.requestCounted = cb.safeIncrementRequests()
+	if !a.requestCounted {
+		cb.recordUnserved()
+	}
+	a.state = StateClosed
+	a.gen = cb.admissionGeneration()
+	return nil
+}
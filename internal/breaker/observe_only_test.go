@@ -0,0 +1,104 @@
+package breaker
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestExecuteObserveOnly_RunsWhileOpen(t *testing.T) {
+	cb := New(tripOnFirstFailure(Settings{Name: "observe-open", Timeout: time.Minute}))
+	cb.Execute(failFunc)
+
+	ran := 0
+	for i := 0; i < 3; i++ {
+		_, err := cb.ExecuteObserveOnly(func() (interface{}, error) {
+			ran++
+			return "ok", nil
+		})
+		if err != nil {
+			t.Fatalf("Expected the observe-only call to run while Open, got %v", err)
+		}
+	}
+	cb.ExecuteObserveOnly(failFunc)
+
+	if ran != 3 {
+		t.Errorf("Expected 3 calls to run, got %d", ran)
+	}
+	if cb.State() != StateOpen {
+		t.Errorf("Expected state untouched, got %v", cb.State())
+	}
+	want := Counts{Requests: 4, TotalSuccesses: 3, TotalFailures: 1, ConsecutiveFailures: 1}
+	if got := cb.Counts(); got != want {
+		t.Errorf("Expected counts %+v, got %+v", want, got)
+	}
+	if _, err := cb.Execute(successFunc); !errors.Is(err, ErrOpenState) {
+		t.Errorf("Expected Execute still rejected, got %v", err)
+	}
+}
+
+func TestExecuteObserveOnly_NeverTrips(t *testing.T) {
+	cb := New(tripOnFirstFailure(Settings{Name: "observe-closed", Timeout: time.Minute}))
+
+	for i := 0; i < 5; i++ {
+		cb.ExecuteObserveOnly(failFunc)
+	}
+
+	if cb.State() != StateClosed {
+		t.Fatalf("Expected observe-only failures not to trip, got %v", cb.State())
+	}
+	if got := cb.Counts().TotalFailures; got != 5 {
+		t.Errorf("Expected 5 failures counted, got %d", got)
+	}
+
+	// Enforcement judges the next outcome against the observed counts
+	cb.Execute(failFunc)
+	if cb.State() != StateOpen {
+		t.Errorf("Expected the next Execute failure to trip, got %v", cb.State())
+	}
+}
+
+func TestExecuteObserveOnly_DoesNotDecideHalfOpen(t *testing.T) {
+	cb := New(tripOnFirstFailure(Settings{Name: "observe-half-open", Timeout: 10 * time.Millisecond}))
+	cb.Execute(failFunc)
+	time.Sleep(20 * time.Millisecond)
+	cb.Execute(func() (interface{}, error) {
+		// A probe is in flight: observe-only calls still run and decide nothing
+		if _, err := cb.ExecuteObserveOnly(failFunc); err == nil {
+			t.Errorf("Expected the observe-only call to run during the probe, got %v", err)
+		}
+		if cb.State() != StateHalfOpen {
+			t.Errorf("Expected HalfOpen while the probe runs, got %v", cb.State())
+		}
+		return "ok", nil
+	})
+
+	if cb.State() != StateClosed {
+		t.Errorf("Expected the probe alone to decide, got %v", cb.State())
+	}
+}
+
+func TestExecuteObserveOnly_IgnoredOutcomeNotCounted(t *testing.T) {
+	cb := New(Settings{Name: "observe-ignored", Timeout: time.Minute})
+	errValidation := errors.New("validation")
+
+	_, err := cb.ExecuteObserveOnly(func() (interface{}, error) {
+		return nil, errors.Join(ErrIgnoreOutcome, errValidation)
+	})
+
+	if !errors.Is(err, errValidation) || errors.Is(err, ErrIgnoreOutcome) {
+		t.Errorf("Expected the sentinel stripped, got %v", err)
+	}
+	if got := cb.Counts(); got != (Counts{}) {
+		t.Errorf("Expected nothing counted, got %+v", got)
+	}
+}
+
+func TestExecuteObserveOnly_AfterClose(t *testing.T) {
+	cb := New(Settings{Name: "observe-closed-breaker"})
+	cb.Close()
+
+	if _, err := cb.ExecuteObserveOnly(successFunc); !errors.Is(err, ErrBreakerClosed) {
+		t.Errorf("Expected ErrBreakerClosed, got %v", err)
+	}
+}
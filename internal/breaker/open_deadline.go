@@ -0,0 +1,82 @@
+package breaker
+
+import "time"
+
+// openHoldCheckOdds is how many held rejections there are for each one that
+// reads the clock anyway (see openHeldNow).
+const openHoldCheckOdds = 64
+
+// openHeldNow reports whether openHeld holds the circuit Open: the rejection
+// fast path, without a clock read. The release timer runs late when the
+// scheduler is saturated, so a held rejection that finds unserved at a
+// multiple of openHoldCheckOdds reads the clock and releases an expired hold
+// itself. Each rejection counted as demand advances unserved, so about one in
+// openHoldCheckOdds of those does, and picking it costs a load rather than a
+// shared write. Rejections outside demand (synthetic calls) rely on the timer.
+func (cb *CircuitBreaker) openHeldNow() bool {
+	return cb.openHeld.Load() && (cb.unserved.Load()%openHoldCheckOdds != 0 || cb.openHoldUnexpired())
+}
+
+// openHoldUnexpired reports whether the held deadline is still ahead,
+// releasing the hold if not.
+func (cb *CircuitBreaker) openHoldUnexpired() bool {
+	if until := cb.openDeadline.Load(); until > 0 && cb.now() < until {
+		return true
+	}
+	cb.openHeld.Store(false)
+	return false
+}
+
+// openDeadlineCached reports whether the cached end of the open period (see
+// cacheOpenDeadline) is still ahead: the Open rejection fast path.
+func (cb *CircuitBreaker) openDeadlineCached() bool {
+	if cb.openHeldNow() {
+		return true
+	}
+	until := cb.openDeadline.Load()
+	return until > 0 && cb.now() < until
+}
+
+// cacheOpenDeadline caches that the circuit keeps rejecting for remaining, so
+// rejections until then skip the openedAt/openWait computation. On the system
+// clock it also sets openHeld, released by a timer at the deadline, so they
+// skip reading the clock too. A timer never fires early; a fake clock does not
+// drive timers, so it is left to the deadline comparison.
+//
+// gen is the generation read before the inputs were loaded. If the cache was
+// invalidated since, the inputs may be stale (a shorter Timeout must not be
+// masked by a longer cached deadline), so the entry is withdrawn.
+func (cb *CircuitBreaker) cacheOpenDeadline(gen uint64, remaining time.Duration) {
+	until := cb.now() + int64(remaining)
+	cb.openDeadline.Store(until)
+	if cb.openDeadlineGen.Load() != gen {
+		cb.openDeadline.CompareAndSwap(until, 0)
+		return
+	}
+
+	if cb.clock != nil || !cb.openHeld.CompareAndSwap(false, true) {
+		return
+	}
+	time.AfterFunc(remaining, func() { cb.releaseOpenHold(gen) })
+	// An invalidation between the check above and the hold clears it itself
+	// unless it came first.
+	if cb.openDeadlineGen.Load() != gen {
+		cb.openHeld.Store(false)
+	}
+}
+
+// releaseOpenHold clears openHeld at the deadline cached in generation gen.
+// A hold from a later generation belongs to that generation's timer.
+func (cb *CircuitBreaker) releaseOpenHold(gen uint64) {
+	if cb.openDeadlineGen.Load() == gen {
+		cb.openHeld.Store(false)
+	}
+}
+
+// invalidateOpenDeadline drops the cached deadline. Called on every state
+// transition and on updates that change the open wait.
+func (cb *CircuitBreaker) invalidateOpenDeadline() {
+	cb.openDeadlineGen.Add(1)
+	cb.openHeld.Store(false)
+	cb.openDeadline.Store(0)
+}
@@ -0,0 +1,112 @@
+package breaker
+
+import (
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// Goroutines hammer an open circuit across the end of its Timeout while the
+// probe holds HalfOpen. Once the deadline has passed, nobody may still be
+// rejected with ErrOpenState from a stale cached deadline.
+func TestOpenDeadline_NoStaleRejectionsAtExpiry(t *testing.T) {
+	const timeout = 30 * time.Millisecond
+	const slack = 50 * time.Millisecond
+
+	cb := New(tripOnFirstFailure(Settings{Name: "open-deadline-race", Timeout: timeout}))
+	cb.Execute(failFunc)
+	deadline := time.Now().Add(timeout)
+
+	stop := make(chan struct{})
+	var lastOpenRejection atomic.Int64
+	var probed atomic.Bool
+	var wg sync.WaitGroup
+	for w := 0; w < 8; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				select {
+				case <-stop:
+					return
+				default:
+				}
+				// Stamp the call on entry: a rejection decided before the
+				// deadline may return late when the goroutine is descheduled
+				started := time.Now().UnixNano()
+				_, err := cb.Execute(func() (interface{}, error) {
+					probed.Store(true)
+					<-stop // Hold HalfOpen until the test ends
+					return "ok", nil
+				})
+				if errors.Is(err, ErrOpenState) {
+					lastOpenRejection.Store(started)
+				}
+			}
+		}()
+	}
+
+	time.Sleep(timeout + 3*slack)
+	close(stop)
+	wg.Wait()
+
+	if !probed.Load() {
+		t.Fatal("Expected a probe to be admitted after the deadline")
+	}
+	if last := time.Unix(0, lastOpenRejection.Load()); last.After(deadline.Add(slack)) {
+		t.Errorf("Expected no ErrOpenState for calls made after the deadline, got one %v after it", last.Sub(deadline))
+	}
+}
+
+func TestOpenDeadline_InvalidatedByTimeoutUpdate(t *testing.T) {
+	cb := New(tripOnFirstFailure(Settings{Name: "open-deadline-update", Timeout: time.Hour}))
+	cb.Execute(failFunc)
+
+	// Caches a deadline an hour out
+	if _, err := cb.Execute(successFunc); !errors.Is(err, ErrOpenState) {
+		t.Fatalf("Expected ErrOpenState, got %v", err)
+	}
+
+	if err := cb.UpdateSettings(SettingsUpdate{Timeout: DurationPtr(10 * time.Millisecond)}); err != nil {
+		t.Fatalf("UpdateSettings: %v", err)
+	}
+	time.Sleep(20 * time.Millisecond)
+
+	if _, err := cb.Execute(successFunc); err != nil {
+		t.Errorf("Expected the shorter Timeout to apply despite the cached deadline, got %v", err)
+	}
+}
+
+func TestOpenDeadline_InvalidatedOnReopen(t *testing.T) {
+	cb := New(tripOnFirstFailure(Settings{Name: "open-deadline-reopen", Timeout: 20 * time.Millisecond}))
+	cb.Execute(failFunc)
+	cb.Execute(successFunc) // Caches the first open period's deadline
+	time.Sleep(30 * time.Millisecond)
+
+	cb.Execute(failFunc) // Failed probe reopens
+	if cb.State() != StateOpen {
+		t.Fatalf("Expected Open after the failed probe, got %v", cb.State())
+	}
+	if _, err := cb.Execute(successFunc); !errors.Is(err, ErrOpenState) {
+		t.Errorf("Expected the new open period enforced, got %v", err)
+	}
+
+	time.Sleep(30 * time.Millisecond)
+	if _, err := cb.Execute(successFunc); err != nil {
+		t.Errorf("Expected a probe after the new open period, got %v", err)
+	}
+}
+
+func TestOpenDeadline_StaleGenerationWithdrawn(t *testing.T) {
+	cb := New(Settings{Name: "open-deadline-gen"})
+
+	gen := cb.openDeadlineGen.Load()
+	cb.invalidateOpenDeadline() // A transition lands while inputs are read
+	cb.cacheOpenDeadline(gen, time.Hour)
+
+	if cb.openDeadlineCached() {
+		t.Error("Expected a deadline computed before an invalidation to be withdrawn")
+	}
+}
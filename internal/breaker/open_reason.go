@@ -0,0 +1,160 @@
+package breaker
+
+import "fmt"
+
+// OpenReasonKind identifies why the circuit entered the Open state.
+type OpenReasonKind int32
+
+const (
+	// OpenReasonNone indicates the circuit is not open (or recovering).
+	OpenReasonNone OpenReasonKind = iota
+
+	// OpenReasonFailureRate indicates the adaptive failure rate threshold was exceeded.
+	OpenReasonFailureRate
+
+	// OpenReasonConsecutiveFailures indicates the default static threshold
+	// (ConsecutiveFailures > 5) was exceeded.
+	OpenReasonConsecutiveFailures
+
+	// OpenReasonReadyToTrip indicates a custom ReadyToTrip or ReadyToTripEx
+	// callback returned true.
+	OpenReasonReadyToTrip
+
+	// OpenReasonProbeFailed indicates a half-open probe request failed and the
+	// circuit went back to Open.
+	OpenReasonProbeFailed
+
+	// OpenReasonCycleLimit indicates MaxRequestsPerCycle requests were admitted
+	// during the closed period.
+	OpenReasonCycleLimit
+
+	// OpenReasonEngine indicates a custom Settings.Engine tripped the circuit.
+	OpenReasonEngine
+
+	// OpenReasonMirrored indicates the circuit followed the breaker it mirrors
+	// (see MirrorFrom) into Open.
+	OpenReasonMirrored
+)
+
+// String returns the string representation of the reason kind.
+func (k OpenReasonKind) String() string {
+	switch k {
+	case OpenReasonNone:
+		return "none"
+	case OpenReasonFailureRate:
+		return "failure-rate"
+	case OpenReasonConsecutiveFailures:
+		return "consecutive-failures"
+	case OpenReasonReadyToTrip:
+		return "ready-to-trip"
+	case OpenReasonProbeFailed:
+		return "probe-failed"
+	case OpenReasonCycleLimit:
+		return "cycle-limit"
+	case OpenReasonEngine:
+		return "engine"
+	case OpenReasonMirrored:
+		return "mirrored"
+	default:
+		return stateUnknownStr
+	}
+}
+
+// OpenReason describes why the circuit is currently open.
+//
+// It is recorded on every transition into Open, persists while the circuit is
+// Open, carries over into HalfOpen (with Recovering set), and is cleared when
+// the circuit closes.
+type OpenReason struct {
+	// Kind identifies the path that opened the circuit.
+	Kind OpenReasonKind
+
+	// Detail is a human-readable explanation captured at the moment the circuit
+	// opened, e.g. "failure rate 18.00% (9/50) exceeded threshold 10.00%".
+	Detail string
+
+	// Recovering is true while the circuit is HalfOpen, probing for recovery
+	// from the recorded reason.
+	Recovering bool
+
+	// Counts are the counts at the moment the circuit opened. Live counts are
+	// cleared on the transition, so this is the only record of the window that
+	// caused the trip.
+	Counts Counts
+}
+
+// String returns a human-readable description of the reason.
+//
+// Returns "" when no reason is recorded, and prefixes "recovering from: "
+// while the circuit is HalfOpen.
+func (r OpenReason) String() string {
+	if r.Kind == OpenReasonNone {
+		return ""
+	}
+
+	desc := r.Detail
+	if desc == "" {
+		desc = r.Kind.String()
+	}
+	if r.Recovering {
+		return "recovering from: " + desc
+	}
+	return desc
+}
+
+// tripReason builds the OpenReason for a Closed → Open trip from the counts
+// that caused it. Detail is left empty where it follows from Kind and Counts
+// alone; withDetail formats it when the reason is read, so a trip does not pay
+// for a string nobody asks for.
+func (cb *CircuitBreaker) tripReason(counts Counts) *OpenReason {
+	switch {
+	case cb.customReadyToTrip:
+		return &OpenReason{Kind: OpenReasonReadyToTrip, Counts: counts}
+	case cb.adaptiveThreshold:
+		// Formatted now: the threshold may be updated while the circuit is Open
+		rate := cb.failureRate(counts)
+		return &OpenReason{
+			Kind: OpenReasonFailureRate,
+			Detail: fmt.Sprintf("failure rate %.2f%% (%d/%d) exceeded threshold %s",
+				rate*100, counts.TotalFailures, counts.Requests, cb.tripComparison().describe(cb)),
+			Counts: counts,
+		}
+	default:
+		return &OpenReason{Kind: OpenReasonConsecutiveFailures, Counts: counts}
+	}
+}
+
+// withDetail returns r with the Detail tripReason left for later filled in.
+func (cb *CircuitBreaker) withDetail(r OpenReason) OpenReason {
+	if r.Detail != "" {
+		return r
+	}
+	switch r.Kind {
+	case OpenReasonReadyToTrip:
+		// The callback name is in the format, so only the counts are boxed
+		format := "ReadyToTrip returned true (%d/%d failed, %d consecutive)"
+		if cb.readyToTripEx != nil {
+			format = "ReadyToTripEx returned true (%d/%d failed, %d consecutive)"
+		}
+		r.Detail = fmt.Sprintf(format, r.Counts.TotalFailures, r.Counts.Requests, r.Counts.ConsecutiveFailures)
+	case OpenReasonConsecutiveFailures:
+		r.Detail = fmt.Sprintf("%d consecutive failures", r.Counts.ConsecutiveFailures)
+	}
+	return r
+}
+
+// currentOpenReason returns the recorded open reason adjusted for the given state.
+func (cb *CircuitBreaker) currentOpenReason(state State) OpenReason {
+	if state == StateClosed {
+		return OpenReason{}
+	}
+
+	reason := cb.openReason.Load()
+	if reason == nil {
+		return OpenReason{}
+	}
+
+	r := cb.withDetail(*reason)
+	r.Recovering = state == StateHalfOpen
+	return r
+}
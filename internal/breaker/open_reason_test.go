@@ -0,0 +1,147 @@
+package breaker
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestOpenReason_ConsecutiveFailures(t *testing.T) {
+	cb := New(Settings{Name: "test", Timeout: time.Minute})
+
+	for i := 0; i < 6; i++ {
+		cb.Execute(failFunc)
+	}
+
+	reason := cb.Diagnostics().OpenReason
+	if reason.Kind != OpenReasonConsecutiveFailures {
+		t.Fatalf("Expected consecutive-failures reason, got %v", reason.Kind)
+	}
+	if reason.String() != "6 consecutive failures" {
+		t.Errorf("Unexpected detail: %q", reason.String())
+	}
+}
+
+func TestOpenReason_FailureRate(t *testing.T) {
+	cb := New(Settings{
+		Name:                 "test",
+		Timeout:              time.Minute,
+		AdaptiveThreshold:    true,
+		FailureRateThreshold: 0.10,
+		MinimumObservations:  10,
+	})
+
+	for i := 0; i < 8; i++ {
+		cb.Execute(successFunc)
+	}
+	cb.Execute(failFunc)
+	cb.Execute(failFunc) // 2/10 = 20%
+
+	reason := cb.Diagnostics().OpenReason
+	if reason.Kind != OpenReasonFailureRate {
+		t.Fatalf("Expected failure-rate reason, got %v", reason.Kind)
+	}
+	want := "failure rate 20.00% (2/10) exceeded threshold 10.00%"
+	if reason.Detail != want {
+		t.Errorf("Expected detail %q, got %q", want, reason.Detail)
+	}
+}
+
+func TestOpenReason_CustomReadyToTrip(t *testing.T) {
+	cb := New(Settings{
+		Name:        "test",
+		Timeout:     time.Minute,
+		ReadyToTrip: func(c Counts) bool { return c.TotalFailures >= 2 },
+	})
+
+	cb.Execute(failFunc)
+	cb.Execute(failFunc)
+
+	reason := cb.Diagnostics().OpenReason
+	if reason.Kind != OpenReasonReadyToTrip {
+		t.Fatalf("Expected ready-to-trip reason, got %v", reason.Kind)
+	}
+	if !strings.Contains(reason.Detail, "2/2 failed") {
+		t.Errorf("Expected counts in detail, got %q", reason.Detail)
+	}
+}
+
+func TestOpenReason_LifecycleThroughHalfOpen(t *testing.T) {
+	cb := New(Settings{
+		Name:        "test",
+		Timeout:     20 * time.Millisecond,
+		ReadyToTrip: func(c Counts) bool { return c.ConsecutiveFailures >= 1 },
+	})
+
+	cb.Execute(failFunc)
+	tripDetail := cb.Diagnostics().OpenReason.Detail
+
+	// Probe fails: distinct reason from the threshold trip
+	time.Sleep(30 * time.Millisecond)
+	cb.Execute(failFunc)
+	reason := cb.Diagnostics().OpenReason
+	if cb.State() != StateOpen || reason.Kind != OpenReasonProbeFailed {
+		t.Fatalf("Expected probe-failed reason in Open, got state=%v kind=%v", cb.State(), reason.Kind)
+	}
+	if reason.Detail == tripDetail {
+		t.Error("Probe failure should record a distinct detail from the threshold trip")
+	}
+
+	// Carries over into HalfOpen as "recovering from"
+	time.Sleep(30 * time.Millisecond)
+	block := make(chan struct{})
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		cb.Execute(func() (interface{}, error) {
+			<-block
+			return nil, nil
+		})
+	}()
+	requireState(t, cb, StateHalfOpen, time.Second)
+
+	reason = cb.Diagnostics().OpenReason
+	if !reason.Recovering || reason.Kind != OpenReasonProbeFailed {
+		t.Errorf("Expected recovering probe-failed reason in HalfOpen, got %+v", reason)
+	}
+	if reason.String() != "recovering from: half-open probe failed" {
+		t.Errorf("Unexpected half-open reason string: %q", reason.String())
+	}
+
+	// Cleared on Closed
+	close(block)
+	<-done
+	if cb.State() != StateClosed {
+		t.Fatalf("Expected closed after successful probe, got %v", cb.State())
+	}
+	if reason := cb.Diagnostics().OpenReason; reason.Kind != OpenReasonNone || reason.String() != "" {
+		t.Errorf("Expected reason cleared after close, got %+v", reason)
+	}
+}
+
+func TestOpenReason_PreservedByTimerReset(t *testing.T) {
+	cb := New(Settings{Name: "test", Timeout: time.Minute})
+
+	for i := 0; i < 6; i++ {
+		cb.Execute(failFunc)
+	}
+	before := cb.Diagnostics().OpenReason
+
+	if err := cb.UpdateSettings(SettingsUpdate{Timeout: DurationPtr(2 * time.Minute)}); err != nil {
+		t.Fatalf("UpdateSettings failed: %v", err)
+	}
+
+	if after := cb.Diagnostics().OpenReason; after != before {
+		t.Errorf("Timer reset should not change open reason: before=%+v after=%+v", before, after)
+	}
+}
+
+func TestOpenReason_ClosedIsEmpty(t *testing.T) {
+	cb := New(Settings{Name: "test"})
+	if reason := cb.Diagnostics().OpenReason; reason.Kind != OpenReasonNone {
+		t.Errorf("Expected no reason on a fresh breaker, got %+v", reason)
+	}
+	if OpenReasonKind(99).String() != "unknown" {
+		t.Error("Expected unknown for invalid reason kind")
+	}
+}
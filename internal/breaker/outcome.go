@@ -0,0 +1,47 @@
+package breaker
+
+import "errors"
+
+// stripIgnoreOutcome removes ErrIgnoreOutcome from err so the caller receives
+// only the underlying error.
+//
+//   - err == ErrIgnoreOutcome: returns nil (nothing else to report)
+//   - errors.Join(ErrIgnoreOutcome, realErr): returns realErr
+//   - Any other wrapping (e.g. fmt.Errorf with %w): returned unchanged
+func stripIgnoreOutcome(err error) error {
+	if err == ErrIgnoreOutcome {
+		return nil
+	}
+
+	joined, ok := err.(interface{ Unwrap() []error })
+	if !ok {
+		return err
+	}
+
+	var remaining []error
+	for _, e := range joined.Unwrap() {
+		if e != ErrIgnoreOutcome {
+			remaining = append(remaining, e)
+		}
+	}
+
+	switch len(remaining) {
+	case 0:
+		return nil
+	case 1:
+		return remaining[0]
+	default:
+		return errors.Join(remaining...)
+	}
+}
+
+// isNestedRejection reports whether err is a rejection from a circuit breaker
+// (typically an inner breaker called from within this one's request function)
+// rather than an error from the backend itself.
+func isNestedRejection(err error) bool {
+	return errors.Is(err, ErrOpenState) ||
+		errors.Is(err, ErrTooManyRequests) ||
+		errors.Is(err, ErrTooManyConcurrent) ||
+		errors.Is(err, ErrDependencyOpen) ||
+		errors.Is(err, ErrDeadlineTooShort)
+}
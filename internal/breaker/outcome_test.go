@@ -0,0 +1,130 @@
+package breaker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+)
+
+var errValidation = errors.New("validation failed")
+
+func ignoredFunc() (interface{}, error) {
+	return nil, errors.Join(ErrIgnoreOutcome, errValidation)
+}
+
+func TestIgnoreOutcome_DoesNotAffectCounts(t *testing.T) {
+	cb := New(Settings{Name: "test"})
+
+	cb.Execute(successFunc)
+	cb.Execute(failFunc)
+	before := cb.Counts()
+
+	for i := 0; i < 10; i++ {
+		_, err := cb.Execute(ignoredFunc)
+		if !errors.Is(err, errValidation) {
+			t.Fatalf("Expected underlying error, got %v", err)
+		}
+		if errors.Is(err, ErrIgnoreOutcome) {
+			t.Fatal("Sentinel should be stripped from the returned error")
+		}
+	}
+
+	if after := cb.Counts(); after != before {
+		t.Errorf("Ignored outcomes changed counts: before=%+v after=%+v", before, after)
+	}
+	if rate := cb.Metrics().FailureRate; rate != 0.5 {
+		t.Errorf("Expected failure rate unchanged at 0.5, got %v", rate)
+	}
+}
+
+func TestIgnoreOutcome_DoesNotBreakConsecutiveStreak(t *testing.T) {
+	cb := New(Settings{Name: "test", Timeout: time.Minute})
+
+	// 5 failures interleaved with ignored outcomes still trip on the 6th failure
+	for i := 0; i < 5; i++ {
+		cb.Execute(failFunc)
+		cb.Execute(ignoredFunc)
+	}
+	if counts := cb.Counts(); counts.ConsecutiveFailures != 5 {
+		t.Fatalf("Expected 5 consecutive failures, got %d", counts.ConsecutiveFailures)
+	}
+	cb.Execute(failFunc)
+	if cb.State() != StateOpen {
+		t.Errorf("Expected circuit to trip, got %v", cb.State())
+	}
+}
+
+func TestIgnoreOutcome_HalfOpenProbeUndecided(t *testing.T) {
+	cb := New(Settings{
+		Name:        "test",
+		Timeout:     10 * time.Millisecond,
+		MaxRequests: 1,
+		ReadyToTrip: func(c Counts) bool { return c.ConsecutiveFailures >= 1 },
+	})
+
+	cb.Execute(failFunc)
+	time.Sleep(20 * time.Millisecond)
+
+	// Ignored probe: stays half-open and releases the slot
+	result, err := cb.Execute(func() (interface{}, error) {
+		return "cached", ErrIgnoreOutcome
+	})
+	if err != nil || result != "cached" {
+		t.Fatalf("Expected (cached, nil), got (%v, %v)", result, err)
+	}
+	if cb.State() != StateHalfOpen {
+		t.Fatalf("Ignored probe should not decide the probe, got %v", cb.State())
+	}
+	if n := cb.halfOpenRequests.Load(); n != 0 {
+		t.Errorf("Expected half-open slot released, got %d in flight", n)
+	}
+
+	// The next real probe decides
+	cb.Execute(successFunc)
+	if cb.State() != StateClosed {
+		t.Errorf("Expected real probe to close circuit, got %v", cb.State())
+	}
+}
+
+func TestIgnoreOutcome_ExecuteContext(t *testing.T) {
+	cb := New(Settings{Name: "test"})
+
+	_, err := cb.ExecuteContext(context.Background(), ignoredFunc)
+	if !errors.Is(err, errValidation) {
+		t.Fatalf("Expected underlying error, got %v", err)
+	}
+	if counts := cb.Counts(); counts.Requests != 0 {
+		t.Errorf("Expected no counted requests, got %+v", counts)
+	}
+}
+
+func TestStripIgnoreOutcome(t *testing.T) {
+	other := errors.New("other")
+	wrapped := fmt.Errorf("wrapped: %w", ErrIgnoreOutcome)
+
+	tests := []struct {
+		name string
+		in   error
+		want error
+	}{
+		{"sentinel alone", ErrIgnoreOutcome, nil},
+		{"joined with one", errors.Join(ErrIgnoreOutcome, errValidation), errValidation},
+		{"joined alone", errors.Join(ErrIgnoreOutcome), nil},
+		{"fmt wrapped", wrapped, wrapped},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := stripIgnoreOutcome(tt.in); got != tt.want {
+				t.Errorf("stripIgnoreOutcome(%v) = %v, want %v", tt.in, got, tt.want)
+			}
+		})
+	}
+
+	// Joined with several: both remain
+	got := stripIgnoreOutcome(errors.Join(ErrIgnoreOutcome, errValidation, other))
+	if !errors.Is(got, errValidation) || !errors.Is(got, other) || errors.Is(got, ErrIgnoreOutcome) {
+		t.Errorf("Expected both underlying errors without sentinel, got %v", got)
+	}
+}
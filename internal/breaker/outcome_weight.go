@@ -0,0 +1,117 @@
+package breaker
+
+import "math"
+
+// outcomeWeightFailureCutoff is the failure weight above which a weighted call
+// counts as a whole failure for the integer counters (TotalFailures, the
+// consecutive counters) and half-open decisions.
+const outcomeWeightFailureCutoff = 0.5
+
+// failureWeightOf classifies a completed call as a failure weight in [0, 1].
+// Returns ok=false if the outcome should not be recorded.
+//
+// Without OutcomeWeight, IsSuccessful decides and the weight is 0 or 1.
+// If the classifier panics, ClassifierPanicOutcome decides.
+func (cb *CircuitBreaker) failureWeightOf(result interface{}, err error) (weight float64, ok bool) {
+	var panicked bool
+	if cb.outcomeWeight != nil {
+		weight, panicked = safeCallOutcomeWeight(cb.name, cb.outcomeWeight, result, err)
+	} else {
+		var success bool
+		success, panicked = cb.classify(err)
+		weight = overrideWeight(success)
+	}
+	if !panicked {
+		return weight, true
+	}
+
+	cb.eventCounter().classifierPanics.Add(1)
+	switch cb.classifierPanicOutcome {
+	case ClassifierPanicFailure:
+		return 1, true
+	case ClassifierPanicSuccess:
+		return 0, true
+	case ClassifierPanicIgnore:
+		return 0, false
+	default:
+		return overrideWeight(DefaultIsSuccessful(err)), true
+	}
+}
+
+// recordWeightedOutcome records a call with the given failure weight and
+// reports whether it counts as a success for state transitions.
+func (cb *CircuitBreaker) recordWeightedOutcome(weight float64) bool {
+	success := weight <= outcomeWeightFailureCutoff
+	if cb.outcomeWeight == nil {
+		cb.recordOutcome(success)
+		return success
+	}
+
+	cb.countOutcome(success)
+	cb.addFailureWeight(weight)
+	if cb.healthScoreAlpha > 0 {
+		cb.updateHealthScore(1 - weight)
+	}
+	return success
+}
+
+// settleOutcome handles the state transitions for a call recorded with
+// recordWeightedOutcome.
+func (cb *CircuitBreaker) settleOutcome(success bool, weight float64, currentState State) {
+	// A partial failure at or below the cutoff still raises the weighted rate,
+	// so it is evaluated against ReadyToTrip like a failure
+	if success && weight > 0 && currentState == StateClosed {
+		cb.checkAndTripCircuit()
+	}
+
+	cb.handleStateTransition(success, currentState)
+}
+
+// clampFailureWeight bounds an OutcomeWeight result to [0, 1].
+// NaN is treated as a full failure.
+func clampFailureWeight(weight float64) float64 {
+	switch {
+	case math.IsNaN(weight), weight > 1:
+		return 1
+	case weight < 0:
+		return 0
+	default:
+		return weight
+	}
+}
+
+// addFailureWeight atomically adds weight to the accumulated failure weight.
+func (cb *CircuitBreaker) addFailureWeight(weight float64) {
+	if weight == 0 {
+		return
+	}
+	for {
+		old := cb.failureWeight.Load()
+		sum := math.Float64bits(math.Float64frombits(old) + weight)
+		if cb.failureWeight.CompareAndSwap(old, sum) {
+			return
+		}
+	}
+}
+
+func (cb *CircuitBreaker) getFailureWeight() float64 {
+	return math.Float64frombits(cb.failureWeight.Load())
+}
+
+// failureRate returns the failure rate of counts, using FailureWeight when
+// OutcomeWeight is set. Returns 0 if there are no requests.
+func (cb *CircuitBreaker) failureRate(counts Counts) float64 {
+	return failureRateOf(counts, cb.outcomeWeight != nil)
+}
+
+// failureRateOf returns FailureWeight / Requests when weighted, otherwise
+// TotalFailures / Requests. Returns 0 if there are no requests.
+func failureRateOf(counts Counts, weighted bool) float64 {
+	if counts.Requests == 0 {
+		return 0
+	}
+	if weighted {
+		return counts.FailureWeight / float64(counts.Requests)
+	}
+	return float64(counts.TotalFailures) / float64(counts.Requests)
+}
@@ -0,0 +1,198 @@
+package breaker
+
+import (
+	"errors"
+	"math"
+	"testing"
+	"time"
+)
+
+// batchResult is a batch-style partial result: some items may fail.
+type batchResult struct {
+	Total  int
+	Failed int
+}
+
+var errPartialBatch = errors.New("partial batch failure")
+
+// batchWeight weighs a call by the fraction of failed batch items.
+func batchWeight(result interface{}, err error) float64 {
+	batch, ok := result.(*batchResult)
+	if !ok || batch.Total == 0 {
+		if err != nil {
+			return 1
+		}
+		return 0
+	}
+	return float64(batch.Failed) / float64(batch.Total)
+}
+
+// batchCall returns a request function for a batch of 10 items with failed failures.
+func batchCall(failed int) func() (interface{}, error) {
+	return func() (interface{}, error) {
+		result := &batchResult{Total: 10, Failed: failed}
+		if failed > 0 {
+			return result, errPartialBatch
+		}
+		return result, nil
+	}
+}
+
+func TestOutcomeWeight_FractionalAccumulation(t *testing.T) {
+	cb := New(Settings{Name: "batch", OutcomeWeight: batchWeight})
+
+	cb.Execute(batchCall(3)) // 0.3
+	cb.Execute(batchCall(7)) // 0.7
+	cb.Execute(batchCall(0)) // 0.0
+
+	counts := cb.Counts()
+	if counts.Requests != 3 {
+		t.Fatalf("Expected 3 requests, got %d", counts.Requests)
+	}
+	if math.Abs(counts.FailureWeight-1.0) > 1e-9 {
+		t.Errorf("Expected FailureWeight 1.0, got %v", counts.FailureWeight)
+	}
+
+	// Only the 7/10 batch crosses the 0.5 cutoff for the integer counters
+	if counts.TotalFailures != 1 || counts.TotalSuccesses != 2 {
+		t.Errorf("Expected 1 failure and 2 successes, got %+v", counts)
+	}
+
+	metrics := cb.Metrics()
+	if math.Abs(metrics.FailureRate-1.0/3) > 1e-9 {
+		t.Errorf("Expected weighted failure rate 1/3, got %v", metrics.FailureRate)
+	}
+	if math.Abs(metrics.SuccessRate-2.0/3) > 1e-9 {
+		t.Errorf("Expected weighted success rate 2/3, got %v", metrics.SuccessRate)
+	}
+}
+
+func TestOutcomeWeight_ConsecutiveCutoff(t *testing.T) {
+	cb := New(Settings{Name: "batch", Timeout: time.Minute, OutcomeWeight: batchWeight})
+
+	// Exactly half the batch failing is not a failure for the consecutive counters
+	for i := 0; i < 10; i++ {
+		cb.Execute(batchCall(5))
+	}
+	if counts := cb.Counts(); counts.ConsecutiveFailures != 0 || counts.ConsecutiveSuccesses != 10 {
+		t.Fatalf("Expected weight 0.5 to count as success, got %+v", cb.Counts())
+	}
+
+	// More than half failing is a failure; six in a row trip the default ReadyToTrip
+	for i := 0; i < 6; i++ {
+		cb.Execute(batchCall(6))
+	}
+	if cb.State() != StateOpen {
+		t.Errorf("Expected six >50%% batch failures to trip, got %v", cb.State())
+	}
+}
+
+func TestOutcomeWeight_AdaptiveTripsOnWeightedRate(t *testing.T) {
+	cb := New(Settings{
+		Name:                 "batch",
+		Timeout:              time.Minute,
+		AdaptiveThreshold:    true,
+		FailureRateThreshold: 0.25,
+		MinimumObservations:  10,
+		OutcomeWeight:        batchWeight,
+	})
+
+	// Every batch has 3/10 failures: no call is a whole failure, but the
+	// weighted rate (30%) is above the 25% threshold.
+	for i := 0; i < 9; i++ {
+		cb.Execute(batchCall(3))
+	}
+	if cb.State() != StateClosed {
+		t.Fatalf("Expected closed below MinimumObservations, got %v", cb.State())
+	}
+	if cb.Counts().TotalFailures != 0 {
+		t.Fatalf("Expected no whole failures, got %d", cb.Counts().TotalFailures)
+	}
+
+	cb.Execute(batchCall(3))
+	if cb.State() != StateOpen {
+		t.Fatalf("Expected weighted rate 30%% to trip at 25%%, got %v", cb.State())
+	}
+	if reason := cb.Diagnostics().OpenReason; reason.Detail != "failure rate 30.00% (0/10) exceeded threshold 25.00%" {
+		t.Errorf("Unexpected open reason: %q", reason.Detail)
+	}
+}
+
+func TestOutcomeWeight_ResultPassedThrough(t *testing.T) {
+	cb := New(Settings{Name: "batch", OutcomeWeight: batchWeight})
+
+	result, err := cb.Execute(batchCall(7))
+	if !errors.Is(err, errPartialBatch) {
+		t.Errorf("Expected partial batch error, got %v", err)
+	}
+	batch, ok := result.(*batchResult)
+	if !ok || batch.Failed != 7 {
+		t.Errorf("Expected partial result passed through, got %#v", result)
+	}
+}
+
+func TestOutcomeWeight_ClampAndPanic(t *testing.T) {
+	tests := []struct {
+		name   string
+		weight func(interface{}, error) float64
+		want   float64
+	}{
+		{"above one", func(interface{}, error) float64 { return 3 }, 1},
+		{"negative", func(interface{}, error) float64 { return -1 }, 0},
+		{"NaN", func(interface{}, error) float64 { return math.NaN() }, 1},
+		{"panic", func(interface{}, error) float64 { panic("classifier bug") }, 0}, // ClassifierPanicDefault: err == nil
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cb := New(Settings{Name: "batch", OutcomeWeight: tt.weight})
+			cb.Execute(successFunc)
+
+			if got := cb.Counts().FailureWeight; got != tt.want {
+				t.Errorf("Expected FailureWeight %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestOutcomeWeight_PanicAndRecordWeighOne(t *testing.T) {
+	cb := New(Settings{Name: "batch", OutcomeWeight: batchWeight})
+
+	func() {
+		defer func() { _ = recover() }()
+		cb.Execute(panicFunc)
+	}()
+	cb.Record(false)
+	cb.Record(true)
+
+	counts := cb.Counts()
+	if counts.FailureWeight != 2 {
+		t.Errorf("Expected FailureWeight 2 (panic + Record(false)), got %v", counts.FailureWeight)
+	}
+	if counts.TotalFailures != 2 || counts.TotalSuccesses != 1 {
+		t.Errorf("Unexpected counts: %+v", counts)
+	}
+}
+
+func TestOutcomeWeight_ClearedWithCounts(t *testing.T) {
+	cb := New(Settings{Name: "batch", OutcomeWeight: batchWeight})
+
+	cb.Execute(batchCall(4))
+	if err := cb.UpdateSettings(SettingsUpdate{Interval: DurationPtr(time.Minute)}); err != nil {
+		t.Fatalf("UpdateSettings failed: %v", err)
+	}
+
+	if got := cb.Counts().FailureWeight; got != 0 {
+		t.Errorf("Expected FailureWeight reset with counts, got %v", got)
+	}
+}
+
+func TestOutcomeWeight_UnsetLeavesFailureWeightZero(t *testing.T) {
+	cb := New(Settings{Name: "plain"})
+
+	cb.Execute(failFunc)
+
+	if got := cb.Counts().FailureWeight; got != 0 {
+		t.Errorf("Expected FailureWeight 0 without OutcomeWeight, got %v", got)
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"math"
 	"sync"
 	"sync/atomic"
+	"time"
 )
 
 // callbackPanicHandler handles panics in user callbacks with proper logging and metrics.
@@ -42,8 +43,29 @@ func (h *callbackPanicHandler) handleOnStateChangePanic(name string, from, to St
 	// This prevents a panicking callback from blocking state transitions
 }
 
+// handleOnStateChangeDetailedPanic handles a panic in the OnStateChangeDetailed
+// callback. Logs the panic but allows the state transition to proceed.
+func (h *callbackPanicHandler) handleOnStateChangeDetailedPanic(name string, from, to State, r interface{}) {
+	logMutex.Lock()
+	defer logMutex.Unlock()
+
+	fmt.Printf("[AUTOBREAKER WARNING] Circuit %q: OnStateChangeDetailed callback panicked during transition %v → %v: %v\n",
+		name, from, to, r)
+}
+
+// handleShadowReadyToTripPanic handles a panic in the ShadowReadyToTrip callback.
+// The shadow decision defaults to "do not trip"; live state is unaffected.
+func (h *callbackPanicHandler) handleShadowReadyToTripPanic(name string, r interface{}) {
+	logMutex.Lock()
+	defer logMutex.Unlock()
+
+	fmt.Printf("[AUTOBREAKER WARNING] Circuit %q: ShadowReadyToTrip callback panicked: %v\n",
+		name, r)
+}
+
 // handleIsSuccessfulPanic handles a panic in the IsSuccessful callback.
-// Returns a safe default: treat as failure (conservative approach).
+// Returns failure; Settings.ClassifierPanicOutcome decides how the call is
+// actually recorded.
 func (h *callbackPanicHandler) handleIsSuccessfulPanic(name string, r interface{}) bool {
 	// Log the panic with stack trace
 	logMutex.Lock()
@@ -52,11 +74,39 @@ func (h *callbackPanicHandler) handleIsSuccessfulPanic(name string, r interface{
 	fmt.Printf("[AUTOBREAKER WARNING] Circuit %q: IsSuccessful callback panicked: %v\n",
 		name, r)
 
-	// Safe default: treat as failure
-	// This is conservative - better to potentially trip circuit than ignore errors
 	return false
 }
 
+// logClassifierLatched logs that IsSuccessful is no longer called after
+// panicking on streak consecutive calls (see Settings.ClassifierPanicLatch).
+func logClassifierLatched(name string, streak uint32) {
+	logMutex.Lock()
+	defer logMutex.Unlock()
+
+	fmt.Printf("[AUTOBREAKER WARNING] Circuit %q: IsSuccessful panicked on %d consecutive calls; "+
+		"using the default classifier until it is replaced\n", name, streak)
+}
+
+// handleOnDegradedPanic handles a panic in the OnDegraded callback.
+// Logs the panic; the degraded flag remains set.
+func (h *callbackPanicHandler) handleOnDegradedPanic(name string, rate float64, r interface{}) {
+	logMutex.Lock()
+	defer logMutex.Unlock()
+
+	fmt.Printf("[AUTOBREAKER WARNING] Circuit %q: OnDegraded callback panicked at failure rate %.4f: %v\n",
+		name, rate, r)
+}
+
+// handleOnWarningPanic handles a panic in the OnWarning callback.
+// Logs the panic; the warning latch remains set.
+func (h *callbackPanicHandler) handleOnWarningPanic(name string, rate float64, r interface{}) {
+	logMutex.Lock()
+	defer logMutex.Unlock()
+
+	fmt.Printf("[AUTOBREAKER WARNING] Circuit %q: OnWarning callback panicked at failure rate %.4f: %v\n",
+		name, rate, r)
+}
+
 // safeCallWithRecovery executes a callback with panic recovery and proper handling.
 // It provides deterministic behavior for each callback type.
 func safeCallWithRecovery(fn func(), panicHandler func(interface{})) {
@@ -90,6 +140,47 @@ func safeCallReadyToTrip(circuitName string, fn func(Counts) bool, counts Counts
 	return result
 }
 
+// safeCallShadowReadyToTrip executes ShadowReadyToTrip callback with panic recovery.
+func safeCallShadowReadyToTrip(circuitName string, fn func(Counts) bool, counts Counts) bool {
+	var result bool
+	handler := &callbackPanicHandler{}
+
+	safeCallWithRecovery(func() {
+		result = fn(counts)
+	}, func(r interface{}) {
+		handler.handleShadowReadyToTripPanic(circuitName, r)
+		result = false
+	})
+
+	return result
+}
+
+// handleProbeGatePanic handles a panic in the ProbeGate callback.
+// The probe is allowed, so a faulty gate cannot hold the circuit open.
+func (h *callbackPanicHandler) handleProbeGatePanic(name string, r interface{}) {
+	logMutex.Lock()
+	defer logMutex.Unlock()
+
+	fmt.Printf("[AUTOBREAKER WARNING] Circuit %q: ProbeGate callback panicked: %v\n",
+		name, r)
+}
+
+// safeCallProbeGate executes ProbeGate callback with panic recovery.
+// Returns true (allow the probe) if the callback panics.
+func safeCallProbeGate(circuitName string, fn func() bool) bool {
+	var result bool
+	handler := &callbackPanicHandler{}
+
+	safeCallWithRecovery(func() {
+		result = fn()
+	}, func(r interface{}) {
+		handler.handleProbeGatePanic(circuitName, r)
+		result = true
+	})
+
+	return result
+}
+
 // safeCallOnStateChange executes OnStateChange callback with panic recovery.
 func safeCallOnStateChange(circuitName string, fn func(string, State, State), from, to State) {
 	if fn == nil {
@@ -105,25 +196,273 @@ func safeCallOnStateChange(circuitName string, fn func(string, State, State), fr
 	})
 }
 
+// safeCallOnStateChangeDetailed executes OnStateChangeDetailed callback with panic recovery.
+func safeCallOnStateChangeDetailed(circuitName string, fn func(string, State, State, Counts), from, to State, counts Counts) {
+	if fn == nil {
+		return
+	}
+
+	handler := &callbackPanicHandler{}
+
+	safeCallWithRecovery(func() {
+		fn(circuitName, from, to, counts)
+	}, func(r interface{}) {
+		handler.handleOnStateChangeDetailedPanic(circuitName, from, to, r)
+	})
+}
+
+// safeCallOnDegraded executes OnDegraded callback with panic recovery.
+func safeCallOnDegraded(circuitName string, fn func(string, float64), rate float64) {
+	if fn == nil {
+		return
+	}
+
+	handler := &callbackPanicHandler{}
+
+	safeCallWithRecovery(func() {
+		fn(circuitName, rate)
+	}, func(r interface{}) {
+		handler.handleOnDegradedPanic(circuitName, rate, r)
+	})
+}
+
+// safeCallOnWarning executes OnWarning callback with panic recovery.
+func safeCallOnWarning(circuitName string, fn func(string, float64, Counts), rate float64, counts Counts) {
+	if fn == nil {
+		return
+	}
+
+	handler := &callbackPanicHandler{}
+
+	safeCallWithRecovery(func() {
+		fn(circuitName, rate, counts)
+	}, func(r interface{}) {
+		handler.handleOnWarningPanic(circuitName, rate, r)
+	})
+}
+
+// handleOnStuckOpenPanic handles a panic in the OnStuckOpen callback.
+// Logs the panic; StuckOpenAction is still taken.
+func (h *callbackPanicHandler) handleOnStuckOpenPanic(name string, openFor time.Duration, r interface{}) {
+	logMutex.Lock()
+	defer logMutex.Unlock()
+
+	fmt.Printf("[AUTOBREAKER WARNING] Circuit %q: OnStuckOpen callback panicked after %v open: %v\n",
+		name, openFor, r)
+}
+
+// handleOnPanicThresholdPanic handles a panic in the OnPanicThreshold callback.
+// Logs the panic; the request's own panic is still recorded and re-raised.
+func (h *callbackPanicHandler) handleOnPanicThresholdPanic(name string, panics uint32, r interface{}) {
+	logMutex.Lock()
+	defer logMutex.Unlock()
+
+	fmt.Printf("[AUTOBREAKER WARNING] Circuit %q: OnPanicThreshold callback panicked at %d panics: %v\n",
+		name, panics, r)
+}
+
+// safeCallOnPanicThreshold executes OnPanicThreshold callback with panic recovery.
+func safeCallOnPanicThreshold(circuitName string, fn func(string, uint32), panics uint32) {
+	handler := &callbackPanicHandler{}
+
+	safeCallWithRecovery(func() {
+		fn(circuitName, panics)
+	}, func(r interface{}) {
+		handler.handleOnPanicThresholdPanic(circuitName, panics, r)
+	})
+}
+
+// safeCallOnStuckOpen executes OnStuckOpen callback with panic recovery.
+func safeCallOnStuckOpen(circuitName string, fn func(string, time.Duration), openFor time.Duration) {
+	if fn == nil {
+		return
+	}
+
+	handler := &callbackPanicHandler{}
+
+	safeCallWithRecovery(func() {
+		fn(circuitName, openFor)
+	}, func(r interface{}) {
+		handler.handleOnStuckOpenPanic(circuitName, openFor, r)
+	})
+}
+
+// handleOnDisabledChangePanic handles a panic in the OnDisabledChange callback.
+// Logs the panic; the mode change has already taken effect.
+func (h *callbackPanicHandler) handleOnDisabledChangePanic(name string, disabled bool, r interface{}) {
+	logMutex.Lock()
+	defer logMutex.Unlock()
+
+	fmt.Printf("[AUTOBREAKER WARNING] Circuit %q: OnDisabledChange callback panicked (disabled=%v): %v\n",
+		name, disabled, r)
+}
+
+// safeCallOnDisabledChange executes OnDisabledChange callback with panic recovery.
+func safeCallOnDisabledChange(circuitName string, fn func(string, bool), disabled bool) {
+	if fn == nil {
+		return
+	}
+
+	handler := &callbackPanicHandler{}
+
+	safeCallWithRecovery(func() {
+		fn(circuitName, disabled)
+	}, func(r interface{}) {
+		handler.handleOnDisabledChangePanic(circuitName, disabled, r)
+	})
+}
+
+// handleOnAutoTunePanic handles a panic in the OnAutoTune callback.
+// Logs the panic; the threshold change has already taken effect.
+func (h *callbackPanicHandler) handleOnAutoTunePanic(name string, r interface{}) {
+	logMutex.Lock()
+	defer logMutex.Unlock()
+
+	fmt.Printf("[AUTOBREAKER WARNING] Circuit %q: OnAutoTune callback panicked: %v\n", name, r)
+}
+
+// safeCallOnAutoTune executes OnAutoTune callback with panic recovery.
+func safeCallOnAutoTune(circuitName string, fn func(string, ChangeSet), changes ChangeSet) {
+	if fn == nil {
+		return
+	}
+
+	handler := &callbackPanicHandler{}
+
+	safeCallWithRecovery(func() {
+		fn(circuitName, changes)
+	}, func(r interface{}) {
+		handler.handleOnAutoTunePanic(circuitName, r)
+	})
+}
+
 // safeCallIsSuccessful executes IsSuccessful callback with panic recovery.
-// Returns false (failure) if callback panics.
-func safeCallIsSuccessful(circuitName string, fn func(error) bool, err error) bool {
-	var result bool
+// Returns false (failure) and panicked=true if callback panics.
+func safeCallIsSuccessful(circuitName string, fn func(error) bool, err error) (result, panicked bool) {
 	handler := &callbackPanicHandler{}
 
 	safeCallWithRecovery(func() {
 		result = fn(err)
 	}, func(r interface{}) {
 		result = handler.handleIsSuccessfulPanic(circuitName, r)
+		panicked = true
+	})
+
+	return result, panicked
+}
+
+// handleIsProbeSuccessfulPanic handles a panic in the IsProbeSuccessful callback.
+// Returns a safe default: the probe failed, so the circuit stays protected.
+func (h *callbackPanicHandler) handleIsProbeSuccessfulPanic(name string, r interface{}) bool {
+	logMutex.Lock()
+	defer logMutex.Unlock()
+
+	fmt.Printf("[AUTOBREAKER WARNING] Circuit %q: IsProbeSuccessful callback panicked: %v\n",
+		name, r)
+
+	return false
+}
+
+// safeCallIsProbeSuccessful executes IsProbeSuccessful callback with panic recovery.
+// Returns false (probe failed) if callback panics.
+func safeCallIsProbeSuccessful(circuitName string, fn func(interface{}, error, time.Duration) bool, result interface{}, err error, elapsed time.Duration) bool {
+	var ok bool
+	handler := &callbackPanicHandler{}
+
+	safeCallWithRecovery(func() {
+		ok = fn(result, err, elapsed)
+	}, func(r interface{}) {
+		ok = handler.handleIsProbeSuccessfulPanic(circuitName, r)
 	})
 
+	return ok
+}
+
+// handleOutcomeWeightPanic handles a panic in the OutcomeWeight callback.
+// Returns a safe default: treat as a full failure (conservative approach).
+func (h *callbackPanicHandler) handleOutcomeWeightPanic(name string, r interface{}) float64 {
+	logMutex.Lock()
+	defer logMutex.Unlock()
+
+	fmt.Printf("[AUTOBREAKER WARNING] Circuit %q: OutcomeWeight callback panicked: %v\n",
+		name, r)
+
+	return 1
+}
+
+// safeCallOutcomeWeight executes OutcomeWeight callback with panic recovery.
+// Returns 1 (full failure) and panicked=true if callback panics; other results
+// are clamped to [0, 1].
+func safeCallOutcomeWeight(circuitName string, fn func(interface{}, error) float64, result interface{}, err error) (weight float64, panicked bool) {
+	handler := &callbackPanicHandler{}
+
+	safeCallWithRecovery(func() {
+		weight = clampFailureWeight(fn(result, err))
+	}, func(r interface{}) {
+		weight = handler.handleOutcomeWeightPanic(circuitName, r)
+		panicked = true
+	})
+
+	return weight, panicked
+}
+
+// handleEnginePanic handles a panic in a DecisionEngine method. The caller
+// applies the safe default for the method.
+func (h *callbackPanicHandler) handleEnginePanic(name, method string, r interface{}) {
+	logMutex.Lock()
+	defer logMutex.Unlock()
+
+	fmt.Printf("[AUTOBREAKER WARNING] Circuit %q: DecisionEngine.%s panicked: %v\n",
+		name, method, r)
+}
+
+// safeCallEngine executes a DecisionEngine method with panic recovery.
+func safeCallEngine(circuitName, method string, fn func()) {
+	handler := &callbackPanicHandler{}
+
+	safeCallWithRecovery(fn, func(r interface{}) {
+		handler.handleEnginePanic(circuitName, method, r)
+	})
+}
+
+// safeCallEngineShouldTrip executes DecisionEngine.ShouldTrip with panic
+// recovery. Returns false (do not trip) if it panics.
+func safeCallEngineShouldTrip(circuitName string, engine DecisionEngine) bool {
+	var result bool
+	safeCallEngine(circuitName, "ShouldTrip", func() {
+		result = engine.ShouldTrip()
+	})
 	return result
 }
 
+// safeCallEngineShouldAdmit executes DecisionEngine.ShouldAdmit with panic
+// recovery. Returns true (admit) if it panics.
+func safeCallEngineShouldAdmit(circuitName string, engine DecisionEngine, state State, now time.Time) (admit bool, reason error) {
+	admit = true
+	safeCallEngine(circuitName, "ShouldAdmit", func() {
+		admit, reason = engine.ShouldAdmit(state, now)
+	})
+	if admit {
+		reason = nil
+	}
+	return admit, reason
+}
+
 // safeIncrementCounter safely increments a uint32 counter with saturation protection.
 // Returns true if the counter was incremented, false if it was already at max.
 // Logs a warning only once per saturation event (uses saturatedFlag to track).
+//
+// The uncontended, unsaturated case is a single CompareAndSwap, inlined into
+// the hot path; incrementCounterSlow handles the rest.
 func safeIncrementCounter(counter *atomic.Uint32, saturatedFlag *atomic.Bool, counterName, circuitName string) bool {
+	if current := counter.Load(); current != math.MaxUint32 && counter.CompareAndSwap(current, current+1) {
+		return true
+	}
+	return incrementCounterSlow(counter, saturatedFlag, counterName, circuitName)
+}
+
+// incrementCounterSlow implements safeIncrementCounter after a failed fast path.
+func incrementCounterSlow(counter *atomic.Uint32, saturatedFlag *atomic.Bool, counterName, circuitName string) bool {
 	// Use CompareAndSwap loop for atomic check-and-increment
 	for {
 		current := counter.Load()
@@ -148,6 +487,43 @@ func (cb *CircuitBreaker) safeIncrementRequests() bool {
 	return safeIncrementCounter(&cb.requests, &cb.requestsSaturated, "requests", cb.name)
 }
 
+// recordUnserved counts an execution attempt that is demand (Metrics.Demand)
+// but not in Requests: a rejection, or a request uncounted after admission.
+// Served attempts are demand through Requests alone, which keeps Demand off the
+// hot path.
+//
+// unserved is 64-bit, so it is counted with a single Add rather than the
+// compare-and-swap of the 32-bit counters; it saturates only as part of Demand.
+func (cb *CircuitBreaker) recordUnserved() {
+	if cb.unserved.Add(1) == math.MaxUint32+1 {
+		cb.unservedSaturates()
+	}
+}
+
+// unservedSaturates flags, and logs once, that Demand has saturated.
+func (cb *CircuitBreaker) unservedSaturates() {
+	if cb.unservedSaturated.CompareAndSwap(false, true) {
+		logCounterSaturation("unserved", cb.name, math.MaxUint32)
+	}
+}
+
+// demandOf returns Metrics.Demand for a window with the given Requests.
+func (cb *CircuitBreaker) demandOf(requests uint32) uint32 {
+	demand := uint64(requests) + uint64(cb.unserved.Load())
+	if demand > math.MaxUint32 {
+		return math.MaxUint32
+	}
+	return uint32(demand)
+}
+
+// uncountRequest removes an admitted request whose outcome is not recorded
+// from Requests. It stays demand.
+func (cb *CircuitBreaker) uncountRequest() {
+	if cb.safeDecrementRequests() {
+		cb.recordUnserved()
+	}
+}
+
 // safeDecrementRequests safely decrements the requests counter with underflow protection.
 // Returns true if the counter was decremented, false if it was already at 0.
 func (cb *CircuitBreaker) safeDecrementRequests() bool {
@@ -0,0 +1,18 @@
+package breaker
+
+// recordPanic counts a request whose function panicked with r in
+// Counts.Panics, journals it, and fires OnPanicThreshold the first time the
+// window's panics exceed PanicThreshold. Called after the panic's failure is
+// recorded and before any resulting transition clears the counts.
+func (cb *CircuitBreaker) recordPanic(r interface{}) {
+	safeIncrementCounter(&cb.panics, nil, "panics", cb.name)
+	cb.journalPanic(r)
+
+	if cb.onPanicThreshold == nil {
+		return
+	}
+	panics := cb.panics.Load()
+	if panics > cb.panicThreshold && cb.panicThresholdFired.CompareAndSwap(false, true) {
+		safeCallOnPanicThreshold(cb.name, cb.onPanicThreshold, panics)
+	}
+}
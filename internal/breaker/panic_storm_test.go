@@ -0,0 +1,111 @@
+package breaker
+
+import (
+	"testing"
+	"time"
+)
+
+func TestOnPanicThreshold_FiresOncePerWindow(t *testing.T) {
+	var fired []uint32
+	clk := newFakeClock()
+	cb := newWithClock(clk, Settings{
+		Name:           "panic-storm",
+		Interval:       time.Minute,
+		RecoverPanics:  true,
+		PanicThreshold: 2,
+		OnPanicThreshold: func(_ string, panics uint32) {
+			fired = append(fired, panics)
+		},
+	})
+
+	// Below the default trip rule (more than 5 consecutive failures)
+	for i := 0; i < 5; i++ {
+		cb.Execute(panicFunc)
+	}
+	if len(fired) != 1 || fired[0] != 3 {
+		t.Fatalf("Expected one callback at the 3rd panic, got %v", fired)
+	}
+	counts := cb.Counts()
+	if counts.Panics != 5 || counts.TotalFailures != 5 {
+		t.Errorf("Expected 5 panics counted as 5 failures, got %+v", counts)
+	}
+
+	// A new window resets the count and re-arms the callback
+	clk.advance(time.Minute)
+	cb.Execute(successFunc)
+	if got := cb.Counts().Panics; got != 0 {
+		t.Fatalf("Expected Panics reset with the window, got %d", got)
+	}
+	for i := 0; i < 3; i++ {
+		cb.Execute(panicFunc)
+	}
+	if len(fired) != 2 {
+		t.Errorf("Expected the callback again in the new window, got %v", fired)
+	}
+}
+
+func TestOnPanicThreshold_CircuitStillOpens(t *testing.T) {
+	calls := 0
+	cb := New(Settings{
+		Name:             "panic-trip",
+		RecoverPanics:    true,
+		OnPanicThreshold: func(string, uint32) { calls++ },
+	})
+
+	for i := 0; i < 6; i++ {
+		if _, err := cb.Execute(panicFunc); err == nil {
+			t.Fatal("Expected the recovered panic as an error")
+		}
+	}
+	if cb.State() != StateOpen {
+		t.Errorf("Expected panics to trip the circuit as failures, got %v", cb.State())
+	}
+	if calls != 1 {
+		t.Errorf("Expected the callback once, got %d", calls)
+	}
+	if got := cb.Counts().Panics; got != 0 {
+		t.Errorf("Expected Panics cleared by the trip, got %d", got)
+	}
+}
+
+func TestOnPanicThreshold_CallbackPanicRecovered(t *testing.T) {
+	cb := New(Settings{
+		Name:             "panic-callback",
+		RecoverPanics:    true,
+		OnPanicThreshold: func(string, uint32) { panic("pager down") },
+	})
+
+	if _, err := cb.Execute(panicFunc); err == nil {
+		t.Fatal("Expected the request's panic as an error")
+	}
+	if got := cb.Counts().Panics; got != 1 {
+		t.Errorf("Expected the panic counted, got %d", got)
+	}
+}
+
+func TestJournal_PanicType(t *testing.T) {
+	cb := New(Settings{
+		Name:          "panic-type",
+		RecoverPanics: true,
+		JournalSize:   4,
+		ReadyToTrip:   func(c Counts) bool { return c.Panics >= 2 },
+	})
+
+	cb.Execute(failFunc)
+	cb.Execute(panicFunc)
+	cb.Execute(func() (interface{}, error) {
+		var s []int
+		return s[1], nil // runtime.boundsError
+	})
+
+	entries := cb.LastTripJournal()
+	if len(entries) != 3 {
+		t.Fatalf("Expected 3 journaled requests, got %+v", entries)
+	}
+	if entries[0].PanicType != "" {
+		t.Errorf("Expected no PanicType for a plain failure, got %q", entries[0].PanicType)
+	}
+	if entries[1].PanicType != "string" || entries[2].PanicType != "runtime.boundsError" {
+		t.Errorf("Expected panic type names, got %q and %q", entries[1].PanicType, entries[2].PanicType)
+	}
+}
@@ -0,0 +1,70 @@
+package breaker
+
+// The plain closed path admits and records a call with none of the closed-path
+// features in play: only the request and success counters change. Whether a
+// breaker qualifies is cached in plainClosed, so a Closed call branches once
+// instead of checking each feature in turn. Failures, canceled calls and any
+// change of state mid-call fall back to the full recording path.
+
+// hasClosedFeatures reports whether any feature fixed at construction acts on
+// Closed calls beyond counting them.
+func (cb *CircuitBreaker) hasClosedFeatures() bool {
+	return cb.customEngine || cb.watchesRate || cb.trackLatency ||
+		cb.outcomeWeight != nil || cb.healthScoreAlpha > 0 ||
+		cb.hold != nil || cb.maxRequestsPerCycle > 0 ||
+		cb.cache != nil && cb.cache.lastSuccess ||
+		cb.bulkhead != nil || cb.history != nil || cb.journal != nil ||
+		cb.baseline != nil || cb.recent != nil
+}
+
+// refreshPlainClosed recomputes plainClosed. Called after every change to one
+// of its runtime inputs; serialized, so the last recompute sees every change.
+func (cb *CircuitBreaker) refreshPlainClosed() {
+	cb.plainMu.Lock()
+	defer cb.plainMu.Unlock()
+
+	plain := !cb.closedFeatures && !cb.intervalEnabled.Load() &&
+		cb.isSuccessful.Load() == &defaultClassifier &&
+		!cb.maintenance.Load() && !cb.closed.Load() && cb.dependencies.Load() == nil
+	cb.plainClosed.Store(plain)
+}
+
+// inPlainClosed reports whether the breaker takes the plain path: plainClosed
+// is set, and the circuit is Closed and not disabled (the whole low byte of the
+// state word is zero). Inlined, so other states pay two loads.
+func (cb *CircuitBreaker) inPlainClosed() bool {
+	return cb.plainClosed.Load() && cb.state.Load()&(stateMask|disabledBit) == uint64(StateClosed)
+}
+
+// admitPlain admits a as a plain Closed call, given inPlainClosed. Returns
+// false, with nothing changed, if the call needs the full admission checks.
+func (cb *CircuitBreaker) admitPlain(a *admission) bool {
+	if a.directives != 0 || a.synthetic || a.observe || a.err() != nil {
+		return false
+	}
+
+	a.demand = true
+	a.requestCounted = cb.safeIncrementRequests()
+	if !a.requestCounted {
+		cb.recordUnserved()
+	}
+	a.state = StateClosed
+	a.gen = cb.admissionGeneration()
+	return true
+}
+
+// runPlain runs req for a call admitPlain has let through. A success is
+// counted here; anything else is recorded by recordCall.
+func (cb *CircuitBreaker) runPlain(a *admission, req func() (interface{}, error)) (interface{}, error) {
+	result, err, elapsed, panicked := cb.invokeRecovering(a, req)
+	if panicked {
+		return result, err
+	}
+	if err != nil || !a.requestCounted || a.err() != nil || !cb.plainClosed.Load() || cb.Disabled() {
+		return cb.recordCall(a, result, err, elapsed, nil)
+	}
+	if !cb.lateOutcome(a.gen) {
+		cb.countOutcome(true)
+	}
+	return result, nil
+}
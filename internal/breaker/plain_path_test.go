@@ -0,0 +1,83 @@
+package breaker
+
+import (
+	"testing"
+	"time"
+)
+
+func TestPlainClosed_FollowsRuntimeInputs(t *testing.T) {
+	cb := New(Settings{Name: "plain"})
+	if !cb.plainClosed.Load() {
+		t.Fatal("breaker without closed-path features should take the plain path")
+	}
+
+	steps := []struct {
+		name   string
+		change func()
+		want   bool
+	}{
+		{"maintenance", cb.EnterMaintenance, false},
+		{"maintenance over", cb.ExitMaintenance, true},
+		{"interval", func() { cb.UpdateSettings(SettingsUpdate{Interval: DurationPtr(time.Minute)}) }, false},
+		{"interval off", func() { cb.UpdateSettings(SettingsUpdate{Interval: DurationPtr(0)}) }, true},
+		{"classifier", func() {
+			fn := func(err error) bool { return true }
+			cb.UpdateSettings(SettingsUpdate{IsSuccessful: &fn})
+		}, false},
+		{"classifier reset", func() {
+			var fn func(error) bool
+			cb.UpdateSettings(SettingsUpdate{IsSuccessful: &fn})
+		}, true},
+		{"dependency", func() { cb.DependsOn(New(Settings{Name: "upstream"})) }, false},
+	}
+	for _, step := range steps {
+		step.change()
+		if got := cb.plainClosed.Load(); got != step.want {
+			t.Fatalf("after %s: plainClosed = %v, want %v", step.name, got, step.want)
+		}
+	}
+}
+
+func TestPlainClosed_ExcludedByClosedFeatures(t *testing.T) {
+	for name, settings := range map[string]Settings{
+		"adaptive":      {AdaptiveThreshold: true},
+		"track latency": {TrackLatency: true},
+		"bulkhead":      {MaxConcurrent: 1},
+		"journal":       {JournalSize: 4},
+		"min closed":    {MinClosedDuration: time.Second},
+	} {
+		if New(settings).plainClosed.Load() {
+			t.Errorf("%s: plainClosed set despite a closed-path feature", name)
+		}
+	}
+}
+
+func TestPlainClosed_ClosedBreakerLeavesPath(t *testing.T) {
+	cb := New(Settings{Name: "plain"})
+	cb.Close()
+	if cb.plainClosed.Load() {
+		t.Fatal("plainClosed still set after Close")
+	}
+	if _, err := cb.Execute(successFunc); err != ErrBreakerClosed {
+		t.Fatalf("Execute after Close = %v, want ErrBreakerClosed", err)
+	}
+}
+
+func TestPlainClosed_DisabledRunsUncounted(t *testing.T) {
+	cb := New(Settings{Name: "plain"})
+	cb.Disable()
+	if _, err := cb.Execute(successFunc); err != nil {
+		t.Fatalf("Execute while disabled: %v", err)
+	}
+	if got := cb.Counts().Requests; got != 0 {
+		t.Fatalf("Requests = %d while disabled, want 0", got)
+	}
+
+	cb.Enable()
+	cb.Execute(successFunc)
+	cb.Execute(failFunc)
+	counts := cb.Counts()
+	if counts.Requests != 2 || counts.TotalSuccesses != 1 || counts.TotalFailures != 1 {
+		t.Fatalf("counts = %+v, want 2 requests, 1 success, 1 failure", counts)
+	}
+}
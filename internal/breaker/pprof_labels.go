@@ -0,0 +1,26 @@
+package breaker
+
+import (
+	"context"
+	"runtime/pprof"
+)
+
+// pprofLabelKey is the runtime/pprof label key set by PprofLabels.
+const pprofLabelKey = "autobreaker"
+
+// invoke calls req, under the pprof label autobreaker=<name> added to ctx's
+// labels when PprofLabels is set.
+func (cb *CircuitBreaker) invoke(ctx context.Context, req func() (interface{}, error)) (interface{}, error) {
+	if !cb.pprofLabels {
+		return req()
+	}
+	return cb.invokeLabeled(ctx, req)
+}
+
+// invokeLabeled calls req under the pprof label autobreaker=<name>.
+func (cb *CircuitBreaker) invokeLabeled(ctx context.Context, req func() (interface{}, error)) (result interface{}, err error) {
+	pprof.Do(ctx, pprof.Labels(pprofLabelKey, cb.name), func(context.Context) {
+		result, err = req()
+	})
+	return result, err
+}
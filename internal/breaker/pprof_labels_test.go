@@ -0,0 +1,110 @@
+package breaker
+
+import (
+	"bytes"
+	"context"
+	"runtime/pprof"
+	"strings"
+	"testing"
+)
+
+// goroutineLabelsInclude reports whether any goroutine carries all the given
+// pprof labels, formatted as `"key":"value"`, per the goroutine profile.
+func goroutineLabelsInclude(t *testing.T, labels ...string) bool {
+	t.Helper()
+	var buf bytes.Buffer
+	if err := pprof.Lookup("goroutine").WriteTo(&buf, 1); err != nil {
+		t.Fatalf("goroutine profile: %v", err)
+	}
+	for _, line := range strings.Split(buf.String(), "\n") {
+		if !strings.Contains(line, "# labels:") {
+			continue
+		}
+		found := true
+		for _, label := range labels {
+			found = found && strings.Contains(line, label)
+		}
+		if found {
+			return true
+		}
+	}
+	return false
+}
+
+func TestPprofLabels_Execute(t *testing.T) {
+	cb := New(Settings{Name: "pprof-execute", PprofLabels: true})
+
+	var labeled bool
+	_, err := cb.Execute(func() (interface{}, error) {
+		labeled = goroutineLabelsInclude(t, `"autobreaker":"pprof-execute"`)
+		return "ok", nil
+	})
+	if err != nil {
+		t.Fatalf("Execute: %v", err)
+	}
+	if !labeled {
+		t.Error("Expected the request to run under the autobreaker pprof label")
+	}
+	if goroutineLabelsInclude(t, `"autobreaker":"pprof-execute"`) {
+		t.Error("Expected the label removed after the request returned")
+	}
+}
+
+func TestPprofLabels_ExecuteContextComposes(t *testing.T) {
+	cb := New(Settings{Name: "pprof-context", PprofLabels: true})
+
+	var labeled bool
+	pprof.Do(context.Background(), pprof.Labels("tenant", "acme"), func(ctx context.Context) {
+		cb.ExecuteContext(ctx, func() (interface{}, error) {
+			labeled = goroutineLabelsInclude(t, `"autobreaker":"pprof-context"`, `"tenant":"acme"`)
+			return "ok", nil
+		})
+
+		// The caller's labels are restored afterwards
+		if !goroutineLabelsInclude(t, `"tenant":"acme"`) {
+			t.Error("Expected the caller's labels restored after ExecuteContext")
+		}
+	})
+
+	if !labeled {
+		t.Error("Expected the breaker label added to the caller's labels")
+	}
+}
+
+func TestPprofLabels_DisabledByDefault(t *testing.T) {
+	cb := New(Settings{Name: "pprof-disabled"})
+
+	var labeled bool
+	cb.Execute(func() (interface{}, error) {
+		labeled = goroutineLabelsInclude(t, `"autobreaker":"pprof-disabled"`)
+		return "ok", nil
+	})
+	cb.ExecuteContext(context.Background(), func() (interface{}, error) {
+		labeled = labeled || goroutineLabelsInclude(t, `"autobreaker":"pprof-disabled"`)
+		return "ok", nil
+	})
+
+	if labeled {
+		t.Error("Expected no pprof label without PprofLabels")
+	}
+	if cb.CurrentSettings().PprofLabels {
+		t.Error("Expected PprofLabels false in CurrentSettings")
+	}
+}
+
+func TestPprofLabels_PanicPropagates(t *testing.T) {
+	cb := New(Settings{Name: "pprof-panic", PprofLabels: true})
+
+	func() {
+		defer func() {
+			if r := recover(); r != "test panic" {
+				t.Errorf("Expected the request's panic, got %v", r)
+			}
+		}()
+		cb.Execute(panicFunc)
+	}()
+
+	if got := cb.Counts().TotalFailures; got != 1 {
+		t.Errorf("Expected the panic counted as a failure, got %d failures", got)
+	}
+}
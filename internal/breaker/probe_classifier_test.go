@@ -0,0 +1,139 @@
+package breaker
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestIsProbeSuccessful_StricterProbeReopens(t *testing.T) {
+	var probeCalls int
+	cb := New(Settings{
+		Name:          "probe",
+		StartHalfOpen: true,
+		Timeout:       time.Minute,
+		IsProbeSuccessful: func(result interface{}, err error, d time.Duration) bool {
+			probeCalls++
+			return err == nil && result == "deep-ok"
+		},
+	})
+
+	// 200 but degraded: a success by IsSuccessful, a failure as a probe
+	result, err := cb.Execute(func() (interface{}, error) { return "degraded", nil })
+	if err != nil || result != "degraded" {
+		t.Fatalf("Expected probe result returned, got %v, %v", result, err)
+	}
+
+	if cb.State() != StateOpen {
+		t.Errorf("Expected failed probe verdict to reopen, got %v", cb.State())
+	}
+	if reason := cb.Diagnostics().OpenReason; reason.Kind != OpenReasonProbeFailed {
+		t.Errorf("Expected probe-failed reason, got %v", reason.Kind)
+	}
+	// Counts were cleared by the reopen; the reason snapshot shows what was recorded
+	if counts := cb.Diagnostics().OpenReason.Counts; counts.TotalSuccesses != 1 || counts.TotalFailures != 0 {
+		t.Errorf("Expected the probe recorded as a success, got %+v", counts)
+	}
+	if probeCalls != 1 {
+		t.Errorf("Expected IsProbeSuccessful called once, got %d", probeCalls)
+	}
+}
+
+func TestIsProbeSuccessful_PassingProbeCloses(t *testing.T) {
+	cb := New(Settings{
+		Name:          "probe",
+		StartHalfOpen: true,
+		IsProbeSuccessful: func(result interface{}, err error, d time.Duration) bool {
+			return err == nil && result == "deep-ok"
+		},
+	})
+
+	cb.ExecuteContext(context.Background(), func() (interface{}, error) { return "deep-ok", nil })
+	if cb.State() != StateClosed {
+		t.Errorf("Expected passing probe to close, got %v", cb.State())
+	}
+}
+
+func TestIsProbeSuccessful_NotUsedForRegularTraffic(t *testing.T) {
+	calls := 0
+	cb := New(Settings{
+		Name: "probe",
+		IsProbeSuccessful: func(interface{}, error, time.Duration) bool {
+			calls++
+			return false
+		},
+	})
+
+	for i := 0; i < 5; i++ {
+		cb.Execute(successFunc)
+	}
+	if calls != 0 {
+		t.Errorf("Expected IsProbeSuccessful unused in Closed state, got %d calls", calls)
+	}
+}
+
+func TestIsProbeSuccessful_DurationCoversRequestOnly(t *testing.T) {
+	var got time.Duration
+	cb := New(Settings{
+		Name:          "probe",
+		StartHalfOpen: true,
+		IsProbeSuccessful: func(_ interface{}, _ error, d time.Duration) bool {
+			got = d
+			return d < 100*time.Millisecond
+		},
+	})
+
+	cb.Execute(func() (interface{}, error) {
+		time.Sleep(20 * time.Millisecond)
+		return nil, nil
+	})
+	if got < 20*time.Millisecond || got > 100*time.Millisecond {
+		t.Errorf("Expected the request duration (~20ms), got %v", got)
+	}
+	if cb.State() != StateClosed {
+		t.Errorf("Expected fast probe to close, got %v", cb.State())
+	}
+}
+
+func TestIsProbeSuccessful_BudgetUsesVerdicts(t *testing.T) {
+	verdicts := []bool{true, false, false}
+	var i int
+	cb := New(Settings{
+		Name:              "probe",
+		StartHalfOpen:     true,
+		Timeout:           time.Minute,
+		HalfOpenMaxProbes: 3,
+		MaxRequests:       3,
+		IsProbeSuccessful: func(interface{}, error, time.Duration) bool {
+			v := verdicts[i]
+			i++
+			return v
+		},
+	})
+
+	// All three probes succeed per IsSuccessful, but the verdicts are 1:2
+	for n := 0; n < 3; n++ {
+		cb.Execute(successFunc)
+	}
+	if cb.State() != StateOpen {
+		t.Errorf("Expected majority of failed verdicts to reopen, got %v", cb.State())
+	}
+}
+
+func TestIsProbeSuccessful_PanicFailsProbe(t *testing.T) {
+	cb := New(Settings{
+		Name:          "probe",
+		StartHalfOpen: true,
+		Timeout:       time.Minute,
+		IsProbeSuccessful: func(interface{}, error, time.Duration) bool {
+			panic("probe classifier bug")
+		},
+	})
+
+	if _, err := cb.Execute(successFunc); err != nil {
+		t.Fatalf("Expected request result unaffected, got %v", err)
+	}
+	if cb.State() != StateOpen {
+		t.Errorf("Expected panicking probe classifier to reopen, got %v", cb.State())
+	}
+}
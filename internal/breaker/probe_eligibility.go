@@ -0,0 +1,103 @@
+package breaker
+
+import "context"
+
+// ExecuteOpts are per-call options for ExecuteWithOpts.
+type ExecuteOpts struct {
+	// ProbeEligible marks the call as safe to act as a half-open recovery
+	// probe: cheap and idempotent, like a GET or a health check. In HalfOpen,
+	// calls that are not eligible are rejected rather than admitted as probes,
+	// so a heavyweight or non-idempotent operation (a payment) never tests a
+	// backend that may still be down. See Settings.EligibleProbeWait for the
+	// fallback when no eligible call arrives.
+	//
+	// Execute and the other Execute variants treat every call as eligible.
+	// ExecuteWithOpts and ExecuteContextWithOpts have no implicit default: set
+	// ProbeEligible for every operation that may probe.
+	ProbeEligible bool
+
+	// FairnessKey identifies who the call is made for, such as a tenant ID,
+	// to share half-open probing fairly. In HalfOpen, a call with the same key
+	// as the last admitted probe is rejected like a probe beyond MaxRequests
+	// for Settings.ProbeFairnessWait after that probe was admitted, so a call
+	// with another key gets the next probe slot. Once the wait has passed, the
+	// same key may probe again, so a single caller is never starved.
+	//
+	// Only the last probe's key is remembered, and it is forgotten on leaving
+	// HalfOpen. Empty (the default) opts out: the call probes as usual.
+	FairnessKey string
+}
+
+// anyProbe are the options of Execute and the other Execute variants: every
+// call may probe, without a fairness key.
+var anyProbe = ExecuteOpts{ProbeEligible: true}
+
+// ExecuteWithOpts runs req like Execute, with per-call options.
+//
+// A call with ProbeEligible unset is handled like Execute in Closed and Open,
+// but in HalfOpen it is rejected without running: with a *TooManyRequestsError
+// (ErrTooManyRequests), or ErrOpenState under Settings.RejectIneligibleAsOpen.
+// Rejected calls are not counted, and are tallied in
+// Metrics.IneligibleRejections. Once Settings.EligibleProbeWait has passed
+// since entering HalfOpen, such calls are admitted as probes like any other.
+//
+// A call with a FairnessKey yields the next probe slot to other keys, as
+// described there. Such rejections are tallied in Metrics.FairnessRejections.
+//
+// Thread-safe: Safe to call concurrently.
+//
+// Example - Probe With Reads Only:
+//
+//	opts := autobreaker.ExecuteOpts{ProbeEligible: req.Method == http.MethodGet}
+//	resp, err := breaker.ExecuteWithOpts(opts, func() (interface{}, error) {
+//	    return client.Do(req)
+//	})
+func (cb *CircuitBreaker) ExecuteWithOpts(opts ExecuteOpts, req func() (interface{}, error)) (interface{}, error) {
+	return cb.execute(req, nil, false, opts)
+}
+
+// ExecuteContextWithOpts runs req like ExecuteContext, with per-call options
+// applied as in ExecuteWithOpts. A context from WithForceProbeEligible makes
+// the call probe-eligible whatever opts.ProbeEligible says.
+//
+// Thread-safe: Safe to call concurrently.
+//
+// Example - Probe With Reads Only, Plus an Operator Override:
+//
+//	opts := autobreaker.ExecuteOpts{ProbeEligible: r.Method == http.MethodGet}
+//	if isAdmin(r) && r.URL.Query().Get("probe") == "1" {
+//	    ctx = autobreaker.WithForceProbeEligible(ctx)
+//	}
+//	resp, err := breaker.ExecuteContextWithOpts(ctx, opts, func() (interface{}, error) {
+//	    return client.Do(req.WithContext(ctx))
+//	})
+func (cb *CircuitBreaker) ExecuteContextWithOpts(ctx context.Context, opts ExecuteOpts, req func() (interface{}, error)) (interface{}, error) {
+	a := admission{ctx: ctx, opts: opts, directives: callDirectives(ctx)}
+	return cb.run(&a, req, nil)
+}
+
+// ineligibleMayProbe reports whether EligibleProbeWait has run out in the
+// current HalfOpen episode, so any request may probe.
+func (cb *CircuitBreaker) ineligibleMayProbe() bool {
+	if cb.eligibleProbeWait <= 0 {
+		return false
+	}
+	return cb.now()-cb.stateChangedAt.Load() >= int64(cb.eligibleProbeWait)
+}
+
+// rejectIneligible records a probe-ineligible request turned away in HalfOpen
+// and builds its rejection.
+func (cb *CircuitBreaker) rejectIneligible(a *admission) error {
+	cb.eventCounter().ineligibleRejections.Add(1)
+	cb.awaitingEligible.Store(true)
+	if cb.rejectIneligibleAsOpen {
+		return a.reject(rejectedOpen, cb.rejectOpen())
+	}
+	return a.reject(rejectedBusy, cb.tooManyRequestsError())
+}
+
+// awaitingEligibleProbe reports whether the HalfOpen circuit is turning
+// requests away while it waits for a probe-eligible one.
+func (cb *CircuitBreaker) awaitingEligibleProbe(state State) bool {
+	return state == StateHalfOpen && cb.awaitingEligible.Load() && !cb.ineligibleMayProbe()
+}
@@ -0,0 +1,117 @@
+package breaker
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+var (
+	eligible   = ExecuteOpts{ProbeEligible: true}
+	ineligible = ExecuteOpts{}
+)
+
+func TestExecuteWithOpts_OnlyEligibleRequestsProbe(t *testing.T) {
+	clk := newFakeClock()
+	cb := newWithClock(clk, tripOnFirstFailure(Settings{Name: "probe-eligible", Timeout: 10 * time.Millisecond}))
+	tripToHalfOpen(t, cb, clk)
+
+	ran := 0
+	for i := 0; i < 5; i++ {
+		_, err := cb.ExecuteWithOpts(ineligible, func() (interface{}, error) {
+			ran++
+			return "ok", nil
+		})
+		if !errors.Is(err, ErrTooManyRequests) {
+			t.Fatalf("Expected ErrTooManyRequests for an ineligible request, got %v", err)
+		}
+	}
+	if ran != 0 {
+		t.Errorf("Expected no ineligible request to run, %d did", ran)
+	}
+	if cb.State() != StateHalfOpen {
+		t.Fatalf("Expected HalfOpen while waiting for an eligible probe, got %v", cb.State())
+	}
+	if !cb.Diagnostics().AwaitingEligibleProbe {
+		t.Error("Expected Diagnostics to report waiting for an eligible probe")
+	}
+	if got := cb.Metrics().IneligibleRejections; got != 5 {
+		t.Errorf("Expected 5 ineligible rejections, got %d", got)
+	}
+
+	if _, err := cb.ExecuteWithOpts(eligible, successFunc); err != nil {
+		t.Fatalf("Expected the eligible probe to run, got %v", err)
+	}
+	if cb.State() != StateClosed {
+		t.Errorf("Expected the eligible probe to close the circuit, got %v", cb.State())
+	}
+	if cb.Diagnostics().AwaitingEligibleProbe {
+		t.Error("Expected no longer waiting once Closed")
+	}
+}
+
+func TestExecuteWithOpts_IneligibleAllowedOutsideHalfOpen(t *testing.T) {
+	cb := New(tripOnFirstFailure(Settings{Name: "probe-ineligible-closed", Timeout: time.Minute}))
+
+	if _, err := cb.ExecuteWithOpts(ineligible, successFunc); err != nil {
+		t.Errorf("Expected an ineligible request to run while Closed, got %v", err)
+	}
+	cb.ExecuteWithOpts(ineligible, failFunc)
+	if cb.State() != StateOpen {
+		t.Errorf("Expected an ineligible failure to count toward tripping, got %v", cb.State())
+	}
+	if _, err := cb.ExecuteWithOpts(ineligible, successFunc); !errors.Is(err, ErrOpenState) {
+		t.Errorf("Expected ErrOpenState while Open, got %v", err)
+	}
+}
+
+func TestExecuteWithOpts_RejectIneligibleAsOpen(t *testing.T) {
+	clk := newFakeClock()
+	cb := newWithClock(clk, tripOnFirstFailure(Settings{
+		Name:                   "probe-ineligible-open",
+		Timeout:                10 * time.Millisecond,
+		RejectIneligibleAsOpen: true,
+	}))
+	tripToHalfOpen(t, cb, clk)
+
+	if _, err := cb.ExecuteWithOpts(ineligible, successFunc); !errors.Is(err, ErrOpenState) {
+		t.Errorf("Expected ErrOpenState, got %v", err)
+	}
+}
+
+func TestExecuteWithOpts_FallsBackAfterEligibleProbeWait(t *testing.T) {
+	clk := newFakeClock()
+	cb := newWithClock(clk, tripOnFirstFailure(Settings{
+		Name:              "probe-eligible-fallback",
+		Timeout:           10 * time.Millisecond,
+		EligibleProbeWait: 30 * time.Millisecond,
+	}))
+	tripToHalfOpen(t, cb, clk)
+
+	if _, err := cb.ExecuteWithOpts(ineligible, successFunc); !errors.Is(err, ErrTooManyRequests) {
+		t.Fatalf("Expected rejection within EligibleProbeWait, got %v", err)
+	}
+	clk.advance(40 * time.Millisecond)
+
+	if cb.Diagnostics().AwaitingEligibleProbe {
+		t.Error("Expected no longer waiting once EligibleProbeWait has passed")
+	}
+	if _, err := cb.ExecuteWithOpts(ineligible, successFunc); err != nil {
+		t.Fatalf("Expected an ineligible request to probe after EligibleProbeWait, got %v", err)
+	}
+	if cb.State() != StateClosed {
+		t.Errorf("Expected the fallback probe to close the circuit, got %v", cb.State())
+	}
+}
+
+func TestExecuteWithOpts_RejectionsNotCounted(t *testing.T) {
+	clk := newFakeClock()
+	cb := newWithClock(clk, tripOnFirstFailure(Settings{Name: "probe-ineligible-counts", Timeout: 10 * time.Millisecond}))
+	tripToHalfOpen(t, cb, clk)
+
+	cb.ExecuteWithOpts(ineligible, successFunc)
+
+	if got := cb.Counts().Requests; got != 0 {
+		t.Errorf("Expected rejected requests not counted, got %d", got)
+	}
+}
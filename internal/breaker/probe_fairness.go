@@ -0,0 +1,47 @@
+package breaker
+
+import "time"
+
+// defaultProbeFairnessWait is the ProbeFairnessWait used when it is 0.
+const defaultProbeFairnessWait = time.Second
+
+// probeKey is the fairness key of the last admitted probe and when (UnixNano)
+// it was admitted.
+type probeKey struct {
+	key        string
+	admittedAt int64
+}
+
+// rememberProbeKey records the fairness key of a probe just admitted. A probe
+// without a key is remembered too, so any key may take the next slot.
+func (cb *CircuitBreaker) rememberProbeKey(key string) {
+	if key == "" && cb.lastProbeKey.Load() == nil {
+		return // Fairness not in use
+	}
+	cb.lastProbeKey.Store(&probeKey{key: key, admittedAt: cb.now()})
+}
+
+// probeKeyRepeats reports whether key was the last admitted probe's key and
+// ProbeFairnessWait has not passed since, so the next slot should go to
+// another key. Always false for an empty key.
+func (cb *CircuitBreaker) probeKeyRepeats(key string) bool {
+	if key == "" {
+		return false
+	}
+	last := cb.lastProbeKey.Load()
+	if last == nil || last.key != key {
+		return false
+	}
+	wait := cb.probeFairnessWait
+	if wait == 0 {
+		wait = defaultProbeFairnessWait
+	}
+	return cb.now()-last.admittedAt < int64(wait)
+}
+
+// rejectRepeatedKey records a request turned away in HalfOpen for probe
+// fairness and builds its rejection.
+func (cb *CircuitBreaker) rejectRepeatedKey(a *admission) error {
+	cb.eventCounter().fairnessRejections.Add(1)
+	return a.reject(rejectedBusy, cb.tooManyRequestsError())
+}
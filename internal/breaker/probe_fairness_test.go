@@ -0,0 +1,135 @@
+package breaker
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+// probeAs runs a successful request with the given fairness key, appending the
+// key to admitted if the request ran.
+func probeAs(cb *CircuitBreaker, key string, admitted *[]string) error {
+	_, err := cb.ExecuteWithOpts(ExecuteOpts{ProbeEligible: true, FairnessKey: key}, func() (interface{}, error) {
+		*admitted = append(*admitted, key)
+		return "ok", nil
+	})
+	return err
+}
+
+func TestProbeFairness_AlternatesKeys(t *testing.T) {
+	clk := newFakeClock()
+	cb := newWithClock(clk, tripOnFirstFailure(Settings{
+		Name:              "fair-probes",
+		Timeout:           10 * time.Millisecond,
+		HalfOpenMaxProbes: 6,
+	}))
+	tripToHalfOpen(t, cb, clk)
+
+	// Each tenant arrives twice in a row until the circuit recovers
+	var admitted []string
+	for _, key := range []string{"a", "a", "b", "b", "a", "a", "b", "b", "a", "a", "b", "b"} {
+		err := probeAs(cb, key, &admitted)
+		if err != nil && !errors.Is(err, ErrTooManyRequests) {
+			t.Fatalf("Expected admission or ErrTooManyRequests, got %v", err)
+		}
+		if cb.State() == StateClosed {
+			break
+		}
+	}
+
+	want := []string{"a", "b", "a", "b", "a", "b"}
+	if len(admitted) != len(want) {
+		t.Fatalf("Expected admitted probes %v, got %v", want, admitted)
+	}
+	for i := range want {
+		if admitted[i] != want[i] {
+			t.Fatalf("Expected admitted probes %v, got %v", want, admitted)
+		}
+	}
+	if cb.State() != StateClosed {
+		t.Errorf("Expected the probe budget to close the circuit, got %v", cb.State())
+	}
+
+	metrics := cb.Metrics()
+	if metrics.FairnessRejections != 5 {
+		t.Errorf("Expected 5 fairness rejections, got %d", metrics.FairnessRejections)
+	}
+	if metrics.ProbeSuccesses != 6 || metrics.ProbeFailures != 0 {
+		t.Errorf("Expected 6 successful probes, got %d successes and %d failures",
+			metrics.ProbeSuccesses, metrics.ProbeFailures)
+	}
+}
+
+func TestProbeFairness_SingleKeyNotStarved(t *testing.T) {
+	clk := newFakeClock()
+	cb := newWithClock(clk, tripOnFirstFailure(Settings{
+		Name:              "fair-probes",
+		Timeout:           10 * time.Millisecond,
+		HalfOpenMaxProbes: 2,
+		ProbeFairnessWait: 20 * time.Millisecond,
+	}))
+	tripToHalfOpen(t, cb, clk)
+
+	var admitted []string
+	if err := probeAs(cb, "a", &admitted); err != nil {
+		t.Fatalf("Expected the first probe to run, got %v", err)
+	}
+	if err := probeAs(cb, "a", &admitted); !errors.Is(err, ErrTooManyRequests) {
+		t.Fatalf("Expected the same key to yield within ProbeFairnessWait, got %v", err)
+	}
+
+	clk.advance(30 * time.Millisecond)
+	if err := probeAs(cb, "a", &admitted); err != nil {
+		t.Fatalf("Expected the same key to probe after ProbeFairnessWait, got %v", err)
+	}
+	if cb.State() != StateClosed {
+		t.Errorf("Expected a single tenant to recover the circuit, got %v", cb.State())
+	}
+}
+
+func TestProbeFairness_KeyForgottenOnLeavingHalfOpen(t *testing.T) {
+	clk := newFakeClock()
+	cb := newWithClock(clk, tripOnFirstFailure(Settings{Name: "fair-probes", Timeout: 10 * time.Millisecond}))
+	tripToHalfOpen(t, cb, clk)
+
+	fail := ExecuteOpts{ProbeEligible: true, FairnessKey: "a"}
+	if _, err := cb.ExecuteWithOpts(fail, failFunc); err == nil || errors.Is(err, ErrTooManyRequests) {
+		t.Fatalf("Expected the failing probe to run, got %v", err)
+	}
+	if cb.State() != StateOpen {
+		t.Fatalf("Expected the failed probe to reopen the circuit, got %v", cb.State())
+	}
+	if cb.lastProbeKey.Load() != nil {
+		t.Error("Expected the probe key to be forgotten on leaving HalfOpen")
+	}
+
+	clk.advance(20 * time.Millisecond)
+	var admitted []string
+	if err := probeAs(cb, "a", &admitted); err != nil {
+		t.Fatalf("Expected the same key to probe in the next HalfOpen episode, got %v", err)
+	}
+	if metrics := cb.Metrics(); metrics.ProbeFailures != 1 || metrics.ProbeSuccesses != 1 {
+		t.Errorf("Expected 1 failed and 1 successful probe, got %d and %d",
+			metrics.ProbeFailures, metrics.ProbeSuccesses)
+	}
+}
+
+func TestProbeFairness_NoKeyUnaffected(t *testing.T) {
+	clk := newFakeClock()
+	cb := newWithClock(clk, tripOnFirstFailure(Settings{
+		Name:              "fair-probes",
+		Timeout:           10 * time.Millisecond,
+		HalfOpenMaxProbes: 3,
+	}))
+	tripToHalfOpen(t, cb, clk)
+
+	var admitted []string
+	for i := 0; i < 3; i++ {
+		if err := probeAs(cb, "", &admitted); err != nil {
+			t.Fatalf("Expected keyless probe %d to run, got %v", i+1, err)
+		}
+	}
+	if cb.lastProbeKey.Load() != nil {
+		t.Error("Expected no probe key to be remembered without fairness keys")
+	}
+}
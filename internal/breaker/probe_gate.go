@@ -0,0 +1,15 @@
+package breaker
+
+// probeGateOpen reports whether Settings.ProbeGate lets an open circuit whose
+// timeout has elapsed move to HalfOpen, counting the rejection in
+// Metrics.ProbeGateRejections if not. True without a gate.
+func (cb *CircuitBreaker) probeGateOpen() bool {
+	if cb.probeGate == nil {
+		return true
+	}
+	if safeCallProbeGate(cb.name, cb.probeGate) {
+		return true
+	}
+	cb.eventCounter().probeGateRejections.Add(1)
+	return false
+}
@@ -0,0 +1,85 @@
+package breaker
+
+import (
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestProbeGate_HoldsOpenPastTimeout(t *testing.T) {
+	const timeout = 30 * time.Second
+	var overloaded atomic.Bool
+	overloaded.Store(true)
+	clk := newFakeClock()
+	cb := newWithClock(clk, tripOnFirstFailure(Settings{
+		Name:      "gated",
+		Timeout:   timeout,
+		ProbeGate: func() bool { return !overloaded.Load() },
+	}))
+
+	cb.Execute(failFunc)
+	clk.advance(2 * timeout)
+
+	// Overloaded: the circuit stays open well past its timeout
+	ran := false
+	_, err := cb.Execute(func() (interface{}, error) {
+		ran = true
+		return nil, nil
+	})
+	if err != ErrOpenState || ran {
+		t.Fatalf("Expected the gate to reject without running, got ran=%v %v", ran, err)
+	}
+	if cb.State() != StateOpen {
+		t.Fatalf("Expected Open while gated, got %v", cb.State())
+	}
+	if got := cb.Metrics().ProbeGateRejections; got != 1 {
+		t.Errorf("Expected 1 gate rejection, got %d", got)
+	}
+
+	// The shedder recovers: the next request probes and closes the circuit
+	overloaded.Store(false)
+	if _, err := cb.Execute(successFunc); err != nil {
+		t.Fatalf("Expected the probe admitted once the gate opens, got %v", err)
+	}
+	if cb.State() != StateClosed {
+		t.Errorf("Expected Closed after the probe, got %v", cb.State())
+	}
+}
+
+func TestProbeGate_NotConsultedBeforeTimeout(t *testing.T) {
+	calls := 0
+	clk := newFakeClock()
+	cb := newWithClock(clk, tripOnFirstFailure(Settings{
+		Name:      "gate-early",
+		Timeout:   30 * time.Second,
+		ProbeGate: func() bool { calls++; return true },
+	}))
+
+	cb.Execute(failFunc)
+	clk.advance(10 * time.Second)
+	cb.Execute(successFunc)
+	if calls != 0 {
+		t.Errorf("Expected the gate not consulted before Timeout, got %d calls", calls)
+	}
+	if got := cb.Metrics().ProbeGateRejections; got != 0 {
+		t.Errorf("Expected no gate rejections, got %d", got)
+	}
+}
+
+func TestProbeGate_PanicAllowsProbe(t *testing.T) {
+	const timeout = 10 * time.Millisecond
+	cb := New(tripOnFirstFailure(Settings{
+		Name:      "gate-panic",
+		Timeout:   timeout,
+		ProbeGate: func() bool { panic("shedder down") },
+	}))
+
+	cb.Execute(failFunc)
+	time.Sleep(2 * timeout)
+	if _, err := cb.Execute(successFunc); err != nil {
+		t.Fatalf("Expected a panicking gate to allow the probe, got %v", err)
+	}
+	if cb.State() != StateClosed {
+		t.Errorf("Expected Closed, got %v", cb.State())
+	}
+}
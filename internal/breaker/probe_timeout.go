@@ -0,0 +1,95 @@
+package breaker
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// probeWatchdog is the HalfOpenProbeTimeout state: the timeout and the leases
+// of the probes being watched, so Close can stop their timers.
+type probeWatchdog struct {
+	timeout time.Duration
+	leases  sync.Map // *probeLease -> struct{}
+}
+
+// halfOpenProbeTimeout returns HalfOpenProbeTimeout, 0 without a watchdog.
+func (cb *CircuitBreaker) halfOpenProbeTimeout() time.Duration {
+	if cb.watchdog == nil {
+		return 0
+	}
+	return cb.watchdog.timeout
+}
+
+// probeLease tracks one admitted half-open probe for the HalfOpenProbeTimeout
+// watchdog. Exactly one of the probe's own release and the watchdog claims the
+// lease; the claimant gives the slot back.
+type probeLease struct {
+	claimed atomic.Bool
+	timer   *time.Timer
+}
+
+// watchProbe starts the watchdog for a probe admitted in the HalfOpen episode
+// identified by epoch. Returns nil when HalfOpenProbeTimeout is not set.
+func (cb *CircuitBreaker) watchProbe(epoch uint64) *probeLease {
+	w := cb.watchdog
+	if w == nil {
+		return nil
+	}
+	lease := &probeLease{}
+	lease.timer = time.AfterFunc(w.timeout, func() {
+		cb.abandonProbe(lease, epoch)
+	})
+
+	// Tracked so Close can stop the timer; a Close that raced past the
+	// tracking is caught by the re-check
+	w.leases.Store(lease, struct{}{})
+	if cb.closed.Load() {
+		lease.timer.Stop()
+	}
+	return lease
+}
+
+// abandonProbe releases the slot of a probe that outlived HalfOpenProbeTimeout
+// and records it as a failed probe.
+//
+// If the circuit has left the HalfOpen episode the probe was admitted in, the
+// slot counters have already been reset by the transition and the probe can no
+// longer decide anything, so only its probesInFlight count is given back: that
+// count follows the probe, not the episode, and the probe's own release will
+// find the lease claimed.
+func (cb *CircuitBreaker) abandonProbe(lease *probeLease, epoch uint64) {
+	if !lease.claimed.CompareAndSwap(false, true) {
+		return // The probe returned first
+	}
+	cb.watchdog.leases.Delete(lease)
+	cb.probesInFlight.Add(-1)
+	if cb.state.Load()&^disabledBit != epoch<<stateBits|uint64(StateHalfOpen) {
+		return
+	}
+
+	cb.eventCounter().probeTimeouts.Add(1)
+	cb.halfOpenRequests.Add(-1)
+
+	cb.recordOutcome(false)
+	cb.recordTimeoutFailure(true)
+	cb.journalOutcome(JournalFailure, errProbeAbandoned)
+	cb.decideHalfOpen(false)
+}
+
+// expired reports whether the watchdog abandoned the probe. The outcome of an
+// abandoned probe has already been recorded as a failure and must be discarded.
+// Nil-safe: a nil lease (no watchdog) never expires.
+func (l *probeLease) expired() bool {
+	return l != nil && l.claimed.Load()
+}
+
+// release claims the lease for the returning probe and stops the watchdog.
+// Reports false if the watchdog already released the slot.
+func (l *probeLease) release() bool {
+	if l == nil {
+		return true
+	}
+	l.timer.Stop()
+	return l.claimed.CompareAndSwap(false, true)
+}
@@ -0,0 +1,201 @@
+package breaker
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+// startHungProbe moves cb to HalfOpen and admits a probe that blocks until the
+// returned release function is called. done is closed once the probe returns.
+func startHungProbe(t *testing.T, cb *CircuitBreaker, execute func(func() (interface{}, error))) (release func(), done chan struct{}) {
+	t.Helper()
+	cb.Execute(failFunc)
+	requireState(t, cb, StateOpen, time.Second)
+	time.Sleep(20 * time.Millisecond) // Past Timeout
+
+	unblock := make(chan struct{})
+	started := make(chan struct{})
+	done = make(chan struct{})
+	go func() {
+		defer close(done)
+		execute(func() (interface{}, error) {
+			close(started)
+			<-unblock
+			return "late", nil
+		})
+	}()
+	<-started
+	return func() { close(unblock) }, done
+}
+
+func TestHalfOpenProbeTimeout_ReleasesHungProbe(t *testing.T) {
+	for _, tt := range []struct {
+		name    string
+		execute func(cb *CircuitBreaker) func(func() (interface{}, error))
+	}{
+		{"Execute", func(cb *CircuitBreaker) func(func() (interface{}, error)) {
+			return func(req func() (interface{}, error)) { cb.Execute(req) }
+		}},
+		{"ExecuteContext", func(cb *CircuitBreaker) func(func() (interface{}, error)) {
+			return func(req func() (interface{}, error)) { cb.ExecuteContext(context.Background(), req) }
+		}},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			cb := New(tripOnFirstFailure(Settings{
+				Name:                 "probe-timeout",
+				Timeout:              10 * time.Millisecond,
+				HalfOpenProbeTimeout: 30 * time.Millisecond,
+			}))
+
+			release, done := startHungProbe(t, cb, tt.execute(cb))
+			defer func() { release(); <-done }()
+
+			if cb.State() != StateHalfOpen {
+				t.Fatalf("Expected HalfOpen while the probe runs, got %v", cb.State())
+			}
+
+			// The watchdog abandons the probe and reopens the circuit
+			requireState(t, cb, StateOpen, time.Second)
+			if got := cb.halfOpenRequests.Load(); got != 0 {
+				t.Errorf("Expected the slot released, halfOpenRequests=%d", got)
+			}
+			if got := cb.probesInFlight.Load(); got != 0 {
+				t.Errorf("Expected no probes in flight, got %d", got)
+			}
+			if got := cb.Metrics().ProbeTimeouts; got != 1 {
+				t.Errorf("Expected ProbeTimeouts 1, got %d", got)
+			}
+
+			// The abandoned call's late success is discarded
+			counts := cb.Counts()
+			release()
+			<-done
+			release = func() {}
+			if cb.State() != StateOpen {
+				t.Errorf("Expected the late result not to close the circuit, got %v", cb.State())
+			}
+			if got := cb.Counts(); got != counts {
+				t.Errorf("Expected counts unchanged by the late result: %+v, want %+v", got, counts)
+			}
+			if got := cb.halfOpenRequests.Load(); got != 0 {
+				t.Errorf("Expected the slot released only once, halfOpenRequests=%d", got)
+			}
+		})
+	}
+}
+
+func TestHalfOpenProbeTimeout_RecoversAfterAbandonedProbe(t *testing.T) {
+	cb := New(tripOnFirstFailure(Settings{
+		Name:                 "probe-timeout-recover",
+		Timeout:              10 * time.Millisecond,
+		HalfOpenProbeTimeout: 30 * time.Millisecond,
+	}))
+
+	release, done := startHungProbe(t, cb, func(req func() (interface{}, error)) { cb.Execute(req) })
+	defer func() { release(); <-done }()
+
+	requireState(t, cb, StateOpen, time.Second)
+	time.Sleep(20 * time.Millisecond) // Past Timeout again
+
+	// The next probe gets the released slot while the hung call is still running
+	if _, err := cb.Execute(successFunc); err != nil {
+		t.Fatalf("Expected the next probe admitted, got %v", err)
+	}
+	if cb.State() != StateClosed {
+		t.Errorf("Expected Closed after a successful probe, got %v", cb.State())
+	}
+}
+
+func TestHalfOpenProbeTimeout_ProbeReturnsInTime(t *testing.T) {
+	cb := New(tripOnFirstFailure(Settings{
+		Name:                 "probe-timeout-in-time",
+		Timeout:              10 * time.Millisecond,
+		HalfOpenProbeTimeout: 20 * time.Millisecond,
+	}))
+
+	cb.Execute(failFunc)
+	time.Sleep(20 * time.Millisecond)
+	cb.Execute(successFunc)
+	if cb.State() != StateClosed {
+		t.Fatalf("Expected Closed, got %v", cb.State())
+	}
+
+	// The stopped watchdog never fires
+	time.Sleep(40 * time.Millisecond)
+	if cb.State() != StateClosed {
+		t.Errorf("Expected Closed to stick, got %v", cb.State())
+	}
+	if got := cb.Metrics().ProbeTimeouts; got != 0 {
+		t.Errorf("Expected ProbeTimeouts 0, got %d", got)
+	}
+}
+
+func TestHalfOpenProbeTimeout_CountsTowardProbeBudget(t *testing.T) {
+	cb := New(tripOnFirstFailure(Settings{
+		Name:                 "probe-timeout-budget",
+		Timeout:              10 * time.Millisecond,
+		MaxRequests:          2,
+		HalfOpenMaxProbes:    2,
+		HalfOpenProbeTimeout: 30 * time.Millisecond,
+	}))
+
+	release, done := startHungProbe(t, cb, func(req func() (interface{}, error)) { cb.Execute(req) })
+	defer func() { release(); <-done }()
+
+	cb.Execute(successFunc)
+	if cb.State() != StateHalfOpen {
+		t.Fatalf("Expected HalfOpen until both budgeted probes decide, got %v", cb.State())
+	}
+
+	// One success and one abandoned probe: no majority, so the circuit reopens
+	requireState(t, cb, StateOpen, time.Second)
+}
+
+func TestHalfOpenProbeTimeout_InCurrentSettings(t *testing.T) {
+	cb := New(Settings{Name: "probe-timeout-settings", HalfOpenProbeTimeout: time.Second})
+
+	if got := cb.CurrentSettings().HalfOpenProbeTimeout; got != time.Second {
+		t.Errorf("Expected HalfOpenProbeTimeout 1s, got %v", got)
+	}
+}
+
+func TestHalfOpenProbeTimeout_FiresAfterHalfOpenEnded(t *testing.T) {
+	cb := New(tripOnFirstFailure(Settings{
+		Name:                  "probe-timeout-stale",
+		Timeout:               10 * time.Millisecond,
+		MaxRequests:           2,
+		HalfOpenProbeTimeout:  30 * time.Millisecond,
+		ProbeRejectionDetails: true,
+	}))
+
+	release, done := startHungProbe(t, cb, func(req func() (interface{}, error)) { cb.Execute(req) })
+	defer func() { release(); <-done }()
+
+	// A second probe fails: the HalfOpen period ends before the watchdog fires
+	cb.Execute(failFunc)
+	if cb.State() != StateOpen {
+		t.Fatalf("Expected Open after the failed probe, got %v", cb.State())
+	}
+
+	// The watchdog fires for the stale probe and must give back its count
+	time.Sleep(60 * time.Millisecond)
+	if got := cb.probesInFlight.Load(); got != 0 {
+		t.Errorf("Expected no probes in flight after the stale watchdog, got %d", got)
+	}
+	if got := cb.Metrics().ProbeTimeouts; got != 0 {
+		t.Errorf("Expected a stale probe not counted as a timeout, got %d", got)
+	}
+
+	release()
+	<-done
+	release = func() {}
+	if got := cb.probesInFlight.Load(); got != 0 {
+		t.Errorf("Expected the late return not to release twice, probesInFlight=%d", got)
+	}
+
+	// A rejection reports no phantom probe in flight
+	if err := cb.tooManyRequestsError().(*TooManyRequestsError); err.ProbeInFlight {
+		t.Errorf("Expected no probe in flight reported, got %v", err)
+	}
+}
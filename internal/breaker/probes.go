@@ -0,0 +1,126 @@
+package breaker
+
+import "time"
+
+// tryConsumeProbe takes one execution from the HalfOpen probe budget.
+// Always succeeds when no budget is configured (HalfOpenMaxProbes=0).
+// Returns false once HalfOpenMaxProbes probes have been admitted in the current
+// HalfOpen episode.
+func (cb *CircuitBreaker) tryConsumeProbe() bool {
+	if cb.halfOpenMaxProbes == 0 {
+		return true
+	}
+
+	for {
+		used := cb.halfOpenProbes.Load()
+		if used >= cb.halfOpenMaxProbes {
+			return false
+		}
+		if cb.halfOpenProbes.CompareAndSwap(used, used+1) {
+			return true
+		}
+	}
+}
+
+// refundProbe returns a probe to the budget when an admitted probe produced no
+// outcome (context canceled or ErrIgnoreOutcome). Without the refund, the budget
+// could be spent without ever reaching a close/reopen decision.
+func (cb *CircuitBreaker) refundProbe() {
+	if cb.halfOpenMaxProbes == 0 {
+		return
+	}
+
+	for {
+		used := cb.halfOpenProbes.Load()
+		if used == 0 {
+			return
+		}
+		if cb.halfOpenProbes.CompareAndSwap(used, used-1) {
+			return
+		}
+	}
+}
+
+// decideHalfOpen applies a probe outcome recorded in HalfOpen state.
+//
+// Without a probe budget, the first outcome decides: success closes the circuit,
+// failure reopens it. With HalfOpenMaxProbes set, the decision waits until the
+// budget's worth of outcomes has been recorded, then closes only if successes
+// outnumber failures. RequireAllSuccesses additionally reopens on the first failure.
+func (cb *CircuitBreaker) decideHalfOpen(success bool) {
+	if success {
+		cb.probeSuccessesTotal.Add(1)
+	} else {
+		cb.probeFailuresTotal.Add(1)
+		cb.episodeProbeFailed()
+	}
+
+	if cb.halfOpenMaxProbes == 0 {
+		if success {
+			cb.transitionToClosed()
+		} else {
+			cb.transitionBackToOpen()
+		}
+		return
+	}
+
+	if !success && cb.requireAllSuccesses {
+		cb.transitionBackToOpen()
+		return
+	}
+
+	var successes, failures uint32
+	if success {
+		successes = cb.probeSuccesses.Add(1)
+		failures = cb.probeFailures.Load()
+	} else {
+		failures = cb.probeFailures.Add(1)
+		successes = cb.probeSuccesses.Load()
+	}
+	if successes+failures < cb.halfOpenMaxProbes {
+		return // Budget not exhausted yet, wait for remaining probes
+	}
+
+	if cb.probesRecovered(successes, failures) {
+		cb.transitionToClosed()
+	} else {
+		cb.transitionBackToOpen()
+	}
+}
+
+// classifiesProbe reports whether a request admitted in state is a probe
+// classified by IsProbeSuccessful.
+func (cb *CircuitBreaker) classifiesProbe(state State) bool {
+	return state == StateHalfOpen && cb.isProbeSuccessful != nil
+}
+
+// completeOutcome records a completed call with the given failure weight and
+// handles state transitions. Probes classified by IsProbeSuccessful are counted
+// by weight (the normal classifier), but IsProbeSuccessful decides whether the
+// circuit closes or reopens.
+func (cb *CircuitBreaker) completeOutcome(weight float64, currentState State, result interface{}, err error, elapsed time.Duration) {
+	success := cb.recordClassified(weight, err, elapsed)
+	if !cb.classifiesProbe(currentState) {
+		cb.settleOutcome(success, weight, currentState)
+		return
+	}
+	cb.decideHalfOpen(safeCallIsProbeSuccessful(cb.name, cb.isProbeSuccessful, result, err, elapsed))
+}
+
+// recordClassified records a classified outcome in the counts, weighting slow
+// calls (SlowCallFactor) and tallying them as timeouts, and journals it. Reports
+// whether it was recorded as a success.
+func (cb *CircuitBreaker) recordClassified(weight float64, err error, elapsed time.Duration) bool {
+	slow := false
+	if cb.slowCallFactor > 0 {
+		weight, slow = cb.slowCallWeight(weight, elapsed)
+	}
+	success := cb.recordWeightedOutcome(weight)
+	if success {
+		cb.journalOutcome(JournalSuccess, err)
+	} else {
+		cb.recordTimeoutFailure(slow)
+		cb.journalOutcome(JournalFailure, err)
+	}
+	return success
+}
@@ -0,0 +1,224 @@
+package breaker
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// newProbeBudgetBreaker returns a breaker on clk that trips on one failure and
+// enters HalfOpen after a short timeout, with the given probe budget.
+func newProbeBudgetBreaker(clk *fakeClock, budget, maxRequests uint32, requireAll bool) *CircuitBreaker {
+	return newWithClock(clk, Settings{
+		Name:                "test",
+		MaxRequests:         maxRequests,
+		Timeout:             20 * time.Millisecond,
+		HalfOpenMaxProbes:   budget,
+		RequireAllSuccesses: requireAll,
+		ReadyToTrip:         func(c Counts) bool { return c.ConsecutiveFailures >= 1 },
+	})
+}
+
+func TestHalfOpenMaxProbes_SequentialProbesBounded(t *testing.T) {
+	clk := newFakeClock()
+	cb := newProbeBudgetBreaker(clk, 3, 1, false)
+	tripToHalfOpen(t, cb, clk)
+
+	var calls int
+	probe := func() (interface{}, error) {
+		calls++
+		return "ok", nil
+	}
+
+	// Successes alone don't close the circuit until the budget is exhausted
+	cb.Execute(probe)
+	cb.Execute(probe)
+	if cb.State() != StateHalfOpen {
+		t.Fatalf("Expected half-open before budget exhausted, got %v", cb.State())
+	}
+	diag := cb.Diagnostics()
+	if diag.HalfOpenProbesUsed != 2 || diag.HalfOpenMaxProbes != 3 {
+		t.Errorf("Expected 2/3 probes used, got %d/%d", diag.HalfOpenProbesUsed, diag.HalfOpenMaxProbes)
+	}
+
+	cb.Execute(probe)
+	if cb.State() != StateClosed {
+		t.Fatalf("Expected closed after 3 successful probes, got %v", cb.State())
+	}
+	if calls != 3 {
+		t.Errorf("Expected 3 backend calls, got %d", calls)
+	}
+	if used := cb.Diagnostics().HalfOpenProbesUsed; used != 0 {
+		t.Errorf("Expected probes used to reset on close, got %d", used)
+	}
+}
+
+func TestHalfOpenMaxProbes_MajorityDecision(t *testing.T) {
+	clk := newFakeClock()
+	cb := newProbeBudgetBreaker(clk, 3, 1, false)
+	tripToHalfOpen(t, cb, clk)
+
+	// A failure does not reopen early without RequireAllSuccesses
+	cb.Execute(successFunc)
+	cb.Execute(failFunc)
+	if cb.State() != StateHalfOpen {
+		t.Fatalf("Expected half-open after early failure, got %v", cb.State())
+	}
+
+	cb.Execute(failFunc)
+	if cb.State() != StateOpen {
+		t.Fatalf("Expected open when failures outnumber successes, got %v", cb.State())
+	}
+	if reason := cb.Diagnostics().OpenReason.Kind; reason != OpenReasonProbeFailed {
+		t.Errorf("Expected OpenReasonProbeFailed, got %v", reason)
+	}
+}
+
+func TestHalfOpenMaxProbes_RequireAllSuccesses(t *testing.T) {
+	clk := newFakeClock()
+	cb := newProbeBudgetBreaker(clk, 3, 1, true)
+	tripToHalfOpen(t, cb, clk)
+
+	cb.Execute(successFunc)
+	cb.Execute(failFunc)
+	if cb.State() != StateOpen {
+		t.Fatalf("Expected open on first failed probe, got %v", cb.State())
+	}
+
+	// Budget resets on the next HalfOpen episode
+	clk.advance(30 * time.Millisecond)
+	for i := 0; i < 3; i++ {
+		if _, err := cb.Execute(successFunc); err != nil {
+			t.Fatalf("Probe %d rejected: %v", i+1, err)
+		}
+	}
+	if cb.State() != StateClosed {
+		t.Fatalf("Expected closed after all probes succeeded, got %v", cb.State())
+	}
+}
+
+func TestHalfOpenMaxProbes_ConcurrentProbesBounded(t *testing.T) {
+	const budget = 5
+	clk := newFakeClock()
+	cb := newProbeBudgetBreaker(clk, budget, 100, false)
+	tripToHalfOpen(t, cb, clk)
+
+	release := make(chan struct{})
+	var calls atomic.Int32
+	var rejected atomic.Int32
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := cb.Execute(func() (interface{}, error) {
+				calls.Add(1)
+				<-release
+				return "ok", nil
+			})
+			if errors.Is(err, ErrTooManyRequests) {
+				rejected.Add(1)
+			}
+		}()
+	}
+
+	// Wait until every goroutine has either been admitted or rejected
+	deadline := time.Now().Add(time.Second)
+	for calls.Load()+rejected.Load() < 50 && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	close(release)
+	wg.Wait()
+
+	if got := calls.Load(); got != budget {
+		t.Errorf("Expected backend to receive %d calls, got %d", budget, got)
+	}
+	if got := rejected.Load(); got != 50-budget {
+		t.Errorf("Expected %d rejections, got %d", 50-budget, got)
+	}
+	if cb.State() != StateClosed {
+		t.Errorf("Expected closed after successful probes, got %v", cb.State())
+	}
+}
+
+func TestHalfOpenMaxProbes_FastFailingProbesBounded(t *testing.T) {
+	const budget = 4
+	cb := New(Settings{
+		Name:              "test",
+		MaxRequests:       100,
+		Timeout:           time.Minute,
+		HalfOpenMaxProbes: budget,
+		ReadyToTrip:       func(c Counts) bool { return c.ConsecutiveFailures >= 1 },
+	})
+	cb.Execute(failFunc)
+	cb.openedAt.Store(time.Now().Add(-2 * time.Minute).UnixNano())
+
+	var calls atomic.Int32
+	var wg sync.WaitGroup
+	for i := 0; i < 200; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			cb.Execute(func() (interface{}, error) {
+				calls.Add(1)
+				return nil, errors.New("still down")
+			})
+		}()
+	}
+	wg.Wait()
+
+	if got := calls.Load(); got > budget {
+		t.Errorf("Expected at most %d backend calls, got %d", budget, got)
+	}
+	if cb.State() != StateOpen {
+		t.Errorf("Expected open after failed probes, got %v", cb.State())
+	}
+}
+
+func TestHalfOpenMaxProbes_NoOutcomeRefundsBudget(t *testing.T) {
+	clk := newFakeClock()
+	cb := newProbeBudgetBreaker(clk, 1, 1, false)
+	tripToHalfOpen(t, cb, clk)
+
+	// Ignored outcome: probe returned to the budget
+	cb.Execute(func() (interface{}, error) {
+		return nil, ErrIgnoreOutcome
+	})
+	if used := cb.Diagnostics().HalfOpenProbesUsed; used != 0 {
+		t.Fatalf("Expected ignored probe to be refunded, got %d used", used)
+	}
+
+	// Canceled context: probe returned to the budget
+	ctx, cancel := context.WithCancel(context.Background())
+	cb.ExecuteContext(ctx, func() (interface{}, error) {
+		cancel()
+		return "ok", nil
+	})
+	if used := cb.Diagnostics().HalfOpenProbesUsed; used != 0 {
+		t.Fatalf("Expected canceled probe to be refunded, got %d used", used)
+	}
+
+	if _, err := cb.Execute(successFunc); err != nil {
+		t.Fatalf("Expected probe to be admitted, got %v", err)
+	}
+	if cb.State() != StateClosed {
+		t.Errorf("Expected closed, got %v", cb.State())
+	}
+}
+
+func TestHalfOpenMaxProbes_DisabledKeepsFirstOutcomeDecision(t *testing.T) {
+	clk := newFakeClock()
+	cb := newProbeBudgetBreaker(clk, 0, 1, true)
+	tripToHalfOpen(t, cb, clk)
+
+	cb.Execute(successFunc)
+	if cb.State() != StateClosed {
+		t.Fatalf("Expected first success to close without a budget, got %v", cb.State())
+	}
+	if diag := cb.Diagnostics(); diag.HalfOpenMaxProbes != 0 || diag.HalfOpenProbesUsed != 0 {
+		t.Errorf("Expected no probe budget, got %d/%d", diag.HalfOpenProbesUsed, diag.HalfOpenMaxProbes)
+	}
+}
@@ -0,0 +1,59 @@
+package breaker
+
+import "time"
+
+// QuickStats is a lean snapshot of circuit breaker state for high-frequency scrapers.
+//
+// It carries only what a metrics agent needs on every scrape: no configuration,
+// no predictions, and no derived alerting flags. Use Diagnostics() for those.
+type QuickStats struct {
+	// State is the current circuit breaker state.
+	State State
+
+	// Counts contains the request and failure counters.
+	Counts Counts
+
+	// FailureRate is the current failure rate, computed as in Metrics.FailureRate.
+	FailureRate float64
+
+	// StateChangedAt is the timestamp of the last state transition.
+	StateChangedAt time.Time
+
+	// OpenedAt is when the circuit last entered Open. Zero while Closed.
+	OpenedAt time.Time
+}
+
+// QuickStats returns a lean snapshot of state, counts, failure rate, and timestamps.
+//
+// Unlike Diagnostics(), QuickStats never invokes user callbacks and never computes
+// predictions (WillTripNext, TimeUntilHalfOpen), so it is safe to call every few
+// milliseconds across hundreds of breakers.
+//
+// Like Metrics(), the values are read one atomic at a time and may be slightly
+// inconsistent under concurrent Execute() calls.
+//
+// Performance: zero allocations; a handful of atomic loads.
+//
+// Thread-safe: Safe to call concurrently.
+//
+// Example:
+//
+//	stats := breaker.QuickStats()
+//	gauge.Set(float64(stats.State))
+//	failureRate.Set(stats.FailureRate)
+func (cb *CircuitBreaker) QuickStats() QuickStats {
+	stats := QuickStats{
+		State:  cb.State(),
+		Counts: cb.Counts(),
+	}
+	if stats.Counts.Requests > 0 {
+		stats.FailureRate = cb.failureRate(stats.Counts)
+	}
+	if ts := cb.stateChangedAt.Load(); ts > 0 {
+		stats.StateChangedAt = time.Unix(0, ts)
+	}
+	if ts := cb.openedAt.Load(); ts > 0 {
+		stats.OpenedAt = time.Unix(0, ts)
+	}
+	return stats
+}
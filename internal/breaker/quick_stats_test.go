@@ -0,0 +1,141 @@
+package breaker
+
+import (
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestQuickStats_Snapshot(t *testing.T) {
+	cb := New(Settings{Name: "scrape", Timeout: time.Minute})
+
+	for i := 0; i < 3; i++ {
+		cb.Execute(successFunc)
+	}
+	cb.Execute(failFunc)
+
+	stats := cb.QuickStats()
+	if stats.State != StateClosed {
+		t.Errorf("Expected closed, got %v", stats.State)
+	}
+	if stats.Counts != cb.Counts() {
+		t.Errorf("Expected counts %+v, got %+v", cb.Counts(), stats.Counts)
+	}
+	if stats.FailureRate != 0.25 {
+		t.Errorf("Expected failure rate 0.25, got %v", stats.FailureRate)
+	}
+	if !stats.StateChangedAt.Equal(cb.Metrics().StateChangedAt) {
+		t.Errorf("Expected StateChangedAt %v, got %v", cb.Metrics().StateChangedAt, stats.StateChangedAt)
+	}
+	if !stats.OpenedAt.IsZero() {
+		t.Errorf("Expected zero OpenedAt while closed, got %v", stats.OpenedAt)
+	}
+
+	for i := 0; i < 6; i++ {
+		cb.Execute(failFunc)
+	}
+	stats = cb.QuickStats()
+	if stats.State != StateOpen || stats.OpenedAt.IsZero() {
+		t.Errorf("Expected open with OpenedAt set, got %v at %v", stats.State, stats.OpenedAt)
+	}
+}
+
+func TestQuickStats_ZeroAllocsNoCallbacks(t *testing.T) {
+	var calls atomic.Int32
+	cb := New(Settings{
+		Name: "scrape",
+		ReadyToTrip: func(counts Counts) bool {
+			calls.Add(1)
+			return false
+		},
+	})
+	cb.Execute(failFunc)
+	calls.Store(0)
+
+	allocs := testing.AllocsPerRun(100, func() {
+		_ = cb.QuickStats()
+	})
+	if allocs != 0 {
+		t.Errorf("Expected zero allocations, got %v", allocs)
+	}
+	if got := calls.Load(); got != 0 {
+		t.Errorf("Expected QuickStats not to invoke ReadyToTrip, got %d calls", got)
+	}
+}
+
+func TestDiagnosticsCache_Disabled(t *testing.T) {
+	var calls atomic.Int32
+	cb := New(Settings{
+		Name: "scrape",
+		ReadyToTrip: func(counts Counts) bool {
+			calls.Add(1)
+			return false
+		},
+	})
+
+	for i := 0; i < 5; i++ {
+		cb.Diagnostics()
+	}
+	if got := calls.Load(); got != 5 {
+		t.Errorf("Expected prediction on every call without cache, got %d", got)
+	}
+}
+
+func TestDiagnosticsCache_ReusesPrediction(t *testing.T) {
+	var calls atomic.Int32
+	cb := New(Settings{
+		Name:                "scrape",
+		DiagnosticsCacheTTL: 50 * time.Millisecond,
+		ReadyToTrip: func(counts Counts) bool {
+			calls.Add(1)
+			return counts.ConsecutiveFailures >= 2
+		},
+	})
+
+	for i := 0; i < 5; i++ {
+		if cb.Diagnostics().WillTripNext {
+			t.Fatal("Expected WillTripNext false with no failures")
+		}
+	}
+	if got := calls.Load(); got != 1 {
+		t.Errorf("Expected one prediction within TTL, got %d", got)
+	}
+
+	// A failure within the TTL is not reflected until the cache expires
+	cb.Execute(failFunc)
+	calls.Store(0)
+	if cb.Diagnostics().WillTripNext {
+		t.Error("Expected cached WillTripNext false within TTL")
+	}
+
+	time.Sleep(60 * time.Millisecond)
+	if !cb.Diagnostics().WillTripNext {
+		t.Error("Expected fresh WillTripNext true after TTL")
+	}
+	if got := calls.Load(); got != 1 {
+		t.Errorf("Expected one fresh prediction after TTL, got %d", got)
+	}
+}
+
+func TestDiagnosticsCache_BypassedOutsideClosed(t *testing.T) {
+	cb := New(Settings{
+		Name:                "scrape",
+		Timeout:             time.Minute,
+		DiagnosticsCacheTTL: time.Hour,
+	})
+
+	for i := 0; i < 5; i++ {
+		cb.Execute(failFunc)
+	}
+	if !cb.Diagnostics().WillTripNext {
+		t.Fatal("Expected WillTripNext true after 5 consecutive failures")
+	}
+
+	cb.Execute(failFunc)
+	if cb.State() != StateOpen {
+		t.Fatalf("Expected open, got %v", cb.State())
+	}
+	if cb.Diagnostics().WillTripNext {
+		t.Error("Expected WillTripNext false while open despite cached prediction")
+	}
+}
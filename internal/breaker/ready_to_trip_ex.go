@@ -0,0 +1,47 @@
+package breaker
+
+import "time"
+
+// WindowInfo is the context of a trip decision, passed to Settings.ReadyToTripEx
+// along with the counts. It is filled from the breaker's atomics for each call.
+type WindowInfo struct {
+	// WindowStart is when the current observation window started: when the
+	// counts were last cleared by Interval or a state transition (aligned to the
+	// window boundary with AlignIntervalToWallClock).
+	WindowStart time.Time
+
+	// Now is the time of the decision.
+	Now time.Time
+
+	// State is the state the decision is made in (StateClosed when tripping).
+	State State
+
+	// MinimumObservations and FailureRateThreshold are the current settings,
+	// including runtime updates and the adaptive defaults. They are reported as
+	// configured even outside adaptive mode, for rules of your own.
+	MinimumObservations  uint32
+	FailureRateThreshold float64
+
+	// DefaultWouldTrip reports whether the trip rule the breaker would use
+	// without a custom callback (adaptive, ConsecutiveFailureThreshold or
+	// DefaultReadyToTrip) trips on these counts.
+	DefaultWouldTrip bool
+}
+
+// callReadyToTripEx adapts ReadyToTripEx to the ReadyToTrip signature used
+// wherever the trip rule is evaluated.
+func (cb *CircuitBreaker) callReadyToTripEx(counts Counts) bool {
+	return cb.readyToTripEx(counts, cb.windowInfo(counts))
+}
+
+// windowInfo builds the WindowInfo for a decision on counts.
+func (cb *CircuitBreaker) windowInfo(counts Counts) WindowInfo {
+	return WindowInfo{
+		WindowStart:          time.Unix(0, cb.lastClearedAt.Load()),
+		Now:                  time.Unix(0, cb.now()),
+		State:                cb.machineState(),
+		MinimumObservations:  cb.getMinimumObservations(),
+		FailureRateThreshold: cb.getFailureRateThreshold(),
+		DefaultWouldTrip:     cb.defaultTrip(counts),
+	}
+}
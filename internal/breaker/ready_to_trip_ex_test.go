@@ -0,0 +1,135 @@
+package breaker
+
+import (
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestReadyToTripEx_TimeAware(t *testing.T) {
+	var last atomic.Pointer[WindowInfo]
+	cb := New(Settings{
+		Name:    "time-aware",
+		Timeout: time.Minute,
+		// Trip on the default rule, but only once the window has run 50ms
+		ReadyToTripEx: func(counts Counts, info WindowInfo) bool {
+			last.Store(&info)
+			return info.DefaultWouldTrip && info.Now.Sub(info.WindowStart) >= 50*time.Millisecond
+		},
+	})
+
+	for i := 0; i < 8; i++ {
+		cb.Execute(failFunc)
+	}
+	if cb.State() != StateClosed {
+		t.Fatalf("Expected no trip in a young window, got %v", cb.State())
+	}
+	info := last.Load()
+	if info == nil || !info.DefaultWouldTrip {
+		t.Fatalf("Expected the default rule to trip on 8 consecutive failures, got %+v", info)
+	}
+	if info.State != StateClosed {
+		t.Errorf("Expected State Closed, got %v", info.State)
+	}
+	if !info.WindowStart.Equal(cb.Metrics().CountsLastClearedAt) {
+		t.Errorf("Expected WindowStart %v, got %v", cb.Metrics().CountsLastClearedAt, info.WindowStart)
+	}
+	if info.Now.Before(info.WindowStart) {
+		t.Errorf("Expected Now %v after WindowStart %v", info.Now, info.WindowStart)
+	}
+
+	time.Sleep(60 * time.Millisecond)
+	cb.Execute(failFunc)
+	if cb.State() != StateOpen {
+		t.Fatalf("Expected a trip once the window has run 50ms, got %v", cb.State())
+	}
+	if detail := cb.Diagnostics().OpenReason.Detail; !strings.HasPrefix(detail, "ReadyToTripEx returned true") {
+		t.Errorf("Expected the reason to name ReadyToTripEx, got %q", detail)
+	}
+}
+
+func TestReadyToTripEx_DelegatesToDefault(t *testing.T) {
+	tests := []struct {
+		name     string
+		settings Settings
+	}{
+		{"static", Settings{}},
+		{"consecutive threshold", Settings{ConsecutiveFailureThreshold: 2}},
+		{"adaptive", Settings{AdaptiveThreshold: true, FailureRateThreshold: 0.2, MinimumObservations: 10}},
+	}
+
+	// Successes then failures until the circuit trips; returns the failures
+	tripPoint := func(s Settings) int {
+		s.Name = "delegate"
+		s.Timeout = time.Minute
+		cb := New(s)
+		for i := 0; i < 20; i++ {
+			cb.Execute(successFunc)
+		}
+		for n := 1; n <= 100; n++ {
+			cb.Execute(failFunc)
+			if cb.State() == StateOpen {
+				return n
+			}
+		}
+		return -1
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			want := tripPoint(tt.settings)
+
+			var info atomic.Pointer[WindowInfo]
+			delegating := tt.settings
+			delegating.ReadyToTripEx = func(counts Counts, i WindowInfo) bool {
+				info.Store(&i)
+				return i.DefaultWouldTrip
+			}
+			if got := tripPoint(delegating); got != want || want < 0 {
+				t.Errorf("Expected the delegating rule to trip after %d failures like the default, got %d", want, got)
+			}
+
+			if tt.settings.AdaptiveThreshold {
+				if i := info.Load(); i.MinimumObservations != 10 || i.FailureRateThreshold != 0.2 {
+					t.Errorf("Expected the adaptive settings in WindowInfo, got %+v", i)
+				}
+			}
+		})
+	}
+}
+
+func TestReadyToTripEx_WinsOverReadyToTrip(t *testing.T) {
+	cb := New(Settings{
+		Name:          "precedence",
+		ReadyToTrip:   alwaysTrip,
+		ReadyToTripEx: func(Counts, WindowInfo) bool { return false },
+	})
+	for i := 0; i < 10; i++ {
+		cb.Execute(failFunc)
+	}
+	if cb.State() != StateClosed {
+		t.Errorf("Expected ReadyToTripEx to decide, got %v", cb.State())
+	}
+
+	settings := cb.CurrentSettings()
+	if settings.ReadyToTripEx == nil || settings.ReadyToTrip != nil {
+		t.Errorf("Expected CurrentSettings to report ReadyToTripEx only")
+	}
+}
+
+func TestReadyToTripEx_WindowInfoNoAlloc(t *testing.T) {
+	cb := New(Settings{
+		Name:              "no-alloc",
+		AdaptiveThreshold: true,
+		ReadyToTripEx:     func(counts Counts, info WindowInfo) bool { return info.DefaultWouldTrip },
+	})
+	counts := Counts{Requests: 30, TotalFailures: 10}
+
+	allocs := testing.AllocsPerRun(100, func() {
+		_ = cb.callReadyToTripEx(counts)
+	})
+	if allocs != 0 {
+		t.Errorf("Expected zero allocations, got %v", allocs)
+	}
+}
@@ -0,0 +1,59 @@
+package breaker
+
+// Record feeds an externally classified outcome into the circuit breaker.
+//
+// Use Record when the success/failure decision is made outside a wrapped call, for
+// example by a separate validation step after the fact, or when an external health
+// signal should drive the circuit. The outcome is counted and evaluated exactly as
+// if it had come through Execute():
+//
+//   - Closed: Counts the outcome; failures may trip the circuit via ReadyToTrip
+//   - HalfOpen: Success closes the circuit, failure reopens it
+//   - Open: The observation is discarded (counts are frozen while Open)
+//   - Maintenance: The observation is discarded (see EnterMaintenance)
+//   - Disabled: The observation is discarded (see Disable)
+//
+// Record is an observation, not a call: admission is not checked, so it never
+// returns ErrOpenState or ErrTooManyRequests, never occupies a half-open slot,
+// and never triggers the Open → HalfOpen timeout transition.
+//
+// Record bypasses IsSuccessful: the success argument is taken as the final
+// classification.
+//
+// Thread-safe: Can be called concurrently with Execute() and other methods.
+//
+// Example:
+//
+//	resp, err := client.Do(req)
+//	if err == nil {
+//	    breaker.Record(validate(resp) == nil)
+//	}
+func (cb *CircuitBreaker) Record(success bool) {
+	// Observations during maintenance, while disabled or after Close are discarded
+	if cb.maintenance.Load() || cb.Disabled() || cb.closed.Load() {
+		return
+	}
+
+	// Check if interval-based count clearing is needed (only in Closed state)
+	if cb.intervalEnabled.Load() && cb.machineState() == StateClosed {
+		cb.maybeResetCounts()
+	}
+
+	currentState := cb.machineState()
+	if currentState == StateOpen {
+		return
+	}
+
+	// If counter is saturated, skip recording (same as Execute)
+	if !cb.safeIncrementRequests() {
+		return
+	}
+
+	cb.recordOutcome(success)
+	if success {
+		cb.journalOutcome(JournalSuccess, nil)
+	} else {
+		cb.journalOutcome(JournalFailure, nil)
+	}
+	cb.handleStateTransition(success, currentState)
+}
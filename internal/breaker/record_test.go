@@ -0,0 +1,118 @@
+package breaker
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRecord_TripsLikeExecute(t *testing.T) {
+	viaExecute := New(Settings{Name: "execute", Timeout: time.Minute})
+	viaRecord := New(Settings{Name: "record", Timeout: time.Minute})
+
+	for i := 0; i < 6; i++ {
+		viaExecute.Execute(failFunc)
+		viaRecord.Record(false)
+
+		if viaExecute.State() != viaRecord.State() {
+			t.Fatalf("After %d failures: Execute state=%v, Record state=%v",
+				i+1, viaExecute.State(), viaRecord.State())
+		}
+		if i < 5 && viaExecute.Counts() != viaRecord.Counts() {
+			t.Fatalf("After %d failures: Execute counts=%+v, Record counts=%+v",
+				i+1, viaExecute.Counts(), viaRecord.Counts())
+		}
+	}
+
+	if viaRecord.State() != StateOpen {
+		t.Fatalf("Expected Record(false) run to trip circuit, got %v", viaRecord.State())
+	}
+	if viaRecord.Diagnostics().OpenReason.Kind != OpenReasonConsecutiveFailures {
+		t.Errorf("Expected consecutive-failures reason, got %v", viaRecord.Diagnostics().OpenReason.Kind)
+	}
+}
+
+func TestRecord_AdaptiveTrip(t *testing.T) {
+	cb := New(Settings{
+		Name:                 "test",
+		Timeout:              time.Minute,
+		AdaptiveThreshold:    true,
+		FailureRateThreshold: 0.10,
+		MinimumObservations:  10,
+	})
+
+	for i := 0; i < 8; i++ {
+		cb.Record(true)
+	}
+	cb.Record(false)
+	if cb.State() != StateClosed {
+		t.Fatalf("Expected closed at 1/9, got %v", cb.State())
+	}
+	cb.Record(false) // 2/10 = 20%
+	if cb.State() != StateOpen {
+		t.Fatalf("Expected open at 20%% failure rate, got %v", cb.State())
+	}
+}
+
+func TestRecord_OpenStateIgnored(t *testing.T) {
+	cb := New(Settings{
+		Name:        "test",
+		Timeout:     time.Minute,
+		ReadyToTrip: func(c Counts) bool { return c.ConsecutiveFailures >= 1 },
+	})
+
+	cb.Record(false)
+	if cb.State() != StateOpen {
+		t.Fatalf("Expected open, got %v", cb.State())
+	}
+
+	cb.Record(true)
+	if cb.State() != StateOpen {
+		t.Errorf("Record should not change Open state, got %v", cb.State())
+	}
+	if counts := cb.Counts(); counts.Requests != 0 {
+		t.Errorf("Expected counts frozen while Open, got %+v", counts)
+	}
+}
+
+func TestRecord_HalfOpenDecides(t *testing.T) {
+	cb := New(Settings{
+		Name:        "test",
+		Timeout:     10 * time.Millisecond,
+		ReadyToTrip: func(c Counts) bool { return c.ConsecutiveFailures >= 1 },
+	})
+
+	cb.Record(false)
+	time.Sleep(20 * time.Millisecond)
+
+	// Record never performs the timeout transition itself
+	cb.Record(true)
+	if cb.State() != StateOpen {
+		t.Fatalf("Record should not transition Open → HalfOpen, got %v", cb.State())
+	}
+
+	// Move to HalfOpen via admission, then let an external signal decide
+	cb.transitionToHalfOpen()
+	cb.Record(true)
+	if cb.State() != StateClosed {
+		t.Errorf("Expected Record(true) in HalfOpen to close circuit, got %v", cb.State())
+	}
+}
+
+func TestRecord_BypassesIsSuccessful(t *testing.T) {
+	called := false
+	cb := New(Settings{
+		Name: "test",
+		IsSuccessful: func(error) bool {
+			called = true
+			return true
+		},
+	})
+
+	cb.Record(false)
+	if called {
+		t.Error("Record should not call IsSuccessful")
+	}
+	if counts := cb.Counts(); counts.TotalFailures != 1 {
+		t.Errorf("Expected 1 failure, got %+v", counts)
+	}
+}
@@ -0,0 +1,57 @@
+package breaker
+
+import (
+	"fmt"
+	"runtime/debug"
+)
+
+// PanicError is returned by Execute() and ExecuteContext() in place of a panic
+// in the request function when Settings.RecoverPanics is set.
+//
+// If the panic value is an error, PanicError unwraps to it, so errors.Is and
+// errors.As see through to the original error:
+//
+//	var panicErr *autobreaker.PanicError
+//	if errors.As(err, &panicErr) {
+//	    log.Printf("request panicked: %v\n%s", panicErr.Value, panicErr.Stack)
+//	}
+type PanicError struct {
+	// Value is the value passed to panic.
+	Value interface{}
+
+	// Stack is the stack trace of the panicking goroutine, captured when the
+	// panic was recovered.
+	Stack []byte
+}
+
+// Error implements the error interface.
+func (e *PanicError) Error() string {
+	return fmt.Sprintf("autobreaker: request panicked: %v", e.Value)
+}
+
+// Unwrap returns the panic value if it is an error, otherwise nil.
+func (e *PanicError) Unwrap() error {
+	err, _ := e.Value.(error)
+	return err
+}
+
+// newPanicError wraps a recovered panic value with the current stack. Must be
+// called from the deferred function that recovered.
+func newPanicError(r interface{}) *PanicError {
+	return &PanicError{Value: r, Stack: debug.Stack()}
+}
+
+// runDisabled runs req while the breaker is disabled, or for a call with
+// WithBypass: directly, without admission or accounting, converting a panic to
+// a *PanicError with RecoverPanics.
+func (cb *CircuitBreaker) runDisabled(req func() (interface{}, error)) (result interface{}, err error) {
+	if !cb.recoverPanics {
+		return req()
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			result, err = nil, newPanicError(r)
+		}
+	}()
+	return req()
+}
@@ -0,0 +1,129 @@
+package breaker
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestRecoverPanics_ReturnsPanicError(t *testing.T) {
+	cb := New(Settings{Name: "recover-panics", RecoverPanics: true})
+
+	result, err := cb.Execute(panicFunc)
+
+	if result != nil {
+		t.Errorf("Expected nil result, got %v", result)
+	}
+	var panicErr *PanicError
+	if !errors.As(err, &panicErr) {
+		t.Fatalf("Expected *PanicError, got %T: %v", err, err)
+	}
+	if panicErr.Value != "test panic" {
+		t.Errorf("Expected panic value %q, got %v", "test panic", panicErr.Value)
+	}
+	if !strings.Contains(string(panicErr.Stack), "panicFunc") {
+		t.Errorf("Expected stack to include the panicking function, got:\n%s", panicErr.Stack)
+	}
+	if got := err.Error(); got != "autobreaker: request panicked: test panic" {
+		t.Errorf("Error() = %q", got)
+	}
+
+	counts := cb.Counts()
+	if counts.Requests != 1 || counts.TotalFailures != 1 {
+		t.Errorf("Expected the panic counted as a failure, got %+v", counts)
+	}
+}
+
+func TestRecoverPanics_Trips(t *testing.T) {
+	cb := New(Settings{
+		Name:          "recover-panics-trip",
+		RecoverPanics: true,
+		ReadyToTrip: func(counts Counts) bool {
+			return counts.ConsecutiveFailures >= 2
+		},
+	})
+
+	cb.Execute(panicFunc)
+	cb.Execute(panicFunc)
+
+	if cb.State() != StateOpen {
+		t.Fatalf("Expected Open after 2 panics, got %v", cb.State())
+	}
+	if _, err := cb.Execute(successFunc); !errors.Is(err, ErrOpenState) {
+		t.Errorf("Expected ErrOpenState, got %v", err)
+	}
+}
+
+func TestRecoverPanics_ExecuteContext(t *testing.T) {
+	cb := New(Settings{Name: "recover-panics-context", RecoverPanics: true})
+
+	_, err := cb.ExecuteContext(context.Background(), panicFunc)
+
+	var panicErr *PanicError
+	if !errors.As(err, &panicErr) {
+		t.Fatalf("Expected *PanicError, got %T: %v", err, err)
+	}
+	if counts := cb.Counts(); counts.Requests != 1 || counts.TotalFailures != 1 {
+		t.Errorf("Expected the panic counted as a failure, got %+v", counts)
+	}
+}
+
+func TestRecoverPanics_ExecuteContextCanceled(t *testing.T) {
+	cb := New(Settings{Name: "recover-panics-canceled", RecoverPanics: true})
+	ctx, cancel := context.WithCancel(context.Background())
+
+	_, err := cb.ExecuteContext(ctx, func() (interface{}, error) {
+		cancel()
+		panic("test panic")
+	})
+
+	// A panic is a failure even if the context was canceled meanwhile
+	var panicErr *PanicError
+	if !errors.As(err, &panicErr) {
+		t.Fatalf("Expected *PanicError, got %T: %v", err, err)
+	}
+	if counts := cb.Counts(); counts.Requests != 1 || counts.TotalFailures != 1 {
+		t.Errorf("Expected the panic counted as a failure, got %+v", counts)
+	}
+}
+
+func TestRecoverPanics_UnwrapsErrorValue(t *testing.T) {
+	cb := New(Settings{Name: "recover-panics-unwrap", RecoverPanics: true})
+	errBoom := errors.New("boom")
+
+	_, err := cb.Execute(func() (interface{}, error) {
+		panic(errBoom)
+	})
+
+	if !errors.Is(err, errBoom) {
+		t.Errorf("Expected errors.Is to find the panicked error, got %v", err)
+	}
+}
+
+func TestRecoverPanics_Disabled(t *testing.T) {
+	cb := New(Settings{Name: "recover-panics-disabled", RecoverPanics: true})
+	cb.Disable()
+
+	_, err := cb.Execute(panicFunc)
+
+	var panicErr *PanicError
+	if !errors.As(err, &panicErr) {
+		t.Fatalf("Expected *PanicError while disabled, got %T: %v", err, err)
+	}
+	if counts := cb.Counts(); counts.Requests != 0 {
+		t.Errorf("Expected nothing counted while disabled, got %+v", counts)
+	}
+}
+
+func TestRecoverPanics_DefaultRepanics(t *testing.T) {
+	cb := New(Settings{Name: "recover-panics-default"})
+
+	defer func() {
+		if r := recover(); r != "test panic" {
+			t.Errorf("Expected re-panic with the original value, got %v", r)
+		}
+	}()
+	cb.Execute(panicFunc)
+	t.Error("Expected Execute to re-panic")
+}
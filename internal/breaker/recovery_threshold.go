@@ -0,0 +1,39 @@
+package breaker
+
+// beginRecovery starts the RecoveryWindows period after a HalfOpen → Closed
+// transition: the backend stays on probation (see onProbation) until that many
+// clean windows have ended. Without an Interval no window ever ends, so the
+// period never starts.
+func (cb *CircuitBreaker) beginRecovery() {
+	if cb.recoveryWindows == 0 || cb.getInterval() <= 0 || !cb.onProbation() {
+		return
+	}
+	cb.recoveryWindowsLeft.Store(cb.recoveryWindows)
+}
+
+// endRecovery ends any recovery period in progress.
+func (cb *CircuitBreaker) endRecovery() {
+	cb.recoveryWindowsLeft.Store(0)
+}
+
+// endRecoveryWindow credits the recovery period with a window ending at an
+// Interval boundary, given its counts. A window counts if it was a full one
+// with at least MinimumObservations requests at or below RecoverFailureRate.
+// The backend is healthy again once the last required window is credited.
+// Called by the interval clear, before the counts are cleared.
+func (cb *CircuitBreaker) endRecoveryWindow(counts Counts) {
+	left := cb.recoveryWindowsLeft.Load()
+	threshold := cb.getRecoverFailureRate()
+	if left == 0 || threshold <= 0 || cb.partialWindow.Load() {
+		return
+	}
+	if counts.Requests == 0 || counts.Requests < cb.getMinimumObservations() {
+		return // Too little traffic to vouch for the backend
+	}
+	if cb.rateExceeds(cb.failureRate(counts), threshold) {
+		return
+	}
+	if cb.recoveryWindowsLeft.CompareAndSwap(left, left-1) && left == 1 {
+		cb.unhealthy.Store(false)
+	}
+}
@@ -0,0 +1,253 @@
+package breaker
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+// recoverySettings trips at 10% and, after a recovery, at 5% until one clean
+// window has ended.
+func recoverySettings(name string) Settings {
+	return Settings{
+		Name:                 name,
+		AdaptiveThreshold:    true,
+		FailureRateThreshold: 0.10,
+		RecoverFailureRate:   0.05,
+		RecoveryWindows:      1,
+		MinimumObservations:  20,
+		Interval:             time.Minute,
+		Timeout:              10 * time.Second,
+	}
+}
+
+// runWindow runs successes and then failures through cb, stopping at the first
+// rejection. Returns the number of calls admitted.
+func runWindow(cb *CircuitBreaker, successes, failures int) int {
+	for i := 0; i < successes+failures; i++ {
+		req := successFunc
+		if i >= successes {
+			req = failFunc
+		}
+		if _, err := cb.Execute(req); err == ErrOpenState {
+			return i
+		}
+	}
+	return successes + failures
+}
+
+// tripAndRecover trips cb and closes it again with a successful probe.
+func tripAndRecover(t *testing.T, cb *CircuitBreaker, clk *fakeClock) {
+	t.Helper()
+	runWindow(cb, 0, 20)
+	if cb.State() != StateOpen {
+		t.Fatalf("Expected Open, got %v", cb.State())
+	}
+	clk.advance(cb.getTimeout())
+	cb.Execute(successFunc)
+	if cb.State() != StateClosed {
+		t.Fatalf("Expected Closed after the probe, got %v", cb.State())
+	}
+}
+
+func TestRecoveryWindows_TripsLowerAfterRecovery(t *testing.T) {
+	clk := newFakeClock()
+	cb := newWithClock(clk, recoverySettings("recovery-trip"))
+
+	// Before any recovery, 8% is under the trip rate
+	runWindow(cb, 92, 8)
+	if cb.State() != StateClosed {
+		t.Fatalf("Expected 8%% not to trip before a recovery, got %v", cb.State())
+	}
+	if diag := cb.Diagnostics(); diag.RecoveryComparison || diag.EffectiveFailureRateThreshold != 0.10 {
+		t.Errorf("Expected the normal threshold in force, got %+v", diag)
+	}
+
+	tripAndRecover(t, cb, clk)
+	diag := cb.Diagnostics()
+	if !diag.RecoveryComparison || diag.EffectiveFailureRateThreshold != 0.05 || diag.RecoveryWindowsRemaining != 1 {
+		t.Errorf("Expected the recovery threshold in force for 1 window, got comparison=%v threshold=%v windows=%d",
+			diag.RecoveryComparison, diag.EffectiveFailureRateThreshold, diag.RecoveryWindowsRemaining)
+	}
+
+	// The same 8% now trips
+	runWindow(cb, 92, 8)
+	if cb.State() != StateOpen {
+		t.Fatalf("Expected 8%% to trip during the recovery period, got %v", cb.State())
+	}
+	if reason := cb.Diagnostics().OpenReason; !strings.Contains(reason.Detail, "(recovery)") {
+		t.Errorf("Expected the trip reason to name the recovery threshold, got %+v", reason)
+	}
+	if got := cb.Diagnostics().RecoveryWindowsRemaining; got != 0 {
+		t.Errorf("Expected the recovery period ended by the trip, got %d windows left", got)
+	}
+}
+
+func TestRecoveryWindows_RevertsAfterCleanWindows(t *testing.T) {
+	clk := newFakeClock()
+	settings := recoverySettings("recovery-revert")
+	settings.RecoveryWindows = 2
+	cb := newWithClock(clk, settings)
+	tripAndRecover(t, cb, clk)
+
+	// An idle window does not vouch for the backend
+	clk.advance(time.Minute)
+	runWindow(cb, 10, 0)
+	if got := cb.Diagnostics().RecoveryWindowsRemaining; got != 2 {
+		t.Fatalf("Expected a window under MinimumObservations not to count, got %d left", got)
+	}
+
+	// Two clean windows end the period, each credited when the next one begins
+	for i := 0; i < 2; i++ {
+		clk.advance(time.Minute)
+		runWindow(cb, 97, 3)
+	}
+	if got := cb.Diagnostics().RecoveryWindowsRemaining; got != 1 {
+		t.Fatalf("Expected 1 window left, got %d", got)
+	}
+	clk.advance(time.Minute)
+	runWindow(cb, 92, 8)
+	diag := cb.Diagnostics()
+	if cb.State() != StateClosed || diag.RecoveryComparison || diag.EffectiveFailureRateThreshold != 0.10 {
+		t.Errorf("Expected the normal threshold back after 2 clean windows, got state=%v comparison=%v threshold=%v",
+			cb.State(), diag.RecoveryComparison, diag.EffectiveFailureRateThreshold)
+	}
+}
+
+func TestRecoveryWindows_RuntimeUpdate(t *testing.T) {
+	clk := newFakeClock()
+	settings := recoverySettings("recovery-update")
+	settings.RecoverFailureRate = 0
+	cb := newWithClock(clk, settings)
+
+	// Disabled: a recovery keeps the normal threshold
+	tripAndRecover(t, cb, clk)
+	if cb.Diagnostics().RecoveryComparison {
+		t.Fatal("Expected no recovery period while disabled")
+	}
+
+	if err := cb.UpdateSettings(SettingsUpdate{RecoverFailureRate: Float64Ptr(0.2)}); err == nil {
+		t.Error("Expected an error for RecoverFailureRate above FailureRateThreshold")
+	}
+	if err := cb.UpdateSettings(SettingsUpdate{
+		FailureRateThreshold: Float64Ptr(0.3),
+		RecoverFailureRate:   Float64Ptr(0.2),
+	}); err != nil {
+		t.Fatalf("Expected raising both together to succeed, got %v", err)
+	}
+	if err := cb.UpdateSettings(SettingsUpdate{FailureRateThreshold: Float64Ptr(0.1)}); err == nil {
+		t.Error("Expected an error for FailureRateThreshold below RecoverFailureRate")
+	}
+	if got := cb.CurrentSettings().RecoverFailureRate; got != 0.2 {
+		t.Errorf("Expected RecoverFailureRate 0.2, got %v", got)
+	}
+
+	clk.advance(time.Minute)
+	tripAndRecover(t, cb, clk)
+	if diag := cb.Diagnostics(); !diag.RecoveryComparison || diag.EffectiveFailureRateThreshold != 0.2 {
+		t.Errorf("Expected the updated recovery threshold in force, got %+v", diag)
+	}
+
+	// Disabling ends the period in progress
+	if err := cb.UpdateSettings(SettingsUpdate{RecoverFailureRate: Float64Ptr(0)}); err != nil {
+		t.Fatalf("UpdateSettings: %v", err)
+	}
+	if diag := cb.Diagnostics(); diag.RecoveryComparison || diag.RecoveryWindowsRemaining != 0 {
+		t.Errorf("Expected the recovery period ended, got %+v", diag)
+	}
+}
+
+func TestRecoveryWindows_Validation(t *testing.T) {
+	tests := []struct {
+		name     string
+		settings Settings
+		code     IssueCode
+	}{
+		{"above trip rate", Settings{AdaptiveThreshold: true, FailureRateThreshold: 0.05, RecoverFailureRate: 0.1, Interval: time.Minute}, IssueThresholdOrder},
+		{"without interval", Settings{AdaptiveThreshold: true, RecoverFailureRate: 0.02, RecoveryWindows: 2}, IssueIgnoredField},
+		{"without adaptive", Settings{RecoverFailureRate: 0.02, Interval: time.Minute}, IssueIgnoredField},
+		{"windows without recover rate", Settings{RecoveryWindows: 3}, IssueIgnoredField},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			found := false
+			for _, issue := range ValidateSettings(tt.settings) {
+				if issue.Code == tt.code && strings.HasPrefix(issue.Fields[0], "Recover") {
+					found = true
+				}
+			}
+			if !found {
+				t.Errorf("Expected a %v issue, got %+v", tt.code, ValidateSettings(tt.settings))
+			}
+		})
+	}
+}
+
+// flappingBackend is a backend that only heals when it rests. While hot, its
+// first loaded window after a rest fails at 9% (just under the 10% trip rate)
+// and later ones at 11%. Each loaded window heats it up, each rested window
+// cools it down.
+type flappingBackend struct {
+	heat   int
+	loaded int // Loaded windows since the last rest
+}
+
+// window runs one Interval of traffic through cb.
+func (b *flappingBackend) window(cb *CircuitBreaker) {
+	failures := 0
+	if b.heat > 0 {
+		failures = 9
+		if b.loaded > 0 {
+			failures = 11
+		}
+	}
+	if runWindow(cb, 100-failures, failures) == 0 {
+		// Rejected from the start: the backend rested
+		b.loaded = 0
+		if b.heat > 0 {
+			b.heat--
+		}
+		return
+	}
+	b.loaded++
+	if b.heat > 0 {
+		b.heat++
+	}
+}
+
+// countTrips runs the flapping backend for windows Intervals and returns how
+// often the circuit opened from Closed.
+func countTrips(settings Settings, windows int) (int, *CircuitBreaker) {
+	trips := 0
+	settings.OnStateChange = func(_ string, from, to State) {
+		if from == StateClosed && to == StateOpen {
+			trips++
+		}
+	}
+	clk := newFakeClock()
+	cb := newWithClock(clk, settings)
+	backend := &flappingBackend{heat: 3}
+	for i := 0; i < windows; i++ {
+		backend.window(cb)
+		clk.advance(time.Minute)
+	}
+	return trips, cb
+}
+
+func TestRecoveryWindows_FewerCyclesWhenOscillating(t *testing.T) {
+	settings := recoverySettings("oscillating")
+	settings.Timeout = 150 * time.Second // Each trip rests the backend two windows
+
+	without := settings
+	without.RecoverFailureRate = 0
+	baseline, _ := countTrips(without, 40)
+
+	trips, cb := countTrips(settings, 40)
+	if trips >= baseline {
+		t.Errorf("Expected fewer open/close cycles with hysteresis, got %d with vs %d without", trips, baseline)
+	}
+	if cb.State() != StateClosed || cb.Diagnostics().RecoveryComparison {
+		t.Errorf("Expected the healed backend closed on the normal threshold, got %v", cb.Diagnostics())
+	}
+	t.Logf("open/close cycles over 40 windows: %d with hysteresis, %d without", trips, baseline)
+}
@@ -0,0 +1,156 @@
+package breaker
+
+import (
+	"fmt"
+	"sync"
+)
+
+// Registry holds independently configured circuit breakers by name.
+//
+// Unlike Group, whose children share one policy and are keyed by endpoint, a
+// Registry is a process-wide directory of unrelated breakers (one per backend),
+// used to find a breaker by name and to observe all of them together.
+//
+// Key Features:
+//
+//   - Get-or-Create: GetOrCreate() returns the existing breaker for a name or
+//     creates it, exactly once, from the given settings
+//   - Lookup: Lookup() finds a breaker without creating it
+//   - Batch Metrics: Collect() snapshots every breaker for a metrics endpoint
+//   - Batch Updates: ApplyAll() updates many breakers' settings, all or nothing
+//
+// Example:
+//
+//	registry := autobreaker.NewRegistry()
+//	users := registry.GetOrCreate(autobreaker.Settings{Name: "user-service"})
+//	orders := registry.GetOrCreate(autobreaker.Settings{Name: "order-service"})
+//
+//	for _, nm := range registry.Collect() {
+//	    export(nm.Name, nm.Metrics)
+//	}
+//
+// Thread-safe: All methods are safe for concurrent use.
+type Registry struct {
+	mu       sync.RWMutex
+	names    []string // all registered names, in registration order
+	breakers map[string]*CircuitBreaker
+}
+
+// NamedMetrics pairs a breaker's name and labels (see Settings.Labels) with a
+// snapshot of its metrics. Returned by Registry.Collect().
+type NamedMetrics struct {
+	Name    string
+	Labels  map[string]string
+	Metrics Metrics
+}
+
+// NewRegistry creates an empty registry.
+func NewRegistry() *Registry {
+	return &Registry{
+		breakers: make(map[string]*CircuitBreaker),
+	}
+}
+
+// GetOrCreate returns the breaker registered under settings.Name, creating it
+// from settings if none exists.
+//
+// Settings are only used on creation; if the name is already registered, the
+// existing breaker is returned unchanged. Like New(), GetOrCreate panics on
+// invalid settings.
+//
+// Thread-safe: Concurrent calls for the same name return the same breaker.
+func (r *Registry) GetOrCreate(settings Settings) *CircuitBreaker {
+	r.mu.RLock()
+	cb, ok := r.breakers[settings.Name]
+	r.mu.RUnlock()
+	if ok {
+		return cb
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	// Re-check after acquiring write lock (another goroutine may have created it)
+	if cb, ok := r.breakers[settings.Name]; ok {
+		return cb
+	}
+
+	cb = New(settings)
+	r.breakers[settings.Name] = cb
+	r.names = append(r.names, settings.Name)
+
+	return cb
+}
+
+// Register creates a breaker from settings and registers it under
+// settings.Name.
+//
+// Unlike GetOrCreate, Register insists on its settings taking effect: it returns
+// an error wrapping ErrAlreadyRegistered if the name is taken, and the
+// validation error if settings are invalid (see NewChecked), instead of
+// panicking.
+func (r *Registry) Register(settings Settings) (*CircuitBreaker, error) {
+	if err := validateSettings(&settings); err != nil {
+		return nil, err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.breakers[settings.Name]; ok {
+		return nil, fmt.Errorf("%w: %q", ErrAlreadyRegistered, settings.Name)
+	}
+
+	cb := newBreaker(&settings)
+	r.breakers[settings.Name] = cb
+	r.names = append(r.names, settings.Name)
+
+	return cb, nil
+}
+
+// Lookup returns the breaker registered under name, if any.
+func (r *Registry) Lookup(name string) (*CircuitBreaker, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	cb, ok := r.breakers[name]
+	return cb, ok
+}
+
+// Names returns all registered names, in registration order.
+func (r *Registry) Names() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	names := make([]string, len(r.names))
+	copy(names, r.names)
+	return names
+}
+
+// Collect returns a metrics snapshot of every registered breaker, in
+// registration order.
+//
+// The set of breakers is captured under a single read lock acquisition, which
+// only copies pointers; metrics are read after the lock is released. Collect
+// therefore never holds up Execute() and only briefly delays the creating path
+// of GetOrCreate. Breakers created after the capture are not included.
+//
+// Each breaker's metrics are read atomically per field, so the result is a
+// point-in-time view per breaker and a near-simultaneous one across breakers,
+// matching calling Metrics() on each in quick succession.
+func (r *Registry) Collect() []NamedMetrics {
+	r.mu.RLock()
+	out := make([]NamedMetrics, len(r.names))
+	breakers := make([]*CircuitBreaker, len(r.names))
+	for i, name := range r.names {
+		out[i].Name = name
+		breakers[i] = r.breakers[name]
+	}
+	r.mu.RUnlock()
+
+	for i, cb := range breakers {
+		out[i].Labels = cb.Labels()
+		out[i].Metrics = cb.Metrics()
+	}
+	return out
+}
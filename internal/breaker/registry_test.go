@@ -0,0 +1,144 @@
+package breaker
+
+import (
+	"errors"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestRegistry_GetOrCreate(t *testing.T) {
+	r := NewRegistry()
+
+	a := r.GetOrCreate(Settings{Name: "a", Timeout: time.Minute})
+	if again := r.GetOrCreate(Settings{Name: "a", Timeout: time.Second}); again != a {
+		t.Error("Expected the existing breaker for a registered name")
+	}
+	if a.getTimeout() != time.Minute {
+		t.Errorf("Expected settings from first registration, got Timeout %v", a.getTimeout())
+	}
+
+	if cb, ok := r.Lookup("a"); !ok || cb != a {
+		t.Errorf("Expected Lookup to find a, got %v, %v", cb, ok)
+	}
+	if _, ok := r.Lookup("missing"); ok {
+		t.Error("Expected Lookup to miss an unregistered name")
+	}
+}
+
+func TestRegistry_GetOrCreate_Concurrent(t *testing.T) {
+	r := NewRegistry()
+
+	const goroutines = 50
+	results := make([]*CircuitBreaker, goroutines)
+	var wg sync.WaitGroup
+	for i := 0; i < goroutines; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			results[i] = r.GetOrCreate(Settings{Name: "shared"})
+		}(i)
+	}
+	wg.Wait()
+
+	for i, cb := range results {
+		if cb != results[0] {
+			t.Fatalf("Goroutine %d got a different breaker", i)
+		}
+	}
+	if names := r.Names(); !reflect.DeepEqual(names, []string{"shared"}) {
+		t.Errorf("Expected a single registration, got %v", names)
+	}
+}
+
+func TestRegistry_Collect(t *testing.T) {
+	r := NewRegistry()
+
+	if got := r.Collect(); len(got) != 0 {
+		t.Errorf("Expected no entries for an empty registry, got %v", got)
+	}
+
+	tripOnFirst := func(counts Counts) bool { return counts.ConsecutiveFailures >= 1 }
+
+	closed := r.GetOrCreate(Settings{Name: "closed"})
+	open := r.GetOrCreate(Settings{Name: "open", Timeout: time.Minute, ReadyToTrip: tripOnFirst})
+	r.GetOrCreate(Settings{Name: "half-open", StartHalfOpen: true})
+
+	closed.Execute(successFunc)
+	closed.Execute(successFunc)
+	open.Execute(failFunc)
+
+	got := r.Collect()
+	want := []struct {
+		name     string
+		state    State
+		requests uint32
+	}{
+		{"closed", StateClosed, 2},
+		{"open", StateOpen, 0},
+		{"half-open", StateHalfOpen, 0},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("Expected %d entries, got %d", len(want), len(got))
+	}
+	for i, w := range want {
+		if got[i].Name != w.name || got[i].Metrics.State != w.state ||
+			got[i].Metrics.Counts.Requests != w.requests {
+			t.Errorf("Entry %d: expected %s %v with %d requests, got %s %v with %d requests",
+				i, w.name, w.state, w.requests,
+				got[i].Name, got[i].Metrics.State, got[i].Metrics.Counts.Requests)
+		}
+	}
+}
+
+func TestRegistry_CollectConcurrentWithGetOrCreate(t *testing.T) {
+	r := NewRegistry()
+	r.GetOrCreate(Settings{Name: "base"})
+
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 100; i++ {
+			r.GetOrCreate(Settings{Name: "svc-" + string(rune('a'+i%26))})
+		}
+	}()
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 100; i++ {
+			for _, nm := range r.Collect() {
+				if nm.Name == "" {
+					t.Error("Expected every entry to be named")
+				}
+			}
+		}
+	}()
+	wg.Wait()
+
+	if got := len(r.Collect()); got != 27 {
+		t.Errorf("Expected 27 entries, got %d", got)
+	}
+}
+
+func TestRegistry_Register(t *testing.T) {
+	r := NewRegistry()
+
+	a, err := r.Register(Settings{Name: "a", Timeout: time.Minute})
+	if err != nil {
+		t.Fatalf("Expected Register to succeed, got %v", err)
+	}
+	if cb := r.GetOrCreate(Settings{Name: "a"}); cb != a {
+		t.Error("Expected GetOrCreate to return the registered breaker")
+	}
+
+	if _, err := r.Register(Settings{Name: "a"}); !errors.Is(err, ErrAlreadyRegistered) {
+		t.Errorf("Expected ErrAlreadyRegistered for a taken name, got %v", err)
+	}
+	if _, err := r.Register(Settings{Name: "b", Timeout: -time.Second}); err == nil {
+		t.Error("Expected a validation error for invalid settings")
+	}
+	if names := r.Names(); !reflect.DeepEqual(names, []string{"a"}) {
+		t.Errorf("Expected only a registered, got %v", names)
+	}
+}
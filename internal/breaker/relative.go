@@ -0,0 +1,74 @@
+package breaker
+
+import "fmt"
+
+// defaultRelativeFailureRateMultiplier is the RelativeFailureRateMultiplier
+// used when Baseline is set and the multiplier is 0.
+const defaultRelativeFailureRateMultiplier = 2.0
+
+// tripComparison is the failure rate comparison made by the adaptive trip rule.
+type tripComparison struct {
+	threshold    float64 // Effective failure rate threshold
+	baselineRate float64 // Baseline's failure rate, 0 without a Baseline
+	relative     bool    // threshold is relative to the baseline
+	burnRate     bool    // threshold is ErrorBudget × BurnRateThreshold
+	recovery     bool    // threshold is RecoverFailureRate
+}
+
+// tripComparison returns the threshold the adaptive trip rule compares the
+// failure rate against: RelativeFailureRateMultiplier × the Baseline's rate when
+// the baseline has enough observations, otherwise RecoverFailureRate until the
+// backend has recovered to it, and the burn rate threshold or FailureRateThreshold
+// after that.
+func (cb *CircuitBreaker) tripComparison() tripComparison {
+	absolute := tripComparison{threshold: cb.getFailureRateThreshold()}
+	if cb.errorBudget > 0 {
+		absolute.threshold = cb.errorBudget * cb.burnRateThreshold
+		absolute.burnRate = true
+	}
+	if cb.onProbation() {
+		absolute.threshold = cb.getRecoverFailureRate()
+		absolute.recovery = true
+		absolute.burnRate = false
+	}
+	if cb.relativeBaseline == nil {
+		return absolute
+	}
+
+	// Lock-free snapshot of the baseline's counts
+	b := cb.relativeBaseline
+	counts := b.Counts()
+	absolute.baselineRate = b.failureRate(counts)
+
+	minimum := b.getMinimumObservations()
+	if minimum == 0 {
+		minimum = cb.getMinimumObservations() // Static baseline: use ours
+	}
+	if counts.Requests == 0 || counts.Requests < minimum {
+		return absolute // Too little baseline traffic to compare against
+	}
+
+	return tripComparison{
+		threshold:    cb.relativeMultiplier * absolute.baselineRate,
+		baselineRate: absolute.baselineRate,
+		relative:     true,
+	}
+}
+
+// describe returns the threshold part of a trip reason, e.g. "10.00%",
+// "2.00% (recovery)", "1.40% (14.0× error budget 0.10%)" or
+// "10.00% (2.0× baseline \"api-stable\" at 5.00%)".
+func (c tripComparison) describe(cb *CircuitBreaker) string {
+	if c.recovery {
+		return fmt.Sprintf("%.2f%% (recovery)", c.threshold*100)
+	}
+	if c.burnRate {
+		return fmt.Sprintf("%.2f%% (%.1f× error budget %.2f%%)",
+			c.threshold*100, cb.burnRateThreshold, cb.errorBudget*100)
+	}
+	if !c.relative {
+		return fmt.Sprintf("%.2f%%", c.threshold*100)
+	}
+	return fmt.Sprintf("%.2f%% (%.1f× baseline %q at %.2f%%)",
+		c.threshold*100, cb.relativeMultiplier, cb.relativeBaseline.name, c.baselineRate*100)
+}
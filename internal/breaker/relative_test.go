@@ -0,0 +1,176 @@
+package breaker
+
+import (
+	"strings"
+	"testing"
+)
+
+// pairedBreakers returns a stable breaker and a canary compared against it.
+// The absolute threshold is high, so only the relative comparison trips.
+func pairedBreakers(multiplier float64) (stable, canary *CircuitBreaker) {
+	stable = New(Settings{
+		Name:                 "api-stable",
+		AdaptiveThreshold:    true,
+		FailureRateThreshold: 0.5,
+		MinimumObservations:  20,
+	})
+	canary = New(Settings{
+		Name:                          "api-canary",
+		AdaptiveThreshold:             true,
+		FailureRateThreshold:          0.5,
+		MinimumObservations:           20,
+		Baseline:                      stable,
+		RelativeFailureRateMultiplier: multiplier,
+	})
+	return stable, canary
+}
+
+// failAt returns a request failing on the listed positions of every 20.
+func failAt(i int, positions ...int) func() (interface{}, error) {
+	for _, p := range positions {
+		if i%20 == p {
+			return failFunc
+		}
+	}
+	return successFunc
+}
+
+func TestRelative_CanaryAtThreeTimesBaselineTrips(t *testing.T) {
+	stable, canary := pairedBreakers(2)
+
+	// Identical traffic: the stable deployment fails 5%, the canary 15%
+	for i := 0; i < 100 && canary.State() == StateClosed; i++ {
+		stable.Execute(failAt(i, 0))
+		canary.Execute(failAt(i, 0, 7, 14))
+	}
+
+	if canary.State() != StateOpen {
+		t.Errorf("Expected canary Open, got %v", canary.State())
+	}
+	if stable.State() != StateClosed {
+		t.Errorf("Expected stable Closed, got %v", stable.State())
+	}
+}
+
+func TestRelative_CanaryMatchingBaselineStaysClosed(t *testing.T) {
+	stable, canary := pairedBreakers(2)
+
+	for i := 0; i < 100; i++ {
+		stable.Execute(failAt(i, 0, 7, 14))
+		canary.Execute(failAt(i, 0, 7, 14))
+	}
+
+	if canary.State() != StateClosed {
+		t.Errorf("Expected canary Closed at the baseline's rate, got %v", canary.State())
+	}
+}
+
+func TestRelative_InsufficientBaselineFallsBackToAbsolute(t *testing.T) {
+	stable, canary := pairedBreakers(2)
+
+	// 19 baseline requests: below the baseline's MinimumObservations
+	for i := 0; i < 19; i++ {
+		stable.Execute(successFunc)
+	}
+	// 30% failures: above 2× the baseline's 0%, below the absolute 50%
+	for i := 0; i < 20; i++ {
+		canary.Execute(failAt(i, 0, 3, 6, 9, 12, 15))
+	}
+	if canary.State() != StateClosed {
+		t.Fatalf("Expected absolute threshold to apply, got %v", canary.State())
+	}
+
+	diag := canary.Diagnostics()
+	if diag.RelativeComparison {
+		t.Error("Expected RelativeComparison false with too little baseline traffic")
+	}
+	if diag.EffectiveFailureRateThreshold != 0.5 {
+		t.Errorf("Expected effective threshold 0.5, got %v", diag.EffectiveFailureRateThreshold)
+	}
+
+	// One more baseline request enables the comparison: the next failure trips
+	stable.Execute(successFunc)
+	canary.Execute(failFunc)
+	if canary.State() != StateOpen {
+		t.Errorf("Expected canary Open once the baseline has enough traffic, got %v", canary.State())
+	}
+}
+
+func TestRelative_Diagnostics(t *testing.T) {
+	stable, canary := pairedBreakers(3)
+
+	for i := 0; i < 20; i++ {
+		stable.Execute(failAt(i, 0, 10)) // 10%
+		canary.Execute(failAt(i, 0))     // 5%
+	}
+
+	diag := canary.Diagnostics()
+	if !diag.RelativeComparison {
+		t.Fatal("Expected RelativeComparison")
+	}
+	if diag.BaselineFailureRate != 0.1 {
+		t.Errorf("Expected BaselineFailureRate 0.1, got %v", diag.BaselineFailureRate)
+	}
+	if diag.Metrics.FailureRate != 0.05 {
+		t.Errorf("Expected canary FailureRate 0.05, got %v", diag.Metrics.FailureRate)
+	}
+	if got := diag.EffectiveFailureRateThreshold; got < 0.3-1e-9 || got > 0.3+1e-9 {
+		t.Errorf("Expected EffectiveFailureRateThreshold 0.3, got %v", got)
+	}
+
+	if d := stable.Diagnostics(); d.RelativeComparison || d.BaselineFailureRate != 0 || d.EffectiveFailureRateThreshold != 0.5 {
+		t.Errorf("Expected absolute comparison without a Baseline, got %v/%v/%v",
+			d.RelativeComparison, d.BaselineFailureRate, d.EffectiveFailureRateThreshold)
+	}
+}
+
+func TestRelative_TripReasonNamesBaseline(t *testing.T) {
+	stable, canary := pairedBreakers(2)
+
+	for i := 0; i < 100 && canary.State() == StateClosed; i++ {
+		stable.Execute(failAt(i, 0))
+		canary.Execute(failAt(i, 0, 7, 14))
+	}
+
+	reason := canary.Diagnostics().LastTripReason
+	if !strings.Contains(reason, `baseline "api-stable"`) {
+		t.Errorf("Expected trip reason to name the baseline, got %q", reason)
+	}
+}
+
+func TestRelative_StaticBaselineUsesOwnMinimum(t *testing.T) {
+	stable := New(Settings{Name: "static-stable"})
+	canary := New(Settings{
+		Name:                 "static-canary",
+		AdaptiveThreshold:    true,
+		FailureRateThreshold: 0.5,
+		MinimumObservations:  20,
+		Baseline:             stable,
+	})
+
+	for i := 0; i < 19; i++ {
+		stable.Execute(successFunc)
+	}
+	if canary.Diagnostics().RelativeComparison {
+		t.Error("Expected the canary's MinimumObservations to gate a static baseline")
+	}
+
+	stable.Execute(successFunc)
+	if !canary.Diagnostics().RelativeComparison {
+		t.Error("Expected RelativeComparison with 20 baseline requests")
+	}
+}
+
+func TestRelative_DefaultMultiplier(t *testing.T) {
+	stable := New(Settings{Name: "default-stable", AdaptiveThreshold: true})
+	canary := New(Settings{Name: "default-canary", AdaptiveThreshold: true, Baseline: stable})
+
+	got := canary.CurrentSettings()
+	if got.RelativeFailureRateMultiplier != defaultRelativeFailureRateMultiplier {
+		t.Errorf("Expected default multiplier %v, got %v",
+			defaultRelativeFailureRateMultiplier, got.RelativeFailureRateMultiplier)
+	}
+	if got.Baseline != stable {
+		t.Error("Expected CurrentSettings to report Baseline")
+	}
+}
@@ -0,0 +1,26 @@
+package breaker
+
+// ResetConsecutive zeroes the consecutive failure and success streaks
+// (Counts.ConsecutiveFailures and Counts.ConsecutiveSuccesses), leaving the
+// windowed totals (Requests, TotalSuccesses, TotalFailures) and the state
+// untouched.
+//
+// Use it after a known-transient incident to give a backend another chance
+// under a consecutive-failure threshold (DefaultReadyToTrip or
+// ConsecutiveFailureThreshold): the streak restarts, while the window keeps
+// its history for the failure rate and the adaptive trip rule.
+//
+// Each counter is zeroed atomically. An outcome recorded concurrently lands
+// either before the reset, and is cleared from the streak, or after it, and
+// starts the new streak.
+//
+// Thread-safe: Can be called concurrently with Execute() and other methods.
+//
+// Example:
+//
+//	// The failures were caused by our own deploy, not the backend
+//	breaker.ResetConsecutive()
+func (cb *CircuitBreaker) ResetConsecutive() {
+	cb.consecutiveFailures.Store(0)
+	cb.consecutiveSuccesses.Store(0)
+}
@@ -0,0 +1,58 @@
+package breaker
+
+import "testing"
+
+func TestResetConsecutive_RestartsStreak(t *testing.T) {
+	cb := New(Settings{Name: "inventory"})
+
+	// Five consecutive failures: one short of DefaultReadyToTrip
+	cb.Execute(successFunc)
+	for i := 0; i < 5; i++ {
+		cb.Execute(failFunc)
+	}
+	if counts := cb.Counts(); counts.ConsecutiveFailures != 5 {
+		t.Fatalf("Expected a streak of 5, got %+v", counts)
+	}
+
+	cb.ResetConsecutive()
+
+	counts := cb.Counts()
+	if counts.ConsecutiveFailures != 0 || counts.ConsecutiveSuccesses != 0 {
+		t.Errorf("Expected the streaks cleared, got %+v", counts)
+	}
+	if counts.Requests != 6 || counts.TotalSuccesses != 1 || counts.TotalFailures != 5 {
+		t.Errorf("Expected the windowed totals kept, got %+v", counts)
+	}
+
+	// The sixth failure starts a new streak instead of tripping
+	cb.Execute(failFunc)
+	if cb.State() != StateClosed {
+		t.Fatalf("Expected Closed after the streak restarted, got %v", cb.State())
+	}
+	if counts := cb.Counts(); counts.ConsecutiveFailures != 1 || counts.TotalFailures != 6 {
+		t.Errorf("Expected a new streak of 1, got %+v", counts)
+	}
+
+	// The new streak trips as usual
+	for i := 0; i < 5; i++ {
+		cb.Execute(failFunc)
+	}
+	if cb.State() != StateOpen {
+		t.Errorf("Expected the new streak to trip, got %v", cb.State())
+	}
+}
+
+func TestResetConsecutive_ConsecutiveFailureThreshold(t *testing.T) {
+	cb := New(Settings{Name: "inventory", ConsecutiveFailureThreshold: 2})
+
+	cb.Execute(failFunc)
+	cb.Execute(failFunc)
+	cb.ResetConsecutive()
+	cb.Execute(failFunc)
+	if cb.State() != StateClosed {
+		t.Errorf("Expected Closed after the streak restarted, got %v", cb.State())
+	}
+	if got := cb.FailuresUntilTrip(); got != 2 {
+		t.Errorf("Expected 2 failures until trip, got %d", got)
+	}
+}
@@ -0,0 +1,88 @@
+package breaker
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// retryAfterError is implemented by rejection errors that know how long until
+// the circuit may admit a probe.
+type retryAfterError interface {
+	RetryAfter() time.Duration
+}
+
+// retryAfterOf returns the retry hint carried by err (found with errors.As).
+func retryAfterOf(err error) (time.Duration, bool) {
+	var ra retryAfterError
+	if err == nil || !errors.As(err, &ra) {
+		return 0, false
+	}
+	d := ra.RetryAfter()
+	if d < 0 {
+		d = 0
+	}
+	return d, true
+}
+
+// RetryableWithin reports whether a retry within d of the rejection err could
+// be admitted, i.e. whether the circuit may be ready to probe before d elapses.
+//
+// The answer comes from a RetryAfter() time.Duration method on err or any
+// error it wraps. Errors without that information, including the plain
+// ErrOpenState sentinel, conservatively return false, as does nil.
+//
+// Use it in retry loops to skip retries that are bound to be rejected:
+//
+//	_, err := breaker.Execute(call)
+//	if errors.Is(err, autobreaker.ErrOpenState) {
+//	    if deadline, ok := ctx.Deadline(); !ok || !autobreaker.RetryableWithin(err, time.Until(deadline)) {
+//	        return err // circuit won't probe in time, fail now
+//	    }
+//	}
+func RetryableWithin(err error, d time.Duration) bool {
+	retryAfter, ok := retryAfterOf(err)
+	return ok && retryAfter <= d
+}
+
+// SleepUntilHalfOpen blocks until the earliest time the circuit that rejected
+// with err may admit a probe, as reported by err's RetryAfter() method.
+//
+// Returns nil after sleeping, or ctx.Err() if ctx is done first. If err
+// carries no retry information (including the plain ErrOpenState sentinel),
+// SleepUntilHalfOpen returns err immediately without sleeping, so retry loops
+// stop instead of spinning.
+//
+// Example:
+//
+//	for {
+//	    result, err := breaker.Execute(call)
+//	    if !errors.Is(err, autobreaker.ErrOpenState) {
+//	        return result, err
+//	    }
+//	    if err := autobreaker.SleepUntilHalfOpen(ctx, err); err != nil {
+//	        return nil, err
+//	    }
+//	}
+func SleepUntilHalfOpen(ctx context.Context, err error) error {
+	retryAfter, ok := retryAfterOf(err)
+	if !ok {
+		return err
+	}
+	if ctxErr := ctx.Err(); ctxErr != nil {
+		return ctxErr
+	}
+	if retryAfter == 0 {
+		return nil
+	}
+
+	timer := time.NewTimer(retryAfter)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,91 @@
+package breaker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+)
+
+// hintedOpenError is an open-state rejection carrying a retry hint.
+type hintedOpenError struct {
+	retryAfter time.Duration
+}
+
+func (e hintedOpenError) Error() string             { return ErrOpenState.Error() }
+func (e hintedOpenError) Unwrap() error             { return ErrOpenState }
+func (e hintedOpenError) RetryAfter() time.Duration { return e.retryAfter }
+
+func TestRetryableWithin(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		d    time.Duration
+		want bool
+	}{
+		{"nil error", nil, time.Hour, false},
+		{"plain ErrOpenState", ErrOpenState, time.Hour, false},
+		{"other error", errors.New("boom"), time.Hour, false},
+		{"probe before budget", hintedOpenError{time.Second}, 2 * time.Second, true},
+		{"probe at budget", hintedOpenError{time.Second}, time.Second, true},
+		{"probe after budget", hintedOpenError{time.Minute}, time.Second, false},
+		{"negative hint", hintedOpenError{-time.Second}, 0, true},
+		{"wrapped hint", fmt.Errorf("call: %w", hintedOpenError{time.Second}), time.Minute, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := RetryableWithin(tt.err, tt.d); got != tt.want {
+				t.Errorf("RetryableWithin(%v, %v) = %v, want %v", tt.err, tt.d, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSleepUntilHalfOpen_Sleeps(t *testing.T) {
+	start := time.Now()
+	if err := SleepUntilHalfOpen(context.Background(), hintedOpenError{20 * time.Millisecond}); err != nil {
+		t.Fatalf("Expected nil after sleeping, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed < 20*time.Millisecond {
+		t.Errorf("Expected to sleep at least 20ms, slept %v", elapsed)
+	}
+}
+
+func TestSleepUntilHalfOpen_NoRetryInfo(t *testing.T) {
+	start := time.Now()
+	if err := SleepUntilHalfOpen(context.Background(), ErrOpenState); !errors.Is(err, ErrOpenState) {
+		t.Errorf("Expected ErrOpenState returned unchanged, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 10*time.Millisecond {
+		t.Errorf("Expected no sleep without retry info, slept %v", elapsed)
+	}
+}
+
+func TestSleepUntilHalfOpen_ContextCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		cancel()
+	}()
+
+	start := time.Now()
+	err := SleepUntilHalfOpen(ctx, hintedOpenError{time.Minute})
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected context.Canceled, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("Expected early return on cancel, slept %v", elapsed)
+	}
+}
+
+func TestSleepUntilHalfOpen_ContextAlreadyDone(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Nanosecond)
+	defer cancel()
+	<-ctx.Done()
+
+	if err := SleepUntilHalfOpen(ctx, hintedOpenError{0}); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected context.DeadlineExceeded, got %v", err)
+	}
+}
@@ -22,6 +22,8 @@ func (cb *CircuitBreaker) getInterval() time.Duration {
 
 func (cb *CircuitBreaker) setInterval(val time.Duration) {
 	cb.interval.Store(int64(val))
+	cb.intervalEnabled.Store(val > 0)
+	cb.refreshPlainClosed()
 }
 
 func (cb *CircuitBreaker) getTimeout() time.Duration {
@@ -49,3 +51,19 @@ func (cb *CircuitBreaker) getMinimumObservations() uint32 {
 func (cb *CircuitBreaker) setMinimumObservations(val uint32) {
 	cb.minimumObservations.Store(val)
 }
+
+func (cb *CircuitBreaker) getWarningThresholdFraction() float64 {
+	return math.Float64frombits(cb.warningThresholdFraction.Load())
+}
+
+func (cb *CircuitBreaker) setWarningThresholdFraction(val float64) {
+	cb.warningThresholdFraction.Store(math.Float64bits(val))
+}
+
+func (cb *CircuitBreaker) getRecoverFailureRate() float64 {
+	return math.Float64frombits(cb.recoverFailureRate.Load())
+}
+
+func (cb *CircuitBreaker) setRecoverFailureRate(val float64) {
+	cb.recoverFailureRate.Store(math.Float64bits(val))
+}
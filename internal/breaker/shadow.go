@@ -0,0 +1,15 @@
+package breaker
+
+// evaluateShadowTrip calls ShadowReadyToTrip with counts and records a shadow
+// trip the first time it returns true in the current observation window.
+func (cb *CircuitBreaker) evaluateShadowTrip(counts Counts) {
+	if cb.shadowReadyToTrip == nil {
+		return
+	}
+	if !safeCallShadowReadyToTrip(cb.name, cb.shadowReadyToTrip, counts) {
+		return
+	}
+	if cb.shadowTripped.CompareAndSwap(false, true) {
+		cb.eventCounter().shadowTrips.Add(1)
+	}
+}
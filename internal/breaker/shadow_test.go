@@ -0,0 +1,130 @@
+package breaker
+
+import (
+	"testing"
+	"time"
+)
+
+// twoConsecutiveFailures is a stricter shadow rule than DefaultReadyToTrip.
+func twoConsecutiveFailures(counts Counts) bool {
+	return counts.ConsecutiveFailures >= 2
+}
+
+// failTwiceThenSucceed drives traffic that trips twoConsecutiveFailures but
+// never the default rule.
+func failTwiceThenSucceed(cb *CircuitBreaker, rounds int) {
+	for i := 0; i < rounds; i++ {
+		cb.Execute(failFunc)
+		cb.Execute(failFunc)
+		cb.Execute(successFunc)
+	}
+}
+
+func TestShadowReadyToTrip_StricterShadowDiverges(t *testing.T) {
+	cb := New(Settings{
+		Name:              "shadow-stricter",
+		ShadowReadyToTrip: twoConsecutiveFailures,
+	})
+
+	failTwiceThenSucceed(cb, 5)
+
+	if cb.State() != StateClosed {
+		t.Fatalf("Expected live circuit Closed, got %v", cb.State())
+	}
+	diag := cb.Diagnostics()
+	if !diag.ShadowWouldTrip {
+		t.Error("Expected ShadowWouldTrip")
+	}
+	// Latched once for the window, not once per failure
+	if diag.ShadowTripCount != 1 {
+		t.Errorf("Expected ShadowTripCount 1, got %d", diag.ShadowTripCount)
+	}
+}
+
+func TestShadowReadyToTrip_CountsOncePerWindow(t *testing.T) {
+	cb := New(Settings{
+		Name:              "shadow-windows",
+		Interval:          time.Hour,
+		ShadowReadyToTrip: twoConsecutiveFailures,
+	})
+
+	for w := 0; w < 3; w++ {
+		failTwiceThenSucceed(cb, 2)
+		cb.clearIntervalCounts(cb.transitionEpoch()) // Interval boundary
+
+		if cb.Diagnostics().ShadowWouldTrip {
+			t.Fatalf("Window %d: expected ShadowWouldTrip re-armed by the reset", w)
+		}
+	}
+
+	if got := cb.Diagnostics().ShadowTripCount; got != 3 {
+		t.Errorf("Expected ShadowTripCount 3 over 3 windows, got %d", got)
+	}
+	if cb.State() != StateClosed {
+		t.Errorf("Expected live circuit Closed, got %v", cb.State())
+	}
+}
+
+func TestShadowReadyToTrip_EvaluatedWhenLiveTrips(t *testing.T) {
+	cb := New(tripOnFirstFailure(Settings{
+		Name:              "shadow-live-trip",
+		ShadowReadyToTrip: func(counts Counts) bool { return counts.TotalFailures >= 1 },
+	}))
+
+	cb.Execute(failFunc)
+
+	if cb.State() != StateOpen {
+		t.Fatalf("Expected live circuit Open, got %v", cb.State())
+	}
+	diag := cb.Diagnostics()
+	if diag.ShadowTripCount != 1 {
+		t.Errorf("Expected the shadow to agree with the live trip, got ShadowTripCount %d", diag.ShadowTripCount)
+	}
+	if diag.ShadowWouldTrip {
+		t.Error("Expected ShadowWouldTrip cleared with the counts on the transition")
+	}
+}
+
+func TestShadowReadyToTrip_LenientShadowNeverTrips(t *testing.T) {
+	cb := New(Settings{
+		Name:              "shadow-lenient",
+		ShadowReadyToTrip: func(counts Counts) bool { return false },
+	})
+
+	for i := 0; i < 6; i++ {
+		cb.Execute(failFunc)
+	}
+
+	if cb.State() != StateOpen {
+		t.Fatalf("Expected live circuit Open, got %v", cb.State())
+	}
+	if got := cb.Diagnostics().ShadowTripCount; got != 0 {
+		t.Errorf("Expected ShadowTripCount 0, got %d", got)
+	}
+}
+
+func TestShadowReadyToTrip_PanicDoesNotAffectLive(t *testing.T) {
+	cb := New(Settings{
+		Name:              "shadow-panic",
+		ShadowReadyToTrip: func(counts Counts) bool { panic("shadow panic") },
+	})
+
+	for i := 0; i < 6; i++ {
+		cb.Execute(failFunc)
+	}
+
+	if cb.State() != StateOpen {
+		t.Errorf("Expected live circuit to trip despite the shadow panic, got %v", cb.State())
+	}
+	if got := cb.Diagnostics().ShadowTripCount; got != 0 {
+		t.Errorf("Expected a panicking shadow to count as no trip, got %d", got)
+	}
+}
+
+func TestShadowReadyToTrip_InCurrentSettings(t *testing.T) {
+	cb := New(Settings{Name: "shadow-settings", ShadowReadyToTrip: twoConsecutiveFailures})
+
+	if cb.CurrentSettings().ShadowReadyToTrip == nil {
+		t.Error("Expected CurrentSettings to report ShadowReadyToTrip")
+	}
+}
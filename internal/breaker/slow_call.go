@@ -0,0 +1,32 @@
+package breaker
+
+import "time"
+
+// slowCallThreshold returns the latency above which a call is slow: the
+// learned p95 times SlowCallFactor. Returns 0 without SlowCallFactor or before
+// the p95 has been learned.
+func (cb *CircuitBreaker) slowCallThreshold() time.Duration {
+	if cb.slowCallFactor == 0 {
+		return 0
+	}
+	p95 := cb.latencyP95()
+	if p95 == 0 {
+		return 0
+	}
+	return time.Duration(float64(p95) * cb.slowCallFactor)
+}
+
+// slowCallWeight returns the failure weight of a call that took elapsed: weight
+// itself, or 1 if the call would count as a success but was slow. slow reports
+// the latter.
+func (cb *CircuitBreaker) slowCallWeight(weight float64, elapsed time.Duration) (_ float64, slow bool) {
+	if weight > outcomeWeightFailureCutoff {
+		return weight, false
+	}
+	threshold := cb.slowCallThreshold()
+	if threshold == 0 || elapsed <= threshold {
+		return weight, false
+	}
+	cb.eventCounter().slowCalls.Add(1)
+	return 1, true
+}
@@ -0,0 +1,98 @@
+package breaker
+
+import (
+	"testing"
+	"time"
+)
+
+// sleepFunc returns a request that succeeds after d.
+func sleepFunc(d time.Duration) func() (interface{}, error) {
+	return func() (interface{}, error) {
+		time.Sleep(d)
+		return "ok", nil
+	}
+}
+
+// learnBaseline feeds n observations of d into the latency histogram, as if n
+// calls had completed in d.
+func learnBaseline(cb *CircuitBreaker, n int, d time.Duration) {
+	for i := 0; i < n; i++ {
+		cb.latency.observe(d)
+	}
+}
+
+func TestSlowCallFactor_BurstIsSlow(t *testing.T) {
+	cb := New(Settings{Name: "slow-burst", SlowCallFactor: 1.5})
+
+	// Stable baseline around 3ms: p95 reports its 4ms bucket
+	learnBaseline(cb, 200, 3*time.Millisecond)
+	if got := cb.Diagnostics().SlowCallThreshold; got != 6*time.Millisecond {
+		t.Fatalf("Expected a 6ms threshold (4ms p95 x 1.5), got %v", got)
+	}
+
+	// Calls at the baseline are successes
+	cb.Execute(sleepFunc(time.Millisecond))
+	if got := cb.Counts(); got.TotalSuccesses != 1 || got.TotalFailures != 0 {
+		t.Fatalf("Expected a call at the baseline to succeed, got %+v", got)
+	}
+
+	// A 3x slower burst is slow
+	for i := 0; i < 3; i++ {
+		if _, err := cb.Execute(sleepFunc(10 * time.Millisecond)); err != nil {
+			t.Fatalf("Expected the result to be returned unchanged, got %v", err)
+		}
+	}
+	if got := cb.Counts(); got.TotalFailures != 3 {
+		t.Errorf("Expected the burst to be recorded as failures, got %+v", got)
+	}
+	if got := cb.Metrics().SlowCalls; got != 3 {
+		t.Errorf("Expected 3 slow calls, got %d", got)
+	}
+}
+
+func TestSlowCallFactor_BurstTrips(t *testing.T) {
+	cb := New(Settings{
+		Name:                 "slow-trip",
+		SlowCallFactor:       1.5,
+		AdaptiveThreshold:    true,
+		FailureRateThreshold: 0.2,
+		MinimumObservations:  10,
+	})
+	learnBaseline(cb, 200, 3*time.Millisecond)
+
+	for i := 0; i < 8; i++ {
+		cb.Execute(sleepFunc(time.Millisecond))
+	}
+	for i := 0; i < 5 && cb.State() == StateClosed; i++ {
+		cb.Execute(sleepFunc(10 * time.Millisecond))
+	}
+
+	if cb.State() != StateOpen {
+		t.Errorf("Expected the slow burst to trip the circuit, got %v (counts %+v)", cb.State(), cb.Counts())
+	}
+}
+
+func TestSlowCallFactor_NotBeforeBaseline(t *testing.T) {
+	cb := New(Settings{Name: "slow-unlearned", SlowCallFactor: 1})
+
+	cb.Execute(sleepFunc(5 * time.Millisecond))
+	if got := cb.Counts(); got.TotalSuccesses != 1 {
+		t.Errorf("Expected no slow classification before the p95 is learned, got %+v", got)
+	}
+	if got := cb.Diagnostics().SlowCallThreshold; got != 0 {
+		t.Errorf("Expected no threshold before the p95 is learned, got %v", got)
+	}
+}
+
+func TestSlowCallFactor_FailuresStayFailures(t *testing.T) {
+	cb := New(Settings{Name: "slow-failure", SlowCallFactor: 1})
+	learnBaseline(cb, 100, time.Millisecond)
+
+	cb.Execute(failFunc)
+	if got := cb.Metrics().SlowCalls; got != 0 {
+		t.Errorf("Expected a failure not to count as a slow call, got %d", got)
+	}
+	if !cb.CurrentSettings().TrackLatency {
+		t.Error("Expected SlowCallFactor to enable latency tracking")
+	}
+}
@@ -0,0 +1,102 @@
+package breaker
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestStartHalfOpen_InitialState(t *testing.T) {
+	before := time.Now().UnixNano()
+	cb := New(Settings{Name: "cold", StartHalfOpen: true})
+
+	if cb.State() != StateHalfOpen {
+		t.Fatalf("Expected half-open, got %v", cb.State())
+	}
+	if got := cb.stateChangedAt.Load(); got < before {
+		t.Errorf("Expected stateChangedAt set at construction, got %d", got)
+	}
+	if got := cb.openedAt.Load(); got != 0 {
+		t.Errorf("Expected openedAt 0 for a circuit that never opened, got %d", got)
+	}
+	if got := cb.lastClearedAt.Load(); got != cb.stateChangedAt.Load() {
+		t.Errorf("Expected lastClearedAt %d to match stateChangedAt, got %d", cb.stateChangedAt.Load(), got)
+	}
+	if reason := cb.Diagnostics().OpenReason; reason.Kind != OpenReasonNone {
+		t.Errorf("Expected no open reason, got %v", reason.Kind)
+	}
+	if !cb.CurrentSettings().StartHalfOpen {
+		t.Error("Expected CurrentSettings to report StartHalfOpen")
+	}
+}
+
+func TestStartHalfOpen_ProbeSuccessCloses(t *testing.T) {
+	var transitions []string
+	cb := New(Settings{
+		Name:          "cold",
+		StartHalfOpen: true,
+		OnStateChange: func(_ string, from, to State) {
+			transitions = append(transitions, from.String()+"->"+to.String())
+		},
+	})
+
+	if _, err := cb.Execute(successFunc); err != nil {
+		t.Fatalf("Expected probe to execute, got %v", err)
+	}
+	if cb.State() != StateClosed {
+		t.Errorf("Expected successful probe to close, got %v", cb.State())
+	}
+	if len(transitions) != 1 || transitions[0] != "half-open->closed" {
+		t.Errorf("Expected single half-open->closed transition, got %v", transitions)
+	}
+}
+
+func TestStartHalfOpen_ProbeFailureOpens(t *testing.T) {
+	cb := New(Settings{Name: "cold", Timeout: time.Minute, StartHalfOpen: true})
+
+	cb.Execute(failFunc)
+
+	if cb.State() != StateOpen {
+		t.Fatalf("Expected failed probe to open, got %v", cb.State())
+	}
+	if reason := cb.Diagnostics().OpenReason; reason.Kind != OpenReasonProbeFailed {
+		t.Errorf("Expected OpenReasonProbeFailed, got %v", reason.Kind)
+	}
+	if _, err := cb.Execute(successFunc); !errors.Is(err, ErrOpenState) {
+		t.Errorf("Expected ErrOpenState after failed probe, got %v", err)
+	}
+}
+
+func TestStartHalfOpen_AdmissionLimitedByMaxRequests(t *testing.T) {
+	cb := New(Settings{Name: "cold", StartHalfOpen: true})
+
+	release := make(chan struct{})
+	started := make(chan struct{})
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		cb.Execute(func() (interface{}, error) {
+			close(started)
+			<-release
+			return nil, nil
+		})
+	}()
+	<-started
+
+	// Normal traffic is held back while the mandatory probe is in flight
+	if _, err := cb.Execute(successFunc); !errors.Is(err, ErrTooManyRequests) {
+		t.Errorf("Expected ErrTooManyRequests while probe in flight, got %v", err)
+	}
+
+	close(release)
+	wg.Wait()
+
+	if cb.State() != StateClosed {
+		t.Fatalf("Expected probe to close the circuit, got %v", cb.State())
+	}
+	if _, err := cb.Execute(successFunc); err != nil {
+		t.Errorf("Expected normal traffic after probe, got %v", err)
+	}
+}
@@ -8,17 +8,22 @@ import (
 func (cb *CircuitBreaker) handleStateTransition(success bool, currentState State) {
 	switch currentState {
 	case StateClosed:
+		// Feed a custom decision engine before asking it
+		if cb.customEngine {
+			cb.engineOutcome(success)
+		}
+
 		// Only check for trip on failure (Closed → Open)
 		if !success {
 			cb.checkAndTripCircuit()
 		}
+		// Track the warn band, early warning and health (one comparison unless configured)
+		if cb.watchesRate {
+			cb.updateRateLatches()
+		}
 	case StateHalfOpen:
 		// Transition based on outcome (HalfOpen → Closed or Open)
-		if success {
-			cb.transitionToClosed()
-		} else {
-			cb.transitionBackToOpen()
-		}
+		cb.decideHalfOpen(success)
 	}
 }
 
@@ -26,114 +31,333 @@ func (cb *CircuitBreaker) handleStateTransition(success bool, currentState State
 func (cb *CircuitBreaker) checkAndTripCircuit() {
 	counts := cb.Counts()
 
+	// Evaluate the shadow rule on the same counts (never changes state)
+	cb.evaluateShadowTrip(counts)
+
+	cb.tripIfReady(counts)
+}
+
+// tripIfReady opens the circuit if the trip rule is met by counts, unless
+// MinClosedDuration defers the trip.
+func (cb *CircuitBreaker) tripIfReady(counts Counts) {
 	// Check if we should trip with panic recovery
-	shouldTrip := safeCallReadyToTrip(cb.name, cb.readyToTrip, counts)
+	if !cb.shouldTrip(counts) {
+		return
+	}
+	if cb.deferTrip() {
+		return
+	}
 
-	if !shouldTrip {
+	// Record why, using the counts that caused the trip
+	if cb.customEngine {
+		cb.openFromClosed(cb.engineTripReason(counts))
 		return
 	}
+	cb.openFromClosed(cb.tripReason(counts))
+}
 
+// openFromClosed transitions from Closed to Open, recording reason.
+// Returns false if another goroutine won the transition.
+func (cb *CircuitBreaker) openFromClosed(reason *OpenReason) bool {
 	// Attempt atomic state transition from Closed to Open
-	if !cb.state.CompareAndSwap(int32(StateClosed), int32(StateOpen)) {
-		return // Lost race, another goroutine already transitioned
+	epoch, ok := cb.commitTransition(StateClosed, StateOpen)
+	if !ok {
+		return false // Lost race, another goroutine already transitioned
 	}
 
 	// Successfully transitioned to Open
-	// Record the timestamp
-	now := time.Now().UnixNano()
+	cb.openReason.Store(reason)
+	cb.lastTrip.Store(reason)
+	if cb.hold != nil {
+		cb.hold.deferred.Store(false)
+	}
+
+	// Adaptive mode: backend is unhealthy until the rate recovers after
+	// re-closing. Static mode has no rate to recover, so health is the state.
+	if cb.adaptiveThreshold {
+		cb.unhealthy.Store(true)
+	}
+	cb.endRecovery()
+
+	// Record the timestamp (and the backend-requested end of the open period)
+	now := cb.now()
+	cb.openUntil.Store(cb.takeRetryAfter(now))
 	cb.openedAt.Store(now)
 	cb.stateChangedAt.Store(now)
+	cb.startIncident(now)
+	cb.beginEpisode(now, reason)
 
 	// Defensive reset: ensure halfOpenRequests is 0 when entering Open from Closed
 	cb.halfOpenRequests.Store(0)
 
-	// Clear counts
+	// Keep the outcomes that led here
+	cb.snapshotJournal(StateClosed)
+
+	// Forget the outcomes that tripped a custom engine
+	cb.resetEngine()
+
+	// Clear counts, keeping them for OnStateChangeDetailed
+	counts := cb.Counts()
 	cb.clearCounts()
 
-	// Call state change callback if configured with panic recovery
-	// Note: Callback sees zero counts (clearCounts called before callback)
-	safeCallOnStateChange(cb.name, cb.onStateChange, StateClosed, StateOpen)
+	// Call state change callbacks if configured with panic recovery
+	// Note: OnStateChange sees zero counts (clearCounts called before callback)
+	cb.notifyStateChange(epoch, StateClosed, StateOpen, counts)
+	return true
+}
+
+// notifyStateChange calls OnStateChange and then OnStateChangeDetailed, with
+// panic recovery, for the transition committed at epoch. counts are the counts
+// at the transition, before clearing. With OrderStateChanges the callbacks are
+// delivered in epoch order, possibly by another goroutine.
+func (cb *CircuitBreaker) notifyStateChange(epoch uint64, from, to State, counts Counts) {
+	ev := stateChangeEvent{from: from, to: to, counts: counts}
+	if cb.stateChanges != nil {
+		cb.stateChanges.dispatch(epoch, ev, cb.deliverStateChange)
+		return
+	}
+	cb.deliverStateChange(ev)
+}
+
+// deliverStateChange calls the state change callbacks for ev.
+func (cb *CircuitBreaker) deliverStateChange(ev stateChangeEvent) {
+	safeCallOnStateChange(cb.name, cb.onStateChange, ev.from, ev.to)
+	safeCallOnStateChangeDetailed(cb.name, cb.onStateChangeDetailed, ev.from, ev.to, ev.counts)
+	cb.notifyMirrors(ev.to)
+	cb.notifyViews()
 }
 
 // shouldTransitionToHalfOpen checks if timeout has elapsed since circuit opened.
 func (cb *CircuitBreaker) shouldTransitionToHalfOpen() bool {
+	// Fast path: before the cached end of the open period
+	if cb.openDeadlineCached() {
+		return false
+	}
+	gen := cb.openDeadlineGen.Load()
+
 	openedAt := cb.openedAt.Load()
 	if openedAt == 0 {
 		return false // Never opened
 	}
 
-	// Use monotonic clock for duration calculation to prevent issues from time jumps
-	openedTime := time.Unix(0, openedAt)
-	elapsed := time.Since(openedTime)
-	return elapsed >= cb.getTimeout()
+	// Keep failing fast during maintenance: probe outcomes would not be recorded
+	if cb.maintenance.Load() {
+		return false
+	}
+
+	// openedAt is on the monotonic timeline (see now), so wall-clock jumps
+	// cannot shorten or extend the open period
+	elapsed := time.Duration(cb.now() - openedAt)
+	wait := cb.openWait(openedAt)
+	if elapsed >= wait {
+		return cb.probeGateOpen()
+	}
+
+	// Reject without recomputing until the wait is over
+	cb.cacheOpenDeadline(gen, wait-elapsed)
+	return false
+}
+
+// openWait returns how long a circuit opened at openedAt (UnixNano) waits before
+// probing: the backend-requested backoff if one applied on opening (see
+// ExecuteWithHint), otherwise Timeout (the learned timeout under
+// AdaptiveTimeout), or less if IntervalResetsOpenState brings the next Interval
+// boundary forward.
+func (cb *CircuitBreaker) openWait(openedAt int64) time.Duration {
+	if until := cb.openUntil.Load(); until > 0 {
+		return time.Duration(until - openedAt)
+	}
+
+	timeout := cb.effectiveTimeout()
+	if !cb.intervalResetsOpen {
+		return timeout
+	}
+	interval := cb.getInterval()
+	if interval <= 0 {
+		return timeout
+	}
+
+	// Next boundary: Interval after opening, or the next aligned window start
+	wait := time.Duration(cb.windowStart(openedAt) + int64(interval) - openedAt)
+	if wait < timeout {
+		return wait
+	}
+	return timeout
 }
 
 // transitionToHalfOpen transitions from Open to HalfOpen state.
-func (cb *CircuitBreaker) transitionToHalfOpen() {
+// Returns false if another goroutine won the transition.
+func (cb *CircuitBreaker) transitionToHalfOpen() bool {
 	// Attempt atomic state transition from Open to HalfOpen
-	if !cb.state.CompareAndSwap(int32(StateOpen), int32(StateHalfOpen)) {
-		return // Lost race, another goroutine already transitioned
+	epoch, ok := cb.commitTransition(StateOpen, StateHalfOpen)
+	if !ok {
+		return false // Lost race, another goroutine already transitioned
 	}
 
 	// Successfully transitioned to HalfOpen
-	cb.stateChangedAt.Store(time.Now().UnixNano())
+	cb.stateChangedAt.Store(cb.now())
 
-	// Clear counts
+	// Clear counts, keeping them for OnStateChangeDetailed
+	counts := cb.Counts()
 	cb.clearCounts()
 
-	// Reset half-open request counter
+	// Reset half-open request counter, probe budget and verdicts for this episode
 	cb.halfOpenRequests.Store(0)
+	cb.halfOpenProbes.Store(0)
+	cb.probeSuccesses.Store(0)
+	cb.probeFailures.Store(0)
+	cb.awaitingEligible.Store(false)
+	cb.lastProbeKey.Store(nil)
+
+	// Call state change callbacks if configured with panic recovery
+	cb.notifyStateChange(epoch, StateOpen, StateHalfOpen, counts)
+	return true
+}
+
+// admitAfterTimeout attempts the Open → HalfOpen transition once Timeout has
+// elapsed and reports whether the caller may proceed as a probe. The winner of the
+// transition always proceeds; losers proceed unless TransitionLoserBehavior is
+// TransitionLoserReject.
+func (cb *CircuitBreaker) admitAfterTimeout() bool {
+	if cb.transitionToHalfOpen() {
+		return true
+	}
+	return cb.transitionLoserBehavior != TransitionLoserReject
+}
+
+// tryAcquireProbeSlot reserves one of the MaxRequests concurrent half-open slots
+// and, if HalfOpenMaxProbes is set, one execution from the probe budget.
+// Returns false and records a probe rejection if all slots are in use or the
+// budget is exhausted. With HalfOpenProbeTimeout set, the returned lease is
+// watched so a probe that never returns cannot hold its slot forever. key is
+// the probe's fairness key (see ExecuteOpts.FairnessKey), or empty.
+// The caller must release an acquired slot with releaseProbeSlot.
+func (cb *CircuitBreaker) tryAcquireProbeSlot(key string) (*probeLease, bool) {
+	current := cb.halfOpenRequests.Add(1)
+	if current > int32(cb.getMaxRequests()) {
+		cb.halfOpenRequests.Add(-1) // Undo increment
+		cb.eventCounter().probeRejections.Add(1)
+		return nil, false
+	}
+	if !cb.tryConsumeProbe() {
+		cb.halfOpenRequests.Add(-1) // Release slot
+		cb.eventCounter().probeRejections.Add(1)
+		return nil, false
+	}
+
+	cb.awaitingEligible.Store(false)
+	cb.rememberProbeKey(key)
+	cb.episodeProbeAdmitted()
+
+	// First probe in flight: start the clock reported by TooManyRequestsError
+	if cb.probesInFlight.Add(1) == 1 {
+		cb.probeStartedAt.Store(cb.now())
+	}
+	return cb.watchProbe(cb.transitionEpoch()), true
+}
 
-	// Call state change callback if configured with panic recovery
-	safeCallOnStateChange(cb.name, cb.onStateChange, StateOpen, StateHalfOpen)
+// releaseProbeSlot releases a slot acquired with tryAcquireProbeSlot, unless
+// the HalfOpenProbeTimeout watchdog already released it.
+func (cb *CircuitBreaker) releaseProbeSlot(lease *probeLease) {
+	if lease != nil {
+		cb.watchdog.leases.Delete(lease)
+	}
+	if !lease.release() {
+		return
+	}
+	cb.probesInFlight.Add(-1)
+	cb.halfOpenRequests.Add(-1)
 }
 
 // transitionToClosed transitions from HalfOpen to Closed state (recovery).
 func (cb *CircuitBreaker) transitionToClosed() {
 	// Attempt atomic state transition from HalfOpen to Closed
-	if !cb.state.CompareAndSwap(int32(StateHalfOpen), int32(StateClosed)) {
+	epoch, ok := cb.commitTransition(StateHalfOpen, StateClosed)
+	if !ok {
 		return // Lost race, another goroutine already transitioned
 	}
 
 	// Successfully transitioned to Closed (recovery complete)
-	now := time.Now().UnixNano()
+	now := cb.now()
 	cb.stateChangedAt.Store(now)
 
+	// Learn from this incident before its timestamps are cleared
+	cb.learnRecovery(cb.incidentStart(), cb.openedAt.Load(), now)
+
 	// Clear openedAt timestamp (circuit is no longer open)
 	// This ensures clean state and prevents stale timestamp issues
 	cb.openedAt.Store(0)
 
-	// Clear counts
+	// Recovery complete, forget why the circuit was open
+	cb.openReason.Store(nil)
+	cb.endIncident()
+	cb.endEpisode(now)
+	cb.cycleAdmitted.Store(0)
+	cb.holdClosed(now)
+
+	// Probe budget applies to HalfOpen only
+	cb.halfOpenProbes.Store(0)
+
+	// Require RecoveryWindows clean windows before the backend counts as recovered
+	cb.beginRecovery()
+
+	// Judge the recovered backend on new outcomes only
+	cb.resetEngine()
+
+	// Clear counts, keeping them for OnStateChangeDetailed
+	counts := cb.Counts()
 	cb.clearCounts()
 
-	// Reset last cleared timestamp
-	cb.lastClearedAt.Store(now)
+	// Reset last cleared timestamp (aligned to the window boundary if configured)
+	cb.lastClearedAt.Store(cb.windowStart(now))
 
-	// Call state change callback if configured with panic recovery
-	// Note: Callback sees zero counts (clearCounts called before callback)
-	safeCallOnStateChange(cb.name, cb.onStateChange, StateHalfOpen, StateClosed)
+	// Call state change callbacks if configured with panic recovery
+	// Note: OnStateChange sees zero counts (clearCounts called before callback)
+	cb.notifyStateChange(epoch, StateHalfOpen, StateClosed, counts)
 }
 
 // transitionBackToOpen transitions from HalfOpen back to Open (failed recovery).
 func (cb *CircuitBreaker) transitionBackToOpen() {
+	// Record why (distinct from a threshold trip)
+	cb.reopenFromHalfOpen(&OpenReason{
+		Kind:   OpenReasonProbeFailed,
+		Detail: "half-open probe failed",
+		Counts: cb.Counts(),
+	})
+}
+
+// reopenFromHalfOpen transitions from HalfOpen back to Open, recording reason.
+func (cb *CircuitBreaker) reopenFromHalfOpen(reason *OpenReason) {
 	// Attempt atomic state transition from HalfOpen to Open
-	if !cb.state.CompareAndSwap(int32(StateHalfOpen), int32(StateOpen)) {
+	epoch, ok := cb.commitTransition(StateHalfOpen, StateOpen)
+	if !ok {
 		return // Lost race, another goroutine already transitioned
 	}
 
 	// Successfully transitioned back to Open
-	// Record new open timestamp
-	now := time.Now().UnixNano()
+	cb.openReason.Store(reason)
+
+	// Record new open timestamp (and the backend-requested end of the open period)
+	now := cb.now()
+	cb.openUntil.Store(cb.takeRetryAfter(now))
 	cb.openedAt.Store(now)
 	cb.stateChangedAt.Store(now)
 
+	// A failed probe continues the incident (or starts one after StartHalfOpen)
+	cb.startIncident(now)
+
 	// Defensive reset: ensure halfOpenRequests is 0 when re-entering Open
 	cb.halfOpenRequests.Store(0)
+	cb.halfOpenProbes.Store(0)
 
-	// Clear counts
+	// Keep the outcomes that led here
+	cb.snapshotJournal(StateHalfOpen)
+
+	// Clear counts, keeping them for OnStateChangeDetailed
+	counts := cb.Counts()
 	cb.clearCounts()
 
-	// Call state change callback if configured with panic recovery
-	// Note: Callback sees zero counts (clearCounts called before callback)
-	safeCallOnStateChange(cb.name, cb.onStateChange, StateHalfOpen, StateOpen)
+	// Call state change callbacks if configured with panic recovery
+	// Note: OnStateChange sees zero counts (clearCounts called before callback)
+	cb.notifyStateChange(epoch, StateHalfOpen, StateOpen, counts)
 }
@@ -0,0 +1,171 @@
+package breaker
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+// stateChange is one OnStateChangeDetailed invocation.
+type stateChange struct {
+	from, to State
+	counts   Counts
+}
+
+// recordStateChanges returns settings that record OnStateChangeDetailed calls.
+func recordStateChanges(s Settings) (Settings, func() []stateChange) {
+	var mu sync.Mutex
+	var changes []stateChange
+	s.OnStateChangeDetailed = func(name string, from, to State, counts Counts) {
+		mu.Lock()
+		defer mu.Unlock()
+		changes = append(changes, stateChange{from, to, counts})
+	}
+	return s, func() []stateChange {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]stateChange(nil), changes...)
+	}
+}
+
+func TestOnStateChangeDetailed_TripCounts(t *testing.T) {
+	var plainCounts Counts
+	var cb *CircuitBreaker
+	settings, changes := recordStateChanges(Settings{
+		Name: "detailed",
+		ReadyToTrip: func(counts Counts) bool {
+			return counts.ConsecutiveFailures >= 3
+		},
+		OnStateChange: func(name string, from, to State) {
+			plainCounts = cb.Counts()
+		},
+	})
+	cb = New(settings)
+
+	cb.Execute(successFunc)
+	cb.Execute(successFunc)
+	for i := 0; i < 3; i++ {
+		cb.Execute(failFunc)
+	}
+
+	got := changes()
+	if len(got) != 1 {
+		t.Fatalf("Expected 1 state change, got %d: %+v", len(got), got)
+	}
+	if got[0].from != StateClosed || got[0].to != StateOpen {
+		t.Errorf("Expected Closed → Open, got %v → %v", got[0].from, got[0].to)
+	}
+	want := Counts{
+		Requests:            5,
+		TotalSuccesses:      2,
+		TotalFailures:       3,
+		ConsecutiveFailures: 3,
+	}
+	if got[0].counts != want {
+		t.Errorf("countsAtTransition = %+v, want %+v", got[0].counts, want)
+	}
+
+	// OnStateChange still sees the cleared counts
+	if plainCounts != (Counts{}) {
+		t.Errorf("Expected OnStateChange to see zero counts, got %+v", plainCounts)
+	}
+}
+
+func TestOnStateChangeDetailed_ProbeCounts(t *testing.T) {
+	settings, changes := recordStateChanges(tripOnFirstFailure(Settings{
+		Name:    "detailed-probe",
+		Timeout: 10 * time.Millisecond,
+	}))
+	cb := New(settings)
+
+	cb.Execute(failFunc)
+	time.Sleep(20 * time.Millisecond)
+	cb.Execute(successFunc)
+
+	got := changes()
+	if len(got) != 3 {
+		t.Fatalf("Expected 3 state changes, got %d: %+v", len(got), got)
+	}
+	if got[1].from != StateOpen || got[1].to != StateHalfOpen {
+		t.Errorf("Expected Open → HalfOpen, got %v → %v", got[1].from, got[1].to)
+	}
+	if got[1].counts != (Counts{}) {
+		t.Errorf("Expected zero counts leaving Open, got %+v", got[1].counts)
+	}
+	if got[2].from != StateHalfOpen || got[2].to != StateClosed {
+		t.Errorf("Expected HalfOpen → Closed, got %v → %v", got[2].from, got[2].to)
+	}
+	want := Counts{Requests: 1, TotalSuccesses: 1, ConsecutiveSuccesses: 1}
+	if got[2].counts != want {
+		t.Errorf("countsAtTransition = %+v, want %+v", got[2].counts, want)
+	}
+}
+
+func TestOnStateChangeDetailed_FailedProbeCounts(t *testing.T) {
+	settings, changes := recordStateChanges(tripOnFirstFailure(Settings{
+		Name:    "detailed-reopen",
+		Timeout: 10 * time.Millisecond,
+	}))
+	cb := New(settings)
+
+	cb.Execute(failFunc)
+	time.Sleep(20 * time.Millisecond)
+	cb.Execute(failFunc)
+
+	got := changes()
+	if len(got) != 3 {
+		t.Fatalf("Expected 3 state changes, got %d: %+v", len(got), got)
+	}
+	if got[2].from != StateHalfOpen || got[2].to != StateOpen {
+		t.Errorf("Expected HalfOpen → Open, got %v → %v", got[2].from, got[2].to)
+	}
+	want := Counts{Requests: 1, TotalFailures: 1, ConsecutiveFailures: 1}
+	if got[2].counts != want {
+		t.Errorf("countsAtTransition = %+v, want %+v", got[2].counts, want)
+	}
+}
+
+func TestOnStateChangeDetailed_CalledAfterOnStateChange(t *testing.T) {
+	var order []string
+	cb := New(tripOnFirstFailure(Settings{
+		Name: "detailed-order",
+		OnStateChange: func(name string, from, to State) {
+			order = append(order, "plain")
+		},
+		OnStateChangeDetailed: func(name string, from, to State, counts Counts) {
+			order = append(order, "detailed")
+		},
+	}))
+
+	cb.Execute(failFunc)
+
+	if len(order) != 2 || order[0] != "plain" || order[1] != "detailed" {
+		t.Errorf("Expected [plain detailed], got %v", order)
+	}
+}
+
+func TestOnStateChangeDetailed_PanicDoesNotBlockTransition(t *testing.T) {
+	cb := New(tripOnFirstFailure(Settings{
+		Name: "detailed-panic",
+		OnStateChangeDetailed: func(name string, from, to State, counts Counts) {
+			panic("callback panic")
+		},
+	}))
+
+	cb.Execute(failFunc)
+
+	if cb.State() != StateOpen {
+		t.Errorf("Expected Open despite callback panic, got %v", cb.State())
+	}
+}
+
+func TestOnStateChangeDetailed_InCurrentSettings(t *testing.T) {
+	cb := New(Settings{
+		Name:                  "detailed-settings",
+		OnStateChangeDetailed: func(name string, from, to State, counts Counts) {},
+	})
+
+	if cb.CurrentSettings().OnStateChangeDetailed == nil {
+		t.Error("Expected CurrentSettings to report OnStateChangeDetailed")
+	}
+}
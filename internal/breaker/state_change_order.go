@@ -0,0 +1,59 @@
+package breaker
+
+import "sync"
+
+// stateChangeEvent is one committed transition awaiting its callbacks.
+type stateChangeEvent struct {
+	from, to State
+	counts   Counts
+}
+
+// stateChangeDispatcher delivers state change events in transition order for
+// OrderStateChanges.
+//
+// Events are keyed by their transition epoch, which commitTransition assigns in
+// commit order. Whichever goroutine finds no dispatch running becomes the
+// dispatcher and delivers events while the next epoch is present; a gap (a
+// transition committed but not yet queued) ends the run, and the goroutine
+// queuing the missing event resumes it. No goroutine ever waits for another.
+type stateChangeDispatcher struct {
+	mu          sync.Mutex
+	pending     map[uint64]stateChangeEvent
+	next        uint64 // Epoch of the next event to deliver
+	dispatching bool
+}
+
+func newStateChangeDispatcher(epoch uint64) *stateChangeDispatcher {
+	return &stateChangeDispatcher{
+		pending: make(map[uint64]stateChangeEvent),
+		next:    epoch + 1,
+	}
+}
+
+// dispatch queues the event committed at epoch and, unless another goroutine is
+// already dispatching, delivers queued events in order.
+func (d *stateChangeDispatcher) dispatch(epoch uint64, ev stateChangeEvent, deliver func(stateChangeEvent)) {
+	d.mu.Lock()
+	d.pending[epoch] = ev
+	if d.dispatching {
+		d.mu.Unlock()
+		return // The running dispatcher delivers it in turn
+	}
+	d.dispatching = true
+
+	for {
+		ev, ok := d.pending[d.next]
+		if !ok {
+			d.dispatching = false
+			d.mu.Unlock()
+			return
+		}
+		delete(d.pending, d.next)
+		d.next++
+		d.mu.Unlock()
+
+		deliver(ev) // Callbacks are panic-safe
+
+		d.mu.Lock()
+	}
+}
@@ -0,0 +1,164 @@
+package breaker
+
+import (
+	"runtime"
+	"sync"
+	"testing"
+	"time"
+)
+
+// recordDelivered returns a deliver func for stateChangeDispatcher that records
+// event destinations, and a func returning them.
+func recordDelivered() (func(stateChangeEvent), func() []State) {
+	var mu sync.Mutex
+	var got []State
+	return func(ev stateChangeEvent) {
+			mu.Lock()
+			defer mu.Unlock()
+			got = append(got, ev.to)
+		}, func() []State {
+			mu.Lock()
+			defer mu.Unlock()
+			return append([]State(nil), got...)
+		}
+}
+
+func TestStateChangeDispatcher_WaitsForGap(t *testing.T) {
+	d := newStateChangeDispatcher(0)
+	deliver, delivered := recordDelivered()
+
+	// Epoch 2 is queued before epoch 1: nothing can be delivered yet
+	d.dispatch(2, stateChangeEvent{from: StateOpen, to: StateHalfOpen}, deliver)
+	if got := delivered(); len(got) != 0 {
+		t.Fatalf("Expected no delivery before epoch 1, got %v", got)
+	}
+
+	// Queuing the missing epoch delivers both, in order
+	d.dispatch(1, stateChangeEvent{from: StateClosed, to: StateOpen}, deliver)
+	got := delivered()
+	if len(got) != 2 || got[0] != StateOpen || got[1] != StateHalfOpen {
+		t.Errorf("Expected [Open HalfOpen], got %v", got)
+	}
+}
+
+func TestStateChangeDispatcher_NestedDispatchQueued(t *testing.T) {
+	d := newStateChangeDispatcher(0)
+	var order []State
+	var deliver func(stateChangeEvent)
+	deliver = func(ev stateChangeEvent) {
+		order = append(order, ev.to)
+		if ev.to == StateOpen {
+			// A transition from inside a callback must not deadlock
+			d.dispatch(2, stateChangeEvent{from: StateOpen, to: StateHalfOpen}, deliver)
+			if len(order) != 1 {
+				t.Error("Expected nested event delivered after the current callback")
+			}
+		}
+	}
+
+	d.dispatch(1, stateChangeEvent{from: StateClosed, to: StateOpen}, deliver)
+
+	if len(order) != 2 || order[0] != StateOpen || order[1] != StateHalfOpen {
+		t.Errorf("Expected [Open HalfOpen], got %v", order)
+	}
+}
+
+func TestOrderStateChanges_NestedTransitionFromCallback(t *testing.T) {
+	var cb *CircuitBreaker
+	settings, changes := recordStateChanges(tripOnFirstFailure(Settings{
+		Name:              "ordered-nested",
+		Timeout:           time.Hour,
+		OrderStateChanges: true,
+	}))
+	detailed := settings.OnStateChangeDetailed
+	settings.OnStateChangeDetailed = func(name string, from, to State, counts Counts) {
+		detailed(name, from, to, counts)
+		if to == StateOpen {
+			cb.transitionToHalfOpen() // Commits while this callback runs
+		}
+	}
+	cb = New(settings)
+
+	cb.Execute(failFunc)
+
+	got := changes()
+	if len(got) != 2 {
+		t.Fatalf("Expected 2 state changes, got %d: %+v", len(got), got)
+	}
+	if got[0].to != StateOpen || got[1].from != StateOpen || got[1].to != StateHalfOpen {
+		t.Errorf("Expected Closed → Open → HalfOpen, got %+v", got)
+	}
+}
+
+func TestOrderStateChanges_StressValidChain(t *testing.T) {
+	const (
+		goroutines  = 16
+		transitions = 3000
+	)
+	settings, changes := recordStateChanges(tripOnFirstFailure(Settings{
+		Name:              "ordered-stress",
+		Timeout:           time.Nanosecond,
+		MaxRequests:       goroutines,
+		OrderStateChanges: true,
+	}))
+	record := settings.OnStateChangeDetailed
+	settings.OnStateChangeDetailed = func(name string, from, to State, counts Counts) {
+		runtime.Gosched() // Widen the window for a later transition to report first
+		record(name, from, to, counts)
+	}
+	cb := New(settings)
+
+	// Failed probes reopen and successful probes close, within microseconds
+	deadline := time.Now().Add(5 * time.Second)
+	var wg sync.WaitGroup
+	for g := 0; g < goroutines; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			for i := 0; cb.transitionEpoch() < transitions && time.Now().Before(deadline); i++ {
+				if (i+g)%2 == 0 {
+					cb.Execute(failFunc)
+				} else {
+					cb.Execute(successFunc)
+				}
+			}
+		}(g)
+	}
+	wg.Wait()
+
+	got := changes()
+	if uint64(len(got)) != cb.transitionEpoch() {
+		t.Fatalf("Expected one event per transition (%d), got %d", cb.transitionEpoch(), len(got))
+	}
+	if testing.Short() && len(got) < 100 || !testing.Short() && len(got) < transitions/2 {
+		t.Fatalf("Expected rapid transitions, got only %d", len(got))
+	}
+
+	prev := StateClosed
+	for i, ev := range got {
+		if ev.from != prev {
+			t.Fatalf("Event %d: from %v, but the previous event ended in %v (%+v)",
+				i, ev.from, prev, got[max(0, i-3):i+1])
+		}
+		prev = ev.to
+	}
+	if prev != cb.State() {
+		t.Errorf("Last event ended in %v, but the circuit is %v", prev, cb.State())
+	}
+}
+
+func TestOrderStateChanges_DisabledByDefault(t *testing.T) {
+	cb := New(Settings{Name: "unordered"})
+
+	if cb.stateChanges != nil {
+		t.Error("Expected no dispatcher without OrderStateChanges")
+	}
+}
+
+func TestOrderStateChanges_InCurrentSettings(t *testing.T) {
+	cb := New(Settings{Name: "ordered-settings", OrderStateChanges: true})
+
+	if !cb.CurrentSettings().OrderStateChanges {
+		t.Error("Expected CurrentSettings to report OrderStateChanges")
+	}
+}
@@ -0,0 +1,356 @@
+package breaker
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"sync/atomic"
+	"time"
+)
+
+// defaultStreamTimeout is the StreamTimeout used when it is not set.
+const defaultStreamTimeout = time.Minute
+
+// ErrStreamClosedEarly is the error classified by IsStreamFailure when a stream
+// returned by ExecuteStream or ExecuteHTTP is closed before reaching EOF.
+var ErrStreamClosedEarly = errors.New("autobreaker: stream closed before EOF")
+
+// TransportStreamFailure is an IsStreamFailure classifier that counts only
+// transport failures: a truncated stream (io.ErrUnexpectedEOF) or a network
+// error. Closing a stream early and application errors are not failures.
+//
+// Example:
+//
+//	breaker := autobreaker.New(autobreaker.Settings{
+//	    Name:            "downloads",
+//	    IsStreamFailure: autobreaker.TransportStreamFailure,
+//	})
+func TransportStreamFailure(err error) bool {
+	if errors.Is(err, io.ErrUnexpectedEOF) {
+		return true
+	}
+	var netErr net.Error
+	return errors.As(err, &netErr)
+}
+
+// ExecuteStream runs req like Execute, for calls whose result is a stream: the
+// outcome is recorded when the stream finishes, not when req returns.
+//
+// Admission is unchanged (ErrOpenState, ErrTooManyRequests, MaxConcurrent and
+// so on). If req returns an error, the outcome is recorded immediately, as in
+// Execute. Otherwise the returned ReadCloser wraps the stream, and the call
+// completes when the stream does:
+//
+//   - A Read reaching io.EOF records a success
+//   - A Read failing with another error records that error
+//   - Close before EOF records ErrStreamClosedEarly
+//
+// Errors are classified with IsStreamFailure (by default every error is a
+// failure); an error it rejects is recorded as neither success nor failure.
+// Until the stream completes, the call holds its HalfOpen slot (so a probe is
+// decided by its body) and its MaxConcurrent slot.
+//
+// A stream still open StreamTimeout after req returned is detached: its slots
+// are released and its outcome is not recorded, so a leaked or very long
+// stream cannot block recovery. The stream itself stays usable.
+//
+// Thread-safe: Safe to call concurrently. A single returned stream follows the
+// io.ReadCloser contract and must not be read concurrently.
+//
+// Example:
+//
+//	body, err := breaker.ExecuteStream(func() (io.ReadCloser, error) {
+//	    return storage.Open(ctx, key)
+//	})
+//	if err != nil {
+//	    return err
+//	}
+//	defer body.Close()
+//	_, err = io.Copy(dst, body) // A failure halfway through reaches the breaker
+func (cb *CircuitBreaker) ExecuteStream(req func() (io.ReadCloser, error)) (io.ReadCloser, error) {
+	call, err := cb.admitStream()
+	if err != nil {
+		return nil, err
+	}
+	if call == nil {
+		return runDisabledStream(cb, req)
+	}
+
+	var body io.ReadCloser
+	_, err = call.run(func() (interface{}, error) {
+		var err error
+		body, err = req()
+		return body, err
+	})
+	if call.done.Load() {
+		return nil, err // Panicked (RecoverPanics), already recorded
+	}
+	if err != nil || body == nil {
+		call.record(body, err)
+		return body, err
+	}
+	return call.track(body), nil
+}
+
+// ExecuteHTTP is ExecuteStream for HTTP calls: the response is classified with
+// IsSuccessful or OutcomeWeight when req returns, like Execute, and if it counts
+// as a success, the outcome is deferred to the end of the response body, as in
+// ExecuteStream. A failed response (a 5xx under a status-aware OutcomeWeight) is
+// recorded immediately and its body is returned untracked.
+//
+// Example - RoundTripper:
+//
+//	func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+//	    return t.breaker.ExecuteHTTP(func() (*http.Response, error) {
+//	        return t.next.RoundTrip(req)
+//	    })
+//	}
+func (cb *CircuitBreaker) ExecuteHTTP(req func() (*http.Response, error)) (*http.Response, error) {
+	call, err := cb.admitStream()
+	if err != nil {
+		return nil, err
+	}
+	if call == nil {
+		var resp *http.Response
+		_, err := cb.runDisabled(func() (interface{}, error) {
+			var err error
+			resp, err = req()
+			return resp, err
+		})
+		return resp, err
+	}
+
+	var resp *http.Response
+	_, err = call.run(func() (interface{}, error) {
+		var err error
+		resp, err = req()
+		return resp, err
+	})
+	if call.done.Load() {
+		return nil, err // Panicked (RecoverPanics), already recorded
+	}
+	if err != nil || resp == nil || resp.Body == nil {
+		call.record(resp, err)
+		return resp, err
+	}
+
+	weight, ok := cb.failureWeightOf(resp, nil)
+	if !ok || weight > outcomeWeightFailureCutoff {
+		call.recordWeight(weight, ok, resp, nil)
+		return resp, nil
+	}
+	resp.Body = call.track(resp.Body)
+	return resp, nil
+}
+
+// streamCall is a call admitted by admitStream whose outcome may be recorded
+// after it returns. It holds the HalfOpen and MaxConcurrent slots until done.
+type streamCall struct {
+	admission
+	cb    *CircuitBreaker
+	start time.Time
+	timer atomic.Pointer[time.Timer] // StreamTimeout timer, set by track
+	done  atomic.Bool                // Outcome recorded (or discarded) and slots released
+}
+
+// admitStream runs the admission checks of Execute and reserves the slots for
+// a stream call. Returns a nil call while disabled: the call runs unprotected.
+func (cb *CircuitBreaker) admitStream() (*streamCall, error) {
+	a := admission{opts: anyProbe}
+	if err := cb.admit(&a); err != nil {
+		return nil, err
+	}
+	if a.unprotected {
+		return nil, nil
+	}
+	return &streamCall{admission: a, cb: cb, start: time.Now()}, nil
+}
+
+// run invokes req. A panic is recorded as a failure and completes the call,
+// then is re-raised or, with RecoverPanics, returned as a *PanicError.
+func (c *streamCall) run(req func() (interface{}, error)) (result interface{}, err error) {
+	cb := c.cb
+	defer func() {
+		if r := recover(); r != nil {
+			c.complete(func() {
+				if cb.maintenance.Load() || cb.Disabled() {
+					cb.discardOutcome(c.requestCounted, c.state)
+				} else if !c.lease.expired() {
+					cb.recordOutcome(false)
+					cb.recordPanic(r)
+					cb.handleStateTransition(false, c.state)
+				}
+			})
+
+			if cb.recoverPanics {
+				result, err = nil, newPanicError(r)
+				return
+			}
+			panic(r)
+		}
+	}()
+	return cb.invoke(context.Background(), req)
+}
+
+// record classifies and records a call that completed when req returned.
+func (c *streamCall) record(result interface{}, err error) {
+	cb := c.cb
+	switch {
+	case errors.Is(err, ErrIgnoreOutcome):
+		c.discard(stripIgnoreOutcome(err))
+	case err != nil && !cb.countNestedRejections && isNestedRejection(err):
+		c.discard(err)
+	default:
+		weight, ok := cb.failureWeightOf(result, err)
+		c.recordWeight(weight, ok, result, err)
+	}
+}
+
+// recordWeight records a completed call with the given failure weight; ok is
+// false if the classifier panicked under ClassifierPanicIgnore.
+func (c *streamCall) recordWeight(weight float64, ok bool, result interface{}, err error) {
+	if !ok {
+		c.discard(err)
+		return
+	}
+	c.complete(func() {
+		elapsed := time.Since(c.start)
+		if c.cb.trackLatency {
+			c.cb.recordLatency(elapsed)
+		}
+		c.cb.completeOutcome(weight, c.state, result, err, elapsed)
+	})
+}
+
+// discard completes the call without recording an outcome.
+func (c *streamCall) discard(err error) {
+	c.complete(func() {
+		c.cb.discardOutcome(c.requestCounted, c.state)
+		c.cb.journalIgnored(c.requestCounted, err)
+	})
+}
+
+// track wraps body so the call completes when the stream does, and starts the
+// StreamTimeout safety timer.
+func (c *streamCall) track(body io.ReadCloser) io.ReadCloser {
+	timeout := c.cb.streamTimeout
+	if timeout == 0 {
+		timeout = defaultStreamTimeout
+	}
+	c.timer.Store(time.AfterFunc(timeout, c.detach))
+	return &trackedStream{body: body, call: c}
+}
+
+// finish records the outcome of a tracked stream: success on EOF (streamErr
+// nil), otherwise streamErr classified by IsStreamFailure.
+func (c *streamCall) finish(streamErr error) {
+	cb := c.cb
+	if streamErr != nil && !cb.isStreamFailure(streamErr) {
+		c.discard(streamErr)
+		return
+	}
+	c.complete(func() {
+		elapsed := time.Since(c.start)
+		if cb.trackLatency {
+			cb.recordLatency(elapsed)
+		}
+		cb.completeOutcome(overrideWeight(streamErr == nil), c.state, nil, streamErr, elapsed)
+	})
+}
+
+// detach gives up on a stream still open after StreamTimeout.
+func (c *streamCall) detach() {
+	if c.done.Load() {
+		return
+	}
+	c.complete(func() {
+		c.cb.eventCounter().streamTimeouts.Add(1)
+		c.cb.discardOutcome(c.requestCounted, c.state)
+	})
+}
+
+// complete runs recordFn and releases the call's slots, once: a second
+// completion is a no-op. Outcomes during maintenance or while disabled, of a
+// saturated request counter, of a probe the HalfOpenProbeTimeout watchdog
+// abandoned, or of a call that outlived its window are discarded instead.
+func (c *streamCall) complete(recordFn func()) {
+	if !c.done.CompareAndSwap(false, true) {
+		return
+	}
+	if timer := c.timer.Load(); timer != nil {
+		timer.Stop()
+	}
+
+	cb := c.cb
+	switch {
+	case c.lease.expired():
+		// The watchdog already recorded the probe as a failure
+	case cb.lateOutcome(c.gen):
+		// The window the call belonged to is gone (counted in LateOutcomes)
+	case cb.maintenance.Load() || cb.Disabled():
+		cb.discardOutcome(c.requestCounted, c.state)
+	case !c.requestCounted:
+		// Counter saturated: the call is not recorded, as in Execute
+	default:
+		recordFn()
+	}
+
+	cb.release(&c.admission)
+}
+
+// isStreamFailure classifies a stream error with IsStreamFailure (panic-safe;
+// a panicking classifier counts the error as a failure).
+func (cb *CircuitBreaker) isStreamFailure(err error) (failure bool) {
+	if cb.streamFailure == nil {
+		return true
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			failure = true
+		}
+	}()
+	return cb.streamFailure(err)
+}
+
+// trackedStream is the ReadCloser returned by ExecuteStream and ExecuteHTTP.
+type trackedStream struct {
+	body io.ReadCloser
+	call *streamCall
+	eof  bool
+}
+
+// Read reads from the stream, completing the call on EOF or a read error.
+func (s *trackedStream) Read(p []byte) (int, error) {
+	n, err := s.body.Read(p)
+	switch {
+	case err == io.EOF:
+		s.eof = true
+		s.call.finish(nil)
+	case err != nil:
+		s.call.finish(err)
+	}
+	return n, err
+}
+
+// Close closes the stream. Closing before EOF completes the call with
+// ErrStreamClosedEarly.
+func (s *trackedStream) Close() error {
+	err := s.body.Close()
+	if !s.eof {
+		s.call.finish(ErrStreamClosedEarly)
+	}
+	return err
+}
+
+// runDisabledStream runs req unprotected while the breaker is disabled.
+func runDisabledStream(cb *CircuitBreaker, req func() (io.ReadCloser, error)) (io.ReadCloser, error) {
+	var body io.ReadCloser
+	_, err := cb.runDisabled(func() (interface{}, error) {
+		var err error
+		body, err = req()
+		return body, err
+	})
+	return body, err
+}
@@ -0,0 +1,269 @@
+package breaker
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+// failingReader yields n bytes of data, then fails with err (io.EOF for a
+// clean end).
+type failingReader struct {
+	n      int
+	err    error
+	closed bool
+}
+
+func (r *failingReader) Read(p []byte) (int, error) {
+	if r.n == 0 {
+		return 0, r.err
+	}
+	if len(p) > r.n {
+		p = p[:r.n]
+	}
+	for i := range p {
+		p[i] = 'x'
+	}
+	r.n -= len(p)
+	return len(p), nil
+}
+
+func (r *failingReader) Close() error {
+	r.closed = true
+	return nil
+}
+
+// streamOf returns a req for ExecuteStream yielding r.
+func streamOf(r io.ReadCloser) func() (io.ReadCloser, error) {
+	return func() (io.ReadCloser, error) { return r, nil }
+}
+
+func TestExecuteStream_CleanEOFRecordsSuccess(t *testing.T) {
+	cb := New(Settings{Name: "stream-eof"})
+
+	body, err := cb.ExecuteStream(streamOf(&failingReader{n: 100, err: io.EOF}))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if got := cb.Counts(); got.TotalSuccesses != 0 || got.Requests != 1 {
+		t.Fatalf("Expected the outcome deferred until EOF, got %+v", got)
+	}
+
+	data, err := io.ReadAll(body)
+	if err != nil || len(data) != 100 {
+		t.Fatalf("Expected 100 bytes without error, got %d, %v", len(data), err)
+	}
+	body.Close()
+
+	if got := cb.Counts(); got.TotalSuccesses != 1 || got.TotalFailures != 0 {
+		t.Errorf("Expected one success, got %+v", got)
+	}
+}
+
+func TestExecuteStream_MidStreamFailureTrips(t *testing.T) {
+	cb := New(tripOnFirstFailure(Settings{Name: "stream-fail"}))
+	boom := errors.New("connection reset")
+
+	body, err := cb.ExecuteStream(streamOf(&failingReader{n: 50, err: boom}))
+	if err != nil {
+		t.Fatalf("Expected the stream to start, got %v", err)
+	}
+	if _, err := io.ReadAll(body); !errors.Is(err, boom) {
+		t.Fatalf("Expected the read error to reach the caller, got %v", err)
+	}
+	body.Close()
+
+	if cb.State() != StateOpen {
+		t.Errorf("Expected the mid-stream failure to trip the circuit, got %v", cb.State())
+	}
+}
+
+func TestExecuteStream_CloseBeforeEOF(t *testing.T) {
+	t.Run("failure by default", func(t *testing.T) {
+		var classified error
+		cb := New(Settings{Name: "stream-close"})
+		cb.streamFailure = func(err error) bool {
+			classified = err
+			return true
+		}
+		r := &failingReader{n: 100, err: io.EOF}
+
+		body, _ := cb.ExecuteStream(streamOf(r))
+		body.Read(make([]byte, 10))
+		body.Close()
+
+		if !r.closed {
+			t.Error("Expected Close to reach the underlying stream")
+		}
+		if got := cb.Counts(); got.TotalFailures != 1 {
+			t.Errorf("Expected closing early to be a failure, got %+v", got)
+		}
+		if !errors.Is(classified, ErrStreamClosedEarly) {
+			t.Errorf("Expected ErrStreamClosedEarly to be classified, got %v", classified)
+		}
+	})
+
+	t.Run("ignored by TransportStreamFailure", func(t *testing.T) {
+		cb := New(Settings{Name: "stream-close-transport", IsStreamFailure: TransportStreamFailure})
+
+		body, _ := cb.ExecuteStream(streamOf(&failingReader{n: 100, err: io.EOF}))
+		body.Read(make([]byte, 10))
+		body.Close()
+
+		if got := cb.Counts(); got.TotalFailures != 0 || got.TotalSuccesses != 0 || got.Requests != 0 {
+			t.Errorf("Expected closing early to be recorded as neither, got %+v", got)
+		}
+	})
+}
+
+func TestExecuteStream_TransportStreamFailure(t *testing.T) {
+	cb := New(Settings{Name: "stream-transport", IsStreamFailure: TransportStreamFailure})
+
+	truncated, _ := cb.ExecuteStream(streamOf(&failingReader{n: 10, err: io.ErrUnexpectedEOF}))
+	io.ReadAll(truncated)
+	truncated.Close()
+
+	app, _ := cb.ExecuteStream(streamOf(&failingReader{n: 10, err: errors.New("bad record")}))
+	io.ReadAll(app)
+	app.Close()
+
+	if got := cb.Counts(); got.TotalFailures != 1 || got.Requests != 1 {
+		t.Errorf("Expected only the truncated stream to count, got %+v", got)
+	}
+}
+
+func TestExecuteStream_HoldsHalfOpenSlotUntilDone(t *testing.T) {
+	clk := newFakeClock()
+	cb := newWithClock(clk, tripOnFirstFailure(Settings{Name: "stream-probe", Timeout: 10 * time.Millisecond}))
+	tripToHalfOpen(t, cb, clk)
+
+	body, err := cb.ExecuteStream(streamOf(&failingReader{n: 10, err: io.EOF}))
+	if err != nil {
+		t.Fatalf("Expected the stream to be admitted as the probe, got %v", err)
+	}
+
+	if _, err := cb.Execute(successFunc); !errors.Is(err, ErrTooManyRequests) {
+		t.Fatalf("Expected the open stream to hold the probe slot, got %v", err)
+	}
+	if cb.State() != StateHalfOpen {
+		t.Fatalf("Expected HalfOpen until the stream finishes, got %v", cb.State())
+	}
+
+	io.ReadAll(body)
+	body.Close()
+
+	if cb.State() != StateClosed {
+		t.Errorf("Expected the completed stream to close the circuit, got %v", cb.State())
+	}
+}
+
+func TestExecuteStream_TimeoutDetaches(t *testing.T) {
+	clk := newFakeClock()
+	cb := newWithClock(clk, tripOnFirstFailure(Settings{
+		Name:          "stream-timeout",
+		Timeout:       10 * time.Millisecond,
+		StreamTimeout: 20 * time.Millisecond,
+	}))
+	tripToHalfOpen(t, cb, clk)
+
+	body, err := cb.ExecuteStream(streamOf(&failingReader{n: 10, err: errors.New("late")}))
+	if err != nil {
+		t.Fatalf("Expected the stream to be admitted, got %v", err)
+	}
+	time.Sleep(50 * time.Millisecond) // StreamTimeout runs on a real timer
+
+	if got := cb.Metrics().StreamTimeouts; got != 1 {
+		t.Fatalf("Expected one stream timeout, got %d", got)
+	}
+	if _, err := cb.Execute(successFunc); err != nil {
+		t.Fatalf("Expected the detached stream to release the probe slot, got %v", err)
+	}
+	if cb.State() != StateClosed {
+		t.Fatalf("Expected the next probe to close the circuit, got %v", cb.State())
+	}
+
+	// Finishing after the timeout records nothing
+	io.ReadAll(body)
+	body.Close()
+	if got := cb.Counts(); got.TotalFailures != 0 {
+		t.Errorf("Expected the detached stream's failure to be ignored, got %+v", got)
+	}
+}
+
+func TestExecuteStream_ReqErrorRecordedImmediately(t *testing.T) {
+	cb := New(Settings{Name: "stream-req-error"})
+
+	body, err := cb.ExecuteStream(func() (io.ReadCloser, error) {
+		return nil, errors.New("dial failed")
+	})
+	if err == nil || body != nil {
+		t.Fatalf("Expected the req error, got %v, %v", body, err)
+	}
+	if got := cb.Counts(); got.TotalFailures != 1 {
+		t.Errorf("Expected one failure, got %+v", got)
+	}
+}
+
+func TestExecuteHTTP(t *testing.T) {
+	statusAware := func(s Settings) Settings {
+		s.OutcomeWeight = func(result interface{}, err error) float64 {
+			if err != nil {
+				return 1
+			}
+			if resp, ok := result.(*http.Response); ok && resp.StatusCode >= 500 {
+				return 1
+			}
+			return 0
+		}
+		return s
+	}
+	respond := func(status int, body io.ReadCloser) func() (*http.Response, error) {
+		return func() (*http.Response, error) {
+			return &http.Response{StatusCode: status, Body: body}, nil
+		}
+	}
+
+	t.Run("body failure is recorded", func(t *testing.T) {
+		cb := New(statusAware(Settings{Name: "http-body"}))
+
+		resp, err := cb.ExecuteHTTP(respond(http.StatusOK, &failingReader{n: 20, err: io.ErrUnexpectedEOF}))
+		if err != nil {
+			t.Fatalf("Expected the response, got %v", err)
+		}
+		io.ReadAll(resp.Body)
+		resp.Body.Close()
+
+		if got := cb.Counts(); got.TotalFailures != 1 || got.TotalSuccesses != 0 {
+			t.Errorf("Expected the truncated body to be a failure, got %+v", got)
+		}
+	})
+
+	t.Run("complete body is a success", func(t *testing.T) {
+		cb := New(statusAware(Settings{Name: "http-ok"}))
+
+		resp, _ := cb.ExecuteHTTP(respond(http.StatusOK, io.NopCloser(strings.NewReader("hello"))))
+		io.ReadAll(resp.Body)
+		resp.Body.Close()
+
+		if got := cb.Counts(); got.TotalSuccesses != 1 {
+			t.Errorf("Expected one success, got %+v", got)
+		}
+	})
+
+	t.Run("failed status is recorded immediately", func(t *testing.T) {
+		cb := New(statusAware(Settings{Name: "http-503"}))
+		r := &failingReader{n: 5, err: io.EOF}
+
+		resp, _ := cb.ExecuteHTTP(respond(http.StatusServiceUnavailable, r))
+		if got := cb.Counts(); got.TotalFailures != 1 {
+			t.Errorf("Expected the 503 recorded on return, got %+v", got)
+		}
+		if resp.Body != io.ReadCloser(r) {
+			t.Error("Expected the body of a failed response to be returned untracked")
+		}
+		resp.Body.Close()
+	})
+}
@@ -0,0 +1,123 @@
+package breaker
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+// incidentTracker follows an open incident, from the first entry into Open
+// until the circuit closes, for MaxOpenDuration and AdaptiveTimeout.
+type incidentTracker struct {
+	maxOpen     time.Duration // MaxOpenDuration; 0 when only AdaptiveTimeout is set
+	action      StuckOpenAction
+	onStuckOpen func(string, time.Duration)
+
+	// startedAt (UnixNano) is set on the first entry into Open and cleared on
+	// Closed; stuckDeadline is when checkStuckOpen next acts (0 outside an
+	// incident)
+	startedAt     atomic.Int64
+	stuckDeadline atomic.Int64
+}
+
+// stuckOpenSettings returns MaxOpenDuration, OnStuckOpen and StuckOpenAction,
+// or zeros when incidents are not tracked.
+func (cb *CircuitBreaker) stuckOpenSettings() (time.Duration, func(string, time.Duration), StuckOpenAction) {
+	inc := cb.incident
+	if inc == nil {
+		return 0, nil, 0
+	}
+	return inc.maxOpen, inc.onStuckOpen, inc.action
+}
+
+// watchesStuckOpen reports whether MaxOpenDuration is set.
+func (cb *CircuitBreaker) watchesStuckOpen() bool {
+	return cb.incident != nil && cb.incident.maxOpen > 0
+}
+
+// incidentStart returns when the current incident started (UnixNano), 0
+// outside an incident or when incidents are not tracked.
+func (cb *CircuitBreaker) incidentStart() int64 {
+	if cb.incident == nil {
+		return 0
+	}
+	return cb.incident.startedAt.Load()
+}
+
+// startIncident records the start of an open incident (UnixNano) if one isn't
+// already in progress. Called on every entry into Open.
+func (cb *CircuitBreaker) startIncident(now int64) {
+	inc := cb.incident
+	if inc == nil {
+		return
+	}
+	if inc.startedAt.CompareAndSwap(0, now) && inc.maxOpen > 0 {
+		inc.stuckDeadline.Store(now + int64(inc.maxOpen))
+	}
+}
+
+// endIncident clears the incident timer. Called on every entry into Closed.
+func (cb *CircuitBreaker) endIncident() {
+	if inc := cb.incident; inc != nil {
+		inc.stuckDeadline.Store(0)
+		inc.startedAt.Store(0)
+	}
+}
+
+// checkStuckOpen invokes OnStuckOpen and takes StuckOpenAction if the current
+// incident has outlasted MaxOpenDuration. The next check is due one
+// MaxOpenDuration later, so a circuit that stays stuck is remediated periodically.
+// Only the caller that claims the deadline acts.
+func (cb *CircuitBreaker) checkStuckOpen(now int64) {
+	if cb.maintenance.Load() || cb.Disabled() {
+		return
+	}
+
+	inc := cb.incident
+	deadline := inc.stuckDeadline.Load()
+	if deadline == 0 || now < deadline {
+		return
+	}
+	if !inc.stuckDeadline.CompareAndSwap(deadline, now+int64(inc.maxOpen)) {
+		return // Another caller is handling this deadline
+	}
+
+	startedAt := inc.startedAt.Load()
+	if startedAt == 0 {
+		return // Incident ended concurrently
+	}
+
+	safeCallOnStuckOpen(cb.name, inc.onStuckOpen, time.Duration(now-startedAt))
+
+	switch inc.action {
+	case StuckOpenForceHalfOpen:
+		cb.transitionToHalfOpen()
+	case StuckOpenForceClose:
+		cb.forceClose()
+	}
+}
+
+// forceClose transitions the circuit from Open directly to Closed.
+func (cb *CircuitBreaker) forceClose() {
+	epoch, ok := cb.commitTransition(StateOpen, StateClosed)
+	if !ok {
+		return // Lost race, another goroutine already transitioned
+	}
+
+	now := cb.now()
+	cb.stateChangedAt.Store(now)
+	cb.openedAt.Store(0)
+	cb.openReason.Store(nil)
+	cb.halfOpenRequests.Store(0)
+	cb.halfOpenProbes.Store(0)
+	cb.endIncident()
+	cb.endEpisode(now)
+	cb.cycleAdmitted.Store(0)
+
+	// Clear counts and start a fresh window. The health latch stays set: the
+	// backend was never shown to recover.
+	counts := cb.Counts()
+	cb.clearCounts()
+	cb.lastClearedAt.Store(cb.windowStart(now))
+
+	cb.notifyStateChange(epoch, StateOpen, StateClosed, counts)
+}
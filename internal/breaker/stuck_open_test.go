@@ -0,0 +1,244 @@
+package breaker
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+// stuckOpenRecorder captures OnStuckOpen invocations.
+type stuckOpenRecorder struct {
+	mu    sync.Mutex
+	calls []time.Duration
+}
+
+func (r *stuckOpenRecorder) record(_ string, openFor time.Duration) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.calls = append(r.calls, openFor)
+}
+
+func (r *stuckOpenRecorder) count() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return len(r.calls)
+}
+
+// failProbes trips cb and cycles it through n failed half-open probes.
+func failProbes(t *testing.T, cb *CircuitBreaker, n int) {
+	t.Helper()
+	for i := 0; i < 6; i++ {
+		cb.Execute(failFunc)
+	}
+	for i := 0; i < n; i++ {
+		time.Sleep(15 * time.Millisecond)
+		cb.Execute(failFunc)
+		if cb.State() != StateOpen {
+			t.Fatalf("Expected failed probe %d to reopen, got %v", i, cb.State())
+		}
+	}
+}
+
+func TestStuckOpen_IncidentSpansFailedProbes(t *testing.T) {
+	rec := &stuckOpenRecorder{}
+	cb := New(Settings{
+		Name:            "stuck",
+		Timeout:         10 * time.Millisecond,
+		MaxOpenDuration: time.Hour,
+		OnStuckOpen:     rec.record,
+	})
+
+	for i := 0; i < 6; i++ {
+		cb.Execute(failFunc)
+	}
+	start := cb.incident.startedAt.Load()
+	if start == 0 {
+		t.Fatal("Expected incident to start on trip")
+	}
+
+	failProbes(t, cb, 3)
+	if got := cb.incident.startedAt.Load(); got != start {
+		t.Errorf("Expected failed probes to continue the incident, start moved %v", time.Duration(got-start))
+	}
+
+	// Past the cap: callback reports the whole incident
+	now := start + int64(time.Hour) + int64(time.Second)
+	cb.checkStuckOpen(now)
+	if rec.count() != 1 || rec.calls[0] != time.Hour+time.Second {
+		t.Fatalf("Expected one callback with openFor 1h0m1s, got %v", rec.calls)
+	}
+	if cb.State() != StateOpen {
+		t.Errorf("Expected alert-only to leave the circuit open, got %v", cb.State())
+	}
+
+	// Repeats once per MaxOpenDuration, not on every check
+	cb.checkStuckOpen(now + int64(time.Minute))
+	if rec.count() != 1 {
+		t.Errorf("Expected no repeat within MaxOpenDuration, got %d calls", rec.count())
+	}
+	cb.checkStuckOpen(now + int64(time.Hour))
+	if rec.count() != 2 {
+		t.Errorf("Expected repeat after another MaxOpenDuration, got %d calls", rec.count())
+	}
+}
+
+func TestStuckOpen_Actions(t *testing.T) {
+	tests := []struct {
+		action    StuckOpenAction
+		wantState State
+	}{
+		{StuckOpenAlert, StateOpen},
+		{StuckOpenForceHalfOpen, StateHalfOpen},
+		{StuckOpenForceClose, StateClosed},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.action.String(), func(t *testing.T) {
+			rec := &stuckOpenRecorder{}
+			var transitions []string
+			cb := New(Settings{
+				Name:            "stuck",
+				Timeout:         10 * time.Millisecond,
+				MaxOpenDuration: time.Hour,
+				OnStuckOpen:     rec.record,
+				StuckOpenAction: tt.action,
+				OnStateChange: func(_ string, from, to State) {
+					transitions = append(transitions, from.String()+"->"+to.String())
+				},
+			})
+
+			failProbes(t, cb, 3)
+			transitions = nil
+
+			cb.checkStuckOpen(cb.incident.startedAt.Load() + int64(time.Hour))
+
+			if rec.count() != 1 || rec.calls[0] != time.Hour {
+				t.Fatalf("Expected one OnStuckOpen call with openFor 1h, got %v", rec.calls)
+			}
+			if cb.State() != tt.wantState {
+				t.Errorf("Expected %v after %v, got %v", tt.wantState, tt.action, cb.State())
+			}
+			if tt.action != StuckOpenAlert && len(transitions) != 1 {
+				t.Errorf("Expected one forced transition, got %v", transitions)
+			}
+		})
+	}
+}
+
+func TestStuckOpen_ForceCloseEndsIncident(t *testing.T) {
+	cb := New(Settings{
+		Name:            "stuck",
+		Timeout:         time.Hour,
+		MaxOpenDuration: 20 * time.Millisecond,
+		StuckOpenAction: StuckOpenForceClose,
+	})
+
+	for i := 0; i < 6; i++ {
+		cb.Execute(failFunc)
+	}
+	if _, err := cb.Execute(successFunc); !errors.Is(err, ErrOpenState) {
+		t.Fatalf("Expected ErrOpenState before the cap, got %v", err)
+	}
+
+	time.Sleep(30 * time.Millisecond)
+
+	// Execute observes the stuck circuit, closes it, and runs the request
+	if _, err := cb.Execute(successFunc); err != nil {
+		t.Fatalf("Expected request admitted after force-close, got %v", err)
+	}
+	if cb.State() != StateClosed {
+		t.Errorf("Expected closed, got %v", cb.State())
+	}
+	if cb.incident.startedAt.Load() != 0 || cb.incident.stuckDeadline.Load() != 0 {
+		t.Error("Expected incident timer reset on close")
+	}
+	if reason := cb.Diagnostics().OpenReason; reason.Kind != OpenReasonNone {
+		t.Errorf("Expected open reason cleared, got %v", reason.Kind)
+	}
+}
+
+func TestStuckOpen_ResetsOnRecovery(t *testing.T) {
+	rec := &stuckOpenRecorder{}
+	cb := New(Settings{
+		Name:            "stuck",
+		Timeout:         10 * time.Millisecond,
+		MaxOpenDuration: time.Hour,
+		OnStuckOpen:     rec.record,
+	})
+
+	failProbes(t, cb, 1)
+	first := cb.incident.startedAt.Load()
+
+	time.Sleep(15 * time.Millisecond)
+	cb.Execute(successFunc)
+	if cb.State() != StateClosed {
+		t.Fatalf("Expected recovery, got %v", cb.State())
+	}
+	if cb.incident.startedAt.Load() != 0 {
+		t.Fatal("Expected incident cleared on close")
+	}
+
+	for i := 0; i < 6; i++ {
+		cb.Execute(failFunc)
+	}
+	second := cb.incident.startedAt.Load()
+	if second <= first {
+		t.Errorf("Expected a new incident after recovery")
+	}
+
+	// The first incident's deadline no longer applies
+	cb.checkStuckOpen(first + int64(time.Hour))
+	if rec.count() != 0 {
+		t.Errorf("Expected no callback before the new incident's cap, got %d", rec.count())
+	}
+}
+
+func TestStuckOpen_DisabledAndMaintenance(t *testing.T) {
+	rec := &stuckOpenRecorder{}
+	cb := New(Settings{Name: "plain", Timeout: time.Hour})
+	for i := 0; i < 6; i++ {
+		cb.Execute(failFunc)
+	}
+	if cb.incident != nil {
+		t.Error("Expected no incident tracking without MaxOpenDuration")
+	}
+
+	cb = New(Settings{
+		Name:            "maint",
+		Timeout:         time.Hour,
+		MaxOpenDuration: time.Millisecond,
+		OnStuckOpen:     rec.record,
+		StuckOpenAction: StuckOpenForceClose,
+	})
+	for i := 0; i < 6; i++ {
+		cb.Execute(failFunc)
+	}
+	cb.EnterMaintenance()
+	time.Sleep(5 * time.Millisecond)
+	cb.Execute(successFunc)
+
+	if rec.count() != 0 || cb.State() != StateOpen {
+		t.Errorf("Expected no remediation during maintenance, got %d calls, state %v", rec.count(), cb.State())
+	}
+}
+
+func TestStuckOpen_CallbackPanicStillActs(t *testing.T) {
+	cb := New(Settings{
+		Name:            "stuck",
+		Timeout:         time.Hour,
+		MaxOpenDuration: time.Millisecond,
+		OnStuckOpen:     func(string, time.Duration) { panic("pager down") },
+		StuckOpenAction: StuckOpenForceHalfOpen,
+	})
+	for i := 0; i < 6; i++ {
+		cb.Execute(failFunc)
+	}
+	time.Sleep(5 * time.Millisecond)
+
+	cb.Diagnostics()
+
+	if cb.State() != StateHalfOpen {
+		t.Errorf("Expected action taken despite callback panic, got %v", cb.State())
+	}
+}
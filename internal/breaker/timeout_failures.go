@@ -0,0 +1,12 @@
+package breaker
+
+// recordTimeoutFailure counts a recorded failure in Counts.TimeoutFailures if
+// timedOut: the breaker itself timed the call out (a slow call under
+// SlowCallFactor, or a probe abandoned by the HalfOpenProbeTimeout watchdog).
+// Errors returned by the request are never classified. Called after the
+// failure is recorded and before any resulting transition clears the counts.
+func (cb *CircuitBreaker) recordTimeoutFailure(timedOut bool) {
+	if timedOut {
+		safeIncrementCounter(&cb.timeoutFailures, nil, "timeoutFailures", cb.name)
+	}
+}
@@ -0,0 +1,94 @@
+package breaker
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"testing"
+	"time"
+)
+
+func TestTimeoutFailures_TrackedApartFromErrors(t *testing.T) {
+	cb := New(Settings{
+		Name:           "timeout-mix",
+		SlowCallFactor: 1.5,
+		ReadyToTrip:    func(Counts) bool { return false },
+	})
+	learnBaseline(cb, 200, 3*time.Millisecond)
+
+	// Four ordinary errors: the request's own timeouts are its errors
+	cb.Execute(failFunc)
+	cb.Execute(failFunc)
+	cb.Execute(func() (interface{}, error) {
+		return nil, fmt.Errorf("query: %w", context.DeadlineExceeded)
+	})
+	cb.Execute(func() (interface{}, error) {
+		return nil, &net.DNSError{Err: "i/o timeout", IsTimeout: true}
+	})
+
+	// One timeout: a slow call
+	cb.Execute(sleepFunc(10 * time.Millisecond))
+
+	counts := cb.Counts()
+	if counts.TotalFailures != 5 || counts.TimeoutFailures != 1 {
+		t.Errorf("Expected 5 failures of which 1 timeout, got %+v", counts)
+	}
+}
+
+func TestTimeoutFailures_ResetWithWindow(t *testing.T) {
+	clk := newFakeClock()
+	cb := newWithClock(clk, Settings{
+		Name:           "timeout-window",
+		Interval:       time.Minute,
+		SlowCallFactor: 1.5,
+		ReadyToTrip:    func(Counts) bool { return false },
+	})
+	learnBaseline(cb, 200, 3*time.Millisecond)
+
+	cb.Execute(sleepFunc(10 * time.Millisecond))
+	if got := cb.Counts().TimeoutFailures; got != 1 {
+		t.Fatalf("Expected 1 timeout failure, got %d", got)
+	}
+
+	clk.advance(time.Minute)
+	cb.Execute(failFunc)
+	if got := cb.Counts(); got.TimeoutFailures != 0 || got.TotalFailures != 1 {
+		t.Errorf("Expected timeout failures reset with the window, got %+v", got)
+	}
+}
+
+func TestTimeoutFailures_AbandonedProbe(t *testing.T) {
+	cb := New(tripOnFirstFailure(Settings{
+		Name:                 "timeout-probe",
+		Timeout:              10 * time.Millisecond,
+		MaxRequests:          2,
+		HalfOpenMaxProbes:    2,
+		HalfOpenProbeTimeout: 30 * time.Millisecond,
+	}))
+
+	release, done := startHungProbe(t, cb, func(req func() (interface{}, error)) { cb.Execute(req) })
+	defer func() { release(); <-done }()
+
+	// The watchdog records the hung probe as a timeout; the budget keeps HalfOpen
+	deadline := time.Now().Add(time.Second)
+	for cb.Metrics().ProbeTimeouts == 0 && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	if got := cb.Counts(); got.TotalFailures != 1 || got.TimeoutFailures != 1 {
+		t.Errorf("Expected the abandoned probe counted as a timeout failure, got %+v", got)
+	}
+}
+
+func TestTimeoutFailures_ExecuteContextDeadline(t *testing.T) {
+	cb := New(Settings{Name: "timeout-ctx"})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Millisecond)
+	defer cancel()
+	cb.ExecuteContext(ctx, func() (interface{}, error) {
+		<-ctx.Done()
+		return nil, ctx.Err()
+	})
+	if got := cb.Counts(); got.TimeoutFailures != 0 || got.TotalFailures != 0 {
+		t.Errorf("Expected the caller's deadline not to count, got %+v", got)
+	}
+}
@@ -0,0 +1,70 @@
+package breaker
+
+import (
+	"fmt"
+	"time"
+)
+
+// TooManyRequestsError is returned by Execute() and ExecuteContext() with
+// Settings.ProbeRejectionDetails when a HalfOpen circuit rejects a request:
+// MaxRequests probes are already in flight, the HalfOpenMaxProbes budget is
+// spent, or the request is not probe-eligible (see ExecuteWithOpts).
+//
+// It wraps ErrTooManyRequests, so errors.Is matches the sentinel. The probe
+// fields let callers choose between failing fast and briefly waiting for a
+// probe's verdict:
+//
+//	var tooMany *autobreaker.TooManyRequestsError
+//	if errors.As(err, &tooMany) && tooMany.ProbeInFlight && tooMany.ProbeElapsed < 50*time.Millisecond {
+//	    time.Sleep(10 * time.Millisecond) // Verdict is imminent; re-check
+//	    return breaker.Execute(req)
+//	}
+//
+// The fields are a snapshot taken at rejection; the probe may have completed
+// by the time the caller reads them.
+type TooManyRequestsError struct {
+	// Name is the name of the circuit breaker that rejected the request.
+	Name string
+
+	// ProbeInFlight reports whether a probe was executing at rejection. False
+	// when the probe budget is spent and the last probes have finished.
+	ProbeInFlight bool
+
+	// ProbeElapsed is how long probes had been continuously in flight at
+	// rejection: since the in-flight count last rose from zero. Zero when
+	// ProbeInFlight is false.
+	ProbeElapsed time.Duration
+}
+
+// Error implements the error interface.
+func (e *TooManyRequestsError) Error() string {
+	if e.ProbeInFlight {
+		return fmt.Sprintf("%v: %q (probe in flight for %v)", ErrTooManyRequests, e.Name, e.ProbeElapsed)
+	}
+	return fmt.Sprintf("%v: %q", ErrTooManyRequests, e.Name)
+}
+
+// Unwrap returns ErrTooManyRequests.
+func (e *TooManyRequestsError) Unwrap() error {
+	return ErrTooManyRequests
+}
+
+// tooManyRequestsError builds the rejection for a request turned away in
+// HalfOpen: ErrTooManyRequests, or a *TooManyRequestsError with
+// ProbeRejectionDetails.
+func (cb *CircuitBreaker) tooManyRequestsError() error {
+	cb.episodeRejected()
+	if !cb.probeRejectionDetails {
+		return ErrTooManyRequests
+	}
+	err := &TooManyRequestsError{Name: cb.name}
+	if cb.probesInFlight.Load() > 0 {
+		if started := cb.probeStartedAt.Load(); started > 0 {
+			err.ProbeInFlight = true
+			if elapsed := time.Duration(cb.now() - started); elapsed > 0 {
+				err.ProbeElapsed = elapsed
+			}
+		}
+	}
+	return err
+}
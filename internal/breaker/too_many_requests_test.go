@@ -0,0 +1,155 @@
+package breaker
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+// halfOpenWithBlockedProbe trips cb, waits out Timeout and starts a probe that
+// blocks until release is closed. Returns once the probe is executing.
+func halfOpenWithBlockedProbe(t *testing.T, cb *CircuitBreaker, release chan struct{}) *sync.WaitGroup {
+	t.Helper()
+	cb.Execute(failFunc)
+	time.Sleep(20 * time.Millisecond)
+
+	started := make(chan struct{})
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		cb.Execute(func() (interface{}, error) {
+			close(started)
+			<-release
+			return "ok", nil
+		})
+	}()
+	<-started
+	return &wg
+}
+
+func TestTooManyRequestsError_ProbeInFlight(t *testing.T) {
+	cb := New(tripOnFirstFailure(Settings{Name: "too-many", Timeout: 10 * time.Millisecond, ProbeRejectionDetails: true}))
+	release := make(chan struct{})
+	probe := halfOpenWithBlockedProbe(t, cb, release)
+
+	time.Sleep(20 * time.Millisecond)
+	_, err := cb.Execute(successFunc)
+	close(release)
+	probe.Wait()
+
+	if !errors.Is(err, ErrTooManyRequests) {
+		t.Fatalf("Expected errors.Is(err, ErrTooManyRequests), got %v", err)
+	}
+	var tooMany *TooManyRequestsError
+	if !errors.As(err, &tooMany) {
+		t.Fatalf("Expected *TooManyRequestsError, got %T", err)
+	}
+	if tooMany.Name != "too-many" {
+		t.Errorf("Name = %q, want %q", tooMany.Name, "too-many")
+	}
+	if !tooMany.ProbeInFlight {
+		t.Error("Expected ProbeInFlight while the probe is executing")
+	}
+	if tooMany.ProbeElapsed < 20*time.Millisecond || tooMany.ProbeElapsed > time.Second {
+		t.Errorf("Expected ProbeElapsed of at least 20ms, got %v", tooMany.ProbeElapsed)
+	}
+}
+
+func TestTooManyRequestsError_BareSentinelByDefault(t *testing.T) {
+	cb := New(tripOnFirstFailure(Settings{Name: "too-many-default", Timeout: 10 * time.Millisecond}))
+	release := make(chan struct{})
+	probe := halfOpenWithBlockedProbe(t, cb, release)
+
+	_, err := cb.Execute(successFunc)
+	close(release)
+	probe.Wait()
+
+	if err != ErrTooManyRequests {
+		t.Errorf("Expected the bare ErrTooManyRequests without ProbeRejectionDetails, got %#v", err)
+	}
+}
+
+func TestTooManyRequestsError_ConcurrentHalfOpenLoad(t *testing.T) {
+	const maxRequests = 3
+	cb := New(tripOnFirstFailure(Settings{
+		Name:                  "too-many-load",
+		Timeout:               10 * time.Millisecond,
+		MaxRequests:           maxRequests,
+		ProbeRejectionDetails: true,
+	}))
+	release := make(chan struct{})
+	probe := halfOpenWithBlockedProbe(t, cb, release)
+
+	// Fill the remaining slots, then hammer the circuit from many goroutines
+	var admitted, rejected sync.WaitGroup
+	errs := make(chan error, 100)
+	for i := 0; i < maxRequests-1; i++ {
+		admitted.Add(1)
+		started := make(chan struct{})
+		go func() {
+			defer admitted.Done()
+			cb.Execute(func() (interface{}, error) {
+				close(started)
+				<-release
+				return "ok", nil
+			})
+		}()
+		<-started
+	}
+	for i := 0; i < cap(errs); i++ {
+		rejected.Add(1)
+		go func() {
+			defer rejected.Done()
+			_, err := cb.Execute(successFunc)
+			errs <- err
+		}()
+	}
+	rejected.Wait()
+	close(release)
+	probe.Wait()
+	admitted.Wait()
+	close(errs)
+
+	for err := range errs {
+		var tooMany *TooManyRequestsError
+		if !errors.As(err, &tooMany) {
+			t.Fatalf("Expected *TooManyRequestsError, got %v", err)
+		}
+		if !errors.Is(err, ErrTooManyRequests) {
+			t.Errorf("Expected errors.Is to match ErrTooManyRequests: %v", err)
+		}
+		if !tooMany.ProbeInFlight || tooMany.ProbeElapsed <= 0 {
+			t.Errorf("Expected a probe in flight with elapsed time, got %+v", tooMany)
+		}
+	}
+	if got := cb.probesInFlight.Load(); got != 0 {
+		t.Errorf("Expected no probes in flight after release, got %d", got)
+	}
+}
+
+func TestTooManyRequestsError_NoProbeInFlight(t *testing.T) {
+	cb := New(Settings{Name: "too-many-idle", ProbeRejectionDetails: true})
+
+	err := cb.tooManyRequestsError()
+
+	var tooMany *TooManyRequestsError
+	if !errors.As(err, &tooMany) {
+		t.Fatalf("Expected *TooManyRequestsError, got %T", err)
+	}
+	if tooMany.ProbeInFlight || tooMany.ProbeElapsed != 0 {
+		t.Errorf("Expected no probe in flight, got %+v", tooMany)
+	}
+	if got, want := err.Error(), `too many requests: "too-many-idle"`; got != want {
+		t.Errorf("Error() = %q, want %q", got, want)
+	}
+}
+
+func TestTooManyRequestsError_Message(t *testing.T) {
+	err := &TooManyRequestsError{Name: "api", ProbeInFlight: true, ProbeElapsed: 15 * time.Millisecond}
+
+	if got, want := err.Error(), `too many requests: "api" (probe in flight for 15ms)`; got != want {
+		t.Errorf("Error() = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,165 @@
+package breaker
+
+import (
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// raceOpenToHalfOpen trips cb, waits for the timeout, then sends goroutines
+// concurrent requests that block until every non-executing request has returned.
+// Returns the number of executions and the errors observed by kind.
+func raceOpenToHalfOpen(t *testing.T, cb *CircuitBreaker, goroutines int) (executed, openState, tooMany int32) {
+	t.Helper()
+
+	cb.Execute(failFunc)
+	if cb.State() != StateOpen {
+		t.Fatalf("Expected open, got %v", cb.State())
+	}
+	time.Sleep(20 * time.Millisecond)
+
+	var (
+		executions, openErrs, tooManyErrs, returned atomic.Int32
+		start                                       = make(chan struct{})
+		release                                     = make(chan struct{})
+		wg                                          sync.WaitGroup
+	)
+	for i := 0; i < goroutines; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			<-start
+			_, err := cb.Execute(func() (interface{}, error) {
+				executions.Add(1)
+				<-release
+				return "ok", nil
+			})
+			switch err {
+			case ErrOpenState:
+				openErrs.Add(1)
+				returned.Add(1)
+			case ErrTooManyRequests:
+				tooManyErrs.Add(1)
+				returned.Add(1)
+			case nil:
+			default:
+				t.Errorf("Unexpected error: %v", err)
+				returned.Add(1)
+			}
+		}()
+	}
+
+	close(start)
+	deadline := time.Now().Add(5 * time.Second)
+	for executions.Load()+returned.Load() < int32(goroutines) && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	close(release)
+	wg.Wait()
+
+	return executions.Load(), openErrs.Load(), tooManyErrs.Load()
+}
+
+func TestTransitionLoserProbe_LosersCompeteForProbeSlots(t *testing.T) {
+	const goroutines = 500
+	cb := New(Settings{
+		Name:        "test",
+		MaxRequests: 10,
+		Timeout:     10 * time.Millisecond,
+		ReadyToTrip: func(c Counts) bool { return c.ConsecutiveFailures >= 1 },
+	})
+
+	executed, openState, tooMany := raceOpenToHalfOpen(t, cb, goroutines)
+
+	if executed != 10 {
+		t.Errorf("Expected MaxRequests=10 executions, got %d", executed)
+	}
+	if openState != 0 {
+		t.Errorf("Expected no ErrOpenState once ready to probe, got %d", openState)
+	}
+	if tooMany != goroutines-10 {
+		t.Errorf("Expected %d ErrTooManyRequests, got %d", goroutines-10, tooMany)
+	}
+}
+
+func TestTransitionLoserReject_OnlyWinnerProbes(t *testing.T) {
+	const goroutines = 500
+	cb := New(Settings{
+		Name:                    "test",
+		MaxRequests:             10,
+		Timeout:                 10 * time.Millisecond,
+		TransitionLoserBehavior: TransitionLoserReject,
+		ReadyToTrip:             func(c Counts) bool { return c.ConsecutiveFailures >= 1 },
+	})
+
+	executed, openState, tooMany := raceOpenToHalfOpen(t, cb, goroutines)
+
+	// The winner executes; losers of the swap get ErrOpenState. Requests that
+	// observe HalfOpen directly still compete for the remaining probe slots, so
+	// the number of executions stays within MaxRequests either way.
+	if executed < 1 || executed > 10 {
+		t.Errorf("Expected between 1 and MaxRequests executions, got %d", executed)
+	}
+	if executed+openState+tooMany != goroutines {
+		t.Errorf("Expected every request accounted for, got %d executed, %d open, %d too many",
+			executed, openState, tooMany)
+	}
+}
+
+func TestAdmitAfterTimeout_LoserPolicy(t *testing.T) {
+	tests := []struct {
+		behavior  TransitionLoserBehavior
+		wantLoser bool
+	}{
+		{TransitionLoserProbe, true},
+		{TransitionLoserReject, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.behavior.String(), func(t *testing.T) {
+			cb := New(Settings{
+				Name:                    "test",
+				TransitionLoserBehavior: tt.behavior,
+				Timeout:                 time.Minute,
+				ReadyToTrip:             func(c Counts) bool { return c.ConsecutiveFailures >= 1 },
+			})
+			cb.Execute(failFunc)
+
+			// The winner of the swap always proceeds
+			if !cb.admitAfterTimeout() {
+				t.Fatal("Expected transition winner to be admitted")
+			}
+			if cb.State() != StateHalfOpen {
+				t.Fatalf("Expected half-open, got %v", cb.State())
+			}
+
+			// A second attempt loses the swap (already HalfOpen)
+			if got := cb.admitAfterTimeout(); got != tt.wantLoser {
+				t.Errorf("Loser admitted = %v, want %v", got, tt.wantLoser)
+			}
+		})
+	}
+}
+
+func TestTransitionLoserBehavior_Validation(t *testing.T) {
+	defer func() {
+		if r := recover(); r == nil {
+			t.Error("Expected panic for unknown TransitionLoserBehavior")
+		}
+	}()
+	New(Settings{Name: "test", TransitionLoserBehavior: 7})
+}
+
+func TestTransitionLoserBehavior_String(t *testing.T) {
+	tests := map[TransitionLoserBehavior]string{
+		TransitionLoserProbe:  "probe",
+		TransitionLoserReject: "reject",
+		7:                     "unknown",
+	}
+	for b, want := range tests {
+		if got := b.String(); got != want {
+			t.Errorf("%d.String() = %q, want %q", b, got, want)
+		}
+	}
+}
@@ -8,7 +8,8 @@ import (
 // State represents the circuit breaker state.
 //
 // The circuit breaker implements a state machine with three states:
-// Closed → Open → HalfOpen → Closed (or back to Open).
+// Closed → Open → HalfOpen → Closed (or back to Open). A fourth value,
+// StateDisabled, reports the operator override set by Disable().
 //
 // Thread-safe: State values can be safely compared and returned from State() method.
 type State int32
@@ -47,6 +48,17 @@ const (
 	//
 	// This state probes the backend to determine if it has recovered.
 	StateHalfOpen
+
+	// StateDisabled indicates the breaker was taken out of the request path with
+	// Disable().
+	//
+	// In this state:
+	//   - Every request runs directly, without admission checks or accounting
+	//   - The state machine is frozen (see Metrics.MachineState) and resumes
+	//     from where it was when Enable() is called
+	//
+	// It is never entered by the state machine itself, only by the operator.
+	StateDisabled
 )
 
 const (
@@ -55,7 +67,8 @@ const (
 
 // String returns the string representation of the state.
 //
-// Returns "closed", "open", "half-open", or "unknown" for invalid states.
+// Returns "closed", "open", "half-open", "disabled", or "unknown" for invalid
+// states.
 // Useful for logging and debugging.
 func (s State) String() string {
 	switch s {
@@ -65,6 +78,8 @@ func (s State) String() string {
 		return "open"
 	case StateHalfOpen:
 		return "half-open"
+	case StateDisabled:
+		return "disabled"
 	default:
 		return stateUnknownStr
 	}
@@ -115,6 +130,129 @@ type Counts struct {
 	// Resets to 0 on any success.
 	// Used by default ReadyToTrip (trips after 5 consecutive failures).
 	ConsecutiveFailures uint32
+
+	// FailureWeight is the sum of failure weights in the current window when
+	// Settings.OutcomeWeight is set. Adaptive thresholds use FailureWeight / Requests
+	// as the failure rate in that case. Always zero when OutcomeWeight is unset.
+	FailureWeight float64
+
+	// Panics is the number of requests in the current window whose function
+	// panicked. Each is also counted in TotalFailures, so panics trip the circuit
+	// like any other failure; this count tells a panic storm (a bad deploy)
+	// apart from a failing dependency. See Settings.OnPanicThreshold.
+	Panics uint32
+
+	// TimeoutFailures is the number of failures in the current window caused by
+	// the breaker's own timeouts: calls recorded as slow under
+	// Settings.SlowCallFactor, and probes abandoned by the
+	// Settings.HalfOpenProbeTimeout watchdog. Each is also counted in
+	// TotalFailures; TotalFailures - TimeoutFailures is the ordinary errors.
+	// Errors returned by the request are ordinary errors, even deadline or
+	// Timeout() ones, and ExecuteContext's own deadline is not a failure.
+	TimeoutFailures uint32
+}
+
+// TransitionLoserBehavior controls what happens to requests that race to move
+// the circuit from Open to HalfOpen and lose.
+//
+// When the Timeout elapses, many goroutines may observe the Open state at once and
+// attempt the Open → HalfOpen transition; exactly one wins the atomic swap.
+type TransitionLoserBehavior int32
+
+const (
+	// TransitionLoserProbe lets losers proceed as half-open probes, subject to the
+	// same MaxRequests (and HalfOpenMaxProbes) limits as the winner. Losers beyond
+	// those limits get ErrTooManyRequests. Callers never see ErrOpenState once the
+	// circuit is ready to probe.
+	TransitionLoserProbe TransitionLoserBehavior = iota
+
+	// TransitionLoserReject rejects losers with ErrOpenState, so only the winner
+	// (and requests that arrive after the circuit is already HalfOpen) probe the
+	// backend. Rejected losers are not counted as requests.
+	TransitionLoserReject
+)
+
+// String returns the string representation of the behavior.
+func (b TransitionLoserBehavior) String() string {
+	switch b {
+	case TransitionLoserProbe:
+		return "probe"
+	case TransitionLoserReject:
+		return "reject"
+	default:
+		return stateUnknownStr
+	}
+}
+
+// StuckOpenAction selects the remediation taken when a circuit has been open for
+// longer than Settings.MaxOpenDuration within a single incident.
+type StuckOpenAction int32
+
+const (
+	// StuckOpenAlert only invokes OnStuckOpen; the circuit keeps its normal
+	// Open/HalfOpen cycle.
+	StuckOpenAlert StuckOpenAction = iota
+
+	// StuckOpenForceHalfOpen moves the circuit to HalfOpen immediately, without
+	// waiting for Timeout, so the next request probes the backend.
+	StuckOpenForceHalfOpen
+
+	// StuckOpenForceClose closes the circuit and admits normal traffic. If the
+	// backend is still failing, the circuit trips again and a new incident starts.
+	StuckOpenForceClose
+)
+
+// String returns the string representation of the action.
+func (a StuckOpenAction) String() string {
+	switch a {
+	case StuckOpenAlert:
+		return "alert"
+	case StuckOpenForceHalfOpen:
+		return "force-half-open"
+	case StuckOpenForceClose:
+		return "force-close"
+	default:
+		return stateUnknownStr
+	}
+}
+
+// ClassifierPanicOutcome selects how a call is recorded when its classifier
+// (IsSuccessful or OutcomeWeight) panics. Set via Settings.ClassifierPanicOutcome.
+type ClassifierPanicOutcome int32
+
+const (
+	// ClassifierPanicDefault classifies the call as DefaultIsSuccessful would:
+	// a success if the request returned a nil error, otherwise a failure. A
+	// buggy classifier then cannot trip a healthy backend, and failing calls
+	// still count.
+	ClassifierPanicDefault ClassifierPanicOutcome = iota
+
+	// ClassifierPanicFailure records the call as a failure (fail closed).
+	ClassifierPanicFailure
+
+	// ClassifierPanicSuccess records the call as a success (fail open), so a
+	// buggy classifier cannot trip the circuit on its own.
+	ClassifierPanicSuccess
+
+	// ClassifierPanicIgnore records the call as neither a success nor a
+	// failure, like ErrIgnoreOutcome.
+	ClassifierPanicIgnore
+)
+
+// String returns the string representation of the outcome.
+func (o ClassifierPanicOutcome) String() string {
+	switch o {
+	case ClassifierPanicDefault:
+		return "default"
+	case ClassifierPanicFailure:
+		return "failure"
+	case ClassifierPanicSuccess:
+		return "success"
+	case ClassifierPanicIgnore:
+		return "ignore"
+	default:
+		return stateUnknownStr
+	}
 }
 
 // Settings configures a circuit breaker.
@@ -176,11 +314,12 @@ type Counts struct {
 //
 // Validation:
 //
-// New() validates settings and panics on invalid configuration:
+// New() validates settings and panics on invalid configuration, e.g.:
 //   - FailureRateThreshold: Must be in (0, 1) exclusive when AdaptiveThreshold=true
-//   - Interval: Must be >= 0 (negative values invalid)
+//   - Interval, Timeout: Must be >= 0 (negative values invalid)
 //
-// All other fields have sensible defaults and don't require validation.
+// Contradictory combinations (fields that are set but ignored) are accepted unless
+// Strict is true. ValidateSettings reports every issue without constructing a breaker.
 //
 // Thread-Safety Note:
 //
@@ -190,10 +329,133 @@ type Settings struct {
 	// Name is an identifier for the circuit breaker.
 	Name string
 
+	// Labels attaches metadata (team, tier, region, ...) to the breaker for
+	// exporters and logging, so consumers don't need side tables keyed by Name.
+	// Read it back with Labels(); it is also reported in Diagnostics.
+	//
+	// The map is copied at construction, so later changes to it have no effect.
+	// Keys must be non-empty, and "name" is reserved: exporters use it for Name.
+	// Keep the set small and low-cardinality, as exporters typically turn every
+	// label into a metric label.
+	//
+	// Default: nil (no labels)
+	Labels map[string]string
+
 	// MaxRequests is the maximum number of concurrent requests allowed in half-open state.
 	// Default: 1 if set to 0.
 	MaxRequests uint32
 
+	// HalfOpenMaxProbes is the total number of executions permitted during a single
+	// HalfOpen episode.
+	//
+	// MaxRequests only limits concurrency, so fast sequential probes can send many
+	// calls to a recovering backend before the first result closes the circuit.
+	// With HalfOpenMaxProbes set, once the budget is consumed further calls get
+	// ErrTooManyRequests until the circuit transitions. MaxRequests still caps how
+	// many of the budgeted probes run at once.
+	//
+	// The close/reopen decision is made once HalfOpenMaxProbes outcomes have been
+	// recorded: the circuit closes if successes outnumber failures and reopens
+	// otherwise (see RequireAllSuccesses to reopen on the first failure instead).
+	// In adaptive mode with RecoverFailureRate set, the probes' failure rate must
+	// be at or below RecoverFailureRate instead.
+	// Probes that produce no outcome (context canceled, ErrIgnoreOutcome) are
+	// returned to the budget. The budget resets on every entry into HalfOpen.
+	//
+	// Default: 0 (no budget; the first probe outcome decides)
+	HalfOpenMaxProbes uint32
+
+	// RequireAllSuccesses makes the circuit reopen on the first failed probe when
+	// HalfOpenMaxProbes is set, so it closes only if every budgeted probe succeeds.
+	// Ignored when HalfOpenMaxProbes is 0.
+	//
+	// Default: false (decide by majority once the budget is exhausted)
+	RequireAllSuccesses bool
+
+	// HalfOpenProbeTimeout bounds how long a half-open probe may hold its slot.
+	//
+	// A probe that never returns (a hung backend call without its own deadline)
+	// otherwise keeps one of the MaxRequests slots forever, and with the default
+	// MaxRequests of 1 the circuit never recovers. Once a probe has run for
+	// HalfOpenProbeTimeout, a watchdog releases its slot and records the probe
+	// as a failure, which reopens the circuit (or counts toward the
+	// HalfOpenMaxProbes decision). The outcome of the abandoned call, if it ever
+	// returns, is discarded.
+	//
+	// Default: 0 (no watchdog; a probe holds its slot until it returns)
+	HalfOpenProbeTimeout time.Duration
+
+	// ProbeRejectionDetails makes HalfOpen rejections return a
+	// *TooManyRequestsError instead of the bare ErrTooManyRequests. It wraps the
+	// sentinel (errors.Is matches, == does not) and reports whether a probe is
+	// executing and for how long, so callers can wait briefly for its verdict.
+	//
+	// Default: false (return ErrTooManyRequests, comparable with ==)
+	ProbeRejectionDetails bool
+
+	// EligibleProbeWait is how long HalfOpen holds out for a probe-eligible
+	// request (see ExecuteOpts.ProbeEligible) before admitting any request as a
+	// probe, so recovery isn't starved when no safe operation comes along.
+	// The wait starts on entering HalfOpen.
+	//
+	// Default: 0 (never fall back; only eligible requests probe)
+	EligibleProbeWait time.Duration
+
+	// RejectIneligibleAsOpen makes HalfOpen reject probe-ineligible requests
+	// with ErrOpenState, as if the circuit were still open, instead of
+	// ErrTooManyRequests.
+	//
+	// Default: false (reject with ErrTooManyRequests)
+	RejectIneligibleAsOpen bool
+
+	// ProbeFairnessWait is how long a fairness key (see ExecuteOpts.FairnessKey)
+	// that was just admitted as a half-open probe yields the next probe slot to
+	// other keys. If no other key arrives within the wait, the same key may
+	// probe again, so recovery isn't starved with a single tenant.
+	//
+	// Default: 1 second
+	ProbeFairnessWait time.Duration
+
+	// ProbeGate is consulted once Timeout has elapsed in Open, before the circuit
+	// moves to HalfOpen to probe. While it returns false the circuit stays Open
+	// past its timeout and keeps rejecting with ErrOpenState; the first request
+	// after it returns true probes as usual.
+	//
+	// Lets a global load shedder suppress recovery probes while the system is
+	// overloaded. It is called on every request that finds the open period over,
+	// so it must be cheap, such as an atomic load. A panic is logged and treated
+	// as true, so a faulty gate cannot hold the circuit open. MaxOpenDuration
+	// still bounds how long the circuit stays open.
+	//
+	// Thread-Safety: This callback must be thread-safe.
+	//
+	// Default: nil (probe as soon as Timeout elapses)
+	//
+	// Example:
+	//   ProbeGate: func() bool { return !shedder.Overloaded() },
+	ProbeGate func() bool
+
+	// TransitionLoserBehavior controls requests that lose the race to transition the
+	// circuit from Open to HalfOpen once Timeout has elapsed. See TransitionLoserProbe
+	// and TransitionLoserReject.
+	//
+	// With TransitionLoserReject, a burst arriving just as the circuit becomes ready
+	// to probe sees one execution (the winner) while the rest get ErrOpenState.
+	//
+	// Default: TransitionLoserProbe (losers compete for the MaxRequests probe slots)
+	TransitionLoserBehavior TransitionLoserBehavior
+
+	// StartHalfOpen makes a newly constructed breaker begin in HalfOpen instead of
+	// Closed, so the first request is a mandatory probe.
+	//
+	// Useful for cold starts behind a load balancer: a dead backend is detected by
+	// a single validating request rather than after failures accumulate. Admission
+	// follows the normal HalfOpen rules (MaxRequests, HalfOpenMaxProbes): a
+	// successful probe closes the circuit, a failed one opens it.
+	//
+	// Default: false (start Closed)
+	StartHalfOpen bool
+
 	// Interval is the period to clear counts in closed state.
 	//
 	// Valid range: >= 0 (negative values will panic)
@@ -201,13 +463,112 @@ type Settings struct {
 	// Common values: 60s for time-based windows, 0 for event-based
 	Interval time.Duration
 
+	// AlignIntervalToWallClock aligns Interval windows to wall-clock boundaries:
+	// each window starts at a multiple of Interval since the Unix epoch, so with
+	// Interval=60s windows begin at :00 of every minute. Replicas started at
+	// different times then clear their counts simultaneously and export
+	// comparable window metrics.
+	//
+	// Windows restarted by a state transition or UpdateSettings are aligned too,
+	// and Metrics.CountsLastClearedAt reports the aligned window start. Intervals
+	// that don't divide a day evenly (e.g. 7m) still align, to epoch multiples.
+	//
+	// The first window after construction is usually partial: it started at the
+	// previous boundary but only covers traffic since construction. By default it
+	// is evaluated like any other window (MinimumObservations still applies); see
+	// RequireFullWindow.
+	//
+	// Ignored when Interval is 0.
+	//
+	// Default: false (windows start when counts were last cleared)
+	AlignIntervalToWallClock bool
+
+	// RequireFullWindow holds off adaptive trips during the partial first window
+	// after construction when AlignIntervalToWallClock is set. The default adaptive
+	// ReadyToTrip does not trip until the first boundary has passed; static and
+	// custom ReadyToTrip thresholds are unaffected. Diagnostics.PartialWindow
+	// reports when trips are held off.
+	//
+	// Default: false (the partial first window is evaluated as-is)
+	RequireFullWindow bool
+
+	// PreserveStreaksOnIntervalReset keeps ConsecutiveFailures and
+	// ConsecutiveSuccesses when Interval elapses; only the windowed totals
+	// (Requests, TotalSuccesses, TotalFailures, FailureWeight) are cleared.
+	//
+	// Without it, a failure streak that straddles an interval boundary is
+	// forgotten, so a static threshold such as DefaultReadyToTrip
+	// (ConsecutiveFailures > 5) can be defeated by a well-timed reset: five
+	// failures before the boundary and five after never trip. With it, streaks
+	// are broken only by an outcome of the opposite kind or a state transition,
+	// and static thresholds trip on the run regardless of the window.
+	//
+	// Rate-based thresholds are unaffected: they use the windowed totals. Note
+	// that a preserved streak may exceed the windowed totals (e.g.
+	// ConsecutiveFailures > TotalFailures just after a reset).
+	//
+	// Ignored when Interval is 0.
+	//
+	// Default: false (interval resets clear the streaks too)
+	PreserveStreaksOnIntervalReset bool
+
+	// IntervalResetsOpenState lets an Interval boundary end the wait of an Open
+	// circuit: when the boundary passes while the circuit is Open, the next
+	// request moves it to HalfOpen and probes, as if Timeout had elapsed.
+	//
+	// This forgives long-lived open circuits whose backend may have recovered
+	// silently. The circuit probes after whichever comes first, Timeout or the
+	// next Interval boundary: Interval after it opened, or the next aligned
+	// boundary with AlignIntervalToWallClock. With Interval >= Timeout it has no
+	// effect. A failed probe reopens the circuit and the wait starts over.
+	// Diagnostics.TimeUntilHalfOpen and AlertSummary reflect the shorter wait.
+	//
+	// Ignored when Interval is 0.
+	//
+	// Default: false (an Open circuit waits for Timeout)
+	IntervalResetsOpenState bool
+
 	// Timeout is the duration to wait before transitioning from open to half-open.
 	//
+	// Timeout (like Interval) is measured on the monotonic clock, so wall-clock
+	// steps (NTP, VM migration) neither end an open period early nor hold it open.
+	//
 	// Valid range: > 0 recommended
 	// Default: 60 seconds if set to 0
 	// Common values: 10s-120s depending on service recovery time
 	Timeout time.Duration
 
+	// AdaptiveTimeout learns the open wait from how long the backend takes to
+	// recover, instead of always waiting Timeout.
+	//
+	// Every time a half-open probe closes the circuit, the breaker estimates the
+	// recovery time of that incident: the backend came back somewhere between
+	// the last sign it was down (the trip, or the last failed probe) and the
+	// successful probe, so the midpoint is taken. The learned timeout moves part
+	// of the way toward each estimate (an exponentially weighted average), so
+	// over several open/close cycles it converges toward the typical recovery
+	// time, kept within [MinTimeout, MaxTimeout].
+	//
+	// Timeout is the starting estimate, and updating Timeout with
+	// UpdateSettings restarts learning from the new value. Diagnostics reports
+	// the learned value as LearnedTimeout. A backend-requested backoff (see
+	// ExecuteWithHint) still takes precedence for the open period it applies to.
+	//
+	// Default: false (always wait Timeout)
+	AdaptiveTimeout bool
+
+	// MinTimeout is the lower bound of the learned timeout.
+	// Ignored unless AdaptiveTimeout is set.
+	//
+	// Default: 1 second if set to 0
+	MinTimeout time.Duration
+
+	// MaxTimeout is the upper bound of the learned timeout.
+	// Ignored unless AdaptiveTimeout is set.
+	//
+	// Default: 10 minutes if set to 0
+	MaxTimeout time.Duration
+
 	// ReadyToTrip is called when counts are updated in Closed state after each request.
 	// If it returns true, the circuit breaker transitions from Closed to Open (trips).
 	//
@@ -215,7 +576,8 @@ type Settings struct {
 	// and should return true when the failure threshold is exceeded.
 	//
 	// Default Implementation:
-	//   - When AdaptiveThreshold=false: Uses DefaultReadyToTrip (ConsecutiveFailures > 5)
+	//   - When AdaptiveThreshold=false: Uses DefaultReadyToTrip (ConsecutiveFailures > 5),
+	//     or ConsecutiveFailures > ConsecutiveFailureThreshold when that is set
 	//   - When AdaptiveThreshold=true: Uses adaptive logic (FailureRate > threshold && Requests >= MinimumObservations)
 	//
 	// Custom implementations can use any logic based on Counts fields:
@@ -244,6 +606,70 @@ type Settings struct {
 	//   }
 	ReadyToTrip func(counts Counts) bool
 
+	// ReadyToTripEx is ReadyToTrip with the context of the decision: when the
+	// observation window started, the current time and thresholds, and whether
+	// the default trip rule would trip (see WindowInfo). It enables time-aware
+	// rules and rules that refine the default decision instead of replacing it.
+	//
+	// If both are set, ReadyToTripEx is used and ReadyToTrip is never called.
+	// It is called wherever ReadyToTrip would be, with the same thread-safety,
+	// performance and panic-recovery rules.
+	//
+	// Example - Trip Only Once the Window Has Run 10s:
+	//   ReadyToTripEx: func(counts autobreaker.Counts, info autobreaker.WindowInfo) bool {
+	//       return info.DefaultWouldTrip && info.Now.Sub(info.WindowStart) >= 10*time.Second
+	//   }
+	//
+	// Default: nil (use ReadyToTrip)
+	ReadyToTripEx func(counts Counts, info WindowInfo) bool
+
+	// ShadowReadyToTrip is a candidate trip rule evaluated in shadow mode, for
+	// trying out new trip criteria against production traffic before switching.
+	//
+	// It is called with the same counts, at the same points, as the live trip
+	// rule, but its decision never changes the circuit state. A shadow trip is
+	// recorded the first time it returns true in an observation window (counts
+	// cleared by an Interval reset or a state transition re-arm it); see
+	// Diagnostics.ShadowTripCount and Diagnostics.ShadowWouldTrip. Compare
+	// ShadowTripCount with the live trips to see how often the rules diverge.
+	//
+	// A panic is logged and treated as "would not trip".
+	//
+	// Default: nil (no shadow evaluation)
+	//
+	// Example - trying a stricter rule before replacing the live one:
+	//   AdaptiveThreshold: true, // Live: trips above 5%
+	//   ShadowReadyToTrip: func(counts autobreaker.Counts) bool {
+	//       return counts.Requests >= 20 && counts.TotalFailures*100 > counts.Requests*2 // Above 2%
+	//   },
+	ShadowReadyToTrip func(counts Counts) bool
+
+	// ConsecutiveFailureThreshold changes the number of consecutive failures the
+	// default static trip rule tolerates: the circuit trips when
+	// ConsecutiveFailures > ConsecutiveFailureThreshold, i.e. on failure
+	// ConsecutiveFailureThreshold+1. DefaultReadyToTrip corresponds to 5.
+	//
+	// Saves writing a ReadyToTrip closure just to change the count. Shadowed by
+	// ReadyToTrip and by AdaptiveThreshold, which replace the static rule.
+	//
+	// Default: 0 (DefaultReadyToTrip, trips after 5 consecutive failures)
+	ConsecutiveFailureThreshold uint32
+
+	// Engine replaces the trip rule with a custom DecisionEngine, such as
+	// EWMAEngine. The engine sees every outcome recorded while Closed, decides
+	// when the circuit trips, and may reject requests in Closed and HalfOpen
+	// (see DecisionEngine). ReadyToTrip, AdaptiveThreshold and
+	// ConsecutiveFailureThreshold are then ignored; Timeout and half-open
+	// recovery work as usual.
+	//
+	// Not updateable at runtime. WillTripNext is always false with an engine,
+	// which cannot be asked about a hypothetical failure.
+	//
+	// Thread-Safety: The engine must be safe for concurrent use.
+	//
+	// Default: nil (the trip rule configured by the fields above)
+	Engine DecisionEngine
+
 	// OnStateChange is called whenever the circuit breaker transitions between states.
 	// It receives the circuit name, previous state, and new state.
 	//
@@ -262,8 +688,8 @@ type Settings struct {
 	//   - HalfOpen → Open: Probe requests failed, backend still unhealthy
 	//
 	// Important: This callback is invoked AFTER counts are cleared. If you need
-	// pre-transition counts (e.g., to log "tripped after N failures"), capture
-	// them in your ReadyToTrip callback instead, which runs before the transition.
+	// pre-transition counts (e.g., to log "tripped after N failures"), use
+	// OnStateChangeDetailed instead.
 	//
 	// Thread-Safety: This callback must be thread-safe. It may be called concurrently
 	// from multiple goroutines during state transitions.
@@ -293,6 +719,56 @@ type Settings struct {
 	//   }
 	OnStateChange func(name string, from State, to State)
 
+	// OnStateChangeDetailed is like OnStateChange but also receives the counts
+	// at the transition, captured just before they are cleared. On Closed → Open
+	// these are the counts that tripped the circuit; on HalfOpen → Closed or
+	// Open, the probe counts.
+	//
+	// When both are set, OnStateChange is called first. The same thread-safety
+	// and performance guidance applies.
+	//
+	// Default: nil (no callback)
+	//
+	// Example:
+	//   OnStateChangeDetailed: func(name string, from, to autobreaker.State, counts autobreaker.Counts) {
+	//       if to == autobreaker.StateOpen {
+	//           log.Printf("circuit %s tripped after %d failures in %d requests",
+	//               name, counts.TotalFailures, counts.Requests)
+	//       }
+	//   }
+	OnStateChangeDetailed func(name string, from, to State, countsAtTransition Counts)
+
+	// OrderStateChanges delivers OnStateChange and OnStateChangeDetailed in
+	// exactly the order the transitions were committed.
+	//
+	// By default each callback runs on the goroutine that won the transition,
+	// right after it. Under heavy concurrency transitions can follow each other
+	// within microseconds (Open → HalfOpen → Open on a failed probe), and the
+	// goroutines reporting them race: a subscriber can see HalfOpen → Closed
+	// before Open → HalfOpen. With OrderStateChanges, callbacks are dispatched
+	// one at a time, and each event's from is the previous event's to.
+	//
+	// The state machine stays lock-free. A transition that finds another one's
+	// callback running queues its event instead of waiting, and the goroutine
+	// already dispatching delivers it next. A callback may therefore run on the
+	// goroutine of a different transition, after the call that caused it has
+	// returned. Nested transitions from inside a callback are queued the same way.
+	//
+	// Default: false (callbacks run on the transitioning goroutine, unordered)
+	OrderStateChanges bool
+
+	// OnDisabledChange is called when the breaker is disabled (disabled=true) or
+	// re-enabled (disabled=false) via Disable() and Enable(). Repeated calls that
+	// don't change the mode do not fire it.
+	//
+	// Use this to audit operator bypasses; State does not change, so
+	// OnStateChange does not fire.
+	//
+	// Thread-Safety: This callback must be thread-safe.
+	//
+	// Default: nil (no callback)
+	OnDisabledChange func(name string, disabled bool)
+
 	// IsSuccessful determines whether an error should be counted as success or failure.
 	// It receives the error returned by the request function passed to Execute().
 	//
@@ -325,6 +801,11 @@ type Settings struct {
 	// Avoid I/O, logging, or expensive computations.
 	//
 	// Note: Panics are always counted as failures, regardless of this callback.
+	// A panic in this callback is recorded per ClassifierPanicOutcome.
+	//
+	// Note: Errors matching ErrIgnoreOutcome (errors.Is) never reach this callback;
+	// they are neither successes nor failures. Nor do rejections from nested
+	// breakers, unless CountNestedRejections is set.
 	//
 	// Example - HTTP Client:
 	//   IsSuccessful: func(err error) bool {
@@ -338,6 +819,161 @@ type Settings struct {
 	//   }
 	IsSuccessful func(err error) bool
 
+	// OutcomeWeight classifies a completed call as a fractional failure. It receives
+	// the result and error returned by the request function and returns a weight in
+	// [0, 1]: the call counts as weight failures and (1 - weight) successes.
+	//
+	// Use it when a call can partially fail and the result is needed to judge how
+	// badly, e.g. a batch fetch where some items failed. When set, IsSuccessful is
+	// not called.
+	//
+	// Accumulation:
+	//   - Counts.FailureWeight accumulates the weights; adaptive thresholds
+	//     (FailureRateThreshold, WarnFailureRate, RecoverFailureRate) and
+	//     Metrics.FailureRate use FailureWeight / Requests
+	//   - TotalFailures, TotalSuccesses, the consecutive counters and half-open
+	//     decisions count each call as a whole: a weight above 0.5 is a failure,
+	//     0.5 or below is a success
+	//   - In Closed state, any call with a non-zero weight is evaluated against
+	//     ReadyToTrip, so partial failures alone can trip an adaptive breaker
+	//
+	// Out-of-range weights are clamped to [0, 1]; NaN counts as a full failure.
+	// Panics are counted as a full failure (weight 1). A panic in OutcomeWeight
+	// itself is recorded per ClassifierPanicOutcome. Errors matching
+	// ErrIgnoreOutcome never reach this callback.
+	//
+	// The result is returned to the caller unchanged regardless of the weight.
+	//
+	// Thread-Safety: This callback must be thread-safe.
+	//
+	// Default: nil (IsSuccessful decides; every call is a whole success or failure)
+	//
+	// Example - Batch Fetch:
+	//   OutcomeWeight: func(result interface{}, err error) float64 {
+	//       batch, ok := result.(*BatchResult)
+	//       if !ok {
+	//           if err != nil { return 1 }
+	//           return 0
+	//       }
+	//       return float64(batch.Failed) / float64(batch.Total)
+	//   }
+	OutcomeWeight func(result interface{}, err error) float64
+
+	// ClassifierPanicOutcome controls how a call is recorded when IsSuccessful or
+	// OutcomeWeight panics. The panic is always recovered, logged and counted in
+	// Metrics.ClassifierPanics; the request's own result and error are returned
+	// to the caller unchanged.
+	//
+	//   - ClassifierPanicDefault: Classify by err == nil, like DefaultIsSuccessful
+	//   - ClassifierPanicFailure: Count as a failure (fail closed)
+	//   - ClassifierPanicSuccess: Count as a success (fail open)
+	//   - ClassifierPanicIgnore: Count as neither, like ErrIgnoreOutcome
+	//
+	// The default keeps a classifier bug from taking a healthy backend out of
+	// service while failing calls still trip the circuit. Choose
+	// ClassifierPanicFailure to fail closed.
+	//
+	// Panics in the request function itself are always failures.
+	//
+	// Default: ClassifierPanicDefault
+	ClassifierPanicOutcome ClassifierPanicOutcome
+
+	// ClassifierPanicLatch stops calling IsSuccessful after this many
+	// consecutive calls to it panicked: the breaker latches to
+	// DefaultIsSuccessful (err == nil) and logs a warning once, instead of
+	// recovering a panic on every request. A call that does not panic resets
+	// the streak. The latch is released when SettingsUpdate.IsSuccessful
+	// installs a replacement. See Diagnostics.ClassifierLatched.
+	//
+	// Has no effect with OutcomeWeight, which is not replaceable.
+	//
+	// Default: 0 (never latch)
+	ClassifierPanicLatch uint32
+
+	// RecoverPanics makes Execute and ExecuteContext return a panic in the
+	// request function as a *PanicError instead of re-panicking, so callers
+	// don't need their own recover. The panic still counts as a failure and can
+	// trip the circuit; the *PanicError carries the panic value and stack.
+	//
+	// While the breaker is disabled, panics are converted too, without being
+	// counted.
+	//
+	// Default: false (re-panic with the original value)
+	RecoverPanics bool
+
+	// IsProbeSuccessful decides whether a half-open probe closes the circuit,
+	// allowing stricter criteria for probes than for regular traffic (e.g. a
+	// latency bound or a deep health field in the response).
+	//
+	// It is called only for requests executed via Execute, ExecuteContext, or
+	// ExecuteClassified while the circuit is HalfOpen, with the request's result,
+	// error, and the duration of the request function alone. Returning false
+	// reopens the circuit (or counts against the probe budget with
+	// HalfOpenMaxProbes).
+	//
+	// The probe is still counted by the normal classifier (IsSuccessful,
+	// OutcomeWeight, or the ExecuteClassified flag), so Counts and Metrics stay
+	// truthful: a degraded-but-successful probe is recorded as a success even
+	// though the circuit reopens. Calls classified as ignored (ErrIgnoreOutcome,
+	// nested rejections) never reach it, and panicking probes always fail.
+	// Record() observations are decided by their success flag alone.
+	//
+	// A panic in this callback is recovered and the probe treated as failed.
+	//
+	// Thread-Safety: This callback must be thread-safe.
+	//
+	// Default: nil (the normal classifier also decides probes)
+	//
+	// Example:
+	//   IsProbeSuccessful: func(result interface{}, err error, d time.Duration) bool {
+	//       resp, ok := result.(*HealthResponse)
+	//       return err == nil && ok && resp.Deep == "ok" && d < 100*time.Millisecond
+	//   }
+	IsProbeSuccessful func(result interface{}, err error, duration time.Duration) bool
+
+	// CountNestedRejections makes rejections from nested circuit breakers count as
+	// failures.
+	//
+	// With layered clients, a request function may call another breaker-protected
+	// function and return its rejection. By default such errors (anything matching
+	// ErrOpenState, ErrTooManyRequests, ErrTooManyConcurrent, ErrDependencyOpen, or
+	// ErrDeadlineTooShort via errors.Is) are ignored outcomes, like ErrIgnoreOutcome: neither a success
+	// nor a failure, so an outage of one deep dependency doesn't trip every breaker
+	// up the stack. They never reach IsSuccessful or OutcomeWeight.
+	//
+	// Set to true for the previous behavior, where nested rejections are classified
+	// by IsSuccessful (DefaultIsSuccessful counts them as failures). The explicit
+	// flag passed to ExecuteClassified also takes precedence.
+	//
+	// Default: false (nested rejections are ignored)
+	CountNestedRejections bool
+
+	// IsStreamFailure classifies the error that ends a stream returned by
+	// ExecuteStream or ExecuteHTTP: a Read error, or ErrStreamClosedEarly when
+	// the stream is closed before EOF. Returning true records a failure;
+	// returning false records neither success nor failure. A clean EOF is always
+	// a success.
+	//
+	// Use TransportStreamFailure to count only truncated streams
+	// (io.ErrUnexpectedEOF) and network errors.
+	//
+	// A panic in this callback is recovered and the error treated as a failure.
+	//
+	// Thread-Safety: This callback must be thread-safe.
+	//
+	// Default: nil (every stream error, including closing early, is a failure)
+	IsStreamFailure func(err error) bool
+
+	// StreamTimeout bounds how long a stream returned by ExecuteStream or
+	// ExecuteHTTP may hold its HalfOpen and MaxConcurrent slots. A stream still
+	// open after StreamTimeout is detached: its slots are released and its
+	// outcome is not recorded (see Metrics.StreamTimeouts). The stream itself is
+	// not interrupted.
+	//
+	// Valid range: >= 0
+	// Default: 1 minute if set to 0
+	StreamTimeout time.Duration
+
 	// --- Adaptive Settings (AutoBreaker Extensions) ---
 
 	// AdaptiveThreshold enables percentage-based failure thresholds.
@@ -355,6 +991,10 @@ type Settings struct {
 	// FailureRateThreshold is the failure rate (0.0-1.0) that triggers circuit open.
 	// Only used when AdaptiveThreshold is true.
 	//
+	// The circuit trips when the failure rate is strictly above the threshold
+	// (see RateEpsilon for the exact comparison); a rate exactly at the threshold
+	// does not trip.
+	//
 	// Valid range: (0, 1) exclusive - values outside this range will panic
 	// Default: 0.05 (5% failure rate) if set to 0
 	// Recommended values:
@@ -380,14 +1020,679 @@ type Settings struct {
 	//   First 19 requests: Circuit won't trip regardless of failure rate
 	//   20+ requests: Circuit trips if failure rate exceeds 5%
 	MinimumObservations uint32
+
+	// MinObservationWindow additionally requires MinimumObservations requests within
+	// this recent window before adaptive logic activates.
+	// Only used when AdaptiveThreshold is true and ReadyToTrip is nil.
+	//
+	// MinimumObservations alone is a raw count: with a long Interval (or none), 20
+	// requests trickling in over an hour activate evaluation just as 20 requests in
+	// the last second do. With MinObservationWindow set, a rate is only judged when
+	// the traffic behind it is recent and dense enough to be meaningful.
+	//
+	// Recent requests are counted in 10 sub-periods of MinObservationWindow/10, so
+	// the window covered is between 90% and 100% of MinObservationWindow.
+	//
+	// Valid range: >= 0
+	// Default: 0 (disabled - MinimumObservations counts the whole Interval)
+	//
+	// Example: With MinimumObservations=20 and MinObservationWindow=10s:
+	//   20 requests over the last minute: Circuit won't trip regardless of failure rate
+	//   20 requests within the last 10s: Circuit trips if failure rate exceeds threshold
+	MinObservationWindow time.Duration
+
+	// RecoverFailureRate is the failure rate (0.0-1.0) at or below which the backend
+	// is considered recovered after the trip rate (FailureRateThreshold) was exceeded.
+	// Only used when AdaptiveThreshold is true.
+	//
+	// Using the same rate for tripping and for recovering causes flapping near the
+	// boundary. With RecoverFailureRate below FailureRateThreshold, the circuit opens
+	// at the higher rate but is only trusted again at the lower one:
+	//
+	//   - Tripping: once the trip rate has been exceeded (the circuit tripped, or the
+	//     rate went above it while the trip was held off), the adaptive trip rule
+	//     compares against RecoverFailureRate until MinimumObservations requests
+	//     show a rate at or below it (or, with RecoveryWindows, that many clean
+	//     windows after re-closing). A backend that re-closes still failing just
+	//     under FailureRateThreshold reopens instead of bouncing around it.
+	//   - Closing: with HalfOpenMaxProbes, the circuit closes only if the probes
+	//     failed at a rate at or below RecoverFailureRate.
+	//   - Health: Diagnostics.Healthy stays false until the rate has recovered.
+	//
+	// A healthy backend whose rate hovers between the two thresholds changes
+	// neither the state nor the health signal. Diagnostics.RecoveryComparison
+	// reports whether the trip rule is currently using RecoverFailureRate.
+	//
+	// Runtime-tunable via SettingsUpdate.RecoverFailureRate.
+	//
+	// Valid range: (0, FailureRateThreshold]
+	// Default: FailureRateThreshold if set to 0 (no hysteresis)
+	RecoverFailureRate float64
+
+	// RecoveryWindows makes a recovery (see RecoverFailureRate) take clean Interval
+	// windows instead of MinimumObservations requests. After a HalfOpen → Closed
+	// transition the circuit keeps tripping on RecoverFailureRate until this many
+	// full windows have ended at or below it, each with at least
+	// MinimumObservations requests. Windows with too little traffic neither count
+	// nor break the run. Diagnostics.RecoveryWindowsRemaining reports the windows
+	// still required.
+	//
+	// A backend that has just recovered often still fails at a rate just under
+	// FailureRateThreshold for a while; requiring whole windows keeps one good
+	// stretch of requests from ending the recovery early.
+	//
+	// Only used when AdaptiveThreshold is true, RecoverFailureRate is below
+	// FailureRateThreshold and Interval > 0.
+	//
+	// Default: 0 (recovered as soon as MinimumObservations requests show it)
+	RecoveryWindows uint32
+
+	// RateEpsilon is the tolerance used when comparing a failure rate against a
+	// threshold (FailureRateThreshold, WarnFailureRate).
+	//
+	// Failure rates are computed with float division (TotalFailures / Requests), so a
+	// rate that is conceptually exactly at the threshold may compute as a hair above
+	// or below it. The exact comparison is:
+	//
+	//	rate > threshold + RateEpsilon
+	//
+	// so a rate at the threshold (e.g. 10/100 with threshold 0.10) never trips, and
+	// the first rate meaningfully above it always does.
+	//
+	// Valid range: [0, 0.01)
+	// Default: 1e-9 if set to 0
+	RateEpsilon float64
+
+	// Baseline pairs this breaker with another one protecting the same backend, for
+	// canary comparison. The adaptive trip rule then compares this breaker's failure
+	// rate against the baseline's instead of FailureRateThreshold:
+	//
+	//	rate > RelativeFailureRateMultiplier × baselineRate + RateEpsilon
+	//
+	// Only used when AdaptiveThreshold is true and ReadyToTrip is nil.
+	//
+	// During a canary deploy, give the canary's breaker the stable deployment's
+	// breaker as Baseline: the canary trips when it fails significantly more than
+	// the baseline, rather than when the backend as a whole is unhealthy.
+	//
+	// The baseline's counts are read with Counts(), which never blocks. Both rates
+	// are subject to their own MinimumObservations (the baseline's, or this
+	// breaker's if the baseline is not adaptive). While the baseline has fewer, the
+	// comparison falls back to the absolute FailureRateThreshold.
+	//
+	// A baseline with no failures makes any canary failure rate above RateEpsilon
+	// trip. The warn band, early warning and health hysteresis stay absolute.
+	//
+	// Default: nil (absolute FailureRateThreshold)
+	//
+	// Example:
+	//   stable := autobreaker.New(autobreaker.Settings{Name: "api-stable", AdaptiveThreshold: true})
+	//   canary := autobreaker.New(autobreaker.Settings{
+	//       Name:                          "api-canary",
+	//       AdaptiveThreshold:             true,
+	//       Baseline:                      stable,
+	//       RelativeFailureRateMultiplier: 2, // Trip at twice the stable failure rate
+	//   })
+	Baseline *CircuitBreaker
+
+	// RelativeFailureRateMultiplier is how many times the Baseline's failure rate
+	// this breaker's rate must exceed to trip.
+	// Only used when Baseline is set.
+	//
+	// Valid range: > 0 (values below 1 trip before the canary is worse than the baseline)
+	// Default: 2.0 if set to 0
+	RelativeFailureRateMultiplier float64
+
+	// ErrorBudget is the failure fraction the service level objective allows,
+	// e.g. 0.001 for 99.9% availability. When set, the adaptive trip rule trips on
+	// burn rate instead of FailureRateThreshold:
+	//
+	//	rate > ErrorBudget × BurnRateThreshold + RateEpsilon
+	//
+	// The burn rate is the window's failure rate divided by ErrorBudget: how many
+	// times faster than sustainable the window spends the budget. A burn rate of 1
+	// spends exactly the budget over the SLO period.
+	//
+	// Only used when AdaptiveThreshold is true and ReadyToTrip is nil. The
+	// window is the usual Interval and MinimumObservations applies; a small budget
+	// needs a MinimumObservations large enough that one failure does not exceed
+	// the threshold. RecoverFailureRate and Baseline still take precedence, and
+	// the warn band, early warning and health hysteresis stay based on
+	// FailureRateThreshold.
+	//
+	// Valid range: [0, 1)
+	// Default: 0 (trip on FailureRateThreshold)
+	//
+	// Example - page-level fast burn on a 99.9% SLO:
+	//   Settings{
+	//       AdaptiveThreshold:   true,
+	//       ErrorBudget:         0.001,
+	//       BurnRateThreshold:   14, // Trip above a 1.4% failure rate
+	//       MinimumObservations: 1000,
+	//   }
+	ErrorBudget float64
+
+	// BurnRateThreshold is the burn rate above which the circuit trips.
+	// Only used when ErrorBudget is set.
+	//
+	// Valid range: > 0 (values below 1 trip before the budget is spent)
+	// Default: 1.0 if set to 0
+	BurnRateThreshold float64
+
+	// PredictiveReject enables deadline-aware rejection in ExecuteContext.
+	//
+	// When true, the circuit breaker tracks request latency and learns the backend's
+	// 95th percentile (p95). If the context passed to ExecuteContext has a deadline
+	// that leaves less time than the learned p95, the request is rejected with
+	// ErrDeadlineTooShort before it is executed, rather than starting a call that
+	// will almost certainly time out.
+	//
+	// Rejections are not counted as requests and never affect circuit state.
+	// No requests are rejected until enough latency observations (20) have been
+	// collected to estimate the p95.
+	//
+	// Default: false (no latency tracking, deadlines are not inspected)
+	PredictiveReject bool
+
+	// TrackLatency records request latencies in the fixed-bucket histogram
+	// exposed by LatencyHistogram(), without enabling PredictiveReject.
+	// PredictiveReject implies latency tracking.
+	//
+	// The histogram has a fixed number of buckets, so memory use is constant
+	// regardless of traffic.
+	//
+	// Default: false (latency is tracked only with PredictiveReject)
+	TrackLatency bool
+
+	// SlowCallFactor counts a call as a failure when it would have been a
+	// success but took longer than the learned p95 latency times SlowCallFactor,
+	// so "slow" follows the backend's own baseline instead of a hand-tuned
+	// absolute threshold. Slow calls are recorded like any other failure and can
+	// trip the circuit; see Metrics.SlowCalls and Diagnostics.SlowCallThreshold.
+	// This applies to every recorded call, including ExecuteClassified successes
+	// and streams (timed until they finish).
+	//
+	// The p95 comes from the latency histogram (implied by SlowCallFactor), which
+	// has 2x log-scale buckets and reports a bucket's upper bound: the p95 is
+	// over-estimated by up to 2x, so a factor of 1.5 already flags calls about 3x
+	// slower than a steady baseline. No call is slow until the p95 has been
+	// learned (20 observations). The histogram is never reset, so a lasting
+	// slowdown gradually becomes the new baseline.
+	//
+	// Valid range: 0 or >= 1
+	// Default: 0 (latency does not affect outcomes)
+	SlowCallFactor float64
+
+	// EpisodeHistory is the number of finished open periods retained by
+	// Episodes() and reported in Diagnostics.Episodes. Each episode runs from the
+	// circuit leaving Closed until it closes again and records its duration, the
+	// reason it opened, and the requests rejected and probes attempted meanwhile:
+	// the impact of one incident on callers.
+	//
+	// Valid range: >= 0
+	// Default: 0 (episodes are not tracked)
+	EpisodeHistory int
+
+	// --- Warning Level ---
+
+	// WarnFailureRate is the failure rate (0.0-1.0) above which the circuit is
+	// considered degraded, without tripping.
+	//
+	// This provides an intermediate alerting level below the hard trip point, e.g.
+	// warn at 5% and trip at 15%, so on-call can be paged before the circuit opens.
+	// While the failure rate exceeds WarnFailureRate in Closed state, Metrics.Degraded
+	// and Diagnostics.Degraded are true.
+	//
+	// The warn band is evaluated only once Requests >= MinimumObservations.
+	//
+	// Valid range: [0, 1); must be below FailureRateThreshold when AdaptiveThreshold=true
+	// Default: 0 (disabled)
+	WarnFailureRate float64
+
+	// OnDegraded is called when the failure rate crosses above WarnFailureRate
+	// without tripping the circuit. It receives the circuit name and the failure rate
+	// at the time of the crossing.
+	//
+	// Fires once per crossing: it will not fire again until the rate has dropped back
+	// to or below WarnFailureRate, or counts have been cleared (interval reset or
+	// state transition).
+	//
+	// Thread-Safety: This callback must be thread-safe.
+	//
+	// Example:
+	//   OnDegraded: func(name string, rate float64) {
+	//       go pager.Warn("circuit %s degraded: %.1f%% failures", name, rate*100)
+	//   }
+	OnDegraded func(name string, rate float64)
+
+	// WarningThresholdFraction enables an early warning below the trip point,
+	// expressed as a fraction of FailureRateThreshold.
+	//
+	// With FailureRateThreshold=0.10 and WarningThresholdFraction=0.6, OnWarning
+	// fires when the failure rate rises above 6%. Only used when AdaptiveThreshold
+	// is true, and only once Requests >= MinimumObservations.
+	//
+	// Latching: OnWarning fires at most once per excursion. The warning latches
+	// until counts are cleared (interval reset or state transition) or the rate
+	// drops below 90% of the warning level, so a rate hovering around the boundary
+	// doesn't fire on every request. Diagnostics.WarningLatched reports the latch.
+	//
+	// Runtime-tunable via SettingsUpdate.WarningThresholdFraction.
+	//
+	// Valid range: [0, 1)
+	// Default: 0 (disabled)
+	WarningThresholdFraction float64
+
+	// OnWarning is called when the failure rate crosses the early-warning level
+	// (WarningThresholdFraction × FailureRateThreshold). It receives the circuit
+	// name, the failure rate, and the counts at the time of the crossing.
+	//
+	// Thread-Safety: This callback must be thread-safe.
+	//
+	// Example:
+	//   OnWarning: func(name string, rate float64, counts autobreaker.Counts) {
+	//       go alerter.Warn("circuit %s at %.1f%% failures (%d requests)",
+	//           name, rate*100, counts.Requests)
+	//   }
+	OnWarning func(name string, failureRate float64, counts Counts)
+
+	// --- Panic Storm ---
+
+	// PanicThreshold is the number of panics in one window (Counts.Panics) that
+	// OnPanicThreshold tolerates: it fires when the count exceeds this.
+	//
+	// Default: 0 (the first panic in a window fires OnPanicThreshold)
+	PanicThreshold uint32
+
+	// OnPanicThreshold is called when the panics in the current window exceed
+	// PanicThreshold, at most once per window (the latch resets whenever the
+	// counts are cleared). It receives the circuit name and the panic count.
+	//
+	// Panics still count as failures and trip the circuit as before. This is a
+	// separate signal for paging the team that owns the calling code: a panic
+	// storm usually means a bad deploy, not an unhealthy dependency. The
+	// journal's JournalEntry.PanicType names the panic values' types for triage.
+	//
+	// Called synchronously from the request that crossed the threshold, before
+	// the failure is evaluated for tripping.
+	//
+	// Thread-Safety: This callback must be thread-safe.
+	//
+	// Example:
+	//   OnPanicThreshold: func(name string, panics uint32) {
+	//       go pager.Page("panic storm calling %s: %d panics this window", name, panics)
+	//   }
+	OnPanicThreshold func(name string, panicsInWindow uint32)
+
+	// --- Stuck-Open Protection ---
+
+	// MaxOpenDuration caps how long a circuit may stay open within one incident
+	// before OnStuckOpen is invoked and StuckOpenAction is taken.
+	//
+	// An incident starts when the circuit trips and ends when it closes; cycles
+	// through failed half-open probes stay within the same incident, so a buggy
+	// ReadyToTrip or an always-failing probe can't blackhole traffic silently.
+	// While stuck, the callback and action repeat once every MaxOpenDuration.
+	//
+	// Evaluated lazily, without a background goroutine: the check runs when
+	// Execute, ExecuteContext, or Diagnostics observes the circuit Open, so an
+	// idle breaker is only remediated once it sees traffic again. Not evaluated
+	// during maintenance (see EnterMaintenance).
+	//
+	// Valid range: >= 0
+	// Default: 0 (unlimited)
+	MaxOpenDuration time.Duration
+
+	// OnStuckOpen is called when the circuit has been open longer than
+	// MaxOpenDuration within one incident. It receives the circuit name and how
+	// long the incident has lasted, and runs before StuckOpenAction is taken.
+	//
+	// Thread-Safety: This callback must be thread-safe.
+	//
+	// Example:
+	//   OnStuckOpen: func(name string, openFor time.Duration) {
+	//       go pager.Page("circuit %s stuck open for %s", name, openFor)
+	//   }
+	OnStuckOpen func(name string, openFor time.Duration)
+
+	// StuckOpenAction is the remediation taken once MaxOpenDuration is exceeded.
+	// See StuckOpenAlert, StuckOpenForceHalfOpen, and StuckOpenForceClose.
+	//
+	// Default: StuckOpenAlert (callback only)
+	StuckOpenAction StuckOpenAction
+
+	// --- Cycle Limit ---
+
+	// MaxRequestsPerCycle caps how many requests the circuit admits during one
+	// closed period. Once the cap is reached, the next request opens the circuit
+	// (OpenReasonCycleLimit) and is rejected with ErrOpenState; after Timeout the
+	// circuit probes and, on closing, starts a new cycle with a fresh allowance.
+	//
+	// This is a crude load shedder tied to the breaker, useful for chaos testing
+	// and rate-governed backends. The allowance counts admitted requests
+	// regardless of outcome and is not cleared by Interval; half-open probes and
+	// Record() observations do not count against it.
+	//
+	// Valid range: >= 0
+	// Default: 0 (unlimited)
+	MaxRequestsPerCycle uint32
+
+	// --- Minimum Closed Duration ---
+
+	// MinClosedDuration keeps the circuit closed for at least this long after
+	// it recovers (HalfOpen → Closed), to damp flapping. A trip decided within
+	// the window is deferred, not dropped: the request that failed is recorded
+	// as usual, and the first request after the window ends re-evaluates the
+	// trip rule on the counts at that time. If the failure rate is still bad,
+	// the circuit opens and that request is rejected with ErrOpenState;
+	// otherwise the circuit stays closed.
+	//
+	// Only recoveries start the window: a new breaker, or one closed by
+	// StuckOpenAction, trips as usual. MaxRequestsPerCycle is not deferred.
+	//
+	// Valid range: >= 0
+	// Default: 0 (trip as soon as the trip rule is met)
+	MinClosedDuration time.Duration
+
+	// --- Bulkhead ---
+
+	// MaxConcurrent limits how many requests may execute at once through
+	// Execute() and ExecuteContext(), in any state. Requests beyond the limit
+	// are rejected with ErrTooManyConcurrent (after waiting up to
+	// MaxConcurrentWait). Bulkhead rejections are not counted as requests and
+	// never affect the circuit state.
+	//
+	// The limit isolates a slow backend from exhausting the caller's goroutines
+	// or connections, independently of failure detection.
+	//
+	// Default: 0 (unlimited)
+	MaxConcurrent uint32
+
+	// MaxConcurrentWait is how long a request waits for a MaxConcurrent slot
+	// before being rejected with ErrTooManyConcurrent. With ExecuteContext, the
+	// wait also ends when the context is done, returning the context error.
+	//
+	// Time spent waiting is reported as Metrics.AvgWaitTime so operators can see
+	// contention.
+	//
+	// Valid range: >= 0
+	// Default: 0 (reject immediately when all slots are busy)
+	MaxConcurrentWait time.Duration
+
+	// --- Result Cache ---
+
+	// CacheTTL enables serving the last-known-good result from
+	// ExecuteWithCache() and ExecuteCached() while the circuit rejects requests.
+	//
+	// ExecuteWithCache() keeps the result of the most recent call that returned
+	// a nil error in Closed state. When the circuit rejects a later call with
+	// ErrOpenState, that result is returned instead, flagged as stale, as long
+	// as it is no older than CacheTTL. Execute() and ExecuteContext() neither
+	// populate nor consult the cache unless CacheLastSuccess is set.
+	//
+	// Only a single value is kept per breaker, so this suits breakers guarding
+	// one read-mostly resource (a config document, a price list), not calls
+	// whose results depend on their arguments.
+	//
+	// Memory: the cached value is held by reference until a newer result
+	// replaces it, even after it has expired. A large result (or one that pins
+	// a large object graph) stays reachable for the breaker's lifetime.
+	//
+	// Default: 0 (no caching, ExecuteWithCache behaves like Execute)
+	CacheTTL time.Duration
+
+	// CacheLastSuccess makes every call that returns a nil error in Closed
+	// state, through Execute(), ExecuteContext() or any other execution method,
+	// replace the cached result. Without it only ExecuteWithCache() and
+	// ExecuteCached() calls populate the cache, so a breaker shared between
+	// plain and cached call sites may serve an older value than it has seen.
+	// Only used when CacheTTL is set.
+	//
+	// Default: false
+	CacheLastSuccess bool
+
+	// ServeStaleOnTooManyRequests also serves the cached result when a
+	// HalfOpen circuit rejects a call with ErrTooManyRequests (all probe slots
+	// busy), not only on ErrOpenState. Only used when CacheTTL is set.
+	//
+	// Default: false (ErrTooManyRequests is returned as-is)
+	ServeStaleOnTooManyRequests bool
+
+	// --- Threshold Recommendation ---
+
+	// RecommendationWindow enables the baseline analyzer behind Recommendation():
+	// the breaker learns the failure rate it normally sees over this window and
+	// suggests a FailureRateThreshold from it.
+	//
+	// The window is divided into RecommendationBucket periods. Each completed
+	// period with traffic contributes one failure-rate observation; the
+	// suggestion is the mean of the observations within the window plus three
+	// standard deviations, clamped to [RecommendationMinThreshold,
+	// RecommendationMaxThreshold]. Observations are recorded by requests, so
+	// memory is fixed (one slot per period) and no goroutine is started.
+	//
+	// Advisory only unless AutoTune is set.
+	//
+	// Valid range: >= 0, and at least RecommendationBucket
+	// Default: 0 (no analyzer)
+	// Typical: 24 * time.Hour
+	RecommendationWindow time.Duration
+
+	// RecommendationBucket is the observation period of the baseline analyzer.
+	// At most 10000 periods fit in RecommendationWindow.
+	//
+	// Default: time.Hour when RecommendationWindow is set
+	RecommendationBucket time.Duration
+
+	// RecommendationMinThreshold and RecommendationMaxThreshold bound suggested
+	// thresholds (and so thresholds applied by AutoTune). The lower bound keeps
+	// a near-perfect baseline from producing a hair-trigger breaker; the upper
+	// bound keeps a noisy one from producing a breaker that never trips.
+	//
+	// Valid range: (0, 1), Min < Max
+	// Default: 0.01 and 0.5
+	RecommendationMinThreshold float64
+	RecommendationMaxThreshold float64
+
+	// AutoTune applies the recommendation to FailureRateThreshold through
+	// UpdateSettings, at most once per AutoTuneInterval, once the
+	// recommendation reaches ConfidenceMedium. Each applied change is reported
+	// to OnAutoTune as a ChangeSet.
+	//
+	// Requires AdaptiveThreshold and RecommendationWindow.
+	//
+	// Default: false (recommendations are advisory)
+	AutoTune bool
+
+	// AutoTuneInterval is the minimum time between AutoTune changes.
+	//
+	// Default: RecommendationBucket
+	AutoTuneInterval time.Duration
+
+	// OnAutoTune is called after AutoTune changes FailureRateThreshold, with the
+	// ChangeSet returned by UpdateSettingsDetailed, for audit logging. It is not
+	// called when the recommendation equals the current threshold.
+	//
+	// Called synchronously from the request that completed an observation
+	// period; keep it fast. Panics are recovered and logged.
+	//
+	// Default: nil
+	OnAutoTune func(name string, changes ChangeSet)
+
+	// --- Observability ---
+
+	// DiagnosticsCacheTTL caches the Diagnostics.WillTripNext prediction for the
+	// given duration. Computing it invokes ReadyToTrip, which can be noticeable
+	// when Diagnostics() is polled frequently across many breakers. Only worthwhile
+	// with an expensive custom ReadyToTrip: checking the cache reads the clock,
+	// which costs more than the default trip rules.
+	//
+	// Cached predictions may lag the counts by up to the TTL, except that
+	// WillTripNext is always false outside Closed state. Use QuickStats() instead
+	// of Diagnostics() when predictions aren't needed at all.
+	//
+	// Valid range: >= 0
+	// Default: 0 (no caching, computed on every call)
+	DiagnosticsCacheTTL time.Duration
+
+	// MetricsHistoryInterval enables a small in-memory history of metrics
+	// samples, read with History(), for sparklines in admin pages without an
+	// external metrics system.
+	//
+	// Sampling piggybacks on Execute() and ExecuteContext(): the first request
+	// at least MetricsHistoryInterval after the previous sample records one
+	// (time, state, requests, failures, failure rate). No goroutine is started,
+	// so an idle breaker records nothing; gaps show up in the sample timestamps.
+	// Requests that find no sample due pay one atomic load and a clock read.
+	//
+	// Valid range: >= 0
+	// Default: 0 (no history)
+	MetricsHistoryInterval time.Duration
+
+	// MetricsHistoryRetention is the number of samples kept. Older samples are
+	// overwritten, so memory is fixed at construction.
+	//
+	// Default: 60 when MetricsHistoryInterval is set (5 minutes at 5s intervals)
+	MetricsHistoryRetention uint32
+
+	// JournalSize enables an outcome journal of the last JournalSize requests,
+	// for reconstructing the sequence of outcomes behind a surprising trip.
+	//
+	// Each counted request whose outcome is recorded or ignored adds an entry:
+	// sequence number, time, outcome (success, failure, ignored or panic), error
+	// text and the counts right after recording. On every entry into Open the
+	// journal is snapshotted, read with LastTripJournal(). Requests discarded
+	// during maintenance or while disabled, and ExecuteUncounted calls, are not
+	// journaled.
+	//
+	// Memory is fixed at construction: JournalSize entries of at most
+	// JournalErrorLength bytes of error text each. Writes never wait: when two
+	// requests record at the same instant, one entry is dropped and counted in
+	// Metrics.JournalDrops.
+	//
+	// Valid range: >= 0
+	// Default: 0 (no journal)
+	JournalSize int
+
+	// JournalErrorLength is the maximum error text kept per journal entry, in
+	// bytes. Ignored unless JournalSize is set.
+	//
+	// Valid range: >= 0
+	// Default: 128 if set to 0
+	JournalErrorLength int
+
+	// LogTripJournal prints the journal snapshot, one line per entry, every
+	// time the circuit enters Open. Ignored unless JournalSize is set.
+	//
+	// Default: false
+	LogTripJournal bool
+
+	// HealthScoreAlpha enables a smoothed health score, read with HealthScore(),
+	// for load balancers that weight endpoints by health instead of taking them
+	// in and out of rotation.
+	//
+	// The score is an exponentially weighted moving average of the success rate,
+	// updated on each recorded outcome: score += alpha × (outcome - score), where
+	// outcome is 1 for a success and 0 for a failure (1 - weight with
+	// OutcomeWeight). Higher values react faster; after n outcomes at a new rate
+	// the score has moved 1 - (1-alpha)^n of the way there. No goroutine is
+	// started.
+	//
+	// Valid range: [0, 1]
+	// Default: 0 (disabled; HealthScore reports the current window's success rate)
+	HealthScoreAlpha float64
+
+	// PprofLabels runs each request function under the runtime/pprof label
+	// autobreaker=<Name>, so CPU profile samples taken inside protected calls
+	// are attributed to the breaker (e.g. go tool pprof -tagfocus=autobreaker=payments).
+	//
+	// ExecuteContext adds the label to those already carried by ctx. Execute
+	// has no context: the request runs with only the breaker label, and the
+	// goroutine's labels are cleared when it returns, so use ExecuteContext
+	// with the context from your own pprof.Do when combining labels.
+	//
+	// Off by default: labeling allocates on every call, adding roughly 100ns
+	// and 3 allocations (see BenchmarkExecute_PprofLabels).
+	//
+	// Default: false
+	PprofLabels bool
+
+	// --- Validation ---
+
+	// Strict makes construction fail on settings that are ignored, shadowed by
+	// another setting, or unlikely to behave as intended (e.g. FailureRateThreshold
+	// without AdaptiveThreshold, or ReadyToTrip alongside AdaptiveThreshold).
+	// New() panics and NewChecked() returns the first such Issue as an error.
+	//
+	// Use ValidateSettings to inspect every issue without constructing a breaker.
+	//
+	// Default: false (only invalid values are rejected)
+	Strict bool
+
+	// ExpectedRequestRate is the steady request rate (requests per second) the
+	// breaker is expected to see. Only used by ConfigWarnings, to flag trip
+	// conditions the expected traffic can never meet, e.g. MinimumObservations
+	// above the requests expected per Interval.
+	//
+	// Valid range: >= 0
+	// Default: 0 (unknown; only traffic-independent checks are made)
+	ExpectedRequestRate float64
+
+	// LogConfigWarnings logs each ConfigWarnings entry when the breaker is
+	// created and after every successful UpdateSettings, as
+	// "[AUTOBREAKER WARNING] Circuit "name": ...". Never fails construction or
+	// the update; use Strict for fatal checks.
+	//
+	// Default: false (call ConfigWarnings to inspect)
+	LogConfigWarnings bool
 }
 
 var (
 	// ErrOpenState is returned when the circuit breaker is open.
 	ErrOpenState = errors.New("circuit breaker is open")
 
-	// ErrTooManyRequests is returned when too many requests are attempted in half-open state.
+	// ErrTooManyRequests is returned when too many requests are attempted in
+	// half-open state (wrapped in *TooManyRequestsError with ProbeRejectionDetails).
 	ErrTooManyRequests = errors.New("too many requests")
+
+	// ErrServedStale is wrapped in the error ExecuteCached returns alongside a
+	// cached result served in place of a rejection.
+	ErrServedStale = errors.New("served stale result")
+
+	// ErrTooManyConcurrent is returned when all MaxConcurrent slots are busy and
+	// none became free within MaxConcurrentWait.
+	ErrTooManyConcurrent = errors.New("too many concurrent requests")
+
+	// ErrDeadlineTooShort is returned by ExecuteContext when PredictiveReject is enabled
+	// and the context deadline leaves less time than the learned p95 latency.
+	ErrDeadlineTooShort = errors.New("deadline too short for expected latency")
+
+	// ErrIgnoreOutcome marks an execution whose outcome should not be attributed to
+	// the backend. Return it (alone or via errors.Join) from the request function.
+	ErrIgnoreOutcome = errors.New("ignore outcome")
+
+	// ErrDependencyOpen is wrapped by DependencyOpenError when a request is rejected
+	// because a dependency (see DependsOn) is open.
+	ErrDependencyOpen = errors.New("dependency circuit breaker is open")
+
+	// ErrDependencyCycle is returned by DependsOn when the dependency would create a cycle.
+	ErrDependencyCycle = errors.New("dependency cycle")
+
+	// ErrMirrorCycle is returned by MirrorFrom when the source mirrors, directly
+	// or transitively, the breaker that would follow it.
+	ErrMirrorCycle = errors.New("mirror cycle")
+
+	// ErrAlreadyRegistered is returned by Registry.Register and Configure when a
+	// breaker with the same name already exists.
+	ErrAlreadyRegistered = errors.New("circuit breaker already registered")
+
+	// ErrNotRegistered is returned (wrapped in *ApplyError) by Registry.ApplyAll
+	// when an update names a breaker that is not registered.
+	ErrNotRegistered = errors.New("circuit breaker not registered")
+
+	// ErrBreakerClosed is returned by Execute() and ExecuteContext() after the
+	// breaker was shut down with Close().
+	ErrBreakerClosed = errors.New("circuit breaker has been closed")
 )
 
 // DefaultReadyToTrip returns true after 5 consecutive failures.
@@ -428,6 +1733,13 @@ var (
 //	    // Trips after 6 consecutive failures
 //	})
 //
+// Example - Different Count (trips on the 4th consecutive failure):
+//
+//	breaker := autobreaker.New(autobreaker.Settings{
+//	    Name:                        "critical-service",
+//	    ConsecutiveFailureThreshold: 3,
+//	})
+//
 // Example - Custom Threshold:
 //
 //	breaker := autobreaker.New(autobreaker.Settings{
@@ -440,6 +1752,12 @@ func DefaultReadyToTrip(counts Counts) bool {
 	return counts.ConsecutiveFailures > 5
 }
 
+// consecutiveReadyToTrip is the static trip rule with ConsecutiveFailureThreshold
+// in place of DefaultReadyToTrip's 5.
+func (cb *CircuitBreaker) consecutiveReadyToTrip(counts Counts) bool {
+	return counts.ConsecutiveFailures > cb.consecutiveThreshold
+}
+
 // DefaultIsSuccessful returns true only for nil errors.
 //
 // This is the default IsSuccessful implementation. It treats any non-nil error
@@ -546,6 +1864,29 @@ type SettingsUpdate struct {
 	// Only applies when adaptive threshold is enabled.
 	// Valid range: > 0 (will be validated)
 	MinimumObservations *uint32
+
+	// WarningThresholdFraction updates the early-warning level as a fraction of
+	// FailureRateThreshold. Zero disables the warning.
+	// Valid range: [0, 1)
+	WarningThresholdFraction *float64
+
+	// RecoverFailureRate updates the rate the backend must recover to before
+	// FailureRateThreshold applies again. Zero disables the hysteresis and ends
+	// any recovery period in progress.
+	// Only applies when adaptive threshold is enabled.
+	// Valid range: 0 or (0, FailureRateThreshold]
+	RecoverFailureRate *float64
+
+	// IsSuccessful replaces the success classifier, for example to swap out
+	// one that panics (see Settings.ClassifierPanicLatch, whose latch it
+	// releases). A pointer like the other fields, so SettingsUpdate stays
+	// comparable; a nil function restores DefaultIsSuccessful.
+	// Not allowed on a breaker created with OutcomeWeight, which classifies instead.
+	//
+	// Example:
+	//   fixed := func(err error) bool { return err == nil || errors.Is(err, ErrNotFound) }
+	//   breaker.UpdateSettings(autobreaker.SettingsUpdate{IsSuccessful: &fixed})
+	IsSuccessful *func(err error) bool
 }
 
 // Uint32Ptr returns a pointer to the given uint32 value.
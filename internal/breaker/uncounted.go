@@ -0,0 +1,69 @@
+package breaker
+
+import "time"
+
+// ExecuteUncounted runs req like Execute, but keeps its outcome out of the
+// counts. It is meant for synthetic traffic (health checks, synthetic
+// monitoring) that would otherwise dilute the real failure rate at low traffic.
+//
+// Admission is unchanged: the call is rejected with ErrOpenState while the
+// circuit is open, takes a HalfOpen slot (and a HalfOpenMaxProbes execution)
+// like any probe, and is subject to MaxConcurrent. Its outcome is classified
+// as usual (IsSuccessful or OutcomeWeight, panics as failures) but is not added
+// to Counts, so it never feeds ReadyToTrip, the adaptive failure rate or the
+// counts in Metrics. It is tallied in Metrics.SyntheticSuccesses and
+// Metrics.SyntheticFailures instead.
+//
+// The exception is HalfOpen: an uncounted call admitted as a probe still
+// decides whether the circuit closes or reopens (per IsProbeSuccessful when
+// set), exactly like a counted probe. That is the point of synthetic traffic
+// during recovery: a circuit with no real traffic still recovers.
+//
+// Maintenance, ErrIgnoreOutcome and nested rejections behave as in Execute.
+//
+// Thread-safe: Safe to call concurrently.
+//
+// Example - Synthetic Monitoring:
+//
+//	ticker := time.NewTicker(10 * time.Second)
+//	for range ticker.C {
+//	    _, err := breaker.ExecuteUncounted(func() (interface{}, error) {
+//	        return client.Ping(ctx)
+//	    })
+//	    monitor.Report(err)
+//	}
+func (cb *CircuitBreaker) ExecuteUncounted(req func() (interface{}, error)) (interface{}, error) {
+	return cb.execute(req, nil, true, anyProbe)
+}
+
+// completeSynthetic classifies a completed uncounted call and records it with
+// recordSynthetic.
+func (cb *CircuitBreaker) completeSynthetic(currentState State, result interface{}, err error, elapsed time.Duration) {
+	weight, ok := cb.failureWeightOf(result, err)
+	if !ok {
+		// Classifier panicked under ClassifierPanicIgnore
+		cb.discardOutcome(false, currentState)
+		return
+	}
+
+	success := weight <= outcomeWeightFailureCutoff
+	probeSuccess := success
+	if cb.classifiesProbe(currentState) {
+		probeSuccess = safeCallIsProbeSuccessful(cb.name, cb.isProbeSuccessful, result, err, elapsed)
+	}
+	cb.recordSynthetic(success, probeSuccess, currentState)
+}
+
+// recordSynthetic tallies an uncounted call. In HalfOpen the call is a probe
+// like any other, so probeSuccess still decides whether the circuit closes.
+func (cb *CircuitBreaker) recordSynthetic(success, probeSuccess bool, currentState State) {
+	if success {
+		cb.eventCounter().syntheticSuccesses.Add(1)
+	} else {
+		cb.eventCounter().syntheticFailures.Add(1)
+	}
+
+	if currentState == StateHalfOpen {
+		cb.decideHalfOpen(probeSuccess)
+	}
+}
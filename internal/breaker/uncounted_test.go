@@ -0,0 +1,133 @@
+package breaker
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestExecuteUncounted_ExcludedFromCounts(t *testing.T) {
+	cb := New(tripOnFirstFailure(Settings{Name: "uncounted", Timeout: time.Minute}))
+
+	cb.ExecuteUncounted(successFunc)
+	cb.ExecuteUncounted(failFunc)
+	cb.ExecuteUncounted(failFunc)
+
+	if cb.State() != StateClosed {
+		t.Errorf("Expected synthetic failures not to trip, got %v", cb.State())
+	}
+	if got := cb.Counts(); got != (Counts{}) {
+		t.Errorf("Expected empty counts, got %+v", got)
+	}
+	m := cb.Metrics()
+	if m.SyntheticSuccesses != 1 || m.SyntheticFailures != 2 {
+		t.Errorf("Expected 1 synthetic success and 2 failures, got %d and %d", m.SyntheticSuccesses, m.SyntheticFailures)
+	}
+}
+
+func TestExecuteUncounted_RejectedWhileOpen(t *testing.T) {
+	cb := New(tripOnFirstFailure(Settings{Name: "uncounted-open", Timeout: time.Minute}))
+	cb.Execute(failFunc)
+
+	if _, err := cb.ExecuteUncounted(successFunc); !errors.Is(err, ErrOpenState) {
+		t.Errorf("Expected ErrOpenState, got %v", err)
+	}
+	if got := cb.Metrics().SyntheticSuccesses; got != 0 {
+		t.Errorf("Expected a rejected call not tallied, got %d", got)
+	}
+}
+
+// Unlike in Closed, an uncounted probe decides the HalfOpen episode.
+func TestExecuteUncounted_DecidesHalfOpen(t *testing.T) {
+	for _, tt := range []struct {
+		name string
+		req  func() (interface{}, error)
+		want State
+	}{
+		{"success closes", successFunc, StateClosed},
+		{"failure reopens", failFunc, StateOpen},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			cb := New(tripOnFirstFailure(Settings{Name: "uncounted-probe", Timeout: 10 * time.Millisecond}))
+			cb.Execute(failFunc)
+			time.Sleep(20 * time.Millisecond)
+
+			cb.ExecuteUncounted(tt.req)
+
+			if cb.State() != tt.want {
+				t.Errorf("Expected %v, got %v", tt.want, cb.State())
+			}
+		})
+	}
+}
+
+func TestExecuteUncounted_TakesHalfOpenSlot(t *testing.T) {
+	cb := New(tripOnFirstFailure(Settings{Name: "uncounted-slot", Timeout: 10 * time.Millisecond}))
+	cb.Execute(failFunc)
+	time.Sleep(20 * time.Millisecond)
+
+	release := make(chan struct{})
+	started := make(chan struct{})
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		cb.ExecuteUncounted(func() (interface{}, error) {
+			close(started)
+			<-release
+			return "ok", nil
+		})
+	}()
+	<-started
+
+	_, err := cb.Execute(successFunc)
+	close(release)
+	<-done
+
+	if !errors.Is(err, ErrTooManyRequests) {
+		t.Errorf("Expected the synthetic probe to hold the slot, got %v", err)
+	}
+	if cb.State() != StateClosed {
+		t.Errorf("Expected the synthetic probe to close the circuit, got %v", cb.State())
+	}
+}
+
+// At low traffic, synthetic successes interleaved with real calls would dilute a
+// 50% real failure rate below the threshold. Uncounted, they don't.
+func TestExecuteUncounted_LowTrafficTripsAtRealRate(t *testing.T) {
+	for _, tt := range []struct {
+		name      string
+		synthetic func(cb *CircuitBreaker)
+		wantTrip  bool
+	}{
+		{"uncounted", func(cb *CircuitBreaker) { cb.ExecuteUncounted(successFunc) }, true},
+		{"counted", func(cb *CircuitBreaker) { cb.Execute(successFunc) }, false},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			cb := New(Settings{
+				Name:                 "uncounted-low-traffic",
+				AdaptiveThreshold:    true,
+				FailureRateThreshold: 0.3,
+				MinimumObservations:  10,
+				Timeout:              time.Minute,
+			})
+
+			for i := 0; i < 10; i++ {
+				if cb.State() != StateClosed {
+					t.Fatalf("Expected Closed before the 10th real call, tripped after %d", i)
+				}
+				if i%2 == 0 {
+					cb.Execute(successFunc)
+				} else {
+					cb.Execute(failFunc)
+				}
+				for j := 0; j < 4; j++ {
+					tt.synthetic(cb)
+				}
+			}
+
+			if tripped := cb.State() == StateOpen; tripped != tt.wantTrip {
+				t.Errorf("Expected tripped=%v, got state %v", tt.wantTrip, cb.State())
+			}
+		})
+	}
+}
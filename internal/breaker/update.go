@@ -3,7 +3,6 @@ package breaker
 import (
 	"errors"
 	"fmt"
-	"time"
 )
 
 // UpdateSettings atomically updates the circuit breaker configuration at runtime.
@@ -106,18 +105,46 @@ import (
 //	}
 //
 // Returns nil on success, or an error describing which field failed validation.
+// Use UpdateSettingsDetailed to also learn what changed.
 func (cb *CircuitBreaker) UpdateSettings(update SettingsUpdate) error {
+	_, err := cb.UpdateSettingsDetailed(update)
+	return err
+}
+
+// UpdateSettingsDetailed is UpdateSettings, additionally returning a ChangeSet
+// describing the applied changes.
+//
+// The ChangeSet is computed from the values actually stored, so it lists only
+// fields that changed and reports whether a count or timer reset was triggered.
+// It is empty when every field was nil or equal to its current value, and when
+// validation fails (nothing is applied).
+//
+// Example - Audit Logging:
+//
+//	changes, err := breaker.UpdateSettingsDetailed(update)
+//	if err != nil {
+//	    return err
+//	}
+//	if !changes.Empty() {
+//	    entry, _ := json.Marshal(changes)
+//	    audit.Log(user, time.Now(), entry)
+//	}
+func (cb *CircuitBreaker) UpdateSettingsDetailed(update SettingsUpdate) (ChangeSet, error) {
 	// Validate all settings before applying any changes
-	if err := cb.validateUpdate(update); err != nil {
-		return err
+	if err := cb.validateUpdate(&update); err != nil {
+		return ChangeSet{}, err
 	}
 
-	// Track if we need to reset counts or timer
-	var needsCountReset bool
-	var needsTimerReset bool
+	return cb.applyUpdate(&update), nil
+}
+
+// applyUpdate applies a validated update and returns the resulting ChangeSet.
+func (cb *CircuitBreaker) applyUpdate(update *SettingsUpdate) ChangeSet {
+	var changes ChangeSet
+	openWaitChanged := false // Timeout or Interval changed
 
 	// Check current state for smart reset logic
-	currentState := cb.State()
+	currentState := cb.machineState()
 
 	// Apply updates atomically
 	// Note: We can't make all updates truly atomic without locks, but we can
@@ -125,62 +152,135 @@ func (cb *CircuitBreaker) UpdateSettings(update SettingsUpdate) error {
 
 	// Update MaxRequests (simple field update)
 	if update.MaxRequests != nil {
-		cb.setMaxRequests(*update.MaxRequests)
+		if recordChange(&changes, "MaxRequests", cb.getMaxRequests(), *update.MaxRequests) {
+			cb.setMaxRequests(*update.MaxRequests)
+		}
 	}
 
 	// Update Interval and check if reset needed
 	if update.Interval != nil {
-		oldInterval := cb.getInterval()
-		newInterval := *update.Interval
-
-		cb.setInterval(newInterval)
+		if recordChange(&changes, "Interval", cb.getInterval(), *update.Interval) {
+			cb.setInterval(*update.Interval)
+			openWaitChanged = true
 
-		// If interval changed and we're in Closed state, reset counts
-		if oldInterval != newInterval && currentState == StateClosed {
-			needsCountReset = true
+			// If interval changed and we're in Closed state, reset counts
+			if currentState == StateClosed {
+				changes.CountsReset = true
+			}
 		}
 	}
 
 	// Update Timeout and check if timer reset needed
 	if update.Timeout != nil {
-		oldTimeout := cb.getTimeout()
 		newTimeout := *update.Timeout
+		if recordChange(&changes, "Timeout", cb.getTimeout(), newTimeout) {
+			cb.setTimeout(newTimeout)
+			openWaitChanged = true
 
-		cb.setTimeout(newTimeout)
+			// Restart learning from the new starting estimate
+			if cb.learner != nil {
+				cb.learner.learned.Store(int64(cb.learner.clamp(newTimeout)))
+			}
 
-		// If timeout changed and we're in Open state, reset timer
-		if oldTimeout != newTimeout && currentState == StateOpen {
-			needsTimerReset = true
+			// If timeout changed and we're in Open state, reset timer
+			if currentState == StateOpen {
+				changes.TimerReset = true
+			}
 		}
 	}
 
 	// Update FailureRateThreshold (simple field update)
 	if update.FailureRateThreshold != nil {
-		cb.setFailureRateThreshold(*update.FailureRateThreshold)
+		if recordChange(&changes, "FailureRateThreshold", cb.getFailureRateThreshold(), *update.FailureRateThreshold) {
+			cb.setFailureRateThreshold(*update.FailureRateThreshold)
+		}
 	}
 
 	// Update MinimumObservations (simple field update)
 	if update.MinimumObservations != nil {
-		cb.setMinimumObservations(*update.MinimumObservations)
+		if recordChange(&changes, "MinimumObservations", cb.getMinimumObservations(), *update.MinimumObservations) {
+			cb.setMinimumObservations(*update.MinimumObservations)
+		}
+	}
+
+	// Update WarningThresholdFraction (simple field update)
+	if update.WarningThresholdFraction != nil {
+		if recordChange(&changes, "WarningThresholdFraction", cb.getWarningThresholdFraction(), *update.WarningThresholdFraction) {
+			cb.setWarningThresholdFraction(*update.WarningThresholdFraction)
+		}
+	}
+
+	// Update RecoverFailureRate; disabling it ends the recovery period
+	if update.RecoverFailureRate != nil {
+		if recordChange(&changes, "RecoverFailureRate", cb.getRecoverFailureRate(), *update.RecoverFailureRate) {
+			cb.setRecoverFailureRate(*update.RecoverFailureRate)
+		}
+		if *update.RecoverFailureRate == 0 {
+			cb.endRecovery()
+		}
+	}
+
+	// Replace IsSuccessful, releasing a ClassifierPanicLatch latch
+	if update.IsSuccessful != nil {
+		cb.replaceIsSuccessful(*update.IsSuccessful)
+		changes.recordReplaced("IsSuccessful")
 	}
 
 	// Apply smart resets after all settings are updated
-	if needsCountReset {
+	if changes.CountsReset {
 		cb.resetCounts()
 	}
 
-	if needsTimerReset {
-		// Reset the open timer to start timeout from now
-		now := time.Now().UnixNano()
+	if changes.TimerReset {
+		// Reset the open timer to start timeout from now,
+		// with the new Timeout, discarding any backend-requested backoff
+		now := cb.now()
+		cb.openUntil.Store(0)
 		cb.openedAt.Store(now)
 	}
 
-	return nil
+	// Timeout and Interval (IntervalResetsOpenState) shape the open wait
+	if openWaitChanged {
+		cb.invalidateOpenDeadline()
+	}
+
+	if cb.logConfigWarnings && !changes.Empty() {
+		cb.reportConfigWarnings()
+	}
+
+	return changes
 }
 
 // validateUpdate validates all non-nil fields in the update.
 // Returns an error if any field is invalid.
-func (cb *CircuitBreaker) validateUpdate(update SettingsUpdate) error {
+func (cb *CircuitBreaker) validateUpdate(update *SettingsUpdate) error {
+	if err := validateSettingsUpdate(update, cb.adaptiveThreshold); err != nil {
+		return err
+	}
+
+	// OutcomeWeight classifies instead of IsSuccessful, and cannot be replaced
+	if update.IsSuccessful != nil && cb.outcomeWeight != nil {
+		return errors.New("autobreaker: IsSuccessful cannot be updated on a breaker classifying with OutcomeWeight")
+	}
+
+	// RecoverFailureRate must stay at or below FailureRateThreshold, whichever
+	// of the two the update changes
+	if cb.adaptiveThreshold && (update.RecoverFailureRate != nil || update.FailureRateThreshold != nil) {
+		threshold, recovery := cb.getFailureRateThreshold(), cb.getRecoverFailureRate()
+		if update.FailureRateThreshold != nil {
+			threshold = *update.FailureRateThreshold
+		}
+		if update.RecoverFailureRate != nil {
+			recovery = *update.RecoverFailureRate
+		}
+		return validateRecoverFailureRate(recovery, threshold)
+	}
+	return nil
+}
+
+// validateSettingsUpdate validates all non-nil fields in the update against a
+// breaker with the given adaptive mode. Returns an error if any field is invalid.
+func validateSettingsUpdate(update *SettingsUpdate, adaptiveThreshold bool) error {
 	// Validate MaxRequests
 	if update.MaxRequests != nil {
 		if *update.MaxRequests == 0 {
@@ -207,7 +307,7 @@ func (cb *CircuitBreaker) validateUpdate(update SettingsUpdate) error {
 		threshold := *update.FailureRateThreshold
 
 		// Only validate if adaptive mode is enabled
-		if cb.adaptiveThreshold {
+		if adaptiveThreshold {
 			if threshold <= 0 || threshold >= 1 {
 				return fmt.Errorf("autobreaker: FailureRateThreshold must be in range (0, 1), got %f", threshold)
 			}
@@ -223,6 +323,24 @@ func (cb *CircuitBreaker) validateUpdate(update SettingsUpdate) error {
 		}
 	}
 
+	// Validate WarningThresholdFraction
+	if update.WarningThresholdFraction != nil {
+		if err := validateWarningThresholdFraction(*update.WarningThresholdFraction); err != nil {
+			return err
+		}
+	}
+
+	// Validate RecoverFailureRate, against FailureRateThreshold if both are set
+	if update.RecoverFailureRate != nil && adaptiveThreshold {
+		threshold := 1.0
+		if update.FailureRateThreshold != nil {
+			threshold = *update.FailureRateThreshold
+		}
+		if err := validateRecoverFailureRate(*update.RecoverFailureRate, threshold); err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
 
@@ -233,8 +351,16 @@ func (cb *CircuitBreaker) resetCounts() {
 	cb.totalFailures.Store(0)
 	cb.consecutiveSuccesses.Store(0)
 	cb.consecutiveFailures.Store(0)
+	cb.failureWeight.Store(0)
+	cb.unserved.Store(0)
+	cb.panics.Store(0)
+	cb.timeoutFailures.Store(0)
+	cb.panicThresholdFired.Store(false)
+	cb.degraded.Store(false)
+	cb.warningLatched.Store(false)
+	cb.generation.Add(1) // Calls in flight belong to the discarded window
 
-	// Update the lastClearedAt timestamp
-	now := time.Now().UnixNano()
-	cb.lastClearedAt.Store(now)
+	// Update the lastClearedAt timestamp (aligned to the window boundary if configured)
+	now := cb.now()
+	cb.lastClearedAt.Store(cb.windowStart(now))
 }
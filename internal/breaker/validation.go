@@ -0,0 +1,640 @@
+package breaker
+
+import (
+	"fmt"
+	"math"
+	"time"
+)
+
+// IssueSeverity classifies a settings Issue.
+type IssueSeverity int
+
+const (
+	// SeverityInfo marks a setting that relies on an implicit default. Never fatal.
+	SeverityInfo IssueSeverity = iota
+
+	// SeverityWarning marks a setting that is ignored, shadowed by another setting,
+	// or unlikely to behave as intended. Fatal only when Settings.Strict is true.
+	SeverityWarning
+
+	// SeverityError marks an invalid setting. New() panics and NewChecked() returns
+	// the issue as an error.
+	SeverityError
+)
+
+// String returns the severity name ("info", "warning", "error").
+func (s IssueSeverity) String() string {
+	switch s {
+	case SeverityInfo:
+		return "info"
+	case SeverityWarning:
+		return "warning"
+	case SeverityError:
+		return "error"
+	default:
+		return fmt.Sprintf("IssueSeverity(%d)", int(s))
+	}
+}
+
+// IssueCode identifies the kind of problem an Issue describes. Codes are stable
+// and safe to match on in configuration tests.
+type IssueCode string
+
+const (
+	// IssueOutOfRange: a field value is outside its valid range.
+	IssueOutOfRange IssueCode = "out_of_range"
+
+	// IssueUnknownValue: an enum field holds an unknown value.
+	IssueUnknownValue IssueCode = "unknown_value"
+
+	// IssueThresholdOrder: two related rate thresholds are in the wrong order.
+	IssueThresholdOrder IssueCode = "threshold_order"
+
+	// IssueIgnoredField: a field is set but has no effect in this configuration.
+	IssueIgnoredField IssueCode = "ignored_field"
+
+	// IssueShadowedField: a field is set but another field takes precedence over it.
+	IssueShadowedField IssueCode = "shadowed_field"
+
+	// IssueSuspiciousTiming: durations are valid but unlikely to behave as intended.
+	IssueSuspiciousTiming IssueCode = "suspicious_timing"
+
+	// IssueImplicitDefault: a zero value is silently replaced by a default.
+	IssueImplicitDefault IssueCode = "implicit_default"
+
+	// IssueInvalidLabel: a Labels key is empty or reserved.
+	IssueInvalidLabel IssueCode = "invalid_label"
+)
+
+// shortInterval is the Interval below which observation windows are likely to
+// reset before slow requests complete.
+const shortInterval = time.Second
+
+// Issue describes one problem found by ValidateSettings.
+//
+// Issue implements error, so the issue that made New() panic or NewChecked() fail
+// can be recovered with errors.As.
+type Issue struct {
+	// Code identifies the kind of problem.
+	Code IssueCode
+
+	// Severity is how serious the problem is, after Settings.Strict is applied.
+	Severity IssueSeverity
+
+	// Fields names the offending Settings field(s).
+	Fields []string
+
+	// Message is a human-readable description.
+	Message string
+}
+
+// Error returns the message prefixed with "autobreaker: ".
+func (i Issue) Error() string {
+	return "autobreaker: " + i.Message
+}
+
+// ValidateSettings checks settings for invalid values and contradictory
+// combinations, returning every issue found (nil if there are none).
+//
+// It is a pure function intended for configuration tests:
+//
+//	func TestBreakerConfig(t *testing.T) {
+//	    for _, issue := range autobreaker.ValidateSettings(loadSettings()) {
+//	        if issue.Severity >= autobreaker.SeverityWarning {
+//	            t.Errorf("%s %v: %s", issue.Code, issue.Fields, issue.Message)
+//	        }
+//	    }
+//	}
+//
+// Issues with SeverityError make New() panic and NewChecked() fail. When
+// Settings.Strict is true, warnings are reported as errors and are fatal too.
+// Issues are returned in a fixed order: errors in the order New() checks them,
+// followed by warnings and info.
+func ValidateSettings(settings Settings) []Issue {
+	return settingsIssues(&settings, true)
+}
+
+// settingsIssues implements ValidateSettings. Info issues, never fatal, are
+// left out unless withInfo is set, so New does not build their messages.
+func settingsIssues(settings *Settings, withInfo bool) []Issue {
+	var issues []Issue
+	add := func(code IssueCode, severity IssueSeverity, fields []string, format string, args ...interface{}) {
+		if severity == SeverityWarning && settings.Strict {
+			severity = SeverityError
+		}
+		issues = append(issues, Issue{
+			Code:     code,
+			Severity: severity,
+			Fields:   fields,
+			Message:  fmt.Sprintf(format, args...),
+		})
+	}
+
+	tripRate := settings.FailureRateThreshold
+	if tripRate == 0 {
+		tripRate = 0.05
+	}
+	timeout := settings.Timeout
+	if timeout == 0 {
+		timeout = 60 * time.Second
+	}
+
+	// --- Invalid values ---
+
+	for _, key := range sortedLabelKeys(settings.Labels) {
+		switch key {
+		case "":
+			add(IssueInvalidLabel, SeverityError, []string{"Labels"},
+				"Labels keys must not be empty")
+		case reservedLabelName:
+			add(IssueInvalidLabel, SeverityError, []string{"Labels"},
+				"Labels key %q is reserved for the breaker name", key)
+		}
+	}
+
+	// FailureRateThreshold must be in (0, 1) exclusive range if explicitly set
+	if settings.AdaptiveThreshold && settings.FailureRateThreshold != 0 &&
+		(settings.FailureRateThreshold <= 0 || settings.FailureRateThreshold >= 1) {
+		add(IssueOutOfRange, SeverityError, []string{"FailureRateThreshold"},
+			"FailureRateThreshold must be in range (0, 1), got %v", settings.FailureRateThreshold)
+	}
+
+	if settings.TransitionLoserBehavior != TransitionLoserProbe && settings.TransitionLoserBehavior != TransitionLoserReject {
+		add(IssueUnknownValue, SeverityError, []string{"TransitionLoserBehavior"},
+			"unknown TransitionLoserBehavior %d", settings.TransitionLoserBehavior)
+	}
+
+	// Interval can be 0 for no reset, but not negative
+	if settings.Interval < 0 {
+		add(IssueOutOfRange, SeverityError, []string{"Interval"},
+			"Interval cannot be negative, got %v", settings.Interval)
+	}
+
+	// Timeout can be 0 for the default, but not negative
+	if settings.Timeout < 0 {
+		add(IssueOutOfRange, SeverityError, []string{"Timeout"},
+			"Timeout cannot be negative, got %v", settings.Timeout)
+	}
+
+	if settings.MinObservationWindow < 0 {
+		add(IssueOutOfRange, SeverityError, []string{"MinObservationWindow"},
+			"MinObservationWindow cannot be negative, got %v", settings.MinObservationWindow)
+	}
+
+	if settings.MinTimeout < 0 {
+		add(IssueOutOfRange, SeverityError, []string{"MinTimeout"},
+			"MinTimeout cannot be negative, got %v", settings.MinTimeout)
+	}
+	if settings.MaxTimeout < 0 {
+		add(IssueOutOfRange, SeverityError, []string{"MaxTimeout"},
+			"MaxTimeout cannot be negative, got %v", settings.MaxTimeout)
+	}
+	if settings.AdaptiveTimeout {
+		minTimeout, maxTimeout := settings.MinTimeout, settings.MaxTimeout
+		if minTimeout == 0 {
+			minTimeout = defaultMinTimeout
+		}
+		if maxTimeout == 0 {
+			maxTimeout = defaultMaxTimeout
+		}
+		if minTimeout > 0 && maxTimeout > 0 && minTimeout > maxTimeout {
+			add(IssueThresholdOrder, SeverityError, []string{"MinTimeout", "MaxTimeout"},
+				"MinTimeout (%v) must not exceed MaxTimeout (%v)", minTimeout, maxTimeout)
+		}
+	} else {
+		if settings.MinTimeout != 0 {
+			add(IssueIgnoredField, SeverityWarning, []string{"MinTimeout", "AdaptiveTimeout"},
+				"MinTimeout is ignored without AdaptiveTimeout")
+		}
+		if settings.MaxTimeout != 0 {
+			add(IssueIgnoredField, SeverityWarning, []string{"MaxTimeout", "AdaptiveTimeout"},
+				"MaxTimeout is ignored without AdaptiveTimeout")
+		}
+	}
+
+	if settings.HalfOpenProbeTimeout < 0 {
+		add(IssueOutOfRange, SeverityError, []string{"HalfOpenProbeTimeout"},
+			"HalfOpenProbeTimeout cannot be negative, got %v", settings.HalfOpenProbeTimeout)
+	}
+
+	if settings.SlowCallFactor != 0 && !(settings.SlowCallFactor >= 1) {
+		add(IssueOutOfRange, SeverityError, []string{"SlowCallFactor"},
+			"SlowCallFactor must be 0 (disabled) or >= 1, got %v", settings.SlowCallFactor)
+	}
+
+	if settings.EpisodeHistory < 0 {
+		add(IssueOutOfRange, SeverityError, []string{"EpisodeHistory"},
+			"EpisodeHistory cannot be negative, got %d", settings.EpisodeHistory)
+	}
+
+	if settings.ProbeFairnessWait < 0 {
+		add(IssueOutOfRange, SeverityError, []string{"ProbeFairnessWait"},
+			"ProbeFairnessWait cannot be negative, got %v", settings.ProbeFairnessWait)
+	}
+
+	if settings.StreamTimeout < 0 {
+		add(IssueOutOfRange, SeverityError, []string{"StreamTimeout"},
+			"StreamTimeout cannot be negative, got %v", settings.StreamTimeout)
+	}
+
+	if settings.EligibleProbeWait < 0 {
+		add(IssueOutOfRange, SeverityError, []string{"EligibleProbeWait"},
+			"EligibleProbeWait cannot be negative, got %v", settings.EligibleProbeWait)
+	}
+
+	if settings.MinClosedDuration < 0 {
+		add(IssueOutOfRange, SeverityError, []string{"MinClosedDuration"},
+			"MinClosedDuration cannot be negative, got %v", settings.MinClosedDuration)
+	}
+
+	if settings.MaxOpenDuration < 0 {
+		add(IssueOutOfRange, SeverityError, []string{"MaxOpenDuration"},
+			"MaxOpenDuration cannot be negative, got %v", settings.MaxOpenDuration)
+	}
+
+	if settings.StuckOpenAction < StuckOpenAlert || settings.StuckOpenAction > StuckOpenForceClose {
+		add(IssueUnknownValue, SeverityError, []string{"StuckOpenAction"},
+			"unknown StuckOpenAction %d", settings.StuckOpenAction)
+	}
+
+	if settings.ClassifierPanicOutcome < ClassifierPanicDefault || settings.ClassifierPanicOutcome > ClassifierPanicIgnore {
+		add(IssueUnknownValue, SeverityError, []string{"ClassifierPanicOutcome"},
+			"unknown ClassifierPanicOutcome %d", settings.ClassifierPanicOutcome)
+	}
+
+	if settings.MaxConcurrentWait < 0 {
+		add(IssueOutOfRange, SeverityError, []string{"MaxConcurrentWait"},
+			"MaxConcurrentWait cannot be negative, got %v", settings.MaxConcurrentWait)
+	}
+
+	if settings.CacheTTL < 0 {
+		add(IssueOutOfRange, SeverityError, []string{"CacheTTL"},
+			"CacheTTL cannot be negative, got %v", settings.CacheTTL)
+	}
+
+	if settings.RecommendationWindow < 0 {
+		add(IssueOutOfRange, SeverityError, []string{"RecommendationWindow"},
+			"RecommendationWindow cannot be negative, got %v", settings.RecommendationWindow)
+	}
+
+	if settings.RecommendationBucket < 0 {
+		add(IssueOutOfRange, SeverityError, []string{"RecommendationBucket"},
+			"RecommendationBucket cannot be negative, got %v", settings.RecommendationBucket)
+	}
+
+	if settings.RecommendationWindow > 0 && settings.RecommendationBucket >= 0 {
+		bucket := settings.RecommendationBucket
+		if bucket == 0 {
+			bucket = defaultRecommendationBucket
+		}
+		if settings.RecommendationWindow < bucket {
+			add(IssueThresholdOrder, SeverityError, []string{"RecommendationWindow", "RecommendationBucket"},
+				"RecommendationWindow (%v) must be at least RecommendationBucket (%v)", settings.RecommendationWindow, bucket)
+		} else if settings.RecommendationWindow/bucket > maxRecommendationBuckets {
+			add(IssueOutOfRange, SeverityError, []string{"RecommendationWindow", "RecommendationBucket"},
+				"RecommendationWindow holds %d RecommendationBucket periods, more than %d",
+				settings.RecommendationWindow/bucket, maxRecommendationBuckets)
+		}
+	}
+
+	if settings.RecommendationMinThreshold < 0 || settings.RecommendationMinThreshold >= 1 {
+		add(IssueOutOfRange, SeverityError, []string{"RecommendationMinThreshold"},
+			"RecommendationMinThreshold must be in range (0, 1), got %v", settings.RecommendationMinThreshold)
+	}
+
+	if settings.RecommendationMaxThreshold < 0 || settings.RecommendationMaxThreshold >= 1 {
+		add(IssueOutOfRange, SeverityError, []string{"RecommendationMaxThreshold"},
+			"RecommendationMaxThreshold must be in range (0, 1), got %v", settings.RecommendationMaxThreshold)
+	}
+
+	minRec, maxRec := settings.RecommendationMinThreshold, settings.RecommendationMaxThreshold
+	if minRec == 0 {
+		minRec = defaultRecommendationMinThreshold
+	}
+	if maxRec == 0 {
+		maxRec = defaultRecommendationMaxThreshold
+	}
+	if minRec > 0 && minRec < 1 && maxRec > 0 && maxRec < 1 && minRec >= maxRec {
+		add(IssueThresholdOrder, SeverityError, []string{"RecommendationMinThreshold", "RecommendationMaxThreshold"},
+			"RecommendationMinThreshold (%v) must be less than RecommendationMaxThreshold (%v)", minRec, maxRec)
+	}
+
+	if settings.AutoTuneInterval < 0 {
+		add(IssueOutOfRange, SeverityError, []string{"AutoTuneInterval"},
+			"AutoTuneInterval cannot be negative, got %v", settings.AutoTuneInterval)
+	}
+
+	if settings.MetricsHistoryInterval < 0 {
+		add(IssueOutOfRange, SeverityError, []string{"MetricsHistoryInterval"},
+			"MetricsHistoryInterval cannot be negative, got %v", settings.MetricsHistoryInterval)
+	}
+
+	if settings.JournalSize < 0 {
+		add(IssueOutOfRange, SeverityError, []string{"JournalSize"},
+			"JournalSize cannot be negative, got %d", settings.JournalSize)
+	}
+	if settings.JournalErrorLength < 0 {
+		add(IssueOutOfRange, SeverityError, []string{"JournalErrorLength"},
+			"JournalErrorLength cannot be negative, got %d", settings.JournalErrorLength)
+	}
+
+	if !(settings.HealthScoreAlpha >= 0 && settings.HealthScoreAlpha <= 1) {
+		add(IssueOutOfRange, SeverityError, []string{"HealthScoreAlpha"},
+			"HealthScoreAlpha must be in range [0, 1], got %v", settings.HealthScoreAlpha)
+	}
+
+	if settings.DiagnosticsCacheTTL < 0 {
+		add(IssueOutOfRange, SeverityError, []string{"DiagnosticsCacheTTL"},
+			"DiagnosticsCacheTTL cannot be negative, got %v", settings.DiagnosticsCacheTTL)
+	}
+
+	if err := validateWarningThresholdFraction(settings.WarningThresholdFraction); err != nil {
+		add(IssueOutOfRange, SeverityError, []string{"WarningThresholdFraction"},
+			"WarningThresholdFraction must be in range [0, 1), got %v", settings.WarningThresholdFraction)
+	}
+
+	// RelativeFailureRateMultiplier of 0 uses the default
+	if !(settings.RelativeFailureRateMultiplier >= 0) || math.IsInf(settings.RelativeFailureRateMultiplier, 1) {
+		add(IssueOutOfRange, SeverityError, []string{"RelativeFailureRateMultiplier"},
+			"RelativeFailureRateMultiplier must be a finite value >= 0, got %v", settings.RelativeFailureRateMultiplier)
+	}
+
+	if !(settings.ErrorBudget >= 0 && settings.ErrorBudget < 1) {
+		add(IssueOutOfRange, SeverityError, []string{"ErrorBudget"},
+			"ErrorBudget must be in range [0, 1), got %v", settings.ErrorBudget)
+	}
+
+	// BurnRateThreshold of 0 uses the default
+	if !(settings.BurnRateThreshold >= 0) || math.IsInf(settings.BurnRateThreshold, 1) {
+		add(IssueOutOfRange, SeverityError, []string{"BurnRateThreshold"},
+			"BurnRateThreshold must be a finite value >= 0, got %v", settings.BurnRateThreshold)
+	}
+
+	if !(settings.ExpectedRequestRate >= 0) || math.IsInf(settings.ExpectedRequestRate, 1) {
+		add(IssueOutOfRange, SeverityError, []string{"ExpectedRequestRate"},
+			"ExpectedRequestRate must be a finite value >= 0, got %v", settings.ExpectedRequestRate)
+	}
+
+	// RateEpsilon of 0 uses the default tolerance
+	if settings.RateEpsilon < 0 || settings.RateEpsilon >= 0.01 {
+		add(IssueOutOfRange, SeverityError, []string{"RateEpsilon"},
+			"RateEpsilon must be in range [0, 0.01), got %v", settings.RateEpsilon)
+	}
+
+	// RecoverFailureRate of 0 means same as FailureRateThreshold
+	if settings.AdaptiveThreshold && settings.RecoverFailureRate != 0 &&
+		validateRecoverFailureRate(settings.RecoverFailureRate, tripRate) != nil {
+		add(IssueThresholdOrder, SeverityError, []string{"RecoverFailureRate", "FailureRateThreshold"},
+			"RecoverFailureRate must be in range (0, FailureRateThreshold=%v], got %v",
+			tripRate, settings.RecoverFailureRate)
+	}
+
+	// WarnFailureRate of 0 disables the warn band
+	if settings.WarnFailureRate < 0 || settings.WarnFailureRate >= 1 {
+		add(IssueOutOfRange, SeverityError, []string{"WarnFailureRate"},
+			"WarnFailureRate must be in range [0, 1), got %v", settings.WarnFailureRate)
+	} else if settings.AdaptiveThreshold && settings.WarnFailureRate > 0 && settings.WarnFailureRate >= tripRate {
+		add(IssueThresholdOrder, SeverityError, []string{"WarnFailureRate", "FailureRateThreshold"},
+			"WarnFailureRate (%v) must be below FailureRateThreshold (%v)",
+			settings.WarnFailureRate, tripRate)
+	}
+
+	// --- Ignored and shadowed fields ---
+
+	if !settings.AdaptiveThreshold {
+		if settings.FailureRateThreshold != 0 {
+			add(IssueIgnoredField, SeverityWarning, []string{"FailureRateThreshold", "AdaptiveThreshold"},
+				"FailureRateThreshold is ignored without AdaptiveThreshold")
+		}
+		// The warn band still honors MinimumObservations without adaptive mode
+		if settings.MinimumObservations != 0 && settings.WarnFailureRate == 0 {
+			add(IssueIgnoredField, SeverityWarning, []string{"MinimumObservations", "AdaptiveThreshold"},
+				"MinimumObservations is ignored without AdaptiveThreshold or WarnFailureRate")
+		}
+		if settings.MinObservationWindow != 0 {
+			add(IssueIgnoredField, SeverityWarning, []string{"MinObservationWindow", "AdaptiveThreshold"},
+				"MinObservationWindow is ignored without AdaptiveThreshold")
+		}
+		if settings.Baseline != nil {
+			add(IssueIgnoredField, SeverityWarning, []string{"Baseline", "AdaptiveThreshold"},
+				"Baseline is ignored without AdaptiveThreshold")
+		}
+		if settings.ErrorBudget != 0 {
+			add(IssueIgnoredField, SeverityWarning, []string{"ErrorBudget", "AdaptiveThreshold"},
+				"ErrorBudget is ignored without AdaptiveThreshold")
+		}
+		if settings.RecoverFailureRate != 0 {
+			add(IssueIgnoredField, SeverityWarning, []string{"RecoverFailureRate", "AdaptiveThreshold"},
+				"RecoverFailureRate is ignored without AdaptiveThreshold")
+		}
+		if settings.WarningThresholdFraction != 0 {
+			add(IssueIgnoredField, SeverityWarning, []string{"WarningThresholdFraction", "AdaptiveThreshold"},
+				"WarningThresholdFraction is ignored without AdaptiveThreshold")
+		}
+	}
+
+	if settings.CacheTTL == 0 {
+		if settings.CacheLastSuccess {
+			add(IssueIgnoredField, SeverityWarning, []string{"CacheLastSuccess", "CacheTTL"},
+				"CacheLastSuccess is ignored without CacheTTL")
+		}
+		if settings.ServeStaleOnTooManyRequests {
+			add(IssueIgnoredField, SeverityWarning, []string{"ServeStaleOnTooManyRequests", "CacheTTL"},
+				"ServeStaleOnTooManyRequests is ignored without CacheTTL")
+		}
+	}
+
+	if settings.RelativeFailureRateMultiplier != 0 && settings.Baseline == nil {
+		add(IssueIgnoredField, SeverityWarning, []string{"RelativeFailureRateMultiplier", "Baseline"},
+			"RelativeFailureRateMultiplier is ignored without Baseline")
+	}
+
+	if settings.BurnRateThreshold != 0 && settings.ErrorBudget == 0 {
+		add(IssueIgnoredField, SeverityWarning, []string{"BurnRateThreshold", "ErrorBudget"},
+			"BurnRateThreshold is ignored without ErrorBudget")
+	}
+
+	if settings.AdaptiveThreshold && settings.ReadyToTrip != nil {
+		add(IssueShadowedField, SeverityWarning, []string{"ReadyToTrip", "AdaptiveThreshold"},
+			"ReadyToTrip overrides the adaptive trip rule; FailureRateThreshold and MinimumObservations do not decide when to trip")
+	}
+
+	if settings.ReadyToTripEx != nil && settings.ReadyToTrip != nil {
+		add(IssueShadowedField, SeverityWarning, []string{"ReadyToTrip", "ReadyToTripEx"},
+			"ReadyToTripEx takes precedence; ReadyToTrip is never called")
+	}
+
+	if settings.Engine != nil && (settings.ReadyToTrip != nil || settings.ReadyToTripEx != nil || settings.AdaptiveThreshold || settings.ConsecutiveFailureThreshold > 0) {
+		add(IssueShadowedField, SeverityWarning, []string{"Engine", "ReadyToTrip", "ReadyToTripEx", "AdaptiveThreshold", "ConsecutiveFailureThreshold"},
+			"Engine replaces the trip rule; ReadyToTrip, ReadyToTripEx, AdaptiveThreshold and ConsecutiveFailureThreshold do not decide when to trip")
+	}
+
+	if settings.ConsecutiveFailureThreshold > 0 && (settings.ReadyToTrip != nil || settings.AdaptiveThreshold) {
+		add(IssueShadowedField, SeverityWarning, []string{"ConsecutiveFailureThreshold", "ReadyToTrip", "AdaptiveThreshold"},
+			"ConsecutiveFailureThreshold only configures the default static trip rule; ReadyToTrip or AdaptiveThreshold replaces it")
+	}
+
+	if settings.OutcomeWeight != nil && settings.IsSuccessful != nil {
+		add(IssueShadowedField, SeverityWarning, []string{"IsSuccessful", "OutcomeWeight"},
+			"IsSuccessful is never called when OutcomeWeight is set")
+	}
+
+	if settings.RequireAllSuccesses && settings.HalfOpenMaxProbes == 0 {
+		add(IssueIgnoredField, SeverityWarning, []string{"RequireAllSuccesses", "HalfOpenMaxProbes"},
+			"RequireAllSuccesses is ignored without HalfOpenMaxProbes")
+	}
+
+	if settings.AlignIntervalToWallClock && settings.Interval == 0 {
+		add(IssueIgnoredField, SeverityWarning, []string{"AlignIntervalToWallClock", "Interval"},
+			"AlignIntervalToWallClock is ignored without Interval")
+	}
+
+	if settings.RequireFullWindow && (!settings.AlignIntervalToWallClock || !settings.AdaptiveThreshold) {
+		add(IssueIgnoredField, SeverityWarning, []string{"RequireFullWindow", "AlignIntervalToWallClock"},
+			"RequireFullWindow is ignored without AlignIntervalToWallClock and AdaptiveThreshold")
+	}
+
+	if settings.PreserveStreaksOnIntervalReset && settings.Interval == 0 {
+		add(IssueIgnoredField, SeverityWarning, []string{"PreserveStreaksOnIntervalReset", "Interval"},
+			"PreserveStreaksOnIntervalReset is ignored without Interval")
+	}
+
+	if settings.RecoveryWindows != 0 && settings.RecoverFailureRate != 0 && settings.AdaptiveThreshold &&
+		settings.Interval == 0 {
+		add(IssueIgnoredField, SeverityWarning, []string{"RecoveryWindows", "Interval"},
+			"RecoveryWindows is ignored without Interval: no window ever ends the recovery period")
+	}
+
+	if settings.RecoveryWindows != 0 && settings.RecoverFailureRate == 0 {
+		add(IssueIgnoredField, SeverityWarning, []string{"RecoveryWindows", "RecoverFailureRate"},
+			"RecoveryWindows is ignored without RecoverFailureRate")
+	}
+
+	if settings.IntervalResetsOpenState && settings.Interval == 0 {
+		add(IssueIgnoredField, SeverityWarning, []string{"IntervalResetsOpenState", "Interval"},
+			"IntervalResetsOpenState is ignored without Interval")
+	}
+
+	if settings.MaxOpenDuration == 0 {
+		if settings.OnStuckOpen != nil {
+			add(IssueIgnoredField, SeverityWarning, []string{"OnStuckOpen", "MaxOpenDuration"},
+				"OnStuckOpen is never called without MaxOpenDuration")
+		}
+		if settings.StuckOpenAction != StuckOpenAlert {
+			add(IssueIgnoredField, SeverityWarning, []string{"StuckOpenAction", "MaxOpenDuration"},
+				"StuckOpenAction is ignored without MaxOpenDuration")
+		}
+	}
+
+	if settings.ClassifierPanicOutcome != ClassifierPanicDefault && settings.IsSuccessful == nil && settings.OutcomeWeight == nil {
+		add(IssueIgnoredField, SeverityWarning, []string{"ClassifierPanicOutcome", "IsSuccessful", "OutcomeWeight"},
+			"ClassifierPanicOutcome is ignored with the default classifier, which cannot panic")
+	}
+
+	if settings.ClassifierPanicLatch > 0 && settings.OutcomeWeight != nil {
+		add(IssueIgnoredField, SeverityWarning, []string{"ClassifierPanicLatch", "OutcomeWeight"},
+			"ClassifierPanicLatch is ignored with OutcomeWeight, which is not replaceable")
+	}
+
+	if settings.RecommendationWindow == 0 {
+		if settings.RecommendationBucket > 0 {
+			add(IssueIgnoredField, SeverityWarning, []string{"RecommendationBucket", "RecommendationWindow"},
+				"RecommendationBucket is ignored without RecommendationWindow")
+		}
+		if settings.RecommendationMinThreshold > 0 || settings.RecommendationMaxThreshold > 0 {
+			add(IssueIgnoredField, SeverityWarning, []string{"RecommendationMinThreshold", "RecommendationMaxThreshold", "RecommendationWindow"},
+				"RecommendationMinThreshold and RecommendationMaxThreshold are ignored without RecommendationWindow")
+		}
+	}
+
+	if settings.AutoTune && (settings.RecommendationWindow == 0 || !settings.AdaptiveThreshold) {
+		add(IssueIgnoredField, SeverityWarning, []string{"AutoTune", "RecommendationWindow", "AdaptiveThreshold"},
+			"AutoTune is ignored without RecommendationWindow and AdaptiveThreshold")
+	}
+
+	if !settings.AutoTune && (settings.AutoTuneInterval > 0 || settings.OnAutoTune != nil) {
+		add(IssueIgnoredField, SeverityWarning, []string{"AutoTuneInterval", "OnAutoTune", "AutoTune"},
+			"AutoTuneInterval and OnAutoTune are ignored without AutoTune")
+	}
+
+	if settings.MetricsHistoryRetention > 0 && settings.MetricsHistoryInterval == 0 {
+		add(IssueIgnoredField, SeverityWarning, []string{"MetricsHistoryRetention", "MetricsHistoryInterval"},
+			"MetricsHistoryRetention is ignored without MetricsHistoryInterval")
+	}
+
+	if settings.JournalSize == 0 && (settings.JournalErrorLength > 0 || settings.LogTripJournal) {
+		add(IssueIgnoredField, SeverityWarning, []string{"JournalErrorLength", "LogTripJournal", "JournalSize"},
+			"JournalErrorLength and LogTripJournal are ignored without JournalSize")
+	}
+
+	if settings.MaxConcurrentWait > 0 && settings.MaxConcurrent == 0 {
+		add(IssueIgnoredField, SeverityWarning, []string{"MaxConcurrentWait", "MaxConcurrent"},
+			"MaxConcurrentWait is ignored without MaxConcurrent")
+	}
+
+	if settings.OnDegraded != nil && settings.WarnFailureRate == 0 {
+		add(IssueIgnoredField, SeverityWarning, []string{"OnDegraded", "WarnFailureRate"},
+			"OnDegraded is never called without WarnFailureRate")
+	}
+
+	if settings.OnWarning != nil && (settings.WarningThresholdFraction == 0 || !settings.AdaptiveThreshold) {
+		add(IssueIgnoredField, SeverityWarning, []string{"OnWarning", "WarningThresholdFraction"},
+			"OnWarning is never called without WarningThresholdFraction and AdaptiveThreshold")
+	}
+
+	if settings.PanicThreshold != 0 && settings.OnPanicThreshold == nil {
+		add(IssueIgnoredField, SeverityWarning, []string{"PanicThreshold", "OnPanicThreshold"},
+			"PanicThreshold is ignored without OnPanicThreshold")
+	}
+
+	// --- Suspicious timing ---
+
+	if settings.Interval > 0 && settings.Interval < shortInterval {
+		add(IssueSuspiciousTiming, SeverityWarning, []string{"Interval"},
+			"Interval %v is shorter than %v; counts may reset before slow requests complete", settings.Interval, shortInterval)
+	}
+
+	if settings.Interval > 0 && timeout < settings.Interval {
+		add(IssueSuspiciousTiming, SeverityWarning, []string{"Timeout", "Interval"},
+			"Timeout %v is shorter than Interval %v; the circuit probes before a full observation window has elapsed",
+			timeout, settings.Interval)
+	}
+
+	// --- Implicit defaults ---
+
+	if withInfo && settings.MaxRequests == 0 {
+		add(IssueImplicitDefault, SeverityInfo, []string{"MaxRequests"},
+			"MaxRequests is 0 and defaults to 1")
+	}
+
+	return issues
+}
+
+// validateSettings checks construction-time settings for invalid values.
+// Returns the first issue with SeverityError, or nil if settings are valid.
+func validateSettings(settings *Settings) error {
+	for _, issue := range settingsIssues(settings, false) {
+		if issue.Severity == SeverityError {
+			return issue
+		}
+	}
+	return nil
+}
+
+// validateWarningThresholdFraction checks WarningThresholdFraction is in [0, 1).
+func validateWarningThresholdFraction(fraction float64) error {
+	if fraction < 0 || fraction >= 1 {
+		return fmt.Errorf("autobreaker: WarningThresholdFraction must be in range [0, 1), got %v", fraction)
+	}
+	return nil
+}
+
+// validateRecoverFailureRate checks RecoverFailureRate is 0 or in
+// (0, failureRateThreshold].
+func validateRecoverFailureRate(recovery, failureRateThreshold float64) error {
+	if !(recovery >= 0) || recovery > failureRateThreshold {
+		return fmt.Errorf("autobreaker: RecoverFailureRate must be 0 or in range (0, FailureRateThreshold=%v], got %v",
+			failureRateThreshold, recovery)
+	}
+	return nil
+}
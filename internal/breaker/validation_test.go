@@ -0,0 +1,619 @@
+package breaker
+
+import (
+	"errors"
+	"math"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// issueKey is the comparable part of an Issue used by the matrix tests.
+type issueKey struct {
+	Code     IssueCode
+	Severity IssueSeverity
+	Field    string
+}
+
+func keysOf(issues []Issue) []issueKey {
+	var keys []issueKey
+	for _, issue := range issues {
+		keys = append(keys, issueKey{issue.Code, issue.Severity, issue.Fields[0]})
+	}
+	return keys
+}
+
+func alwaysTrip(Counts) bool                { return true }
+func alwaysTripEx(Counts, WindowInfo) bool  { return true }
+func alwaysSuccessful(error) bool           { return true }
+func zeroWeight(interface{}, error) float64 { return 0 }
+func onDegradedNoop(string, float64)        {}
+func onWarningNoop(string, float64, Counts) {}
+
+func TestValidateSettings_Matrix(t *testing.T) {
+	// base has no issues at all, so each case reports only what it changes.
+	base := func() Settings {
+		return Settings{Name: "test", MaxRequests: 1, Timeout: time.Minute}
+	}
+
+	tests := []struct {
+		name   string
+		modify func(*Settings)
+		want   []issueKey
+	}{
+		{"clean", func(s *Settings) {}, nil},
+		{"clean adaptive", func(s *Settings) {
+			s.AdaptiveThreshold = true
+			s.FailureRateThreshold = 0.1
+			s.MinimumObservations = 50
+			s.RecoverFailureRate = 0.05
+			s.WarnFailureRate = 0.08
+			s.WarningThresholdFraction = 0.5
+			s.OnDegraded = onDegradedNoop
+			s.OnWarning = onWarningNoop
+		}, nil},
+
+		// Invalid values
+		{"FailureRateThreshold zero adaptive", func(s *Settings) {
+			s.AdaptiveThreshold = true
+		}, nil},
+		{"FailureRateThreshold one", func(s *Settings) {
+			s.AdaptiveThreshold = true
+			s.FailureRateThreshold = 1
+		}, []issueKey{{IssueOutOfRange, SeverityError, "FailureRateThreshold"}}},
+		{"FailureRateThreshold negative", func(s *Settings) {
+			s.AdaptiveThreshold = true
+			s.FailureRateThreshold = -0.1
+		}, []issueKey{{IssueOutOfRange, SeverityError, "FailureRateThreshold"}}},
+		{"TransitionLoserBehavior unknown", func(s *Settings) {
+			s.TransitionLoserBehavior = 7
+		}, []issueKey{{IssueUnknownValue, SeverityError, "TransitionLoserBehavior"}}},
+		{"Interval negative", func(s *Settings) {
+			s.Interval = -time.Second
+		}, []issueKey{{IssueOutOfRange, SeverityError, "Interval"}}},
+		{"Timeout negative", func(s *Settings) {
+			s.Timeout = -time.Second
+		}, []issueKey{{IssueOutOfRange, SeverityError, "Timeout"}}},
+		{"RelativeFailureRateMultiplier negative", func(s *Settings) {
+			s.AdaptiveThreshold = true
+			s.Baseline = New(Settings{Name: "stable"})
+			s.RelativeFailureRateMultiplier = -1
+		}, []issueKey{{IssueOutOfRange, SeverityError, "RelativeFailureRateMultiplier"}}},
+		{"RelativeFailureRateMultiplier NaN", func(s *Settings) {
+			s.AdaptiveThreshold = true
+			s.Baseline = New(Settings{Name: "stable"})
+			s.RelativeFailureRateMultiplier = math.NaN()
+		}, []issueKey{{IssueOutOfRange, SeverityError, "RelativeFailureRateMultiplier"}}},
+		{"RelativeFailureRateMultiplier without Baseline", func(s *Settings) {
+			s.AdaptiveThreshold = true
+			s.RelativeFailureRateMultiplier = 3
+		}, []issueKey{{IssueIgnoredField, SeverityWarning, "RelativeFailureRateMultiplier"}}},
+		{"ErrorBudget at 1", func(s *Settings) {
+			s.AdaptiveThreshold = true
+			s.ErrorBudget = 1
+		}, []issueKey{{IssueOutOfRange, SeverityError, "ErrorBudget"}}},
+		{"ErrorBudget without AdaptiveThreshold", func(s *Settings) {
+			s.ErrorBudget = 0.001
+		}, []issueKey{{IssueIgnoredField, SeverityWarning, "ErrorBudget"}}},
+		{"BurnRateThreshold negative", func(s *Settings) {
+			s.AdaptiveThreshold = true
+			s.ErrorBudget = 0.001
+			s.BurnRateThreshold = -1
+		}, []issueKey{{IssueOutOfRange, SeverityError, "BurnRateThreshold"}}},
+		{"BurnRateThreshold without ErrorBudget", func(s *Settings) {
+			s.AdaptiveThreshold = true
+			s.BurnRateThreshold = 14
+		}, []issueKey{{IssueIgnoredField, SeverityWarning, "BurnRateThreshold"}}},
+		{"ExpectedRequestRate negative", func(s *Settings) {
+			s.ExpectedRequestRate = -1
+		}, []issueKey{{IssueOutOfRange, SeverityError, "ExpectedRequestRate"}}},
+		{"ExpectedRequestRate infinite", func(s *Settings) {
+			s.ExpectedRequestRate = math.Inf(1)
+		}, []issueKey{{IssueOutOfRange, SeverityError, "ExpectedRequestRate"}}},
+		{"MinObservationWindow negative", func(s *Settings) {
+			s.AdaptiveThreshold = true
+			s.MinObservationWindow = -time.Second
+		}, []issueKey{{IssueOutOfRange, SeverityError, "MinObservationWindow"}}},
+		{"DiagnosticsCacheTTL negative", func(s *Settings) {
+			s.DiagnosticsCacheTTL = -time.Second
+		}, []issueKey{{IssueOutOfRange, SeverityError, "DiagnosticsCacheTTL"}}},
+		{"WarningThresholdFraction one", func(s *Settings) {
+			s.AdaptiveThreshold = true
+			s.WarningThresholdFraction = 1
+		}, []issueKey{{IssueOutOfRange, SeverityError, "WarningThresholdFraction"}}},
+		{"RateEpsilon too large", func(s *Settings) {
+			s.RateEpsilon = 0.01
+		}, []issueKey{{IssueOutOfRange, SeverityError, "RateEpsilon"}}},
+		{"RecoverFailureRate above trip rate", func(s *Settings) {
+			s.AdaptiveThreshold = true
+			s.FailureRateThreshold = 0.1
+			s.RecoverFailureRate = 0.2
+		}, []issueKey{{IssueThresholdOrder, SeverityError, "RecoverFailureRate"}}},
+		{"RecoverFailureRate above default trip rate", func(s *Settings) {
+			s.AdaptiveThreshold = true
+			s.RecoverFailureRate = 0.06
+		}, []issueKey{{IssueThresholdOrder, SeverityError, "RecoverFailureRate"}}},
+		{"WarnFailureRate out of range", func(s *Settings) {
+			s.WarnFailureRate = 1
+		}, []issueKey{{IssueOutOfRange, SeverityError, "WarnFailureRate"}}},
+		{"WarnFailureRate at trip rate", func(s *Settings) {
+			s.AdaptiveThreshold = true
+			s.FailureRateThreshold = 0.1
+			s.WarnFailureRate = 0.1
+		}, []issueKey{{IssueThresholdOrder, SeverityError, "WarnFailureRate"}}},
+		{"WarnFailureRate out of range adaptive reports range only", func(s *Settings) {
+			s.AdaptiveThreshold = true
+			s.WarnFailureRate = 1.5
+		}, []issueKey{{IssueOutOfRange, SeverityError, "WarnFailureRate"}}},
+
+		// Ignored fields without AdaptiveThreshold
+		{"FailureRateThreshold without adaptive", func(s *Settings) {
+			s.FailureRateThreshold = 0.1
+		}, []issueKey{{IssueIgnoredField, SeverityWarning, "FailureRateThreshold"}}},
+		{"FailureRateThreshold out of range without adaptive", func(s *Settings) {
+			s.FailureRateThreshold = 2
+		}, []issueKey{{IssueIgnoredField, SeverityWarning, "FailureRateThreshold"}}},
+		{"MinimumObservations without adaptive", func(s *Settings) {
+			s.MinimumObservations = 50
+		}, []issueKey{{IssueIgnoredField, SeverityWarning, "MinimumObservations"}}},
+		{"MinimumObservations with warn band", func(s *Settings) {
+			s.MinimumObservations = 50
+			s.WarnFailureRate = 0.1
+		}, nil},
+		{"CacheLastSuccess without CacheTTL", func(s *Settings) {
+			s.CacheLastSuccess = true
+		}, []issueKey{{IssueIgnoredField, SeverityWarning, "CacheLastSuccess"}}},
+		{"ServeStaleOnTooManyRequests without CacheTTL", func(s *Settings) {
+			s.ServeStaleOnTooManyRequests = true
+		}, []issueKey{{IssueIgnoredField, SeverityWarning, "ServeStaleOnTooManyRequests"}}},
+		{"Baseline without adaptive", func(s *Settings) {
+			s.Baseline = New(Settings{Name: "stable"})
+		}, []issueKey{{IssueIgnoredField, SeverityWarning, "Baseline"}}},
+		{"MinObservationWindow without adaptive", func(s *Settings) {
+			s.MinObservationWindow = 10 * time.Second
+		}, []issueKey{{IssueIgnoredField, SeverityWarning, "MinObservationWindow"}}},
+		{"RecoverFailureRate without adaptive", func(s *Settings) {
+			s.RecoverFailureRate = 0.5
+		}, []issueKey{{IssueIgnoredField, SeverityWarning, "RecoverFailureRate"}}},
+		{"WarningThresholdFraction without adaptive", func(s *Settings) {
+			s.WarningThresholdFraction = 0.5
+		}, []issueKey{{IssueIgnoredField, SeverityWarning, "WarningThresholdFraction"}}},
+		{"all adaptive fields without adaptive", func(s *Settings) {
+			s.FailureRateThreshold = 0.1
+			s.MinimumObservations = 50
+			s.RecoverFailureRate = 0.05
+			s.WarningThresholdFraction = 0.5
+		}, []issueKey{
+			{IssueIgnoredField, SeverityWarning, "FailureRateThreshold"},
+			{IssueIgnoredField, SeverityWarning, "MinimumObservations"},
+			{IssueIgnoredField, SeverityWarning, "RecoverFailureRate"},
+			{IssueIgnoredField, SeverityWarning, "WarningThresholdFraction"},
+		}},
+
+		// Shadowed fields
+		{"ReadyToTrip with adaptive", func(s *Settings) {
+			s.AdaptiveThreshold = true
+			s.ReadyToTrip = alwaysTrip
+		}, []issueKey{{IssueShadowedField, SeverityWarning, "ReadyToTrip"}}},
+		{"ReadyToTrip static", func(s *Settings) {
+			s.ReadyToTrip = alwaysTrip
+		}, nil},
+		{"ConsecutiveFailureThreshold with ReadyToTrip", func(s *Settings) {
+			s.ConsecutiveFailureThreshold = 3
+			s.ReadyToTrip = alwaysTrip
+		}, []issueKey{{IssueShadowedField, SeverityWarning, "ConsecutiveFailureThreshold"}}},
+		{"ConsecutiveFailureThreshold with adaptive", func(s *Settings) {
+			s.ConsecutiveFailureThreshold = 3
+			s.AdaptiveThreshold = true
+		}, []issueKey{{IssueShadowedField, SeverityWarning, "ConsecutiveFailureThreshold"}}},
+		{"ReadyToTripEx with ReadyToTrip", func(s *Settings) {
+			s.ReadyToTrip = alwaysTrip
+			s.ReadyToTripEx = alwaysTripEx
+		}, []issueKey{{IssueShadowedField, SeverityWarning, "ReadyToTrip"}}},
+		{"ReadyToTripEx with adaptive", func(s *Settings) {
+			s.AdaptiveThreshold = true
+			s.ConsecutiveFailureThreshold = 0
+			s.ReadyToTripEx = alwaysTripEx
+		}, nil},
+		{"Engine with ReadyToTrip", func(s *Settings) {
+			s.Engine = NewEWMAEngine(0, 0, 0)
+			s.ReadyToTrip = alwaysTrip
+		}, []issueKey{{IssueShadowedField, SeverityWarning, "Engine"}}},
+		{"Engine alone", func(s *Settings) {
+			s.Engine = NewEWMAEngine(0, 0, 0)
+		}, nil},
+		{"ConsecutiveFailureThreshold static", func(s *Settings) {
+			s.ConsecutiveFailureThreshold = 3
+		}, nil},
+		{"IsSuccessful with OutcomeWeight", func(s *Settings) {
+			s.IsSuccessful = alwaysSuccessful
+			s.OutcomeWeight = zeroWeight
+		}, []issueKey{{IssueShadowedField, SeverityWarning, "IsSuccessful"}}},
+		{"OutcomeWeight alone", func(s *Settings) {
+			s.OutcomeWeight = zeroWeight
+		}, nil},
+
+		// Ignored dependents
+		{"RequireAllSuccesses without budget", func(s *Settings) {
+			s.RequireAllSuccesses = true
+		}, []issueKey{{IssueIgnoredField, SeverityWarning, "RequireAllSuccesses"}}},
+		{"RequireAllSuccesses with budget", func(s *Settings) {
+			s.RequireAllSuccesses = true
+			s.HalfOpenMaxProbes = 3
+		}, nil},
+		{"AlignIntervalToWallClock without Interval", func(s *Settings) {
+			s.AlignIntervalToWallClock = true
+		}, []issueKey{{IssueIgnoredField, SeverityWarning, "AlignIntervalToWallClock"}}},
+		{"RequireFullWindow without alignment", func(s *Settings) {
+			s.AdaptiveThreshold = true
+			s.Interval = time.Minute
+			s.RequireFullWindow = true
+		}, []issueKey{{IssueIgnoredField, SeverityWarning, "RequireFullWindow"}}},
+		{"RequireFullWindow without adaptive", func(s *Settings) {
+			s.Interval = time.Minute
+			s.AlignIntervalToWallClock = true
+			s.RequireFullWindow = true
+		}, []issueKey{{IssueIgnoredField, SeverityWarning, "RequireFullWindow"}}},
+		{"aligned adaptive full window", func(s *Settings) {
+			s.AdaptiveThreshold = true
+			s.Interval = time.Minute
+			s.AlignIntervalToWallClock = true
+			s.RequireFullWindow = true
+		}, nil},
+		{"PreserveStreaksOnIntervalReset without Interval", func(s *Settings) {
+			s.PreserveStreaksOnIntervalReset = true
+		}, []issueKey{{IssueIgnoredField, SeverityWarning, "PreserveStreaksOnIntervalReset"}}},
+		{"PreserveStreaksOnIntervalReset with Interval", func(s *Settings) {
+			s.PreserveStreaksOnIntervalReset = true
+			s.Interval = time.Minute
+		}, nil},
+		{"IntervalResetsOpenState without Interval", func(s *Settings) {
+			s.IntervalResetsOpenState = true
+		}, []issueKey{{IssueIgnoredField, SeverityWarning, "IntervalResetsOpenState"}}},
+		{"IntervalResetsOpenState with Interval", func(s *Settings) {
+			s.IntervalResetsOpenState = true
+			s.Interval = time.Minute
+		}, nil},
+		{"MinTimeout negative", func(s *Settings) {
+			s.AdaptiveTimeout = true
+			s.MinTimeout = -time.Second
+		}, []issueKey{{IssueOutOfRange, SeverityError, "MinTimeout"}}},
+		{"MinTimeout above MaxTimeout", func(s *Settings) {
+			s.AdaptiveTimeout = true
+			s.MinTimeout = time.Minute
+			s.MaxTimeout = time.Second
+		}, []issueKey{{IssueThresholdOrder, SeverityError, "MinTimeout"}}},
+		{"MinTimeout above default MaxTimeout", func(s *Settings) {
+			s.AdaptiveTimeout = true
+			s.MinTimeout = time.Hour
+		}, []issueKey{{IssueThresholdOrder, SeverityError, "MinTimeout"}}},
+		{"timeout bounds without AdaptiveTimeout", func(s *Settings) {
+			s.MinTimeout = time.Second
+			s.MaxTimeout = time.Minute
+		}, []issueKey{
+			{IssueIgnoredField, SeverityWarning, "MinTimeout"},
+			{IssueIgnoredField, SeverityWarning, "MaxTimeout"},
+		}},
+		{"HalfOpenProbeTimeout negative", func(s *Settings) {
+			s.HalfOpenProbeTimeout = -time.Second
+		}, []issueKey{{IssueOutOfRange, SeverityError, "HalfOpenProbeTimeout"}}},
+		{"JournalSize negative", func(s *Settings) {
+			s.JournalSize = -1
+		}, []issueKey{{IssueOutOfRange, SeverityError, "JournalSize"}}},
+		{"JournalErrorLength negative", func(s *Settings) {
+			s.JournalSize = 8
+			s.JournalErrorLength = -1
+		}, []issueKey{{IssueOutOfRange, SeverityError, "JournalErrorLength"}}},
+		{"journal settings without JournalSize", func(s *Settings) {
+			s.LogTripJournal = true
+		}, []issueKey{{IssueIgnoredField, SeverityWarning, "JournalErrorLength"}}},
+		{"EligibleProbeWait negative", func(s *Settings) {
+			s.EligibleProbeWait = -time.Second
+		}, []issueKey{{IssueOutOfRange, SeverityError, "EligibleProbeWait"}}},
+		{"MinClosedDuration negative", func(s *Settings) {
+			s.MinClosedDuration = -time.Second
+		}, []issueKey{{IssueOutOfRange, SeverityError, "MinClosedDuration"}}},
+		{"ProbeFairnessWait negative", func(s *Settings) {
+			s.ProbeFairnessWait = -time.Second
+		}, []issueKey{{IssueOutOfRange, SeverityError, "ProbeFairnessWait"}}},
+		{"SlowCallFactor below 1", func(s *Settings) {
+			s.SlowCallFactor = 0.5
+		}, []issueKey{{IssueOutOfRange, SeverityError, "SlowCallFactor"}}},
+		{"EpisodeHistory negative", func(s *Settings) {
+			s.EpisodeHistory = -1
+		}, []issueKey{{IssueOutOfRange, SeverityError, "EpisodeHistory"}}},
+		{"StreamTimeout negative", func(s *Settings) {
+			s.StreamTimeout = -time.Second
+		}, []issueKey{{IssueOutOfRange, SeverityError, "StreamTimeout"}}},
+		{"MaxOpenDuration negative", func(s *Settings) {
+			s.MaxOpenDuration = -time.Second
+		}, []issueKey{{IssueOutOfRange, SeverityError, "MaxOpenDuration"}}},
+		{"StuckOpenAction unknown", func(s *Settings) {
+			s.MaxOpenDuration = time.Hour
+			s.StuckOpenAction = 9
+		}, []issueKey{{IssueUnknownValue, SeverityError, "StuckOpenAction"}}},
+		{"stuck-open settings without MaxOpenDuration", func(s *Settings) {
+			s.OnStuckOpen = func(string, time.Duration) {}
+			s.StuckOpenAction = StuckOpenForceClose
+		}, []issueKey{
+			{IssueIgnoredField, SeverityWarning, "OnStuckOpen"},
+			{IssueIgnoredField, SeverityWarning, "StuckOpenAction"},
+		}},
+		{"stuck-open protection", func(s *Settings) {
+			s.MaxOpenDuration = time.Hour
+			s.OnStuckOpen = func(string, time.Duration) {}
+			s.StuckOpenAction = StuckOpenForceHalfOpen
+		}, nil},
+		{"ClassifierPanicOutcome unknown", func(s *Settings) {
+			s.IsSuccessful = DefaultIsSuccessful
+			s.ClassifierPanicOutcome = 5
+		}, []issueKey{{IssueUnknownValue, SeverityError, "ClassifierPanicOutcome"}}},
+		{"ClassifierPanicOutcome with default classifier", func(s *Settings) {
+			s.ClassifierPanicOutcome = ClassifierPanicIgnore
+		}, []issueKey{{IssueIgnoredField, SeverityWarning, "ClassifierPanicOutcome"}}},
+		{"ClassifierPanicOutcome with OutcomeWeight", func(s *Settings) {
+			s.OutcomeWeight = func(interface{}, error) float64 { return 0 }
+			s.ClassifierPanicOutcome = ClassifierPanicSuccess
+		}, nil},
+		{"MaxConcurrentWait negative", func(s *Settings) {
+			s.MaxConcurrent = 4
+			s.MaxConcurrentWait = -time.Second
+		}, []issueKey{{IssueOutOfRange, SeverityError, "MaxConcurrentWait"}}},
+		{"CacheTTL negative", func(s *Settings) {
+			s.CacheTTL = -time.Second
+		}, []issueKey{{IssueOutOfRange, SeverityError, "CacheTTL"}}},
+		{"Labels empty key", func(s *Settings) {
+			s.Labels = map[string]string{"": "x", "team": "a"}
+		}, []issueKey{{IssueInvalidLabel, SeverityError, "Labels"}}},
+		{"Labels reserved key", func(s *Settings) {
+			s.Labels = map[string]string{"name": "x"}
+		}, []issueKey{{IssueInvalidLabel, SeverityError, "Labels"}}},
+		{"Labels valid", func(s *Settings) {
+			s.Labels = map[string]string{"team": "a", "tier": ""}
+		}, nil},
+		{"RecommendationWindow negative", func(s *Settings) {
+			s.RecommendationWindow = -time.Hour
+		}, []issueKey{{IssueOutOfRange, SeverityError, "RecommendationWindow"}}},
+		{"RecommendationWindow shorter than bucket", func(s *Settings) {
+			s.RecommendationWindow = 30 * time.Minute
+		}, []issueKey{{IssueThresholdOrder, SeverityError, "RecommendationWindow"}}},
+		{"RecommendationWindow too many buckets", func(s *Settings) {
+			s.RecommendationWindow = 24 * time.Hour
+			s.RecommendationBucket = time.Second
+		}, []issueKey{{IssueOutOfRange, SeverityError, "RecommendationWindow"}}},
+		{"RecommendationWindow valid", func(s *Settings) {
+			s.RecommendationWindow = 24 * time.Hour
+		}, nil},
+		{"RecommendationBucket negative", func(s *Settings) {
+			s.RecommendationWindow = 24 * time.Hour
+			s.RecommendationBucket = -time.Hour
+		}, []issueKey{{IssueOutOfRange, SeverityError, "RecommendationBucket"}}},
+		{"RecommendationMinThreshold out of range", func(s *Settings) {
+			s.RecommendationWindow = 24 * time.Hour
+			s.RecommendationMinThreshold = 1
+		}, []issueKey{{IssueOutOfRange, SeverityError, "RecommendationMinThreshold"}}},
+		{"RecommendationMaxThreshold out of range", func(s *Settings) {
+			s.RecommendationWindow = 24 * time.Hour
+			s.RecommendationMaxThreshold = -0.5
+		}, []issueKey{{IssueOutOfRange, SeverityError, "RecommendationMaxThreshold"}}},
+		{"RecommendationMinThreshold above max", func(s *Settings) {
+			s.RecommendationWindow = 24 * time.Hour
+			s.RecommendationMinThreshold = 0.6
+		}, []issueKey{{IssueThresholdOrder, SeverityError, "RecommendationMinThreshold"}}},
+		{"AutoTuneInterval negative", func(s *Settings) {
+			s.AutoTune = true
+			s.AdaptiveThreshold = true
+			s.RecommendationWindow = 24 * time.Hour
+			s.AutoTuneInterval = -time.Hour
+		}, []issueKey{{IssueOutOfRange, SeverityError, "AutoTuneInterval"}}},
+		{"RecommendationBucket without window", func(s *Settings) {
+			s.RecommendationBucket = time.Hour
+		}, []issueKey{{IssueIgnoredField, SeverityWarning, "RecommendationBucket"}}},
+		{"Recommendation clamps without window", func(s *Settings) {
+			s.RecommendationMaxThreshold = 0.3
+		}, []issueKey{{IssueIgnoredField, SeverityWarning, "RecommendationMinThreshold"}}},
+		{"AutoTune without adaptive", func(s *Settings) {
+			s.AutoTune = true
+			s.RecommendationWindow = 24 * time.Hour
+		}, []issueKey{{IssueIgnoredField, SeverityWarning, "AutoTune"}}},
+		{"AutoTune valid", func(s *Settings) {
+			s.AutoTune = true
+			s.AdaptiveThreshold = true
+			s.RecommendationWindow = 24 * time.Hour
+			s.OnAutoTune = func(string, ChangeSet) {}
+		}, nil},
+		{"OnAutoTune without AutoTune", func(s *Settings) {
+			s.OnAutoTune = func(string, ChangeSet) {}
+		}, []issueKey{{IssueIgnoredField, SeverityWarning, "AutoTuneInterval"}}},
+		{"MetricsHistoryInterval negative", func(s *Settings) {
+			s.MetricsHistoryInterval = -time.Second
+		}, []issueKey{{IssueOutOfRange, SeverityError, "MetricsHistoryInterval"}}},
+		{"MetricsHistoryRetention without interval", func(s *Settings) {
+			s.MetricsHistoryRetention = 10
+		}, []issueKey{{IssueIgnoredField, SeverityWarning, "MetricsHistoryRetention"}}},
+		{"MetricsHistoryRetention with interval", func(s *Settings) {
+			s.MetricsHistoryInterval = time.Second
+			s.MetricsHistoryRetention = 10
+		}, nil},
+		{"HealthScoreAlpha negative", func(s *Settings) {
+			s.HealthScoreAlpha = -0.1
+		}, []issueKey{{IssueOutOfRange, SeverityError, "HealthScoreAlpha"}}},
+		{"HealthScoreAlpha above 1", func(s *Settings) {
+			s.HealthScoreAlpha = 1.5
+		}, []issueKey{{IssueOutOfRange, SeverityError, "HealthScoreAlpha"}}},
+		{"HealthScoreAlpha NaN", func(s *Settings) {
+			s.HealthScoreAlpha = math.NaN()
+		}, []issueKey{{IssueOutOfRange, SeverityError, "HealthScoreAlpha"}}},
+		{"HealthScoreAlpha 1", func(s *Settings) {
+			s.HealthScoreAlpha = 1
+		}, nil},
+		{"MaxConcurrentWait without MaxConcurrent", func(s *Settings) {
+			s.MaxConcurrentWait = time.Second
+		}, []issueKey{{IssueIgnoredField, SeverityWarning, "MaxConcurrentWait"}}},
+		{"bulkhead", func(s *Settings) {
+			s.MaxConcurrent = 4
+			s.MaxConcurrentWait = time.Second
+		}, nil},
+		{"OnDegraded without WarnFailureRate", func(s *Settings) {
+			s.OnDegraded = onDegradedNoop
+		}, []issueKey{{IssueIgnoredField, SeverityWarning, "OnDegraded"}}},
+		{"OnDegraded with WarnFailureRate", func(s *Settings) {
+			s.OnDegraded = onDegradedNoop
+			s.WarnFailureRate = 0.1
+		}, nil},
+		{"OnWarning without fraction", func(s *Settings) {
+			s.AdaptiveThreshold = true
+			s.OnWarning = onWarningNoop
+		}, []issueKey{{IssueIgnoredField, SeverityWarning, "OnWarning"}}},
+		{"OnWarning without adaptive", func(s *Settings) {
+			s.WarningThresholdFraction = 0.5
+			s.OnWarning = onWarningNoop
+		}, []issueKey{
+			{IssueIgnoredField, SeverityWarning, "WarningThresholdFraction"},
+			{IssueIgnoredField, SeverityWarning, "OnWarning"},
+		}},
+
+		// Timing
+		{"Interval short", func(s *Settings) {
+			s.Interval = 100 * time.Millisecond
+		}, []issueKey{{IssueSuspiciousTiming, SeverityWarning, "Interval"}}},
+		{"Interval at threshold", func(s *Settings) {
+			s.Interval = time.Second
+		}, nil},
+		{"Timeout shorter than Interval", func(s *Settings) {
+			s.Timeout = 10 * time.Second
+			s.Interval = time.Minute
+		}, []issueKey{{IssueSuspiciousTiming, SeverityWarning, "Timeout"}}},
+		{"default Timeout shorter than Interval", func(s *Settings) {
+			s.Timeout = 0
+			s.Interval = 2 * time.Minute
+		}, []issueKey{{IssueSuspiciousTiming, SeverityWarning, "Timeout"}}},
+		{"Timeout equal to Interval", func(s *Settings) {
+			s.Interval = time.Minute
+		}, nil},
+		{"Timeout with no Interval", func(s *Settings) {
+			s.Timeout = time.Millisecond
+		}, nil},
+
+		// Implicit defaults
+		{"MaxRequests zero", func(s *Settings) {
+			s.MaxRequests = 0
+		}, []issueKey{{IssueImplicitDefault, SeverityInfo, "MaxRequests"}}},
+
+		// Combinations keep a fixed order: errors, then warnings, then info
+		{"errors before warnings before info", func(s *Settings) {
+			s.MaxRequests = 0
+			s.Interval = 10 * time.Millisecond
+			s.FailureRateThreshold = 0.1
+			s.RateEpsilon = -1
+		}, []issueKey{
+			{IssueOutOfRange, SeverityError, "RateEpsilon"},
+			{IssueIgnoredField, SeverityWarning, "FailureRateThreshold"},
+			{IssueSuspiciousTiming, SeverityWarning, "Interval"},
+			{IssueImplicitDefault, SeverityInfo, "MaxRequests"},
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			settings := base()
+			tt.modify(&settings)
+
+			got := keysOf(ValidateSettings(settings))
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Expected issues %v, got %v", tt.want, got)
+			}
+
+			// Strict promotes warnings to errors and leaves info alone
+			settings.Strict = true
+			var strictWant []issueKey
+			for _, key := range tt.want {
+				if key.Severity == SeverityWarning {
+					key.Severity = SeverityError
+				}
+				strictWant = append(strictWant, key)
+			}
+			if got := keysOf(ValidateSettings(settings)); !reflect.DeepEqual(got, strictWant) {
+				t.Errorf("Strict: expected issues %v, got %v", strictWant, got)
+			}
+		})
+	}
+}
+
+func TestValidateSettings_ConstructorsHonorSeverity(t *testing.T) {
+	tests := []struct {
+		name     string
+		settings Settings
+		wantErr  bool
+		wantCode IssueCode
+	}{
+		{"info is never fatal", Settings{Strict: true}, false, ""},
+		{"warning is not fatal", Settings{FailureRateThreshold: 0.1}, false, ""},
+		{"warning is fatal under strict", Settings{FailureRateThreshold: 0.1, Strict: true}, true, IssueIgnoredField},
+		{"shadowed is fatal under strict", Settings{AdaptiveThreshold: true, ReadyToTrip: alwaysTrip, Strict: true}, true, IssueShadowedField},
+		{"error is fatal", Settings{Timeout: -time.Second}, true, IssueOutOfRange},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cb, cleanup, err := NewChecked(tt.settings)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("NewChecked error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr {
+				cleanup()
+				if cb.CurrentSettings().Strict != tt.settings.Strict {
+					t.Error("Expected CurrentSettings to report Strict")
+				}
+				return
+			}
+
+			var issue Issue
+			if !errors.As(err, &issue) {
+				t.Fatalf("Expected error to be an Issue, got %T", err)
+			}
+			if issue.Code != tt.wantCode || issue.Severity != SeverityError {
+				t.Errorf("Expected %s error, got %s %s", tt.wantCode, issue.Code, issue.Severity)
+			}
+
+			defer func() {
+				if r := recover(); r == nil {
+					t.Error("Expected New to panic")
+				}
+			}()
+			New(tt.settings)
+		})
+	}
+}
+
+func TestValidateSettings_IssueFormatting(t *testing.T) {
+	issues := ValidateSettings(Settings{Interval: -time.Second})
+	if len(issues) == 0 {
+		t.Fatal("Expected an issue")
+	}
+
+	want := "autobreaker: Interval cannot be negative, got -1s"
+	if got := issues[0].Error(); got != want {
+		t.Errorf("Expected %q, got %q", want, got)
+	}
+	if got := SeverityWarning.String(); got != "warning" {
+		t.Errorf("Expected %q, got %q", "warning", got)
+	}
+	if got := IssueSeverity(9).String(); got != "IssueSeverity(9)" {
+		t.Errorf("Expected IssueSeverity(9), got %q", got)
+	}
+}
+
+func TestValidateSettings_StrictCurrentSettingsRoundTrip(t *testing.T) {
+	cb := New(Settings{
+		Name:          "batch",
+		Timeout:       time.Minute,
+		OutcomeWeight: zeroWeight,
+		Strict:        true,
+	})
+
+	// Defaults applied by New must not make a strict replica fail
+	if _, _, err := NewChecked(cb.CurrentSettings()); err != nil {
+		t.Errorf("Expected strict replica to construct, got %v", err)
+	}
+}
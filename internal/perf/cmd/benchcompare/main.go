@@ -0,0 +1,18 @@
+// Command benchcompare runs the circuit breaker hot-path benchmarks for the
+// current tree and a git ref, prints a JSON report, and exits non-zero if any
+// benchmark regressed beyond the threshold or gained allocations.
+//
+// Usage:
+//
+//	go run ./internal/perf/cmd/benchcompare -ref main -threshold 10
+package main
+
+import (
+	"os"
+
+	"github.com/1mb-dev/autobreaker/internal/perf"
+)
+
+func main() {
+	os.Exit(perf.Main(os.Args[1:], os.Stdout, os.Stderr, perf.Runner{}))
+}
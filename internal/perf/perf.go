@@ -0,0 +1,336 @@
+// Package perf compares circuit breaker benchmark results between the current
+// tree and a git ref, and flags hot-path regressions.
+//
+// It backs the "make bench-compare" target. Raw benchmark output for both trees
+// can be written to disk in the standard Go benchmark format, so it can also be
+// fed to benchstat for a statistical comparison:
+//
+//	go run ./internal/perf/cmd/benchcompare -ref v1.1.2 -raw /tmp/bench
+//	benchstat /tmp/bench/base.txt /tmp/bench/head.txt
+package perf
+
+import (
+	"bufio"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// DefaultBenchPattern selects the hot-path benchmarks gated by bench-compare.
+const DefaultBenchPattern = `^Benchmark(Execute_Closed|Execute_ClosedInterval|Execute_Open|ExecuteContext_Closed|State|Counts|Metrics|UpdateSettings)$`
+
+// DefaultPackage is the package whose benchmarks are run.
+const DefaultPackage = "./internal/breaker"
+
+// Result is the aggregated result of one benchmark across all of its runs.
+// Values are medians, which are less sensitive to noisy runs than means.
+type Result struct {
+	Name        string  `json:"name"`
+	NsPerOp     float64 `json:"ns_per_op"`
+	BytesPerOp  float64 `json:"bytes_per_op"`
+	AllocsPerOp float64 `json:"allocs_per_op"`
+	Samples     int     `json:"samples"`
+}
+
+// Parse reads `go test -bench -benchmem` output and returns results keyed by
+// benchmark name (without the -GOMAXPROCS suffix). Non-benchmark lines are ignored.
+func Parse(r io.Reader) (map[string]Result, error) {
+	type samples struct{ ns, bytes, allocs []float64 }
+	byName := make(map[string]*samples)
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 4 || !strings.HasPrefix(fields[0], "Benchmark") {
+			continue
+		}
+		if _, err := strconv.Atoi(fields[1]); err != nil {
+			continue // Not a result line (e.g. "BenchmarkFoo ... FAIL")
+		}
+
+		name := fields[0]
+		if i := strings.LastIndexByte(name, '-'); i > 0 {
+			if _, err := strconv.Atoi(name[i+1:]); err == nil {
+				name = name[:i]
+			}
+		}
+
+		s, ok := byName[name]
+		if !ok {
+			s = &samples{}
+			byName[name] = s
+		}
+
+		// Remaining fields are value/unit pairs
+		for i := 2; i+1 < len(fields); i += 2 {
+			v, err := strconv.ParseFloat(fields[i], 64)
+			if err != nil {
+				return nil, fmt.Errorf("perf: invalid value %q in %q", fields[i], scanner.Text())
+			}
+			switch fields[i+1] {
+			case "ns/op":
+				s.ns = append(s.ns, v)
+			case "B/op":
+				s.bytes = append(s.bytes, v)
+			case "allocs/op":
+				s.allocs = append(s.allocs, v)
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	results := make(map[string]Result, len(byName))
+	for name, s := range byName {
+		results[name] = Result{
+			Name:        name,
+			NsPerOp:     median(s.ns),
+			BytesPerOp:  median(s.bytes),
+			AllocsPerOp: median(s.allocs),
+			Samples:     len(s.ns),
+		}
+	}
+	return results, nil
+}
+
+// Comparison is the base/head comparison of a single benchmark.
+type Comparison struct {
+	Name      string  `json:"name"`
+	Base      Result  `json:"base"`
+	Head      Result  `json:"head"`
+	DeltaPct  float64 `json:"delta_pct"`
+	Regressed bool    `json:"regressed"`
+	Reason    string  `json:"reason,omitempty"`
+}
+
+// Report is the machine-readable outcome of a comparison.
+type Report struct {
+	BaseRef      string       `json:"base_ref"`
+	ThresholdPct float64      `json:"threshold_pct"`
+	Comparisons  []Comparison `json:"comparisons"`
+	Regressions  int          `json:"regressions"`
+}
+
+// Failed reports whether any benchmark regressed.
+func (r Report) Failed() bool {
+	return r.Regressions > 0
+}
+
+// Compare compares head results against base results.
+//
+// A benchmark regresses if its ns/op grew by more than thresholdPct percent or
+// it gained allocations. Benchmarks present in only one side are skipped.
+// Comparisons are sorted by name.
+func Compare(base, head map[string]Result, thresholdPct float64) Report {
+	report := Report{ThresholdPct: thresholdPct}
+
+	for name, h := range head {
+		b, ok := base[name]
+		if !ok {
+			continue
+		}
+
+		c := Comparison{Name: name, Base: b, Head: h}
+		if b.NsPerOp > 0 {
+			c.DeltaPct = (h.NsPerOp - b.NsPerOp) / b.NsPerOp * 100
+		}
+
+		switch {
+		case h.AllocsPerOp > b.AllocsPerOp:
+			c.Regressed = true
+			c.Reason = fmt.Sprintf("allocs/op %g -> %g", b.AllocsPerOp, h.AllocsPerOp)
+		case c.DeltaPct > thresholdPct:
+			c.Regressed = true
+			c.Reason = fmt.Sprintf("ns/op +%.1f%% exceeds %.1f%%", c.DeltaPct, thresholdPct)
+		}
+		if c.Regressed {
+			report.Regressions++
+		}
+
+		report.Comparisons = append(report.Comparisons, c)
+	}
+
+	sort.Slice(report.Comparisons, func(i, j int) bool {
+		return report.Comparisons[i].Name < report.Comparisons[j].Name
+	})
+	return report
+}
+
+// Runner runs benchmarks in a source tree.
+type Runner struct {
+	// Command runs name with args in dir and returns its combined output.
+	// Defaults to executing the command with os/exec.
+	Command func(dir, name string, args ...string) ([]byte, error)
+
+	// Pattern is the -bench regular expression. Defaults to DefaultBenchPattern.
+	Pattern string
+
+	// Count is the number of runs per benchmark (-count). Defaults to 5.
+	Count int
+
+	// Package is the package to benchmark. Defaults to DefaultPackage.
+	Package string
+}
+
+// Bench runs the benchmarks in dir and returns the raw output.
+func (r Runner) Bench(dir string) ([]byte, error) {
+	pattern := r.Pattern
+	if pattern == "" {
+		pattern = DefaultBenchPattern
+	}
+	count := r.Count
+	if count <= 0 {
+		count = 5
+	}
+	pkg := r.Package
+	if pkg == "" {
+		pkg = DefaultPackage
+	}
+
+	out, err := r.command()(dir, "go", "test", "-run", "^$", "-bench", pattern,
+		"-benchmem", "-count", strconv.Itoa(count), pkg)
+	if err != nil {
+		return nil, fmt.Errorf("perf: benchmarks failed in %s: %w\n%s", dir, err, out)
+	}
+	return out, nil
+}
+
+// BenchRef checks out ref of the repository at repoDir into a temporary git
+// worktree, runs the benchmarks there, and removes the worktree.
+func (r Runner) BenchRef(repoDir, ref string) ([]byte, error) {
+	tmp, err := os.MkdirTemp("", "autobreaker-perf-")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(tmp)
+
+	tree := filepath.Join(tmp, "tree")
+	run := r.command()
+	if out, err := run(repoDir, "git", "worktree", "add", "--detach", tree, ref); err != nil {
+		return nil, fmt.Errorf("perf: checking out %s: %w\n%s", ref, err, out)
+	}
+	defer run(repoDir, "git", "worktree", "remove", "--force", tree) //nolint:errcheck // best-effort cleanup
+
+	return r.Bench(tree)
+}
+
+func (r Runner) command() func(dir, name string, args ...string) ([]byte, error) {
+	if r.Command != nil {
+		return r.Command
+	}
+	return func(dir, name string, args ...string) ([]byte, error) {
+		cmd := exec.Command(name, args...)
+		cmd.Dir = dir
+		return cmd.CombinedOutput()
+	}
+}
+
+// Main implements the benchcompare command. It returns the process exit code:
+// 0 if no benchmark regressed, 1 on regression, 2 on usage or execution errors.
+//
+// Flags:
+//
+//	-ref        git ref to compare against (default "main")
+//	-threshold  maximum allowed ns/op regression in percent (default 10)
+//	-count      runs per benchmark (default 5)
+//	-bench      benchmark regular expression (default DefaultBenchPattern)
+//	-repo       repository root (default ".")
+//	-raw        directory to write base.txt and head.txt for benchstat
+func Main(args []string, stdout, stderr io.Writer, runner Runner) int {
+	fs := flag.NewFlagSet("benchcompare", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	ref := fs.String("ref", "main", "git ref to compare against")
+	threshold := fs.Float64("threshold", 10, "maximum allowed ns/op regression in percent")
+	count := fs.Int("count", 5, "runs per benchmark")
+	pattern := fs.String("bench", DefaultBenchPattern, "benchmark regular expression")
+	repo := fs.String("repo", ".", "repository root")
+	raw := fs.String("raw", "", "directory to write base.txt and head.txt for benchstat")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+
+	runner.Pattern = *pattern
+	runner.Count = *count
+
+	baseOut, err := runner.BenchRef(*repo, *ref)
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return 2
+	}
+	headOut, err := runner.Bench(*repo)
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return 2
+	}
+
+	if *raw != "" {
+		if err := writeRaw(*raw, baseOut, headOut); err != nil {
+			fmt.Fprintln(stderr, err)
+			return 2
+		}
+	}
+
+	base, err := Parse(strings.NewReader(string(baseOut)))
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return 2
+	}
+	head, err := Parse(strings.NewReader(string(headOut)))
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return 2
+	}
+
+	report := Compare(base, head, *threshold)
+	report.BaseRef = *ref
+
+	enc := json.NewEncoder(stdout)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(report); err != nil {
+		fmt.Fprintln(stderr, err)
+		return 2
+	}
+
+	if report.Failed() {
+		for _, c := range report.Comparisons {
+			if c.Regressed {
+				fmt.Fprintf(stderr, "REGRESSION %s: %s\n", c.Name, c.Reason)
+			}
+		}
+		return 1
+	}
+	return 0
+}
+
+// writeRaw writes raw benchmark output for benchstat.
+func writeRaw(dir string, base, head []byte) error {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+	if err := os.WriteFile(filepath.Join(dir, "base.txt"), base, 0o644); err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(dir, "head.txt"), head, 0o644)
+}
+
+// median returns the median of values, or 0 if there are none.
+func median(values []float64) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+	sorted := append([]float64(nil), values...)
+	sort.Float64s(sorted)
+	mid := len(sorted) / 2
+	if len(sorted)%2 == 0 {
+		return (sorted[mid-1] + sorted[mid]) / 2
+	}
+	return sorted[mid]
+}
@@ -0,0 +1,159 @@
+package perf
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+const sampleOutput = `goos: linux
+goarch: amd64
+pkg: github.com/1mb-dev/autobreaker/internal/breaker
+BenchmarkExecute_Closed-8   	24053371	        50.00 ns/op	       0 B/op	       0 allocs/op
+BenchmarkExecute_Closed-8   	23641590	        52.00 ns/op	       0 B/op	       0 allocs/op
+BenchmarkExecute_Closed-8   	23238746	        70.00 ns/op	       0 B/op	       0 allocs/op
+BenchmarkState-8            	1000000000	         0.66 ns/op	       0 B/op	       0 allocs/op
+PASS
+ok  	github.com/1mb-dev/autobreaker/internal/breaker	4.210s
+`
+
+func TestParse_MediansAndStripsProcs(t *testing.T) {
+	results, err := Parse(strings.NewReader(sampleOutput))
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+
+	if len(results) != 2 {
+		t.Fatalf("Expected 2 results, got %d: %+v", len(results), results)
+	}
+
+	closed, ok := results["BenchmarkExecute_Closed"]
+	if !ok {
+		t.Fatalf("Expected BenchmarkExecute_Closed without -8 suffix, got %+v", results)
+	}
+	if closed.NsPerOp != 52 {
+		t.Errorf("Expected median 52 ns/op, got %v", closed.NsPerOp)
+	}
+	if closed.Samples != 3 {
+		t.Errorf("Expected 3 samples, got %d", closed.Samples)
+	}
+}
+
+func TestParse_InvalidValue(t *testing.T) {
+	_, err := Parse(strings.NewReader("BenchmarkX-8 100 abc ns/op\n"))
+	if err == nil {
+		t.Fatal("Expected error for invalid value")
+	}
+}
+
+func TestCompare_ThresholdAndAllocs(t *testing.T) {
+	base := map[string]Result{
+		"BenchmarkA": {Name: "BenchmarkA", NsPerOp: 100},
+		"BenchmarkB": {Name: "BenchmarkB", NsPerOp: 100},
+		"BenchmarkC": {Name: "BenchmarkC", NsPerOp: 100},
+		"BenchmarkD": {Name: "BenchmarkD", NsPerOp: 100},
+	}
+	head := map[string]Result{
+		"BenchmarkA": {Name: "BenchmarkA", NsPerOp: 105},                // within threshold
+		"BenchmarkB": {Name: "BenchmarkB", NsPerOp: 120},                // too slow
+		"BenchmarkC": {Name: "BenchmarkC", NsPerOp: 90, AllocsPerOp: 1}, // new allocation
+		"BenchmarkE": {Name: "BenchmarkE", NsPerOp: 500},                // no baseline
+	}
+
+	report := Compare(base, head, 10)
+
+	if len(report.Comparisons) != 3 {
+		t.Fatalf("Expected 3 comparisons, got %d", len(report.Comparisons))
+	}
+	if report.Regressions != 2 {
+		t.Errorf("Expected 2 regressions, got %d", report.Regressions)
+	}
+	if !report.Failed() {
+		t.Error("Expected report to fail")
+	}
+
+	want := map[string]bool{"BenchmarkA": false, "BenchmarkB": true, "BenchmarkC": true}
+	for i, c := range report.Comparisons {
+		if i > 0 && report.Comparisons[i-1].Name >= c.Name {
+			t.Errorf("Expected comparisons sorted by name, got %s before %s",
+				report.Comparisons[i-1].Name, c.Name)
+		}
+		if c.Regressed != want[c.Name] {
+			t.Errorf("%s: expected regressed=%v, got %v (%s)", c.Name, want[c.Name], c.Regressed, c.Reason)
+		}
+	}
+}
+
+// fakeRunner returns a Runner whose benchmark output depends on whether it runs
+// in the git worktree (base) or the repository itself (head).
+func fakeRunner(base, head string) (Runner, *[]string) {
+	var calls []string
+	return Runner{
+		Command: func(dir, name string, args ...string) ([]byte, error) {
+			calls = append(calls, name+" "+strings.Join(args, " "))
+			if name == "git" {
+				return nil, nil
+			}
+			if dir == "repo" {
+				return []byte(head), nil
+			}
+			return []byte(base), nil
+		},
+	}, &calls
+}
+
+func TestMain_NoRegression(t *testing.T) {
+	runner, calls := fakeRunner(sampleOutput, sampleOutput)
+	var stdout, stderr bytes.Buffer
+
+	code := Main([]string{"-repo", "repo", "-ref", "v1.0.0", "-count", "3"}, &stdout, &stderr, runner)
+	if code != 0 {
+		t.Fatalf("Expected exit code 0, got %d (stderr: %s)", code, stderr.String())
+	}
+
+	var report Report
+	if err := json.Unmarshal(stdout.Bytes(), &report); err != nil {
+		t.Fatalf("Expected JSON report, got %q: %v", stdout.String(), err)
+	}
+	if report.BaseRef != "v1.0.0" {
+		t.Errorf("Expected base ref v1.0.0, got %q", report.BaseRef)
+	}
+	if len(report.Comparisons) != 2 {
+		t.Errorf("Expected 2 comparisons, got %d", len(report.Comparisons))
+	}
+
+	var sawCount bool
+	for _, c := range *calls {
+		if strings.HasPrefix(c, "go test") && strings.Contains(c, "-count 3") {
+			sawCount = true
+		}
+	}
+	if !sawCount {
+		t.Errorf("Expected go test to run with -count 3, calls: %v", *calls)
+	}
+}
+
+func TestMain_Regression(t *testing.T) {
+	slower := strings.ReplaceAll(sampleOutput, "52.00 ns/op", "90.00 ns/op")
+	slower = strings.ReplaceAll(slower, "50.00 ns/op", "90.00 ns/op")
+	runner, _ := fakeRunner(sampleOutput, slower)
+	var stdout, stderr bytes.Buffer
+
+	code := Main([]string{"-repo", "repo"}, &stdout, &stderr, runner)
+	if code != 1 {
+		t.Fatalf("Expected exit code 1, got %d", code)
+	}
+	if !strings.Contains(stderr.String(), "REGRESSION BenchmarkExecute_Closed") {
+		t.Errorf("Expected regression report on stderr, got %q", stderr.String())
+	}
+}
+
+func TestMain_BadFlag(t *testing.T) {
+	runner, _ := fakeRunner("", "")
+	var stdout, stderr bytes.Buffer
+
+	if code := Main([]string{"-nope"}, &stdout, &stderr, runner); code != 2 {
+		t.Errorf("Expected exit code 2 for unknown flag, got %d", code)
+	}
+}
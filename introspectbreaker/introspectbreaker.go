@@ -0,0 +1,331 @@
+// Package introspectbreaker serves read-only circuit breaker introspection on a
+// net.Listener, typically a unix socket, for debugging agents and sidecar
+// tooling that cannot reach an HTTP port.
+//
+// The protocol is line oriented: the client sends one command per line and
+// the server answers each with one line of JSON. Commands:
+//
+//	list             [{"name": "payments", "state": "closed"}, ...]
+//	diag <name>      the breaker's Diagnostics()
+//	metrics <name>   the breaker's Metrics()
+//	history <name>   the breaker's History(0) samples, oldest first
+//
+// Any other input is answered with {"error": "..."} and the connection stays
+// open. There is no command that changes a breaker: the socket can be handed to
+// tooling without handing over control of the circuits.
+//
+// The package is separate so that autobreaker itself does not import net or
+// encoding/json; a process that never calls Serve pays nothing.
+//
+// Example:
+//
+//	l, err := net.Listen("unix", "/run/myapp/breakers.sock")
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	srv, err := introspectbreaker.Serve(l, payments, inventory)
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	defer srv.Close()
+//
+//	// echo "diag payments" | nc -U /run/myapp/breakers.sock
+package introspectbreaker
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/1mb-dev/autobreaker"
+)
+
+// Defaults for the zero values of Options.
+const (
+	DefaultWriteTimeout  = 5 * time.Second
+	DefaultIdleTimeout   = 2 * time.Minute
+	DefaultMaxLineLength = 1024
+)
+
+// Options configures ServeOptions.
+type Options struct {
+	// Breakers are served by name. Names must be unique.
+	Breakers []*autobreaker.CircuitBreaker
+
+	// Registry, if set, is consulted for names not among Breakers, and its
+	// breakers are listed after them. Breakers registered after Serve are
+	// visible to later commands.
+	Registry *autobreaker.Registry
+
+	// WriteTimeout bounds writing one response. A client that stops reading is
+	// disconnected once it expires. Default: DefaultWriteTimeout.
+	WriteTimeout time.Duration
+
+	// IdleTimeout disconnects a client that sends no complete command for this
+	// long. Default: DefaultIdleTimeout.
+	IdleTimeout time.Duration
+
+	// MaxLineLength is the longest command accepted, in bytes. A longer line
+	// is answered with an error and the client is disconnected.
+	// Default: DefaultMaxLineLength.
+	MaxLineLength int
+}
+
+// Server answers introspection commands on a listener. Created by Serve or
+// ServeOptions; stop it with Close.
+type Server struct {
+	listener net.Listener
+	opts     Options
+	byName   map[string]*autobreaker.CircuitBreaker
+
+	mu      sync.Mutex
+	conns   map[net.Conn]struct{}
+	closing bool
+	wg      sync.WaitGroup
+}
+
+// Serve starts answering commands for breakers on l in the background, with
+// default Options. See ServeOptions.
+func Serve(l net.Listener, breakers ...*autobreaker.CircuitBreaker) (*Server, error) {
+	return ServeOptions(l, Options{Breakers: breakers})
+}
+
+// ServeOptions starts answering commands on l in the background and returns
+// the running Server. Each connection is served by its own goroutine.
+//
+// Returns an error, without touching l, if l is nil or two breakers share a
+// name. The server stops when Close is called or l stops accepting; Close owns
+// l either way.
+func ServeOptions(l net.Listener, opts Options) (*Server, error) {
+	if l == nil {
+		return nil, errors.New("introspectbreaker: nil listener")
+	}
+	byName := make(map[string]*autobreaker.CircuitBreaker, len(opts.Breakers))
+	for _, cb := range opts.Breakers {
+		if cb == nil {
+			return nil, errors.New("introspectbreaker: nil breaker")
+		}
+		if _, dup := byName[cb.Name()]; dup {
+			return nil, fmt.Errorf("introspectbreaker: duplicate breaker name %q", cb.Name())
+		}
+		byName[cb.Name()] = cb
+	}
+	if opts.WriteTimeout <= 0 {
+		opts.WriteTimeout = DefaultWriteTimeout
+	}
+	if opts.IdleTimeout <= 0 {
+		opts.IdleTimeout = DefaultIdleTimeout
+	}
+	if opts.MaxLineLength <= 0 {
+		opts.MaxLineLength = DefaultMaxLineLength
+	}
+
+	s := &Server{
+		listener: l,
+		opts:     opts,
+		byName:   byName,
+		conns:    make(map[net.Conn]struct{}),
+	}
+	s.wg.Add(1)
+	go s.acceptLoop()
+	return s, nil
+}
+
+// Close stops accepting connections, lets each connected client's current
+// response finish (bounded by WriteTimeout), disconnects every client and
+// waits for their goroutines to exit. Close is safe to call more than once;
+// later calls return nil.
+func (s *Server) Close() error {
+	s.mu.Lock()
+	if s.closing {
+		s.mu.Unlock()
+		return nil
+	}
+	s.closing = true
+	err := s.listener.Close()
+	for c := range s.conns {
+		// Unblocks the read waiting for the next command; a response being
+		// written completes first
+		c.SetReadDeadline(time.Now())
+	}
+	s.mu.Unlock()
+
+	s.wg.Wait()
+	return err
+}
+
+func (s *Server) acceptLoop() {
+	defer s.wg.Done()
+	for {
+		c, err := s.listener.Accept()
+		if err != nil {
+			return
+		}
+		if !s.track(c) {
+			c.Close()
+			return
+		}
+		go s.serveConn(c)
+	}
+}
+
+// track registers c as connected, unless the server is closing.
+func (s *Server) track(c net.Conn) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closing {
+		return false
+	}
+	s.conns[c] = struct{}{}
+	s.wg.Add(1)
+	return true
+}
+
+func (s *Server) serveConn(c net.Conn) {
+	defer func() {
+		s.mu.Lock()
+		delete(s.conns, c)
+		s.mu.Unlock()
+		c.Close()
+		s.wg.Done()
+	}()
+
+	scanner := bufio.NewScanner(c)
+	scanner.Buffer(make([]byte, 0, min(256, s.opts.MaxLineLength)), s.opts.MaxLineLength)
+	for {
+		if !s.armRead(c) {
+			return
+		}
+		if !scanner.Scan() {
+			if errors.Is(scanner.Err(), bufio.ErrTooLong) {
+				s.reply(c, errorResponse(fmt.Sprintf("command longer than %d bytes", s.opts.MaxLineLength)))
+			}
+			return
+		}
+		if !s.reply(c, s.handle(scanner.Text())) {
+			return
+		}
+	}
+}
+
+// armRead sets c's read deadline for the next command, unless the server is
+// closing. Holding mu orders it with Close's deadline, so a client is never
+// left waiting out IdleTimeout after Close.
+func (s *Server) armRead(c net.Conn) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closing {
+		return false
+	}
+	c.SetReadDeadline(time.Now().Add(s.opts.IdleTimeout))
+	return true
+}
+
+// reply writes response and a newline within WriteTimeout. Returns false if
+// the client should be disconnected.
+func (s *Server) reply(c net.Conn, response []byte) bool {
+	c.SetWriteDeadline(time.Now().Add(s.opts.WriteTimeout))
+	_, err := c.Write(append(response, '\n'))
+	return err == nil
+}
+
+// handle returns the JSON response to one command line.
+func (s *Server) handle(line string) []byte {
+	command, name, _ := strings.Cut(strings.TrimSpace(line), " ")
+	name = strings.TrimSpace(name)
+
+	if command == "list" {
+		if name != "" {
+			return errorResponse("list takes no arguments")
+		}
+		return encode(s.list())
+	}
+
+	var snapshot func(cb *autobreaker.CircuitBreaker) interface{}
+	switch command {
+	case "diag":
+		snapshot = func(cb *autobreaker.CircuitBreaker) interface{} { return cb.Diagnostics() }
+	case "metrics":
+		snapshot = func(cb *autobreaker.CircuitBreaker) interface{} { return cb.Metrics() }
+	case "history":
+		snapshot = func(cb *autobreaker.CircuitBreaker) interface{} {
+			if samples := cb.History(0); samples != nil {
+				return samples
+			}
+			return []autobreaker.MetricsSample{}
+		}
+	case "":
+		return errorResponse("empty command")
+	default:
+		return errorResponse(fmt.Sprintf("unknown command %q (want list, diag, metrics or history)", command))
+	}
+
+	if name == "" {
+		return errorResponse(command + " requires a breaker name")
+	}
+	cb, ok := s.lookup(name)
+	if !ok {
+		return errorResponse(fmt.Sprintf("unknown breaker %q", name))
+	}
+	return encode(snapshot(cb))
+}
+
+// listEntry is one element of the list response.
+type listEntry struct {
+	Name   string            `json:"name"`
+	State  string            `json:"state"`
+	Labels map[string]string `json:"labels,omitempty"`
+}
+
+// list returns every served breaker: Options.Breakers in order, then the
+// registry's breakers not already listed.
+func (s *Server) list() []listEntry {
+	entries := make([]listEntry, 0, len(s.opts.Breakers))
+	add := func(cb *autobreaker.CircuitBreaker) {
+		entries = append(entries, listEntry{Name: cb.Name(), State: cb.State().String(), Labels: cb.Labels()})
+	}
+	for _, cb := range s.opts.Breakers {
+		add(cb)
+	}
+	if s.opts.Registry != nil {
+		for _, name := range s.opts.Registry.Names() {
+			if _, shadowed := s.byName[name]; shadowed {
+				continue
+			}
+			if cb, ok := s.opts.Registry.Lookup(name); ok {
+				add(cb)
+			}
+		}
+	}
+	return entries
+}
+
+func (s *Server) lookup(name string) (*autobreaker.CircuitBreaker, bool) {
+	if cb, ok := s.byName[name]; ok {
+		return cb, true
+	}
+	if s.opts.Registry != nil {
+		return s.opts.Registry.Lookup(name)
+	}
+	return nil, false
+}
+
+func encode(v interface{}) []byte {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return errorResponse("encoding response: " + err.Error())
+	}
+	return data
+}
+
+func errorResponse(msg string) []byte {
+	data, _ := json.Marshal(struct {
+		Error string `json:"error"`
+	}{msg})
+	return data
+}
@@ -0,0 +1,295 @@
+package introspectbreaker
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"net"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/1mb-dev/autobreaker"
+)
+
+// client is a connection to a test server.
+type client struct {
+	t    *testing.T
+	conn net.Conn
+	r    *bufio.Reader
+}
+
+// serve starts a server with opts on a unix socket in a temp dir and returns
+// a client connected to it. The server is closed when the test ends.
+func serve(t *testing.T, opts Options) (*Server, *client) {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "breakers.sock")
+	l, err := net.Listen("unix", path)
+	if err != nil {
+		t.Fatalf("Expected to listen on %s: %v", path, err)
+	}
+	srv, err := ServeOptions(l, opts)
+	if err != nil {
+		t.Fatalf("Expected the server to start: %v", err)
+	}
+	t.Cleanup(func() { srv.Close() })
+
+	conn, err := net.Dial("unix", path)
+	if err != nil {
+		t.Fatalf("Expected to connect: %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return srv, &client{t: t, conn: conn, r: bufio.NewReader(conn)}
+}
+
+// send writes line as a command and decodes the one-line response into v.
+func (c *client) send(line string, v interface{}) {
+	c.t.Helper()
+	c.conn.SetDeadline(time.Now().Add(time.Second))
+	if _, err := c.conn.Write([]byte(line + "\n")); err != nil {
+		c.t.Fatalf("Expected to send %q: %v", line, err)
+	}
+	resp, err := c.r.ReadString('\n')
+	if err != nil {
+		c.t.Fatalf("Expected a response to %q: %v", line, err)
+	}
+	if err := json.Unmarshal([]byte(resp), v); err != nil {
+		c.t.Fatalf("Expected JSON in response to %q, got %q: %v", line, resp, err)
+	}
+}
+
+// sendError sends line and returns the response's error message.
+func (c *client) sendError(line string) string {
+	c.t.Helper()
+	var resp struct{ Error string }
+	c.send(line, &resp)
+	return resp.Error
+}
+
+// expectDisconnected fails unless the server closes the connection within a second.
+func (c *client) expectDisconnected() {
+	c.t.Helper()
+	c.conn.SetReadDeadline(time.Now().Add(time.Second))
+	for {
+		if _, err := c.r.ReadByte(); err != nil {
+			var ne net.Error
+			if errors.As(err, &ne) && ne.Timeout() {
+				c.t.Fatal("Expected the server to disconnect the client")
+			}
+			return
+		}
+	}
+}
+
+func failing() (interface{}, error) { return nil, errors.New("boom") }
+
+func TestServe_Commands(t *testing.T) {
+	payments := autobreaker.New(autobreaker.Settings{
+		Name:                   "payments",
+		Labels:                 map[string]string{"team": "billing"},
+		MetricsHistoryInterval: time.Nanosecond,
+	})
+	inventory := autobreaker.New(autobreaker.Settings{Name: "inventory"})
+	payments.Execute(failing)
+	for i := 0; i < 6; i++ {
+		inventory.Execute(failing)
+	}
+	_, c := serve(t, Options{Breakers: []*autobreaker.CircuitBreaker{payments, inventory}})
+
+	var list []listEntry
+	c.send("list", &list)
+	if len(list) != 2 || list[0].Name != "payments" || list[0].Labels["team"] != "billing" ||
+		list[1].Name != "inventory" || list[1].State != "open" {
+		t.Errorf("Expected both breakers listed with state and labels, got %+v", list)
+	}
+
+	var diag autobreaker.Diagnostics
+	c.send("diag inventory", &diag)
+	if diag.Name != "inventory" || diag.State != autobreaker.StateOpen {
+		t.Errorf("Expected inventory's diagnostics, got %+v", diag)
+	}
+
+	var metrics autobreaker.Metrics
+	c.send("metrics payments", &metrics)
+	if metrics.Counts.Requests != 1 || metrics.Counts.TotalFailures != 1 {
+		t.Errorf("Expected payments' metrics, got %+v", metrics.Counts)
+	}
+
+	var history []autobreaker.MetricsSample
+	c.send("history payments", &history)
+	if len(history) == 0 {
+		t.Error("Expected payments' history samples")
+	}
+	c.send("history inventory", &history)
+	if history == nil || len(history) != 0 {
+		t.Errorf("Expected an empty array without history, got %v", history)
+	}
+}
+
+func TestServe_MalformedInput(t *testing.T) {
+	cb := autobreaker.New(autobreaker.Settings{Name: "payments"})
+	_, c := serve(t, Options{Breakers: []*autobreaker.CircuitBreaker{cb}, MaxLineLength: 64})
+
+	for line, want := range map[string]string{
+		"":                   "empty command",
+		"   ":                "empty command",
+		"reset payments":     "unknown command",
+		"DIAG payments":      "unknown command",
+		"diag":               "requires a breaker name",
+		"metrics   ":         "requires a breaker name",
+		"history unknown":    "unknown breaker",
+		"list payments":      "takes no arguments",
+		"{\"cmd\":\"list\"}": "unknown command",
+	} {
+		if got := c.sendError(line); !strings.Contains(got, want) {
+			t.Errorf("Expected %q to be answered with %q, got %q", line, want, got)
+		}
+	}
+
+	// The connection survives bad commands
+	var metrics autobreaker.Metrics
+	c.send("  metrics payments  ", &metrics)
+	if metrics.State != autobreaker.StateClosed {
+		t.Errorf("Expected metrics after malformed input, got %+v", metrics)
+	}
+
+	// An overlong line is refused and the client disconnected
+	if got := c.sendError("diag " + strings.Repeat("x", 100)); !strings.Contains(got, "longer than 64 bytes") {
+		t.Errorf("Expected the overlong command refused, got %q", got)
+	}
+	c.expectDisconnected()
+}
+
+func TestServe_Registry(t *testing.T) {
+	registry := autobreaker.NewRegistry()
+	registry.GetOrCreate(autobreaker.Settings{Name: "users"})
+	direct := autobreaker.New(autobreaker.Settings{Name: "payments"})
+	_, c := serve(t, Options{Breakers: []*autobreaker.CircuitBreaker{direct}, Registry: registry})
+
+	// Registered after Serve
+	registry.GetOrCreate(autobreaker.Settings{Name: "orders"})
+
+	var list []listEntry
+	c.send("list", &list)
+	var names []string
+	for _, e := range list {
+		names = append(names, e.Name)
+	}
+	if strings.Join(names, ",") != "payments,users,orders" {
+		t.Errorf("Expected direct breakers then the registry's, got %v", names)
+	}
+
+	var diag autobreaker.Diagnostics
+	c.send("diag orders", &diag)
+	if diag.Name != "orders" {
+		t.Errorf("Expected the registry's breaker, got %q", diag.Name)
+	}
+}
+
+func TestServeOptions_Errors(t *testing.T) {
+	a := autobreaker.New(autobreaker.Settings{Name: "dup"})
+	b := autobreaker.New(autobreaker.Settings{Name: "dup"})
+
+	if _, err := Serve(nil, a); err == nil {
+		t.Error("Expected an error for a nil listener")
+	}
+
+	l, err := net.Listen("unix", filepath.Join(t.TempDir(), "dup.sock"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+	if _, err := Serve(l, a, b); err == nil || !strings.Contains(err.Error(), `"dup"`) {
+		t.Errorf("Expected a duplicate name error, got %v", err)
+	}
+	if _, err := Serve(l, a, nil); err == nil {
+		t.Error("Expected an error for a nil breaker")
+	}
+}
+
+// pipeListener hands out the server ends of net.Pipe connections, which are
+// unbuffered: a client that stops reading blocks the server's next write.
+type pipeListener struct {
+	conns  chan net.Conn
+	closed chan struct{}
+}
+
+func newPipeListener() *pipeListener {
+	return &pipeListener{conns: make(chan net.Conn), closed: make(chan struct{})}
+}
+
+func (l *pipeListener) dial() net.Conn {
+	client, server := net.Pipe()
+	l.conns <- server
+	return client
+}
+
+func (l *pipeListener) Accept() (net.Conn, error) {
+	select {
+	case c := <-l.conns:
+		return c, nil
+	case <-l.closed:
+		return nil, net.ErrClosed
+	}
+}
+
+func (l *pipeListener) Close() error   { close(l.closed); return nil }
+func (l *pipeListener) Addr() net.Addr { return &net.UnixAddr{Name: "pipe", Net: "unix"} }
+
+func TestServe_SlowClientDisconnected(t *testing.T) {
+	l := newPipeListener()
+	cb := autobreaker.New(autobreaker.Settings{Name: "payments"})
+	srv, err := ServeOptions(l, Options{
+		Breakers:     []*autobreaker.CircuitBreaker{cb},
+		WriteTimeout: 20 * time.Millisecond,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer srv.Close()
+
+	conn := l.dial()
+	defer conn.Close()
+	if _, err := conn.Write([]byte("diag payments\n")); err != nil {
+		t.Fatal(err)
+	}
+
+	// Never reading the response: the server gives up on the write and hangs up
+	time.Sleep(100 * time.Millisecond)
+	c := &client{t: t, conn: conn, r: bufio.NewReader(conn)}
+	c.expectDisconnected()
+}
+
+func TestServe_IdleClientDisconnected(t *testing.T) {
+	_, c := serve(t, Options{IdleTimeout: 20 * time.Millisecond})
+	c.expectDisconnected()
+}
+
+func TestServer_Close(t *testing.T) {
+	cb := autobreaker.New(autobreaker.Settings{Name: "payments"})
+	srv, c := serve(t, Options{Breakers: []*autobreaker.CircuitBreaker{cb}})
+
+	var list []listEntry
+	c.send("list", &list)
+
+	// Close returns promptly despite the connected client's two-minute idle timeout
+	done := make(chan error, 1)
+	go func() { done <- srv.Close() }()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Errorf("Expected a clean close, got %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Expected Close to disconnect idle clients")
+	}
+	c.expectDisconnected()
+
+	if _, err := net.Dial("unix", srv.listener.Addr().String()); err == nil {
+		t.Error("Expected no new connections after Close")
+	}
+	if err := srv.Close(); err != nil {
+		t.Errorf("Expected a second Close to return nil, got %v", err)
+	}
+}